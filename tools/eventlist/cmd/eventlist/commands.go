@@ -0,0 +1,931 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"errors"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/compare"
+	"eventlist/pkg/cut"
+	"eventlist/pkg/diag"
+	"eventlist/pkg/diff"
+	"eventlist/pkg/event"
+	"eventlist/pkg/generate"
+	"eventlist/pkg/merge"
+	"eventlist/pkg/output"
+	"eventlist/pkg/redact"
+	"eventlist/pkg/scvdgen"
+	"eventlist/pkg/where"
+	"eventlist/pkg/xml/scvd"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var errOption = errors.New("option not allowed")
+var errExport = errors.New("export requires -f json, xml or html and -o <outputFile>")
+var errCapture = errors.New("capture requires a live source and a capture file")
+var errAssert = errors.New("assert requires a rules file and a capture")
+var errSelftest = errors.New("selftest requires a golden file and a capture")
+
+// optionInfo is an option in the usage text
+type optionInfo struct {
+	sopt string
+	lopt string
+	opt  string
+}
+
+// options of the decoder in the order of the usage text
+var optionInfos = []optionInfo{
+	{"a", "", "<fileName>"},
+	{"b", "begin", ""},
+	{"h", "help", ""},
+	{"I", "", "<fileName>"},
+	{"o", "", "<fileName>"},
+	{"s", "statistic", ""},
+	{"V", "version", ""},
+	{"f", "format", "<formatType>"},
+	{"l", "level", "<Error|API|Op|Detail>"},
+	{"", "float", "<float|double|half>"},
+	{"", "fixed", "<Qm.n>"},
+	{"", "precision", "<digits>"},
+	{"", "profile", "<name|list>"},
+	{"", "profiles", "<fileName>"},
+	{"", "script", "<fileName>"},
+	{"", "decoder", "<first>-<last>=<command>"},
+	{"", "svd", "<fileName>"},
+	{"", "svd-events", "<first>-<last>,..."},
+	{"", "severity", "<fileName>"},
+	{"", "override", "<fileName>"},
+	{"", "rtos", "<name>"},
+	{"", "component-version", "<name=version,...>"},
+	{"", "pack", "<vendor::name@version,...>"},
+	{"", "cache-dir", "<dir>"},
+	{"", "checklist", "<fileName>"},
+	{"", "assert", "<fileName>"},
+	{"", "golden", "<fileName>"},
+	{"", "update-golden", ""},
+	{"", "dashboard", "<fileName>"},
+	{"", "template", "<fileName>"},
+	{"", "out", "<format>=<fileName>"},
+	{"", "columns", "<list>"},
+	{"", "live", "<source>"},
+	{"", "http", "<address>"},
+	{"", "ack", ""},
+	{"", "resync", ""},
+	{"", "queue", "<records>"},
+	{"", "queue-policy", "<block|drop-oldest|drop-newest>"},
+	{"", "framing", "<none|cobs|slip|auto>"},
+	{"", "pyocd", "<target>[,<probe>]"},
+	{"", "recorder-filter", "<filter>"},
+	{"", "reset", ""},
+	{"", "capture", "<fileName>"},
+	{"", "where", "<conditions>"},
+	{"", "tree", ""},
+	{"", "markers", "<ranges>"},
+	{"", "levels", ""},
+	{"", "annotations", "<fileName>"},
+	{"", "object-names", ""},
+	{"", "color", "<auto|always|never>"},
+	{"", "hyperlinks", "<auto|always|never>"},
+	{"", "no-pager", ""},
+	{"", "watch", ""},
+	{"", "deterministic", ""},
+	{"", "strict", ""},
+	{"", "unknown-events", ""},
+	{"q", "quiet", ""},
+	{"", "squash", ""},
+	{"", "sort", "<time|index|component|duration>"},
+	{"", "reverse", ""},
+	{"", "head", "<n>"},
+	{"", "tail", "<n>"},
+	{"", "skip", "<n>"},
+	{"", "limit", "<n>"},
+	{"", "jobs", "<n>"},
+	{"", "index", ""},
+	{"", "stats-only", ""},
+	{"", "output-buffer", "<bytes>"},
+	{"", "flush-interval", "<duration>"},
+	{"", "stats-interval", "<duration>"},
+	{"", "rotate-size", "<bytes>"},
+	{"", "rotate-interval", "<duration>"},
+	{"", "rotate-compress", ""},
+	{"", "heatmap", "<fileName>"},
+	{"", "heatmap-buckets", "<n>"},
+	{"", "heatmap-rows", "<rows>"},
+	{"", "chart", "<style>"},
+	{"", "chart-width", "<n>"},
+	{"", "chart-events", "<ranges>"},
+	{"", "mqtt", "<url>"},
+	{"", "sink", "<url>"},
+	{"", "syslog", "<url|fileName>"},
+	{"", "syslog-format", "<rfc5424|json>"},
+	{"", "influx", "<fileName|url>"},
+	{"", "otlp", "<address>"},
+	{"", "health", ""},
+	{"", "health-json", "<fileName>"},
+	{"", "gaps", "<n>"},
+	{"", "gap-time", "<seconds>"},
+	{"", "error-context", "<n>"},
+	{"", "error-context-dir", "<dirName>"},
+	{"", "trace-self", "<fileName>"},
+	{"", "config", "<fileName>"},
+	{"", "no-config", ""},
+	{"", "diagnostics", "<text|json>"},
+}
+
+// command is a subcommand of the tool
+type command struct {
+	name    string
+	args    string // arguments of the usage line
+	summary string
+	// options of a decoding command, nil allows all options
+	options []string
+	// prepare sets the options implied by the command and returns the input files
+	prepare func(flags *flag.FlagSet) ([]string, error)
+	// main runs a command not decoding a capture with the options of the decoder
+	main func(args []string)
+}
+
+// options shared by the decoding commands
+var decodeOptions = []string{"I", "a", "decoder", "svd", "svd-events", "object-names", "profile", "profiles", "float", "fixed", "precision",
+	"severity", "override", "rtos", "component-version", "pack", "cache-dir", "config", "no-config", "q", "quiet", "no-pager", "trace-self", "diagnostics"}
+
+var commands = []command{
+	{
+		name:    "decode",
+		args:    "[options] <logFile>",
+		summary: "decode a capture, the same as without command",
+	},
+	{
+		name:    "stats",
+		args:    "[options] <logFile>",
+		summary: "print the start/stop event statistic",
+		options: append([]string{"o", "f", "l", "where", "health", "health-json", "gaps", "gap-time", "markers", "levels"}, decodeOptions...),
+		prepare: func(flags *flag.FlagSet) ([]string, error) {
+			return flags.Args(), flags.Set("stats-only", "true")
+		},
+	},
+	{
+		name:    "validate",
+		args:    "[options] <logFile>",
+		summary: "check the capture health, the SCVD files and a checklist",
+		options: append([]string{"o", "checklist", "health-json", "unknown-events", "gaps", "gap-time"}, decodeOptions...),
+		prepare: func(flags *flag.FlagSet) ([]string, error) {
+			if err := flags.Set("s", "true"); err != nil {
+				return nil, err
+			}
+			return flags.Args(), flags.Set("health", "true")
+		},
+	},
+	{
+		name:    "assert",
+		args:    "[options] <rulesFile> <logFile>",
+		summary: "check YAML rules against a capture, fails if one is violated",
+		options: append([]string{"o", "health-json"}, decodeOptions...),
+		prepare: func(flags *flag.FlagSet) ([]string, error) {
+			if flags.NArg() != 2 {
+				return nil, errAssert
+			}
+			if err := flags.Set("s", "true"); err != nil {
+				return nil, err
+			}
+			return flags.Args()[1:], flags.Set("assert", flags.Arg(0))
+		},
+	},
+	{
+		name:    "selftest",
+		args:    "[options] <goldenFile> <logFile>",
+		summary: "compare the decoded events with a golden file, e.g. to test SCVD files",
+		options: append([]string{"f", "l", "b", "s", "where", "columns", "update-golden"}, decodeOptions...),
+		prepare: func(flags *flag.FlagSet) ([]string, error) {
+			if flags.NArg() != 2 {
+				return nil, errSelftest
+			}
+			return flags.Args()[1:], flags.Set("golden", flags.Arg(0))
+		},
+	},
+	{
+		name:    "merge",
+		args:    "-o <outputFile> <logFile>...",
+		summary: "merge captures into one capture ordered by timestamp",
+		main:    mergeMain,
+	},
+	{
+		name:    "cut",
+		args:    "-o <outputFile> [options] <logFile>",
+		summary: "write the events of a time range, components or a session to a new capture",
+		main:    cutMain,
+	},
+	{
+		name:    "redact",
+		args:    "-o <outputFile> [options] <logFile>",
+		summary: "write a capture with hashed strings, addresses and values to share it",
+		main:    redactMain,
+	},
+	{
+		name:    "export",
+		args:    "-f <json|xml|html> -o <outputFile> [options] <logFile>",
+		summary: "write the events and the statistic as json, xml or html file",
+		options: append([]string{"o", "f", "l", "out", "where", "columns", "dashboard", "squash", "sort", "reverse",
+			"head", "tail", "skip", "limit"}, decodeOptions...),
+		prepare: func(flags *flag.FlagSet) ([]string, error) {
+			switch format := flags.Lookup("f").Value.String(); format {
+			case "json", "xml", "html":
+			default:
+				if !output.IsFormat(format) || format == "txt" {
+					return nil, errExport
+				}
+			}
+			if len(flags.Lookup("o").Value.String()) == 0 {
+				return nil, errExport
+			}
+			return flags.Args(), nil
+		},
+	},
+	{
+		name:    "capture",
+		args:    "[options] <source>|--pyocd <target> <captureFile>",
+		summary: "record a live source to a file and print its events",
+		options: append([]string{"l", "where", "columns", "color", "hyperlinks", "framing", "ack", "resync", "queue", "queue-policy", "http", "stats-interval", "pyocd", "recorder-filter", "reset"}, decodeOptions...),
+		prepare: func(flags *flag.FlagSet) ([]string, error) {
+			if len(flags.Lookup("pyocd").Value.String()) != 0 {
+				if flags.NArg() != 1 {
+					return nil, errCapture
+				}
+				return nil, flags.Set("capture", flags.Arg(0))
+			}
+			if flags.NArg() != 2 {
+				return nil, errCapture
+			}
+			if err := flags.Set("live", flags.Arg(0)); err != nil {
+				return nil, err
+			}
+			return nil, flags.Set("capture", flags.Arg(1))
+		},
+	},
+	{
+		name:    "compare",
+		args:    "[options] <baselineFile> <logFile>",
+		summary: "compare counts and durations with a baseline capture",
+		main:    compareMain,
+	},
+	{
+		name:    "diff",
+		args:    "[options] <baselineFile> <logFile>",
+		summary: "print the added, removed and changed events as unified diff",
+		main:    diffMain,
+	},
+	{
+		name:    "generate",
+		args:    "-o <outputFile> <scenarioFile>",
+		summary: "write a capture of the events of a YAML scenario",
+		main:    generateMain,
+	},
+	{
+		name:    "scvd-gen",
+		args:    "[-o <outputFile>] [--name <component>] <headerFile>",
+		summary: "write an SCVD skeleton of the EventID definitions of a C header",
+		main:    scvdGenMain,
+	},
+	{
+		name:    "header-gen",
+		args:    "[-o <outputFile>] <scvdFile>",
+		summary: "write a C header of the event IDs of an SCVD file",
+		main:    headerGenMain,
+	},
+	{
+		name:    "view",
+		args:    "[-I <scvdFile>]... [-a <elf/axfFile>] [--markers <ranges>] <logFile>",
+		summary: "show the events in an interactive terminal viewer",
+		main:    viewMain,
+	},
+	{
+		name:    "serve",
+		args:    "[--addr <address>]",
+		summary: "serve workspaces of decoded captures as JSON API",
+		main:    serveMain,
+	},
+}
+
+// findCommand returns the command of a name, nil if there is none
+func findCommand(name string) *command {
+	for i := range commands {
+		if commands[i].name == name {
+			return &commands[i]
+		}
+	}
+	return nil
+}
+
+// allows returns true if the command takes the option, a nil command takes all options
+func (c *command) allows(name string) bool {
+	if c == nil || c.options == nil {
+		return true
+	}
+	for _, o := range c.options {
+		if o == name {
+			return true
+		}
+	}
+	return false
+}
+
+// check rejects the options not taken by the command and prepares the
+// implied options, it returns the input files
+func (c *command) check(flags *flag.FlagSet) ([]string, error) {
+	var err error
+	flags.Visit(func(f *flag.Flag) {
+		if err == nil && !c.allows(f.Name) {
+			dash := "--"
+			if len(f.Name) == 1 {
+				dash = "-"
+			}
+			err = fmt.Errorf("%w with %s: %s%s", errOption, c.name, dash, f.Name)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if c.prepare == nil {
+		return flags.Args(), nil
+	}
+	return c.prepare(flags)
+}
+
+// mergeMain runs the command "merge": merges captures ordered by timestamp
+func mergeMain(args []string) {
+	flags := flag.NewFlagSet("merge", flag.ContinueOnError)
+	outputFile := flags.String("o", "", "output file name")
+	flags.Usage = func() {
+		fmt.Printf("Usage: %s merge -o <outputFile> <logFile>...\n", Progname)
+		infoOpt(flags, "o", "", "<fileName>")
+	}
+	flags.SetOutput(nopWriter{})
+	if err := flags.Parse(args); err != nil {
+		if err != flag.ErrHelp {
+			diags.Error(diag.Error, err)
+		}
+		return
+	}
+	if len(*outputFile) == 0 {
+		diags.Errorf(diag.Error, "merge requires -o <outputFile>")
+		return
+	}
+	if flags.NArg() < 2 {
+		diags.Errorf(diag.Error, "merge requires at least two input files")
+		return
+	}
+
+	var captures []io.Reader
+	for _, name := range flags.Args() {
+		file, err := os.Open(name)
+		if err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+		defer file.Close()
+		captures = append(captures, file)
+	}
+	out, err := os.Create(*outputFile)
+	if err != nil {
+		diags.Error(diag.Error, err)
+		return
+	}
+	if _, err = merge.Merge(out, captures...); err != nil {
+		_ = out.Close()
+		diags.Error(diag.Decode, err)
+		return
+	}
+	if err = out.Close(); err != nil {
+		diags.Error(diag.Error, err)
+	}
+}
+
+// compareMain runs the command "compare": compares a capture with a baseline
+func compareMain(args []string) {
+	flags := flag.NewFlagSet("compare", flag.ContinueOnError)
+	var files includes
+	flags.Var(&files, "I", "include SCVD file name")
+	outputFile := flags.String("o", "", "output file name")
+	countThreshold := flags.Float64("count-threshold", compare.DefaultThresholds.Count, "allowed change of event counts in percent")
+	durationThreshold := flags.Float64("duration-threshold", compare.DefaultThresholds.Duration, "allowed increase of durations in percent")
+	flags.Usage = func() {
+		fmt.Printf("Usage: %s compare [-I <scvdFile>]... [-o <outputFile>] <baselineFile> <logFile>\n", Progname)
+		infoOpt(flags, "I", "", "<fileName>")
+		infoOpt(flags, "o", "", "<fileName>")
+		infoOpt(flags, "", "count-threshold", "<percent>")
+		infoOpt(flags, "", "duration-threshold", "<percent>")
+	}
+	flags.SetOutput(nopWriter{})
+	if err := flags.Parse(args); err != nil {
+		if err != flag.ErrHelp {
+			diags.Error(diag.Error, err)
+		}
+		return
+	}
+	if flags.NArg() != 2 {
+		diags.Errorf(diag.Error, "compare requires a baseline and a capture file")
+		return
+	}
+
+	evdefs := make(map[uint16]scvd.Event)
+	typedefs := make(map[string]map[string]map[int16]string)
+	memberTypes := make(map[string]map[string]string)
+	var p []string = files
+	if err := scvd.Get(&p, evdefs, typedefs, memberTypes); err != nil {
+		diags.Error(diag.SCVD, err)
+		return
+	}
+	var profiles [2]*compare.Profile
+	for i, name := range flags.Args() {
+		file, err := os.Open(name)
+		if err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+		profiles[i], err = compare.Read(file, evdefs, typedefs, memberTypes)
+		file.Close()
+		if err != nil {
+			diags.Error(diag.Decode, fmt.Errorf("%s: %w", name, err))
+			return
+		}
+	}
+	result := compare.Compare(profiles[0], profiles[1],
+		compare.Thresholds{Count: *countThreshold, Duration: *durationThreshold})
+
+	out := io.Writer(os.Stdout)
+	if len(*outputFile) != 0 {
+		file, err := os.Create(*outputFile)
+		if err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+		defer file.Close()
+		out = file
+	}
+	if err := result.Report(out); err != nil {
+		diags.Error(diag.Error, err)
+		return
+	}
+	if n := result.Regressions(); n != 0 {
+		diags.Errorf(diag.Regression, "%d regressions beyond the thresholds", n)
+	}
+}
+
+// diffMain runs the command "diff": prints the differences of the events of two captures
+func diffMain(args []string) {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	var files includes
+	flags.Var(&files, "I", "include SCVD file name")
+	outputFile := flags.String("o", "", "output file name")
+	timestamps := flags.Bool("timestamps", false, "compare the timestamps of the events too")
+	context := flags.Int("context", diff.DefaultContext, "number of unchanged events around a change")
+	flags.Usage = func() {
+		fmt.Printf("Usage: %s diff [-I <scvdFile>]... [-o <outputFile>] <baselineFile> <logFile>\n", Progname)
+		infoOpt(flags, "I", "", "<fileName>")
+		infoOpt(flags, "o", "", "<fileName>")
+		infoOpt(flags, "", "timestamps", "")
+		infoOpt(flags, "", "context", "<n>")
+	}
+	flags.SetOutput(nopWriter{})
+	if err := flags.Parse(args); err != nil {
+		if err != flag.ErrHelp {
+			diags.Error(diag.Error, err)
+		}
+		return
+	}
+	if flags.NArg() != 2 {
+		diags.Errorf(diag.Error, "diff requires a baseline and a capture file")
+		return
+	}
+	if *context < 0 {
+		diags.Errorf(diag.Error, "invalid --context: %d", *context)
+		return
+	}
+
+	evdefs := make(map[uint16]scvd.Event)
+	typedefs := make(map[string]map[string]map[int16]string)
+	memberTypes := make(map[string]map[string]string)
+	var p []string = files
+	if err := scvd.Get(&p, evdefs, typedefs, memberTypes); err != nil {
+		diags.Error(diag.SCVD, err)
+		return
+	}
+	var lines [2][]diff.Line
+	for i, name := range flags.Args() {
+		file, err := os.Open(name)
+		if err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+		lines[i], err = diff.Read(file, evdefs, typedefs, memberTypes)
+		file.Close()
+		if err != nil {
+			diags.Error(diag.Decode, fmt.Errorf("%s: %w", name, err))
+			return
+		}
+	}
+	result := diff.Diff(lines[0], lines[1], diff.Options{Timestamps: *timestamps, Context: *context})
+
+	out := io.Writer(os.Stdout)
+	if len(*outputFile) != 0 {
+		file, err := os.Create(*outputFile)
+		if err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+		defer file.Close()
+		out = file
+	}
+	if err := result.Write(out, flags.Arg(0), flags.Arg(1)); err != nil {
+		diags.Error(diag.Error, err)
+		return
+	}
+	if !result.Equal() {
+		diags.Warning(diag.Differ, "the events differ")
+	}
+}
+
+// publish decodes the events of a capture and passes them to the analyzer,
+// it does not end the analyzer
+func publish(in io.Reader, evdefs map[uint16]scvd.Event, typedefs map[string]map[string]map[int16]string,
+	memberTypes map[string]map[string]string,
+	a bus.Analyzer) error {
+	return output.Decode(in, evdefs, typedefs, memberTypes, func(rec *output.EventRecord, ev *event.Data) error {
+		var def *scvd.Event
+		if evdef, ok := evdefs[ev.Info.ID]; ok {
+			def = &evdef
+		}
+		value := rec.Value
+		return a.Event(bus.NewEvent(rec.Index, rec.Time, ev, def, func() (string, error) { return value, nil }))
+	})
+}
+
+// cutMain runs the command "cut": writes the selected events to a new capture
+func cutMain(args []string) {
+	flags := flag.NewFlagSet("cut", flag.ContinueOnError)
+	var files includes
+	flags.Var(&files, "I", "include SCVD file name")
+	outputFile := flags.String("o", "", "output file name")
+	from := flags.Float64("from", 0, "begin of the time range in s")
+	to := flags.Float64("to", math.Inf(1), "end of the time range in s")
+	var wheres includes
+	flags.Var(&wheres, "where", "events matching conditions key=pattern, e.g. component=RTX*")
+	session := flags.String("session", "", "events of the sessions of a start/stop pair, e.g. A(0) or A(0):3 for the third")
+	flags.Usage = func() {
+		fmt.Printf("Usage: %s cut -o <outputFile> [options] <logFile>\n", Progname)
+		infoOpt(flags, "I", "", "<fileName>")
+		infoOpt(flags, "o", "", "<fileName>")
+		infoOpt(flags, "", "from", "<seconds>")
+		infoOpt(flags, "", "to", "<seconds>")
+		infoOpt(flags, "", "where", "<conditions>")
+		infoOpt(flags, "", "session", "<pair[:n]>")
+	}
+	flags.SetOutput(nopWriter{})
+	if err := flags.Parse(args); err != nil {
+		if err != flag.ErrHelp {
+			diags.Error(diag.Error, err)
+		}
+		return
+	}
+	if len(*outputFile) == 0 {
+		diags.Errorf(diag.Error, "cut requires -o <outputFile>")
+		return
+	}
+	if flags.NArg() != 1 {
+		diags.Errorf(diag.Error, "cut requires one input file")
+		return
+	}
+	filter, err := where.Parse(wheres)
+	if err != nil {
+		diags.Error(diag.Error, err)
+		return
+	}
+
+	evdefs := make(map[uint16]scvd.Event)
+	typedefs := make(map[string]map[string]map[int16]string)
+	memberTypes := make(map[string]map[string]string)
+	var p []string = files
+	if err = scvd.Get(&p, evdefs, typedefs, memberTypes); err != nil {
+		diags.Error(diag.SCVD, err)
+		return
+	}
+	in, err := os.Open(flags.Arg(0))
+	if err != nil {
+		diags.Error(diag.Error, err)
+		return
+	}
+	defer in.Close()
+	out, err := os.Create(*outputFile)
+	if err != nil {
+		diags.Error(diag.Error, err)
+		return
+	}
+	c, err := cut.New(out, cut.Options{From: *from, To: *to, Filter: filter, Session: *session})
+	if err != nil {
+		_ = out.Close()
+		diags.Error(diag.Error, err)
+		return
+	}
+	if err = publish(in, evdefs, typedefs, memberTypes, c); err != nil {
+		_ = out.Close()
+		diags.Error(diag.Decode, err)
+		return
+	}
+	if err = c.End(); err == nil {
+		err = out.Close()
+	}
+	if err != nil {
+		diags.Error(diag.Error, err)
+	}
+}
+
+// redactMain runs the command "redact": writes a capture with redacted values
+func redactMain(args []string) {
+	flags := flag.NewFlagSet("redact", flag.ContinueOnError)
+	var files includes
+	flags.Var(&files, "I", "include SCVD file name")
+	outputFile := flags.String("o", "", "output file name")
+	mode := flags.String("mode", "hash", "replace the values by a hash or a placeholder")
+	salt := flags.String("salt", "", "salt of the hashes")
+	keepStrings := flags.Bool("keep-strings", false, "do not redact the payload of the data records")
+	addresses := flags.String("addresses", "", "address ranges, e.g. 0x20000000-0x2003FFFF,0x40000000-0x5FFFFFFF")
+	var fields includes
+	flags.Var(&fields, "field", "value of the events matching conditions, e.g. component=MyNet:val2, can be repeated")
+	flags.Usage = func() {
+		fmt.Printf("Usage: %s redact -o <outputFile> [options] <logFile>\n", Progname)
+		infoOpt(flags, "I", "", "<fileName>")
+		infoOpt(flags, "o", "", "<fileName>")
+		infoOpt(flags, "", "mode", "<hash|placeholder>")
+		infoOpt(flags, "", "salt", "<text>")
+		infoOpt(flags, "", "keep-strings", "")
+		infoOpt(flags, "", "addresses", "<ranges>")
+		infoOpt(flags, "", "field", "<conditions:valN>")
+	}
+	flags.SetOutput(nopWriter{})
+	if err := flags.Parse(args); err != nil {
+		if err != flag.ErrHelp {
+			diags.Error(diag.Error, err)
+		}
+		return
+	}
+	if len(*outputFile) == 0 {
+		diags.Errorf(diag.Error, "redact requires -o <outputFile>")
+		return
+	}
+	if flags.NArg() != 1 {
+		diags.Errorf(diag.Error, "redact requires one input file")
+		return
+	}
+	opt := redact.Options{Mode: *mode, Salt: *salt, Strings: !*keepStrings}
+	var err error
+	if len(*addresses) != 0 {
+		if opt.Addresses, err = redact.ParseRanges(*addresses); err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+	}
+	for _, f := range fields {
+		field, err := redact.ParseField(f)
+		if err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+		opt.Fields = append(opt.Fields, field)
+	}
+
+	evdefs := make(map[uint16]scvd.Event)
+	typedefs := make(map[string]map[string]map[int16]string)
+	memberTypes := make(map[string]map[string]string)
+	var p []string = files
+	if err = scvd.Get(&p, evdefs, typedefs, memberTypes); err != nil {
+		diags.Error(diag.SCVD, err)
+		return
+	}
+	in, err := os.Open(flags.Arg(0))
+	if err != nil {
+		diags.Error(diag.Error, err)
+		return
+	}
+	defer in.Close()
+	out, err := os.Create(*outputFile)
+	if err != nil {
+		diags.Error(diag.Error, err)
+		return
+	}
+	r, err := redact.New(out, opt)
+	if err != nil {
+		_ = out.Close()
+		diags.Error(diag.Error, err)
+		return
+	}
+	if err = publish(in, evdefs, typedefs, memberTypes, r); err != nil {
+		_ = out.Close()
+		diags.Error(diag.Decode, err)
+		return
+	}
+	if err = r.End(); err == nil {
+		err = out.Close()
+	}
+	if err != nil {
+		diags.Error(diag.Error, err)
+	}
+}
+
+// generateMain runs the command "generate": writes a capture of a scenario
+func generateMain(args []string) {
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	outputFile := flags.String("o", "", "output file name")
+	flags.Usage = func() {
+		fmt.Printf("Usage: %s generate -o <outputFile> <scenarioFile>\n", Progname)
+		infoOpt(flags, "o", "", "<fileName>")
+	}
+	flags.SetOutput(nopWriter{})
+	if err := flags.Parse(args); err != nil {
+		if err != flag.ErrHelp {
+			diags.Error(diag.Error, err)
+		}
+		return
+	}
+	if len(*outputFile) == 0 {
+		diags.Errorf(diag.Error, "generate requires -o <outputFile>")
+		return
+	}
+	if flags.NArg() != 1 {
+		diags.Errorf(diag.Error, "generate requires one scenario file")
+		return
+	}
+	scenario, err := generate.Load(flags.Arg(0))
+	if err != nil {
+		diags.Error(diag.Error, err)
+		return
+	}
+	out, err := os.Create(*outputFile)
+	if err != nil {
+		diags.Error(diag.Error, err)
+		return
+	}
+	if _, err = scenario.Write(out); err != nil {
+		_ = out.Close()
+		diags.Error(diag.Error, err)
+		return
+	}
+	if err = out.Close(); err != nil {
+		diags.Error(diag.Error, err)
+	}
+}
+
+// scvdGenMain runs the command "scvd-gen": writes the skeleton of an SCVD
+// file with an event of every EventID definition of a C header
+func scvdGenMain(args []string) {
+	flags := flag.NewFlagSet("scvd-gen", flag.ContinueOnError)
+	outputFile := flags.String("o", "", "output file name, default: stdout")
+	name := flags.String("name", "", "name of the component, default: name of the header file")
+	flags.Usage = func() {
+		fmt.Printf("Usage: %s scvd-gen [-o <outputFile>] [--name <component>] <headerFile>\n", Progname)
+		infoOpt(flags, "o", "", "<fileName>")
+		infoOpt(flags, "", "name", "<component>")
+	}
+	flags.SetOutput(nopWriter{})
+	if err := flags.Parse(args); err != nil {
+		if err != flag.ErrHelp {
+			diags.Error(diag.Error, err)
+		}
+		return
+	}
+	if flags.NArg() != 1 {
+		diags.Errorf(diag.Error, "scvd-gen requires one header file")
+		return
+	}
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		diags.Error(diag.Error, err)
+		return
+	}
+	defer file.Close()
+	h, err := scvdgen.Parse(file)
+	if err != nil {
+		diags.Errorf(diag.Error, "%s: %v", flags.Arg(0), err)
+		return
+	}
+	if len(*name) == 0 {
+		*name = strings.TrimSuffix(filepath.Base(flags.Arg(0)), filepath.Ext(flags.Arg(0)))
+	}
+	if len(*outputFile) == 0 {
+		if err = h.Write(os.Stdout, *name); err != nil {
+			diags.Error(diag.Error, err)
+		}
+		return
+	}
+	out, err := os.Create(*outputFile)
+	if err != nil {
+		diags.Error(diag.Error, err)
+		return
+	}
+	if err = h.Write(out, *name); err != nil {
+		_ = out.Close()
+		diags.Error(diag.Error, err)
+		return
+	}
+	if err = out.Close(); err != nil {
+		diags.Error(diag.Error, err)
+	}
+}
+
+// headerGenMain runs the command "header-gen": writes a C header with the
+// event IDs of an SCVD file and macros recording the events
+func headerGenMain(args []string) {
+	flags := flag.NewFlagSet("header-gen", flag.ContinueOnError)
+	outputFile := flags.String("o", "", "output file name, default: stdout")
+	flags.Usage = func() {
+		fmt.Printf("Usage: %s header-gen [-o <outputFile>] <scvdFile>\n", Progname)
+		infoOpt(flags, "o", "", "<fileName>")
+	}
+	flags.SetOutput(nopWriter{})
+	if err := flags.Parse(args); err != nil {
+		if err != flag.ErrHelp {
+			diags.Error(diag.Error, err)
+		}
+		return
+	}
+	if flags.NArg() != 1 {
+		diags.Errorf(diag.Error, "header-gen requires one SCVD file")
+		return
+	}
+	viewer, err := scvd.Load(flags.Arg(0))
+	if err != nil {
+		diags.Error(diag.SCVD, err)
+		return
+	}
+	write := func(w io.Writer) error {
+		return scvdgen.WriteHeader(w, viewer, filepath.Base(flags.Arg(0)))
+	}
+	if len(*outputFile) == 0 {
+		if err = write(os.Stdout); err != nil {
+			diags.Error(diag.SCVD, err)
+		}
+		return
+	}
+	out, err := os.Create(*outputFile)
+	if err != nil {
+		diags.Error(diag.Error, err)
+		return
+	}
+	if err = write(out); err != nil {
+		_ = out.Close()
+		diags.Error(diag.SCVD, err)
+		return
+	}
+	if err = out.Close(); err != nil {
+		diags.Error(diag.Error, err)
+	}
+}
+
+// checkGolden compares the output file with the golden file and prints the
+// differences, with update the output is written to the golden file
+func checkGolden(goldenFile string, outputFile string, update bool) {
+	out, err := os.ReadFile(outputFile)
+	if err != nil {
+		diags.Error(diag.Error, err)
+		return
+	}
+	if update {
+		if err = os.WriteFile(goldenFile, out, 0666); err != nil {
+			diags.Error(diag.Error, err)
+		}
+		return
+	}
+	golden, err := os.ReadFile(goldenFile)
+	if err != nil {
+		diags.Error(diag.Error, err)
+		return
+	}
+	lines := func(b []byte) []string {
+		return strings.Split(strings.TrimSuffix(strings.ReplaceAll(string(b), "\r\n", "\n"), "\n"), "\n")
+	}
+	if d := diff.Text(goldenFile, "output", lines(golden), lines(out), diff.DefaultContext); d != "" {
+		fmt.Print(d)
+		diags.Errorf(diag.Differ, "the output differs from %s", goldenFile)
+	}
+}
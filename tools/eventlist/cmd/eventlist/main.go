@@ -19,13 +19,58 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"errors"
+	"eventlist/pkg/annotate"
+	"eventlist/pkg/assert"
+	"eventlist/pkg/capture"
+	"eventlist/pkg/chart"
+	"eventlist/pkg/checklist"
+	"eventlist/pkg/config"
+	"eventlist/pkg/dashboard"
+	"eventlist/pkg/diag"
 	"eventlist/pkg/elf"
+	"eventlist/pkg/errctx"
+	"eventlist/pkg/event"
+	"eventlist/pkg/fetch"
+	"eventlist/pkg/gaps"
+	"eventlist/pkg/health"
+	"eventlist/pkg/heatmap"
+	"eventlist/pkg/influx"
+	"eventlist/pkg/levels"
+	"eventlist/pkg/live"
+	"eventlist/pkg/marker"
+	"eventlist/pkg/metrics"
+	"eventlist/pkg/mqtt"
+	"eventlist/pkg/otel"
 	"eventlist/pkg/output"
+	"eventlist/pkg/override"
+	"eventlist/pkg/pack"
+	"eventlist/pkg/pager"
+	"eventlist/pkg/plugin"
+	"eventlist/pkg/probe"
+	"eventlist/pkg/profile"
+	"eventlist/pkg/rotate"
+	"eventlist/pkg/rtos"
+	"eventlist/pkg/script"
+	"eventlist/pkg/selftrace"
+	"eventlist/pkg/severity"
+	"eventlist/pkg/sink"
+	"eventlist/pkg/syslog"
+	"eventlist/pkg/unknown"
+	"eventlist/pkg/where"
 	"eventlist/pkg/xml/scvd"
+	"eventlist/pkg/xml/svd"
+	"eventlist/pkg/xml/xsd"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
+	"time"
 )
 
 var Progname string
@@ -47,6 +92,121 @@ func (s *includes) Set(v string) error {
 
 var paths includes
 
+// diags reports the errors and warnings and keeps the exit code
+var diags = diag.New("eventlist", os.Stdout, os.Stderr)
+
+// exitCode is the exit code of the last run of main
+var exitCode int
+
+// isTerminal returns true if file is a terminal
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// filterCommands changes the event filter of the target with the filters
+// of --recorder-filter read from the standard input, one per line
+func filterCommands(in io.Reader, flt live.Filterer) {
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 {
+			continue
+		}
+		f, err := probe.ParseFilter(line)
+		if err == nil {
+			err = flt.SetFilter(f)
+		}
+		if err != nil {
+			diags.Warning(diag.OK, err.Error())
+		}
+	}
+}
+
+// reportFailures reports the failures of --strict, a line per kind
+func reportFailures(f *output.Failures) {
+	if f.Count() == 0 {
+		return
+	}
+	var b strings.Builder
+	_ = f.Report(&b)
+	for _, line := range strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n") {
+		diags.Errorf(diag.Decode, "--strict: %s", line)
+	}
+}
+
+// fetchSCVD replaces the URLs of SCVD files by the downloaded files
+func fetchSCVD(files []string) ([]string, error) {
+	local := make([]string, len(files))
+	for i, name := range files {
+		local[i] = name
+		if fetch.IsURL(name) {
+			var err error
+			if local[i], err = fetch.Get(name); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return local, nil
+}
+
+// validateSCVD checks the SCVD files against the bundled schema, the
+// violations are warnings, with strict errors, returns false on errors
+func validateSCVD(files []string, strict bool) bool {
+	schema := xsd.SCVD()
+	ok := true
+	for _, name := range files {
+		file, err := os.Open(name)
+		if err != nil {
+			continue // reported by reading the SCVD files
+		}
+		violations, _ := schema.Validate(file)
+		file.Close()
+		for _, v := range violations {
+			if strict {
+				diags.Errorf(diag.SCVD, "%s:%s", name, v)
+				ok = false
+			} else {
+				diags.Warning(diag.OK, fmt.Sprintf("%s:%s", name, v))
+			}
+		}
+	}
+	return ok
+}
+
+// reportName returns the name of a file target in the reports of
+// --deterministic, without the directory that differs between hosts
+func reportName(target string) string {
+	if strings.Contains(target, "://") {
+		return target
+	}
+	return filepath.Base(target)
+}
+
+// newMarkers creates the markers of the event ID ranges and of the SCVD
+// attribute marker, nil if there are none
+func newMarkers(ids string, evdefs map[uint16]scvd.Event) (*marker.Markers, error) {
+	m := marker.New()
+	found := false
+	if len(ids) != 0 {
+		for _, r := range strings.Split(ids, ",") {
+			first, last, err := plugin.ParseRange(r)
+			if err != nil {
+				return nil, err
+			}
+			m.Add(first, last)
+		}
+		found = true
+	}
+	for _, evdef := range evdefs {
+		found = found || evdef.Marker
+	}
+	if !found {
+		return nil, nil
+	}
+	return m, nil
+}
+
 func infoOpt(flags *flag.FlagSet, sopt string, lopt string, opt string) {
 	fmt.Print("\t")
 	if sopt != "" {
@@ -64,7 +224,11 @@ func infoOpt(flags *flag.FlagSet, sopt string, lopt string, opt string) {
 	if lopt == "help" {
 		fmt.Printf("\t%s\n", "show short help")
 	} else {
-		f := flags.Lookup(sopt)
+		name := sopt
+		if name == "" {
+			name = lopt
+		}
+		f := flags.Lookup(name)
 		if f == nil {
 			fmt.Printf("\t%s\n", "unknown option")
 		} else {
@@ -78,7 +242,6 @@ func infoOpt(flags *flag.FlagSet, sopt string, lopt string, opt string) {
 }
 
 func main() {
-	var err error
 	Progname = os.Args[0]
 	idx := strings.LastIndexByte(Progname, '/')
 	if idx == -1 {
@@ -91,90 +254,991 @@ func main() {
 		Progname = Progname[:idx]
 	}
 
-	commFlag := flag.CommandLine
+	// the errors are reported with the exit code of the diagnostics
+	commFlag := flag.NewFlagSet(Progname, flag.ContinueOnError)
+	commFlag.SetOutput(nopWriter{})
 
 	// --- this is only for unit tests of main()
 	testRun := flag.Lookup("test.run")
 	if testRun != nil {
-		commFlag = flag.NewFlagSet("test", flag.ContinueOnError)
 		flag.CommandLine.VisitAll(func(flag *flag.Flag) {
 			commFlag.Var(flag.Value, flag.Name, flag.Usage)
 		})
 	}
 	// ---
 
+	diags = diag.New(Progname, os.Stdout, os.Stderr)
+	run(commFlag, os.Args[1:], nil)
+	exitCode = diags.Exit()
+	if exitCode != 0 && testRun == nil {
+		os.Exit(exitCode)
+	}
+}
+
+// run decodes a capture with the options of the command line, cmd restricts
+// the options to the ones of a command, nil is the invocation without command
+func run(commFlag *flag.FlagSet, args []string, cmd *command) {
+	var err error
 	usage := false
 
 	commFlag.Usage = func() {
-		fmt.Printf("Usage: %s [-I <scvdFile>]... [-o <outputFile>] [-a <elf/axfFile>] [-b] <logFile>\n",
-			Progname)
-		infoOpt(commFlag, "a", "", "<fileName>")
-		infoOpt(commFlag, "b", "begin", "")
-		infoOpt(commFlag, "h", "help", "")
-		infoOpt(commFlag, "I", "", "<fileName>")
-		infoOpt(commFlag, "o", "", "<fileName>")
-		infoOpt(commFlag, "s", "statistic", "")
-		infoOpt(commFlag, "V", "version", "")
-		infoOpt(commFlag, "f", "format", "<formatType>")
-		infoOpt(commFlag, "l", "level", "<Error|API|Op|Detail>")
+		if cmd != nil {
+			fmt.Printf("Usage: %s %s %s\n", Progname, cmd.name, cmd.args)
+		} else {
+			fmt.Printf("Usage: %s [-I <scvdFile>]... [-o <outputFile>] [-a <elf/axfFile>] [-b] <logFile>\n",
+				Progname)
+			fmt.Printf("       %s <command> [options] [args]\n", Progname)
+			fmt.Println("Commands:")
+			for i := range commands {
+				fmt.Printf("\t%s\t%s\n", commands[i].name, commands[i].summary)
+			}
+			fmt.Println("Options:")
+		}
+		for _, o := range optionInfos {
+			if cmd.allows(o.sopt) || cmd.allows(o.lopt) || o.lopt == "help" {
+				infoOpt(commFlag, o.sopt, o.lopt, o.opt)
+			}
+		}
 		usage = true
 	}
 	// parse command line
 	commFlag.Var(&paths, "I", "include SCVD file name")
 	outputFile := commFlag.String("o", "", "output file name")
 	elfFile := commFlag.String("a", "", "elf/axf file name")
-	formatType := commFlag.String("f", "", "format type: txt, json, xml")
+	formatType := commFlag.String("f", "", "format type: txt, json, xml, html, jsonl, stats")
 	level := commFlag.String("l", "", "level: Error|API|Op|Detail")
+	floatType := commFlag.String("float", "", "interpret %T values as float, double or half")
+	fixedType := commFlag.String("fixed", "", "interpret %T values as fixed point number, e.g. Q15, Q8.8")
+	profileName := commFlag.String("profile", "", "decode profile of the target, list shows the available profiles")
+	profilesFile := commFlag.String("profiles", "", "YAML file with additional decode profiles")
+	precision := commFlag.Int("precision", 6, "fraction digits of %T floating values, -1 for shortest")
+	scriptFile := commFlag.String("script", "", "Lua analysis script file name")
+	templateFile := commFlag.String("template", "", "text/template file writing the events")
+	severityFile := commFlag.String("severity", "", "YAML file mapping event IDs to levels")
+	packs := commFlag.String("pack", "", "CMSIS packs providing SCVD files, e.g. ARM::CMSIS-RTX@5.9.0, downloaded from the pack index")
+	cacheDir := commFlag.String("cache-dir", "", "directory of the downloaded SCVD files, default: eventlist in the user cache directory")
+	kernel := commFlag.String("rtos", "", "bundled event definitions of an RTOS: freertos or zephyr")
+	componentVersions := commFlag.String("component-version", "", "versions of the firmware components selecting the SCVD files, e.g. RTX5=5.9.0,MyNet=1.2")
+	overrideFile := commFlag.String("override", "", "YAML file mapping event IDs to formats or to ignore, taking precedence over the SCVD files")
+	checklistFile := commFlag.String("checklist", "", "YAML checklist of required events")
+	assertFile := commFlag.String("assert", "", "YAML rules the events must fulfill, exits with 5 if one fails")
+	goldenFile := commFlag.String("golden", "", "compare the output with a golden file, exits with 7 if it differs")
+	var updateGolden bool
+	commFlag.BoolVar(&updateGolden, "update-golden", false, "write the output to the golden file instead of comparing")
+	columns := commFlag.String("columns", "", "columns of the event list: index,time,component,event,level,thread,cumulative,message,raw")
+	liveSource := commFlag.String("live", "", "live event source: tcp://host:port, serial:port[,baudrate], udp://[host]:port, gdb://host:port or growing file")
+	var recorderFilters includes
+	commFlag.Var(&recorderFilters, "recorder-filter", "change the event filter of a probe target: [-]<levels>:<components>, e.g. Op|Detail:0x80-0x8F")
+	var resetTarget bool
+	commFlag.BoolVar(&resetTarget, "reset", false, "reset and run the target of a probe source to capture the boot")
+	pyocdTarget := commFlag.String("pyocd", "", "read the Event Recorder buffer of a target with pyOCD: <target>[,<probe>] or auto")
+	framing := commFlag.String("framing", "", "framing of the live records: none, cobs, slip or auto")
+	captureFile := commFlag.String("capture", "", "write the received records to a file, filtered by --where")
+	var wheres includes
+	var decoders includes
+	var outputs includes
+	commFlag.Var(&outputs, "out", "further output <format>=<fileName> written in the same pass, e.g. jsonl=events.jsonl")
+	commFlag.Var(&decoders, "decoder", "decoder process of an event ID range <first>-<last>=<command>")
+	svdFile := commFlag.String("svd", "", "CMSIS-SVD file decoding the register dumps of --svd-events")
+	svdEvents := commFlag.String("svd-events", "", "event ID ranges of register dumps with address and values, e.g. 0xA100-0xA1FF,0xA205")
+	commFlag.Var(&wheres, "where", "print events matching conditions key=pattern, e.g. component=RTX*,level=Error")
+	httpAddr := commFlag.String("http", "", "serve live status page at address, e.g. localhost:8080")
+	dashboardFile := commFlag.String("dashboard", "", "YAML dashboard file name of the html report")
+	traceFile := commFlag.String("trace-self", "", "write the timing of the decoder stages as Chrome trace file")
+	heatmapFile := commFlag.String("heatmap", "", "heatmap of the event activity, file name ending with .csv, .json or .png")
+	heatmapBuckets := commFlag.Int("heatmap-buckets", heatmap.DefaultBuckets, "number of time buckets of the heatmap")
+	heatmapRows := commFlag.String("heatmap-rows", "event", "rows of the heatmap: event or component")
+	chartStyle := commFlag.String("chart", "", "draw a timeline of the events per component: unicode or ascii")
+	chartWidth := commFlag.Int("chart-width", chart.DefaultWidth, "number of time columns of the timeline chart")
+	chartEvents := commFlag.String("chart-events", "", "event ID ranges drawn as one line per event type, e.g. 0xEF00-0xEF1F,0x0A01")
+	mqttURL := commFlag.String("mqtt", "", "publish the events to an MQTT broker, e.g. mqtt://host:1883/prefix")
+	sinkURL := commFlag.String("sink", "", "send the events to a message bus, e.g. kafka://host:9092/topic or nats://host:4222/subject")
+	syslogTarget := commFlag.String("syslog", "", "forward the events to a syslog server, e.g. udp://host:514, or to a file")
+	syslogFormat := commFlag.String("syslog-format", "", "format of the forwarded events: rfc5424 (default) or json")
+	influxTarget := commFlag.String("influx", "", "write event counts and durations in InfluxDB line protocol to a file or write API URL")
+	otlpTarget := commFlag.String("otlp", "", "export the start/stop event pairs as spans to an OTLP/gRPC receiver, e.g. localhost:4317")
+	healthFile := commFlag.String("health-json", "", "write the capture health summary as JSON file")
+	var showHealth bool
+	commFlag.BoolVar(&showHealth, "health", false, "print the capture health summary at the top")
+	gapCount := commFlag.Int("gaps", 0, "report the n largest gaps between consecutive events and the silent time")
+	gapTime := commFlag.Float64("gap-time", 0.1, "shortest time without events in s counted as gap")
+	errorContext := commFlag.Int("error-context", 0, "report the n events before and after every Error event")
+	errorContextDir := commFlag.String("error-context-dir", "", "directory of the JSONL files of the error contexts")
+	configFile := commFlag.String("config", "", "config file with the defaults of the options, default: "+config.Name)
+	var noConfig bool
+	commFlag.BoolVar(&noConfig, "no-config", false, "do not read the config files")
+	var tree bool
+	commFlag.BoolVar(&tree, "tree", false, "indent the events between start and stop events")
+	annotationFile := commFlag.String("annotations", "", "YAML file of annotations at event indexes or times added to the event list")
+	markerIDs := commFlag.String("markers", "", "event ID ranges of the marker events listed after the statistic, e.g. 0xA000-0xA00F")
+	var levelStats bool
+	commFlag.BoolVar(&levelStats, "levels", false, "print the number of events per level after the statistic")
+	var objectNames bool
+	commFlag.BoolVar(&objectNames, "object-names", false, "print the names of the RTOS objects instead of their IDs")
+	colorMode := commFlag.String("color", "auto", "color the event list by level and component: auto, always or never")
+	hyperlinks := commFlag.String("hyperlinks", "auto", "link the source locations file:line of the event list to the files: auto, always or never")
+	sortKey := commFlag.String("sort", "", "sort the event list by time, index, component or duration")
+	head := commFlag.Int("head", 0, "print the first n events only")
+	tail := commFlag.Int("tail", 0, "print the last n events only")
+	skip := commFlag.Int("skip", 0, "do not print the first n events")
+	limit := commFlag.Int("limit", 0, "print at most n events")
+	var useIndex bool
+	commFlag.BoolVar(&useIndex, "index", false, "write an index file next to the log file and seek with it in later runs")
+	jobs := commFlag.Int("jobs", 0, "number of workers formatting the event values, 0 for the number of CPUs")
+	outputBuffer := commFlag.Int("output-buffer", 64<<10, "size of the output buffer in bytes")
+	statsInterval := commFlag.Duration("stats-interval", 0, "write a statistics snapshot of the live events to stderr at the interval, e.g. 5s")
+	flushInterval := commFlag.Duration("flush-interval", 0, "longest time live events stay in the output buffer, 0 flushes every event")
+	rotateSize := commFlag.Int64("rotate-size", 0, "rotate the live output file when it reaches the size in bytes")
+	rotateInterval := commFlag.Duration("rotate-interval", 0, "rotate the live output file after the duration, e.g. 1h")
+	var rotateCompress bool
+	commFlag.BoolVar(&rotateCompress, "rotate-compress", false, "compress the rotated live output files with gzip")
+	var reverse bool
+	commFlag.BoolVar(&reverse, "reverse", false, "print the event list in reverse order")
+	var noPager bool
+	commFlag.BoolVar(&noPager, "no-pager", false, "do not pipe the output to a terminal through $PAGER")
+	var quiet bool
+	commFlag.BoolVar(&quiet, "q", false, "do not show the progress bar")
+	commFlag.BoolVar(&quiet, "quiet", false, "do not show the progress bar")
+	var squash bool
+	commFlag.BoolVar(&squash, "squash", false, "collapse repeated identical events into one line")
+	var ack bool
+	commFlag.BoolVar(&ack, "ack", false, "use acknowledgment protocol on tcp and serial live sources")
+	queueSize := commFlag.Int("queue", 0, "queue up to n live records between the source and the decoding, 0 for no queue")
+	queuePolicy := commFlag.String("queue-policy", "", "policy of a full queue: block (default), drop-oldest or drop-newest")
+	var resync bool
+	commFlag.BoolVar(&resync, "resync", false, "skip invalid live data up to the next record and reconnect lost tcp and serial links")
+	var strict bool
+	commFlag.BoolVar(&strict, "strict", false, "exit with an error if an event cannot be decoded completely")
+	var unknownEvents bool
+	commFlag.BoolVar(&unknownEvents, "unknown-events", false, "report the event IDs without SCVD definition with counts and a sample")
+	var deterministic bool
+	commFlag.BoolVar(&deterministic, "deterministic", false, "byte-identical output of the same inputs: no wall clock times, host names and directories")
+	var watchMode bool
+	commFlag.BoolVar(&watchMode, "watch", false, "decode again when the input file, the ELF file or an SCVD file changes")
 	var statBegin bool
 	commFlag.BoolVar(&statBegin, "b", false, "show statistic at beginning")
 	commFlag.BoolVar(&statBegin, "begin", false, "show statistic at beginning")
 	var showVersion bool
 	commFlag.BoolVar(&showVersion, "V", false, "show version info")
 	commFlag.BoolVar(&showVersion, "version", false, "show version info")
+	diagnostics := commFlag.String("diagnostics", "text", "format of the errors and warnings: text or json")
 	var showStatistic bool
 	commFlag.BoolVar(&showStatistic, "s", false, "show statistic only")
 	commFlag.BoolVar(&showStatistic, "statistic", false, "show statistic only")
-	err = commFlag.Parse(os.Args[1:])
+	var statsOnly bool
+	commFlag.BoolVar(&statsOnly, "stats-only", false, "show statistic only, without formatting the events")
+	err = commFlag.Parse(args)
 
+	if err != nil && err != flag.ErrHelp {
+		diags.Error(diag.Error, err)
+	}
 	if usage || err != nil {
 		return
 	}
+	if err = diags.SetFormat(*diagnostics); err != nil {
+		diags.Error(diag.Error, err)
+		return
+	}
+
+	if cmd == nil && commFlag.NArg() > 0 {
+		if c := findCommand(commFlag.Arg(0)); c != nil {
+			if c.main != nil {
+				c.main(commFlag.Args()[1:])
+			} else {
+				flags := flag.NewFlagSet(c.name, flag.ContinueOnError)
+				flags.SetOutput(nopWriter{})
+				run(flags, commFlag.Args()[1:], c)
+			}
+			return
+		}
+	}
+	eventFile := commFlag.Args()
+	if cmd != nil {
+		if eventFile, err = cmd.check(commFlag); err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+	}
 
 	if showVersion {
 		fmt.Printf("%s %s\n", Progname, versionInfo)
 		return
 	}
 
-	eventFile := commFlag.Args()
+	cfg := &config.Config{}
+	if len(*configFile) == 0 {
+		*configFile = os.Getenv(config.EnvPrefix + "CONFIG")
+	}
+	if len(*configFile) != 0 {
+		cfg, err = config.Load(*configFile)
+	} else if !noConfig {
+		cfg, err = config.Find()
+	}
+	if err == nil {
+		var env *config.Config
+		if env, err = config.Env(os.LookupEnv); err == nil {
+			cfg.Merge(env) // the environment variables take precedence over the config files
+		}
+	}
+	if err != nil {
+		diags.Error(diag.Error, err)
+		return
+	}
+	set := make(map[string]bool) // options given on the command line
+	commFlag.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	if !set["a"] && len(cfg.ELF) != 0 {
+		*elfFile = cfg.ELF
+	}
+	if !set["f"] && len(cfg.Format) != 0 {
+		*formatType = cfg.Format
+	}
+	if !set["l"] && len(cfg.Level) != 0 {
+		*level = cfg.Level
+	}
+	if !set["where"] && len(cfg.Where) != 0 {
+		wheres = cfg.Where
+	}
+	if !set["profile"] && len(cfg.Profile) != 0 {
+		*profileName = cfg.Profile
+	}
+	if !set["columns"] && len(cfg.Columns) != 0 {
+		*columns = cfg.Columns
+	}
+
+	profiles := profile.New()
+	if len(*profilesFile) != 0 {
+		if err = profiles.Load(*profilesFile); err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+	}
+	if *profileName == "list" {
+		_ = profiles.List(os.Stdout)
+		return
+	}
+	output.TimeFactor = nil // default clock until a profile, the config or a clock event sets it
+	var prof profile.Profile
+	if len(*profileName) != 0 {
+		if prof, err = profiles.Get(*profileName); err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+		if prof.Clock > 0 {
+			output.TimeFactor = new(float64)
+			*output.TimeFactor = 1.0 / prof.Clock
+		}
+		if len(*floatType) == 0 && len(*fixedType) == 0 {
+			*floatType = prof.Float
+			*fixedType = prof.Fixed
+		}
+		if len(*kernel) == 0 {
+			*kernel = prof.RTOS
+		}
+	}
+	if err = event.SetByteOrder(prof.Endian); err != nil {
+		diags.Error(diag.Error, err)
+		return
+	}
+	if cfg.Clock > 0 && !set["profile"] {
+		output.TimeFactor = new(float64)
+		*output.TimeFactor = 1.0 / cfg.Clock
+	}
+
+	if len(*pyocdTarget) != 0 {
+		if len(*liveSource) != 0 {
+			diags.Errorf(diag.Error, "only one of --live and --pyocd allowed")
+			return
+		}
+		*liveSource = "pyocd:" + *pyocdTarget
+	}
+	var filters []probe.Filter
+	for _, spec := range recorderFilters {
+		f, err := probe.ParseFilter(spec)
+		if err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+		filters = append(filters, f)
+	}
+	if len(*liveSource) != 0 {
+		if len(eventFile) != 0 {
+			diags.Errorf(diag.Error, "no input file allowed with --live")
+			return
+		}
+		if len(*sortKey) != 0 || reverse {
+			diags.Errorf(diag.Error, "--sort and --reverse not allowed with --live")
+			return
+		}
+		if *head != 0 || *tail != 0 || *skip != 0 || *limit != 0 {
+			diags.Errorf(diag.Error, "--head, --tail, --skip and --limit not allowed with --live")
+			return
+		}
+		if watchMode {
+			diags.Errorf(diag.Error, "--watch not allowed with --live")
+			return
+		}
+		if len(*queuePolicy) != 0 && *queueSize == 0 {
+			diags.Errorf(diag.Error, "--queue-policy requires --queue")
+			return
+		}
+	} else {
+		if len(recorderFilters) != 0 {
+			diags.Errorf(diag.Error, "--recorder-filter requires --live")
+			return
+		}
+		if resetTarget {
+			diags.Errorf(diag.Error, "--reset requires --live")
+			return
+		}
+		if *flushInterval != 0 {
+			diags.Errorf(diag.Error, "--flush-interval requires --live")
+			return
+		}
+		if *statsInterval != 0 {
+			diags.Errorf(diag.Error, "--stats-interval requires --live")
+			return
+		}
+		if len(*httpAddr) != 0 {
+			diags.Errorf(diag.Error, "--http requires --live")
+			return
+		}
+		if ack {
+			diags.Errorf(diag.Error, "--ack requires --live")
+			return
+		}
+		if resync {
+			diags.Errorf(diag.Error, "--resync requires --live")
+			return
+		}
+		if *queueSize != 0 {
+			diags.Errorf(diag.Error, "--queue requires --live")
+			return
+		}
+		if len(*framing) != 0 {
+			diags.Errorf(diag.Error, "--framing requires --live")
+			return
+		}
+		if len(*captureFile) != 0 {
+			diags.Errorf(diag.Error, "--capture requires --live")
+			return
+		}
+		if len(eventFile) == 0 {
+			diags.Errorf(diag.Error, "missing input file")
+			return
+		}
+		if len(eventFile) > 1 {
+			diags.Errorf(diag.Error, "only one binary input file allowed")
+			return
+		}
+	}
+
+	if len(*floatType) != 0 && len(*fixedType) != 0 {
+		diags.Errorf(diag.Error, "only one of --float and --fixed allowed")
+		return
+	}
+	if err = event.SetFloatType(*floatType); err != nil {
+		diags.Error(diag.Error, err)
+		return
+	}
+	if err = event.SetFixedType(*fixedType); err != nil {
+		diags.Error(diag.Error, err)
+		return
+	}
+	event.Precision = *precision
+
+	output.Tree = tree
+	output.ObjectNames = objectNames
+	if err = output.SetColorMode(*colorMode); err != nil {
+		diags.Error(diag.Error, err)
+		return
+	}
+	if err = output.SetHyperlinks(*hyperlinks); err != nil {
+		diags.Error(diag.Error, err)
+		return
+	}
+	output.Squash = squash
+	output.StatsOnly = statsOnly
+	showStatistic = showStatistic || statsOnly
+	output.Index = useIndex
+	if *outputBuffer <= 0 || *flushInterval < 0 {
+		diags.Errorf(diag.Error, "invalid --output-buffer or --flush-interval")
+		return
+	}
+	output.BufferSize = *outputBuffer
+	output.FlushInterval = *flushInterval
+	output.Rotate = rotate.Options{Size: *rotateSize, Interval: *rotateInterval, Compress: rotateCompress}
+	if *rotateSize < 0 || *rotateInterval < 0 {
+		diags.Errorf(diag.Error, "invalid --rotate-size or --rotate-interval")
+		return
+	}
+	if (output.Rotate.Enabled() || rotateCompress) && (len(*liveSource) == 0 || len(*outputFile) == 0) {
+		diags.Errorf(diag.Error, "--rotate-size, --rotate-interval and --rotate-compress require --live and -o")
+		return
+	}
+	if rotateCompress && !output.Rotate.Enabled() {
+		diags.Errorf(diag.Error, "--rotate-compress requires --rotate-size or --rotate-interval")
+		return
+	}
+	output.Workers = *jobs
+	if output.Workers <= 0 {
+		output.Workers = runtime.NumCPU()
+	}
+	output.Where = nil
+	if len(wheres) != 0 {
+		if output.Where, err = where.Parse(wheres); err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+	}
+	// the progress bar would mix with the event list printed to the terminal
+	output.Progress = !quiet && isTerminal(os.Stderr) && (len(*outputFile) != 0 || !isTerminal(os.Stdout))
+	var epoch time.Time
+	if deterministic {
+		output.Progress = false
+		epoch = time.Unix(0, 0).UTC()
+	}
+	influx.Start, syslog.Start, syslog.Anonymous = epoch, epoch, deterministic
+	output.Pager = ""
+	if !noPager {
+		output.Pager = pager.Command()
+	}
+	if *head < 0 || *tail < 0 || *skip < 0 || *limit < 0 {
+		diags.Errorf(diag.Error, "negative number of events")
+		return
+	}
+	if *head != 0 && *limit != 0 {
+		diags.Errorf(diag.Error, "only one of --head and --limit allowed")
+		return
+	}
+	output.Outputs = nil
+	for _, spec := range outputs {
+		target, err := output.ParseTarget(spec)
+		if err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+		output.Outputs = append(output.Outputs, target)
+	}
+	output.Export = nil
+	if len(*templateFile) != 0 {
+		if set["f"] {
+			diags.Errorf(diag.Error, "only one of -f and --template allowed")
+			return
+		}
+		if output.Export, err = output.LoadTemplate(*templateFile); err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+	}
+	if *tail != 0 && (*head != 0 || *skip != 0 || *limit != 0) {
+		diags.Errorf(diag.Error, "--tail cannot be combined with --head, --skip or --limit")
+		return
+	}
+	output.Skip = *skip
+	output.Limit = *limit + *head
+	output.Tail = *tail
+	output.Reverse = reverse
+	output.Sort = ""
+	if len(*sortKey) != 0 {
+		if err = output.SetSort(*sortKey); err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+	}
+	if len(*columns) != 0 {
+		if err = output.SetColumns(*columns); err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+	}
+
+	if len(*dashboardFile) != 0 {
+		if output.Dashboard, err = dashboard.Load(*dashboardFile); err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+	}
 
-	if len(eventFile) == 0 {
-		fmt.Println(Progname + ": missing input file")
+	if watchMode {
+		files := append([]string{}, eventFile...)
+		if len(*elfFile) != 0 {
+			files = append(files, *elfFile)
+		}
+		scvdFiles := []string(paths)
+		if !set["I"] && len(cfg.SCVD) != 0 {
+			if scvdFiles, err = cfg.SCVDFiles(); err != nil {
+				diags.Error(diag.Error, err)
+				return
+			}
+		}
+		for _, name := range scvdFiles {
+			if !fetch.IsURL(name) {
+				files = append(files, name)
+			}
+		}
+		files = append(files, cfg.Files...)
+		watchRun(args, files)
 		return
 	}
-	if len(eventFile) > 1 {
-		fmt.Println(Progname + ": only one binary input file allowed")
+
+	output.Decoders = nil
+	if len(decoders) != 0 {
+		output.Decoders = new(plugin.Set)
+		defer func() {
+			if err := output.Decoders.Close(); err != nil {
+				diags.Error(diag.Error, err)
+			}
+		}()
+		for _, spec := range decoders {
+			first, last, command, err := plugin.Parse(spec)
+			if err != nil {
+				diags.Error(diag.Error, err)
+				return
+			}
+			p, err := plugin.Start(command)
+			if err != nil {
+				diags.Error(diag.Error, err)
+				return
+			}
+			output.Decoders.Add(first, last, p)
+		}
+	}
+	if len(*svdEvents) != 0 && len(*svdFile) == 0 {
+		diags.Errorf(diag.Error, "--svd-events requires --svd")
 		return
 	}
+	if len(*svdFile) != 0 {
+		if len(*svdEvents) == 0 {
+			diags.Errorf(diag.Error, "--svd requires --svd-events")
+			return
+		}
+		device, err := svd.Load(*svdFile)
+		if err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+		if output.Decoders == nil {
+			output.Decoders = new(plugin.Set)
+		}
+		for _, ids := range strings.Split(*svdEvents, ",") {
+			first, last, err := plugin.ParseRange(ids)
+			if err != nil {
+				diags.Error(diag.Error, err)
+				return
+			}
+			output.Decoders.Add(first, last, device)
+		}
+	}
 
+	output.Trace = nil
+	if len(*traceFile) != 0 {
+		trace := selftrace.New()
+		output.Trace = trace
+		defer func() {
+			if err := trace.WriteFile(*traceFile); err != nil {
+				diags.Error(diag.Error, err)
+			}
+		}()
+	}
+
+	endSpan := output.Trace.Span("read elf")
+	elf.Warning = func(err error) { diags.Warning(diag.OK, err.Error()) }
 	if elfFile != nil && len(*elfFile) != 0 {
 		if err = elf.Sections.Readelf(elfFile); err != nil {
-			fmt.Print(Progname + ": ")
-			fmt.Println(err)
+			diags.Error(diag.Error, err)
 			return
 		}
 	}
+	endSpan()
 	evdefs := make(map[uint16]scvd.Event)
 	typedefs := make(map[string]map[string]map[int16]string)
+	memberTypes := make(map[string]map[string]string)
 
+	endSpan = output.Trace.Span("read scvd")
 	var p []string = paths
-	if err = scvd.Get(&p, evdefs, typedefs); err != nil {
-		fmt.Print(Progname + ": ")
-		fmt.Println(err)
+	if !set["I"] && len(cfg.SCVD) != 0 {
+		if p, err = cfg.SCVDFiles(); err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+	}
+	fetch.CacheDir = *cacheDir
+	if p, err = fetchSCVD(p); err != nil {
+		diags.Error(diag.SCVD, err)
 		return
 	}
+	if len(*packs) != 0 {
+		for _, item := range strings.Split(*packs, ",") {
+			id, err := pack.ParseID(item)
+			if err != nil {
+				diags.Error(diag.Error, err)
+				return
+			}
+			files, err := pack.SCVDFiles(id)
+			if err != nil {
+				diags.Error(diag.SCVD, err)
+				return
+			}
+			p = append(p, files...)
+		}
+	}
+	var versions map[string]string
+	if len(*componentVersions) != 0 {
+		if versions, err = scvd.ParseVersions(*componentVersions); err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+	}
+	var warnings []string
+	p, warnings = scvd.Select(p, versions)
+	for _, warning := range warnings {
+		diags.Warning(diag.OK, warning)
+	}
+	if len(*kernel) != 0 { // the SCVD files given with -I take precedence
+		var data []byte
+		if data, err = rtos.Definitions(*kernel); err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+		if err = scvd.Parse(bytes.NewReader(data), evdefs, typedefs, memberTypes); err != nil {
+			diags.Error(diag.SCVD, err)
+			return
+		}
+	}
+	if err = scvd.Get(&p, evdefs, typedefs, memberTypes); err != nil {
+		diags.Error(diag.SCVD, err)
+		return
+	}
+	if !validateSCVD(p, strict) {
+		return
+	}
+	endSpan()
+	output.Ignore = nil
+	if len(*overrideFile) != 0 {
+		var m override.Map
+		if m, err = override.Load(*overrideFile); err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+		m.Apply(evdefs)
+		output.Ignore = m.Ignored()
+	}
+	event.Strict = strict
+	output.Strict = nil
+	if strict {
+		output.Strict = output.NewFailures(evdefs)
+		defer reportFailures(output.Strict)
+	}
+
+	if len(*severityFile) != 0 {
+		var m severity.Map
+		if m, err = severity.Load(*severityFile); err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+		m.Apply(evdefs)
+	}
+
+	output.Analyzers = nil
+	output.Summary = nil
+	if *gapTime <= 0 || *gapCount < 0 {
+		diags.Errorf(diag.Error, "--gap-time and --gaps must be positive")
+		return
+	}
+	health.GapTime = *gapTime
+	if showHealth || len(*healthFile) != 0 {
+		h := health.New(*healthFile)
+		if showHealth {
+			output.Summary = h
+		}
+		output.Analyzers = append(output.Analyzers, h)
+	}
 
-	if err := output.Print(outputFile, formatType, level, &eventFile[0], evdefs, typedefs, statBegin, showStatistic); err != nil {
-		fmt.Print(Progname + ": ")
-		fmt.Println(err)
+	if len(*checklistFile) != 0 {
+		var c *checklist.Checklist
+		if c, err = checklist.Load(*checklistFile); err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+		output.Analyzers = append(output.Analyzers, c)
+	}
+
+	var assertions *assert.Assertions
+	if len(*assertFile) != 0 {
+		if assertions, err = assert.Load(*assertFile); err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+		output.Analyzers = append(output.Analyzers, assertions)
+	}
+
+	if len(*scriptFile) != 0 {
+		var s *script.Script
+		if s, err = script.Load(*scriptFile); err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+		output.Analyzers = append(output.Analyzers, s)
+	}
+
+	if len(*errorContextDir) != 0 && *errorContext == 0 {
+		diags.Errorf(diag.Error, "--error-context-dir requires --error-context")
+		return
+	}
+	if *errorContext != 0 {
+		var c *errctx.Context
+		if c, err = errctx.New(*errorContext, *errorContextDir); err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+		output.Analyzers = append(output.Analyzers, c)
+	}
+
+	if *gapCount != 0 {
+		output.Analyzers = append(output.Analyzers, gaps.New(*gapCount, *gapTime))
+	}
+
+	output.Annotations = nil
+	if len(*annotationFile) != 0 {
+		if output.Annotations, err = annotate.Load(*annotationFile); err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+	}
+
+	output.Marker = nil
+	var markers *marker.Markers
+	if markers, err = newMarkers(*markerIDs, evdefs); err != nil {
+		diags.Error(diag.Error, err)
+		return
+	}
+	if markers != nil {
+		output.Marker = markers.Is
+		output.Analyzers = append(output.Analyzers, markers)
+	}
+
+	if levelStats {
+		output.Analyzers = append(output.Analyzers, levels.New())
+	}
+
+	if unknownEvents {
+		u := unknown.New()
+		u.Known = func(id uint16) bool { return output.Ignore[id] || output.Decoders.Find(id) != nil }
+		output.Analyzers = append(output.Analyzers, u)
+	}
+
+	if len(*heatmapFile) != 0 {
+		var h *heatmap.Heatmap
+		if h, err = heatmap.New(*heatmapFile, *heatmapBuckets); err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+		switch *heatmapRows {
+		case "event":
+		case "component":
+			h.PerComponent = true
+		default:
+			diags.Errorf(diag.Error, "unknown heatmap rows: %s", *heatmapRows)
+			return
+		}
+		output.Analyzers = append(output.Analyzers, h)
+	}
+
+	if len(*chartEvents) != 0 && len(*chartStyle) == 0 {
+		diags.Errorf(diag.Error, "--chart-events requires --chart")
+		return
+	}
+	if len(*chartStyle) != 0 {
+		var c *chart.Chart
+		if c, err = chart.New(*chartStyle, *chartWidth); err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+		if len(*chartEvents) != 0 {
+			for _, ids := range strings.Split(*chartEvents, ",") {
+				first, last, err := plugin.ParseRange(ids)
+				if err != nil {
+					diags.Error(diag.Error, err)
+					return
+				}
+				c.Select(first, last)
+			}
+		}
+		output.Analyzers = append(output.Analyzers, c)
+	}
+
+	if len(*mqttURL) != 0 {
+		var p *mqtt.Publisher
+		if p, err = mqtt.New(*mqttURL, output.Where); err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+		output.Analyzers = append(output.Analyzers, p)
+	}
+
+	if len(*sinkURL) != 0 {
+		var k *sink.Sink
+		if k, err = sink.New(*sinkURL, output.Where); err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+		output.Analyzers = append(output.Analyzers, k)
+	}
+
+	if len(*syslogFormat) != 0 && len(*syslogTarget) == 0 {
+		diags.Errorf(diag.Error, "--syslog-format requires --syslog")
+		return
+	}
+	if len(*syslogTarget) != 0 {
+		var f *syslog.Forwarder
+		if f, err = syslog.New(*syslogTarget, *syslogFormat, output.Where); err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+		if deterministic {
+			f.Name = reportName(f.Name)
+		}
+		output.Analyzers = append(output.Analyzers, f)
+	}
+
+	if len(*influxTarget) != 0 {
+		var w *influx.Writer
+		if w, err = influx.New(*influxTarget); err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+		if deterministic {
+			w.Name = reportName(w.Name)
+		}
+		output.Analyzers = append(output.Analyzers, w)
+	}
+
+	if len(*otlpTarget) != 0 {
+		var e *otel.Exporter
+		if e, err = otel.New(*otlpTarget); err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+		output.Analyzers = append(output.Analyzers, e)
+	}
+
+	if len(*liveSource) != 0 {
+		var m *metrics.Metrics
+		if len(*httpAddr) != 0 {
+			status := live.NewStatus()
+			m = metrics.New()
+			status.Metrics = m
+			if err = status.Serve(*httpAddr); err != nil {
+				diags.Error(diag.Error, err)
+				return
+			}
+			output.Analyzers = append(output.Analyzers, status, m)
+		}
+		if len(*captureFile) != 0 {
+			var c *capture.Writer
+			if c, err = capture.New(*captureFile, output.Where); err != nil {
+				diags.Error(diag.Error, err)
+				return
+			}
+			if deterministic {
+				c.Name = reportName(c.Name)
+			}
+			output.Analyzers = append(output.Analyzers, c)
+		}
+		opts := live.Options{Ack: ack, Framing: *framing, Resync: resync}
+		if addr, _, ok := elf.Symbols.GetAddrSize(probe.Symbol); ok {
+			opts.Info = uint32(addr)
+		}
+		in, err := live.Open(*liveSource, opts)
+		if err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+		defer in.Close()
+		if flt, ok := in.(live.Filterer); ok {
+			for _, f := range filters {
+				if err = flt.SetFilter(f); err != nil {
+					diags.Error(diag.Error, err)
+					return
+				}
+			}
+			go filterCommands(os.Stdin, flt)
+		} else if len(filters) != 0 {
+			diags.Errorf(diag.Error, "--recorder-filter requires a probe source")
+			return
+		}
+		if resetTarget {
+			r, ok := in.(live.Resetter)
+			if !ok {
+				diags.Errorf(diag.Error, "--reset requires a probe source")
+				return
+			}
+			if err = r.Reset(); err != nil {
+				diags.Error(diag.Error, err)
+				return
+			}
+		}
+		if *queueSize != 0 {
+			policy := *queuePolicy
+			if len(policy) == 0 {
+				policy = live.Block
+			}
+			var q *live.Queue
+			if q, err = live.NewQueue(in, *queueSize, policy); err != nil {
+				diags.Error(diag.Error, err)
+				return
+			}
+			in = q
+			output.Analyzers = append(output.Analyzers, q)
+		}
+		if m != nil {
+			m.SetLost(func() int64 { return live.Lost(in) })
+		}
+		if *statsInterval > 0 {
+			s := live.NewSnapshot(os.Stderr, *statsInterval)
+			s.Lost = func() int64 { return live.Lost(in) }
+			output.Analyzers = append(output.Analyzers, s)
+		}
+		output.Level = *level
+		if err = output.Live(outputFile, in, evdefs, typedefs, memberTypes); err != nil {
+			diags.Error(diag.Decode, err)
+		} else if output.Interrupted {
+			diags.Warning(diag.OK, "interrupted, live session ended")
+		}
+		return
+	}
+
+	if len(*goldenFile) != 0 {
+		if len(*outputFile) != 0 {
+			diags.Errorf(diag.Error, "--golden writes no output file, -o not allowed")
+			return
+		}
+		tmp, err := os.CreateTemp("", "eventlist-*.out")
+		if err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+		_ = tmp.Close()
+		defer os.Remove(tmp.Name())
+		*outputFile = tmp.Name()
+	} else if updateGolden {
+		diags.Errorf(diag.Error, "--update-golden requires --golden")
+		return
+	}
+
+	if err := output.Print(outputFile, formatType, level, &eventFile[0], evdefs, typedefs, memberTypes, statBegin, showStatistic); err != nil {
+		kind := diag.Decode
+		if errors.Is(err, output.ErrNoEvents) {
+			kind = diag.Error
+		}
+		diags.Error(kind, err)
+	} else if output.Interrupted {
+		diags.Warning(diag.Decode, "interrupted, the output covers the events read before")
+	} else if len(*goldenFile) != 0 {
+		checkGolden(*goldenFile, *outputFile, updateGolden)
+	} else if assertions != nil && !assertions.Passed() {
+		diags.Errorf(diag.Assert, "%d of %d assertions failed", assertions.Failed(), len(assertions.Rules))
+	} else if !showStatistic && (output.Where != nil || output.Level != "") && output.Shown == 0 {
+		diags.Warning(diag.NoMatch, "no event matched the filter")
 	}
 }
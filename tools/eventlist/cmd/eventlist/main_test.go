@@ -19,11 +19,18 @@
 package main
 
 import (
+	"eventlist/pkg/fetch"
+	"eventlist/pkg/probe"
 	"flag"
 	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"reflect"
 	"regexp"
+	"strings"
 	"testing"
 )
 
@@ -145,6 +152,23 @@ func Test_main(t *testing.T) { //nolint:golint,paralleltest
 			"Event count      total       min         max         average     first       last\\n" +
 			"----- -----      -----       ---         ---         -------     -----       ----\\n"
 
+	linesLive :=
+		"Index Time \\(s\\)   Component Event Property Value\\n" +
+			"----- --------   --------- -------------- -----\\n" +
+			"    0 [0-9.]+ 0xFF      0xFF03         val1=0x00000004, val2=0x00000002\\n" +
+			"    1 [0-9.]+ 0xFE      0xFE00         \"hello wo\"\\n"
+
+	linesLiveWhere :=
+		"Index Time \\(s\\)   Component Event Property Value\\n" +
+			"----- --------   --------- -------------- -----\\n" +
+			"    0 [0-9.]+ 0xFF      0xFF03         val1=0x00000004, val2=0x00000002\\n" +
+			"\\n" +
+			"   Start/Stop event statistic\\n(.*\\n)*" +
+			"   Capture\\n" +
+			"   -------\\n" +
+			"\\n" +
+			"out.out: 1 records written, 1 records filtered out\\n"
+
 	lines2 :=
 		"   Start/Stop event statistic\\n" +
 			"   --------------------------\\n" +
@@ -154,6 +178,12 @@ func Test_main(t *testing.T) { //nolint:golint,paralleltest
 
 	help :=
 		"Usage: [^ ]+ \\[-I <scvdFile>\\]\\.\\.\\. \\[-o <outputFile>\\] \\[-a <elf/axfFile>\\] \\[-b\\] <logFile>\\n" +
+			"       [^ ]+ <command> \\[options\\] \\[args\\]\\n" +
+			"Commands:\\n" +
+			"\\tdecode\\tdecode a capture, the same as without command\\n" +
+			"(\\t[a-z-]+\\t.*\\n)*" +
+			"\\tserve\\tserve workspaces of decoded captures as JSON API\\n" +
+			"Options:\\n" +
 			"\\t-a <fileName> \\telf/axf file name\\n" +
 			"\\t-b --begin\\tshow statistic at beginning\\n" +
 			"\\t-h --help\\tshow short help\\n" +
@@ -162,6 +192,31 @@ func Test_main(t *testing.T) { //nolint:golint,paralleltest
 			"\\t-s --statistic\\tshow statistic only\\n" +
 			"\\t-V --version\\tshow version info\\n"
 
+	helpStats :=
+		"^Usage: [^ ]+ stats \\[options\\] <logFile>\\n" +
+			"\\t-a <fileName> \\telf/axf file name\\n" +
+			"\\t-h --help\\tshow short help\\n" +
+			"\\t-I <fileName> \\tinclude SCVD file name\\n" +
+			"\\t-o <fileName> \\toutput file name\\n" +
+			"\\t-f --format <formatType> \\tformat type: txt, json, xml, html, jsonl, stats\\n"
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	defer l.Close()
+	go func() {
+		data, _ := os.ReadFile("../../testdata/test10.binary")
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = c.Write(data)
+			c.Close()
+		}
+	}()
+
 	versionInfo = "1.2.3 (C) 2022 Arm Ltd. and Contributors"
 	tests := []struct {
 		name       string
@@ -175,6 +230,8 @@ func Test_main(t *testing.T) { //nolint:golint,paralleltest
 		{"-statistic", []string{"-statistic", "-o", outFile, "../../testdata/test10.binary"}, "", outFile},
 		{"-help", []string{"-help"}, help, ""},
 		{"stdout", []string{"../../testdata/test10.binary"}, lines1, ""},
+		{"-q", []string{"-q", "-o", outFile, "../../testdata/test10.binary"}, "", outFile},
+		{"--no-pager", []string{"--no-pager", "../../testdata/test10.binary"}, lines1, ""},
 		{"-o -begin", []string{"-begin", "-o", outFile, "../../testdata/test10.binary"}, "", outFile},
 		{"-o -b", []string{"-b", "-o", outFile, "../../testdata/test10.binary"}, "", outFile},
 		{"-o", []string{"-o", outFile, "../../testdata/test10.binary"}, "", outFile},
@@ -183,10 +240,158 @@ func Test_main(t *testing.T) { //nolint:golint,paralleltest
 		{"-version", []string{"-version"}, ".* [0-9]+\\.[0-9]+\\.[0-9]+ \\(C\\) [0-9]+ Arm Ltd. and Contributors\\n", ""},
 		{"err", []string{"xxx", "yyy"}, ".*: only one binary input file allowed\n", ""},
 		{"missing", nil, ".*: missing input file\n", ""},
+		{"--float", []string{"--float", "int", "xxx"}, ".*: expression.SetFloatType: parsing \"int\": invalid value type\n", ""},
+		{"--fixed", []string{"--fixed", "P15", "xxx"}, ".*: expression.SetFixedType: parsing \"P15\": invalid value type\n", ""},
+		{"--float --fixed", []string{"--float", "half", "--fixed", "Q15", "xxx"}, ".*: only one of --float and --fixed allowed\n", ""},
+		{"--dashboard", []string{"--dashboard", "../../testdata/dashboard_err.yaml", "xxx"}, ".*: invalid dashboard panel: Pie: type pie\n", ""},
+		{"--columns", []string{"--columns", "index,core", "xxx"}, ".*: unknown column: core, the records have no core ID\n", ""},
+		{"--error-context", []string{"--error-context", "-1", "xxx"}, ".*: error context size must be positive: -1\n", ""},
+		{"--error-context-dir", []string{"--error-context-dir", "ctx", "xxx"}, ".*: --error-context-dir requires --error-context\n", ""},
+		{"--sort", []string{"--sort", "level", "xxx"}, ".*: unknown sort key: level\n", ""},
+		{"--live --reverse", []string{"--live", "tcp://" + l.Addr().String(), "--reverse"}, ".*: --sort and --reverse not allowed with --live\n", ""},
+		{"--severity", []string{"--severity", "../../testdata/severity_err.yaml", "xxx"}, ".*: invalid level: 0x1000: Fatal\n", ""},
+		{"--head --limit", []string{"--head", "2", "--limit", "3", "xxx"}, ".*: only one of --head and --limit allowed\n", ""},
+		{"--tail --skip", []string{"--tail", "2", "--skip", "3", "xxx"}, ".*: --tail cannot be combined with --head, --skip or --limit\n", ""},
+		{"--skip", []string{"--skip", "-1", "xxx"}, ".*: negative number of events\n", ""},
+		{"--live --tail", []string{"--live", "tcp://" + l.Addr().String(), "--tail", "2"}, ".*: --head, --tail, --skip and --limit not allowed with --live\n", ""},
+		{"--checklist", []string{"--checklist", "../../testdata/checklist_err.yaml", "xxx"}, ".*: invalid checklist item: Connected: within requires after\n", ""},
+		{"--color", []string{"--color", "red", "xxx"}, ".*: unknown color mode: red\n", ""},
+		{"--hyperlinks", []string{"--hyperlinks", "sometimes", "xxx"}, ".*: unknown hyperlink mode: sometimes\n", ""},
+		{"view", []string{"view"}, ".*: view requires one input file\n", ""},
+		{"view -x", []string{"view", "-x", "xxx"}, ".*: flag provided but not defined: -x\n", ""},
+		{"view -a", []string{"view", "-a", "../../testdata/nix", "xxx"}, ".*: open ../../testdata/nix: (no such file or directory|The system cannot find the file specified.)\n", ""},
+		{"view -I", []string{"view", "-I", "../../testdata/nix.xml", "xxx"}, ".*: open ../../testdata/nix.xml: (no such file or directory|The system cannot find the file specified.)\n", ""},
+		{"view --markers", []string{"view", "--markers", "0xA000-0x9000", "xxx"}, ".*: invalid ID range, want <first>-<last>: 0xA000-0x9000\n", ""},
+		{"view nix", []string{"view", "../../testdata/nix"}, ".*: open ../../testdata/nix: (no such file or directory|The system cannot find the file specified.)\n", ""},
+		{"--profile", []string{"--profile", "cm99", "xxx"}, ".*: unknown profile: cm99\n", ""},
+		{"--profile list", []string{"--profile", "list"}, "cm0plus-le +Cortex-M0\\+, little endian, SysTick or timer timestamps at 48 MHz\n", ""},
+		{"--profiles", []string{"--profiles", "../../testdata/profiles_err.yaml", "xxx"}, ".*: invalid profile: cm3-pdp: endian middle\n", ""},
+		{"--trace-self", []string{"--trace-self", "../../testdata/nix/trace.json", "-o", outFile, "../../testdata/test10.binary"}, ".*: open ../../testdata/nix/trace.json: (no such file or directory|The system cannot find the path specified.)\n", outFile},
+		{"serve -x", []string{"serve", "-x"}, ".*: flag provided but not defined: -x\n", ""},
+		{"serve file", []string{"serve", "xxx"}, ".*: serve takes no input file\n", ""},
+		{"serve --stdio --ui", []string{"serve", "--stdio", "--ui"}, ".*: --stdio excludes --ui, --grpc and --live\n", ""},
+		{"serve addr", []string{"serve", "--addr", "localhost:-1"}, ".*: serving workspaces at http://localhost:-1/api/workspaces\n.*: listen tcp: .*\n", ""},
+		{"--heatmap", []string{"--heatmap", "heat.txt", "xxx"}, ".*: heatmap file must be .csv, .json or .png: heat.txt\n", ""},
+		{"--heatmap-rows", []string{"--heatmap", "heat.json", "--heatmap-rows", "thread", "xxx"}, ".*: unknown heatmap rows: thread\n", ""},
+		{"--heatmap json", []string{"--heatmap", "heat.json", "--heatmap-rows", "component", "--heatmap-buckets", "2", "../../testdata/test.binary"}, "   Detailed event list\\n", "heat.json"},
+		{"--chart", []string{"--chart", "ascii", "--chart-width", "8", "--chart-events", "0xFE00-0xFEFF", "../../testdata/test.binary"}, "   Timeline chart\\n   -+\\n\\nTime: .*\\n\\n0xFE/0xFE00 \\|@      @\\| 2\\n", ""},
+		{"--chart style", []string{"--chart", "braille", "xxx"}, ".*: chart style must be unicode or ascii: braille\n", ""},
+		{"--chart-events", []string{"--chart-events", "0x0A01", "xxx"}, ".*: --chart-events requires --chart\n", ""},
+		{"--live", []string{"--live", "tcp://" + l.Addr().String()}, linesLive, ""},
+		{"--live --capture", []string{"--live", "tcp://" + l.Addr().String(), "--capture", outFile, "--where", "component=0xFF"}, linesLiveWhere, outFile},
+		{"--live nix", []string{"--live", "../../testdata/nix"}, ".*: open ../../testdata/nix: (no such file or directory|The system cannot find the file specified.)\n", ""},
+		{"--live file", []string{"--live", "tcp://" + l.Addr().String(), "xxx"}, ".*: no input file allowed with --live\n", ""},
+		{"--http", []string{"--http", "localhost:0", "xxx"}, ".*: --http requires --live\n", ""},
+		{"--ack", []string{"--ack", "xxx"}, ".*: --ack requires --live\n", ""},
+		{"--resync", []string{"--resync", "xxx"}, ".*: --resync requires --live\n", ""},
+		{"--watch --live", []string{"--live", "../../testdata/test10.binary", "--watch"}, ".*: --watch not allowed with --live\n", ""},
+		{"--stats-interval", []string{"--stats-interval", "5s", "xxx"}, ".*: --stats-interval requires --live\n", ""},
+		{"--queue", []string{"--queue", "10", "xxx"}, ".*: --queue requires --live\n", ""},
+		{"--queue-policy", []string{"--live", "../../testdata/test10.binary", "--queue-policy", "drop-oldest"}, ".*: --queue-policy requires --queue\n", ""},
+		{"--queue policy", []string{"--live", "../../testdata/test10.binary", "--queue", "10", "--queue-policy", "drop"}, ".*: invalid queue policy: drop\n", ""},
+		{"--live --ack --resync", []string{"--live", "tcp://localhost:1", "--ack", "--resync"}, ".*: resynchronization cannot be combined with the acknowledgment protocol\n", ""},
+		{"--live --ack", []string{"--live", "../../testdata/test10.binary", "--ack"}, ".*: acknowledgment protocol requires a tcp or serial source\n", ""},
+		{"--capture", []string{"--capture", outFile, "xxx"}, ".*: --capture requires --live\n", ""},
+		{"--where", []string{"--where", "thread=main", "xxx"}, ".*: invalid --where condition: thread=main: unknown key thread\n", ""},
+		{"--framing", []string{"--framing", "cobs", "xxx"}, ".*: --framing requires --live\n", ""},
+		{"--live --framing", []string{"--live", "../../testdata/test10.binary", "--framing", "hdlc"}, ".*: invalid framing: hdlc\n", ""},
+		{"--config", []string{"--config", "../../testdata/config.yaml", "../../testdata/test10.binary"}, "\\{\"events\":\\[.*\"component\":\"STDIO\",\"eventProperty\":\"stdout\".*\\]", ""},
+		{"--config nix", []string{"--config", "../../testdata/nix.yaml", "xxx"}, ".*: open ../../testdata/nix.yaml: (no such file or directory|The system cannot find the file specified.)\n", ""},
+		{"--no-config", []string{"--no-config", "../../testdata/test10.binary"}, lines1, ""},
+		{"--health", []string{"--health", "../../testdata/test10.binary"}, "^   Capture health\\n   -+\\n\\nScore: [0-9]+/100 .*\\n(.*\\n)*\\n   Detailed event list\\n", ""},
+		{"--health-json", []string{"--health-json", outFile, "../../testdata/test10.binary"}, "^   Detailed event list\\n", outFile},
+		{"--annotations", []string{"--annotations", "../../testdata/annotations.yaml", "../../testdata/test.binary"}, "      # firmware v2 flashed here\\n    0 0.00001224 .*\\n    1 .*\\n      # reset button pressed\\n    2 ", ""},
+		{"--annotations error", []string{"--annotations", "../../testdata/annotations_err.yaml", "../../testdata/test.binary"}, ".*: annotation requires text and either index or time: ../../testdata/annotations_err.yaml: entry 1\n", ""},
+		{"--markers", []string{"--markers", "0xFE00", "../../testdata/test.binary"}, "   Markers\\n   -+\\n\\nIndex Time \\(s\\)   Component Event\\n    2 0.00005640 0xFE      0xFE00 hello wo\\n    3 0.00005820 0xFE      0xFE00 rld\\\\n\\n", ""},
+		{"--levels", []string{"--levels", "../../testdata/test.binary"}, "   Levels\\n   -+\\n\\nLevel +Events +% +First \\(s\\) +Last \\(s\\) +Top component\\nError +0 +- +- +-\\n(.*\\n){3}\\(none\\) +4 100.0% +0.00001224 +0.00005820 +0xFE \\(2\\)\\nTotal +4\\n", ""},
+		{"stats --levels", []string{"stats", "--levels", "../../testdata/test.binary"}, "Op +0 +- +- +-\\n", ""},
+		{"--markers invalid", []string{"--markers", "0xFE00-x", "../../testdata/test.binary"}, ".*: invalid ID range, want <first>-<last>: 0xFE00-x\n", ""},
+		{"--gaps", []string{"--gaps", "2", "--gap-time", "0.000001", "../../testdata/test.binary"}, "   Timeline gaps\\n   -+\\n\\nSilent time: 0.00000180 s in 1 gaps longer than 1e-06 s, 3.2% of 0.00005696 s\\n", ""},
+		{"--gap-time negative", []string{"--gap-time", "-1", "../../testdata/test.binary"}, "--gap-time and --gaps must be positive", ""},
+		{"decode", []string{"decode", "../../testdata/test10.binary"}, lines1, ""},
+		{"decode -x", []string{"decode", "-x", "xxx"}, ".*: flag provided but not defined: -x\n", ""},
+		{"stats", []string{"stats", "../../testdata/test10.binary"}, "^" + lines2, ""},
+		{"stats -h", []string{"stats", "-h"}, helpStats, ""},
+		{"stats --sort", []string{"stats", "--sort", "time", "xxx"}, ".*: option not allowed with stats: --sort\n", ""},
+		{"stats -b", []string{"stats", "-b", "xxx"}, ".*: option not allowed with stats: -b\n", ""},
+		{"validate", []string{"validate", "../../testdata/test10.binary"}, "^   Capture health\n(.*\n)*\n   Start/Stop event statistic\n", ""},
+		{"export", []string{"export", "-o", outFile, "../../testdata/test10.binary"}, ".*: export requires -f json, xml or html and -o <outputFile>\n", ""},
+		{"export -f", []string{"export", "-f", "xml", "../../testdata/test10.binary"}, ".*: export requires -f json, xml or html and -o <outputFile>\n", ""},
+		{"export -f json", []string{"export", "-f", "json", "-o", outFile, "../../testdata/test10.binary"}, "^$", outFile},
+		{"assert", []string{"assert", "../../testdata/assert.yaml", "../../testdata/test10.binary"}, "^   Start/Stop event statistic\n(.*\n)*   Assertions: Polling\n(.*\n)*\n1 of 4 assertions passed: FAILED\n.*: 3 of 4 assertions failed\n", ""},
+		{"assert one", []string{"assert", "../../testdata/test10.binary"}, ".*: assert requires a rules file and a capture\n", ""},
+		{"compare", []string{"compare", "../../testdata/test10.binary", "../../testdata/test10.binary"}, "^   Comparison\n   -+\n\nEvent sequence: identical, 2 events\n\n0 changed, 2 unchanged, 0 regressions: PASSED\n$", ""},
+		{"compare -o", []string{"compare", "-o", outFile, "../../testdata/test.binary", "../../testdata/test10.binary"}, ".*: 4 regressions beyond the thresholds\n$", outFile},
+		{"compare one", []string{"compare", "../../testdata/test10.binary"}, ".*: compare requires a baseline and a capture file\n", ""},
+		{"compare nix", []string{"compare", "../../testdata/test10.binary", "../../testdata/nix"}, ".*: open ../../testdata/nix: .*\n", ""},
+		{"diff", []string{"diff", "../../testdata/test.binary", "../../testdata/test10.binary"}, "^--- ../../testdata/test.binary\n\\+\\+\\+ ../../testdata/test10.binary\n@@ -1,4 \\+1,2 @@\n(.*\n)*1 added, 3 removed, 0 changed events\n.*: warning: the events differ\n$", ""},
+		{"diff same", []string{"diff", "--timestamps", "-o", outFile, "../../testdata/test10.binary", "../../testdata/test10.binary"}, "^$", outFile},
+		{"diff one", []string{"diff", "../../testdata/test10.binary"}, ".*: diff requires a baseline and a capture file\n", ""},
+		{"diff --context", []string{"diff", "--context", "-1", "xxx", "yyy"}, ".*: invalid --context: -1\n", ""},
+		{"cut", []string{"cut", "-o", outFile, "--from", "0.00005", "../../testdata/test.binary"}, "^$", outFile},
+		{"cut -o", []string{"cut", "../../testdata/test.binary"}, ".*: cut requires -o <outputFile>\n", ""},
+		{"cut one", []string{"cut", "-o", outFile, "xxx", "yyy"}, ".*: cut requires one input file\n", ""},
+		{"cut --where", []string{"cut", "-o", outFile, "--where", "nix=1", "xxx"}, ".*: invalid --where condition: nix=1: unknown key nix\n", ""},
+		{"cut --session", []string{"cut", "-o", outFile, "--session", "E(0)", "../../testdata/test.binary"}, ".*: invalid session: E\\(0\\)\n", outFile},
+		{"cut --to", []string{"cut", "-o", outFile, "--from", "2", "--to", "1", "../../testdata/test.binary"}, ".*: invalid time range 2 to 1\n", outFile},
+		{"redact", []string{"redact", "-o", outFile, "--mode", "placeholder", "--addresses", "0x30000000-0x3FFFFFFF", "--field", "id=0xF000:val2", "../../testdata/test.binary"}, "^$", outFile},
+		{"redact -o", []string{"redact", "../../testdata/test.binary"}, ".*: redact requires -o <outputFile>\n", ""},
+		{"redact one", []string{"redact", "-o", outFile, "xxx", "yyy"}, ".*: redact requires one input file\n", ""},
+		{"redact --addresses", []string{"redact", "-o", outFile, "--addresses", "2-1", "xxx"}, ".*: invalid address range: 2-1\n", ""},
+		{"redact --field", []string{"redact", "-o", outFile, "--field", "id=1", "xxx"}, ".*: invalid redact field: id=1\n", ""},
+		{"redact --mode", []string{"redact", "-o", outFile, "--mode", "xxx", "../../testdata/test.binary"}, ".*: unknown redact mode: xxx\n", outFile},
+		{"generate", []string{"generate", "-o", outFile, "../../testdata/scenario.yaml"}, "^$", outFile},
+		{"generate -o", []string{"generate", "../../testdata/scenario.yaml"}, ".*: generate requires -o <outputFile>\n", ""},
+		{"generate one", []string{"generate", "-o", outFile}, ".*: generate requires one scenario file\n", ""},
+		{"generate nix", []string{"generate", "-o", outFile, "../../testdata/nix.yaml"}, ".*: open ../../testdata/nix.yaml: .*\n", ""},
+		{"selftest", []string{"selftest", "../../testdata/test.golden", "../../testdata/test.binary"}, "^$", ""},
+		{"selftest differs", []string{"selftest", "../../testdata/test.golden", "../../testdata/test10.binary"}, "^--- ../../testdata/test.golden\n\\+\\+\\+ output\n@@ -3,10 \\+3,8 @@\n(.*\n)*.*: the output differs from ../../testdata/test.golden\n$", ""},
+		{"scvd-gen", []string{"scvd-gen", "--name", "Network", "../../testdata/events.h"}, "(?s)^<\\?xml .*<component name=\"Network\" version=\"1.0.0\"/>.*<event id=\"0xA102\" level=\"Error\" property=\"EvtNetFail\" .* info=\"send failed\"/>\n  </events>\n\n</component_viewer>\n$", ""},
+		{"scvd-gen -o", []string{"scvd-gen", "-o", outFile, "../../testdata/events.h"}, "^$", outFile},
+		{"scvd-gen none", []string{"scvd-gen"}, ".*: scvd-gen requires one header file\n", ""},
+		{"scvd-gen empty", []string{"scvd-gen", "../../testdata/test.xml"}, ".*: ../../testdata/test.xml: no EventID definition found\n", ""},
+		{"scvd-gen nix", []string{"scvd-gen", "../../testdata/nix.h"}, ".*: open ../../testdata/nix.h: .*\n", ""},
+		{"header-gen", []string{"header-gen", "../../testdata/rtx.xml"}, "(?s)^/\\* Event IDs of RTX5 thread events, generated from rtx.xml, do not edit \\*/\n.*\n#define EvtRTXThread_No +0xF2U  ///< Thread\n.*#endif /\\* RTX5THREADEVENTS_EVENTS_H \\*/\n$", ""},
+		{"header-gen -o", []string{"header-gen", "-o", outFile, "../../testdata/rtx.xml"}, "^$", outFile},
+		{"header-gen none", []string{"header-gen"}, ".*: header-gen requires one SCVD file\n", ""},
+		{"header-gen nix", []string{"header-gen", "../../testdata/nix.xml"}, ".*: open ../../testdata/nix.xml: .*\n", ""},
+		{"header-gen err", []string{"header-gen", "../../testdata/test_err1.xml"}, ".*: component Start/Stop Statistics: invalid EventID argument: nix\n", ""},
+		{"selftest update", []string{"selftest", "--update-golden", outFile, "../../testdata/test.binary"}, "^$", outFile},
+		{"selftest one", []string{"selftest", "../../testdata/test.golden"}, ".*: selftest requires a golden file and a capture\n", ""},
+		{"selftest nix", []string{"selftest", "../../testdata/nix.golden", "../../testdata/test.binary"}, ".*: open ../../testdata/nix.golden: .*\n", ""},
+		{"--golden -o", []string{"--golden", "../../testdata/test.golden", "-o", outFile, "xxx"}, ".*: --golden writes no output file, -o not allowed\n", ""},
+		{"--update-golden", []string{"--update-golden", "xxx"}, ".*: --update-golden requires --golden\n", ""},
+		{"capture", []string{"capture", "xxx"}, ".*: capture requires a live source and a capture file\n", ""},
+		{"capture --pyocd", []string{"capture", "--pyocd", "auto", "xxx", outFile}, ".*: capture requires a live source and a capture file\n", ""},
+		{"capture --pyocd -a", []string{"capture", "--pyocd", "auto", outFile}, ".*: probe source requires the address of EventRecorderInfo, e.g. with -a\n", outFile},
+		{"--pyocd --live", []string{"--pyocd", "auto", "--live", "tcp://localhost:1"}, ".*: only one of --live and --pyocd allowed\n", ""},
+		{"--recorder-filter", []string{"--recorder-filter", "Op:1", "xxx"}, ".*: --recorder-filter requires --live\n", ""},
+		{"--recorder-filter spec", []string{"--recorder-filter", "Op", "--live", "tcp://localhost:1"}, ".*: invalid filter: Op: missing components\n", ""},
+		{"--recorder-filter source", []string{"--recorder-filter", "Op:1", "--live", "tcp://" + l.Addr().String()}, ".*: --recorder-filter requires a probe source\n", ""},
+		{"--reset", []string{"--reset", "xxx"}, ".*: --reset requires --live\n", ""},
+		{"--strict", []string{"--strict", "../../testdata/test.binary"}, "(?s).*: --strict: 1 unknown components, first: event 0, id 0xF000\n$", ""},
+		{"--unknown-events", []string{"--unknown-events", "../../testdata/test.binary"}, "(?s).*   Unknown events\n.*\n0xF000        1        0 val1=0x300066a8, val2=0x00005dc0, val3=0x00000001, val4=0x00000000\n$", ""},
+		{"--override", []string{"--override", "../../testdata/override_err.yaml", "xxx"}, ".*: invalid level: 0x1000: Fatal\n", ""},
+		{"--override ignore", []string{"--override", "../../testdata/override.yaml", "../../testdata/test.binary"}, "(?s)^[^\n]*\n[^\n]*\n\n[^\n]*\n[^\n]*\n    1 0.00000124 0xFF      0xFF00 ", ""},
+		{"--svd", []string{"--svd", "../../testdata/device.svd", "--svd-events", "0xF000", "../../testdata/test.binary"}, "(?s).*\n    0 0.00001224 TIM0      CTRL,STATUS,DATA TIM0.CTRL.EN = 0, TIM0.CTRL.MODE = 0 \\(Off\\), TIM0.CTRL.PRESC = 0x5DC; TIM0.STATUS.READY = 1 \\(Ready\\); TIM0.DATA = 0x0000\n", ""},
+		{"--svd-events", []string{"--svd-events", "0xF000", "xxx"}, ".*: --svd-events requires --svd\n", ""},
+		{"--svd range", []string{"--svd", "../../testdata/device.svd", "--svd-events", "0xF0FF-0xF000", "xxx"}, ".*: invalid ID range, want <first>-<last>: 0xF0FF-0xF000\n", ""},
+		{"--rtos", []string{"--rtos", "freertos", "../../testdata/test.binary"}, "(?s).*\n    0 0.00001224 FreeRTOS Task TaskCreated    task=0x300066a8\n", ""},
+		{"--rtos unknown", []string{"--rtos", "rtx4", "../../testdata/test.binary"}, ".*: unknown RTOS: rtx4\n", ""},
+		{"--profile zephyr", []string{"--profile", "zephyr", "../../testdata/test.binary"}, "(?s).*\n    0 0.00001224 Zephyr Thread ThreadCreate   thread=0x300066a8\n", ""},
+		{"--reset source", []string{"--reset", "--live", "tcp://" + l.Addr().String()}, ".*: --reset requires a probe source\n", ""},
+		{"capture live", []string{"capture", "tcp://" + l.Addr().String(), outFile}, linesLive, outFile},
+		{"merge", []string{"merge", "xxx", "yyy"}, ".*: merge requires -o <outputFile>\n", ""},
+		{"merge -x", []string{"merge", "-x"}, ".*: flag provided but not defined: -x\n", ""},
+		{"merge one", []string{"merge", "-o", outFile, "xxx"}, ".*: merge requires at least two input files\n", ""},
+		{"merge nix", []string{"merge", "-o", outFile, "../../testdata/test10.binary", "../../testdata/nix"}, ".*: open ../../testdata/nix: (no such file or directory|The system cannot find the file specified.)\n", ""},
+		{"merge out", []string{"merge", "-o", "../../testdata/nix/out.bin", "../../testdata/test10.binary", "../../testdata/test10.binary"}, ".*: open ../../testdata/nix/out.bin: (no such file or directory|The system cannot find the path specified.)\n", ""},
+		{"merge files", []string{"merge", "-o", outFile, "../../testdata/test10.binary", "../../testdata/test10.binary"}, "^$", outFile},
 		// -I must be the last test
 		{"-I", []string{"-I", "../../testdata/nix", "xxx"}, ".*: open ../../testdata/nix: (no such file or directory|The system cannot find the file specified.)\\n", ""},
 	}
 	savedArgs := os.Args
+	defer func() { os.Args = savedArgs }()
 	for _, tt := range tests { //nolint:golint,paralleltest
 		t.Run(tt.name, func(t *testing.T) {
 			oldOut := os.Stdout
@@ -211,3 +416,197 @@ func Test_main(t *testing.T) { //nolint:golint,paralleltest
 		})
 	}
 }
+
+func Test_mainEnv(t *testing.T) { //nolint:golint,paralleltest
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		want string
+	}{
+		{"format", map[string]string{"EVENTLIST_FORMAT": "json", "EVENTLIST_SCVD_PATH": "../../testdata/test.xml"},
+			[]string{"--no-config", "../../testdata/test10.binary"}, "\\{\"events\":\\[.*\"component\":\"STDIO\".*\\]"},
+		{"component", map[string]string{"EVENTLIST_SCVD_PATH": "../../testdata/test.xml"},
+			[]string{"--no-config", "../../testdata/test7.binary"}, "\\n    0 0\\.00000124 EvStat    StartA\\(0\\)      File=fff\\n"},
+		{"flag", map[string]string{"EVENTLIST_FORMAT": "json"},
+			[]string{"--no-config", "-f", "txt", "../../testdata/test10.binary"}, "   Detailed event list\\n"},
+		{"config", map[string]string{"EVENTLIST_CONFIG": "../../testdata/config.yaml", "EVENTLIST_LEVEL": "Error"},
+			[]string{"../../testdata/test10.binary"}, "\"index\":1,\"time\":7.75,\"component\":\"\",\"eventProperty\":\"\""},
+		{"clock", map[string]string{"EVENTLIST_CLOCK": "x"},
+			[]string{"--no-config", "xxx"}, ".*: invalid clock in config file: EVENTLIST_CLOCK: x\\n"},
+		{"where", map[string]string{"EVENTLIST_WHERE": "x"},
+			[]string{"--no-config", "xxx"}, ".*: invalid --where condition: x\\n"},
+	}
+	savedArgs := os.Args
+	defer func() { os.Args = savedArgs }()
+	// -I of the previous tests
+	paths = nil
+	for _, tt := range tests { //nolint:golint,paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			oldOut := os.Stdout
+			defer func() { os.Stdout = oldOut }()
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+			os.Args = append(savedArgs, tt.args...)
+			main()
+			w.Close()
+			buf, _ := io.ReadAll(r)
+			if match, _ := regexp.Match(tt.want, buf); !match {
+				t.Errorf("main() %s = %v, want %v", tt.name, string(buf), tt.want)
+			}
+		})
+	}
+}
+
+type testFilterer []probe.Filter
+
+func (f *testFilterer) SetFilter(flt probe.Filter) error {
+	*f = append(*f, flt)
+	return nil
+}
+
+func Test_filterCommands(t *testing.T) { //nolint:golint,paralleltest
+	var got testFilterer
+	filterCommands(strings.NewReader("Op:1\n\n bad \n-all:all\n"), &got)
+	want := testFilterer{{Enable: true, Levels: 4, First: 1, Last: 1}, {Levels: 15, Last: 0xFE}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("filterCommands() = %v, want %v", got, want)
+	}
+}
+
+func Test_mainExit(t *testing.T) { //nolint:golint,paralleltest
+	tests := []struct {
+		name   string
+		args   []string
+		exit   int
+		stderr string
+	}{
+		{"ok", []string{"-o", "out.out", "../../testdata/test10.binary"}, 0, "^$"},
+		{"error", []string{"--sort", "level", "xxx"}, 1, "^$"},
+		{"flag", []string{"-x"}, 1, "^$"},
+		{"open", []string{"../../testdata/nix"}, 1, "^$"},
+		{"decode", []string{"-o", "out.out", "../../testdata/test1.binary"}, 2, "^$"},
+		{"scvd", []string{"-I", "../../testdata/nix.xml", "xxx"}, 3, "^$"},
+		{"schema", []string{"-I", "../../testdata/schema_err.xml", "-o", "out.out", "../../testdata/test.binary"}, 0, "^$"},
+		{"schema --strict", []string{"--strict", "-I", "../../testdata/schema_err.xml", "-o", "out.out", "../../testdata/test.binary"}, 3, "^$"},
+		{"json schema", []string{"--diagnostics", "json", "-I", "../../testdata/schema_err.xml", "-o", "out.out", "../../testdata/test.binary"}, 0,
+			"^\\{\"severity\":\"warning\",\"code\":\"ok\",\"exit\":0,\"message\":\"../../testdata/schema_err.xml:8: unknown element \\\\u003cpint\\\\u003e in \\\\u003ctypedef\\\\u003e\"\\}\\n$"},
+		{"component version", []string{"--component-version", "x", "xxx"}, 1, "^$"},
+		{"json component version", []string{"--diagnostics", "json", "--component-version", "EventRecorderStub=2.0", "-I", "../../testdata/test.xml", "-o", "out.out", "../../testdata/test.binary"}, 0,
+			"^\\{\"severity\":\"warning\",\"code\":\"ok\",\"exit\":0,\"message\":\"no SCVD file of EventRecorderStub 2.0, using version 1.0.0\"\\}\\n$"},
+		{"remote http", []string{"-I", "http://localhost:1/RTX5.scvd", "xxx"}, 3, "^$"},
+		{"pack", []string{"--pack", "ARM.CMSIS-RTX", "xxx"}, 1, "^$"},
+		{"no match", []string{"--where", "component=nix", "-o", "out.out", "../../testdata/test10.binary"}, 4, "^$"},
+		{"level", []string{"-l", "Error", "-s", "-o", "out.out", "../../testdata/test10.binary"}, 0, "^$"},
+		{"json", []string{"--diagnostics", "json", "-I", "../../testdata/nix.xml", "xxx"}, 3,
+			"^\\{\"severity\":\"error\",\"code\":\"scvd\",\"exit\":3,\"message\":\"open ../../testdata/nix.xml: .*\"\\}\\n$"},
+		{"json warning", []string{"--diagnostics", "json", "--where", "component=nix", "-o", "out.out", "../../testdata/test10.binary"}, 4,
+			"^\\{\"severity\":\"warning\",\"code\":\"no-match\",\"exit\":4,\"message\":\"no event matched the filter\"\\}\\n$"},
+		{"json format", []string{"--diagnostics", "yaml", "xxx"}, 1, "^$"},
+		{"merge", []string{"merge", "-o", "out.out", "../../testdata/test10.binary", "../../testdata/nix"}, 1, "^$"},
+		{"assert", []string{"assert", "../../testdata/assert.yaml", "../../testdata/test10.binary"}, 5, "^$"},
+		{"compare", []string{"compare", "--count-threshold", "100", "../../testdata/test.binary", "../../testdata/test10.binary"}, 6, "^$"},
+		{"compare ok", []string{"compare", "../../testdata/test10.binary", "../../testdata/test10.binary"}, 0, "^$"},
+		{"diff", []string{"diff", "../../testdata/test.binary", "../../testdata/test10.binary"}, 7, "^$"},
+		{"diff same", []string{"diff", "../../testdata/test10.binary", "../../testdata/test10.binary"}, 0, "^$"},
+		{"selftest", []string{"selftest", "../../testdata/test.golden", "../../testdata/test10.binary"}, 7, "^$"},
+		{"assert rules", []string{"--assert", "../../testdata/nix.yaml", "xxx"}, 1, "^$"},
+		{"json assert", []string{"--diagnostics", "json", "--assert", "../../testdata/assert.yaml", "-s", "../../testdata/test10.binary"}, 5,
+			"^\\{\"severity\":\"error\",\"code\":\"assert\",\"exit\":5,\"message\":\"3 of 4 assertions failed\"\\}\\n$"},
+	}
+	savedArgs := os.Args
+	defer func() { os.Args = savedArgs }()
+	defer os.Remove("out.out")
+	paths = nil
+	for _, tt := range tests { //nolint:golint,paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			paths = nil
+			oldOut, oldErr := os.Stdout, os.Stderr
+			defer func() { os.Stdout, os.Stderr = oldOut, oldErr }()
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+			re, we, _ := os.Pipe()
+			os.Stderr = we
+			os.Args = append(savedArgs, tt.args...)
+			main()
+			w.Close()
+			we.Close()
+			_, _ = io.ReadAll(r)
+			buf, _ := io.ReadAll(re)
+			if exitCode != tt.exit {
+				t.Errorf("main() %s exit = %d, want %d", tt.name, exitCode, tt.exit)
+			}
+			if match, _ := regexp.Match(tt.stderr, buf); !match {
+				t.Errorf("main() %s stderr = %q, want %q", tt.name, string(buf), tt.stderr)
+			}
+		})
+	}
+}
+
+func Test_mainRemote(t *testing.T) { //nolint:golint,paralleltest
+	scvd, _ := os.ReadFile("../../testdata/test.xml")
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(scvd)
+	}))
+	defer server.Close()
+	savedClient := fetch.Client
+	defer func() { fetch.Client = savedClient }()
+	fetch.Client = server.Client()
+	savedArgs := os.Args
+	defer func() { os.Args = savedArgs }()
+	paths = nil
+	defer func() { paths = nil }()
+
+	cache := t.TempDir()
+	oldOut := os.Stdout
+	defer func() { os.Stdout = oldOut }()
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	os.Args = append(savedArgs, "--cache-dir", cache, "-I", server.URL+"/test.xml", "../../testdata/test10.binary")
+	main()
+	w.Close()
+	buf, _ := io.ReadAll(r)
+	if !strings.Contains(string(buf), " STDIO     stdout ") {
+		t.Errorf("main() remote = %s, want STDIO events", buf)
+	}
+	if files, _ := filepath.Glob(filepath.Join(cache, "*-test.xml")); len(files) != 1 {
+		t.Errorf("main() remote cache = %v, want test.xml", files)
+	}
+}
+
+func Test_watchArgs(t *testing.T) {
+	t.Parallel()
+
+	args := []string{"-I", "a.scvd", "--watch", "-watch=true", "--watchdog", "test.binary"}
+	want := []string{"--no-pager", "-I", "a.scvd", "--watchdog", "test.binary"}
+	if got := watchArgs(args); !reflect.DeepEqual(got, want) {
+		t.Errorf("watchArgs() = %v, want %v", got, want)
+	}
+}
+
+func Test_reportName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"/home/ci/run42/events.lp", "events.lp"},
+		{"events.lp", "events.lp"},
+		{"http://localhost:8086/api/v2/write", "http://localhost:8086/api/v2/write"},
+		{"udp://localhost:514", "udp://localhost:514"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.target, func(t *testing.T) {
+			t.Parallel()
+
+			if got := reportName(tt.target); got != tt.want {
+				t.Errorf("reportName() %s = %v, want %v", tt.target, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"eventlist/pkg/diag"
+	"eventlist/pkg/live"
+	"eventlist/pkg/serve"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// serveMain runs the command "serve": a server of workspaces with decoded captures
+func serveMain(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := flags.String("addr", "localhost:8080", "address of the server")
+	uploadDir := flags.String("upload-dir", "", "directory of the uploaded files, default: a temporary directory removed on exit")
+	grpcAddr := flags.String("grpc", "", "address of the gRPC decoding service, e.g. localhost:9090")
+	ui := flags.Bool("ui", false, "serve the viewer at the address")
+	stdio := flags.Bool("stdio", false, "serve the JSON-RPC API on standard input and output")
+	liveSource := flags.String("live", "", "live event source pushed to the WebSocket /api/live")
+	framing := flags.String("framing", "", "framing of the live records: none, cobs, slip or auto")
+	var scvdFiles includes
+	flags.Var(&scvdFiles, "I", "include SCVD file name of the live events")
+	elfFile := flags.String("a", "", "elf/axf file name of the live events")
+	flags.Usage = func() {
+		fmt.Printf("Usage: %s serve [--addr <address>] [--ui] [--upload-dir <dir>] [--grpc <address>]\n", Progname)
+		fmt.Printf("       [--live <source> [--framing <framing>] [-I <scvdFile>]... [-a <elfFile>]]\n")
+		fmt.Printf("       %s serve --stdio\n", Progname)
+		infoOpt(flags, "", "addr", "<address>")
+		infoOpt(flags, "", "ui", "")
+		infoOpt(flags, "", "upload-dir", "<dir>")
+		infoOpt(flags, "", "grpc", "<address>")
+		infoOpt(flags, "", "stdio", "")
+		infoOpt(flags, "", "live", "<source>")
+		infoOpt(flags, "", "framing", "<framing>")
+		infoOpt(flags, "I", "", "<scvdFile>")
+		infoOpt(flags, "a", "", "<elfFile>")
+	}
+	flags.SetOutput(nopWriter{})
+	if err := flags.Parse(args); err != nil {
+		if err != flag.ErrHelp {
+			diags.Error(diag.Error, err)
+		}
+		return
+	}
+	if flags.NArg() != 0 {
+		diags.Errorf(diag.Error, "serve takes no input file")
+		return
+	}
+	s := serve.New()
+	if *stdio {
+		// standard output carries the responses only
+		if *ui || len(*grpcAddr) != 0 || len(*liveSource) != 0 {
+			diags.Errorf(diag.Error, "--stdio excludes --ui, --grpc and --live")
+			return
+		}
+		if err := s.ServeRPC(os.Stdin, os.Stdout); err != nil {
+			diags.Error(diag.Error, err)
+		}
+		return
+	}
+	s.UI = *ui
+	s.UploadDir = *uploadDir
+	if len(s.UploadDir) == 0 {
+		dir, err := os.MkdirTemp("", "eventlist-uploads-")
+		if err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+		defer os.RemoveAll(dir)
+		s.UploadDir = dir
+	}
+	if len(*liveSource) != 0 {
+		var err error
+		if s.Live, err = serve.NewLive(serve.Options{SCVD: scvdFiles, ELF: *elfFile}); err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+		in, err := live.Open(*liveSource, live.Options{Framing: *framing})
+		if err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+		defer in.Close()
+		go func() {
+			err := s.Live.Run(in)
+			fmt.Printf("%s: live source ended after %d events\n", Progname, s.Live.Received())
+			if err != nil {
+				diags.Error(diag.Decode, err)
+			}
+		}()
+	} else if len(scvdFiles) != 0 || len(*elfFile) != 0 || len(*framing) != 0 {
+		diags.Errorf(diag.Error, "-I, -a and --framing require --live")
+		return
+	}
+	fmt.Printf("%s: serving workspaces at http://%s/api/workspaces\n", Progname, *addr)
+	if s.UI {
+		fmt.Printf("%s: serving the viewer at http://%s/\n", Progname, *addr)
+	}
+	// the temporary upload directory is removed on SIGINT and SIGTERM
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(stop)
+	done := make(chan error, 2)
+	go func() { done <- s.Serve(*addr) }()
+	if len(*grpcAddr) != 0 {
+		fmt.Printf("%s: serving the gRPC decoder at %s\n", Progname, *grpcAddr)
+		go func() { done <- serve.ServeGRPC(*grpcAddr) }()
+	}
+	select {
+	case err := <-done:
+		diags.Error(diag.Error, err)
+	case <-stop:
+	}
+}
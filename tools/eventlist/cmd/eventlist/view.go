@@ -0,0 +1,111 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"eventlist/pkg/diag"
+	"eventlist/pkg/elf"
+	"eventlist/pkg/live"
+	"eventlist/pkg/output"
+	"eventlist/pkg/view"
+	"eventlist/pkg/xml/scvd"
+	"flag"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// newScreen creates the terminal screen of the viewer, replaced by unit tests
+var newScreen = tcell.NewScreen
+
+// viewMain runs the command "view": an interactive viewer of an event file
+func viewMain(args []string) {
+	flags := flag.NewFlagSet("view", flag.ContinueOnError)
+	var files includes
+	flags.Var(&files, "I", "include SCVD file name")
+	elfFile := flags.String("a", "", "elf/axf file name")
+	markerIDs := flags.String("markers", "", "event ID ranges of the marker events, e.g. 0xA000-0xA00F")
+	flags.Usage = func() {
+		fmt.Printf("Usage: %s view [-I <scvdFile>]... [-a <elf/axfFile>] [--markers <ranges>] <logFile>\n", Progname)
+		infoOpt(flags, "a", "", "<fileName>")
+		infoOpt(flags, "I", "", "<fileName>")
+		infoOpt(flags, "", "markers", "<ranges>")
+		fmt.Println("Keys: q quit, / search, n/N next/previous, m/M next/previous marker, f filter, t jump to time, F follow, Enter detail")
+	}
+	flags.SetOutput(nopWriter{})
+	if err := flags.Parse(args); err != nil {
+		if err != flag.ErrHelp {
+			diags.Error(diag.Error, err)
+		}
+		return
+	}
+	if flags.NArg() != 1 {
+		diags.Errorf(diag.Error, "view requires one input file")
+		return
+	}
+
+	var err error
+	if len(*elfFile) != 0 {
+		if err = elf.Sections.Readelf(elfFile); err != nil {
+			diags.Error(diag.Error, err)
+			return
+		}
+	}
+	evdefs := make(map[uint16]scvd.Event)
+	typedefs := make(map[string]map[string]map[int16]string)
+	memberTypes := make(map[string]map[string]string)
+	var p []string = files
+	if err = scvd.Get(&p, evdefs, typedefs, memberTypes); err != nil {
+		diags.Error(diag.SCVD, err)
+		return
+	}
+	markers, err := newMarkers(*markerIDs, evdefs)
+	if err != nil {
+		diags.Error(diag.Error, err)
+		return
+	}
+	output.Marker = nil
+	if markers != nil {
+		output.Marker = markers.Is
+	}
+
+	in, err := live.Open(flags.Arg(0), live.Options{}) // follows the file while it grows
+	if err != nil {
+		diags.Error(diag.Error, err)
+		return
+	}
+	defer in.Close()
+	screen, err := newScreen()
+	if err == nil {
+		err = screen.Init()
+	}
+	if err != nil {
+		diags.Error(diag.Error, err)
+		return
+	}
+	defer screen.Fini()
+	view.New(flags.Arg(0)).Run(screen, in, evdefs, typedefs, memberTypes)
+}
+
+// nopWriter discards the error messages of the flag package
+type nopWriter struct{}
+
+func (nopWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
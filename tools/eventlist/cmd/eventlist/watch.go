@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"eventlist/pkg/diag"
+	"eventlist/pkg/watch"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+)
+
+// watchArgs returns the arguments of a decode run without --watch, the
+// pager would wait for its end
+func watchArgs(args []string) []string {
+	out := []string{"--no-pager"}
+	for _, arg := range args {
+		name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if strings.HasPrefix(arg, "-") && name == "watch" {
+			continue
+		}
+		out = append(out, arg)
+	}
+	return out
+}
+
+// watchRun decodes with the arguments without --watch in a new process,
+// and again whenever one of the files changes, until it is interrupted
+func watchRun(args []string, files []string) {
+	exe, err := os.Executable()
+	if err != nil {
+		diags.Error(diag.Error, err)
+		return
+	}
+	w := watch.New(files)
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	done := make(chan struct{})
+	go func() {
+		<-interrupt
+		close(done)
+	}()
+	for {
+		cmd := exec.Command(exe, watchArgs(args)...)
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+		_ = cmd.Run() // the run reports its errors
+		fmt.Fprintf(os.Stderr, "%s: waiting for changes, Ctrl-C ends\n", Progname)
+		changed, ok := w.Wait(done)
+		if !ok {
+			return
+		}
+		fmt.Fprintf(os.Stderr, "%s: %s changed\n", Progname, strings.Join(changed, ", "))
+	}
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+/*
+#include <stdint.h>
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"path/filepath"
+	"runtime/cgo"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// error messages of the handles, of eventlist_open for the handle 0
+var lastErr sync.Map
+
+// handle of a reader, a cgo.Handle or 0 after an error
+type handle = C.uintptr_t
+
+// eventlist_open opens a log file with the SCVD files, a list separated
+// by the path list separator, and returns the handle of the reader, 0 if
+// it cannot be opened
+//
+//export eventlist_open
+func eventlist_open(logFile *C.char, scvdFiles *C.char) handle {
+	var files []string
+	if list := C.GoString(scvdFiles); list != "" {
+		files = strings.Split(list, string(filepath.ListSeparator))
+	}
+	r, err := open(C.GoString(logFile), files)
+	if err != nil {
+		lastErr.Store(uintptr(0), err.Error())
+		return 0
+	}
+	return handle(cgo.NewHandle(r))
+}
+
+// eventlist_next returns the next event as JSON, NULL after the last event
+// or on an error, the string is released with eventlist_free
+//
+//export eventlist_next
+func eventlist_next(h handle) *C.char {
+	r := cgo.Handle(h).Value().(*reader)
+	data, err := r.next()
+	if err != nil {
+		lastErr.Store(uintptr(h), err.Error())
+		return nil
+	}
+	if data == nil {
+		return nil
+	}
+	return C.CString(string(data))
+}
+
+// eventlist_error returns the error of the last call of eventlist_next of
+// the handle, of eventlist_open with the handle 0, NULL without error, the
+// string is released with eventlist_free
+//
+//export eventlist_error
+func eventlist_error(h handle) *C.char {
+	msg, ok := lastErr.LoadAndDelete(uintptr(h))
+	if !ok {
+		return nil
+	}
+	return C.CString(msg.(string))
+}
+
+// eventlist_free releases a string returned by the library
+//
+//export eventlist_free
+func eventlist_free(s *C.char) {
+	C.free(unsafe.Pointer(s))
+}
+
+// eventlist_close closes the log file of the handle
+//
+//export eventlist_close
+func eventlist_close(h handle) {
+	ch := cgo.Handle(h)
+	_ = ch.Value().(*reader).close()
+	ch.Delete()
+	lastErr.Delete(uintptr(h))
+}
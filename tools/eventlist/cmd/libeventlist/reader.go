@@ -0,0 +1,81 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Command libeventlist is the C shared library of the decoder, so C and
+// C++ tools or Python with ctypes reuse the SCVD handling. Build it with
+//
+//	./make.sh lib -outdir <dir>
+//
+// which writes the library and its header libeventlist.h.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"eventlist/pkg/eval"
+	"eventlist/pkg/event"
+	"eventlist/pkg/output"
+	"eventlist/pkg/xml/scvd"
+)
+
+// record of a decoded event as returned by eventlist_next
+type record struct {
+	output.EventRecord
+	ID    uint16 `json:"id"`
+	Level string `json:"level,omitempty"`
+}
+
+// reader decodes the events of a log file one after the other
+type reader struct {
+	bin     event.Binary
+	in      *bufio.Reader
+	decoder *output.Decoder
+}
+
+// open a log file with the definitions of the SCVD files
+func open(logFile string, scvdFiles []string) (*reader, error) {
+	evdefs := make(map[uint16]scvd.Event)
+	typedefs := make(map[string]map[string]map[int16]string)
+	memberTypes := make(map[string]map[string]string)
+	if err := scvd.Get(&scvdFiles, evdefs, typedefs, memberTypes); err != nil {
+		return nil, err
+	}
+	r := &reader{decoder: output.NewDecoder(evdefs, typedefs, memberTypes)}
+	if r.in = r.bin.Open(&logFile); r.in == nil {
+		return nil, output.ErrNoEvents
+	}
+	return r, nil
+}
+
+// next returns the next event as JSON, nil after the last event
+func (r *reader) next() ([]byte, error) {
+	var ev event.Data
+	if err := ev.Read(r.in); err != nil {
+		if errors.Is(err, eval.ErrEof) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	rec := r.decoder.Record(&ev)
+	return json.Marshal(record{rec, ev.Info.ID, rec.Level()})
+}
+
+func (r *reader) close() error {
+	return r.bin.Close()
+}
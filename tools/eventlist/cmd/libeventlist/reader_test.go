@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"eventlist/pkg/output"
+	"testing"
+)
+
+func TestReader(t *testing.T) {
+	t.Parallel()
+
+	r, err := open("../../testdata/test10.binary", []string{"../../testdata/test.xml"})
+	if err != nil {
+		t.Fatalf("open() error = %v", err)
+	}
+	var got []map[string]any
+	for {
+		data, err := r.next()
+		if err != nil {
+			t.Fatalf("reader.next() error = %v", err)
+		}
+		if data == nil {
+			break
+		}
+		var rec map[string]any
+		if err := json.Unmarshal(data, &rec); err != nil {
+			t.Fatalf("reader.next() = %s, %v", data, err)
+		}
+		got = append(got, rec)
+	}
+	if len(got) != 2 || got[1]["component"] != "STDIO" || got[1]["level"] != "Op" || got[1]["id"] != float64(0xFE00) {
+		t.Errorf("reader.next() = %v, want 2 events", got)
+	}
+	if err := r.close(); err != nil {
+		t.Errorf("reader.close() error = %v", err)
+	}
+
+	if _, err := open("../../testdata/missing.binary", nil); !errors.Is(err, output.ErrNoEvents) {
+		t.Errorf("open() error = %v, want %v", err, output.ErrNoEvents)
+	}
+	if _, err := open("../../testdata/test10.binary", []string{"../../testdata/missing.xml"}); err == nil {
+		t.Errorf("open() error = nil, want error of the SCVD file")
+	}
+}
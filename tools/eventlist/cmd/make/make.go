@@ -19,14 +19,17 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
 	"errors"
 	"flag"
 	"fmt"
+	"math"
 	"os"
 	"os/exec"
 	"path"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -35,16 +38,25 @@ import (
 
 const program = "eventlist"
 const mainPath = "./cmd/" + program
+const wasmPath = "./cmd/wasm"
+const libPath = "./cmd/lib" + program
 const resourceFileName = "resource.syso"
 const unknownVersion = "0.0.0.0"
 const unknownYear = "2023"
 
 var legalCopyright = "Arm Ltd. and Contributors"
 
+// fuzz targets run by the test command with -fuzztime
+var fuzzTargets = []struct{ name, pkg string }{
+	{"FuzzRead", "./pkg/event"},
+	{"FuzzParse", "./pkg/xml/scvd"},
+}
+
 // Errors
 var ErrGitTag = errors.New("git tag error")
 var ErrVersion = errors.New("version error")
 var ErrCommand = errors.New("command error")
+var ErrRegression = errors.New("benchmark regression")
 
 func reportError(err error, msg string) error {
 	return fmt.Errorf("%w: %s", err, msg)
@@ -55,6 +67,11 @@ type Options struct {
 	targetArch string
 	outDir     string
 	covReport  string
+	fuzzTime   string
+	baseline   string
+	update     bool
+	threshold  float64
+	benchCount int
 }
 
 type runner struct {
@@ -74,6 +91,14 @@ func (r runner) run(command string) {
 		if err = r.build(r.options, info); err != nil {
 			fmt.Println(err.Error())
 		}
+	case command == "lib":
+		if err := r.lib(r.options); err != nil {
+			fmt.Println(err.Error())
+		}
+	case command == "wasm":
+		if err := r.wasm(r.options); err != nil {
+			fmt.Println(err.Error())
+		}
 	case command == "test":
 		if err := r.test(); err != nil {
 			fmt.Println(err.Error())
@@ -91,6 +116,11 @@ func (r runner) run(command string) {
 				return
 			}
 		}
+	case command == "bench":
+		if err := r.bench(); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
 	case command == "lint":
 		r.lint()
 	case command == "format":
@@ -116,6 +146,23 @@ func (r runner) executeCommand(command string) (err error) {
 	return err
 }
 
+// executeCommand with the standard output returned instead of printed
+func (r runner) executeCommandOutput(command string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	fmt.Println(command)
+	cmd := exec.Command("bash", "-c", command)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if stderrStr := stderr.String(); stderrStr != "" {
+		fmt.Println(stderrStr)
+	}
+	if err != nil {
+		fmt.Println(stdout.String())
+	}
+	return stdout.String(), err
+}
+
 func (r runner) build(options Options, versionInfo string) (err error) {
 	var extn string
 
@@ -132,12 +179,190 @@ func (r runner) build(options Options, versionInfo string) (err error) {
 	return err
 }
 
+// build the C shared library of the decoder and its header
+func (r runner) lib(options Options) (err error) {
+	extn := ".so"
+	switch options.targetOs {
+	case "windows":
+		extn = ".dll"
+	case "darwin":
+		extn = ".dylib"
+	}
+	cmd := "CGO_ENABLED=1 GOOS=" + options.targetOs + " GOARCH=" + options.targetArch +
+		" go build -buildmode=c-shared -o " + options.outDir + "/lib" + program + extn + " " + libPath
+	if err = r.executeCommand(cmd); err == nil {
+		fmt.Println("library build finished successfully!")
+	}
+	return err
+}
+
+// build the WebAssembly decoder and copy the JavaScript support file of
+// the Go distribution next to it
+func (r runner) wasm(options Options) (err error) {
+	goroot, err := r.executeCommandOutput("go env GOROOT")
+	if err != nil {
+		return err
+	}
+	goroot = strings.TrimSpace(goroot)
+	support := path.Join(goroot, "lib", "wasm", "wasm_exec.js")
+	if _, err := os.Stat(support); err != nil {
+		support = path.Join(goroot, "misc", "wasm", "wasm_exec.js") // before Go 1.24
+	}
+	cmd := "GOOS=js GOARCH=wasm go build -o " + options.outDir + "/" + program + ".wasm " + wasmPath +
+		" && cp \"" + support + "\" " + options.outDir
+	if err = r.executeCommand(cmd); err == nil {
+		fmt.Println("wasm build finished successfully!")
+	}
+	return err
+}
+
 func (r runner) test() (err error) {
 	args := "./..."
 	if len(r.args) != 0 {
 		args = strings.Join(r.args[:], " ")
 	}
-	return r.executeCommand("go test " + args)
+	if err = r.executeCommand("go test " + args); err != nil || r.options.fuzzTime == "" {
+		return err
+	}
+	return r.fuzz(r.options.fuzzTime)
+}
+
+// fuzz runs each of the fuzz targets for the time
+func (r runner) fuzz(fuzzTime string) (err error) {
+	for _, target := range fuzzTargets {
+		err = r.executeCommand("go test -run=^$ -fuzz=^" + target.name + "$ -fuzztime=" + fuzzTime + " " + target.pkg)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// result of a benchmark, with -count the fastest run
+type benchResult struct {
+	nsPerOp     float64
+	allocsPerOp float64
+}
+
+// parse the output of go test -bench, the benchmarks are named by
+// package and name without the GOMAXPROCS suffix
+func parseBench(text string) map[string]benchResult {
+	results := make(map[string]benchResult)
+	var pkg string
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "pkg:" {
+			pkg = fields[1]
+			continue
+		}
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+		name := fields[0]
+		if i := strings.LastIndexByte(name, '-'); i > 0 {
+			if _, err := strconv.Atoi(name[i+1:]); err == nil {
+				name = name[:i]
+			}
+		}
+		if pkg != "" {
+			name = pkg + "." + name
+		}
+		res := benchResult{nsPerOp: math.NaN(), allocsPerOp: math.NaN()}
+		for i := 2; i+1 < len(fields); i++ {
+			v, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				continue
+			}
+			switch fields[i+1] {
+			case "ns/op":
+				res.nsPerOp = v
+			case "allocs/op":
+				res.allocsPerOp = v
+			}
+		}
+		if math.IsNaN(res.nsPerOp) {
+			continue
+		}
+		if old, ok := results[name]; ok && old.nsPerOp <= res.nsPerOp {
+			continue
+		}
+		results[name] = res
+	}
+	return results
+}
+
+// compare the benchmarks with the baseline, returns the regressions
+// of more than threshold percent in time or allocations
+func compareBench(baseline, current map[string]benchResult, threshold float64) (report []string, regressions int) {
+	names := make([]string, 0, len(current))
+	for name := range current {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	limit := 1 + threshold/100
+	for _, name := range names {
+		cur := current[name]
+		base, ok := baseline[name]
+		if !ok {
+			report = append(report, fmt.Sprintf("%-60s %12.0f ns/op (new)", name, cur.nsPerOp))
+			continue
+		}
+		line := fmt.Sprintf("%-60s %12.0f ns/op %+7.1f%%", name, cur.nsPerOp, change(base.nsPerOp, cur.nsPerOp))
+		if !math.IsNaN(cur.allocsPerOp) && !math.IsNaN(base.allocsPerOp) {
+			line += fmt.Sprintf(" %10.0f allocs/op %+7.1f%%", cur.allocsPerOp, change(base.allocsPerOp, cur.allocsPerOp))
+		}
+		if cur.nsPerOp > base.nsPerOp*limit ||
+			(!math.IsNaN(cur.allocsPerOp) && cur.allocsPerOp > base.allocsPerOp*limit) {
+			line += " REGRESSION"
+			regressions++
+		}
+		report = append(report, line)
+	}
+	return report, regressions
+}
+
+// change from base to cur in percent
+func change(base, cur float64) float64 {
+	if base == 0 {
+		return 0
+	}
+	return (cur - base) / base * 100
+}
+
+// bench runs the benchmarks and compares them with the baseline file,
+// without a baseline or with update the results are stored as baseline
+func (r runner) bench() error {
+	args := "./..."
+	if len(r.args) != 0 {
+		args = strings.Join(r.args[:], " ")
+	}
+	out, err := r.executeCommandOutput("go test -run=^$ -bench=. -benchmem -count=" +
+		strconv.Itoa(r.options.benchCount) + " " + args)
+	if err != nil {
+		return err
+	}
+	current := parseBench(out)
+	baseFile := r.options.baseline
+	data, err := os.ReadFile(baseFile)
+	if r.options.update || errors.Is(err, os.ErrNotExist) {
+		if err = os.WriteFile(baseFile, []byte(out), 0o644); err == nil {
+			fmt.Println("info: benchmark baseline written to " + baseFile)
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	report, regressions := compareBench(parseBench(string(data)), current, r.options.threshold)
+	for _, line := range report {
+		fmt.Println(line)
+	}
+	if regressions > 0 {
+		return reportError(ErrRegression, fmt.Sprintf("%d benchmarks regressed by more than %g%% against %s",
+			regressions, r.options.threshold, baseFile))
+	}
+	return nil
 }
 
 func (r runner) coverage() (err error) {
@@ -248,8 +473,8 @@ func createResourceInfoFile(arch string) (version goversioninfo.VersionInfo, err
 
 func isCommandValid(command string) (result bool) {
 	for _, cmd := range []string{
-		"build", "coverage", "coverage-report",
-		"format", "help", "lint", "test",
+		"bench", "build", "coverage", "coverage-report",
+		"format", "help", "lib", "lint", "test", "wasm",
 	} {
 		if cmd == command {
 			return true
@@ -343,6 +568,11 @@ func main() {
 	targetArch := commFlag.String("arch", runtime.GOARCH, "Target architecture")
 	outDir := commFlag.String("outdir", ".", "Output directory")
 	covReport := commFlag.String("html", "", "Coverage report")
+	fuzzTime := commFlag.String("fuzztime", "", "Run the fuzz targets for the duration after the tests")
+	baseline := commFlag.String("baseline", "bench.txt", "Benchmark baseline file")
+	update := commFlag.Bool("update", false, "Write the benchmark results as baseline")
+	threshold := commFlag.Float64("threshold", 20, "Regression threshold of the benchmarks in percent")
+	benchCount := commFlag.Int("count", 3, "Number of runs of each benchmark, the fastest is compared")
 	_ = commFlag.Parse(os.Args[2:])
 	arguments := commFlag.Args()
 
@@ -352,6 +582,11 @@ func main() {
 			targetArch: *targetArch,
 			outDir:     *outDir,
 			covReport:  *covReport,
+			fuzzTime:   *fuzzTime,
+			baseline:   *baseline,
+			update:     *update,
+			threshold:  *threshold,
+			benchCount: *benchCount,
 		},
 		args: arguments,
 	}
@@ -0,0 +1,77 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Command wasm is the WebAssembly build of the decoder, it runs the decode
+// engine inside a browser or a VS Code webview. Build it with
+//
+//	./make.sh wasm -outdir <dir>
+//
+// and load eventlist.wasm with wasm_exec.js of the Go distribution. The
+// global object eventlist then has the functions
+//
+//	eventlist.addSCVD(text)  // add the events of an SCVD file, returns an error message or null
+//	eventlist.decode(bytes)  // decode a log of a Uint8Array, returns a JSON array of the events
+//	eventlist.reset()        // remove the added SCVD files
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"eventlist/pkg/event"
+	"eventlist/pkg/output"
+	"eventlist/pkg/xml/scvd"
+)
+
+// record of a decoded event as returned to JavaScript
+type record struct {
+	output.EventRecord
+	ID    uint16 `json:"id"`
+	Level string `json:"level,omitempty"`
+}
+
+// session holds the definitions of the added SCVD files
+type session struct {
+	evdefs      map[uint16]scvd.Event
+	typedefs    map[string]map[string]map[int16]string
+	memberTypes map[string]map[string]string
+}
+
+func newSession() *session {
+	return &session{evdefs: make(map[uint16]scvd.Event), typedefs: make(map[string]map[string]map[int16]string),
+		memberTypes: make(map[string]map[string]string)}
+}
+
+// add the events, typedefs and member types of the text of an SCVD file
+func (s *session) addSCVD(text []byte) error {
+	return scvd.Parse(bytes.NewReader(text), s.evdefs, s.typedefs, s.memberTypes)
+}
+
+// decode the events of a log, the value of an event is the error message
+// if it cannot be formatted
+func (s *session) decode(data []byte) ([]byte, error) {
+	output.TimeFactor = nil // set by the clock events of the log
+	records := []record{}
+	err := output.Decode(bytes.NewReader(data), s.evdefs, s.typedefs, s.memberTypes, func(rec *output.EventRecord, ev *event.Data) error {
+		records = append(records, record{*rec, ev.Info.ID, rec.Level()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(records)
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestSession(t *testing.T) { //nolint:golint,paralleltest
+	s := newSession()
+	text, err := os.ReadFile("../../testdata/test.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.addSCVD(text); err != nil {
+		t.Fatalf("session.addSCVD() error = %v", err)
+	}
+	if err := s.addSCVD([]byte("<component_viewer>")); err == nil {
+		t.Errorf("session.addSCVD() error = nil, want error")
+	}
+
+	data, err := os.ReadFile("../../testdata/test10.binary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.decode(data)
+	if err != nil {
+		t.Fatalf("session.decode() error = %v", err)
+	}
+	var records []map[string]any
+	if err := json.Unmarshal(got, &records); err != nil {
+		t.Fatalf("session.decode() = %s, %v", got, err)
+	}
+	if len(records) != 2 || records[0]["id"] != float64(0xFF03) || records[1]["value"] != "hello wo" {
+		t.Errorf("session.decode() = %s, want 2 events", got)
+	}
+	if _, err := s.decode(data[:30]); err == nil {
+		t.Errorf("session.decode() error = nil, want error of a broken log")
+	}
+}
@@ -0,0 +1,63 @@
+//go:build js && wasm
+
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"syscall/js"
+)
+
+// bytes of a Uint8Array
+func bytesOf(v js.Value) []byte {
+	b := make([]byte, v.Get("length").Int())
+	js.CopyBytesToGo(b, v)
+	return b
+}
+
+func main() {
+	s := newSession()
+	api := map[string]any{
+		"addSCVD": js.FuncOf(func(_ js.Value, args []js.Value) any {
+			if len(args) != 1 {
+				return "addSCVD(text) requires one argument"
+			}
+			if err := s.addSCVD([]byte(args[0].String())); err != nil {
+				return err.Error()
+			}
+			return nil
+		}),
+		"decode": js.FuncOf(func(_ js.Value, args []js.Value) any {
+			if len(args) != 1 {
+				panic(js.Global().Get("Error").New("decode(bytes) requires one argument"))
+			}
+			data, err := s.decode(bytesOf(args[0]))
+			if err != nil {
+				panic(js.Global().Get("Error").New(err.Error()))
+			}
+			return string(data)
+		}),
+		"reset": js.FuncOf(func(js.Value, []js.Value) any {
+			s = newSession()
+			return nil
+		}),
+	}
+	js.Global().Set("eventlist", js.ValueOf(api))
+	select {} // the functions are called until the page is closed
+}
@@ -3,6 +3,27 @@ module eventlist
 go 1.20
 
 require (
-	github.com/akavel/rsrc v0.10.2 // indirect
+	github.com/gdamore/tcell/v2 v2.7.4
 	github.com/josephspurrier/goversioninfo v1.4.0
+	github.com/yuin/gopher-lua v1.1.1
+	go.bug.st/serial v1.6.4
+	golang.org/x/image v0.18.0
+	golang.org/x/net v0.12.0
+	google.golang.org/grpc v1.58.3
+	google.golang.org/protobuf v1.31.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/akavel/rsrc v0.10.2 // indirect
+	github.com/creack/goselect v0.1.2 // indirect
+	github.com/gdamore/encoding v1.0.0 // indirect
+	github.com/golang/protobuf v1.5.3 // indirect
+	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
+	github.com/mattn/go-runewidth v0.0.15 // indirect
+	github.com/rivo/uniseg v0.4.3 // indirect
+	golang.org/x/sys v0.19.0 // indirect
+	golang.org/x/term v0.18.0 // indirect
+	golang.org/x/text v0.16.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20230711160842-782d3b101e98 // indirect
 )
@@ -13,19 +13,32 @@ usage() {
   echo "  make.sh <command> [OPTIONS...]"
   echo ""
   echo "commands:"
+  echo "  bench           : Run benchmarks and compare them with the baseline"
   echo "  build           : Build executable"
   echo "  coverage        : Run tests with coverage info"
   echo "  format          : Align indentation and format code"
+  echo "  lib             : Build the C shared library libeventlist and its header"
   echo "  lint            : Run linter"
   echo "  test            : Run all tests"
+  echo "  wasm            : Build the WebAssembly decoder eventlist.wasm and copy wasm_exec.js"
   echo ""
   echo "build options:"
   echo "  -arch arg       : Optional target architecture for e.g amd64 etc [default: host arch]"
   echo "  -os arg         : Optional target operating system for e.g windows, linux, darwin etc [default: host OS]"
   echo "  -outdir arg     : Optional output directory for executable generation [default: current directory]"
+  echo "                    also the options of lib, -outdir also of wasm"
   echo ""
   echo "coverage options:"
   echo "  -html arg       : Coverage file path"
+  echo ""
+  echo "test options:"
+  echo "  -fuzztime arg   : Optional duration to run each fuzz target after the tests, for e.g 30s"
+  echo ""
+  echo "bench options:"
+  echo "  -baseline arg   : Optional baseline file [default: bench.txt]"
+  echo "  -count arg      : Optional number of runs of each benchmark, the fastest is compared [default: 3]"
+  echo "  -threshold arg  : Optional regression threshold in percent [default: 20]"
+  echo "  -update         : Write the results as new baseline"
 }
 
 if [ $# -eq 0 ]
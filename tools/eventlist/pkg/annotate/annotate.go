@@ -0,0 +1,99 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package annotate reads a sidecar file of annotations, notes of a reviewer
+// at a time or an event of a log, e.g. "firmware v2 flashed here", that are
+// merged into the event list and the exports.
+package annotate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+var errAnnotation = errors.New("annotation requires text and either index or time")
+
+// Annotation is a note at an event index or at a time in s, it is added to
+// the first event at or after the time
+type Annotation struct {
+	Index *int     `yaml:"index"`
+	Time  *float64 `yaml:"time"`
+	Text  string   `yaml:"text"`
+}
+
+// List holds the annotations by index and the time annotations sorted by time
+type List struct {
+	byIndex map[int][]string
+	byTime  []Annotation
+}
+
+// Load reads an annotation file, a YAML list of annotations:
+//
+//	# notes of app.log
+//	- time: 1.5
+//	  text: firmware v2 flashed here
+//	- index: 120
+//	  text: RF interference starts
+func Load(filename string) (*List, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var entries []Annotation
+	if err = yaml.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	l := &List{byIndex: make(map[int][]string)}
+	for i, a := range entries {
+		if len(a.Text) == 0 || (a.Index == nil) == (a.Time == nil) {
+			return nil, fmt.Errorf("%w: %s: entry %d", errAnnotation, filename, i+1)
+		}
+		if a.Index != nil {
+			l.byIndex[*a.Index] = append(l.byIndex[*a.Index], a.Text)
+		} else {
+			l.byTime = append(l.byTime, a)
+		}
+	}
+	sort.SliceStable(l.byTime, func(i, j int) bool { return *l.byTime[i].Time < *l.byTime[j].Time })
+	return l, nil
+}
+
+// Cursor returns the annotations of the events one after the other
+type Cursor struct {
+	list *List
+	pos  int // next time annotation
+}
+
+// Cursor creates a cursor at the first event
+func (l *List) Cursor() *Cursor {
+	return &Cursor{list: l}
+}
+
+// Next returns the annotations of the next event, the events must be
+// passed in order
+func (c *Cursor) Next(index int, time float64) []string {
+	texts := c.list.byIndex[index]
+	for ; c.pos < len(c.list.byTime) && *c.list.byTime[c.pos].Time <= time; c.pos++ {
+		texts = append(texts[:len(texts):len(texts)], c.list.byTime[c.pos].Text)
+	}
+	return texts
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package annotate
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		filename string
+		wantErr  error
+	}{
+		{"ok", "../../testdata/annotations.yaml", nil},
+		{"index and time", "../../testdata/annotations_err.yaml", errAnnotation},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Load(tt.filename)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Load() %s error = %v, want %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+	if _, err := Load("../../testdata/nix.yaml"); err == nil {
+		t.Errorf("Load() nix error = nil, want error")
+	}
+	if _, err := Load("../../testdata/test.xml"); err == nil {
+		t.Errorf("Load() test.xml error = nil, want error")
+	}
+}
+
+func TestCursor_Next(t *testing.T) {
+	t.Parallel()
+
+	two, three := 1.5, 3.0
+	l := &List{
+		byIndex: map[int][]string{1: {"a"}},
+		byTime:  []Annotation{{Time: &two, Text: "b"}, {Time: &two, Text: "c"}, {Time: &three, Text: "d"}},
+	}
+	events := []struct {
+		index int
+		time  float64
+	}{{0, 1.0}, {1, 1.5}, {2, 2.0}, {3, 4.0}, {4, 5.0}}
+	want := [][]string{nil, {"a", "b", "c"}, nil, {"d"}, nil}
+	c := l.Cursor()
+	for i, ev := range events {
+		if got := c.Next(ev.index, ev.time); !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("Cursor.Next() %d = %v, want %v", ev.index, got, want[i])
+		}
+	}
+	if got := l.byIndex[1]; !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("Cursor.Next() changed index annotations to %v", got)
+	}
+}
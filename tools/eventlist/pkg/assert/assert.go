@@ -0,0 +1,322 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package assert
+
+import (
+	"errors"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/stats"
+	"eventlist/pkg/where"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var errRule = errors.New("invalid assertion")
+
+// Rule is one assertion. Count compares the number of events matching
+// Where, or the number of start/stop pairs, e.g. "== 100". Duration compares
+// a statistic of the times from an event matching Start to the next event
+// matching Stop, e.g. "p99 < 5ms". The statistic is min, max, avg or a
+// percentile p1 to p100. The events are selected with --where expressions.
+type Rule struct {
+	Name     string `yaml:"name"`
+	Where    string `yaml:"where"`
+	Start    string `yaml:"start"`
+	Stop     string `yaml:"stop"`
+	Count    string `yaml:"count"`
+	Duration string `yaml:"duration"`
+
+	where, start, stop *where.Filter
+	count, duration    *check
+	matched            int
+	open               bool // a start event without stop event
+	since              float64
+	durations          stats.Digest
+}
+
+// check compares a number with a limit
+type check struct {
+	stat  string // statistic of the durations
+	op    string
+	limit float64
+}
+
+var units = []struct {
+	name string
+	per  float64 // units per second
+}{{"ms", 1e3}, {"us", 1e6}, {"ns", 1e9}, {"s", 1}}
+
+// parse "op limit" or "stat op limit" with duration, the limit of a
+// duration may have a unit s, ms, us or ns
+func parseCheck(s string, duration bool) (*check, error) {
+	fields := strings.Fields(s)
+	c := &check{}
+	if duration {
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("%w: %s", errRule, s)
+		}
+		c.stat, fields = fields[0], fields[1:]
+		if _, ok := percentile(c.stat); !ok && c.stat != "min" && c.stat != "max" && c.stat != "avg" {
+			return nil, fmt.Errorf("%w: unknown statistic %s", errRule, c.stat)
+		}
+	}
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("%w: %s", errRule, s)
+	}
+	c.op = fields[0]
+	switch c.op {
+	case "==", "!=", "<", "<=", ">", ">=":
+	default:
+		return nil, fmt.Errorf("%w: unknown operator %s", errRule, c.op)
+	}
+	text := strings.Join(fields[1:], "")
+	per := 1.0
+	if duration {
+		for _, unit := range units {
+			if strings.HasSuffix(text, unit.name) {
+				text, per = strings.TrimSuffix(text, unit.name), unit.per
+				break
+			}
+		}
+	}
+	v, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errRule, s)
+	}
+	c.limit = v / per
+	return c, nil
+}
+
+// percentile returns the percent of a statistic p1 to p100
+func percentile(stat string) (float64, bool) {
+	if !strings.HasPrefix(stat, "p") {
+		return 0, false
+	}
+	p, err := strconv.ParseFloat(stat[1:], 64)
+	return p, err == nil && p > 0 && p <= 100
+}
+
+func (c *check) holds(v float64) bool {
+	switch c.op {
+	case "==":
+		return v == c.limit
+	case "!=":
+		return v != c.limit
+	case "<":
+		return v < c.limit
+	case "<=":
+		return v <= c.limit
+	case ">":
+		return v > c.limit
+	}
+	return v >= c.limit
+}
+
+// Assertions checks the rules against the event stream
+type Assertions struct {
+	Title string  `yaml:"title"`
+	Rules []*Rule `yaml:"rules"`
+}
+
+// Load reads the rules from a YAML file
+func Load(filename string) (*Assertions, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var a Assertions
+	if err = yaml.Unmarshal(data, &a); err != nil {
+		return nil, err
+	}
+	for _, r := range a.Rules {
+		if err = r.parse(); err != nil {
+			return nil, err
+		}
+	}
+	return &a, nil
+}
+
+func (r *Rule) parse() error {
+	if r.Name == "" {
+		return fmt.Errorf("%w: missing name", errRule)
+	}
+	var err error
+	filter := func(expr string) *where.Filter {
+		if expr == "" || err != nil {
+			return nil
+		}
+		var f *where.Filter
+		if f, err = where.Parse([]string{expr}); err != nil {
+			err = fmt.Errorf("%w: %s: %v", errRule, r.Name, err)
+		}
+		return f
+	}
+	r.where, r.start, r.stop = filter(r.Where), filter(r.Start), filter(r.Stop)
+	if err != nil {
+		return err
+	}
+	if (r.start == nil) != (r.stop == nil) || (r.start != nil && r.where != nil) {
+		return fmt.Errorf("%w: %s: either where or start and stop", errRule, r.Name)
+	}
+	if r.Duration != "" && r.start == nil {
+		return fmt.Errorf("%w: %s: duration requires start and stop", errRule, r.Name)
+	}
+	if r.Count == "" && r.Duration == "" {
+		return fmt.Errorf("%w: %s: missing count or duration", errRule, r.Name)
+	}
+	if r.Count != "" {
+		if r.count, err = parseCheck(r.Count, false); err != nil {
+			return fmt.Errorf("%w: %s", err, r.Name)
+		}
+	}
+	if r.Duration != "" {
+		if r.duration, err = parseCheck(r.Duration, true); err != nil {
+			return fmt.Errorf("%w: %s", err, r.Name)
+		}
+	}
+	return nil
+}
+
+// Event counts the matching events and measures the start/stop pairs
+func (a *Assertions) Event(ev *bus.Event) error {
+	for _, r := range a.Rules {
+		switch {
+		case r.start != nil:
+			if r.open && r.stop.Match(ev) {
+				r.durations.Add(ev.Time - r.since)
+				r.open = false
+			} else if !r.open && r.start.Match(ev) {
+				r.open, r.since = true, ev.Time
+			}
+		case r.where == nil || r.where.Match(ev):
+			r.matched++
+		}
+	}
+	return nil
+}
+
+// End does nothing, the result is complete after the last event
+func (a *Assertions) End() error {
+	return nil
+}
+
+// stat returns a statistic of the durations, percentiles of many
+// durations are estimated
+func (r *Rule) stat(name string) float64 {
+	switch name {
+	case "min":
+		return r.durations.Min()
+	case "max":
+		return r.durations.Max()
+	case "avg":
+		return r.durations.Mean()
+	}
+	p, _ := percentile(name)
+	return r.durations.Quantile(p / 100)
+}
+
+// results returns the expected and the actual line of every check of the rule
+func (r *Rule) results() (expected []string, actual []string, ok bool) {
+	ok = true
+	if r.count != nil {
+		n := r.matched
+		if r.start != nil {
+			n = r.durations.Count()
+		}
+		expected = append(expected, "count "+r.Count)
+		actual = append(actual, fmt.Sprintf("count %d", n))
+		ok = r.count.holds(float64(n))
+	}
+	if r.duration != nil {
+		expected = append(expected, "duration "+r.Duration)
+		if r.durations.Count() == 0 {
+			actual = append(actual, "duration: no start/stop pair")
+			ok = false
+		} else {
+			v := r.stat(r.duration.stat)
+			actual = append(actual, fmt.Sprintf("duration %s %s", r.duration.stat, formatTime(v)))
+			ok = ok && r.duration.holds(v)
+		}
+	}
+	return expected, actual, ok
+}
+
+func formatTime(t float64) string {
+	switch {
+	case t != 0 && math.Abs(t) < 1e-3:
+		return strconv.FormatFloat(t*1e6, 'g', 6, 64) + "us"
+	case t != 0 && math.Abs(t) < 1:
+		return strconv.FormatFloat(t*1e3, 'g', 6, 64) + "ms"
+	}
+	return strconv.FormatFloat(t, 'g', 6, 64) + "s"
+}
+
+// Failed returns the number of failed rules
+func (a *Assertions) Failed() int {
+	failed := 0
+	for _, r := range a.Rules {
+		if _, _, ok := r.results(); !ok {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Passed reports if all rules hold
+func (a *Assertions) Passed() bool {
+	return a.Failed() == 0
+}
+
+// Report writes the rules as diff: a passed rule with its result, a failed
+// rule with the expected lines marked - and the actual lines marked +
+func (a *Assertions) Report(w io.Writer) error {
+	title := "Assertions"
+	if a.Title != "" {
+		title += ": " + a.Title
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "   %s\n   %s\n\n", title, strings.Repeat("-", len(title)))
+	for _, r := range a.Rules {
+		expected, actual, ok := r.results()
+		if ok {
+			fmt.Fprintf(&b, "  %s: %s\n", r.Name, strings.Join(actual, ", "))
+			continue
+		}
+		for _, line := range expected {
+			fmt.Fprintf(&b, "- %s: %s\n", r.Name, line)
+		}
+		for _, line := range actual {
+			fmt.Fprintf(&b, "+ %s: %s\n", r.Name, line)
+		}
+	}
+	failed := a.Failed()
+	verdict := "PASSED"
+	if failed != 0 {
+		verdict = "FAILED"
+	}
+	fmt.Fprintf(&b, "\n%d of %d assertions passed: %s\n", len(a.Rules)-failed, len(a.Rules), verdict)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,194 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package assert
+
+import (
+	"bytes"
+	"errors"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/event"
+	"eventlist/pkg/xml/scvd"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var poll = &scvd.Event{Brief: "App", Property: "Poll", Level: "Op"}
+var fail = &scvd.Event{Brief: "App", Property: "Fail", Level: "Error"}
+
+func run(a *Assertions) {
+	events := []struct {
+		def   *scvd.Event
+		value string
+	}{
+		{poll, "state=1 result=2"}, {poll, "state=1 result=2"}, {poll, "state=1 result=2"},
+		{poll, "state=1 result=3"}, {fail, "code=3"}, {fail, "code=3"}, {poll, "state=1 result=2"},
+	}
+	for i, e := range events {
+		value := e.value
+		_ = a.Event(bus.NewEvent(i, 4e-6*float64(i+1), &event.Data{}, e.def,
+			func() (string, error) { return value, nil }))
+	}
+	_ = a.End()
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"count", "rules:\n  - name: a\n    where: level=Error\n    count: == 0\n", false},
+		{"duration", "rules:\n  - name: a\n    start: property=A\n    stop: property=B\n    duration: p99 < 5 ms\n", false},
+		{"unit", "rules:\n  - name: a\n    start: property=A\n    stop: property=B\n    duration: max <= 2ns\n", false},
+		{"name", "rules:\n  - count: == 0\n", true},
+		{"check", "rules:\n  - name: a\n", true},
+		{"operator", "rules:\n  - name: a\n    count: =< 1\n", true},
+		{"limit", "rules:\n  - name: a\n    count: == x\n", true},
+		{"statistic", "rules:\n  - name: a\n    start: id=1\n    stop: id=2\n    duration: p0 < 1s\n", true},
+		{"stop", "rules:\n  - name: a\n    start: id=1\n    count: == 1\n", true},
+		{"where", "rules:\n  - name: a\n    where: id=1\n    start: id=1\n    stop: id=2\n    count: == 1\n", true},
+		{"pairs", "rules:\n  - name: a\n    where: id=1\n    duration: max < 1s\n", true},
+		{"expression", "rules:\n  - name: a\n    where: nix=1\n    count: == 1\n", true},
+		{"yaml", "rules: 3\n", true},
+	}
+	for i, tt := range tests {
+		tt := tt
+		filename := filepath.Join(dir, fmt.Sprintf("a%d.yaml", i))
+		_ = os.WriteFile(filename, []byte(tt.content), 0600)
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Load(filename)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Load() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+	if _, err := Load(filepath.Join(dir, "nix.yaml")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Load() nix error = %v, want %v", err, os.ErrNotExist)
+	}
+}
+
+func Test_parseCheck(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		s        string
+		duration bool
+		want     check
+	}{
+		{"count", "!= 3", false, check{op: "!=", limit: 3}},
+		{"seconds", "avg > 1.5 s", true, check{stat: "avg", op: ">", limit: 1.5}},
+		{"milliseconds", "p99 < 5ms", true, check{stat: "p99", op: "<", limit: 5e-3}},
+		{"microseconds", "min >= 20 us", true, check{stat: "min", op: ">=", limit: 20e-6}},
+		{"plain", "max <= 2", true, check{stat: "max", op: "<=", limit: 2}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseCheck(tt.s, tt.duration)
+			if err != nil {
+				t.Errorf("parseCheck() %s error = %v", tt.name, err)
+				return
+			}
+			if *got != tt.want {
+				t.Errorf("parseCheck() %s = %v, want %v", tt.name, *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssertions_Event(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		rule Rule
+		want bool
+	}{
+		{"count", Rule{Name: "a", Where: "level=Error", Count: "== 2"}, true},
+		{"count fails", Rule{Name: "a", Where: "level=Error", Count: "== 0"}, false},
+		{"all", Rule{Name: "a", Count: "> 6"}, true},
+		{"pairs", Rule{Name: "a", Start: "property=Poll", Stop: "property=Fail", Count: "== 1"}, true},
+		{"max", Rule{Name: "a", Start: "property=Poll", Stop: "property=Poll", Duration: "max < 5us"}, true},
+		{"p50", Rule{Name: "a", Start: "property=Poll", Stop: "property=Poll", Duration: "p50 < 3us"}, false},
+		{"no pair", Rule{Name: "a", Start: "property=Reset", Stop: "property=Poll", Duration: "max < 1s"}, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rule := tt.rule
+			if err := rule.parse(); err != nil {
+				t.Errorf("Rule.parse() %s error = %v", tt.name, err)
+				return
+			}
+			a := &Assertions{Rules: []*Rule{&rule}}
+			run(a)
+			if got := a.Passed(); got != tt.want {
+				_, actual, _ := rule.results()
+				t.Errorf("Assertions.Event() %s = %v %v, want %v", tt.name, got, actual, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssertions_Report(t *testing.T) {
+	t.Parallel()
+
+	want := "   Assertions: Polling\n" +
+		"   -------------------\n\n" +
+		"- No errors: count == 0\n" +
+		"+ No errors: count 2\n" +
+		"  Polls: count 5\n" +
+		"  Poll to fail: count 1, duration p99 4us\n" +
+		"- All events: count == 8\n" +
+		"+ All events: count 7\n" +
+		"\n2 of 4 assertions passed: FAILED\n"
+
+	a, err := Load("../../testdata/assert.yaml")
+	if err != nil {
+		t.Errorf("Load() error = %v", err)
+		return
+	}
+	run(a)
+	var b bytes.Buffer
+	if err = a.Report(&b); err != nil {
+		t.Errorf("Assertions.Report() error = %v", err)
+	}
+	if b.String() != want {
+		t.Errorf("Assertions.Report() = %v, want %v", b.String(), want)
+	}
+	if got := a.Failed(); got != 2 {
+		t.Errorf("Assertions.Failed() = %d, want 2", got)
+	}
+	a.Rules = a.Rules[1:3]
+	if !a.Passed() {
+		t.Errorf("Assertions.Passed() = false, want true")
+	}
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bus
+
+import (
+	"eventlist/pkg/event"
+	"eventlist/pkg/xml/scvd"
+	"fmt"
+	"io"
+)
+
+// Event is one decoded event record as seen by the analyzers
+type Event struct {
+	Index  int
+	Time   float64
+	Data   *event.Data
+	Def    *scvd.Event // nil if there is no SCVD definition
+	format func() (string, error)
+	value  string
+	err    error
+	done   bool
+}
+
+// NewEvent creates an event, format is called on the first request of the value
+func NewEvent(index int, time float64, data *event.Data, def *scvd.Event, format func() (string, error)) *Event {
+	return &Event{Index: index, Time: time, Data: data, Def: def, format: format}
+}
+
+// Value returns the formatted value of the event
+func (ev *Event) Value() (string, error) {
+	if !ev.done {
+		if ev.format != nil {
+			ev.value, ev.err = ev.format()
+		}
+		ev.done = true
+	}
+	return ev.value, ev.err
+}
+
+// Component returns the component name, the component number if unknown
+func (ev *Event) Component() string {
+	if ev.Def != nil {
+		return ev.Def.Brief
+	}
+	return fmt.Sprintf("0x%02X", uint8(ev.Data.Info.ID>>8))
+}
+
+// Property returns the event property, the event ID if unknown
+func (ev *Event) Property() string {
+	if ev.Def != nil {
+		return ev.Def.Property
+	}
+	return fmt.Sprintf("0x%04X", ev.Data.Info.ID)
+}
+
+// Level returns the event level, empty if unknown
+func (ev *Event) Level() string {
+	if ev.Def != nil {
+		return ev.Def.Level
+	}
+	return ""
+}
+
+// Analyzer is implemented by every module consuming the decoded event stream
+type Analyzer interface {
+	Event(ev *Event) error // called for every event in order
+	End() error            // called once after the last event
+}
+
+// Reporter is implemented by analyzers adding a section to the text output
+type Reporter interface {
+	Report(w io.Writer) error
+}
+
+type Bus struct {
+	analyzers []Analyzer
+}
+
+// Subscribe adds an analyzer to the bus
+func (b *Bus) Subscribe(a Analyzer) {
+	b.analyzers = append(b.analyzers, a)
+}
+
+// Publish passes an event to all analyzers, stops at the first error
+func (b *Bus) Publish(ev *Event) error {
+	for _, a := range b.analyzers {
+		if err := a.Event(ev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// End finishes all analyzers and returns the first error
+func (b *Bus) End() error {
+	var err error
+	for _, a := range b.analyzers {
+		if e := a.End(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bus
+
+import (
+	"errors"
+	"eventlist/pkg/event"
+	"eventlist/pkg/xml/scvd"
+	"testing"
+)
+
+var errTest = errors.New("test error")
+
+type counter struct {
+	events int
+	ends   int
+	err    error
+}
+
+func (c *counter) Event(ev *Event) error {
+	c.events++
+	return c.err
+}
+
+func (c *counter) End() error {
+	c.ends++
+	return c.err
+}
+
+func TestEvent_Value(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	format := func() (string, error) {
+		calls++
+		return "value", errTest
+	}
+	ev := NewEvent(1, 0.5, nil, nil, format)
+	for i := 0; i < 2; i++ {
+		got, err := ev.Value()
+		if !errors.Is(err, errTest) {
+			t.Errorf("Event.Value() error = %v, want %v", err, errTest)
+		}
+		if got != "value" {
+			t.Errorf("Event.Value() = %v, want %v", got, "value")
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Event.Value() format calls = %v, want 1", calls)
+	}
+	ev = NewEvent(0, 0, nil, nil, nil)
+	if got, err := ev.Value(); got != "" || err != nil {
+		t.Errorf("Event.Value() = %v, %v, want empty", got, err)
+	}
+}
+
+func TestBus_Publish(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		analyzers  []*counter
+		wantErr    bool
+		wantEvents []int
+		wantEnds   []int
+	}{
+		{"none", nil, false, nil, nil},
+		{"two", []*counter{{}, {}}, false, []int{2, 2}, []int{1, 1}},
+		{"error", []*counter{{err: errTest}, {}}, true, []int{1, 0}, []int{1, 1}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var b Bus
+			for _, a := range tt.analyzers {
+				b.Subscribe(a)
+			}
+			var err error
+			for i := 0; i < 2 && err == nil; i++ {
+				err = b.Publish(NewEvent(i, 0, nil, nil, nil))
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Bus.Publish() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if err = b.End(); (err != nil) != tt.wantErr {
+				t.Errorf("Bus.End() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			for i, a := range tt.analyzers {
+				if a.events != tt.wantEvents[i] || a.ends != tt.wantEnds[i] {
+					t.Errorf("Bus %s analyzer %d = %d/%d, want %d/%d", tt.name, i,
+						a.events, a.ends, tt.wantEvents[i], tt.wantEnds[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEvent_names(t *testing.T) {
+	t.Parallel()
+
+	def := scvd.Event{Brief: "Net", Property: "Connect", Level: "Op"}
+	tests := []struct {
+		name          string
+		ev            *Event
+		wantComponent string
+		wantProperty  string
+		wantLevel     string
+	}{
+		{"known", NewEvent(0, 0, &event.Data{Info: event.Info{ID: 0x0A05}}, &def, nil), "Net", "Connect", "Op"},
+		{"unknown", NewEvent(0, 0, &event.Data{Info: event.Info{ID: 0x0A05}}, nil, nil), "0x0A", "0x0A05", ""},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.ev.Component(); got != tt.wantComponent {
+				t.Errorf("Event.Component() %s = %v, want %v", tt.name, got, tt.wantComponent)
+			}
+			if got := tt.ev.Property(); got != tt.wantProperty {
+				t.Errorf("Event.Property() %s = %v, want %v", tt.name, got, tt.wantProperty)
+			}
+			if got := tt.ev.Level(); got != tt.wantLevel {
+				t.Errorf("Event.Level() %s = %v, want %v", tt.name, got, tt.wantLevel)
+			}
+		})
+	}
+}
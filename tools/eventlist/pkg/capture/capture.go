@@ -0,0 +1,86 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package capture
+
+import (
+	"bufio"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/where"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Writer writes the records of the received events to a file while they
+// are received. With a filter only the records of the matching events and
+// the clock events are written, the clock events keep the timestamps of the
+// capture decodable. The records are written unchanged, so the capture can
+// be decoded like the original event stream.
+type Writer struct {
+	Name    string // name of the file in the report
+	file    *os.File
+	out     *bufio.Writer
+	filter  *where.Filter
+	buf     []byte
+	Written int // number of written records
+	Dropped int // number of records not matching the filter
+}
+
+// New creates the capture file, a nil filter writes all records
+func New(filename string, filter *where.Filter) (*Writer, error) {
+	file, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{Name: filename, file: file, out: bufio.NewWriter(file), filter: filter}, nil
+}
+
+// Event writes the record of the event if it passes the filter, the
+// record is flushed at once to keep the capture of an aborted session
+func (w *Writer) Event(ev *bus.Event) error {
+	id := ev.Data.Info.ID
+	if id != 0xFF00 && id != 0xFF03 && !w.filter.Match(ev) {
+		w.Dropped++
+		return nil
+	}
+	w.buf = ev.Data.AppendRecord(w.buf[:0])
+	if _, err := w.out.Write(w.buf); err != nil {
+		return err
+	}
+	w.Written++
+	return w.out.Flush()
+}
+
+// End closes the capture file
+func (w *Writer) End() error {
+	err := w.out.Flush()
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Report writes the number of written and filtered records
+func (w *Writer) Report(out io.Writer) error {
+	title := "Capture"
+	_, err := fmt.Fprintf(out, "   %s\n   %s\n\n%s: %d records written, %d records filtered out\n",
+		title, strings.Repeat("-", len(title)), w.Name, w.Written, w.Dropped)
+	return err
+}
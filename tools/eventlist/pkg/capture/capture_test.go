@@ -0,0 +1,131 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package capture
+
+import (
+	"bufio"
+	"bytes"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/event"
+	"eventlist/pkg/where"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile("../../testdata/test.binary")
+	if err != nil {
+		t.Fatalf("os.ReadFile() error = %v", err)
+	}
+	var events []*event.Data
+	in := bufio.NewReader(bytes.NewReader(data))
+	for {
+		var ev event.Data
+		if err := ev.Read(in); err != nil {
+			break
+		}
+		events = append(events, &ev)
+	}
+
+	tests := []struct {
+		name        string
+		exprs       []string
+		want        []uint16
+		wantDropped int
+		wantAll     bool
+	}{
+		{"all", nil, []uint16{0xF000, 0xFF00, 0xFE00, 0xFE00}, 0, true},
+		{"component", []string{"component=0xFE"}, []uint16{0xFF00, 0xFE00, 0xFE00}, 1, false},
+		{"none", []string{"id=0x1000"}, []uint16{0xFF00}, 3, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var filter *where.Filter
+			if tt.exprs != nil {
+				if filter, err = where.Parse(tt.exprs); err != nil {
+					t.Fatalf("where.Parse() error = %v", err)
+				}
+			}
+			filename := filepath.Join(t.TempDir(), "capture.binary")
+			w, err := New(filename, filter)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			for i, ev := range events {
+				if err := w.Event(bus.NewEvent(i, 0, ev, nil, nil)); err != nil {
+					t.Errorf("Writer.Event() %s error = %v", tt.name, err)
+				}
+			}
+			if err := w.End(); err != nil {
+				t.Errorf("Writer.End() %s error = %v", tt.name, err)
+			}
+			got, _ := os.ReadFile(filename)
+			if tt.wantAll && !bytes.Equal(got, data) {
+				t.Errorf("Writer %s = %x, want %x", tt.name, got, data)
+			}
+			var ids []uint16
+			in := bufio.NewReader(bytes.NewReader(got))
+			for {
+				var ev event.Data
+				if err := ev.Read(in); err != nil {
+					break
+				}
+				ids = append(ids, ev.Info.ID)
+			}
+			if len(ids) != len(tt.want) || w.Written != len(tt.want) || w.Dropped != tt.wantDropped {
+				t.Errorf("Writer %s = %X, written %d, dropped %d, want %X, dropped %d",
+					tt.name, ids, w.Written, w.Dropped, tt.want, tt.wantDropped)
+			}
+			for i := range ids {
+				if ids[i] != tt.want[i] {
+					t.Errorf("Writer %s = %X, want %X", tt.name, ids, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestWriter_Report(t *testing.T) {
+	t.Parallel()
+
+	w := Writer{Name: "capture.binary", Written: 3, Dropped: 1}
+	var b bytes.Buffer
+	if err := w.Report(&b); err != nil {
+		t.Errorf("Writer.Report() error = %v", err)
+	}
+	want := "   Capture\n   -------\n\ncapture.binary: 3 records written, 1 records filtered out\n"
+	if got := b.String(); got != want {
+		t.Errorf("Writer.Report() = %q, want %q", got, want)
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	if _, err := New(filepath.Join(t.TempDir(), "nix", "capture.binary"), nil); err == nil {
+		t.Errorf("New() error = nil, want error")
+	}
+}
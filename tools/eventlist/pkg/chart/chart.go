@@ -0,0 +1,197 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package chart draws a coarse timeline of the events in the terminal, one
+// line per component or per selected event ID, so the temporal relationship
+// of the components is visible without a graphical tool.
+package chart
+
+import (
+	"errors"
+	"eventlist/pkg/bus"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+var errStyle = errors.New("chart style must be unicode or ascii")
+var errWidth = errors.New("chart width must be positive")
+
+// DefaultWidth is the number of time columns if not specified
+const DefaultWidth = 64
+
+// maxLabel is the longest printed row label
+const maxLabel = 24
+
+// glyphs of the event counts of a column, from no event to the most events
+var glyphs = map[string][]string{
+	"unicode": {" ", "▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"},
+	"ascii":   {" ", ".", ":", "-", "=", "+", "*", "#", "@"},
+}
+
+// Chart records the time and the row of the events and draws the rows in
+// the report
+type Chart struct {
+	width  int
+	glyphs []string
+	ranges [][2]uint16    // selected event IDs, rows per component if empty
+	labels []string       // rows in order of appearance
+	rowOf  map[string]int // row index of a label
+	times  []float64
+	rows   []int32
+}
+
+// New creates a chart of width columns drawn with the glyphs of style,
+// unicode or ascii
+func New(style string, width int) (*Chart, error) {
+	g, ok := glyphs[strings.ToLower(style)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errStyle, style)
+	}
+	if width <= 0 {
+		return nil, fmt.Errorf("%w: %d", errWidth, width)
+	}
+	return &Chart{width: width, glyphs: g, rowOf: make(map[string]int)}, nil
+}
+
+// Select draws the events of the IDs first to last, one row per event
+// type "component/property", instead of one row per component
+func (c *Chart) Select(first, last uint16) {
+	c.ranges = append(c.ranges, [2]uint16{first, last})
+}
+
+func (c *Chart) selected(id uint16) bool {
+	for _, r := range c.ranges {
+		if id >= r[0] && id <= r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// Event records the time and the row of an event
+func (c *Chart) Event(ev *bus.Event) error {
+	label := ev.Component()
+	if len(c.ranges) != 0 {
+		if !c.selected(ev.Data.Info.ID) {
+			return nil
+		}
+		label += "/" + ev.Property()
+	}
+	row, ok := c.rowOf[label]
+	if !ok {
+		row = len(c.labels)
+		c.rowOf[label] = row
+		c.labels = append(c.labels, label)
+	}
+	c.times = append(c.times, ev.Time)
+	c.rows = append(c.rows, int32(row))
+	return nil
+}
+
+// End does nothing, the chart is drawn in the report
+func (c *Chart) End() error {
+	return nil
+}
+
+// counts distributes the events over the columns, counts[row][column]
+func (c *Chart) counts() (counts [][]int, start, end float64, max int) {
+	counts = make([][]int, len(c.labels))
+	for i := range counts {
+		counts[i] = make([]int, c.width)
+	}
+	if len(c.times) == 0 {
+		return counts, 0, 0, 0
+	}
+	start, end = c.times[0], c.times[0]
+	for _, t := range c.times {
+		start = math.Min(start, t)
+		end = math.Max(end, t)
+	}
+	width := (end - start) / float64(c.width)
+	for i, t := range c.times {
+		column := 0
+		if width > 0 {
+			column = int((t - start) / width)
+			if column >= c.width { // the last event belongs to the last column
+				column = c.width - 1
+			}
+		}
+		row := counts[c.rows[i]]
+		row[column]++
+		if row[column] > max {
+			max = row[column]
+		}
+	}
+	return counts, start, end, max
+}
+
+// glyph of a column with n events, the counts are scaled logarithmically
+// and a single event is visible
+func (c *Chart) glyph(n, max int) string {
+	if n == 0 {
+		return c.glyphs[0]
+	}
+	levels := len(c.glyphs) - 2
+	return c.glyphs[1+int(float64(levels)*math.Log1p(float64(n))/math.Log1p(float64(max)))]
+}
+
+// Report draws one line per row with the number of events at the end
+func (c *Chart) Report(out io.Writer) error {
+	title := "Timeline chart"
+	if _, err := fmt.Fprintf(out, "   %s\n   %s\n\n", title, strings.Repeat("-", len(title))); err != nil {
+		return err
+	}
+	if len(c.times) == 0 {
+		_, err := fmt.Fprintf(out, "No events\n")
+		return err
+	}
+	counts, start, end, max := c.counts()
+	if _, err := fmt.Fprintf(out, "Time: %.8f s to %.8f s, %.8f s per column\n\n",
+		start, end, (end-start)/float64(c.width)); err != nil {
+		return err
+	}
+	labelWidth := 0
+	for _, label := range c.labels {
+		if n := len(label); n > labelWidth {
+			labelWidth = n
+		}
+	}
+	if labelWidth > maxLabel {
+		labelWidth = maxLabel
+	}
+	var line strings.Builder
+	for i, label := range c.labels {
+		if len(label) > labelWidth {
+			label = label[:labelWidth]
+		}
+		line.Reset()
+		fmt.Fprintf(&line, "%-*s |", labelWidth, label)
+		total := 0
+		for _, n := range counts[i] {
+			line.WriteString(c.glyph(n, max))
+			total += n
+		}
+		fmt.Fprintf(&line, "| %d\n", total)
+		if _, err := io.WriteString(out, line.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chart
+
+import (
+	"bytes"
+	"errors"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/event"
+	"testing"
+)
+
+func ev(time float64, id uint16) *bus.Event {
+	return bus.NewEvent(0, time, &event.Data{Info: event.Info{ID: id}}, nil, nil)
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		style   string
+		width   int
+		wantErr error
+	}{
+		{"unicode", "unicode", 10, nil},
+		{"ascii", "ASCII", 10, nil},
+		{"style", "braille", 10, errStyle},
+		{"width", "ascii", 0, errWidth},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(tt.style, tt.width)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("New() %s error = %v, want %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestChart_Report(t *testing.T) {
+	t.Parallel()
+
+	events := []*bus.Event{ev(1.0, 0x1000), ev(1.5, 0x2001), ev(1.6, 0x1001), ev(2.0, 0x1000),
+		ev(2.1, 0x1000), ev(2.2, 0x1000), ev(3.0, 0x1000)}
+	tests := []struct {
+		name   string
+		style  string
+		ranges [][2]uint16
+		events []*bus.Event
+		want   string
+	}{
+		{"empty", "ascii", nil, nil, "   Timeline chart\n   --------------\n\nNo events\n"},
+		{"ascii", "ascii", nil, events, "   Timeline chart\n   --------------\n\n" +
+			"Time: 1.00000000 s to 3.00000000 s, 0.50000000 s per column\n\n" +
+			"0x10 |==@=| 6\n0x20 | =  | 1\n"},
+		{"unicode", "unicode", nil, events[:4], "   Timeline chart\n   --------------\n\n" +
+			"Time: 1.00000000 s to 2.00000000 s, 0.25000000 s per column\n\n" +
+			"0x10 |█ ██| 3\n0x20 |  █ | 1\n"},
+		{"select", "ascii", [][2]uint16{{0x1001, 0x1001}, {0x2000, 0x20FF}}, events,
+			"   Timeline chart\n   --------------\n\n" +
+				"Time: 1.50000000 s to 1.60000000 s, 0.02500000 s per column\n\n" +
+				"0x20/0x2001 |@   | 1\n0x10/0x1001 |   @| 1\n"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c, _ := New(tt.style, 4)
+			for _, r := range tt.ranges {
+				c.Select(r[0], r[1])
+			}
+			for _, e := range tt.events {
+				_ = c.Event(e)
+			}
+			if err := c.End(); err != nil {
+				t.Errorf("Chart.End() %s error = %v", tt.name, err)
+			}
+			var b bytes.Buffer
+			if err := c.Report(&b); err != nil || b.String() != tt.want {
+				t.Errorf("Chart.Report() %s = %q, %v, want %q", tt.name, b.String(), err, tt.want)
+			}
+		})
+	}
+}
+
+func TestChart_label(t *testing.T) {
+	t.Parallel()
+
+	c, _ := New("ascii", 1)
+	c.labels = []string{"a very long component name of a row"}
+	c.rowOf[c.labels[0]] = 0
+	c.times, c.rows = []float64{1}, []int32{0}
+	var b bytes.Buffer
+	if err := c.Report(&b); err != nil || !bytes.HasSuffix(b.Bytes(), []byte("\na very long component na |@| 1\n")) {
+		t.Errorf("Chart.Report() = %q, %v", b.String(), err)
+	}
+}
@@ -0,0 +1,227 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package checklist
+
+import (
+	"errors"
+	"eventlist/pkg/bus"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var errItem = errors.New("invalid checklist item")
+
+// Item is one required event of a checklist. The patterns of component,
+// property and value use the syntax of path.Match, empty patterns match all.
+type Item struct {
+	Name      string   `yaml:"name"`
+	Component string   `yaml:"component"`
+	Property  string   `yaml:"property"`
+	Level     string   `yaml:"level"`
+	Value     string   `yaml:"value"`
+	After     string   `yaml:"after"`  // name of an item that must occur before
+	Within    *float64 `yaml:"within"` // seconds after the item named by After
+	Min       *int     `yaml:"min"`    // minimum count, default 1 or 0 with max 0
+	Max       *int     `yaml:"max"`    // maximum count, default unlimited
+
+	count int
+	index int // first matching event
+	time  float64
+	after *Item
+}
+
+// Checklist verifies that a capture contains the required events
+type Checklist struct {
+	Title string  `yaml:"title"`
+	Items []*Item `yaml:"items"`
+}
+
+// Load reads a checklist from a YAML file
+func Load(filename string) (*Checklist, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var c Checklist
+	if err = yaml.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	if err = c.check(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (c *Checklist) check() error {
+	names := make(map[string]*Item)
+	for _, item := range c.Items {
+		if item.Name == "" {
+			return fmt.Errorf("%w: missing name", errItem)
+		}
+		if names[item.Name] != nil {
+			return fmt.Errorf("%w: %s: duplicate name", errItem, item.Name)
+		}
+		names[item.Name] = item
+	}
+	for _, item := range c.Items {
+		if item.After != "" {
+			if item.after = names[item.After]; item.after == nil || item.after == item {
+				return fmt.Errorf("%w: %s: after %s", errItem, item.Name, item.After)
+			}
+		} else if item.Within != nil {
+			return fmt.Errorf("%w: %s: within requires after", errItem, item.Name)
+		}
+		if item.Max != nil && *item.Max < item.required() {
+			return fmt.Errorf("%w: %s: max less than min", errItem, item.Name)
+		}
+		for _, pattern := range []string{item.Component, item.Property, item.Value} {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("%w: %s: %v", errItem, item.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func match(pattern string, s string) bool {
+	if pattern == "" {
+		return true
+	}
+	ok, _ := path.Match(pattern, s)
+	return ok
+}
+
+// Event counts the event for every item it matches
+func (c *Checklist) Event(ev *bus.Event) error {
+	for _, item := range c.Items {
+		if !match(item.Component, ev.Component()) || !match(item.Property, ev.Property()) ||
+			(item.Level != "" && item.Level != ev.Level()) {
+			continue
+		}
+		if item.Value != "" {
+			value, _ := ev.Value()
+			if !match(item.Value, value) {
+				continue
+			}
+		}
+		if a := item.after; a != nil {
+			if a.count == 0 || a.index == ev.Index {
+				continue // the preceding item did not occur yet
+			}
+			if item.Within != nil && ev.Time > a.time+*item.Within {
+				continue
+			}
+		}
+		if item.count == 0 {
+			item.index = ev.Index
+			item.time = ev.Time
+		}
+		item.count++
+	}
+	return nil
+}
+
+// End does nothing, the result is complete after the last event
+func (c *Checklist) End() error {
+	return nil
+}
+
+// minimum count of an item
+func (item *Item) required() int {
+	switch {
+	case item.Min != nil:
+		return *item.Min
+	case item.Max != nil && *item.Max == 0:
+		return 0 // the event must not occur
+	}
+	return 1
+}
+
+// result of an item and the reason
+func (item *Item) result() (bool, string) {
+	required := item.required()
+	var where string
+	if item.after != nil {
+		where = " after " + item.after.Name
+		if item.Within != nil {
+			where += fmt.Sprintf(" within %g s", *item.Within)
+		}
+	}
+	switch {
+	case item.count < required && item.count == 0:
+		return false, "not found" + where
+	case item.count < required:
+		return false, fmt.Sprintf("count %d%s, required %d", item.count, where, required)
+	case item.Max != nil && item.count > *item.Max:
+		return false, fmt.Sprintf("count %d%s, allowed %d", item.count, where, *item.Max)
+	case item.count == 0:
+		return true, "not found" + where
+	}
+	return true, fmt.Sprintf("event %d at %.8f s, count %d%s", item.index, item.time, item.count, where)
+}
+
+// Passed reports if all items of the checklist are fulfilled
+func (c *Checklist) Passed() bool {
+	for _, item := range c.Items {
+		if ok, _ := item.result(); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Report writes the result of every item and the overall verdict
+func (c *Checklist) Report(w io.Writer) error {
+	title := "Checklist"
+	if c.Title != "" {
+		title += ": " + c.Title
+	}
+	nameSize := len("Item")
+	for _, item := range c.Items {
+		if len(item.Name) > nameSize {
+			nameSize = len(item.Name)
+		}
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "   %s\n   %s\n\n", title, strings.Repeat("-", len(title)))
+	fmt.Fprintf(&b, "Result %*s Details\n", -nameSize, "Item")
+	fmt.Fprintf(&b, "------ %*s -------\n", -nameSize, "----")
+	passed := 0
+	for _, item := range c.Items {
+		ok, reason := item.result()
+		verdict := "FAIL"
+		if ok {
+			verdict = "PASS"
+			passed++
+		}
+		fmt.Fprintf(&b, "%-6s %*s %s\n", verdict, -nameSize, item.Name, reason)
+	}
+	verdict := "PASSED"
+	if passed != len(c.Items) {
+		verdict = "FAILED"
+	}
+	fmt.Fprintf(&b, "\n%d of %d items passed: %s\n", passed, len(c.Items), verdict)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,164 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package checklist
+
+import (
+	"bytes"
+	"errors"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/event"
+	"eventlist/pkg/xml/scvd"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var poll = &scvd.Event{Brief: "App", Property: "Poll", Level: "Op"}
+var fail = &scvd.Event{Brief: "App", Property: "Fail", Level: "Error"}
+
+// events of testdata/squash.binary decoded with testdata/error.xml
+func run(c *Checklist) {
+	events := []struct {
+		def   *scvd.Event
+		value string
+	}{
+		{poll, "state=1 result=2"}, {poll, "state=1 result=2"}, {poll, "state=1 result=2"},
+		{poll, "state=1 result=3"}, {fail, "code=3"}, {fail, "code=3"}, {poll, "state=1 result=2"},
+	}
+	for i, e := range events {
+		value := e.value
+		_ = c.Event(bus.NewEvent(i, 4e-6*float64(i+1), &event.Data{}, e.def,
+			func() (string, error) { return value, nil }))
+	}
+	_ = c.End()
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"ok", "items:\n  - name: a\n  - name: b\n    after: a\n    within: 1\n", false},
+		{"name", "items:\n  - property: Poll\n", true},
+		{"duplicate", "items:\n  - name: a\n  - name: a\n", true},
+		{"after", "items:\n  - name: a\n    after: b\n", true},
+		{"self", "items:\n  - name: a\n    after: a\n", true},
+		{"within", "items:\n  - name: a\n    within: 1\n", true},
+		{"max", "items:\n  - name: a\n    min: 2\n    max: 1\n", true},
+		{"max default", "items:\n  - name: a\n    max: 0\n", false},
+		{"pattern", "items:\n  - name: a\n    value: \"[\"\n", true},
+		{"yaml", "items: 3\n", true},
+	}
+	for i, tt := range tests {
+		tt := tt
+		filename := filepath.Join(dir, fmt.Sprintf("c%d.yaml", i))
+		_ = os.WriteFile(filename, []byte(tt.content), 0600)
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Load(filename)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Load() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+	if _, err := Load(filepath.Join(dir, "nix.yaml")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Load() nix error = %v, want %v", err, os.ErrNotExist)
+	}
+}
+
+func TestChecklist_Event(t *testing.T) {
+	t.Parallel()
+
+	one := 1
+	zero := 0
+	short := 0.000001
+	tests := []struct {
+		name  string
+		items []*Item
+		want  []bool
+	}{
+		{"match", []*Item{{Name: "a", Component: "App", Property: "Po*"}}, []bool{true}},
+		{"missing", []*Item{{Name: "a", Property: "Reset"}}, []bool{false}},
+		{"optional", []*Item{{Name: "a", Property: "Reset", Min: &zero}}, []bool{true}},
+		{"max", []*Item{{Name: "a", Level: "Error", Max: &one}}, []bool{false}},
+		{"none", []*Item{{Name: "a", Property: "Reset", Max: &zero}}, []bool{true}},
+		{"value", []*Item{{Name: "a", Value: "code=?"}}, []bool{true}},
+		{"after", []*Item{{Name: "a", Level: "Error"}, {Name: "b", Value: "*result=3", After: "a"}}, []bool{true, false}},
+		{"after later", []*Item{{Name: "b", Value: "*result=2", After: "a"}, {Name: "a", Level: "Error"}}, []bool{true, true}},
+		{"within", []*Item{{Name: "a", Value: "*result=3"}, {Name: "b", Level: "Error", After: "a", Within: &short}}, []bool{true, false}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &Checklist{Items: tt.items}
+			if err := c.check(); err != nil {
+				t.Errorf("Checklist.check() %s error = %v", tt.name, err)
+			}
+			run(c)
+			for i, item := range c.Items {
+				if ok, reason := item.result(); ok != tt.want[i] {
+					t.Errorf("Checklist.Event() %s item %s = %v (%s), want %v", tt.name, item.Name, ok, reason, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestChecklist_Report(t *testing.T) {
+	t.Parallel()
+
+	want := "   Checklist: Polling\n" +
+		"   ------------------\n\n" +
+		"Result Item          Details\n" +
+		"------ ----          -------\n" +
+		"PASS   First poll    event 0 at 0.00000400 s, count 5\n" +
+		"PASS   Poll result 3 event 3 at 0.00001600 s, count 1 after First poll within 2e-05 s\n" +
+		"FAIL   Failure       count 2, allowed 1\n" +
+		"PASS   Reset         not found\n" +
+		"\n3 of 4 items passed: FAILED\n"
+
+	c, err := Load("../../testdata/checklist.yaml")
+	if err != nil {
+		t.Errorf("Load() error = %v", err)
+		return
+	}
+	run(c)
+	var b bytes.Buffer
+	if err = c.Report(&b); err != nil {
+		t.Errorf("Checklist.Report() error = %v", err)
+	}
+	if b.String() != want {
+		t.Errorf("Checklist.Report() = %v, want %v", b.String(), want)
+	}
+	if c.Passed() {
+		t.Errorf("Checklist.Passed() = true, want false")
+	}
+	c.Items = c.Items[:2]
+	if !c.Passed() {
+		t.Errorf("Checklist.Passed() = false, want true")
+	}
+}
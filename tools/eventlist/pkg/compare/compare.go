@@ -0,0 +1,278 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compare
+
+import (
+	"eventlist/pkg/event"
+	"eventlist/pkg/output"
+	"eventlist/pkg/xml/scvd"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Thresholds are the allowed changes in percent of the baseline
+type Thresholds struct {
+	Count    float64 // change of an event count in both directions
+	Duration float64 // increase of the average or maximum duration of a start/stop pair
+}
+
+// DefaultThresholds allow a change of 10 percent
+var DefaultThresholds = Thresholds{Count: 10, Duration: 10}
+
+// durations of a start/stop pair
+type durations struct {
+	count    int
+	tot, max float64
+	open     bool // start event without stop event
+	start    float64
+}
+
+// Profile is the event sequence, the event counts and the start/stop
+// durations of a capture
+type Profile struct {
+	sequence []string // event names in recorded order
+	counts   map[string]int
+	pairs    map[string]*durations // per pair A(0) to D(15)
+}
+
+// New creates an empty profile
+func New() *Profile {
+	return &Profile{counts: make(map[string]int), pairs: make(map[string]*durations)}
+}
+
+// Read decodes a capture into a profile
+func Read(in io.Reader, evdefs map[uint16]scvd.Event, typedefs map[string]map[string]map[int16]string,
+	memberTypes map[string]map[string]string) (*Profile, error) {
+	p := New()
+	err := output.Decode(in, evdefs, typedefs, memberTypes, func(rec *output.EventRecord, ev *event.Data) error {
+		p.Add(rec, ev)
+		return nil
+	})
+	return p, err
+}
+
+// Add adds an event, the durations are measured like the start/stop
+// event statistic: a start event while started is ignored like a stop
+// event while stopped
+func (p *Profile) Add(rec *output.EventRecord, ev *event.Data) {
+	name := rec.Component + " " + rec.EventProperty
+	p.sequence = append(p.sequence, name)
+	p.counts[name]++
+	class, group, idx, start := ev.Info.SplitID()
+	if class != 0xEF {
+		return
+	}
+	pair := fmt.Sprintf("%c(%d)", 'A'+group, idx)
+	d := p.pairs[pair]
+	if d == nil {
+		d = &durations{}
+		p.pairs[pair] = d
+	}
+	switch {
+	case start && !d.open:
+		d.open, d.start = true, rec.Time
+	case !start && d.open:
+		diff := rec.Time - d.start
+		d.open = false
+		d.count++
+		d.tot += diff
+		if diff > d.max {
+			d.max = diff
+		}
+	}
+}
+
+// Change is the difference of one metric between baseline and current capture
+type Change struct {
+	Item       string // event name or start/stop pair
+	Metric     string // count, avg or max
+	Baseline   float64
+	Current    float64
+	Regression bool
+}
+
+// Percent returns the change relative to the baseline, +Inf if the baseline is 0
+func (c *Change) Percent() float64 {
+	if c.Baseline == 0 {
+		if c.Current == 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	return (c.Current - c.Baseline) / c.Baseline * 100
+}
+
+// Result is the comparison of two captures
+type Result struct {
+	Events    [2]int // number of events of baseline and current capture
+	Diverge   int    // index of the first different event, -1 if the sequences are equal
+	Names     [2]string
+	Changes   []Change // changed metrics sorted by item
+	Unchanged int
+}
+
+// Compare compares the current capture with the baseline
+func Compare(baseline *Profile, current *Profile, t Thresholds) *Result {
+	r := &Result{Events: [2]int{len(baseline.sequence), len(current.sequence)}, Diverge: -1}
+	for i := 0; i < len(baseline.sequence) || i < len(current.sequence); i++ {
+		if i >= len(baseline.sequence) || i >= len(current.sequence) || baseline.sequence[i] != current.sequence[i] {
+			r.Diverge = i
+			r.Names = [2]string{at(baseline.sequence, i), at(current.sequence, i)}
+			break
+		}
+	}
+
+	add := func(c Change, limit float64, increase bool) {
+		if c.Baseline == c.Current {
+			r.Unchanged++
+			return
+		}
+		p := c.Percent()
+		c.Regression = p > limit || (!increase && -p > limit)
+		r.Changes = append(r.Changes, c)
+	}
+	for _, name := range union(baseline.events(), current.events()) {
+		add(Change{Item: name, Metric: "count",
+			Baseline: float64(baseline.counts[name]), Current: float64(current.counts[name])}, t.Count, false)
+	}
+	for _, name := range union(baseline.pairNames(), current.pairNames()) {
+		b, c := baseline.pairs[name], current.pairs[name]
+		if b == nil {
+			b = &durations{}
+		}
+		if c == nil {
+			c = &durations{}
+		}
+		add(Change{Item: name, Metric: "count", Baseline: float64(b.count), Current: float64(c.count)}, t.Count, false)
+		add(Change{Item: name, Metric: "avg", Baseline: b.avg(), Current: c.avg()}, t.Duration, true)
+		add(Change{Item: name, Metric: "max", Baseline: b.max, Current: c.max}, t.Duration, true)
+	}
+	return r
+}
+
+func (d *durations) avg() float64 {
+	if d.count == 0 {
+		return 0
+	}
+	return d.tot / float64(d.count)
+}
+
+// at returns the name of event i, "end" after the last event
+func at(sequence []string, i int) string {
+	if i < len(sequence) {
+		return sequence[i]
+	}
+	return "end"
+}
+
+// union returns the sorted names of both lists without duplicates
+func union(a []string, b []string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, name := range append(a, b...) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// events returns the names of the events
+func (p *Profile) events() []string {
+	names := make([]string, 0, len(p.counts))
+	for name := range p.counts {
+		names = append(names, name)
+	}
+	return names
+}
+
+// pairNames returns the names of the start/stop pairs
+func (p *Profile) pairNames() []string {
+	names := make([]string, 0, len(p.pairs))
+	for name := range p.pairs {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Regressions returns the number of changes beyond the thresholds
+func (r *Result) Regressions() int {
+	n := 0
+	for _, c := range r.Changes {
+		if c.Regression {
+			n++
+		}
+	}
+	return n
+}
+
+// format a metric, durations in seconds
+func format(metric string, v float64) string {
+	if metric == "count" {
+		return fmt.Sprintf("%d", int(v))
+	}
+	return fmt.Sprintf("%.8f", v)
+}
+
+// Report writes the event sequence and the table of changed metrics
+func (r *Result) Report(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("   Comparison\n   ----------\n\n")
+	if r.Diverge < 0 {
+		fmt.Fprintf(&b, "Event sequence: identical, %d events\n\n", r.Events[0])
+	} else {
+		fmt.Fprintf(&b, "Event sequence: %d and %d events, diverge at event %d: %s / %s\n\n",
+			r.Events[0], r.Events[1], r.Diverge, r.Names[0], r.Names[1])
+	}
+	if len(r.Changes) != 0 {
+		itemSize := len("Item")
+		for _, c := range r.Changes {
+			if len(c.Item) > itemSize {
+				itemSize = len(c.Item)
+			}
+		}
+		fmt.Fprintf(&b, "%*s Metric Baseline    Current     Change   Result\n", -itemSize, "Item")
+		fmt.Fprintf(&b, "%*s ------ --------    -------     ------   ------\n", -itemSize, "----")
+		for _, c := range r.Changes {
+			result := "ok"
+			if c.Regression {
+				result = "REGRESSION"
+			}
+			change := "     new"
+			if p := c.Percent(); !math.IsInf(p, 1) {
+				change = fmt.Sprintf("%+7.1f%%", p)
+			}
+			fmt.Fprintf(&b, "%*s %-6s %-11s %-11s %s %s\n", -itemSize, c.Item, c.Metric,
+				format(c.Metric, c.Baseline), format(c.Metric, c.Current), change, result)
+		}
+		b.WriteString("\n")
+	}
+	verdict := "PASSED"
+	if r.Regressions() != 0 {
+		verdict = "FAILED"
+	}
+	fmt.Fprintf(&b, "%d changed, %d unchanged, %d regressions: %s\n", len(r.Changes), r.Unchanged, r.Regressions(), verdict)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compare
+
+import (
+	"bytes"
+	"eventlist/pkg/event"
+	"eventlist/pkg/output"
+	"os"
+	"testing"
+)
+
+// profile creates a profile of events, an event name A(n) or a(n) is the start or stop event of pair n
+func profile(events []string, times []float64) *Profile {
+	p := New()
+	for i, name := range events {
+		var ev event.Data
+		rec := output.EventRecord{Index: i, Time: times[i], Component: "App", EventProperty: name}
+		if name[0] == 'A' || name[0] == 'a' {
+			ev.Info.ID = 0xEF00 | uint16(name[2]-'0')
+			if name[0] == 'a' {
+				ev.Info.ID |= 0x20
+			}
+		}
+		p.Add(&rec, &ev)
+	}
+	return p
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	baseline := profile([]string{"Init", "A(0)", "a(0)", "Poll", "A(0)", "a(0)"}, []float64{0, 1, 2, 3, 4, 5})
+	tests := []struct {
+		name        string
+		events      []string
+		times       []float64
+		diverge     int
+		changes     int
+		regressions int
+	}{
+		{"same", []string{"Init", "A(0)", "a(0)", "Poll", "A(0)", "a(0)"}, []float64{0, 1, 2, 3, 4, 5}, -1, 0, 0},
+		{"faster", []string{"Init", "A(0)", "a(0)", "Poll", "A(0)", "a(0)"}, []float64{0, 1, 1.5, 3, 4, 4.5}, -1, 2, 0},
+		{"slower", []string{"Init", "A(0)", "a(0)", "Poll", "A(0)", "a(0)"}, []float64{0, 1, 2, 3, 4, 5.5}, -1, 2, 2},
+		{"slightly", []string{"Init", "A(0)", "a(0)", "Poll", "A(0)", "a(0)"}, []float64{0, 1, 2, 3, 4, 5.1}, -1, 2, 0},
+		{"missing", []string{"Init", "A(0)", "a(0)", "A(0)", "a(0)"}, []float64{0, 1, 2, 4, 5}, 3, 1, 1},
+		{"shorter", []string{"Init", "A(0)", "a(0)", "Poll"}, []float64{0, 1, 2, 3}, 4, 3, 3},
+		{"new", []string{"Init", "A(0)", "a(0)", "Poll", "A(0)", "a(0)", "Reset"}, []float64{0, 1, 2, 3, 4, 5, 6}, 6, 1, 1},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := Compare(baseline, profile(tt.events, tt.times), DefaultThresholds)
+			if r.Diverge != tt.diverge {
+				t.Errorf("Compare() %s diverge = %d, want %d", tt.name, r.Diverge, tt.diverge)
+			}
+			if len(r.Changes) != tt.changes {
+				t.Errorf("Compare() %s changes = %v, want %d", tt.name, r.Changes, tt.changes)
+			}
+			if got := r.Regressions(); got != tt.regressions {
+				t.Errorf("Compare() %s regressions = %d, want %d", tt.name, got, tt.regressions)
+			}
+		})
+	}
+}
+
+func TestResult_Report(t *testing.T) {
+	t.Parallel()
+
+	want := "   Comparison\n" +
+		"   ----------\n\n" +
+		"Event sequence: 6 and 7 events, diverge at event 6: end / App Reset\n\n" +
+		"Item      Metric Baseline    Current     Change   Result\n" +
+		"----      ------ --------    -------     ------   ------\n" +
+		"App Reset count  0           1                new REGRESSION\n" +
+		"A(0)      avg    1.00000000  0.75000000    -25.0% ok\n" +
+		"\n2 changed, 6 unchanged, 1 regressions: FAILED\n"
+
+	baseline := profile([]string{"Init", "A(0)", "a(0)", "Poll", "A(0)", "a(0)"}, []float64{0, 1, 2, 3, 4, 5})
+	current := profile([]string{"Init", "A(0)", "a(0)", "Poll", "A(0)", "a(0)", "Reset"}, []float64{0, 1, 2, 3, 4, 4.5, 6})
+	var b bytes.Buffer
+	if err := Compare(baseline, current, DefaultThresholds).Report(&b); err != nil {
+		t.Errorf("Result.Report() error = %v", err)
+	}
+	if b.String() != want {
+		t.Errorf("Result.Report() = %v, want %v", b.String(), want)
+	}
+}
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+
+	file, err := os.Open("../../testdata/test10.binary")
+	if err != nil {
+		t.Errorf("Read() error = %v", err)
+		return
+	}
+	defer file.Close()
+	p, err := Read(file, nil, nil, nil)
+	if err != nil {
+		t.Errorf("Read() error = %v", err)
+	}
+	if len(p.sequence) != 2 {
+		t.Errorf("Read() events = %d, want 2", len(p.sequence))
+	}
+	r := Compare(p, p, DefaultThresholds)
+	if r.Diverge != -1 || len(r.Changes) != 0 {
+		t.Errorf("Compare() self = %d %v, want -1 []", r.Diverge, r.Changes)
+	}
+}
@@ -0,0 +1,211 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var errClock = errors.New("invalid clock in config file")
+
+// Name is the file name of the config file in the working directory or the home directory
+const Name = ".eventlist.yaml"
+
+// Config holds the defaults of the command line options, the options
+// given on the command line take precedence
+type Config struct {
+	SCVD    []string `yaml:"scvd"`    // SCVD files or directories searched for *.scvd files
+	ELF     string   `yaml:"elf"`     // elf/axf file
+	Clock   float64  `yaml:"clock"`   // timestamp frequency in Hz
+	Format  string   `yaml:"format"`  // output format: txt, json, xml or html
+	Level   string   `yaml:"level"`   // level of the printed events
+	Where   []string `yaml:"where"`   // filter expressions of the printed events
+	Profile string   `yaml:"profile"` // decode profile
+	Columns string   `yaml:"columns"` // columns of the event list
+
+	Files []string `yaml:"-"` // the read config files
+}
+
+// Load reads a config file, relative paths are relative to the directory of the file
+func Load(filename string) (*Config, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var c Config
+	if err = yaml.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	if c.Clock < 0 {
+		return nil, fmt.Errorf("%w: %s: %g", errClock, filename, c.Clock)
+	}
+	dir := filepath.Dir(filename)
+	for i, name := range c.SCVD {
+		c.SCVD[i] = resolve(dir, name)
+	}
+	if c.ELF != "" {
+		c.ELF = resolve(dir, c.ELF)
+	}
+	c.Files = []string{filename}
+	return &c, nil
+}
+
+func resolve(dir string, name string) string {
+	if strings.HasPrefix(name, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, name[2:])
+		}
+	}
+	if filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(dir, name)
+}
+
+// Merge sets the values of other that are not empty
+func (c *Config) Merge(other *Config) {
+	if len(other.SCVD) != 0 {
+		c.SCVD = other.SCVD
+	}
+	if other.ELF != "" {
+		c.ELF = other.ELF
+	}
+	if other.Clock != 0 {
+		c.Clock = other.Clock
+	}
+	if other.Format != "" {
+		c.Format = other.Format
+	}
+	if other.Level != "" {
+		c.Level = other.Level
+	}
+	if len(other.Where) != 0 {
+		c.Where = other.Where
+	}
+	if other.Profile != "" {
+		c.Profile = other.Profile
+	}
+	if other.Columns != "" {
+		c.Columns = other.Columns
+	}
+	c.Files = append(c.Files, other.Files...)
+}
+
+// Find reads the config files of the home directory and of the working
+// directory, the values of the working directory take precedence
+func Find() (*Config, error) {
+	var dirs []string
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, home)
+	}
+	if wd, err := os.Getwd(); err == nil {
+		dirs = append(dirs, wd)
+	}
+	return find(dirs)
+}
+
+func find(dirs []string) (*Config, error) {
+	c := &Config{}
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		filename := filepath.Join(dir, Name)
+		if seen[filename] {
+			continue // the working directory is the home directory
+		}
+		seen[filename] = true
+		other, err := Load(filename)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		c.Merge(other)
+	}
+	return c, nil
+}
+
+// SCVDFiles returns the SCVD files, the files *.scvd of directories in sorted order
+func (c *Config) SCVDFiles() ([]string, error) {
+	var files []string
+	for _, name := range c.SCVD {
+		info, err := os.Stat(name)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, name)
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(name, "*.scvd"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(matches)
+		files = append(files, matches...)
+	}
+	return files, nil
+}
+
+// EnvPrefix is the prefix of the environment variables setting the defaults
+const EnvPrefix = "EVENTLIST_"
+
+// Env reads the defaults of the environment variables EVENTLIST_SCVD_PATH
+// (a list of SCVD files and directories separated like PATH), EVENTLIST_ELF,
+// EVENTLIST_CLOCK, EVENTLIST_FORMAT, EVENTLIST_LEVEL, EVENTLIST_WHERE
+// (filter expressions separated by ";"), EVENTLIST_PROFILE and
+// EVENTLIST_COLUMNS, lookup is os.LookupEnv
+func Env(lookup func(string) (string, bool)) (*Config, error) {
+	get := func(name string) string {
+		value, _ := lookup(EnvPrefix + name)
+		return strings.TrimSpace(value)
+	}
+	c := &Config{
+		ELF:     get("ELF"),
+		Format:  get("FORMAT"),
+		Level:   get("LEVEL"),
+		Profile: get("PROFILE"),
+		Columns: get("COLUMNS"),
+	}
+	for _, name := range filepath.SplitList(get("SCVD_PATH")) {
+		if name != "" {
+			c.SCVD = append(c.SCVD, name)
+		}
+	}
+	for _, expr := range strings.Split(get("WHERE"), ";") {
+		if expr = strings.TrimSpace(expr); expr != "" {
+			c.Where = append(c.Where, expr)
+		}
+	}
+	if clock := get("CLOCK"); clock != "" {
+		var err error
+		if c.Clock, err = strconv.ParseFloat(clock, 64); err != nil || c.Clock < 0 {
+			return nil, fmt.Errorf("%w: %sCLOCK: %s", errClock, EnvPrefix, clock)
+		}
+	}
+	return c, nil
+}
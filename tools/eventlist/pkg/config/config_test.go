@@ -0,0 +1,219 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	badClock := filepath.Join(dir, "clock.yaml")
+	_ = os.WriteFile(badClock, []byte("clock: -1\n"), 0600)
+	badYAML := filepath.Join(dir, "yaml.yaml")
+	_ = os.WriteFile(badYAML, []byte("scvd: test.xml\n"), 0600)
+	abs := filepath.Join(dir, "abs.yaml")
+	_ = os.WriteFile(abs, []byte("scvd: [/scvd/RTX5.scvd]\nelf: ../app.axf\nclock: 1e6\n"), 0600)
+
+	tests := []struct {
+		name     string
+		filename string
+		want     *Config
+		wantErr  error
+	}{
+		{"ok", "../../testdata/config.yaml", &Config{
+			SCVD:   []string{filepath.Join("../../testdata", "test.xml")},
+			Format: "json",
+			Level:  "Op",
+			Files:  []string{"../../testdata/config.yaml"},
+		}, nil},
+		{"paths", abs, &Config{
+			SCVD:  []string{"/scvd/RTX5.scvd"},
+			ELF:   filepath.Join(filepath.Dir(dir), "app.axf"),
+			Clock: 1e6,
+			Files: []string{abs},
+		}, nil},
+		{"clock", badClock, nil, errClock},
+		{"yaml", badYAML, nil, nil},
+		{"nix", "../../testdata/nix.yaml", nil, os.ErrNotExist},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Load(tt.filename)
+			if tt.want == nil && err == nil {
+				t.Errorf("Load() %s error = nil, want error", tt.name)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Load() %s error = %v, want %v", tt.name, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Load() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_find(t *testing.T) {
+	t.Parallel()
+
+	home := t.TempDir()
+	project := t.TempDir()
+	empty := t.TempDir()
+	bad := t.TempDir()
+	_ = os.WriteFile(filepath.Join(home, Name), []byte("format: xml\nlevel: Error\nclock: 48e6\n"), 0600)
+	_ = os.WriteFile(filepath.Join(project, Name), []byte("format: json\nwhere: [component=RTX*]\n"), 0600)
+	_ = os.WriteFile(filepath.Join(bad, Name), []byte("clock: -1\n"), 0600)
+
+	tests := []struct {
+		name    string
+		dirs    []string
+		want    *Config
+		wantErr bool
+	}{
+		{"none", []string{empty}, &Config{}, false},
+		{"home", []string{home, empty}, &Config{Format: "xml", Level: "Error", Clock: 48e6,
+			Files: []string{filepath.Join(home, Name)}}, false},
+		{"both", []string{home, project}, &Config{Format: "json", Level: "Error", Clock: 48e6,
+			Where: []string{"component=RTX*"}, Files: []string{filepath.Join(home, Name), filepath.Join(project, Name)}}, false},
+		{"same", []string{project, project}, &Config{Format: "json", Where: []string{"component=RTX*"},
+			Files: []string{filepath.Join(project, Name)}}, false},
+		{"bad", []string{home, bad}, nil, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := find(tt.dirs)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("find() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("find() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_SCVDFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, name := range []string{"b.scvd", "a.scvd", "c.xml"} {
+		_ = os.WriteFile(filepath.Join(dir, name), nil, 0600)
+	}
+	tests := []struct {
+		name    string
+		scvd    []string
+		want    []string
+		wantErr bool
+	}{
+		{"file", []string{"../../testdata/test.xml"}, []string{"../../testdata/test.xml"}, false},
+		{"dir", []string{dir, "../../testdata/rtx.xml"},
+			[]string{filepath.Join(dir, "a.scvd"), filepath.Join(dir, "b.scvd"), "../../testdata/rtx.xml"}, false},
+		{"nix", []string{"../../testdata/nix.scvd"}, nil, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := Config{SCVD: tt.scvd}
+			got, err := c.SCVDFiles()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Config.SCVDFiles() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Config.SCVDFiles() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnv(t *testing.T) {
+	t.Parallel()
+
+	sep := string(filepath.ListSeparator)
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    *Config
+		wantErr error
+	}{
+		{"none", nil, &Config{}, nil},
+		{"all", map[string]string{
+			"EVENTLIST_SCVD_PATH": "scvd" + sep + sep + "RTX5.scvd",
+			"EVENTLIST_ELF":       " app.axf ",
+			"EVENTLIST_CLOCK":     "168e6",
+			"EVENTLIST_FORMAT":    "json",
+			"EVENTLIST_LEVEL":     "Error",
+			"EVENTLIST_WHERE":     "component=RTX*,level=Op; id=0xEF00-0xEFFF;",
+			"EVENTLIST_PROFILE":   "cm4-le-dwt",
+			"EVENTLIST_COLUMNS":   "index,message",
+		}, &Config{
+			SCVD:    []string{"scvd", "RTX5.scvd"},
+			ELF:     "app.axf",
+			Clock:   168e6,
+			Format:  "json",
+			Level:   "Error",
+			Where:   []string{"component=RTX*,level=Op", "id=0xEF00-0xEFFF"},
+			Profile: "cm4-le-dwt",
+			Columns: "index,message",
+		}, nil},
+		{"clock", map[string]string{"EVENTLIST_CLOCK": "fast"}, nil, errClock},
+		{"negative clock", map[string]string{"EVENTLIST_CLOCK": "-1"}, nil, errClock},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Env(func(name string) (string, bool) {
+				value, ok := tt.env[name]
+				return value, ok
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Env() %s error = %v, want %v", tt.name, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Env() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_Merge(t *testing.T) {
+	t.Parallel()
+
+	c := &Config{SCVD: []string{"a.scvd"}, Format: "xml", Level: "Op", Files: []string{"a.yaml"}}
+	c.Merge(&Config{Format: "json", Clock: 1e6})
+	want := &Config{SCVD: []string{"a.scvd"}, Format: "json", Level: "Op", Clock: 1e6, Files: []string{"a.yaml"}}
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("Config.Merge() = %v, want %v", c, want)
+	}
+}
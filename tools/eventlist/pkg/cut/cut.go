@@ -0,0 +1,133 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cut
+
+import (
+	"bufio"
+	"errors"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/where"
+	"fmt"
+	"io"
+	"math"
+)
+
+var errSession = errors.New("invalid session")
+
+// Options select the events written to the cut capture, an event must
+// pass all of them
+type Options struct {
+	From   float64       // begin of the time range in s
+	To     float64       // end of the time range in s, +Inf for the end of the capture
+	Filter *where.Filter // nil selects all events
+	// Session selects the events from a start event of a start/stop pair
+	// to its stop event, e.g. "A(0)" all sessions of pair A(0), "A(0):3" the
+	// third session only
+	Session string
+}
+
+// session is a parsed Options.Session
+type session struct {
+	id    uint16 // ID of the start event
+	index int    // number of the selected session starting at 1, 0 for all
+}
+
+func parseSession(s string) (*session, error) {
+	var group byte
+	var idx, index int
+	n, _ := fmt.Sscanf(s, "%c(%d):%d", &group, &idx, &index)
+	if n < 2 || group < 'A' || group > 'D' || idx < 0 || idx > 15 || index < 0 ||
+		(n == 2 && s != fmt.Sprintf("%c(%d)", group, idx)) ||
+		(n == 3 && (index == 0 || s != fmt.Sprintf("%c(%d):%d", group, idx, index))) {
+		return nil, fmt.Errorf("%w: %s", errSession, s)
+	}
+	return &session{id: 0xEF00 | uint16(group-'A')<<6 | uint16(idx), index: index}, nil
+}
+
+// Cutter writes the records of the selected events and of all clock
+// events, the clock events keep the timestamps decodable. The records are
+// written unchanged, so the events keep their times.
+type Cutter struct {
+	opt     Options
+	session *session
+	out     *bufio.Writer
+	buf     []byte
+	started int  // number of started sessions
+	open    bool // within a selected session
+	Written int  // number of written records
+	Dropped int  // number of records not selected
+}
+
+// New creates a cutter writing to w
+func New(w io.Writer, opt Options) (*Cutter, error) {
+	c := &Cutter{opt: opt, out: bufio.NewWriter(w)}
+	if opt.Session != "" {
+		var err error
+		if c.session, err = parseSession(opt.Session); err != nil {
+			return nil, err
+		}
+	}
+	if math.IsNaN(opt.To) || opt.To < opt.From {
+		return nil, fmt.Errorf("invalid time range %g to %g", opt.From, opt.To)
+	}
+	return c, nil
+}
+
+// inSession returns if the event belongs to a selected session, the
+// start and the stop event belong to it
+func (c *Cutter) inSession(id uint16) bool {
+	if c.session == nil {
+		return true
+	}
+	switch id {
+	case c.session.id:
+		if !c.open {
+			c.started++
+			c.open = c.session.index == 0 || c.started == c.session.index
+			return c.open
+		}
+	case c.session.id | 0x20:
+		if c.open {
+			c.open = false
+			return true
+		}
+	}
+	return c.open
+}
+
+// Event writes the record of the event if it is selected
+func (c *Cutter) Event(ev *bus.Event) error {
+	id := ev.Data.Info.ID
+	selected := c.inSession(id) && ev.Time >= c.opt.From && ev.Time <= c.opt.To && c.opt.Filter.Match(ev)
+	if id != 0xFF00 && id != 0xFF03 && !selected {
+		c.Dropped++
+		return nil
+	}
+	c.buf = ev.Data.AppendRecord(c.buf[:0])
+	if _, err := c.out.Write(c.buf); err != nil {
+		return err
+	}
+	c.Written++
+	return nil
+}
+
+// End flushes the written records
+func (c *Cutter) End() error {
+	return c.out.Flush()
+}
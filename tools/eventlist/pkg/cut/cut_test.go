@@ -0,0 +1,130 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cut
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/eval"
+	"eventlist/pkg/event"
+	"eventlist/pkg/where"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// events of the tests: clock, A(0) start/stop twice with an event of component 0x10
+// between them, and an event of component 0x20
+var ids = []uint16{0xFF03, 0xEF00, 0x1001, 0xEF20, 0x2001, 0xEF00, 0x1001, 0xEF20}
+
+// cut runs the events through a cutter and returns the IDs of the written records
+func cut(t *testing.T, opt Options) []uint16 {
+	var b bytes.Buffer
+	c, err := New(&b, opt)
+	if err != nil {
+		t.Errorf("New() error = %v", err)
+		return nil
+	}
+	for i, id := range ids {
+		data := event.Data{Typ: 2, Time: uint64(i)}
+		data.Info.ID = id
+		if err = c.Event(bus.NewEvent(i, float64(i), &data, nil, nil)); err != nil {
+			t.Errorf("Cutter.Event() error = %v", err)
+		}
+	}
+	if err = c.End(); err != nil {
+		t.Errorf("Cutter.End() error = %v", err)
+	}
+	if c.Written+c.Dropped != len(ids) {
+		t.Errorf("Cutter.Written %d + Dropped %d, want %d", c.Written, c.Dropped, len(ids))
+	}
+	var got []uint16
+	rd := bufio.NewReader(&b)
+	for {
+		var data event.Data
+		if err = data.Read(rd); err != nil {
+			if !errors.Is(err, eval.ErrEof) {
+				t.Errorf("Read() error = %v", err)
+			}
+			return got
+		}
+		got = append(got, data.Info.ID)
+	}
+}
+
+func TestCutter_Event(t *testing.T) {
+	t.Parallel()
+
+	component, _ := where.Parse([]string{"id=0x1000-0x10FF"})
+	tests := []struct {
+		name string
+		opt  Options
+		want []uint16
+	}{
+		{"all", Options{To: math.Inf(1)}, ids},
+		{"range", Options{From: 2, To: 4}, []uint16{0xFF03, 0x1001, 0xEF20, 0x2001}},
+		{"filter", Options{To: math.Inf(1), Filter: component}, []uint16{0xFF03, 0x1001, 0x1001}},
+		{"sessions", Options{To: math.Inf(1), Session: "A(0)"}, []uint16{0xFF03, 0xEF00, 0x1001, 0xEF20, 0xEF00, 0x1001, 0xEF20}},
+		{"session", Options{To: math.Inf(1), Session: "A(0):2"}, []uint16{0xFF03, 0xEF00, 0x1001, 0xEF20}},
+		{"session filter", Options{To: math.Inf(1), Session: "A(0):1", Filter: component}, []uint16{0xFF03, 0x1001}},
+		{"other session", Options{To: math.Inf(1), Session: "B(0)"}, []uint16{0xFF03}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := cut(t, tt.opt); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Cutter.Event() %s = %x, want %x", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		opt     Options
+		wantErr bool
+	}{
+		{"ok", Options{To: 1}, false},
+		{"session", Options{To: 1, Session: "D(15):7"}, false},
+		{"range", Options{From: 2, To: 1}, true},
+		{"NaN", Options{To: math.NaN()}, true},
+		{"group", Options{To: 1, Session: "E(0)"}, true},
+		{"index", Options{To: 1, Session: "A(16)"}, true},
+		{"occurrence", Options{To: 1, Session: "A(1):0"}, true},
+		{"suffix", Options{To: 1, Session: "A(1)x"}, true},
+		{"syntax", Options{To: 1, Session: "A1"}, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := New(&bytes.Buffer{}, tt.opt); (err != nil) != tt.wantErr {
+				t.Errorf("New() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,434 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dashboard
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+var errPanel = errors.New("invalid dashboard panel")
+
+// Event is one event record shown in the report
+type Event struct {
+	Index      int
+	Time       float64
+	Level      string
+	Component  string
+	Property   string
+	Value      string
+	Marker     bool   // listed by the markers panel and linked from it
+	Annotation string // note of a reviewer shown above the event in the tables
+}
+
+// Statistic is one line of the start/stop event statistic
+type Statistic struct {
+	Event string
+	Count int
+	Total string
+	Min   string
+	Max   string
+	Avg   string
+}
+
+// Query selects the events of a panel, empty fields match all events.
+// Component and Property can contain shell patterns like "Net*".
+type Query struct {
+	Component string   `yaml:"component"`
+	Property  string   `yaml:"property"`
+	Level     string   `yaml:"level"`
+	From      *float64 `yaml:"from"`
+	To        *float64 `yaml:"to"`
+	Limit     int      `yaml:"limit"`
+}
+
+type Panel struct {
+	Type    string   `yaml:"type"` // timeline, chart, table, statistics or markers
+	Title   string   `yaml:"title"`
+	Query   Query    `yaml:"query"`
+	By      string   `yaml:"by"`      // chart: component, property or level
+	Columns []string `yaml:"columns"` // table: index, time, level, component, property, value
+}
+
+type Spec struct {
+	Title  string  `yaml:"title"`
+	Panels []Panel `yaml:"panels"`
+}
+
+// Default is the dashboard used when no specification is given
+var Default = Spec{
+	Title: "Event Recorder report",
+	Panels: []Panel{
+		{Type: "timeline", Title: "Timeline"},
+		{Type: "chart", Title: "Events per component", By: "component"},
+		{Type: "markers", Title: "Markers"},
+		{Type: "table", Title: "Errors", Query: Query{Level: "Error"}},
+		{Type: "statistics", Title: "Start/Stop event statistic"},
+		{Type: "table", Title: "Events", Query: Query{Limit: 1000}},
+	},
+}
+
+var columns = map[string]string{
+	"index":     "Index",
+	"time":      "Time (s)",
+	"level":     "Level",
+	"component": "Component",
+	"property":  "Event Property",
+	"value":     "Value",
+}
+
+// Load reads a dashboard specification from a YAML file
+func Load(filename string) (*Spec, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var spec Spec
+	if err = yaml.Unmarshal(data, &spec); err != nil {
+		return nil, err
+	}
+	if err = spec.check(); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+func (spec *Spec) check() error {
+	for _, p := range spec.Panels {
+		switch p.Type {
+		case "timeline", "statistics", "markers":
+		case "chart":
+			switch p.By {
+			case "", "component", "property", "level":
+			default:
+				return fmt.Errorf("%w: %s: by %s", errPanel, p.Title, p.By)
+			}
+		case "table":
+			for _, c := range p.Columns {
+				if _, ok := columns[c]; !ok {
+					return fmt.Errorf("%w: %s: column %s", errPanel, p.Title, c)
+				}
+			}
+		default:
+			return fmt.Errorf("%w: %s: type %s", errPanel, p.Title, p.Type)
+		}
+	}
+	return nil
+}
+
+func match(pattern string, s string) bool {
+	if pattern == "" {
+		return true
+	}
+	ok, err := path.Match(pattern, s)
+	return ok && err == nil
+}
+
+// Match reports if the event is selected by the query
+func (q *Query) Match(ev *Event) bool {
+	return match(q.Component, ev.Component) &&
+		match(q.Property, ev.Property) &&
+		(q.Level == "" || q.Level == ev.Level) &&
+		(q.From == nil || ev.Time >= *q.From) &&
+		(q.To == nil || ev.Time <= *q.To)
+}
+
+// Select returns the events matching the query up to its limit
+func (q *Query) Select(events []Event) []Event {
+	var sel []Event
+	for i := range events {
+		if q.Match(&events[i]) {
+			if q.Limit > 0 && len(sel) >= q.Limit {
+				break
+			}
+			sel = append(sel, events[i])
+		}
+	}
+	return sel
+}
+
+const (
+	svgWidth   = 1000
+	labelWidth = 150
+	rowHeight  = 20
+)
+
+type mark struct {
+	X     int
+	Level string
+}
+
+type row struct {
+	Name  string
+	Y     int
+	Marks []mark
+}
+
+type bar struct {
+	Name  string
+	Count int
+	Width int
+	Y     int
+}
+
+// link of a marker to its row in a table
+type link struct {
+	Anchor string
+	Text   string
+}
+
+type view struct {
+	Title   string
+	Type    string
+	Height  int
+	Rows    []row
+	Bars    []bar
+	Headers []string
+	Cells   [][]string
+	Anchors []string // anchors of the rows of marker events
+	Notes   []string // annotations of the rows
+	Links   []link
+	Stats   []Statistic
+	Count   int
+}
+
+// anchor of the row of a marker event
+func anchor(ev *Event) string {
+	return fmt.Sprintf("event-%d", ev.Index)
+}
+
+// build the timeline rows, one mark per pixel and level
+func timeline(events []Event) []row {
+	if len(events) == 0 {
+		return nil
+	}
+	t0, t1 := events[0].Time, events[0].Time
+	for _, ev := range events {
+		if ev.Time < t0 {
+			t0 = ev.Time
+		}
+		if ev.Time > t1 {
+			t1 = ev.Time
+		}
+	}
+	span := t1 - t0
+	if span <= 0 {
+		span = 1
+	}
+	var rows []row
+	idx := make(map[string]int)
+	seen := make(map[string]bool)
+	for _, ev := range events {
+		i, ok := idx[ev.Component]
+		if !ok {
+			i = len(rows)
+			idx[ev.Component] = i
+			rows = append(rows, row{Name: ev.Component, Y: i * rowHeight})
+		}
+		x := labelWidth + int((ev.Time-t0)/span*float64(svgWidth-labelWidth-10))
+		key := fmt.Sprintf("%d/%d/%s", i, x, ev.Level)
+		if !seen[key] {
+			seen[key] = true
+			rows[i].Marks = append(rows[i].Marks, mark{x, ev.Level})
+		}
+	}
+	return rows
+}
+
+// build the bars of a chart, largest first
+func chart(events []Event, by string) []bar {
+	counts := make(map[string]int)
+	for _, ev := range events {
+		switch by {
+		case "property":
+			counts[ev.Property]++
+		case "level":
+			counts[ev.Level]++
+		default:
+			counts[ev.Component]++
+		}
+	}
+	var bars []bar
+	max := 0
+	for name, count := range counts {
+		bars = append(bars, bar{Name: name, Count: count})
+		if count > max {
+			max = count
+		}
+	}
+	sort.Slice(bars, func(i, j int) bool {
+		if bars[i].Count != bars[j].Count {
+			return bars[i].Count > bars[j].Count
+		}
+		return bars[i].Name < bars[j].Name
+	})
+	for i := range bars {
+		bars[i].Width = bars[i].Count * (svgWidth - labelWidth - 80) / max
+		bars[i].Y = i * rowHeight
+	}
+	return bars
+}
+
+func cell(ev *Event, column string) string {
+	switch column {
+	case "index":
+		return fmt.Sprintf("%d", ev.Index)
+	case "time":
+		return fmt.Sprintf("%.8f", ev.Time)
+	case "level":
+		return ev.Level
+	case "component":
+		return ev.Component
+	case "property":
+		return ev.Property
+	}
+	return ev.Value
+}
+
+// Render writes the HTML report of the events as defined by the dashboard,
+// the first table row of a marker event is the target of its link in the
+// markers panel, a markers panel without markers is left out
+func (spec *Spec) Render(w io.Writer, events []Event, stats []Statistic) error {
+	views := make([]view, 0, len(spec.Panels))
+	anchored := make(map[int]bool)
+	for _, p := range spec.Panels {
+		sel := p.Query.Select(events)
+		v := view{Title: p.Title, Type: p.Type, Count: len(sel)}
+		switch p.Type {
+		case "timeline":
+			v.Rows = timeline(sel)
+			v.Height = len(v.Rows)*rowHeight + 10
+		case "chart":
+			v.Bars = chart(sel, p.By)
+			v.Height = len(v.Bars)*rowHeight + 10
+		case "table":
+			cols := p.Columns
+			if len(cols) == 0 {
+				cols = []string{"index", "time", "component", "property", "value"}
+			}
+			for _, c := range cols {
+				v.Headers = append(v.Headers, columns[c])
+			}
+			for i := range sel {
+				var cells []string
+				for _, c := range cols {
+					cells = append(cells, cell(&sel[i], c))
+				}
+				v.Cells = append(v.Cells, cells)
+				id := ""
+				if sel[i].Marker && !anchored[sel[i].Index] {
+					anchored[sel[i].Index] = true
+					id = anchor(&sel[i])
+				}
+				v.Anchors = append(v.Anchors, id)
+				v.Notes = append(v.Notes, sel[i].Annotation)
+			}
+		case "statistics":
+			v.Stats = stats
+		case "markers":
+			for i := range sel {
+				if sel[i].Marker {
+					v.Links = append(v.Links, link{anchor(&sel[i]), fmt.Sprintf("%d %.8f s %s %s %s",
+						sel[i].Index, sel[i].Time, sel[i].Component, sel[i].Property, sel[i].Value)})
+				}
+			}
+			if len(v.Links) == 0 {
+				continue
+			}
+		}
+		views = append(views, v)
+	}
+	return page.Execute(w, struct {
+		Title string
+		Views []view
+	}{spec.Title, views})
+}
+
+var page = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 20px; }
+table { border-collapse: collapse; font-size: 13px; }
+th, td { border: 1px solid #ccc; padding: 2px 6px; text-align: left; }
+th { background: #eee; }
+tr.note td { background: #ffd; font-style: italic; }
+svg text { font-size: 12px; }
+.Error { fill: #d00; } .API { fill: #06c; } .Op { fill: #080; } .Detail { fill: #888; } .none { fill: #000; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{- range .Views}}
+<h2>{{.Title}}</h2>
+{{- if eq .Type "timeline"}}
+<svg width="1000" height="{{.Height}}">
+{{- range .Rows}}{{$y := .Y}}
+<text x="0" y="{{$y}}" dy="14">{{.Name}}</text>
+{{- range .Marks}}
+<rect x="{{.X}}" y="{{$y}}" width="2" height="16" class="{{if .Level}}{{.Level}}{{else}}none{{end}}"/>
+{{- end}}
+{{- end}}
+</svg>
+{{- else if eq .Type "chart"}}
+<svg width="1000" height="{{.Height}}">
+{{- range .Bars}}
+<text x="0" y="{{.Y}}" dy="14">{{.Name}}</text>
+<rect x="150" y="{{.Y}}" width="{{.Width}}" height="16" class="API"/>
+<text x="{{.Width}}" y="{{.Y}}" dx="155" dy="14">{{.Count}}</text>
+{{- end}}
+</svg>
+{{- else if eq .Type "table"}}{{$anchors := .Anchors}}{{$notes := .Notes}}{{$span := len .Headers}}
+<p>{{.Count}} events</p>
+<table>
+<tr>{{range .Headers}}<th>{{.}}</th>{{end}}</tr>
+{{- range $i, $cells := .Cells}}{{$id := index $anchors $i}}{{$note := index $notes $i}}
+{{- if $note}}
+<tr class="note"><td colspan="{{$span}}">{{$note}}</td></tr>
+{{- end}}
+<tr{{if $id}} id="{{$id}}"{{end}}>{{range $cells}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+</table>
+{{- else if eq .Type "markers"}}
+<ul>
+{{- range .Links}}
+<li><a href="#{{.Anchor}}">{{.Text}}</a></li>
+{{- end}}
+</ul>
+{{- else if eq .Type "statistics"}}
+<table>
+<tr><th>Event</th><th>Count</th><th>Total</th><th>Min</th><th>Max</th><th>Average</th></tr>
+{{- range .Stats}}
+<tr><td>{{.Event}}</td><td>{{.Count}}</td><td>{{.Total}}</td><td>{{.Min}}</td><td>{{.Max}}</td><td>{{.Avg}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
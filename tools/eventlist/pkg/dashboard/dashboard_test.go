@@ -0,0 +1,233 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dashboard
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var events = []Event{
+	{0, 0.5, "API", "Net", "Open", "sock=1", false, ""},
+	{1, 1.0, "Error", "Net", "Fail", "err=-1", false, ""},
+	{2, 1.5, "Op", "FS", "Read", "len=<16>", true, ""},
+	{3, 2.5, "", "0xFF", "0xFF03", "val1=0x00000004", false, "reset <button>"},
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		file    string
+		panels  int
+		wantErr bool
+	}{
+		{"ok", "../../testdata/dashboard.yaml", 4, false},
+		{"type", "../../testdata/dashboard_err.yaml", 0, true},
+		{"yaml", "../../testdata/test.xml", 0, true},
+		{"nix", "../../testdata/nix.yaml", 0, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Load(tt.file)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Load() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+				return
+			}
+			if got != nil && len(got.Panels) != tt.panels {
+				t.Errorf("Load() %s = %v, want %v panels", tt.name, got.Panels, tt.panels)
+			}
+		})
+	}
+}
+
+func TestSpec_check(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		panel   Panel
+		wantErr bool
+	}{
+		{"timeline", Panel{Type: "timeline"}, false},
+		{"chart", Panel{Type: "chart", By: "level"}, false},
+		{"chart by", Panel{Type: "chart", By: "value"}, true},
+		{"table", Panel{Type: "table", Columns: []string{"index", "value"}}, false},
+		{"table column", Panel{Type: "table", Columns: []string{"id"}}, true},
+		{"statistics", Panel{Type: "statistics"}, false},
+		{"unknown", Panel{Type: "pie"}, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			spec := Spec{Panels: []Panel{tt.panel}}
+			if err := spec.check(); (err != nil) != tt.wantErr {
+				t.Errorf("Spec.check() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQuery_Select(t *testing.T) {
+	t.Parallel()
+
+	from := 1.0
+	to := 2.0
+	tests := []struct {
+		name  string
+		query Query
+		want  []int
+	}{
+		{"all", Query{}, []int{0, 1, 2, 3}},
+		{"component", Query{Component: "Net"}, []int{0, 1}},
+		{"pattern", Query{Component: "0x*"}, []int{3}},
+		{"property", Query{Property: "Read"}, []int{2}},
+		{"level", Query{Level: "Error"}, []int{1}},
+		{"from", Query{From: &from}, []int{1, 2, 3}},
+		{"from to", Query{From: &from, To: &to}, []int{1, 2}},
+		{"limit", Query{Limit: 2}, []int{0, 1}},
+		{"none", Query{Component: "USB"}, nil},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []int
+			for _, ev := range tt.query.Select(events) {
+				got = append(got, ev.Index)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Query.Select() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_chart(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		by   string
+		want []bar
+	}{
+		{"component", "", []bar{{"Net", 2, 770, 0}, {"0xFF", 1, 385, 20}, {"FS", 1, 385, 40}}},
+		{"level", "level", []bar{{"", 1, 770, 0}, {"API", 1, 770, 20}, {"Error", 1, 770, 40}, {"Op", 1, 770, 60}}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := chart(events, tt.by); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("chart() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_timeline(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		events []Event
+		want   []row
+	}{
+		{"empty", nil, nil},
+		{"events", events, []row{
+			{"Net", 0, []mark{{150, "API"}, {360, "Error"}}},
+			{"FS", 20, []mark{{570, "Op"}}},
+			{"0xFF", 40, []mark{{990, ""}}},
+		}},
+		{"one", events[:1], []row{{"Net", 0, []mark{{150, "API"}}}}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := timeline(tt.events); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("timeline() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpec_Render(t *testing.T) {
+	t.Parallel()
+
+	stats := []Statistic{{"A(0)", 2, "1.000s", "0.5s", "0.5s", "0.5s"}}
+	spec := Spec{
+		Title: "<Report>",
+		Panels: []Panel{
+			{Type: "table", Title: "Errors", Query: Query{Level: "Error"}, Columns: []string{"time", "level", "value"}},
+			{Type: "statistics", Title: "Stat"},
+			{Type: "chart", Title: "Chart"},
+			{Type: "timeline", Title: "Timeline"},
+		},
+	}
+	tests := []struct {
+		name string
+		spec *Spec
+		want []string
+	}{
+		{"spec", &spec, []string{
+			"<title>&lt;Report&gt;</title>",
+			"<tr><th>Time (s)</th><th>Level</th><th>Value</th></tr>",
+			"<tr><td>1.00000000</td><td>Error</td><td>err=-1</td></tr>",
+			"<tr><td>A(0)</td><td>2</td><td>1.000s</td>",
+			"<rect x=\"150\" y=\"0\" width=\"770\" height=\"16\" class=\"API\"/>",
+			"<rect x=\"360\" y=\"0\" width=\"2\" height=\"16\" class=\"Error\"/>",
+		}},
+		{"default", &Default, []string{
+			"<h2>Timeline</h2>",
+			"<p>1 events</p>",
+			"<td>len=&lt;16&gt;</td>",
+			"<h2>Markers</h2>\n<ul>\n<li><a href=\"#event-2\">2 1.50000000 s FS Read len=&lt;16&gt;</a></li>\n</ul>",
+			"<tr id=\"event-2\"><td>2</td>",
+			"<tr class=\"note\"><td colspan=\"5\">reset &lt;button&gt;</td></tr>\n<tr><td>3</td>",
+		}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			if err := tt.spec.Render(&buf, events, stats); err != nil {
+				t.Errorf("Spec.Render() %s error = %v", tt.name, err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(buf.String(), w) {
+					t.Errorf("Spec.Render() %s = %v, want %v", tt.name, buf.String(), w)
+				}
+			}
+		})
+	}
+}
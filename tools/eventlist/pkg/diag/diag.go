@@ -0,0 +1,124 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package diag
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var errFormat = errors.New("unknown diagnostics format")
+
+// Kind is the category of a diagnostic, its value is the exit code of the tool
+type Kind int
+
+const (
+	OK         Kind = iota // no error
+	Error                  // invalid command line, option or file
+	Decode                 // the capture cannot be decoded
+	SCVD                   // an SCVD file is missing or invalid
+	NoMatch                // the filter matched no event
+	Assert                 // an assertion failed
+	Regression             // a capture changed beyond the thresholds
+	Differ                 // the events of the captures differ
+)
+
+var kindNames = []string{"ok", "error", "decode", "scvd", "no-match", "assert", "regression", "differ"}
+
+func (k Kind) String() string {
+	if k < 0 || int(k) >= len(kindNames) {
+		return fmt.Sprintf("kind(%d)", int(k))
+	}
+	return kindNames[k]
+}
+
+// Diagnostic is an error or warning as written in the JSON format
+type Diagnostic struct {
+	Severity string `json:"severity"` // error or warning
+	Code     string `json:"code"`
+	Exit     int    `json:"exit"`
+	Message  string `json:"message"`
+}
+
+// Reporter writes the diagnostics and keeps the exit code, the text format
+// writes "prog: message" lines to text, the JSON format one object per line
+// to json
+type Reporter struct {
+	prog   string
+	text   io.Writer
+	json   io.Writer
+	asJSON bool
+	exit   Kind
+}
+
+// New creates a reporter in text format
+func New(prog string, text io.Writer, json io.Writer) *Reporter {
+	return &Reporter{prog: prog, text: text, json: json}
+}
+
+// SetFormat selects the format: text or json
+func (r *Reporter) SetFormat(format string) error {
+	switch format {
+	case "", "text":
+		r.asJSON = false
+	case "json":
+		r.asJSON = true
+	default:
+		return fmt.Errorf("%w: %s", errFormat, format)
+	}
+	return nil
+}
+
+func (r *Reporter) write(severity string, kind Kind, message string) {
+	if r.exit == OK {
+		r.exit = kind
+	}
+	if r.asJSON {
+		data, _ := json.Marshal(Diagnostic{Severity: severity, Code: kind.String(), Exit: int(kind), Message: message})
+		fmt.Fprintf(r.json, "%s\n", data)
+		return
+	}
+	if severity == "warning" {
+		fmt.Fprintf(r.text, "%s: warning: %s\n", r.prog, message)
+	} else {
+		fmt.Fprintf(r.text, "%s: %s\n", r.prog, message)
+	}
+}
+
+// Error reports an error, the first error or warning sets the exit code
+func (r *Reporter) Error(kind Kind, err error) {
+	r.write("error", kind, err.Error())
+}
+
+// Errorf reports a formatted error
+func (r *Reporter) Errorf(kind Kind, format string, a ...any) {
+	r.write("error", kind, fmt.Sprintf(format, a...))
+}
+
+// Warning reports a warning, the kind OK keeps the exit code
+func (r *Reporter) Warning(kind Kind, message string) {
+	r.write("warning", kind, message)
+}
+
+// Exit returns the exit code
+func (r *Reporter) Exit() int {
+	return int(r.exit)
+}
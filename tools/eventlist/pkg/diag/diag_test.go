@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package diag
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestKind_String(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		kind Kind
+		want string
+	}{
+		{OK, "ok"},
+		{Error, "error"},
+		{Decode, "decode"},
+		{SCVD, "scvd"},
+		{NoMatch, "no-match"},
+		{Assert, "assert"},
+		{Regression, "regression"},
+		{Differ, "differ"},
+		{Kind(9), "kind(9)"},
+	}
+	for _, tt := range tests {
+		if got := tt.kind.String(); got != tt.want {
+			t.Errorf("Kind.String() %d = %v, want %v", int(tt.kind), got, tt.want)
+		}
+	}
+}
+
+func TestReporter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		format   string
+		report   func(r *Reporter)
+		wantText string
+		wantJSON string
+		exit     int
+	}{
+		{"none", "text", func(r *Reporter) {}, "", "", 0},
+		{"error", "", func(r *Reporter) { r.Error(SCVD, errors.New("open x.scvd")) }, "prog: open x.scvd\n", "", 3},
+		{"first", "text", func(r *Reporter) {
+			r.Errorf(Decode, "record %d", 4)
+			r.Error(Error, errors.New("later"))
+		}, "prog: record 4\nprog: later\n", "", 2},
+		{"warning", "text", func(r *Reporter) {
+			r.Warning(OK, "note")
+			r.Warning(NoMatch, "no event")
+		}, "prog: warning: note\nprog: warning: no event\n", "", 4},
+		{"json", "json", func(r *Reporter) {
+			r.Warning(NoMatch, "no event")
+			r.Error(Decode, errors.New("bad"))
+		}, "", "{\"severity\":\"warning\",\"code\":\"no-match\",\"exit\":4,\"message\":\"no event\"}\n" +
+			"{\"severity\":\"error\",\"code\":\"decode\",\"exit\":2,\"message\":\"bad\"}\n", 4},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var text, json bytes.Buffer
+			r := New("prog", &text, &json)
+			if err := r.SetFormat(tt.format); err != nil {
+				t.Fatalf("Reporter.SetFormat() error = %v", err)
+			}
+			tt.report(r)
+			if text.String() != tt.wantText || json.String() != tt.wantJSON || r.Exit() != tt.exit {
+				t.Errorf("Reporter %s = %q, %q, %d, want %q, %q, %d", tt.name, text.String(), json.String(), r.Exit(),
+					tt.wantText, tt.wantJSON, tt.exit)
+			}
+		})
+	}
+}
+
+func TestReporter_SetFormat(t *testing.T) {
+	t.Parallel()
+
+	r := New("prog", nil, nil)
+	if err := r.SetFormat("yaml"); !errors.Is(err, errFormat) {
+		t.Errorf("Reporter.SetFormat() error = %v, want %v", err, errFormat)
+	}
+}
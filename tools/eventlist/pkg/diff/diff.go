@@ -0,0 +1,335 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package diff
+
+import (
+	"eventlist/pkg/event"
+	"eventlist/pkg/output"
+	"eventlist/pkg/xml/scvd"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DefaultContext is the number of unchanged events around a change
+const DefaultContext = 3
+
+// Line is a decoded event as compared by the diff
+type Line struct {
+	Time      float64
+	Component string
+	Property  string
+	Value     string
+}
+
+// Read decodes the events of a capture
+func Read(in io.Reader, evdefs map[uint16]scvd.Event, typedefs map[string]map[string]map[int16]string,
+	memberTypes map[string]map[string]string) ([]Line, error) {
+	var lines []Line
+	err := output.Decode(in, evdefs, typedefs, memberTypes, func(rec *output.EventRecord, ev *event.Data) error {
+		lines = append(lines, Line{Time: rec.Time, Component: rec.Component, Property: rec.EventProperty, Value: rec.Value})
+		return nil
+	})
+	return lines, err
+}
+
+// Options of the diff
+type Options struct {
+	Timestamps bool // compare the timestamps too
+	Context    int  // unchanged events around a change
+}
+
+// op is one step of the edit script: ' ' keeps a[i] equal to b[j],
+// '-' removes a[i] and '+' adds b[j]
+type op struct {
+	kind byte
+	i, j int
+}
+
+// Result is the edit script turning the baseline into the current events
+type Result struct {
+	a, b    []Line
+	ops     []op
+	opt     Options
+	Added   int // events only in the current capture
+	Removed int // events only in the baseline
+	Changed int // events with same component and property but other value
+}
+
+func (o *Options) key(l *Line) string {
+	s := l.Component + "\x00" + l.Property + "\x00" + l.Value
+	if o.Timestamps {
+		s = fmt.Sprintf("%.8f\x00%s", l.Time, s)
+	}
+	return s
+}
+
+// Diff compares the baseline events a with the current events b
+func Diff(a []Line, b []Line, opt Options) *Result {
+	ka := make([]string, len(a))
+	for i := range a {
+		ka[i] = opt.key(&a[i])
+	}
+	kb := make([]string, len(b))
+	for i := range b {
+		kb[i] = opt.key(&b[i])
+	}
+	r := &Result{a: a, b: b, opt: opt, ops: editScript(ka, kb)}
+	r.count()
+	return r
+}
+
+// editScript returns the shortest edit script of the Myers algorithm,
+// the common prefix and suffix are skipped first
+func editScript(a []string, b []string) []op {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	ops := make([]op, 0, len(a)+len(b))
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, op{' ', i, i})
+	}
+	ops = append(ops, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix], prefix)...)
+	for s := suffix; s > 0; s-- {
+		ops = append(ops, op{' ', len(a) - s, len(b) - s})
+	}
+	return ops
+}
+
+// myers returns the edit script of a and b, the indexes are offset by base
+func myers(a []string, b []string, base int) []op {
+	n, m := len(a), len(b)
+	max := n + m
+	v := make([]int, 2*max+2)
+	var trace [][]int // v[-d..d] at the begin of step d
+	d := 0
+search:
+	for ; d <= max; d++ {
+		trace = append(trace, append([]int{}, v[max-d:max+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[max+k-1] < v[max+k+1]) {
+				x = v[max+k+1]
+			} else {
+				x = v[max+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[max+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	var ops []op
+	x, y := n, m
+	for ; d >= 0; d-- {
+		vd := trace[d] // vd[0] is v[-d]
+		get := func(k int) int { return vd[k+d] }
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && get(k-1) < get(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := 0
+		if d > 0 {
+			prevX = get(prevK)
+		}
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			ops = append(ops, op{' ', base + x, base + y})
+		}
+		if d > 0 {
+			if x == prevX {
+				y--
+				ops = append(ops, op{'+', base + x, base + y})
+			} else {
+				x--
+				ops = append(ops, op{'-', base + x, base + y})
+			}
+		}
+	}
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
+
+// count the added, removed and changed events, a removed and an added
+// event of the same change with same component and property is a changed
+// event
+func (r *Result) count() {
+	for i := 0; i < len(r.ops); {
+		if r.ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		var removed, added []op
+		for ; i < len(r.ops) && r.ops[i].kind == '-'; i++ {
+			removed = append(removed, r.ops[i])
+		}
+		for ; i < len(r.ops) && r.ops[i].kind == '+'; i++ {
+			added = append(added, r.ops[i])
+		}
+		// pair the added events in order with removed events of the same type
+		pairs, p := 0, 0
+		for _, o := range added {
+			for q := p; q < len(removed); q++ {
+				if r.a[removed[q].i].Component == r.b[o.j].Component && r.a[removed[q].i].Property == r.b[o.j].Property {
+					pairs++
+					p = q + 1
+					break
+				}
+			}
+		}
+		r.Changed += pairs
+		r.Added += len(added) - pairs
+		r.Removed += len(removed) - pairs
+	}
+}
+
+// Equal reports if the captures have the same events
+func (r *Result) Equal() bool {
+	return r.Added == 0 && r.Removed == 0 && r.Changed == 0
+}
+
+// hunks returns the ranges of the edit script with changes and their context
+func hunks(ops []op, context int) [][2]int {
+	var hunks [][2]int
+	for i, o := range ops {
+		if o.kind == ' ' {
+			continue
+		}
+		begin, end := i-context, i+1+context
+		if begin < 0 {
+			begin = 0
+		}
+		if end > len(ops) {
+			end = len(ops)
+		}
+		if n := len(hunks); n > 0 && begin <= hunks[n-1][1] {
+			hunks[n-1][1] = end
+		} else {
+			hunks = append(hunks, [2]int{begin, end})
+		}
+	}
+	return hunks
+}
+
+// writeHunks writes the hunks of the edit script, line returns the text of
+// a line of the first or second input
+func writeHunks(b *strings.Builder, ops []op, context int, line func(o op) string) {
+	for _, h := range hunks(ops, context) {
+		ops := ops[h[0]:h[1]]
+		countA, countB := 0, 0
+		for _, o := range ops {
+			if o.kind != '+' {
+				countA++
+			}
+			if o.kind != '-' {
+				countB++
+			}
+		}
+		fmt.Fprintf(b, "@@ -%s +%s @@\n", rng(ops[0].i, countA), rng(ops[0].j, countB))
+		for _, o := range ops {
+			fmt.Fprintf(b, "%c%s\n", o.kind, line(o))
+		}
+	}
+}
+
+// Text returns the differences of two texts split in lines in unified
+// diff format, an empty string if they are equal
+func Text(nameA string, nameB string, a []string, b []string, context int) string {
+	ops := editScript(a, b)
+	var s strings.Builder
+	for _, o := range ops {
+		if o.kind != ' ' {
+			fmt.Fprintf(&s, "--- %s\n+++ %s\n", nameA, nameB)
+			break
+		}
+	}
+	writeHunks(&s, ops, context, func(o op) string {
+		if o.kind == '+' {
+			return b[o.j]
+		}
+		return a[o.i]
+	})
+	return s.String()
+}
+
+// rng formats the range of a hunk header, the first line is 1
+func rng(first int, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", first)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", first+1)
+	}
+	return fmt.Sprintf("%d,%d", first+1, count)
+}
+
+// Write writes the differences in unified diff format, nameA and nameB
+// are the file names of the baseline and the current capture
+func (r *Result) Write(w io.Writer, nameA string, nameB string) error {
+	componentSize, propertySize := 0, 0
+	for _, lines := range [][]Line{r.a, r.b} {
+		for _, l := range lines {
+			if len(l.Component) > componentSize {
+				componentSize = len(l.Component)
+			}
+			if len(l.Property) > propertySize {
+				propertySize = len(l.Property)
+			}
+		}
+	}
+	format := func(l *Line) string {
+		s := fmt.Sprintf("%*s %*s %s", -componentSize, l.Component, -propertySize, l.Property, l.Value)
+		if r.opt.Timestamps {
+			s = fmt.Sprintf("%.8f %s", l.Time, s)
+		}
+		return strings.TrimRight(s, " ")
+	}
+
+	var b strings.Builder
+	if !r.Equal() {
+		fmt.Fprintf(&b, "--- %s\n+++ %s\n", nameA, nameB)
+	}
+	writeHunks(&b, r.ops, r.opt.Context, func(o op) string {
+		if o.kind == '+' {
+			return format(&r.b[o.j])
+		}
+		return format(&r.a[o.i])
+	})
+	fmt.Fprintf(&b, "%d added, %d removed, %d changed events\n", r.Added, r.Removed, r.Changed)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,178 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package diff
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// lines creates events of component App, an event "Poll=2" has property Poll and value 2
+func lines(events string) []Line {
+	var l []Line
+	for i, e := range strings.Fields(events) {
+		property, value, _ := strings.Cut(e, "=")
+		l = append(l, Line{Time: float64(i), Component: "App", Property: property, Value: value})
+	}
+	return l
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		a       string
+		b       string
+		opt     Options
+		added   int
+		removed int
+		changed int
+	}{
+		{"equal", "Init Poll=1 Poll=2", "Init Poll=1 Poll=2", Options{}, 0, 0, 0},
+		{"empty", "", "", Options{}, 0, 0, 0},
+		{"added", "Init Poll=1", "Init Reset Poll=1", Options{}, 1, 0, 0},
+		{"removed", "Init Reset Poll=1 Poll=2", "Init Poll=1", Options{}, 0, 2, 0},
+		{"changed", "Init Poll=1 Poll=2", "Init Poll=1 Poll=3", Options{}, 0, 0, 1},
+		{"replaced", "Init Poll=1", "Reset Poll=1", Options{}, 1, 1, 0},
+		{"all new", "", "Init Poll=1", Options{}, 2, 0, 0},
+		{"timestamps", "Init Poll=1", "Poll=1", Options{}, 0, 1, 0},
+		{"shifted", "Init Poll=1", "Poll=1", Options{Timestamps: true}, 0, 1, 1},
+		{"moved", "A B C D E F", "B C D E F A", Options{}, 1, 1, 0},
+		{"mixed", "A B C A B B A", "C B A B A C", Options{}, 2, 3, 0},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := Diff(lines(tt.a), lines(tt.b), tt.opt)
+			if r.Added != tt.added || r.Removed != tt.removed || r.Changed != tt.changed {
+				t.Errorf("Diff() %s = +%d -%d ~%d, want +%d -%d ~%d", tt.name,
+					r.Added, r.Removed, r.Changed, tt.added, tt.removed, tt.changed)
+			}
+			var edited []Line
+			for _, o := range r.ops {
+				switch {
+				case o.kind == ' ' && tt.opt.key(&r.a[o.i]) != tt.opt.key(&r.b[o.j]):
+					t.Errorf("Diff() %s keeps %v as %v", tt.name, r.a[o.i], r.b[o.j])
+				case o.kind != '-':
+					edited = append(edited, r.b[o.j])
+				}
+			}
+			if !reflect.DeepEqual(edited, r.b) {
+				t.Errorf("Diff() %s edit script %v does not create %v", tt.name, r.ops, r.b)
+			}
+			if r.Equal() != (tt.added+tt.removed+tt.changed == 0) {
+				t.Errorf("Result.Equal() %s = %v", tt.name, r.Equal())
+			}
+		})
+	}
+}
+
+func TestResult_Write(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		opt  Options
+		want string
+	}{
+		{"equal", "Init Poll=1", "Init Poll=1", Options{Context: DefaultContext},
+			"0 added, 0 removed, 0 changed events\n"},
+		{"context", "Init A B C D E F G H", "Init A B C Reset D E F G Fail", Options{Context: 1},
+			"--- a.log\n+++ b.log\n" +
+				"@@ -4,2 +4,3 @@\n App C\n+App Reset\n App D\n" +
+				"@@ -8,2 +9,2 @@\n App G\n-App H\n+App Fail\n" +
+				"2 added, 1 removed, 0 changed events\n"},
+		{"merged", "Init Poll=1 Poll=2", "Init Poll=2 Poll=3", Options{Context: DefaultContext},
+			"--- a.log\n+++ b.log\n" +
+				"@@ -1,3 +1,3 @@\n App Init\n-App Poll 1\n App Poll 2\n+App Poll 3\n" +
+				"1 added, 1 removed, 0 changed events\n"},
+		{"timestamps", "Init Poll=1", "Init Poll=2", Options{Timestamps: true}, "--- a.log\n+++ b.log\n" +
+			"@@ -2 +2 @@\n-1.00000000 App Poll 1\n+1.00000000 App Poll 2\n" +
+			"0 added, 0 removed, 1 changed events\n"},
+		{"insert", "", "Init", Options{}, "--- a.log\n+++ b.log\n" +
+			"@@ -0,0 +1 @@\n+App Init\n" +
+			"1 added, 0 removed, 0 changed events\n"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var b bytes.Buffer
+			if err := Diff(lines(tt.a), lines(tt.b), tt.opt).Write(&b, "a.log", "b.log"); err != nil {
+				t.Errorf("Result.Write() %s error = %v", tt.name, err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("Result.Write() %s = %q, want %q", tt.name, b.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+
+	file, err := os.Open("../../testdata/test10.binary")
+	if err != nil {
+		t.Errorf("Read() error = %v", err)
+		return
+	}
+	defer file.Close()
+	got, err := Read(file, nil, nil, nil)
+	if err != nil {
+		t.Errorf("Read() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Errorf("Read() events = %d, want 2", len(got))
+	}
+}
+
+func TestText(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want string
+	}{
+		{"equal", "a\nb", "a\nb", ""},
+		{"changed", "a\nb\nc", "a\nx\nc", "--- golden\n+++ output\n@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n"},
+		{"added", "a", "a\nb", "--- golden\n+++ output\n@@ -1 +1,2 @@\n a\n+b\n"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := Text("golden", "output", strings.Split(tt.a, "\n"), strings.Split(tt.b, "\n"), DefaultContext)
+			if got != tt.want {
+				t.Errorf("Text() %s = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -19,9 +19,13 @@
 package elf
 
 import (
+	"debug/dwarf"
 	"debug/elf"
 	"errors"
+	"fmt"
+	"sort"
 	"strings"
+	"sync"
 )
 
 type elfSection struct {
@@ -47,6 +51,42 @@ type symbols struct {
 
 var Symbols symbols
 
+// row of the DWARF line table, line 0 ends the code of the row before
+type lineRow struct {
+	addr uint64
+	file string
+	line int
+}
+
+// line table of ELF files, read once on the first lookup
+type lineTable struct {
+	once  sync.Once
+	names []string  // ELF files of the table
+	rows  []lineRow // sorted by address
+	files map[string]bool
+}
+
+type lines struct {
+	table *lineTable
+}
+
+// Lines maps code addresses to source lines with the DWARF line table
+var Lines lines
+
+// Warning reports an ELF file whose line table cannot be read, the
+// addresses of the file have no source lines then
+var Warning func(err error)
+
+// add an ELF file to the line table, the copies of Lines keep their table
+func (l *lines) add(name string) {
+	table := &lineTable{}
+	if l.table != nil {
+		table.names = append(table.names, l.table.names...)
+	}
+	table.names = append(table.names, name)
+	l.table = table
+}
+
 func (s *sections) Readelf(name *string) error {
 	file, err := elf.Open(*name)
 	if err != nil {
@@ -75,9 +115,103 @@ func (s *sections) Readelf(name *string) error {
 	for _, s := range syms {
 		Symbols.symbols[s.Name] = symbol{s.Value, s.Size}
 	}
+	Lines.add(*name)
+	return nil
+}
+
+// read the line tables of the ELF files, a file without debug information
+// or with an invalid line table adds no lines
+func (t *lineTable) load() {
+	t.files = make(map[string]bool)
+	for _, name := range t.names {
+		if err := t.read(name); err != nil && Warning != nil {
+			Warning(fmt.Errorf("%s: no source lines: %w", name, err))
+		}
+	}
+	sort.SliceStable(t.rows, func(i, j int) bool { // the end of a sequence before a start at the same address
+		a, b := &t.rows[i], &t.rows[j]
+		return a.addr < b.addr || a.addr == b.addr && a.line == 0 && b.line != 0
+	})
+}
+
+// read the line tables of all compile units of an ELF file
+func (t *lineTable) read(name string) error {
+	file, err := elf.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	data, err := file.DWARF()
+	if err != nil { // without debug information no lines
+		return nil
+	}
+	var rows []lineRow
+	files := make(map[string]bool)
+	r := data.Reader()
+	for {
+		cu, err := r.Next()
+		if err != nil {
+			return err
+		}
+		if cu == nil {
+			break
+		}
+		r.SkipChildren()
+		if cu.Tag != dwarf.TagCompileUnit {
+			continue
+		}
+		lr, err := data.LineReader(cu)
+		if err != nil {
+			return err
+		}
+		if lr == nil {
+			continue
+		}
+		var e dwarf.LineEntry
+		for lr.Next(&e) == nil {
+			row := lineRow{addr: e.Address}
+			if !e.EndSequence && e.File != nil && e.Line != 0 {
+				row.file, row.line = e.File.Name, e.Line
+				files[row.file] = true
+			}
+			rows = append(rows, row)
+		}
+	}
+	t.rows = append(t.rows, rows...)
+	for file := range files {
+		t.files[file] = true
+	}
 	return nil
 }
 
+// the loaded line table, nil without ELF file
+func (l *lines) loaded() *lineTable {
+	if l.table != nil {
+		l.table.once.Do(l.table.load)
+	}
+	return l.table
+}
+
+// Find returns the source file and line of the code at addr, the line
+// table is read on the first lookup
+func (l *lines) Find(addr uint64) (file string, line int, found bool) {
+	t := l.loaded()
+	if t == nil {
+		return "", 0, false
+	}
+	i := sort.Search(len(t.rows), func(i int) bool { return t.rows[i].addr > addr })
+	if i == 0 || t.rows[i-1].line == 0 {
+		return "", 0, false
+	}
+	return t.rows[i-1].file, t.rows[i-1].line, true
+}
+
+// IsFile reports if name is a source file of the line table
+func (l *lines) IsFile(name string) bool {
+	t := l.loaded()
+	return t != nil && t.files[name]
+}
+
 func (s *sections) GetString(addr uint64) string {
 	for _, es := range s.sections {
 		if addr >= es.addr && addr < es.addr+uint64(len(es.data)) {
@@ -100,3 +234,23 @@ func (s *symbols) GetAddrSize(name string) (addr uint64, size uint64, found bool
 	}
 	return sym.addr, sym.size, true
 }
+
+// State is the content of the read ELF files
+type State struct {
+	sections sections
+	symbols  symbols
+	lines    lines
+}
+
+// Current returns the content of the read ELF files
+func Current() State {
+	return State{Sections, Symbols, Lines}
+}
+
+// Use replaces the content of the read ELF files, e.g. by the
+// content of another capture, State{} removes the content
+func Use(s State) {
+	Sections = s.sections
+	Symbols = s.symbols
+	Lines = s.lines
+}
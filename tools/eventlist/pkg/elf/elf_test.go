@@ -20,6 +20,7 @@ package elf
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -178,3 +179,85 @@ func Test_symbols_GetAddrSize(t *testing.T) {
 		})
 	}
 }
+
+func Test_lines_Find(t *testing.T) { //nolint:golint,paralleltest
+	saved := Current()
+	defer Use(saved)
+
+	Use(State{})
+	name := "../../testdata/elfsym.elf"
+	if err := Sections.Readelf(&name); err != nil {
+		t.Fatalf("Readelf() error = %v", err)
+	}
+	if Lines.table.rows != nil {
+		t.Errorf("Readelf() read the line table, want it read on the first lookup")
+	}
+	file := `C:\Keil\ARM-Tests\Blinky\RTE/Device/IOTKit_CM33\system_IOTKit_CM33.c`
+	tests := []struct {
+		name  string
+		addr  uint64
+		file  string
+		line  int
+		found bool
+	}{
+		{"row", 0x10001596, file, 67, true},
+		{"within row", 0x10001597, file, 67, true},
+		{"line 0", 0x1000159A, "", 0, false},
+		{"before", 0x100, "", 0, false},
+		{"after", 0xFFFFFFFF, "", 0, false},
+	}
+	for _, tt := range tests { //nolint:golint,paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			file, line, found := Lines.Find(tt.addr)
+			if file != tt.file || line != tt.line || found != tt.found {
+				t.Errorf("lines.Find() %s = %v, %v, %v, want %v, %v, %v", tt.name, file, line, found, tt.file, tt.line, tt.found)
+			}
+		})
+	}
+	if !Lines.IsFile(file) || Lines.IsFile("main.c") {
+		t.Errorf("lines.IsFile() = %v, %v, want true, false", Lines.IsFile(file), Lines.IsFile("main.c"))
+	}
+}
+
+func TestUse(t *testing.T) { //nolint:golint,paralleltest
+	saved := Current()
+	defer Use(saved)
+
+	Use(State{})
+	name := "../../testdata/elftest.elf"
+	if err := Sections.Readelf(&name); err != nil {
+		t.Fatalf("Readelf() error = %v", err)
+	}
+	state := Current()
+	if len(state.sections.sections) == 0 {
+		t.Errorf("Current() = %v, want sections", state)
+	}
+	Use(State{})
+	if len(Sections.sections) != 0 || len(Symbols.symbols) != 0 {
+		t.Errorf("Use() empty = %v %v, want no sections and symbols", Sections, Symbols)
+	}
+	Use(state)
+	if !reflect.DeepEqual(Current(), state) {
+		t.Errorf("Use() = %v, want %v", Current(), state)
+	}
+}
+
+func Test_lineTable_load(t *testing.T) { //nolint:golint,paralleltest
+	defer func() { Warning = nil }()
+
+	var warnings []string
+	Warning = func(err error) { warnings = append(warnings, err.Error()) }
+	table := &lineTable{names: []string{"../../testdata/missing.elf", "../../testdata/elfsym.elf"}}
+	table.load()
+	if len(warnings) != 1 || !strings.HasPrefix(warnings[0], "../../testdata/missing.elf: no source lines: ") {
+		t.Errorf("lineTable.load() warnings = %v, want missing.elf", warnings)
+	}
+	if len(table.rows) == 0 {
+		t.Errorf("lineTable.load() rows = 0, want the lines of elfsym.elf")
+	}
+
+	var l lines
+	if _, _, found := l.Find(0x10001596); found || l.IsFile("main.c") {
+		t.Errorf("lines.Find() without ELF file found = %v, want false", found)
+	}
+}
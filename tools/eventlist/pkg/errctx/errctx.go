@@ -0,0 +1,185 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package errctx
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"eventlist/pkg/bus"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var errSize = errors.New("error context size must be positive")
+
+// Record is one event of an error context, Offset is the position relative to the error
+type Record struct {
+	Index         int     `json:"index"`
+	Offset        int     `json:"offset"`
+	Time          float64 `json:"time"`
+	Level         string  `json:"level,omitempty"`
+	Component     string  `json:"component"`
+	EventProperty string  `json:"eventProperty"`
+	Value         string  `json:"value"`
+}
+
+// Window holds the events around one error event
+type Window struct {
+	Error   Record
+	Records []Record // events before, the error and events after
+	missing int      // events still to be collected after the error
+}
+
+// Context collects the n events before and after every Error level event.
+// The windows are written as report sections and, if dir is set,
+// as one JSONL file per error.
+type Context struct {
+	size    int
+	dir     string
+	recent  []Record // ring of the last size events
+	next    int
+	open    []*Window
+	Windows []*Window
+}
+
+// New creates an error context analyzer for size events before and after each error
+func New(size int, dir string) (*Context, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("%w: %d", errSize, size)
+	}
+	return &Context{size: size, dir: dir}, nil
+}
+
+// Event adds an event to the open windows and opens a window on errors
+func (c *Context) Event(ev *bus.Event) error {
+	value, _ := ev.Value()
+	rec := Record{
+		Index:         ev.Index,
+		Time:          ev.Time,
+		Level:         ev.Level(),
+		Component:     ev.Component(),
+		EventProperty: ev.Property(),
+		Value:         value,
+	}
+	open := c.open[:0]
+	for _, w := range c.open {
+		w.Records = append(w.Records, rec)
+		if w.missing--; w.missing > 0 {
+			open = append(open, w)
+		}
+	}
+	c.open = open
+	if rec.Level == "Error" {
+		w := &Window{Error: rec, missing: c.size}
+		for i := range c.recent { // oldest first
+			w.Records = append(w.Records, c.recent[(c.next+i)%len(c.recent)])
+		}
+		w.Records = append(w.Records, rec)
+		c.Windows = append(c.Windows, w)
+		c.open = append(c.open, w)
+	}
+	if len(c.recent) < c.size {
+		c.recent = append(c.recent, rec)
+	} else {
+		c.recent[c.next] = rec
+		c.next = (c.next + 1) % c.size
+	}
+	return nil
+}
+
+// End writes the JSONL files, windows at the end of the events stay shorter
+func (c *Context) End() error {
+	c.open = nil
+	for _, w := range c.Windows {
+		for i := range w.Records {
+			w.Records[i].Offset = w.Records[i].Index - w.Error.Index
+		}
+	}
+	if c.dir == "" {
+		return nil
+	}
+	for _, w := range c.Windows {
+		if err := w.write(filepath.Join(c.dir, fmt.Sprintf("error-%d.jsonl", w.Error.Index))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// write a window as one JSON object per line
+func (w *Window) write(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	out := bufio.NewWriter(file)
+	enc := json.NewEncoder(out)
+	for _, rec := range w.Records {
+		if err = enc.Encode(rec); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = out.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Report writes one section per error, the error event is marked with '>'
+func (c *Context) Report(w io.Writer) error {
+	for i, win := range c.Windows {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		title := fmt.Sprintf("Error context of event %d", win.Error.Index)
+		if _, err := fmt.Fprintf(w, "   %s\n   %s\n\n", title, strings.Repeat("-", len(title))); err != nil {
+			return err
+		}
+		componentSize, propertySize := 0, 0
+		for _, rec := range win.Records {
+			if len(rec.Component) > componentSize {
+				componentSize = len(rec.Component)
+			}
+			if len(rec.EventProperty) > propertySize {
+				propertySize = len(rec.EventProperty)
+			}
+		}
+		for _, rec := range win.Records {
+			mark := " "
+			if rec.Index == win.Error.Index {
+				mark = ">"
+			}
+			line := fmt.Sprintf("%s%5d %.8f %*s %*s %s", mark, rec.Index, rec.Time,
+				-componentSize, rec.Component, -propertySize, rec.EventProperty, rec.Value)
+			if _, err := fmt.Fprintln(w, strings.TrimRight(line, " ")); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
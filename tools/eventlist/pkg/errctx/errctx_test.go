@@ -0,0 +1,145 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package errctx
+
+import (
+	"bytes"
+	"errors"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/event"
+	"eventlist/pkg/xml/scvd"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+var defs = map[bool]*scvd.Event{
+	false: {Brief: "App", Property: "Poll", Level: "Op"},
+	true:  {Brief: "App", Property: "Fail", Level: "Error"},
+}
+
+// run a context over events, errors are given as indexes
+func run(size int, dir string, count int, errs ...int) *Context {
+	c, _ := New(size, dir)
+	for i := 0; i < count; i++ {
+		isErr := false
+		for _, e := range errs {
+			isErr = isErr || e == i
+		}
+		_ = c.Event(bus.NewEvent(i, float64(i), &event.Data{}, defs[isErr], nil))
+	}
+	return c
+}
+
+func indexes(w *Window) []int {
+	var list []int
+	for _, rec := range w.Records {
+		list = append(list, rec.Index)
+	}
+	return list
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	if _, err := New(0, ""); !errors.Is(err, errSize) {
+		t.Errorf("New() error = %v, want %v", err, errSize)
+	}
+	if _, err := New(3, ""); err != nil {
+		t.Errorf("New() error = %v, want nil", err)
+	}
+}
+
+func TestContext_Event(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		size  int
+		count int
+		errs  []int
+		want  [][]int
+	}{
+		{"none", 2, 5, nil, nil},
+		{"middle", 2, 10, []int{5}, [][]int{{3, 4, 5, 6, 7}}},
+		{"begin", 2, 10, []int{0}, [][]int{{0, 1, 2}}},
+		{"end", 2, 10, []int{9}, [][]int{{7, 8, 9}}},
+		{"wrap", 3, 10, []int{8}, [][]int{{5, 6, 7, 8, 9}}},
+		{"overlap", 2, 10, []int{4, 5}, [][]int{{2, 3, 4, 5, 6}, {3, 4, 5, 6, 7}}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := run(tt.size, "", tt.count, tt.errs...)
+			_ = c.End()
+			var got [][]int
+			for _, w := range c.Windows {
+				got = append(got, indexes(w))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Context.Event() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContext_Report(t *testing.T) {
+	t.Parallel()
+
+	want := "   Error context of event 1\n" +
+		"   ------------------------\n\n" +
+		"     0 0.00000000 App Poll\n" +
+		">    1 1.00000000 App Fail\n" +
+		"     2 2.00000000 App Poll\n"
+
+	c := run(1, "", 4, 1)
+	_ = c.End()
+	var b bytes.Buffer
+	if err := c.Report(&b); err != nil {
+		t.Errorf("Context.Report() error = %v", err)
+	}
+	if b.String() != want {
+		t.Errorf("Context.Report() = %q, want %q", b.String(), want)
+	}
+}
+
+func TestContext_End(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	want := "{\"index\":0,\"offset\":-1,\"time\":0,\"level\":\"Op\",\"component\":\"App\",\"eventProperty\":\"Poll\",\"value\":\"\"}\n" +
+		"{\"index\":1,\"offset\":0,\"time\":1,\"level\":\"Error\",\"component\":\"App\",\"eventProperty\":\"Fail\",\"value\":\"\"}\n"
+
+	c := run(1, dir, 2, 1)
+	if err := c.End(); err != nil {
+		t.Errorf("Context.End() error = %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, "error-1.jsonl"))
+	if err != nil || string(got) != want {
+		t.Errorf("Context.End() = %v %v, want %v", string(got), err, want)
+	}
+
+	c = run(1, filepath.Join(dir, "nix"), 2, 1)
+	if err := c.End(); err == nil {
+		t.Errorf("Context.End() error = nil, want error")
+	}
+}
@@ -61,3 +61,15 @@ func TestEval(t *testing.T) {
 		})
 	}
 }
+
+func BenchmarkEval(b *testing.B) {
+	SetVarI("val1", 0x1234)
+	SetVarI("val2", 7)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s := "(val1 >> 4) & 0xFF + val2 * 3"
+		if _, err := Eval(&s); err != nil {
+			b.Fatalf("Eval() error = %v", err)
+		}
+	}
+}
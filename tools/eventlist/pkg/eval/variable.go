@@ -51,13 +51,19 @@ func GetVar(n string) (*Variable, error) {
 	return v, nil
 }
 
+// SetVarI sets an integer variable, an existing variable is
+// updated in place because the event values are set for every event
 func SetVarI(n string, i int64) *Variable {
 	val := Value{t: Integer, i: i}
+	mu.Lock()
+	defer mu.Unlock()
+	if v, ok := names[n]; ok {
+		v.v = val
+		return v
+	}
 	v := new(Variable)
 	v.n = n
 	v.v = val
-	mu.Lock()
-	defer mu.Unlock()
 	if len(names) == 0 {
 		names = make(map[string]*Variable)
 	}
@@ -27,15 +27,41 @@ import (
 	"eventlist/pkg/xml/scvd"
 	"fmt"
 	"io"
+	"math"
+	"net/netip"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 var errEnum = errors.New("invalid enum")
 
 var errFormat = errors.New("invalid format expression")
 
+var errType = errors.New("invalid value type")
+
+var errRange = errors.New("offset outside of event data")
+
+// ErrUnresolved is the error of an address without string in the ELF file
+var ErrUnresolved = errors.New("unresolved string address")
+
+// Strict makes the addresses of %t, %F and %N without string in the ELF
+// file an error instead of printing the address
+var Strict bool
+
+// FloatType overrides the type %T uses to interpret integer values:
+// "float", "double" or "half". Empty uses the type given in the SCVD file.
+var FloatType string
+
+// FixedType overrides the fixed point type %T uses to interpret integer values,
+// for example "Q15" or "Q8.8". Empty uses the type given in the SCVD file.
+var FixedType string
+
+// Precision is the number of fraction digits %T prints for non integer values,
+// -1 prints the smallest number of digits necessary to represent the value
+var Precision = 6
+
 func enumError(fn, str string) *eval.NumError {
 	return &eval.NumError{Func: fn, Num: str, Err: errEnum}
 }
@@ -44,6 +70,151 @@ func formatError(fn, str string) *eval.NumError {
 	return &eval.NumError{Func: fn, Num: str, Err: errFormat}
 }
 
+func typeError(fn, str string) *eval.NumError {
+	return &eval.NumError{Func: fn, Num: str, Err: errType}
+}
+
+// ByteOrder of the records in the event file, the byte order of the target
+var ByteOrder binary.ByteOrder = binary.LittleEndian
+
+// SetByteOrder sets the byte order of the records: little or big
+func SetByteOrder(endian string) error {
+	switch endian {
+	case "", "little":
+		ByteOrder = binary.LittleEndian
+	case "big":
+		ByteOrder = binary.BigEndian
+	default:
+		return typeError("SetByteOrder", endian)
+	}
+	return nil
+}
+
+// SetFloatType sets the type override for %T
+func SetFloatType(typ string) error {
+	switch typ {
+	case "", "float", "double", "half":
+		FloatType = typ
+		return nil
+	}
+	return typeError("SetFloatType", typ)
+}
+
+// SetFixedType sets the fixed point type override for %T
+func SetFixedType(typ string) error {
+	if typ != "" {
+		if _, _, _, ok := parseFixed(typ); !ok {
+			return typeError("SetFixedType", typ)
+		}
+	}
+	FixedType = typ
+	return nil
+}
+
+// get the integer bits, fraction bits and signedness of a fixed point type
+// "Qn", "Qm.n", "UQm.n" or one of the CMSIS-DSP types q7_t, q15_t, q31_t
+func parseFixed(typ string) (m int, n int, signed bool, ok bool) {
+	t := strings.TrimSuffix(strings.ToUpper(typ), "_T")
+	switch {
+	case strings.HasPrefix(t, "UQ"):
+		t = t[2:]
+	case strings.HasPrefix(t, "Q"):
+		t = t[1:]
+		signed = true
+	default:
+		return 0, 0, false, false
+	}
+	var err error
+	if i := strings.IndexByte(t, '.'); i >= 0 {
+		if m, err = strconv.Atoi(t[:i]); err != nil {
+			return 0, 0, false, false
+		}
+		t = t[i+1:]
+	}
+	if n, err = strconv.Atoi(t); err != nil {
+		return 0, 0, false, false
+	}
+	bits := m + n
+	if signed {
+		bits++
+	}
+	if m < 0 || n < 0 || bits < 1 || bits > 64 {
+		return 0, 0, false, false
+	}
+	return m, n, signed, true
+}
+
+// convert the bits of a fixed point value
+func fixedToFloat(bits uint64, m int, n int, signed bool) float64 {
+	size := m + n
+	if signed {
+		size++
+	}
+	if size < 64 {
+		bits &= 1<<size - 1
+	}
+	if signed {
+		v := int64(bits<<(64-size)) >> (64 - size)
+		return math.Ldexp(float64(v), -n)
+	}
+	return math.Ldexp(float64(bits), -n)
+}
+
+// convert an IEEE-754 half precision value to single precision
+func halfToFloat(h uint16) float32 {
+	sign := uint32(h>>15) << 31
+	exp := uint32(h>>10) & 0x1F
+	mant := uint32(h) & 0x3FF
+	switch {
+	case exp == 0x1F: // infinity or NaN
+		return math.Float32frombits(sign | 0xFF<<23 | mant<<13)
+	case exp != 0: // normalized
+		return math.Float32frombits(sign | (exp+112)<<23 | mant<<13)
+	}
+	f := float32(mant) / (1 << 24) // zero or subnormal
+	if sign != 0 {
+		f = -f
+	}
+	return f
+}
+
+// interpret the bits of an integer value as floating point number of type typ
+func getFloat(bits uint64, typ string) (float64, bool) {
+	switch typ {
+	case "float":
+		return float64(math.Float32frombits(uint32(bits))), true
+	case "double":
+		return math.Float64frombits(bits), true
+	case "half":
+		return float64(halfToFloat(uint16(bits))), true
+	}
+	if m, n, signed, ok := parseFixed(typ); ok {
+		return fixedToFloat(bits, m, n, signed), true
+	}
+	return 0, false
+}
+
+// get the type of a typedef member in the form "typedef:member"
+// stay on closing ]
+func getMemberType(memberTypes map[string]map[string]string, value string, i *int) (string, error) {
+	j := strings.IndexByte(value[*i:], ']')
+	if j == -1 {
+		return "", formatError("getMemberType", value[*i:])
+	}
+	k := strings.IndexByte(value[*i:*i+j], ':')
+	if k == -1 {
+		return "", formatError("getMemberType", value[*i:*i+j])
+	}
+	td := strings.TrimSpace(value[*i : *i+k])
+	md := strings.TrimSpace(value[*i+k+1 : *i+j])
+	typ, ok := memberTypes[td][md]
+	if !ok {
+		return "", typeError("getMemberType", td+":"+md)
+	}
+	*i += j
+	return typ, nil
+}
+
 // get the enum value as string
 // count closing ]
 func getEnum(typedefs map[string]map[string]map[int16]string, val int64, value string, i *int) (string, error) {
@@ -97,6 +268,22 @@ func (info *Info) getInfoFromBytes(data []byte) {
 	info.length &= 0x7FFF
 }
 
+// IRQ returns true if the event was recorded in an interrupt
+func (info *Info) IRQ() bool {
+	return info.irq
+}
+
+// SetIRQ marks the event as recorded in an interrupt
+func (info *Info) SetIRQ(irq bool) {
+	info.irq = irq
+}
+
+// Builtin returns true for the events known without SCVD file: the
+// events of the Event Recorder, stdout and the start/stop events
+func (info *Info) Builtin() bool {
+	return info.ID>>8 == 0xFF || info.ID == 0xFE00 || info.ID>>8 == 0xEF
+}
+
 func (info *Info) SplitID() (class uint16, group uint16, idx uint16, start bool) {
 	class = info.ID >> 8            // should be 0xEF
 	group = info.ID >> 6 & 3        // 0..3 are A..D
@@ -116,11 +303,35 @@ type Data struct {
 	Info   Info
 }
 
+// get n bytes at offset of the event data, the values of
+// Eventrecord2 and Eventrecord4 are used in memory order
+func (e *Data) getBytes(offset uint64, n int) ([]byte, error) {
+	var data []byte
+	switch {
+	case e.Data != nil:
+		data = *e.Data
+	case e.Typ == 2:
+		data = make([]byte, 8)
+	default:
+		data = make([]byte, 16)
+	}
+	if e.Data == nil {
+		for i, v := range []int32{e.Value1, e.Value2, e.Value3, e.Value4}[:len(data)/4] {
+			ByteOrder.PutUint32(data[4*i:], uint32(v))
+		}
+	}
+	if offset > uint64(len(data)) || uint64(len(data))-offset < uint64(n) {
+		return nil, errRange
+	}
+	return data[offset : offset+uint64(n)], nil
+}
+
 // calculate a format expression and return the result
 // if unknown code then return the code only
-func (e *Data) calculateExpression(value string, i *int) (string, error) {
+func (e *Data) calculateExpression(memberTypes map[string]map[string]string, value string, i *int) (string, error) {
 	var val eval.Value
 	var out string
+	var typ string
 	var err error
 
 	if *i >= len(value) {
@@ -133,6 +344,13 @@ func (e *Data) calculateExpression(value string, i *int) (string, error) {
 		if err != nil {
 			return "", err
 		}
+		if value[*i] == ',' { // type given by typedef member
+			j := *i + 1
+			if typ, err = getMemberType(memberTypes, value, &j); err != nil {
+				return "", err
+			}
+			*i = j
+		}
 		if value[*i] != ']' {
 			return "", eval.ErrSyntax
 		}
@@ -140,43 +358,81 @@ func (e *Data) calculateExpression(value string, i *int) (string, error) {
 	}
 	switch c {
 	case 'd': // signed decimal
-		out = fmt.Sprintf("%d", val.GetInt())
+		out = strconv.FormatInt(val.GetInt(), 10)
 	case 'u': // unsigned decimal
-		out = fmt.Sprintf("%d", val.GetUInt())
+		out = strconv.FormatUint(val.GetUInt(), 10)
 	case 't': // text
 		out = elf.Sections.GetString(val.GetUInt())
+		if len(out) == 0 && Strict {
+			return "", fmt.Errorf("%w: 0x%08x", ErrUnresolved, val.GetUInt())
+		}
 	case 'x': // hexadecimal
-		out = fmt.Sprintf("0x%02x", val.GetUInt())
+		out = string(appendHex([]byte("0x"), val.GetUInt(), 2))
 	case 'F': // File
 		out = elf.Sections.GetString(val.GetUInt())
+		if len(out) == 0 && Strict {
+			return "", fmt.Errorf("%w: 0x%08x", ErrUnresolved, val.GetUInt())
+		}
 		if len(out) == 0 {
 			out = fmt.Sprintf("0x%08x", val.GetUInt())
 		}
 	case 'C': // address with file
-		return "", eval.ErrSyntax
+		file, line, found := elf.Lines.Find(val.GetUInt())
+		if !found && Strict {
+			return "", fmt.Errorf("%w: 0x%08x", ErrUnresolved, val.GetUInt())
+		}
+		if found {
+			out = file + ":" + strconv.Itoa(line)
+		} else {
+			out = fmt.Sprintf("0x%08x", val.GetUInt())
+		}
 	case 'I': // IPV4
 		out = fmt.Sprintf("%d.%d.%d.%d", val.GetUInt()>>24&0xFF, val.GetUInt()>>16&0xFF,
 			val.GetUInt()>>8&0xFF, val.GetUInt()&0xFF)
-	case 'J': // IPV6			TODO: only part of IPV6
-		out = fmt.Sprintf("%x:%x:%x:%x:", val.GetUInt()>>48&0xFFFF, val.GetUInt()>>32&0xFFFF,
-			val.GetUInt()>>16&0xFFFF, val.GetUInt()&0xFFFF)
+	case 'J': // IPV6 at offset of event data
+		b, err := e.getBytes(val.GetUInt(), 16)
+		if err != nil {
+			return "", err
+		}
+		out = netip.AddrFrom16(*(*[16]byte)(b)).String()
 	case 'N': // string address
 		out = elf.Sections.GetString(val.GetUInt())
+		if len(out) == 0 && Strict {
+			return "", fmt.Errorf("%w: 0x%08x", ErrUnresolved, val.GetUInt())
+		}
 		if len(out) == 0 {
 			out = fmt.Sprintf("0x%08x", val.GetUInt())
 		}
-	case 'M': // MAC address
-		out = fmt.Sprintf("%02x-%02x-%02x-%02x-%02x-%02x", val.GetUInt()>>40&0xFF, val.GetUInt()>>32&0xFF,
-			val.GetUInt()>>24&0xFF, val.GetUInt()>>16&0xFF, val.GetUInt()>>8&0xFF, val.GetUInt()&0xFF)
+	case 'M': // MAC address at offset of event data
+		b, err := e.getBytes(val.GetUInt(), 6)
+		if err != nil {
+			return "", err
+		}
+		out = fmt.Sprintf("%02x-%02x-%02x-%02x-%02x-%02x", b[0], b[1], b[2], b[3], b[4], b[5])
 	case 'S': // address
 		out = fmt.Sprintf("%08x", val.GetUInt())
 	case 'T': // type dependant
+		if FloatType != "" {
+			typ = FloatType
+		} else if FixedType != "" {
+			typ = FixedType
+		}
 		switch {
-		case val.IsFloating(): // TODO: Float not yet possible because of event record format
-			out = fmt.Sprintf("%f", val.GetFloat())
+		case val.IsFloating():
+			out = strconv.FormatFloat(val.GetFloat(), 'f', Precision, 64)
 		case val.IsInteger():
-			out = fmt.Sprintf("%d", val.GetInt())
+			if f, ok := getFloat(val.GetUInt(), typ); ok {
+				out = strconv.FormatFloat(f, 'f', Precision, 64)
+			} else {
+				out = fmt.Sprintf("%d", val.GetInt())
+			}
+		}
+	case 'G': // UUID at offset of event data
+		b, err := e.getBytes(val.GetUInt(), 16)
+		if err != nil {
+			return "", err
 		}
+		out = fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
 	case 'U': // USB descriptor
 	default:
 		out = string(c)
@@ -216,8 +472,10 @@ func (e *Data) calculateEnumExpression(typedefs map[string]map[string]map[int16]
 	return out, nil
 }
 
-func (e *Data) EvalLine(scvdevent scvd.Event, typedefs map[string]map[string]map[int16]string) (string, error) {
-	var s string
+func (e *Data) EvalLine(scvdevent scvd.Event, typedefs map[string]map[string]map[int16]string,
+	memberTypes map[string]map[string]string) (string, error) {
+	var s strings.Builder
+	s.Grow(len(scvdevent.Value) + 16)
 	for i := 0; i < len(scvdevent.Value); i++ {
 		c := scvdevent.Value[i]
 		if c == '%' {
@@ -226,7 +484,7 @@ func (e *Data) EvalLine(scvdevent scvd.Event, typedefs map[string]map[string]map
 				c := scvdevent.Value[i]
 				switch c {
 				case '%':
-					s += string(c)
+					s.WriteByte(c)
 					continue
 				case 'd': // signed decimal
 					fallthrough
@@ -248,81 +506,121 @@ func (e *Data) EvalLine(scvdevent scvd.Event, typedefs map[string]map[string]map
 					fallthrough
 				case 'M': // MAC address
 					fallthrough
+				case 'G': // UUID
+					fallthrough
 				case 'S': // address
 					fallthrough
 				case 'T': // type dependant
 					fallthrough
 				case 'U': // USB descriptor
-					out, err := e.calculateExpression(string(scvdevent.Value), &i)
+					out, err := e.calculateExpression(memberTypes, string(scvdevent.Value), &i)
 					if err != nil {
 						return "", err
 					}
-					s += out
+					s.WriteString(out)
 					i--
 				case 'E': // enum
 					out, err := e.calculateEnumExpression(typedefs, string(scvdevent.Value), &i)
 					if err != nil {
 						return "", err
 					}
-					s += out
+					s.WriteString(out)
 					i--
 				}
 			}
 		} else {
-			s += string(c)
+			s.WriteByte(c)
 		}
 	}
-	return s, nil
+	return s.String(), nil
+}
+
+// append v as lower case hex with at least width digits
+func appendHex(b []byte, v uint64, width int) []byte {
+	const digits = "0123456789abcdef"
+	var buf [16]byte
+	n := len(buf)
+	for v != 0 || len(buf)-n < width {
+		n--
+		buf[n] = digits[v&0xF]
+		v >>= 4
+	}
+	return append(b, buf[n:]...)
 }
 
 func (e *Data) GetValuesAsString() string {
-	value := ""
+	var b []byte
 	switch e.Typ {
 	case 1: // EventrecordData
-		value = "data=0x"
+		b = make([]byte, 0, 7+2*len(*e.Data))
+		b = append(b, "data=0x"...)
 		for _, d := range *e.Data {
-			value += fmt.Sprintf("%02x", d)
+			b = appendHex(b, uint64(d), 2)
 		}
 	case 2: // Eventrecord2
-		value = fmt.Sprintf("val1=0x%08x, val2=0x%08x", uint32(e.Value1), uint32(e.Value2))
+		b = make([]byte, 0, 34)
+		b = append(b, "val1=0x"...)
+		b = appendHex(b, uint64(uint32(e.Value1)), 8)
+		b = append(b, ", val2=0x"...)
+		b = appendHex(b, uint64(uint32(e.Value2)), 8)
 	case 3: // Eventrecord4
-		value = fmt.Sprintf("val1=0x%08x, val2=0x%08x, val3=0x%08x, val4=0x%08x",
-			uint32(e.Value1), uint32(e.Value2), uint32(e.Value3), uint32(e.Value4))
+		b = make([]byte, 0, 70)
+		b = append(b, "val1=0x"...)
+		b = appendHex(b, uint64(uint32(e.Value1)), 8)
+		b = append(b, ", val2=0x"...)
+		b = appendHex(b, uint64(uint32(e.Value2)), 8)
+		b = append(b, ", val3=0x"...)
+		b = appendHex(b, uint64(uint32(e.Value3)), 8)
+		b = append(b, ", val4=0x"...)
+		b = appendHex(b, uint64(uint32(e.Value4)), 8)
 	}
-	return value
+	return string(b)
 }
 
 type Binary struct {
-	file *os.File
+	file   *os.File
+	mapped *Mapped
+	src    *source   // reader of the file below Wrap, replaced by Seek
+	outer  io.Reader // reader of the file returned by Wrap
+	// Wrap wraps the reader of the file, e.g. to count the read bytes
+	Wrap func(io.Reader) io.Reader
 }
 
 func convert16(data []byte) uint16 {
 	if len(data) != 2 {
 		return 0
 	}
-	return binary.LittleEndian.Uint16([]byte{data[0], data[1]})
+	return ByteOrder.Uint16(data)
 }
 
 func convert32(data []byte) uint32 {
 	if len(data) != 4 {
 		return 0
 	}
-	return binary.LittleEndian.Uint32([]byte{data[0], data[1], data[2], data[3]})
+	return ByteOrder.Uint32(data)
 }
 
 func convert64(data []byte) uint64 {
 	if len(data) != 8 {
 		return 0
 	}
-	return binary.LittleEndian.Uint64([]byte{data[0], data[1], data[2], data[3], data[4], data[5], data[6], data[7]})
+	return ByteOrder.Uint64(data)
 }
 
+// buffers of the records read, the data of an event is copied
+var recordPool = sync.Pool{New: func() any { return new([]byte) }}
+
 // get one data record
 func (e *Data) Read(in *bufio.Reader) error {
 	if in == nil {
 		return eval.ErrEof
 	}
-	a2 := make([]byte, 2)
+	buf := recordPool.Get().(*[]byte)
+	defer recordPool.Put(buf)
+	if cap(*buf) < 4 {
+		*buf = make([]byte, 4, 64)
+	}
+	a2 := (*buf)[:2]
 	_, err := io.ReadFull(in, a2)
 	if err != nil {
 		return eval.ErrEof
@@ -333,7 +631,10 @@ func (e *Data) Read(in *bufio.Reader) error {
 		return err
 	}
 	length := int(convert16(a2))
-	data := make([]byte, length)
+	if cap(*buf) < length {
+		*buf = make([]byte, length)
+	}
+	data := (*buf)[:length]
 	_, err = io.ReadFull(in, data)
 	if err != nil {
 		return err
@@ -350,7 +651,7 @@ func (e *Data) Read(in *bufio.Reader) error {
 			return eval.ErrEof
 		}
 		e.Data = new([]uint8)
-		*e.Data = data[12 : 12+int(e.Info.length)]
+		*e.Data = append([]uint8{}, data[12:12+int(e.Info.length)]...)
 	case 2: // Eventrecord2
 		if len(data) < 20 {
 			return eval.ErrEof
@@ -369,8 +670,66 @@ func (e *Data) Read(in *bufio.Reader) error {
 	return nil
 }
 
+// skip one data record, only type, time and info are read,
+// the values are read for the clock events 0xFF00 and 0xFF03 only
+func (e *Data) Skip(in *bufio.Reader) error {
+	if in == nil {
+		return eval.ErrEof
+	}
+	head := make([]byte, 16)
+	if _, err := io.ReadFull(in, head[:2]); err != nil {
+		return eval.ErrEof
+	}
+	if _, err := io.ReadFull(in, head[2:4]); err != nil {
+		return err
+	}
+	length := int(convert16(head[2:4]))
+	if length < 12 {
+		if _, err := in.Discard(length); err != nil {
+			return err
+		}
+		return eval.ErrEof
+	}
+	if _, err := io.ReadFull(in, head[4:16]); err != nil {
+		return err
+	}
+	e.Typ = convert16(head[:2])
+	e.Time = convert64(head[4:12])
+	e.Info.getInfoFromBytes(head[12:16])
+	rest := length - 12
+	if (e.Info.ID == 0xFF00 || e.Info.ID == 0xFF03) && e.Typ != 1 && rest >= 8 {
+		values := make([]byte, 8)
+		if _, err := io.ReadFull(in, values); err != nil {
+			return err
+		}
+		e.Value1 = int32(convert32(values[:4]))
+		e.Value2 = int32(convert32(values[4:]))
+		rest -= 8
+	}
+	_, err := in.Discard(rest)
+	return err
+}
+
+// read one data record completely if full returns true for its ID,
+// else it is skipped like by Skip
+func (e *Data) ReadIf(in *bufio.Reader, full func(id uint16) bool) error {
+	if in == nil {
+		return eval.ErrEof
+	}
+	if head, err := in.Peek(16); err == nil && full(convert16(head[12:14])) {
+		return e.Read(in)
+	}
+	return e.Skip(in)
+}
+
+// the values of the event are global variables of the expression,
+// events are evaluated one at a time
+var valueMu sync.Mutex
+
 func (e *Data) GetValue(value string, i *int) (eval.Value, error) {
 	if *i < len(value) && value[*i] == '[' {
+		valueMu.Lock()
+		defer valueMu.Unlock()
 		if e.Data == nil {
 			eval.SetVarI("val1", int64(e.Value1))
 			eval.SetVarI("val2", int64(e.Value2))
@@ -405,16 +764,67 @@ func (e *Data) GetValue(value string, i *int) (eval.Value, error) {
 	return eval.Value{}, eval.ErrSyntax
 }
 
-func (b *Binary) Open(filename *string) *bufio.Reader {
-	var err error
-	b.file, err = os.Open(*filename)
+// SetPayload makes the event a data record (type 1) of the bytes
+func (e *Data) SetPayload(payload []byte) {
+	e.Typ = 1
+	e.Data = &payload
+	e.Info.length = uint16(len(payload))
+}
+
+// AppendRecord appends the record of the event as read by Read,
+// the data of a type 1 record is padded to a multiple of 4 bytes
+func (e *Data) AppendRecord(buf []byte) []byte {
+	var payload []byte
+	switch e.Typ {
+	case 1:
+		if e.Data != nil {
+			payload = make([]byte, (len(*e.Data)+3)&^3)
+			copy(payload, *e.Data)
+		}
+	case 2:
+		payload = make([]byte, 8)
+	case 3:
+		payload = make([]byte, 16)
+	}
+	if e.Typ == 2 || e.Typ == 3 {
+		for i, v := range []int32{e.Value1, e.Value2, e.Value3, e.Value4}[:len(payload)/4] {
+			ByteOrder.PutUint32(payload[4*i:], uint32(v))
+		}
+	}
+	length := e.Info.length
+	if e.Info.irq {
+		length |= 0x8000
+	}
+	head := make([]byte, 16)
+	ByteOrder.PutUint16(head[0:], e.Typ)
+	ByteOrder.PutUint16(head[2:], uint16(12+len(payload)))
+	ByteOrder.PutUint64(head[4:], e.Time)
+	ByteOrder.PutUint16(head[12:], e.Info.ID)
+	ByteOrder.PutUint16(head[14:], length)
+	buf = append(buf, head...)
+	return append(buf, payload...)
+}
 
+// Open opens the log file, a file of at least MapSize bytes is memory mapped
+func (b *Binary) Open(filename *string) *bufio.Reader {
+	in, err := b.open(*filename)
 	if err != nil {
 		return nil
 	}
-	return bufio.NewReader(b.file)
+	b.src = &source{r: in}
+	b.outer = b.src
+	if b.Wrap != nil {
+		b.outer = b.Wrap(b.src)
+	}
+	return bufio.NewReader(b.outer)
 }
 
 func (b *Binary) Close() error {
+	b.src, b.outer = nil, nil
+	if b.mapped != nil {
+		err := b.mapped.Close()
+		b.mapped = nil
+		return err
+	}
 	return b.file.Close()
 }
@@ -20,10 +20,15 @@ package event
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/binary"
 	"errors"
 	"eventlist/pkg/elf"
 	"eventlist/pkg/eval"
 	"eventlist/pkg/xml/scvd"
+	"io"
+	"math"
+	"os"
 	"reflect"
 	"testing"
 )
@@ -166,6 +171,35 @@ func TestInfo_SplitID(t *testing.T) {
 	}
 }
 
+func TestInfo_Builtin(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		id   uint16
+		want bool
+	}{
+		{0xFF03, true}, {0xFE00, true}, {0xEF21, true}, {0xFE01, false}, {0x0A01, false},
+	}
+	for _, tt := range tests {
+		info := Info{ID: tt.id}
+		if got := info.Builtin(); got != tt.want {
+			t.Errorf("Info.Builtin() 0x%04X = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestInfo_SetIRQ(t *testing.T) {
+	t.Parallel()
+
+	for _, irq := range []bool{true, false} {
+		info := Info{ID: 0x1234, length: 3}
+		info.SetIRQ(irq)
+		if info.IRQ() != irq || info.length != 3 {
+			t.Errorf("Info.SetIRQ(%v) = %+v", irq, info)
+		}
+	}
+}
+
 func TestEventData_calculateExpression(t *testing.T) { //nolint:golint,paralleltest
 	var i int
 
@@ -182,6 +216,9 @@ func TestEventData_calculateExpression(t *testing.T) { //nolint:golint,parallelt
 	}
 
 	var ed1 = fields{Time: 306, Value1: 257, Value2: -24, Value3: 625478261, Value4: 0x4010, Data: nil, Info: Info{}}
+	var ed3 = fields{Time: 306, Data: &[]uint8{0xFF, 0xFF, 0x20, 0x01, 0x0D, 0xB8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}, Info: Info{}}
+	var ed4 = fields{Time: 306, Data: &[]uint8{0x01, 0x00, 0x1A, 0x2B, 0x3C, 0x4D, 0x5E}, Info: Info{}}
+	var ed2 = fields{Time: 306, Value1: 0x3FC00000, Value2: 0x40091EB8, Value3: 0x51EB851F, Value4: 0xC000, Data: nil, Info: Info{}}
 
 	type args struct {
 		value string
@@ -203,21 +240,39 @@ func TestEventData_calculateExpression(t *testing.T) { //nolint:golint,parallelt
 		{"expr x", ed1, args{"x[val1]", &i}, "0x101", 7, false},
 		{"expr F", ed1, args{"F[val4]", &i}, "def", 7, false},
 		{"expr F", ed1, args{"F[val1]", &i}, "0x00000101", 7, false},
-		{"expr C", ed1, args{"C[val2]", &i}, "", 7, true},
+		{"expr C", ed1, args{"C[val1]", &i}, "0x00000101", 7, false},
 		{"expr I", ed1, args{"I[val3]", &i}, "37.72.10.117", 7, false},
-		{"expr J", ed1, args{"J[val3]", &i}, "0:0:2548:a75:", 7, false},
+		{"expr J", ed1, args{"J[0]", &i}, "101:0:e8ff:ffff:750a:4825:1040:0", 4, false},
+		{"expr J data", ed3, args{"J[2]", &i}, "2001:db8::1", 4, false},
+		{"expr J range", ed1, args{"J[val3]", &i}, "", 7, true},
+		{"expr G", ed1, args{"G[0]", &i}, "01010000-e8ff-ffff-750a-482510400000", 4, false},
+		{"expr G data", ed3, args{"G[2]", &i}, "20010db8-0000-0000-0000-000000000001", 4, false},
+		{"expr G range", ed3, args{"G[3]", &i}, "", 4, true},
 		{"expr N", ed1, args{"N[val4]", &i}, "def", 7, false},
 		{"expr N", ed1, args{"N[val1]", &i}, "0x00000101", 7, false},
-		{"expr M", ed1, args{"M[val3]", &i}, "00-00-25-48-0a-75", 7, false},
+		{"expr M", ed1, args{"M[0]", &i}, "01-01-00-00-e8-ff", 4, false},
+		{"expr M data", ed4, args{"M[1]", &i}, "00-1a-2b-3c-4d-5e", 4, false},
+		{"expr M range", ed4, args{"M[2]", &i}, "", 4, true},
 		{"expr S", ed1, args{"S[val3]", &i}, "25480a75", 7, false},
 		{"expr ?", ed1, args{"?[val3]", &i}, "?", 7, false},
 		{"expr err1", ed1, args{"S[", &i}, "", 2, true},
 		{"expr err2", ed1, args{"S[val3,", &i}, "", 6, true},
+		{"expr T float", ed2, args{"T[val1, sensor:temp]", &i}, "1.500000", 20, false},
+		{"expr T double", ed2, args{"T[(val2 << 32) | (uint32_t)val3, sensor:value]", &i}, "3.140000", 46, false},
+		{"expr T q15", ed2, args{"T[val4, sensor:gain]", &i}, "-0.500000", 20, false},
+		{"expr T member", ed2, args{"T[val1, sensor:xxx]", &i}, "", 6, true},
+		{"expr T typedef", ed2, args{"T[val1, sensor]", &i}, "", 6, true},
 	}
 	if err := elf.Sections.Readelf(&fileTest); err != nil {
 		t.Errorf("Data.calculateExpression() cannot open %s", fileTest)
 		return
 	}
+	scvdFiles := []string{"../../testdata/test.xml"}
+	memberTypes := make(map[string]map[string]string)
+	if err := scvd.Get(&scvdFiles, make(map[uint16]scvd.Event), make(map[string]map[string]map[int16]string), memberTypes); err != nil {
+		t.Errorf("Data.calculateExpression() cannot open %s", scvdFiles[0])
+		return
+	}
 	for _, tt := range tests { //nolint:golint,paralleltest
 		t.Run(tt.name, func(t *testing.T) {
 			e := &Data{
@@ -230,7 +285,7 @@ func TestEventData_calculateExpression(t *testing.T) { //nolint:golint,parallelt
 				Info:   tt.fields.Info,
 			}
 			i = 0
-			got, err := e.calculateExpression(tt.args.value, tt.args.i)
+			got, err := e.calculateExpression(memberTypes, tt.args.value, tt.args.i)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Data.calculateExpression() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
 				return
@@ -245,6 +300,289 @@ func TestEventData_calculateExpression(t *testing.T) { //nolint:golint,parallelt
 	}
 }
 
+func TestData_getBytes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		e       Data
+		offset  uint64
+		n       int
+		want    []byte
+		wantErr bool
+	}{
+		{"data", Data{Data: &[]uint8{1, 2, 3, 4}}, 1, 2, []byte{2, 3}, false},
+		{"data end", Data{Data: &[]uint8{1, 2, 3, 4}}, 0, 4, []byte{1, 2, 3, 4}, false},
+		{"data range", Data{Data: &[]uint8{1, 2, 3, 4}}, 3, 2, nil, true},
+		{"data offset", Data{Data: &[]uint8{1, 2, 3, 4}}, 1 << 63, 2, nil, true},
+		{"val2", Data{Typ: 2, Value1: 0x04030201, Value2: 0x08070605}, 2, 6, []byte{3, 4, 5, 6, 7, 8}, false},
+		{"val2 range", Data{Typ: 2, Value1: 0x04030201, Value2: 0x08070605}, 0, 16, nil, true},
+		{"val4", Data{Typ: 3, Value3: 0x04030201, Value4: 0x08070605}, 8, 8, []byte{1, 2, 3, 4, 5, 6, 7, 8}, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.e.getBytes(tt.offset, tt.n)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Data.getBytes() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Data.getBytes() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetFloatType(t *testing.T) { //nolint:golint,paralleltest
+	tests := []struct {
+		name    string
+		typ     string
+		wantErr bool
+	}{
+		{"none", "", false},
+		{"float", "float", false},
+		{"double", "double", false},
+		{"half", "half", false},
+		{"err", "int", true},
+	}
+	for _, tt := range tests { //nolint:golint,paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			FloatType = ""
+			if err := SetFloatType(tt.typ); (err != nil) != tt.wantErr {
+				t.Errorf("SetFloatType() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if !tt.wantErr && FloatType != tt.typ {
+				t.Errorf("SetFloatType() %s = %v, want %v", tt.name, FloatType, tt.typ)
+			}
+		})
+	}
+	FloatType = ""
+}
+
+func TestSetFixedType(t *testing.T) { //nolint:golint,paralleltest
+	tests := []struct {
+		name    string
+		typ     string
+		wantErr bool
+	}{
+		{"none", "", false},
+		{"q15", "Q15", false},
+		{"q8.8", "Q7.8", false},
+		{"uq16.16", "UQ16.16", false},
+		{"err", "int", true},
+	}
+	for _, tt := range tests { //nolint:golint,paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			FixedType = ""
+			if err := SetFixedType(tt.typ); (err != nil) != tt.wantErr {
+				t.Errorf("SetFixedType() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if !tt.wantErr && FixedType != tt.typ {
+				t.Errorf("SetFixedType() %s = %v, want %v", tt.name, FixedType, tt.typ)
+			}
+		})
+	}
+	FixedType = ""
+}
+
+func TestSetByteOrder(t *testing.T) { //nolint:golint,paralleltest
+	tests := []struct {
+		name    string
+		endian  string
+		want    binary.ByteOrder
+		wantErr bool
+	}{
+		{"none", "", binary.LittleEndian, false},
+		{"little", "little", binary.LittleEndian, false},
+		{"big", "big", binary.BigEndian, false},
+		{"err", "middle", binary.LittleEndian, true},
+	}
+	for _, tt := range tests { //nolint:golint,paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			ByteOrder = binary.LittleEndian
+			if err := SetByteOrder(tt.endian); (err != nil) != tt.wantErr {
+				t.Errorf("SetByteOrder() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if ByteOrder != tt.want {
+				t.Errorf("SetByteOrder() %s = %v, want %v", tt.name, ByteOrder, tt.want)
+			}
+		})
+	}
+	ByteOrder = binary.LittleEndian
+}
+
+func TestData_SetPayload(t *testing.T) { //nolint:golint,paralleltest
+	var e Data
+	e.Info.ID = 0xFE00
+	e.SetPayload([]byte("hello"))
+	var got Data
+	if err := got.Read(bufio.NewReader(bytes.NewReader(e.AppendRecord(nil)))); err != nil {
+		t.Errorf("Data.SetPayload() error = %v", err)
+		return
+	}
+	if got.Typ != 1 || got.Info.ID != 0xFE00 || string(*got.Data) != "hello" {
+		t.Errorf("Data.SetPayload() = %d %x %q, want 1 fe00 \"hello\"", got.Typ, got.Info.ID, *got.Data)
+	}
+}
+
+func TestData_ReadBigEndian(t *testing.T) { //nolint:golint,paralleltest
+	read := func(name string) []Data {
+		file, err := os.Open(name)
+		if err != nil {
+			t.Fatalf("Data.Read() %v", err)
+		}
+		defer file.Close()
+		in := bufio.NewReader(file)
+		var records []Data
+		for {
+			var e Data
+			if err := e.Read(in); err != nil {
+				break
+			}
+			records = append(records, e)
+		}
+		return records
+	}
+	want := read("../../testdata/test.binary")
+	ByteOrder = binary.BigEndian
+	got := read("../../testdata/test_be.binary")
+	ByteOrder = binary.LittleEndian
+	if len(want) == 0 || !reflect.DeepEqual(got, want) {
+		t.Errorf("Data.Read() big endian = %v, want %v", got, want)
+	}
+}
+
+func Test_parseFixed(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		typ        string
+		wantM      int
+		wantN      int
+		wantSigned bool
+		wantOk     bool
+	}{
+		{"q7_t", "q7_t", 0, 7, true, true},
+		{"q15_t", "q15_t", 0, 15, true, true},
+		{"q31_t", "q31_t", 0, 31, true, true},
+		{"Q15", "Q15", 0, 15, true, true},
+		{"Q7.8", "Q7.8", 7, 8, true, true},
+		{"UQ16.16", "UQ16.16", 16, 16, false, true},
+		{"UQ0.8", "uq0.8", 0, 8, false, true},
+		{"too big", "Q32.32", 0, 0, false, false},
+		{"empty", "UQ0.0", 0, 0, false, false},
+		{"no number", "Qx", 0, 0, false, false},
+		{"no m", "Qx.4", 0, 0, false, false},
+		{"no Q", "15", 0, 0, false, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m, n, signed, ok := parseFixed(tt.typ)
+			if m != tt.wantM || n != tt.wantN || signed != tt.wantSigned || ok != tt.wantOk {
+				t.Errorf("parseFixed() %s = %v, %v, %v, %v, want %v, %v, %v, %v", tt.name,
+					m, n, signed, ok, tt.wantM, tt.wantN, tt.wantSigned, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_getFloat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		bits   uint64
+		typ    string
+		want   float64
+		wantOk bool
+	}{
+		{"float", 0x3FC00000, "float", 1.5, true},
+		{"float neg", 0xFFFFFFFFC0200000, "float", -2.5, true},
+		{"double", 0x400921FB54442D18, "double", 3.141592653589793, true},
+		{"half", 0x3C00, "half", 1.0, true},
+		{"half neg", 0xC500, "half", -5.0, true},
+		{"half max", 0x7BFF, "half", 65504.0, true},
+		{"half subnormal", 0x0001, "half", 5.960464477539063e-08, true},
+		{"half inf", 0x7C00, "half", math.Inf(1), true},
+		{"q15", 0x4000, "q15_t", 0.5, true},
+		{"q15 neg", 0x8000, "Q15", -1.0, true},
+		{"q31", 0x7FFFFFFF, "Q31", 0.9999999995343387, true},
+		{"q8.8", 0xFF80, "Q7.8", -0.5, true},
+		{"uq8.8", 0xFF80, "UQ8.8", 255.5, true},
+		{"none", 0x3FC00000, "", 0, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := getFloat(tt.bits, tt.typ)
+			if ok != tt.wantOk {
+				t.Errorf("getFloat() %s ok = %v, want %v", tt.name, ok, tt.wantOk)
+			}
+			if got != tt.want {
+				t.Errorf("getFloat() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventData_calculateExpressionPrecision(t *testing.T) { //nolint:golint,paralleltest
+	e := &Data{Value1: 0x4000}
+	tests := []struct {
+		name      string
+		precision int
+		fixed     string
+		want      string
+	}{
+		{"default", 6, "Q15", "0.500000"},
+		{"two", 2, "Q15", "0.50"},
+		{"shortest", -1, "Q15", "0.5"},
+		{"integer", -1, "", "16384"},
+	}
+	defer func() {
+		Precision = 6
+		FixedType = ""
+	}()
+	for _, tt := range tests { //nolint:golint,paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			i := 0
+			Precision = tt.precision
+			FixedType = tt.fixed
+			got, err := e.calculateExpression(nil, "T[val1]", &i)
+			if err != nil {
+				t.Errorf("Data.calculateExpression() %s error = %v", tt.name, err)
+			}
+			if got != tt.want {
+				t.Errorf("Data.calculateExpression() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventData_calculateExpressionStrict(t *testing.T) { //nolint:golint,paralleltest
+	e := &Data{Value1: 0x1234}
+	Strict = true
+	defer func() { Strict = false }()
+	for _, format := range []string{"t[val1]", "F[val1]", "N[val1]"} {
+		i := 0
+		if got, err := e.calculateExpression(nil, format, &i); !errors.Is(err, ErrUnresolved) {
+			t.Errorf("Data.calculateExpression() %s = %v, %v, want %v", format, got, err, ErrUnresolved)
+		}
+	}
+	i := 0
+	if got, err := e.calculateExpression(nil, "S[val1]", &i); err != nil || got != "00001234" {
+		t.Errorf("Data.calculateExpression() = %v, %v, want 00001234", got, err)
+	}
+}
+
 func TestEventData_calculateEnumExpression(t *testing.T) { //nolint:golint,paralleltest
 	var vals = make(map[int16]string)
 	var enms = make(map[string]map[int16]string)
@@ -320,8 +658,8 @@ func TestEventData_EvalLine(t *testing.T) {
 
 	var ev1 scvd.Event = scvd.Event{ID: "id1", Value: "x%%%d[val1]y%u[val2]z"}
 	var ev2 scvd.Event = scvd.Event{ID: "id2", Value: "x%T[val1]y%x[val2]z"}
-	var ev3 scvd.Event = scvd.Event{ID: "id3", Value: "x%I[val3]y%J[val3]z"}
-	var ev4 scvd.Event = scvd.Event{ID: "id4", Value: "x%M[val3]y%S[val3]z"}
+	var ev3 scvd.Event = scvd.Event{ID: "id3", Value: "x%I[val3]y%J[0]z"}
+	var ev4 scvd.Event = scvd.Event{ID: "id4", Value: "x%M[4]y%S[val3]z"}
 	var evE1 scvd.Event = scvd.Event{ID: "idE1", Value: "x%E[val2, typName]y"}
 	var everr1 scvd.Event = scvd.Event{ID: "iderr1", Value: "x%d[;]y"}
 	var everr2 scvd.Event = scvd.Event{ID: "iderr2", Value: "x%E[;]y"}
@@ -359,8 +697,8 @@ func TestEventData_EvalLine(t *testing.T) {
 	}{
 		{"EvalLine ev1", ed1, args{ev1, tds}, "x%257y4711z", false},
 		{"EvalLine ev2", ed1, args{ev2, tds}, "x257y0x1267z", false},
-		{"EvalLine ev3", ed1, args{ev3, tds}, "x37.72.10.117y0:0:2548:a75:z", false},
-		{"EvalLine ev4", ed1, args{ev4, tds}, "x00-00-25-48-0a-75y25480a75z", false},
+		{"EvalLine ev3", ed1, args{ev3, tds}, "x37.72.10.117y101:0:6712:0:750a:4825::z", false},
+		{"EvalLine ev4", ed1, args{ev4, tds}, "x67-12-00-00-75-0ay25480a75z", false},
 		{"EvalLine evE1", ed1, args{evE1, tds}, "xenumy", false},
 		{"EvalLine err1", ed1, args{everr1, tds}, "", true},
 		{"EvalLine err2", ed1, args{everr2, tds}, "", true},
@@ -379,7 +717,7 @@ func TestEventData_EvalLine(t *testing.T) {
 				Data:   tt.fields.Data,
 				Info:   tt.fields.Info,
 			}
-			got, err := e.EvalLine(tt.args.scvdevent, tt.args.typedefs)
+			got, err := e.EvalLine(tt.args.scvdevent, tt.args.typedefs, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Data.EvalLine() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -436,6 +774,33 @@ func TestData_GetValuesAsString(t *testing.T) {
 	}
 }
 
+func Test_appendHex(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		v     uint64
+		width int
+		want  string
+	}{
+		{"zero", 0, 2, "00"},
+		{"byte", 0xA, 2, "0a"},
+		{"wider", 0x1234, 2, "1234"},
+		{"word", 0xABCDEF, 8, "00abcdef"},
+		{"max", math.MaxUint64, 8, "ffffffffffffffff"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := string(appendHex([]byte("0x"), tt.v, tt.width)); got != "0x"+tt.want {
+				t.Errorf("appendHex() %s = %v, want %v", tt.name, got, "0x"+tt.want)
+			}
+		})
+	}
+}
+
 func Test_convert16(t *testing.T) {
 	t.Parallel()
 
@@ -602,6 +967,117 @@ func TestEventData_Read(t *testing.T) {
 	}
 }
 
+func TestData_Skip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		file    string
+		count   int
+		wantErr bool
+	}{
+		{"nil", "", 0, false},
+		{"empty", "../../testdata/test0.binary", 0, false},
+		{"fail1", "../../testdata/test1.binary", 0, true},
+		{"fail2", "../../testdata/test2.binary", 0, true},
+		{"ok", "../../testdata/test10.binary", 2, false},
+		{"tree", "../../testdata/tree.binary", 8, false},
+		{"clock", "../../testdata/test4.binary", 1, false},
+		{"data", "../../testdata/test3.binary", 1, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var b, bSkip Binary
+			in := b.Open(&tt.file)
+			inSkip := bSkip.Open(&tt.file)
+			count := 0
+			for {
+				var e, eSkip Data
+				err := eSkip.Skip(inSkip)
+				if errors.Is(err, eval.ErrEof) {
+					break
+				}
+				if (err != nil) != tt.wantErr {
+					t.Errorf("Data.Skip() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+				}
+				if err != nil {
+					break
+				}
+				_ = e.Read(in)
+				want := Data{Typ: e.Typ, Time: e.Time, Info: e.Info}
+				if e.Info.ID == 0xFF00 || e.Info.ID == 0xFF03 {
+					want.Value1, want.Value2 = e.Value1, e.Value2
+				}
+				if !reflect.DeepEqual(eSkip, want) {
+					t.Errorf("Data.Skip() %s = %v, want %v", tt.name, eSkip, want)
+				}
+				count++
+			}
+			if count != tt.count {
+				t.Errorf("Data.Skip() %s count = %d, want %d", tt.name, count, tt.count)
+			}
+			if in != nil {
+				b.Close()
+				bSkip.Close()
+			}
+		})
+	}
+}
+
+func TestData_ReadIf(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		file  string
+		count int
+	}{
+		{"nil", "", 0},
+		{"ok", "../../testdata/test10.binary", 2},
+		{"tree", "../../testdata/tree.binary", 8},
+		{"clock", "../../testdata/test4.binary", 1},
+	}
+	full := func(id uint16) bool { return id>>8 == 0xEF }
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var b, bIf Binary
+			in := b.Open(&tt.file)
+			inIf := bIf.Open(&tt.file)
+			count := 0
+			for {
+				var e, eIf Data
+				if err := eIf.ReadIf(inIf, full); err != nil {
+					break
+				}
+				_ = e.Read(in)
+				want := Data{Typ: e.Typ, Time: e.Time, Info: e.Info}
+				if full(e.Info.ID) {
+					want = e
+				} else if e.Info.ID == 0xFF00 || e.Info.ID == 0xFF03 {
+					want.Value1, want.Value2 = e.Value1, e.Value2
+				}
+				if !reflect.DeepEqual(eIf, want) {
+					t.Errorf("Data.ReadIf() %s = %v, want %v", tt.name, eIf, want)
+				}
+				count++
+			}
+			if count != tt.count {
+				t.Errorf("Data.ReadIf() %s count = %d, want %d", tt.name, count, tt.count)
+			}
+			if in != nil {
+				b.Close()
+				bIf.Close()
+			}
+		})
+	}
+}
+
 func TestData_GetValue(t *testing.T) { //nolint:golint,paralleltest
 	type fields struct {
 		Time   uint64
@@ -666,3 +1142,152 @@ func TestData_GetValue(t *testing.T) { //nolint:golint,paralleltest
 		})
 	}
 }
+
+func TestBinary_OpenWrap(t *testing.T) {
+	t.Parallel()
+
+	var n int
+	b := Binary{Wrap: func(r io.Reader) io.Reader {
+		return readerFunc(func(p []byte) (int, error) {
+			m, err := r.Read(p)
+			n += m
+			return m, err
+		})
+	}}
+	filename := "../../testdata/test10.binary"
+	in := b.Open(&filename)
+	if in == nil {
+		t.Fatalf("Binary.Open() = nil")
+	}
+	defer b.Close()
+	for {
+		var e Data
+		if err := e.Read(in); err != nil {
+			break
+		}
+	}
+	if n != 48 {
+		t.Errorf("Binary.Open() wrapped reader read %d bytes, want 48", n)
+	}
+}
+
+type readerFunc func(p []byte) (int, error)
+
+func (f readerFunc) Read(p []byte) (int, error) { return f(p) }
+
+func TestData_AppendRecord(t *testing.T) { //nolint:golint,paralleltest
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		ByteOrder = order
+		filename := "../../testdata/test.binary"
+		if order == binary.BigEndian {
+			filename = "../../testdata/test_be.binary"
+		}
+		want, err := os.ReadFile(filename)
+		if err != nil {
+			t.Fatalf("os.ReadFile() error = %v", err)
+		}
+		var b Binary
+		in := b.Open(&filename)
+		var got []byte
+		for {
+			var e Data
+			if err := e.Read(in); err != nil {
+				break
+			}
+			got = e.AppendRecord(got)
+		}
+		b.Close()
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Data.AppendRecord() %v = %x, want %x", order, got, want)
+		}
+	}
+	ByteOrder = binary.LittleEndian
+}
+
+func FuzzRead(f *testing.F) { //nolint:golint,paralleltest
+	for _, filename := range []string{"../../testdata/test.binary", "../../testdata/test_be.binary"} {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			f.Fatalf("os.ReadFile() error = %v", err)
+		}
+		f.Add(data)
+	}
+	f.Add([]byte{1, 0, 12, 0})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		in := bufio.NewReader(bytes.NewReader(data))
+		for {
+			var e Data
+			if err := e.Read(in); err != nil {
+				break
+			}
+			if e.Typ < 1 || e.Typ > 3 {
+				continue
+			}
+			// a record read must read back the same
+			var got Data
+			if err := got.Read(bufio.NewReader(bytes.NewReader(e.AppendRecord(nil)))); err != nil {
+				t.Fatalf("Data.Read() of %x error = %v", e.AppendRecord(nil), err)
+			}
+			if got.Time != e.Time || got.Info != e.Info || got.Value1 != e.Value1 || got.Value4 != e.Value4 {
+				t.Errorf("Data.Read() = %v, want %v", got, e)
+			}
+		}
+		in = bufio.NewReader(bytes.NewReader(data))
+		for {
+			var e Data
+			// a short record is skipped with ErrEof, the end has nothing buffered
+			err := e.Skip(in)
+			if err != nil && (!errors.Is(err, eval.ErrEof) || in.Buffered() == 0) {
+				break
+			}
+		}
+	})
+}
+
+// records of all types for the benchmarks
+func benchRecords(n int) []byte {
+	var data []byte
+	for i := 0; i < n; i++ {
+		e := Data{Time: uint64(10 * i), Typ: uint16(i%3 + 1), Value1: int32(i), Value2: -1, Value3: 2, Value4: 3}
+		e.Info.ID = 0x0A01
+		if e.Typ == 1 {
+			e.SetPayload([]byte("hello\n"))
+		}
+		data = e.AppendRecord(data)
+	}
+	return data
+}
+
+func BenchmarkData_Read(b *testing.B) {
+	data := benchRecords(1000)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		in := bufio.NewReader(bytes.NewReader(data))
+		for {
+			var e Data
+			if err := e.Read(in); err != nil {
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkData_EvalLine(b *testing.B) {
+	ev := scvd.Event{Value: "a=%d[val1] b=%x[val2] ip=%I[val4]"}
+	e := Data{Typ: 3, Value1: -5, Value2: 0xAB, Value4: 0x0A000001}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := e.EvalLine(ev, nil, nil); err != nil {
+			b.Fatalf("Data.EvalLine() error = %v", err)
+		}
+	}
+}
+
+func BenchmarkData_GetValuesAsString(b *testing.B) {
+	e := Data{Typ: 3, Value1: -0x8000, Value2: 0x12345678, Value3: 0xABCDEF, Value4: 0x76543210}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = e.GetValuesAsString()
+	}
+}
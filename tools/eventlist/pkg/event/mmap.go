@@ -0,0 +1,154 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package event
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+)
+
+// MapSize is the size from which a log file is memory mapped by Open,
+// a negative size never maps the file
+var MapSize int64 = 16 << 20
+
+// Mapped is a log file mapped into memory, the records are read without
+// system calls and can be accessed at any offset. Where mapping is not
+// supported the file is read at the offsets instead.
+type Mapped struct {
+	file *os.File
+	data []byte // nil if the file is not mapped
+	size int64
+}
+
+// Map maps the file into memory
+func Map(filename string) (*Mapped, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	m := &Mapped{file: file, size: info.Size()}
+	if m.size > 0 && int64(int(m.size)) == m.size {
+		m.data, _ = mmap(file, int(m.size)) // falls back to reading the file on failure
+	}
+	return m, nil
+}
+
+// Size returns the size of the file
+func (m *Mapped) Size() int64 {
+	return m.size
+}
+
+// Bytes returns the mapped bytes of the file, nil if it is not mapped
+func (m *Mapped) Bytes() []byte {
+	return m.data
+}
+
+// ReadAt reads the bytes at the offset of the file
+func (m *Mapped) ReadAt(p []byte, off int64) (int, error) {
+	if m.data == nil {
+		return m.file.ReadAt(p, off)
+	}
+	if off < 0 {
+		return 0, os.ErrInvalid
+	}
+	if off >= m.size {
+		return 0, io.EOF
+	}
+	n := copy(p, m.data[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Reader returns a reader of the records from the offset of the file
+func (m *Mapped) Reader(offset int64) io.Reader {
+	if m.data != nil && offset >= 0 && offset <= m.size {
+		return bytes.NewReader(m.data[offset:])
+	}
+	return io.NewSectionReader(m, offset, m.size-offset)
+}
+
+// Close unmaps and closes the file
+func (m *Mapped) Close() error {
+	var err error
+	if m.data != nil {
+		err = munmap(m.data)
+		m.data = nil
+	}
+	if cerr := m.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// open the file for reading the records, large files are memory mapped
+func (b *Binary) open(filename string) (io.Reader, error) {
+	if MapSize >= 0 {
+		if info, err := os.Stat(filename); err == nil && info.Size() >= MapSize {
+			if b.mapped, err = Map(filename); err != nil {
+				return nil, err
+			}
+			return b.mapped.Reader(0), nil
+		}
+	}
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	b.file = file
+	return file, nil
+}
+
+// source reads the file from the position set by Seek
+type source struct {
+	r io.Reader
+}
+
+func (s *source) Read(p []byte) (int, error) {
+	return s.r.Read(p)
+}
+
+// Seek positions in, the reader returned by Open, at the offset of the
+// file, returns false if the file cannot be positioned
+func (b *Binary) Seek(in *bufio.Reader, offset int64) bool {
+	if b.src == nil {
+		return false
+	}
+	switch {
+	case b.mapped != nil:
+		b.src.r = b.mapped.Reader(offset)
+	case b.file != nil:
+		if _, err := b.file.Seek(offset, io.SeekStart); err != nil {
+			return false
+		}
+		b.src.r = b.file
+	default:
+		return false
+	}
+	in.Reset(b.outer)
+	return true
+}
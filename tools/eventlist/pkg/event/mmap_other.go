@@ -0,0 +1,35 @@
+//go:build !unix
+
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package event
+
+import (
+	"errors"
+	"os"
+)
+
+// mmap is not supported, the file is read at the offsets instead
+func mmap(_ *os.File, _ int) ([]byte, error) {
+	return nil, errors.New("memory mapping not supported")
+}
+
+func munmap(_ []byte) error {
+	return nil
+}
@@ -0,0 +1,206 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package event
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestMap(t *testing.T) {
+	t.Parallel()
+
+	filename := "../../testdata/test.binary"
+	want, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("os.ReadFile() error = %v", err)
+	}
+	m, err := Map(filename)
+	if err != nil {
+		t.Fatalf("Map() error = %v", err)
+	}
+	defer m.Close()
+	if m.Size() != int64(len(want)) {
+		t.Errorf("Map() size = %d, want %d", m.Size(), len(want))
+	}
+	if m.Bytes() != nil && !bytes.Equal(m.Bytes(), want) {
+		t.Errorf("Map() bytes = %x, want %x", m.Bytes(), want)
+	}
+
+	tests := []struct {
+		name    string
+		off     int64
+		n       int
+		want    []byte
+		wantErr error
+	}{
+		{"start", 0, 4, want[:4], nil},
+		{"record", 16, 8, want[16:24], nil},
+		{"end", int64(len(want)) - 2, 4, want[len(want)-2:], io.EOF},
+		{"behind", int64(len(want)), 4, []byte{}, io.EOF},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) { //nolint:paralleltest
+			p := make([]byte, tt.n)
+			n, err := m.ReadAt(p, tt.off)
+			if err != tt.wantErr { //nolint:errorlint
+				t.Errorf("Mapped.ReadAt() %s error = %v, want %v", tt.name, err, tt.wantErr)
+			}
+			if !bytes.Equal(p[:n], tt.want) {
+				t.Errorf("Mapped.ReadAt() %s = %x, want %x", tt.name, p[:n], tt.want)
+			}
+		})
+	}
+
+	got, err := io.ReadAll(m.Reader(16))
+	if err != nil || !bytes.Equal(got, want[16:]) {
+		t.Errorf("Mapped.Reader() = %x, %v, want %x", got, err, want[16:])
+	}
+}
+
+func TestBinary_OpenMapped(t *testing.T) { //nolint:golint,paralleltest
+	filename := "../../testdata/test.binary"
+	read := func() []Data {
+		var b Binary
+		in := b.Open(&filename)
+		if in == nil {
+			t.Fatalf("Binary.Open() = nil")
+		}
+		var events []Data
+		for {
+			var e Data
+			if err := e.Read(in); err != nil {
+				break
+			}
+			events = append(events, e)
+		}
+		if err := b.Close(); err != nil {
+			t.Errorf("Binary.Close() error = %v", err)
+		}
+		return events
+	}
+	MapSize = -1
+	want := read()
+	MapSize = 0
+	got := read()
+	MapSize = 16 << 20
+	if len(got) == 0 || len(got) != len(want) {
+		t.Fatalf("Binary.Open() mapped %d events, want %d", len(got), len(want))
+	}
+	for i := range got {
+		if got[i].Time != want[i].Time || got[i].Info != want[i].Info || got[i].Value1 != want[i].Value1 {
+			t.Errorf("Binary.Open() mapped event %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestBinary_Seek(t *testing.T) { //nolint:golint,paralleltest
+	filename := "../../testdata/test.binary"
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("os.ReadFile() error = %v", err)
+	}
+	second := int64(4 + ByteOrder.Uint16(data[2:4])) // offset of the second record
+	defer func() { MapSize = 16 << 20 }()
+	for _, size := range []int64{-1, 0} {
+		MapSize = size
+		var b Binary
+		in := b.Open(&filename)
+		var first, want, got Data
+		if err := first.Read(in); err != nil {
+			t.Fatalf("Data.Read() error = %v", err)
+		}
+		if err := want.Read(in); err != nil {
+			t.Fatalf("Data.Read() error = %v", err)
+		}
+		if !b.Seek(in, second) {
+			t.Errorf("Binary.Seek() map size %d = false, want true", size)
+		}
+		if err := got.Read(in); err != nil || got.Time != want.Time || got.Info != want.Info {
+			t.Errorf("Binary.Seek() map size %d read %v, %v, want %v", size, got, err, want)
+		}
+		if err := b.Close(); err != nil {
+			t.Errorf("Binary.Close() error = %v", err)
+		}
+		if b.Seek(in, 0) {
+			t.Errorf("Binary.Seek() after Close = true, want false")
+		}
+	}
+}
+
+// offset of the records behind the hole of the sparse capture
+const sparseOffset = 5 << 30
+
+// write a sparse capture of more than 4 GiB, a record at the start and
+// two records behind a hole, where files are not sparse the test is skipped
+func sparseCapture(t *testing.T) string {
+	t.Helper()
+	if testing.Short() || runtime.GOOS == "windows" {
+		t.Skip("sparse capture of more than 4 GiB")
+	}
+	name := filepath.Join(t.TempDir(), "sparse.binary")
+	file, err := os.Create(name)
+	if err != nil {
+		t.Fatalf("os.Create() error = %v", err)
+	}
+	defer file.Close()
+	first := Data{Typ: 2, Time: 1, Info: Info{ID: 0x0A01}}
+	var last []byte
+	for i := 2; i <= 3; i++ {
+		e := Data{Typ: 2, Time: uint64(i) << 32, Value1: int32(i), Info: Info{ID: 0x0A02}}
+		last = e.AppendRecord(last)
+	}
+	if _, err = file.Write(first.AppendRecord(nil)); err == nil {
+		_, err = file.WriteAt(last, sparseOffset)
+	}
+	if err != nil {
+		t.Fatalf("File.WriteAt() error = %v", err)
+	}
+	return name
+}
+
+func TestMap_sparse(t *testing.T) { //nolint:golint,paralleltest
+	name := sparseCapture(t)
+	m, err := Map(name)
+	if err != nil {
+		t.Fatalf("Map() error = %v", err)
+	}
+	defer m.Close()
+	if m.Size() <= 4<<30 {
+		t.Errorf("Map() size = %d, want more than 4 GiB", m.Size())
+	}
+	read := func(m *Mapped) {
+		var e Data
+		if err := e.Read(bufio.NewReader(m.Reader(sparseOffset))); err != nil || e.Time != 2<<32 || e.Value1 != 2 {
+			t.Errorf("Mapped.Reader() at 5 GiB = %v, %v, want time %d", e, err, uint64(2<<32))
+		}
+		p := make([]byte, 2)
+		if _, err := m.ReadAt(p, sparseOffset+2); err != nil || p[0] != 20 {
+			t.Errorf("Mapped.ReadAt() at 5 GiB = %v, %v, want length 20", p, err)
+		}
+	}
+	read(m)
+	read(&Mapped{file: m.file, size: m.size}) // not mapped
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package event
+
+import (
+	"bufio"
+	"io"
+	"os"
+)
+
+// IDs of the clock events of the Event Recorder
+const (
+	IDInitialize uint16 = 0xFF00 // EventRecorderInitialize: val2 is the timestamp frequency
+	IDClock      uint16 = 0xFF03 // EventRecorderClock: val1 is the timestamp frequency
+	IDGap        uint16 = 0xFF0E // gap of the live events: val1 is the number of lost records, val2 of skipped bytes
+	IDReset      uint16 = 0xFF0F // reset of the target by the probe, not recorded by the target
+)
+
+// Writer appends Event Recorder records to a file or an io.Writer, the
+// records are read back by Data.Read. The methods are named after the
+// functions of the Event Recorder recording the events.
+type Writer struct {
+	out   *bufio.Writer
+	file  *os.File
+	buf   []byte
+	Count int // number of written records
+}
+
+// NewWriter creates a writer appending the records to w
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{out: bufio.NewWriter(w)}
+}
+
+// Create creates the file of a writer
+func Create(filename string) (*Writer, error) {
+	file, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+	w := NewWriter(file)
+	w.file = file
+	return w, nil
+}
+
+// Write appends the record of an event
+func (w *Writer) Write(e *Data) error {
+	w.buf = e.AppendRecord(w.buf[:0])
+	if _, err := w.out.Write(w.buf); err != nil {
+		return err
+	}
+	w.Count++
+	return nil
+}
+
+// EventRecord2 appends a record of two values
+func (w *Writer) EventRecord2(time uint64, id uint16, val1 int32, val2 int32) error {
+	e := Data{Typ: 2, Time: time, Value1: val1, Value2: val2}
+	e.Info.ID = id
+	return w.Write(&e)
+}
+
+// EventRecord4 appends a record of four values
+func (w *Writer) EventRecord4(time uint64, id uint16, val1 int32, val2 int32, val3 int32, val4 int32) error {
+	e := Data{Typ: 3, Time: time, Value1: val1, Value2: val2, Value3: val3, Value4: val4}
+	e.Info.ID = id
+	return w.Write(&e)
+}
+
+// EventRecordData appends a data record, e.g. of a string
+func (w *Writer) EventRecordData(time uint64, id uint16, data []byte) error {
+	e := Data{Time: time}
+	e.Info.ID = id
+	e.SetPayload(data)
+	return w.Write(&e)
+}
+
+// Clock appends an EventRecorderInitialize event setting the timestamp
+// frequency in Hz, the events after it are decoded with the frequency
+func (w *Writer) Clock(time uint64, frequency uint32) error {
+	return w.EventRecord2(time, IDInitialize, 0, int32(frequency))
+}
+
+// Flush writes the buffered records
+func (w *Writer) Flush() error {
+	return w.out.Flush()
+}
+
+// Close flushes the records and closes the file of Create
+func (w *Writer) Close() error {
+	err := w.Flush()
+	if w.file != nil {
+		if closeErr := w.file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package event
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"eventlist/pkg/eval"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWriter_Write(t *testing.T) { //nolint:golint,paralleltest
+	filename := "../../testdata/test.binary"
+	want, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("os.ReadFile() error = %v", err)
+	}
+	var b Binary
+	in := b.Open(&filename)
+	var got bytes.Buffer
+	w := NewWriter(&got)
+	for {
+		var e Data
+		if err := e.Read(in); err != nil {
+			break
+		}
+		if err := w.Write(&e); err != nil {
+			t.Errorf("Writer.Write() error = %v", err)
+		}
+	}
+	b.Close()
+	if err = w.Flush(); err != nil {
+		t.Errorf("Writer.Flush() error = %v", err)
+	}
+	if !reflect.DeepEqual(got.Bytes(), want) {
+		t.Errorf("Writer.Write() = %x, want %x", got.Bytes(), want)
+	}
+	if w.Count != 4 {
+		t.Errorf("Writer.Count = %d, want 4", w.Count)
+	}
+}
+
+func TestWriter_EventRecord(t *testing.T) { //nolint:golint,paralleltest
+	filename := filepath.Join(t.TempDir(), "out.binary")
+	w, err := Create(filename)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	_ = w.Clock(0, 1000000)
+	_ = w.EventRecord2(10, 0xEF00, 1, -2)
+	_ = w.EventRecord4(20, 0xF001, 1, 2, 3, 4)
+	_ = w.EventRecordData(30, 0xFE00, []byte("hello"))
+	if err = w.Close(); err != nil {
+		t.Errorf("Writer.Close() error = %v", err)
+	}
+
+	type rec struct {
+		typ    uint16
+		time   uint64
+		id     uint16
+		values [4]int32
+		data   string
+	}
+	want := []rec{
+		{2, 0, IDInitialize, [4]int32{0, 1000000}, ""},
+		{2, 10, 0xEF00, [4]int32{1, -2}, ""},
+		{3, 20, 0xF001, [4]int32{1, 2, 3, 4}, ""},
+		{1, 30, 0xFE00, [4]int32{}, "hello"},
+	}
+	var b Binary
+	in := b.Open(&filename)
+	defer b.Close()
+	var got []rec
+	for {
+		var e Data
+		if err := e.Read(in); err != nil {
+			if !errors.Is(err, eval.ErrEof) {
+				t.Errorf("Data.Read() error = %v", err)
+			}
+			break
+		}
+		r := rec{e.Typ, e.Time, e.Info.ID, [4]int32{e.Value1, e.Value2, e.Value3, e.Value4}, ""}
+		if e.Data != nil {
+			r.data = string(*e.Data)
+		}
+		got = append(got, r)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Writer.EventRecord() = %v, want %v", got, want)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	t.Parallel()
+
+	if _, err := Create(filepath.Join(t.TempDir(), "nix", "out.binary")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Create() error = %v, want %v", err, os.ErrNotExist)
+	}
+}
+
+func TestWriter_error(t *testing.T) {
+	t.Parallel()
+
+	w := NewWriter(bufio.NewWriterSize(failWriter{}, 16))
+	var err error
+	for i := 0; i < 1000 && err == nil; i++ {
+		err = w.EventRecord2(uint64(i), 1, 0, 0)
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	if !errors.Is(err, errFail) {
+		t.Errorf("Writer.EventRecord2() error = %v, want %v", err, errFail)
+	}
+}
+
+var errFail = errors.New("fail")
+
+type failWriter struct{}
+
+func (failWriter) Write([]byte) (int, error) {
+	return 0, errFail
+}
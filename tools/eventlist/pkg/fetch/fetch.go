@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package fetch downloads remote files, e.g. the SCVD files of a vendor,
+// into a cache directory, so later runs need no network access.
+package fetch
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var errChecksum = errors.New("checksum mismatch")
+var errInsecure = errors.New("http URL without #sha256= checksum")
+var errStatus = errors.New("download failed")
+var errSize = errors.New("remote file too large")
+
+// CacheDir is the directory of the downloaded files, empty uses the
+// directory eventlist in the cache directory of the user
+var CacheDir string
+
+// Client downloads the files
+var Client = &http.Client{Timeout: 30 * time.Second}
+
+// MaxSize is the maximum size of a downloaded file
+var MaxSize int64 = 64 << 20
+
+// IsURL returns true for an http(s) URL
+func IsURL(name string) bool {
+	return strings.HasPrefix(name, "https://") || strings.HasPrefix(name, "http://")
+}
+
+// Dir returns the cache directory
+func Dir() (string, error) {
+	if len(CacheDir) != 0 {
+		return CacheDir, nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "eventlist"), nil
+}
+
+// Get returns the name of the cached file of a URL and downloads it if it is
+// not cached yet. A fragment #sha256=<hex> pins the checksum of the file,
+// the cached and the downloaded file must match it. http URLs require the
+// checksum.
+func Get(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	pin := strings.ToLower(strings.TrimPrefix(u.Fragment, "sha256="))
+	if !strings.HasPrefix(u.Fragment, "sha256=") {
+		pin = ""
+	}
+	u.Fragment = ""
+	if u.Scheme == "http" && len(pin) == 0 {
+		return "", fmt.Errorf("%w: %s", errInsecure, rawURL)
+	}
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	key := sha256.Sum256([]byte(u.String()))
+	name := filepath.Join(dir, hex.EncodeToString(key[:8])+"-"+path.Base(u.Path))
+
+	if data, err := os.ReadFile(name); err == nil {
+		if len(pin) == 0 || checksum(data) == pin {
+			return name, nil
+		}
+	}
+	data, err := download(u.String())
+	if err != nil {
+		return "", err
+	}
+	if sum := checksum(data); len(pin) != 0 && sum != pin {
+		return "", fmt.Errorf("%w: %s: sha256 %s, want %s", errChecksum, u, sum, pin)
+	}
+	return name, write(name, data)
+}
+
+// download returns the content of a URL
+func download(rawURL string) ([]byte, error) {
+	resp, err := Client.Get(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s: %s", errStatus, rawURL, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > MaxSize {
+		return nil, fmt.Errorf("%w: %s", errSize, rawURL)
+	}
+	return data, nil
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// write a file of the cache, a temporary file is renamed, so a concurrent
+// run never reads a partial file
+func write(name string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0700); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(name), ".fetch-*")
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	if e := file.Close(); err == nil {
+		err = e
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), name)
+	}
+	if err != nil {
+		os.Remove(file.Name())
+	}
+	return err
+}
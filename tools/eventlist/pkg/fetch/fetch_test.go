@@ -0,0 +1,117 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fetch
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const scvd = "<component_viewer/>\n"
+
+// checksum of another content
+const otherSum = "1f2b4c1d0ac1bb6c8d33cdb0d28a25b8b8a7b41a27c27f6d38aa3ae1d8d4b6f1"
+
+func TestGet(t *testing.T) { //nolint:golint,paralleltest
+	downloads := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/RTX5.scvd" {
+			http.NotFound(w, r)
+			return
+		}
+		downloads++
+		_, _ = w.Write([]byte(scvd))
+	}))
+	defer server.Close()
+	savedClient, savedDir := Client, CacheDir
+	defer func() { Client, CacheDir = savedClient, savedDir }()
+	Client = server.Client()
+	CacheDir = t.TempDir()
+	sum := checksum([]byte(scvd))
+
+	name, err := Get(server.URL + "/RTX5.scvd")
+	if err != nil || !strings.HasSuffix(name, "-RTX5.scvd") || filepath.Dir(name) != CacheDir {
+		t.Fatalf("Get() = %s, %v, want file in the cache", name, err)
+	}
+	if data, _ := os.ReadFile(name); string(data) != scvd {
+		t.Errorf("Get() file = %q, want %q", data, scvd)
+	}
+	if cached, err := Get(server.URL + "/RTX5.scvd#sha256=" + strings.ToUpper(sum)); err != nil || cached != name || downloads != 1 {
+		t.Errorf("Get() cached = %s, %v, %d downloads, want %s", cached, err, downloads, name)
+	}
+
+	// a changed cache file is downloaded again
+	_ = os.WriteFile(name, []byte("changed"), 0600)
+	if _, err := Get(server.URL + "/RTX5.scvd#sha256=" + sum); err != nil || downloads != 2 {
+		t.Errorf("Get() error = %v, %d downloads, want 2", err, downloads)
+	}
+
+	tests := []struct {
+		name    string
+		url     string
+		wantErr error
+	}{
+		{"checksum", server.URL + "/RTX5.scvd#sha256=" + otherSum, errChecksum},
+		{"status", server.URL + "/nix.scvd", errStatus},
+		{"http", "http://localhost/RTX5.scvd", errInsecure},
+	}
+	for _, tt := range tests { //nolint:golint,paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Get(tt.url); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Get() %s error = %v, want %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+
+	saved := MaxSize
+	defer func() { MaxSize = saved }()
+	MaxSize = 4
+	CacheDir = t.TempDir()
+	if _, err := Get(server.URL + "/RTX5.scvd"); !errors.Is(err, errSize) {
+		t.Errorf("Get() error = %v, want %v", err, errSize)
+	}
+}
+
+func TestIsURL(t *testing.T) {
+	t.Parallel()
+
+	for name, want := range map[string]bool{"https://x/a.scvd": true, "http://x/a.scvd": true, "a.scvd": false, "C:\\a.scvd": false} {
+		if got := IsURL(name); got != want {
+			t.Errorf("IsURL() %s = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestDir(t *testing.T) { //nolint:golint,paralleltest
+	saved := CacheDir
+	defer func() { CacheDir = saved }()
+	CacheDir = "cache"
+	if dir, err := Dir(); dir != "cache" || err != nil {
+		t.Errorf("Dir() = %s, %v, want cache", dir, err)
+	}
+	CacheDir = ""
+	if dir, err := Dir(); err == nil && filepath.Base(dir) != "eventlist" {
+		t.Errorf("Dir() = %s, want .../eventlist", dir)
+	}
+}
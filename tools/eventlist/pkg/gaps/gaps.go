@@ -0,0 +1,156 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gaps finds the largest gaps between consecutive events and the
+// silent time without events, so a hanging or sleeping target or a stalled
+// capture is found.
+package gaps
+
+import (
+	"eventlist/pkg/bus"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Gap is the time between two consecutive events
+type Gap struct {
+	Start  float64 // time of the event before the gap
+	End    float64 // time of the event after the gap
+	Before int     // index of the event before the gap
+	After  int     // index of the event after the gap
+}
+
+// Duration returns the length of the gap in s
+func (g Gap) Duration() float64 {
+	return g.End - g.Start
+}
+
+// Finder keeps the largest gaps and sums the gaps longer than MinGap
+type Finder struct {
+	count   int     // number of kept gaps
+	minGap  float64 // shortest gap counted as silent time in s
+	largest []Gap   // largest gaps, longest first
+	silent  float64 // sum of the gaps longer than minGap
+	gaps    int     // number of the gaps longer than minGap
+	events  int
+	first   float64
+	last    float64
+	index   int  // index of the event at last
+	valid   bool // last can be compared with the next event
+}
+
+// New creates the finder of the count largest gaps, gaps longer than
+// minGap are silent time
+func New(count int, minGap float64) *Finder {
+	return &Finder{count: count, minGap: minGap}
+}
+
+// Event compares the time of an event with the latest event before, a
+// clock event changes the time base, so its timestamp is not compared
+func (f *Finder) Event(ev *bus.Event) error {
+	f.events++
+	if f.events == 1 || ev.Time < f.first {
+		f.first = ev.Time
+	}
+	if id := ev.Data.Info.ID; id == 0xFF00 || id == 0xFF03 {
+		f.last, f.index, f.valid = ev.Time, ev.Index, false
+		return nil
+	}
+	if f.valid {
+		if diff := ev.Time - f.last; diff > 0 {
+			f.add(Gap{Start: f.last, End: ev.Time, Before: f.index, After: ev.Index})
+		}
+	}
+	if !f.valid || ev.Time >= f.last {
+		f.last, f.index = ev.Time, ev.Index
+	}
+	f.valid = true
+	return nil
+}
+
+// add a gap to the silent time and to the largest gaps
+func (f *Finder) add(g Gap) {
+	d := g.Duration()
+	if d > f.minGap {
+		f.silent += d
+		f.gaps++
+	}
+	if f.count <= 0 {
+		return
+	}
+	if len(f.largest) == f.count && d <= f.largest[len(f.largest)-1].Duration() {
+		return
+	}
+	i := len(f.largest)
+	for i > 0 && f.largest[i-1].Duration() < d {
+		i--
+	}
+	if len(f.largest) < f.count {
+		f.largest = append(f.largest, Gap{})
+	}
+	copy(f.largest[i+1:], f.largest[i:])
+	f.largest[i] = g
+}
+
+// End has nothing to finish
+func (f *Finder) End() error {
+	return nil
+}
+
+// Gaps returns the largest gaps, longest first
+func (f *Finder) Gaps() []Gap {
+	return f.largest
+}
+
+// Silent returns the sum and the number of the gaps longer than the
+// shortest silent gap
+func (f *Finder) Silent() (float64, int) {
+	return f.silent, f.gaps
+}
+
+// Report writes the silent time and the largest gaps with the indexes of
+// the events around them
+func (f *Finder) Report(w io.Writer) error {
+	title := "Timeline gaps"
+	if _, err := fmt.Fprintf(w, "   %s\n   %s\n\n", title, strings.Repeat("-", len(title))); err != nil {
+		return err
+	}
+	duration := f.last - f.first
+	ratio := 0.0
+	if duration > 0 {
+		ratio = f.silent / duration * 100
+	}
+	if _, err := fmt.Fprintf(w, "Silent time: %.8f s in %d gaps longer than %g s, %.1f%% of %.8f s\n",
+		f.silent, f.gaps, f.minGap, ratio, duration); err != nil {
+		return err
+	}
+	if len(f.largest) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintf(w, "\n%12s %12s %12s %8s %8s\n", "Gap (s)", "From (s)", "To (s)", "Before", "After"); err != nil {
+		return err
+	}
+	for _, g := range f.largest {
+		if _, err := fmt.Fprintf(w, "%12.8f %12.8f %12.8f %8d %8d\n",
+			g.Duration(), g.Start, g.End, g.Before, g.After); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gaps
+
+import (
+	"bytes"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/event"
+	"reflect"
+	"testing"
+)
+
+type record struct {
+	id   uint16
+	time float64
+}
+
+func run(t *testing.T, f *Finder, records []record) {
+	t.Helper()
+	for i, r := range records {
+		ev := bus.NewEvent(i, r.time, &event.Data{Info: event.Info{ID: r.id}}, nil, nil)
+		if err := f.Event(ev); err != nil {
+			t.Errorf("Finder.Event() error = %v", err)
+		}
+	}
+	if err := f.End(); err != nil {
+		t.Errorf("Finder.End() error = %v", err)
+	}
+}
+
+func TestFinder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		count   int
+		records []record
+		want    []Gap
+		silent  float64
+		gaps    int
+	}{
+		{"none", 3, nil, nil, 0, 0},
+		{"largest", 2, []record{{1, 0}, {1, 0.5}, {1, 0.75}, {1, 2}, {1, 2.125}},
+			[]Gap{{0.75, 2, 2, 3}, {0, 0.5, 0, 1}}, 2.125, 4},
+		{"short", 3, []record{{1, 0}, {1, 0.0625}, {1, 0.125}},
+			[]Gap{{0, 0.0625, 0, 1}, {0.0625, 0.125, 1, 2}}, 0, 0},
+		{"clock", 3, []record{{1, 0}, {0xFF03, 5}, {1, 5.25}, {1, 5.5}},
+			[]Gap{{5.25, 5.5, 2, 3}}, 0.25, 1},
+		{"backwards", 3, []record{{1, 1}, {1, 0.5}, {1, 1.5}},
+			[]Gap{{1, 1.5, 0, 2}}, 0.5, 1},
+		{"report only", 0, []record{{1, 0}, {1, 1}}, nil, 1, 1},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := New(tt.count, 0.1)
+			run(t, f, tt.records)
+			if got := f.Gaps(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Finder.Gaps() %s = %v, want %v", tt.name, got, tt.want)
+			}
+			if silent, gaps := f.Silent(); silent != tt.silent || gaps != tt.gaps {
+				t.Errorf("Finder.Silent() %s = %v, %v, want %v, %v", tt.name, silent, gaps, tt.silent, tt.gaps)
+			}
+		})
+	}
+}
+
+func TestFinder_Report(t *testing.T) {
+	t.Parallel()
+
+	f := New(1, 0.1)
+	run(t, f, []record{{1, 0}, {1, 0.5}, {1, 0.75}, {1, 2}})
+	var b bytes.Buffer
+	if err := f.Report(&b); err != nil {
+		t.Errorf("Finder.Report() error = %v", err)
+	}
+	want := "   Timeline gaps\n   -------------\n\n" +
+		"Silent time: 2.00000000 s in 3 gaps longer than 0.1 s, 100.0% of 2.00000000 s\n\n" +
+		"     Gap (s)     From (s)       To (s)   Before    After\n" +
+		"  1.25000000   0.75000000   2.00000000        2        3\n"
+	if got := b.String(); got != want {
+		t.Errorf("Finder.Report() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,187 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package generate
+
+import (
+	"errors"
+	"eventlist/pkg/event"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+var errScenario = errors.New("invalid scenario")
+
+// DefaultClock is the timestamp frequency in Hz without clock event,
+// the decoder assumes it without clock event too
+const DefaultClock = 25000000
+
+// Stream is an event repeated in a timing pattern
+type Stream struct {
+	ID     uint16  `yaml:"id"`
+	Values []int64 `yaml:"values"` // up to 2 values give a record of 2 values, 3 or 4 of 4 values
+	Step   []int64 `yaml:"step"`   // added to the values on every repetition
+	Data   string  `yaml:"data"`   // payload of a data record instead of values
+	Start  float64 `yaml:"start"`  // time of the first event in s
+	Period float64 `yaml:"period"` // time between the repetitions in s
+	Jitter float64 `yaml:"jitter"` // random deviation of the times up to +-jitter in s
+	Count  int     `yaml:"count"`  // number of events, default 1
+}
+
+// Overflow drops records like an overflow of the recorder buffer
+type Overflow struct {
+	At   float64 `yaml:"at"`   // time of the first dropped record in s
+	Drop int     `yaml:"drop"` // number of dropped records
+}
+
+// Scenario describes the events of a generated capture
+type Scenario struct {
+	Seed      int64      `yaml:"seed"`  // seed of the jitter
+	Clock     uint32     `yaml:"clock"` // timestamp frequency in Hz, written as clock event
+	Wrap      bool       `yaml:"wrap"`  // timestamps of a 32 bit counter wrapping around
+	Events    []Stream   `yaml:"events"`
+	Overflows []Overflow `yaml:"overflows"`
+}
+
+// Load reads a scenario from a YAML file
+func Load(filename string) (*Scenario, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var s Scenario
+	if err = yaml.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	if err = s.check(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (s *Scenario) check() error {
+	for i := range s.Events {
+		e := &s.Events[i]
+		switch {
+		case len(e.Values) > 4:
+			return fmt.Errorf("%w: event 0x%04X: more than 4 values", errScenario, e.ID)
+		case len(e.Step) > len(e.Values):
+			return fmt.Errorf("%w: event 0x%04X: more steps than values", errScenario, e.ID)
+		case e.Data != "" && len(e.Values) != 0:
+			return fmt.Errorf("%w: event 0x%04X: data and values", errScenario, e.ID)
+		case len(e.Data) > 0x7FFF:
+			return fmt.Errorf("%w: event 0x%04X: data too long", errScenario, e.ID)
+		case e.Count < 0 || e.Start < 0 || e.Period < 0 || e.Jitter < 0:
+			return fmt.Errorf("%w: event 0x%04X: negative count or time", errScenario, e.ID)
+		}
+	}
+	for _, o := range s.Overflows {
+		if o.At < 0 || o.Drop < 0 {
+			return fmt.Errorf("%w: negative overflow", errScenario)
+		}
+	}
+	return nil
+}
+
+// record is a generated event with its time in s
+type record struct {
+	time float64
+	data event.Data
+}
+
+// records returns the events of the scenario ordered by time, events of
+// the same time keep the order of the streams
+func (s *Scenario) records() []record {
+	rnd := rand.New(rand.NewSource(s.Seed)) //nolint:gosec
+	var records []record
+	for _, e := range s.Events {
+		count := e.Count
+		if count == 0 {
+			count = 1
+		}
+		values := make([]int64, 4)
+		copy(values, e.Values)
+		for i := 0; i < count; i++ {
+			t := e.Start + float64(i)*e.Period
+			if e.Jitter > 0 {
+				t += (rnd.Float64()*2 - 1) * e.Jitter
+			}
+			var data event.Data
+			switch {
+			case e.Data != "":
+				data.SetPayload([]byte(e.Data))
+			case len(e.Values) <= 2:
+				data.Typ = 2
+			default:
+				data.Typ = 3
+			}
+			data.Info.ID = e.ID
+			data.Value1, data.Value2, data.Value3, data.Value4 =
+				int32(values[0]), int32(values[1]), int32(values[2]), int32(values[3])
+			for j, step := range e.Step {
+				values[j] += step
+			}
+			records = append(records, record{time: math.Max(t, 0), data: data})
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].time < records[j].time })
+
+	for _, o := range s.Overflows {
+		dropped := 0
+		kept := records[:0]
+		for _, r := range records {
+			if r.time >= o.At && dropped < o.Drop {
+				dropped++
+				continue
+			}
+			kept = append(kept, r)
+		}
+		records = kept
+	}
+	return records
+}
+
+// Write writes the capture of the scenario and returns the number of
+// written records, a clock event at time 0 precedes the events if the
+// scenario has a clock
+func (s *Scenario) Write(w io.Writer) (int, error) {
+	out := event.NewWriter(w)
+	clock := float64(DefaultClock)
+	if s.Clock != 0 {
+		clock = float64(s.Clock)
+		if err := out.Clock(0, s.Clock); err != nil {
+			return 0, err
+		}
+	}
+	for _, r := range s.records() {
+		r.data.Time = uint64(math.Round(r.time * clock))
+		if s.Wrap {
+			r.data.Time &= math.MaxUint32
+		}
+		if err := out.Write(&r.data); err != nil {
+			return out.Count, err
+		}
+	}
+	return out.Count, out.Flush()
+}
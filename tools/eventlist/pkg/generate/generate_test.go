@@ -0,0 +1,169 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package generate
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"eventlist/pkg/eval"
+	"eventlist/pkg/event"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"ok", "events:\n  - id: 0xEF00\n    values: [1, 2, 3]\n    count: 2\n", false},
+		{"values", "events:\n  - id: 1\n    values: [1, 2, 3, 4, 5]\n", true},
+		{"step", "events:\n  - id: 1\n    values: [1]\n    step: [1, 2]\n", true},
+		{"data", "events:\n  - id: 1\n    values: [1]\n    data: x\n", true},
+		{"count", "events:\n  - id: 1\n    count: -1\n", true},
+		{"period", "events:\n  - id: 1\n    period: -1\n", true},
+		{"overflow", "overflows:\n  - at: 1\n    drop: -1\n", true},
+		{"yaml", "events: 3\n", true},
+	}
+	for i, tt := range tests {
+		tt := tt
+		filename := filepath.Join(dir, fmt.Sprintf("s%d.yaml", i))
+		_ = os.WriteFile(filename, []byte(tt.content), 0600)
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Load(filename)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Load() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+	if _, err := Load(filepath.Join(dir, "nix.yaml")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Load() nix error = %v, want %v", err, os.ErrNotExist)
+	}
+}
+
+// read returns the records written by the scenario
+func read(t *testing.T, s *Scenario) []event.Data {
+	var b bytes.Buffer
+	n, err := s.Write(&b)
+	if err != nil {
+		t.Errorf("Scenario.Write() error = %v", err)
+	}
+	var records []event.Data
+	rd := bufio.NewReader(&b)
+	for {
+		var data event.Data
+		if err = data.Read(rd); err != nil {
+			if !errors.Is(err, eval.ErrEof) {
+				t.Errorf("Read() error = %v", err)
+			}
+			break
+		}
+		records = append(records, data)
+	}
+	if n != len(records) {
+		t.Errorf("Scenario.Write() = %d, read %d records", n, len(records))
+	}
+	return records
+}
+
+func TestScenario_Write(t *testing.T) {
+	t.Parallel()
+
+	type rec struct {
+		id     uint16
+		typ    uint16
+		time   uint64
+		value1 int32
+	}
+	tests := []struct {
+		name     string
+		scenario Scenario
+		want     []rec
+	}{
+		{"clock", Scenario{Clock: 1000, Events: []Stream{{ID: 0x10, Values: []int64{7}, Start: 0.5}}},
+			[]rec{{0xFF00, 2, 0, 0}, {0x10, 2, 500, 7}}},
+		{"repeat", Scenario{Clock: 1000, Events: []Stream{{ID: 0x10, Values: []int64{1, 2, 3}, Step: []int64{2}, Period: 0.1, Count: 3}}},
+			[]rec{{0xFF00, 2, 0, 0}, {0x10, 3, 0, 1}, {0x10, 3, 100, 3}, {0x10, 3, 200, 5}}},
+		{"order", Scenario{Events: []Stream{{ID: 1, Start: 2e-6, Period: 4e-6, Count: 2}, {ID: 2, Start: 4e-6}, {ID: 3, Start: 2e-6}}},
+			[]rec{{1, 2, 50, 0}, {3, 2, 50, 0}, {2, 2, 100, 0}, {1, 2, 150, 0}}},
+		{"overflow", Scenario{Clock: 1000, Events: []Stream{{ID: 1, Period: 0.1, Count: 5}}, Overflows: []Overflow{{At: 0.1, Drop: 2}}},
+			[]rec{{0xFF00, 2, 0, 0}, {1, 2, 0, 0}, {1, 2, 300, 0}, {1, 2, 400, 0}}},
+		{"wrap", Scenario{Clock: 1000000, Wrap: true, Events: []Stream{{ID: 1, Start: 5000}}},
+			[]rec{{0xFF00, 2, 0, 0}, {1, 2, 5000000000 & 0xFFFFFFFF, 0}}},
+		{"data", Scenario{Clock: 1000, Events: []Stream{{ID: 0xFE00, Data: "hi"}}},
+			[]rec{{0xFF00, 2, 0, 0}, {0xFE00, 1, 0, 0}}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []rec
+			for _, r := range read(t, &tt.scenario) {
+				got = append(got, rec{r.Info.ID, r.Typ, r.Time, r.Value1})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Scenario.Write() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScenario_jitter(t *testing.T) {
+	t.Parallel()
+
+	s := Scenario{Seed: 3, Clock: 1000000, Events: []Stream{{ID: 1, Start: 1, Period: 0.01, Jitter: 0.001, Count: 50}}}
+	first := read(t, &s)
+	if !reflect.DeepEqual(read(t, &s), first) {
+		t.Errorf("Scenario.Write() is not reproducible")
+	}
+	for i, r := range first[1:] {
+		nominal := 1000000 + uint64(i)*10000
+		if r.Time+1000 < nominal || r.Time > nominal+1000 {
+			t.Errorf("Scenario.Write() time %d = %d, want %d +-1000", i, r.Time, nominal)
+		}
+	}
+	s.Seed = 4
+	if reflect.DeepEqual(read(t, &s), first) {
+		t.Errorf("Scenario.Write() seed 4 = seed 3")
+	}
+}
+
+func TestLoad_testdata(t *testing.T) {
+	t.Parallel()
+
+	s, err := Load("../../testdata/scenario.yaml")
+	if err != nil {
+		t.Errorf("Load() error = %v", err)
+		return
+	}
+	if got := len(read(t, s)); got != 20 {
+		t.Errorf("Scenario.Write() = %d records, want 20", got)
+	}
+}
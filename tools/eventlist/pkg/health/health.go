@@ -0,0 +1,197 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package health
+
+import (
+	"encoding/json"
+	"eventlist/pkg/bus"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// GapTime is the shortest time without events counted as gap in s
+var GapTime = 0.1
+
+// Summary is the health of a capture, the score is the product of the
+// coverage, the part of the capture time without gaps, the part of the
+// events without errors and the part of the timestamps going forward
+// in percent, a capture without clock event loses 10 percent
+type Summary struct {
+	Score      int      `json:"score"` // 0 to 100
+	Verdict    string   `json:"verdict"`
+	Events     int      `json:"events"`
+	Duration   float64  `json:"duration"` // time from the first to the last event in s
+	Coverage   float64  `json:"coverage"` // part of the events without clock events with SCVD definition
+	Gaps       int      `json:"gaps"`
+	GapRatio   float64  `json:"gapRatio"` // part of the duration in gaps
+	Errors     int      `json:"errors"`
+	ErrorRatio float64  `json:"errorRatio"` // part of the events with level Error
+	Backwards  int      `json:"backwards"`  // timestamps before the previous one
+	Clock      bool     `json:"clock"`      // a clock event was recorded
+	Hints      []string `json:"hints"`
+}
+
+// Health collects the summary of the event stream and writes it as
+// JSON file at the end if a file name is given
+type Health struct {
+	filename  string
+	events    int
+	clocks    int // clock events, they have no SCVD definition
+	defined   int
+	errors    int
+	backwards int
+	gaps      int
+	gapTime   float64
+	first     float64
+	last      float64
+	clock     bool
+}
+
+// New creates the health analyzer, an empty filename writes no file
+func New(filename string) *Health {
+	return &Health{filename: filename}
+}
+
+// Event counts the event, a clock event changes the time base,
+// so its timestamp is not compared with the previous one
+func (h *Health) Event(ev *bus.Event) error {
+	h.events++
+	if id := ev.Data.Info.ID; id == 0xFF00 || id == 0xFF03 {
+		h.clock = true
+		h.clocks++
+		if h.events == 1 || ev.Time < h.first {
+			h.first = ev.Time
+		}
+		h.last = ev.Time
+		return nil
+	}
+	if h.events == 1 {
+		h.first, h.last = ev.Time, ev.Time
+	} else {
+		diff := ev.Time - h.last
+		switch {
+		case diff < 0:
+			h.backwards++
+		case diff > GapTime:
+			h.gaps++
+			h.gapTime += diff
+		}
+		if ev.Time > h.last {
+			h.last = ev.Time
+		}
+	}
+	if ev.Def != nil {
+		h.defined++
+	}
+	if ev.Level() == "Error" {
+		h.errors++
+	}
+	return nil
+}
+
+// End writes the JSON file
+func (h *Health) End() error {
+	if len(h.filename) == 0 {
+		return nil
+	}
+	data, err := json.MarshalIndent(h.Summary(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(h.filename, append(data, '\n'), 0600)
+}
+
+// Summary returns the health of the events seen so far
+func (h *Health) Summary() Summary {
+	s := Summary{Events: h.events, Gaps: h.gaps, Errors: h.errors, Backwards: h.backwards,
+		Clock: h.clock, Hints: []string{}}
+	if h.events == 0 {
+		s.Verdict = "empty"
+		s.Hints = append(s.Hints, "the capture contains no events")
+		return s
+	}
+	s.Duration = h.last - h.first
+	s.Coverage = 1
+	if h.events > h.clocks {
+		s.Coverage = float64(h.defined) / float64(h.events-h.clocks)
+	}
+	if s.Duration > 0 {
+		s.GapRatio = h.gapTime / s.Duration
+	}
+	s.ErrorRatio = float64(h.errors) / float64(h.events)
+	score := s.Coverage * (1 - s.GapRatio) * (1 - s.ErrorRatio) * (1 - float64(h.backwards)/float64(h.events))
+	if !h.clock {
+		score *= 0.9
+	}
+	s.Score = int(score*100 + 0.5)
+	switch {
+	case s.Score >= 80:
+		s.Verdict = "good"
+	case s.Score >= 50:
+		s.Verdict = "fair"
+	default:
+		s.Verdict = "poor"
+	}
+
+	if s.Coverage < 1 {
+		s.Hints = append(s.Hints, fmt.Sprintf("%d events have no SCVD definition, add the SCVD files with -I", h.events-h.clocks-h.defined))
+	}
+	if h.gaps != 0 {
+		s.Hints = append(s.Hints, fmt.Sprintf("%d gaps longer than %g s, check the recorder buffer and the transfer rate", h.gaps, GapTime))
+	}
+	if h.errors != 0 {
+		s.Hints = append(s.Hints, fmt.Sprintf("%d error events, see the events with -l Error", h.errors))
+	}
+	if h.backwards != 0 {
+		s.Hints = append(s.Hints, fmt.Sprintf("%d timestamps go backwards, the records may be corrupted or the timer wrapped", h.backwards))
+	}
+	if !h.clock {
+		s.Hints = append(s.Hints, "no clock event recorded, set the timestamp clock with --profile")
+	}
+	if s.Verdict == "poor" {
+		s.Hints = append(s.Hints, "consider re-taking the capture")
+	}
+	return s
+}
+
+// Report writes the summary
+func (h *Health) Report(out io.Writer) error {
+	s := h.Summary()
+	title := "Capture health"
+	timestamps := "monotonic"
+	if s.Backwards != 0 {
+		timestamps = fmt.Sprintf("%d going backwards", s.Backwards)
+	}
+	if !s.Clock {
+		timestamps += ", no clock event"
+	}
+	_, err := fmt.Fprintf(out, "   %s\n   %s\n\nScore: %d/100 (%s)\n"+
+		"Events: %d in %.8f s\nCoverage: %.1f%% with SCVD definition\n"+
+		"Gaps: %d, %.1f%% of the time\nErrors: %d, %.1f%% of the events\nTimestamps: %s\n",
+		title, strings.Repeat("-", len(title)), s.Score, s.Verdict,
+		s.Events, s.Duration, s.Coverage*100, s.Gaps, s.GapRatio*100, s.Errors, s.ErrorRatio*100, timestamps)
+	for _, hint := range s.Hints {
+		if err == nil {
+			_, err = fmt.Fprintf(out, "  - %s\n", hint)
+		}
+	}
+	return err
+}
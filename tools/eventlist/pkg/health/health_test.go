@@ -0,0 +1,139 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package health
+
+import (
+	"bytes"
+	"encoding/json"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/event"
+	"eventlist/pkg/xml/scvd"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func ev(time float64, id uint16, level string) *bus.Event {
+	var def *scvd.Event
+	if level != "-" {
+		def = &scvd.Event{Level: level}
+	}
+	return bus.NewEvent(0, time, &event.Data{Info: event.Info{ID: id}}, def, nil)
+}
+
+func TestHealth_Summary(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		events []*bus.Event
+		want   Summary
+	}{
+		{"empty", nil, Summary{Verdict: "empty", Hints: []string{"the capture contains no events"}}},
+		{"good", []*bus.Event{ev(0, 0xFF00, "-"), ev(0.01, 0x1000, "Op"), ev(0.02, 0x1001, "Op")},
+			Summary{Score: 100, Verdict: "good", Events: 3, Duration: 0.02, Coverage: 1, Clock: true, Hints: []string{}}},
+		{"no clock", []*bus.Event{ev(0.01, 0x1000, "Op"), ev(0.02, 0x1001, "Op")},
+			Summary{Score: 90, Verdict: "good", Events: 2, Duration: 0.01, Coverage: 1,
+				Hints: []string{"no clock event recorded, set the timestamp clock with --profile"}}},
+		{"clock back", []*bus.Event{ev(0.5, 0x1000, "Op"), ev(0.001, 0xFF03, "-"), ev(0.002, 0x1001, "Op")},
+			Summary{Score: 100, Verdict: "good", Events: 3, Duration: 0.001, Coverage: 1, Clock: true, Hints: []string{}}},
+		{"poor", []*bus.Event{ev(0, 0xFF00, "-"), ev(0.05, 0x1000, "-"), ev(0.5, 0x1001, "Error"),
+			ev(0.4, 0x1001, "Op"), ev(1.0, 0x1001, "Op")},
+			Summary{Score: 2, Verdict: "poor", Events: 5, Duration: 1.0, Coverage: 0.75, Gaps: 2, GapRatio: 0.95,
+				Errors: 1, ErrorRatio: 0.2, Backwards: 1, Clock: true, Hints: []string{
+					"1 events have no SCVD definition, add the SCVD files with -I",
+					"2 gaps longer than 0.1 s, check the recorder buffer and the transfer rate",
+					"1 error events, see the events with -l Error",
+					"1 timestamps go backwards, the records may be corrupted or the timer wrapped",
+					"consider re-taking the capture",
+				}}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := New("")
+			for _, e := range tt.events {
+				if err := h.Event(e); err != nil {
+					t.Fatalf("Health.Event() error = %v", err)
+				}
+			}
+			if err := h.End(); err != nil {
+				t.Errorf("Health.End() error = %v", err)
+			}
+			if got := h.Summary(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Health.Summary() %s = %+v, want %+v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHealth_End(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "health.json")
+	h := New(filename)
+	_ = h.Event(ev(0, 0xFF00, "-"))
+	_ = h.Event(ev(0.01, 0x1000, "Op"))
+	if err := h.End(); err != nil {
+		t.Fatalf("Health.End() error = %v", err)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("os.ReadFile() error = %v", err)
+	}
+	var got Summary
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, h.Summary()) {
+		t.Errorf("Health.End() = %+v, want %+v", got, h.Summary())
+	}
+
+	h = New(filepath.Join(t.TempDir(), "nix", "health.json"))
+	if err := h.End(); err == nil {
+		t.Errorf("Health.End() error = nil, want error")
+	}
+}
+
+func TestHealth_Report(t *testing.T) {
+	t.Parallel()
+
+	h := New("")
+	_ = h.Event(ev(0.01, 0x1000, "Op"))
+	_ = h.Event(ev(0.005, 0x1001, "-"))
+	var b bytes.Buffer
+	if err := h.Report(&b); err != nil {
+		t.Fatalf("Health.Report() error = %v", err)
+	}
+	want := "   Capture health\n   --------------\n\n" +
+		"Score: 23/100 (poor)\n" +
+		"Events: 2 in 0.00000000 s\n" +
+		"Coverage: 50.0% with SCVD definition\n" +
+		"Gaps: 0, 0.0% of the time\n" +
+		"Errors: 0, 0.0% of the events\n" +
+		"Timestamps: 1 going backwards, no clock event\n" +
+		"  - 1 events have no SCVD definition, add the SCVD files with -I\n"
+	if !strings.HasPrefix(b.String(), want) || !strings.HasSuffix(b.String(), "  - consider re-taking the capture\n") {
+		t.Errorf("Health.Report() = %q, want %q", b.String(), want)
+	}
+}
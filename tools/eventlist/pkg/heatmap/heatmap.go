@@ -0,0 +1,269 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package heatmap
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"eventlist/pkg/bus"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+var errFormat = errors.New("heatmap file must be .csv, .json or .png")
+var errBuckets = errors.New("number of heatmap buckets must be positive")
+
+// DefaultBuckets is the number of time buckets if not specified
+const DefaultBuckets = 100
+
+const (
+	cellWidth  = 8
+	cellHeight = 14
+	margin     = 4
+)
+
+// Heatmap counts the events per event type and time bucket and writes
+// the counts as CSV table, JSON matrix or PNG image at the end of the event
+// stream.
+type Heatmap struct {
+	PerComponent bool // one row per component instead of per event type
+	filename     string
+	buckets      int
+	labels       []string       // rows in order of appearance
+	rowOf        map[string]int // row index of a label
+	times        []float64
+	rows         []int32
+}
+
+// Grid is the result of the bucketing, Counts[row][bucket]
+type Grid struct {
+	Labels []string
+	Start  float64
+	Width  float64 // time span of one bucket
+	Counts [][]int
+	Max    int
+}
+
+// New creates a heatmap written to filename, the extension selects the format
+func New(filename string, buckets int) (*Heatmap, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv", ".json", ".png":
+	default:
+		return nil, fmt.Errorf("%w: %s", errFormat, filename)
+	}
+	if buckets <= 0 {
+		return nil, fmt.Errorf("%w: %d", errBuckets, buckets)
+	}
+	return &Heatmap{filename: filename, buckets: buckets, rowOf: make(map[string]int)}, nil
+}
+
+// Event records time and type of an event, the row is "component/property"
+// or the component
+func (h *Heatmap) Event(ev *bus.Event) error {
+	label := ev.Component()
+	if !h.PerComponent {
+		label += "/" + ev.Property()
+	}
+	row, ok := h.rowOf[label]
+	if !ok {
+		row = len(h.labels)
+		h.rowOf[label] = row
+		h.labels = append(h.labels, label)
+	}
+	h.times = append(h.times, ev.Time)
+	h.rows = append(h.rows, int32(row))
+	return nil
+}
+
+// End writes the heatmap file
+func (h *Heatmap) End() error {
+	file, err := os.Create(h.filename)
+	if err != nil {
+		return err
+	}
+	out := bufio.NewWriter(file)
+	grid := h.Grid()
+	switch strings.ToLower(filepath.Ext(h.filename)) {
+	case ".png":
+		err = grid.WritePNG(out)
+	case ".json":
+		err = grid.WriteJSON(out)
+	default:
+		err = grid.WriteCSV(out)
+	}
+	if err == nil {
+		err = out.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Grid distributes the recorded events over the time buckets
+func (h *Heatmap) Grid() *Grid {
+	g := &Grid{Labels: h.labels, Counts: make([][]int, len(h.labels))}
+	for i := range g.Counts {
+		g.Counts[i] = make([]int, h.buckets)
+	}
+	if len(h.times) == 0 {
+		return g
+	}
+	start, end := h.times[0], h.times[0]
+	for _, t := range h.times {
+		start = math.Min(start, t)
+		end = math.Max(end, t)
+	}
+	g.Start = start
+	g.Width = (end - start) / float64(h.buckets)
+	for i, t := range h.times {
+		bucket := 0
+		if g.Width > 0 {
+			bucket = int((t - start) / g.Width)
+			if bucket >= h.buckets { // the last event belongs to the last bucket
+				bucket = h.buckets - 1
+			}
+		}
+		row := g.Counts[h.rows[i]]
+		row[bucket]++
+		if row[bucket] > g.Max {
+			g.Max = row[bucket]
+		}
+	}
+	return g
+}
+
+// WriteCSV writes one line per event type, the header holds the bucket start times
+func (g *Grid) WriteCSV(w io.Writer) error {
+	c := csv.NewWriter(w)
+	buckets := 0
+	if len(g.Counts) > 0 {
+		buckets = len(g.Counts[0])
+	}
+	header := []string{"event"}
+	for i := 0; i < buckets; i++ {
+		header = append(header, strconv.FormatFloat(g.Start+float64(i)*g.Width, 'f', 8, 64))
+	}
+	if err := c.Write(header); err != nil {
+		return err
+	}
+	for i, label := range g.Labels {
+		record := []string{label}
+		for _, n := range g.Counts[i] {
+			record = append(record, strconv.Itoa(n))
+		}
+		if err := c.Write(record); err != nil {
+			return err
+		}
+	}
+	c.Flush()
+	return c.Error()
+}
+
+// WriteJSON writes the counts as matrix with one row per event type and the
+// start times of the buckets, e.g. for the heatmap of a plotting library
+//
+//	{"start":1,"width":0.5,"times":[1,1.5],"labels":["STDIO/stdout"],"counts":[[3,0]],"max":3}
+func (g *Grid) WriteJSON(w io.Writer) error {
+	buckets := 0
+	if len(g.Counts) > 0 {
+		buckets = len(g.Counts[0])
+	}
+	m := struct {
+		Start  float64   `json:"start"`
+		Width  float64   `json:"width"`
+		Times  []float64 `json:"times"`
+		Labels []string  `json:"labels"`
+		Counts [][]int   `json:"counts"`
+		Max    int       `json:"max"`
+	}{Start: g.Start, Width: g.Width, Times: make([]float64, buckets),
+		Labels: g.Labels, Counts: g.Counts, Max: g.Max}
+	if m.Labels == nil {
+		m.Labels = []string{}
+	}
+	for i := range m.Times {
+		m.Times[i] = g.Start + float64(i)*g.Width
+	}
+	return json.NewEncoder(w).Encode(&m)
+}
+
+// color of a cell, the counts are scaled logarithmically
+func (g *Grid) color(n int) color.RGBA {
+	if n == 0 || g.Max == 0 {
+		return color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}
+	}
+	f := math.Log1p(float64(n)) / math.Log1p(float64(g.Max))
+	mix := func(a, b uint8) uint8 { return uint8(float64(a) + f*(float64(b)-float64(a))) }
+	return color.RGBA{mix(0xFF, 0xBD), mix(0xED, 0x00), mix(0xA0, 0x26), 0xFF}
+}
+
+// WritePNG draws the grid with the event types as row labels
+// and the time range of the capture below the cells
+func (g *Grid) WritePNG(w io.Writer) error {
+	face := basicfont.Face7x13
+	labelWidth := 0
+	for _, label := range g.Labels {
+		if n := font.MeasureString(face, label).Ceil(); n > labelWidth {
+			labelWidth = n
+		}
+	}
+	buckets := 0
+	if len(g.Counts) > 0 {
+		buckets = len(g.Counts[0])
+	}
+	left := margin + labelWidth + margin
+	width := left + buckets*cellWidth + margin
+	height := margin + len(g.Labels)*cellHeight + cellHeight + margin
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	d := font.Drawer{Dst: img, Src: image.Black, Face: face}
+	text := func(x, y int, s string) {
+		d.Dot = fixed.P(x, y)
+		d.DrawString(s)
+	}
+	for row, label := range g.Labels {
+		top := margin + row*cellHeight
+		text(margin, top+cellHeight-3, label)
+		for bucket, n := range g.Counts[row] {
+			cell := image.Rect(left+bucket*cellWidth, top, left+(bucket+1)*cellWidth, top+cellHeight)
+			draw.Draw(img, cell, &image.Uniform{g.color(n)}, image.Point{}, draw.Src)
+		}
+	}
+	bottom := margin + len(g.Labels)*cellHeight + cellHeight - 2
+	text(left, bottom, fmt.Sprintf("%.6f s", g.Start))
+	end := fmt.Sprintf("%.6f s", g.Start+float64(buckets)*g.Width)
+	text(width-margin-font.MeasureString(face, end).Ceil(), bottom, end)
+	return png.Encode(w, img)
+}
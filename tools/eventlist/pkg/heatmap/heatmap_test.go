@@ -0,0 +1,209 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package heatmap
+
+import (
+	"bytes"
+	"errors"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/event"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func ev(time float64, id uint16) *bus.Event {
+	return bus.NewEvent(0, time, &event.Data{Info: event.Info{ID: id}}, nil, nil)
+}
+
+func build(buckets int, events ...*bus.Event) *Heatmap {
+	h, _ := New("x.csv", buckets)
+	for _, e := range events {
+		_ = h.Event(e)
+	}
+	return h
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		filename string
+		buckets  int
+		wantErr  error
+	}{
+		{"csv", "heat.csv", 10, nil},
+		{"png", "heat.PNG", 10, nil},
+		{"json", "heat.json", 10, nil},
+		{"format", "heat.txt", 10, errFormat},
+		{"buckets", "heat.csv", 0, errBuckets},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(tt.filename, tt.buckets)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("New() %s error = %v, want %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHeatmap_Grid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		h      *Heatmap
+		labels []string
+		counts [][]int
+		width  float64
+		max    int
+	}{
+		{"empty", build(2), nil, [][]int{}, 0, 0},
+		{"one", build(2, ev(1.0, 0x1000)), []string{"0x10/0x1000"}, [][]int{{1, 0}}, 0, 1},
+		{"spread", build(2, ev(1.0, 0x1000), ev(1.5, 0x2001), ev(2.0, 0x1000), ev(3.0, 0x1000)),
+			[]string{"0x10/0x1000", "0x20/0x2001"}, [][]int{{1, 2}, {1, 0}}, 1.0, 2},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			g := tt.h.Grid()
+			if !reflect.DeepEqual(g.Labels, tt.labels) || !reflect.DeepEqual(g.Counts, tt.counts) ||
+				g.Width != tt.width || g.Max != tt.max {
+				t.Errorf("Heatmap.Grid() %s = %v %v %v %v, want %v %v %v %v", tt.name,
+					g.Labels, g.Counts, g.Width, g.Max, tt.labels, tt.counts, tt.width, tt.max)
+			}
+		})
+	}
+}
+
+func TestGrid_WriteCSV(t *testing.T) {
+	t.Parallel()
+
+	g := build(2, ev(1.0, 0x1000), ev(1.5, 0x2001), ev(3.0, 0x1000)).Grid()
+	want := "event,1.00000000,2.00000000\n0x10/0x1000,1,1\n0x20/0x2001,1,0\n"
+	var b bytes.Buffer
+	if err := g.WriteCSV(&b); err != nil {
+		t.Errorf("Grid.WriteCSV() error = %v", err)
+	}
+	if b.String() != want {
+		t.Errorf("Grid.WriteCSV() = %v, want %v", b.String(), want)
+	}
+}
+
+func TestGrid_WriteJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		g    *Grid
+		want string
+	}{
+		{"empty", build(2).Grid(), `{"start":0,"width":0,"times":[],"labels":[],"counts":[],"max":0}` + "\n"},
+		{"grid", build(2, ev(1.0, 0x1000), ev(1.5, 0x2001), ev(3.0, 0x1000)).Grid(),
+			`{"start":1,"width":1,"times":[1,2],"labels":["0x10/0x1000","0x20/0x2001"],"counts":[[1,1],[1,0]],"max":1}` + "\n"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var b bytes.Buffer
+			if err := tt.g.WriteJSON(&b); err != nil || b.String() != tt.want {
+				t.Errorf("Grid.WriteJSON() %s = %v, %v, want %v", tt.name, b.String(), err, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeatmap_PerComponent(t *testing.T) {
+	t.Parallel()
+
+	h := build(2)
+	h.PerComponent = true
+	for _, e := range []*bus.Event{ev(1.0, 0x1000), ev(1.5, 0x2001), ev(3.0, 0x1001)} {
+		_ = h.Event(e)
+	}
+	g := h.Grid()
+	if want := []string{"0x10", "0x20"}; !reflect.DeepEqual(g.Labels, want) ||
+		!reflect.DeepEqual(g.Counts, [][]int{{1, 1}, {1, 0}}) {
+		t.Errorf("Heatmap.Grid() = %v %v, want %v", g.Labels, g.Counts, want)
+	}
+}
+
+func TestGrid_WritePNG(t *testing.T) {
+	t.Parallel()
+
+	g := build(4, ev(1.0, 0x1000), ev(1.5, 0x2001), ev(3.0, 0x1000)).Grid()
+	var b bytes.Buffer
+	if err := g.WritePNG(&b); err != nil {
+		t.Errorf("Grid.WritePNG() error = %v", err)
+	}
+	img, err := png.Decode(&b)
+	if err != nil {
+		t.Errorf("Grid.WritePNG() decode error = %v", err)
+		return
+	}
+	if got := img.Bounds().Dy(); got != 2*margin+3*cellHeight {
+		t.Errorf("Grid.WritePNG() height = %v, want %v", got, 2*margin+3*cellHeight)
+	}
+	x := img.Bounds().Dx() - margin - 4*cellWidth + cellWidth/2 // first bucket
+	if r, _, _, _ := img.At(x, margin+cellHeight/2).RGBA(); r>>8 != 0xBD {
+		t.Errorf("Grid.WritePNG() color = %x, want %x", r>>8, 0xBD)
+	}
+}
+
+func TestHeatmap_End(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	tests := []struct {
+		name     string
+		filename string
+		wantErr  bool
+	}{
+		{"csv", filepath.Join(dir, "heat.csv"), false},
+		{"png", filepath.Join(dir, "heat.png"), false},
+		{"json", filepath.Join(dir, "heat.json"), false},
+		{"dir", filepath.Join(dir, "nix", "heat.csv"), true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h, _ := New(tt.filename, 2)
+			_ = h.Event(ev(1.0, 0x1000))
+			if err := h.End(); (err != nil) != tt.wantErr {
+				t.Errorf("Heatmap.End() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if _, err := os.Stat(tt.filename); (err != nil) != tt.wantErr {
+				t.Errorf("Heatmap.End() %s stat error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+}
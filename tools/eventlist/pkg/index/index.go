@@ -0,0 +1,211 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package index writes and reads the index file next to a log file. The
+// index holds blocks of consecutive records with their file offset, the
+// range of the timestamps and the set of components, so that a later run
+// can seek to a record or over blocks without decoding the records before.
+package index
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+)
+
+// DefaultBlockSize is the number of records of a block
+const DefaultBlockSize = 1024
+
+const version = 1
+
+var magic = [4]byte{'E', 'V', 'I', 'X'}
+
+var ErrFormat = errors.New("invalid index file")
+var ErrStale = errors.New("index file does not match the log file")
+
+// Clock is the time base of the decoder before a record
+type Clock struct {
+	Time   uint64  // timestamp of the last clock event
+	Before float64 // time in seconds at the last clock event
+	Factor float64 // seconds per tick set by the last clock event, 0 for none
+}
+
+// Block describes consecutive records of the log file
+type Block struct {
+	Offset     int64     // file offset of the first record
+	Record     int64     // number of the first record
+	Count      int64     // number of records
+	First      uint64    // timestamp of the first record
+	Last       uint64    // timestamp of the last record
+	Components [4]uint64 // set of the component numbers, the high byte of the IDs
+	Clock      Clock     // time base before the first record
+}
+
+// HasComponent returns true if a record of the block belongs to the component
+func (b *Block) HasComponent(no uint8) bool {
+	return b.Components[no>>6]&(1<<(no&63)) != 0
+}
+
+type header struct {
+	Magic     [4]byte
+	Version   uint32
+	Size      int64 // size of the log file
+	ModTime   int64 // modification time of the log file in ns
+	Records   int64
+	BlockSize int64
+	Blocks    int64
+}
+
+// Index of a log file
+type Index struct {
+	Size      int64 // size of the log file
+	ModTime   int64 // modification time of the log file in ns
+	Records   int64
+	BlockSize int64
+	Blocks    []Block
+}
+
+// Name returns the name of the index file of a log file
+func Name(logFile string) string {
+	return logFile + ".idx"
+}
+
+// End returns the file offset after the records of block i
+func (ix *Index) End(i int) int64 {
+	if i+1 < len(ix.Blocks) {
+		return ix.Blocks[i+1].Offset
+	}
+	return ix.Size
+}
+
+// Save writes the index file of the log file
+func (ix *Index) Save(logFile string) error {
+	info, err := os.Stat(logFile)
+	if err != nil {
+		return err
+	}
+	ix.Size, ix.ModTime = info.Size(), info.ModTime().UnixNano()
+	file, err := os.Create(Name(logFile))
+	if err != nil {
+		return err
+	}
+	out := bufio.NewWriter(file)
+	h := header{Magic: magic, Version: version, Size: ix.Size, ModTime: ix.ModTime,
+		Records: ix.Records, BlockSize: ix.BlockSize, Blocks: int64(len(ix.Blocks))}
+	err = binary.Write(out, binary.LittleEndian, &h)
+	if err == nil {
+		err = binary.Write(out, binary.LittleEndian, ix.Blocks)
+	}
+	if err == nil {
+		err = out.Flush()
+	}
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Load reads the index file of the log file, ErrStale is returned
+// if the log file changed since the index was written
+func Load(logFile string) (*Index, error) {
+	file, err := os.Open(Name(logFile))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	ix, err := Read(file)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(logFile)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() != ix.Size || info.ModTime().UnixNano() != ix.ModTime {
+		return nil, ErrStale
+	}
+	return ix, nil
+}
+
+// Read reads an index, the blocks must be consistent with each other
+func Read(r io.Reader) (*Index, error) {
+	var h header
+	if err := binary.Read(r, binary.LittleEndian, &h); err != nil {
+		return nil, ErrFormat
+	}
+	if h.Magic != magic || h.Version != version || h.Blocks < 0 || h.Size < 0 {
+		return nil, ErrFormat
+	}
+	// every record has at least 16 bytes, every block at least one record
+	if h.Records > h.Size/16 || h.Blocks > h.Records {
+		return nil, ErrFormat
+	}
+	ix := &Index{Size: h.Size, ModTime: h.ModTime, Records: h.Records, BlockSize: h.BlockSize,
+		Blocks: make([]Block, h.Blocks)}
+	if err := binary.Read(r, binary.LittleEndian, ix.Blocks); err != nil {
+		return nil, ErrFormat
+	}
+	var offset, record int64
+	for i := range ix.Blocks {
+		b := &ix.Blocks[i]
+		if (i == 0 && b.Offset != 0) || b.Offset < offset || b.Record != record ||
+			b.Count <= 0 || b.Count > ix.Records-record {
+			return nil, ErrFormat
+		}
+		offset, record = b.Offset+16*b.Count, record+b.Count
+	}
+	if record != ix.Records || offset > ix.Size {
+		return nil, ErrFormat
+	}
+	return ix, nil
+}
+
+// Builder builds the index from the records in file order
+type Builder struct {
+	ix Index
+}
+
+// NewBuilder creates a builder of blocks of blockSize records
+func NewBuilder(blockSize int) *Builder {
+	if blockSize <= 0 {
+		blockSize = DefaultBlockSize
+	}
+	return &Builder{ix: Index{BlockSize: int64(blockSize)}}
+}
+
+// Add adds the record at the file offset, clock is the time base before it
+func (b *Builder) Add(offset int64, time uint64, id uint16, clock Clock) {
+	n := len(b.ix.Blocks)
+	if n == 0 || b.ix.Blocks[n-1].Count == b.ix.BlockSize {
+		b.ix.Blocks = append(b.ix.Blocks, Block{Offset: offset, Record: b.ix.Records, First: time, Clock: clock})
+		n++
+	}
+	blk := &b.ix.Blocks[n-1]
+	blk.Count++
+	blk.Last = time
+	no := uint8(id >> 8)
+	blk.Components[no>>6] |= 1 << (no & 63)
+	b.ix.Records++
+}
+
+// Index returns the index of the added records
+func (b *Builder) Index() *Index {
+	return &b.ix
+}
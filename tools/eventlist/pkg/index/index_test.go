@@ -0,0 +1,178 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package index
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func build(records int, blockSize int) *Index {
+	b := NewBuilder(blockSize)
+	for i := 0; i < records; i++ {
+		clock := Clock{Time: uint64(i), Before: float64(i) / 10, Factor: 1e-6}
+		b.Add(int64(16*i), uint64(100*i), uint16(i%3)<<8|0x01, clock)
+	}
+	return b.Index()
+}
+
+func TestBuilder(t *testing.T) {
+	t.Parallel()
+
+	ix := build(10, 4)
+	want := []Block{
+		{Offset: 0, Record: 0, Count: 4, First: 0, Last: 300, Components: [4]uint64{7}, Clock: Clock{0, 0, 1e-6}},
+		{Offset: 64, Record: 4, Count: 4, First: 400, Last: 700, Components: [4]uint64{7}, Clock: Clock{4, 0.4, 1e-6}},
+		{Offset: 128, Record: 8, Count: 2, First: 800, Last: 900, Components: [4]uint64{5}, Clock: Clock{8, 0.8, 1e-6}},
+	}
+	if ix.Records != 10 {
+		t.Errorf("Builder.Index() records = %d, want 10", ix.Records)
+	}
+	if !reflect.DeepEqual(ix.Blocks, want) {
+		t.Errorf("Builder.Index() blocks = %v, want %v", ix.Blocks, want)
+	}
+	if !ix.Blocks[2].HasComponent(2) || ix.Blocks[2].HasComponent(1) {
+		t.Errorf("Block.HasComponent() = %v, want components 0 and 2", ix.Blocks[2].Components)
+	}
+	b := NewBuilder(0)
+	b.Add(0, 0, 0xFF01, Clock{})
+	if got := b.Index(); !got.Blocks[0].HasComponent(0xFF) || got.BlockSize != DefaultBlockSize {
+		t.Errorf("NewBuilder() default = %v, want component 0xFF and block size %d", got, DefaultBlockSize)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	log := filepath.Join(t.TempDir(), "test.binary")
+	if err := os.WriteFile(log, make([]byte, 160), 0o600); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	if _, err := Load(log); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Load() without index error = %v, want %v", err, os.ErrNotExist)
+	}
+	ix := build(10, 4)
+	if err := ix.Save(log); err != nil {
+		t.Fatalf("Index.Save() error = %v", err)
+	}
+	got, err := Load(log)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, ix) {
+		t.Errorf("Load() = %v, want %v", got, ix)
+	}
+	if got.End(1) != 128 || got.End(2) != 160 {
+		t.Errorf("Index.End() = %d, %d, want 128, 160", got.End(1), got.End(2))
+	}
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(log, later, later); err != nil {
+		t.Fatalf("os.Chtimes() error = %v", err)
+	}
+	if _, err := Load(log); !errors.Is(err, ErrStale) {
+		t.Errorf("Load() of changed log error = %v, want %v", err, ErrStale)
+	}
+}
+
+func TestLoad_sparse(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() || runtime.GOOS == "windows" {
+		t.Skip("sparse log of more than 4 GiB")
+	}
+	const offset = 5 << 30
+	log := filepath.Join(t.TempDir(), "sparse.binary")
+	if err := os.WriteFile(log, nil, 0o600); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	if err := os.Truncate(log, offset+32); err != nil {
+		t.Fatalf("os.Truncate() error = %v", err)
+	}
+	b := NewBuilder(1)
+	b.Add(0, 1, 0x0101, Clock{})
+	b.Add(offset, 2<<32, 0x0201, Clock{})
+	b.Add(offset+16, 3<<32, 0x0201, Clock{})
+	ix := b.Index()
+	if err := ix.Save(log); err != nil {
+		t.Fatalf("Index.Save() error = %v", err)
+	}
+	got, err := Load(log)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, ix) {
+		t.Errorf("Load() = %v, want %v", got, ix)
+	}
+	if got.End(0) != offset || got.End(2) != offset+32 || got.Blocks[2].First != 3<<32 {
+		t.Errorf("Index.End() = %d, %d, want %d, %d", got.End(0), got.End(2), offset, offset+32)
+	}
+}
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	log := filepath.Join(dir, "test.binary")
+	if err := os.WriteFile(log, make([]byte, 160), 0o600); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	if err := build(10, 4).Save(log); err != nil {
+		t.Fatalf("Index.Save() error = %v", err)
+	}
+	data, err := os.ReadFile(Name(log))
+	if err != nil {
+		t.Fatalf("os.ReadFile() error = %v", err)
+	}
+	patch := func(off int, b byte) []byte {
+		p := append([]byte{}, data...)
+		p[off] = b
+		return p
+	}
+	const blocks = 48 // size of the header
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr bool
+	}{
+		{"valid", data, false},
+		{"empty", nil, true},
+		{"magic", patch(0, 'X'), true},
+		{"version", patch(4, 2), true},
+		{"truncated", data[:len(data)-8], true},
+		{"records", patch(24, 0xFF), true},
+		{"first offset", patch(blocks, 1), true},
+		{"record", patch(blocks+8, 1), true},
+		{"count", patch(blocks+16, 0), true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := Read(bytes.NewReader(tt.data)); (err != nil) != tt.wantErr {
+				t.Errorf("Read() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package influx exports the event counts and the durations of the
+// start/stop events in the InfluxDB line protocol, to a file or with HTTP
+// to the write API of InfluxDB.
+package influx
+
+import (
+	"bufio"
+	"bytes"
+	"eventlist/pkg/bus"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CountInterval is the interval of the event time the events are counted in
+var CountInterval = time.Second
+
+// Start is the start of the export the event times are added to, the
+// time of New if zero
+var Start time.Time
+
+// BatchSize is the number of lines sent with one HTTP request
+const BatchSize = 5000
+
+// Writer writes the measurements
+//
+//	events,component=<c>,property=<p> count=<n>i <time>
+//	duration,event=A(0) value=<seconds> <time of the stop event>
+//
+// the time is the start of the export plus the event time in nanoseconds
+type Writer struct {
+	target  string
+	file    *os.File
+	out     *bufio.Writer
+	batch   bytes.Buffer // lines of the next HTTP request
+	lines   int          // lines in batch
+	client  *http.Client
+	token   string
+	base    int64 // start of the export in ns
+	bucket  int64 // current count interval
+	counts  map[string]int
+	starts  map[uint16]float64 // time of the start events without stop event
+	line    []byte
+	Written int    // number of written lines
+	Name    string // name of the target in the report
+}
+
+// New creates the file of the line protocol, or writes to the write API
+// if target is an http(s) URL, e.g.
+// http://localhost:8086/api/v2/write?org=o&bucket=b, the API token is
+// read from INFLUX_TOKEN
+func New(target string) (*Writer, error) {
+	start := Start
+	if start.IsZero() {
+		start = time.Now()
+	}
+	w := &Writer{target: target, base: start.UnixNano(), bucket: -1, Name: target,
+		counts: make(map[string]int), starts: make(map[uint16]float64)}
+	if strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") {
+		w.client = &http.Client{Timeout: 30 * time.Second}
+		w.token = os.Getenv("INFLUX_TOKEN")
+		return w, nil
+	}
+	file, err := os.Create(target)
+	if err != nil {
+		return nil, err
+	}
+	w.file = file
+	w.out = bufio.NewWriter(file)
+	return w, nil
+}
+
+// escape a tag value
+var escaper = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+
+// timestamp of an event time in ns
+func (w *Writer) timestamp(t float64) int64 {
+	return w.base + int64(t*1e9)
+}
+
+// Event counts the event and writes the duration of a stop event
+func (w *Writer) Event(ev *bus.Event) error {
+	bucket := int64(ev.Time * 1e9 / float64(CountInterval))
+	if bucket != w.bucket {
+		if err := w.flushCounts(); err != nil {
+			return err
+		}
+		w.bucket = bucket
+	}
+	w.counts["component="+escaper.Replace(ev.Component())+",property="+escaper.Replace(ev.Property())]++
+
+	class, group, idx, start := ev.Data.Info.SplitID()
+	if class != 0xEF {
+		return nil
+	}
+	key := group<<4 | idx
+	if start {
+		w.starts[key] = ev.Time
+		return nil
+	}
+	begin, ok := w.starts[key]
+	if !ok {
+		return nil
+	}
+	delete(w.starts, key)
+	w.line = fmt.Appendf(w.line[:0], "duration,event=%c(%d) value=", byte(group+'A'), idx)
+	w.line = strconv.AppendFloat(w.line, ev.Time-begin, 'g', -1, 64)
+	w.line = append(w.line, ' ')
+	w.line = strconv.AppendInt(w.line, w.timestamp(ev.Time), 10)
+	return w.writeLine(w.line)
+}
+
+// write the counts of the current interval, sorted by the tags
+func (w *Writer) flushCounts() error {
+	if len(w.counts) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(w.counts))
+	for key := range w.counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	ts := w.base + w.bucket*int64(CountInterval)
+	for _, key := range keys {
+		w.line = append(append(w.line[:0], "events,"...), key...)
+		w.line = append(w.line, " count="...)
+		w.line = strconv.AppendInt(w.line, int64(w.counts[key]), 10)
+		w.line = append(w.line, "i "...)
+		w.line = strconv.AppendInt(w.line, ts, 10)
+		if err := w.writeLine(w.line); err != nil {
+			return err
+		}
+		delete(w.counts, key)
+	}
+	return nil
+}
+
+func (w *Writer) writeLine(line []byte) error {
+	w.Written++
+	if w.client == nil {
+		if _, err := w.out.Write(line); err != nil {
+			return err
+		}
+		return w.out.WriteByte('\n')
+	}
+	w.batch.Write(line)
+	w.batch.WriteByte('\n')
+	w.lines++
+	if w.lines >= BatchSize {
+		return w.post()
+	}
+	return nil
+}
+
+// send the batch to the write API
+func (w *Writer) post() error {
+	if w.lines == 0 {
+		return nil
+	}
+	req, err := http.NewRequest(http.MethodPost, w.target, bytes.NewReader(w.batch.Bytes()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if len(w.token) != 0 {
+		req.Header.Set("Authorization", "Token "+w.token)
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("influx write: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	w.batch.Reset()
+	w.lines = 0
+	return nil
+}
+
+// End writes the counts of the last interval and closes the file
+func (w *Writer) End() error {
+	err := w.flushCounts()
+	if w.client != nil {
+		if err == nil {
+			err = w.post()
+		}
+		return err
+	}
+	if e := w.out.Flush(); err == nil {
+		err = e
+	}
+	if e := w.file.Close(); err == nil {
+		err = e
+	}
+	return err
+}
+
+// Report writes the number of written lines
+func (w *Writer) Report(out io.Writer) error {
+	title := "InfluxDB"
+	_, err := fmt.Fprintf(out, "   %s\n   %s\n\n%s: %d lines written\n",
+		title, strings.Repeat("-", len(title)), w.Name, w.Written)
+	return err
+}
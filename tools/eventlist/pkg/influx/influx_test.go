@@ -0,0 +1,140 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package influx
+
+import (
+	"bytes"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/event"
+	"eventlist/pkg/xml/scvd"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var thread = &scvd.Event{Brief: "RTX Thread", Property: "Created"}
+
+func writeEvents(t *testing.T, w *Writer) {
+	t.Helper()
+	w.base = 0
+	events := []struct {
+		id   uint16
+		time float64
+		def  *scvd.Event
+	}{
+		{0xEF00, 0.25, nil}, {0x0A01, 0.5, thread}, {0xEF20, 0.75, nil},
+		{0xEF21, 1.0, nil}, {0x0A01, 1.5, thread}, {0x0A01, 1.75, thread},
+	}
+	for i, e := range events {
+		ev := bus.NewEvent(i, e.time, &event.Data{Info: event.Info{ID: e.id}}, e.def, nil)
+		if err := w.Event(ev); err != nil {
+			t.Errorf("Writer.Event() error = %v", err)
+		}
+	}
+	if err := w.End(); err != nil {
+		t.Errorf("Writer.End() error = %v", err)
+	}
+}
+
+const wantLines = `duration,event=A(0) value=0.5 750000000
+events,component=0xEF,property=0xEF00 count=1i 0
+events,component=0xEF,property=0xEF20 count=1i 0
+events,component=RTX\ Thread,property=Created count=1i 0
+events,component=0xEF,property=0xEF21 count=1i 1000000000
+events,component=RTX\ Thread,property=Created count=2i 1000000000
+`
+
+func TestWriter_file(t *testing.T) {
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), "events.lp")
+	w, err := New(name)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	writeEvents(t, w)
+	data, err := os.ReadFile(name)
+	if err != nil || string(data) != wantLines {
+		t.Errorf("Writer = %s, %v, want %s", data, err, wantLines)
+	}
+	var b bytes.Buffer
+	if err := w.Report(&b); err != nil || !strings.Contains(b.String(), "6 lines written") {
+		t.Errorf("Writer.Report() = %q, %v", b.String(), err)
+	}
+	if _, err := New(filepath.Join(name, "x")); err == nil {
+		t.Errorf("New() error = nil, want error")
+	}
+}
+
+func TestWriter_http(t *testing.T) {
+	t.Parallel()
+
+	var body, auth string
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body += string(data)
+		auth = r.Header.Get("Authorization")
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	w, err := New(server.URL + "/api/v2/write?org=o&bucket=b")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	w.token = "secret"
+	writeEvents(t, w)
+	if body != wantLines || auth != "Token secret" {
+		t.Errorf("Writer = %s with %q, want %s", body, auth, wantLines)
+	}
+
+	status = http.StatusUnauthorized
+	w, _ = New(server.URL)
+	w.Written, w.lines = 1, 1
+	if err := w.End(); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Writer.End() error = %v, want 401", err)
+	}
+}
+
+func TestNew_start(t *testing.T) { //nolint:golint,paralleltest
+	Start = time.Unix(0, 0)
+	defer func() { Start = time.Time{} }()
+
+	w, err := New(filepath.Join(t.TempDir(), "events.lp"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if w.base != 0 {
+		t.Errorf("New() base = %d, want 0", w.base)
+	}
+	w.Name = "events.lp"
+	if err := w.End(); err != nil {
+		t.Errorf("Writer.End() error = %v", err)
+	}
+	var b bytes.Buffer
+	if err := w.Report(&b); err != nil || !strings.Contains(b.String(), "\nevents.lp: 0 lines") {
+		t.Errorf("Writer.Report() = %q, %v", b.String(), err)
+	}
+}
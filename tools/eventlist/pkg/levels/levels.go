@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package levels breaks the events down by the Event Recorder level of their
+// SCVD definition: Error, API, Op and Detail.
+package levels
+
+import (
+	"eventlist/pkg/bus"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Names are the levels of the Event Recorder, always listed in the report
+var Names = []string{"Error", "API", "Op", "Detail"}
+
+// Level is the statistic of the events of a level
+type Level struct {
+	Name       string
+	Count      int
+	First      float64 // time of the first event
+	Last       float64 // time of the last event
+	Components map[string]int
+}
+
+// Levels counts the events per level
+type Levels struct {
+	levels map[string]*Level
+	total  int
+}
+
+// New creates the level statistic
+func New() *Levels {
+	return &Levels{levels: make(map[string]*Level)}
+}
+
+// Event counts the event at its level, an event without definition has no
+// level
+func (l *Levels) Event(ev *bus.Event) error {
+	name := ev.Level()
+	level, ok := l.levels[name]
+	if !ok {
+		level = &Level{Name: name, First: ev.Time, Components: make(map[string]int)}
+		l.levels[name] = level
+	}
+	level.Count++
+	level.Last = ev.Time
+	level.Components[ev.Component()]++
+	l.total++
+	return nil
+}
+
+// End does nothing, the counts are kept for the report
+func (l *Levels) End() error {
+	return nil
+}
+
+// Get returns the statistic of a level, nil if there is no event of it
+func (l *Levels) Get(name string) *Level {
+	return l.levels[name]
+}
+
+// Total returns the number of events
+func (l *Levels) Total() int {
+	return l.total
+}
+
+// the levels in the order of the report: the Event Recorder levels, other
+// levels of decoders sorted by name and the events without level
+func (l *Levels) order() []string {
+	names := append([]string{}, Names...)
+	var others []string
+	for name := range l.levels {
+		known := name == ""
+		for _, n := range Names {
+			known = known || name == n
+		}
+		if !known {
+			others = append(others, name)
+		}
+	}
+	sort.Strings(others)
+	names = append(names, others...)
+	if _, ok := l.levels[""]; ok {
+		names = append(names, "")
+	}
+	return names
+}
+
+// component with the most events of a level, the first name of equal counts
+func (level *Level) top() (string, int) {
+	var top string
+	count := 0
+	for name, n := range level.Components {
+		if n > count || (n == count && name < top) {
+			top, count = name, n
+		}
+	}
+	return top, count
+}
+
+// Report writes the number and the share of the events of every level, the
+// times of the first and the last event and the component with the most
+// events
+func (l *Levels) Report(out io.Writer) error {
+	title := "Levels"
+	if _, err := fmt.Fprintf(out, "   %s\n   %s\n\n", title, strings.Repeat("-", len(title))); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(out, "%-8s %8s %6s %12s %12s  %s\n",
+		"Level", "Events", "%", "First (s)", "Last (s)", "Top component"); err != nil {
+		return err
+	}
+	for _, name := range l.order() {
+		label := name
+		if label == "" {
+			label = "(none)"
+		}
+		level := l.levels[name]
+		if level == nil {
+			if _, err := fmt.Fprintf(out, "%-8s %8d %6s %12s %12s\n", label, 0, "-", "-", "-"); err != nil {
+				return err
+			}
+			continue
+		}
+		top, count := level.top()
+		if _, err := fmt.Fprintf(out, "%-8s %8d %5.1f%% %12.8f %12.8f  %s (%d)\n", label, level.Count,
+			100*float64(level.Count)/float64(l.total), level.First, level.Last, top, count); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(out, "%-8s %8d\n", "Total", l.total)
+	return err
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package levels
+
+import (
+	"bytes"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/event"
+	"eventlist/pkg/xml/scvd"
+	"testing"
+)
+
+var (
+	created = &scvd.Event{Brief: "RTX Thread", Property: "Created", Level: "Op"}
+	fault   = &scvd.Event{Brief: "App", Property: "Fault", Level: "Error"}
+	wait    = &scvd.Event{Brief: "RTX Delay", Property: "Wait", Level: "API"}
+	warning = &scvd.Event{Brief: "Modem", Property: "Retry", Level: "Warning"}
+)
+
+func newLevels(defs ...*scvd.Event) *Levels {
+	l := New()
+	for i, def := range defs {
+		_ = l.Event(bus.NewEvent(i, float64(i)/4, &event.Data{Info: event.Info{ID: 0xEF00}}, def, nil))
+	}
+	return l
+}
+
+func TestLevels_Event(t *testing.T) {
+	t.Parallel()
+
+	l := newLevels(created, fault, created, nil, wait)
+	tests := []struct {
+		name  string
+		level string
+		want  *Level
+	}{
+		{"op", "Op", &Level{Name: "Op", Count: 2, First: 0, Last: 0.5}},
+		{"error", "Error", &Level{Name: "Error", Count: 1, First: 0.25, Last: 0.25}},
+		{"none", "", &Level{Name: "", Count: 1, First: 0.75, Last: 0.75}},
+		{"detail", "Detail", nil},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := l.Get(tt.level)
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("Levels.Get() %s = %v, want nil", tt.name, got)
+				}
+				return
+			}
+			if got == nil || got.Name != tt.want.Name || got.Count != tt.want.Count ||
+				got.First != tt.want.First || got.Last != tt.want.Last {
+				t.Errorf("Levels.Get() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+	if l.Total() != 5 {
+		t.Errorf("Levels.Total() = %d, want 5", l.Total())
+	}
+}
+
+func TestLevels_Report(t *testing.T) {
+	t.Parallel()
+
+	header := "   Levels\n   ------\n\n" +
+		"Level      Events      %    First (s)     Last (s)  Top component\n"
+	tests := []struct {
+		name string
+		l    *Levels
+		want string
+	}{
+		{"none", New(), header +
+			"Error           0      -            -            -\n" +
+			"API             0      -            -            -\n" +
+			"Op              0      -            -            -\n" +
+			"Detail          0      -            -            -\n" +
+			"Total           0\n"},
+		{"levels", newLevels(created, fault, created, nil, warning), header +
+			"Error           1  20.0%   0.25000000   0.25000000  App (1)\n" +
+			"API             0      -            -            -\n" +
+			"Op              2  40.0%   0.00000000   0.50000000  RTX Thread (2)\n" +
+			"Detail          0      -            -            -\n" +
+			"Warning         1  20.0%   1.00000000   1.00000000  Modem (1)\n" +
+			"(none)          1  20.0%   0.75000000   0.75000000  0xEF (1)\n" +
+			"Total           5\n"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var b bytes.Buffer
+			if err := tt.l.Report(&b); err != nil || b.String() != tt.want {
+				t.Errorf("Levels.Report() %s = %q, %v, want %q", tt.name, b.String(), err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package live
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"sync/atomic"
+)
+
+// Frames of the acknowledgment protocol, see docs/ack_protocol.md
+const (
+	maxPayload  = 1024
+	frameHeader = 8 // magic, sequence number, payload length
+	frameCRC    = 2
+)
+
+var (
+	magicData   = [2]byte{'E', 'R'}
+	magicAck    = [2]byte{'A', 'K'}
+	magicResume = [2]byte{'R', 'S'}
+)
+
+// crc16 calculates the CRC-16/CCITT-FALSE of the data
+func crc16(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// ackReader receives the data frames in sequence and acknowledges them
+type ackReader struct {
+	conn     io.ReadWriteCloser
+	in       *bufio.Reader
+	expected uint32 // sequence number of the next frame
+	payload  []byte // received but not yet read data
+	resuming bool   // resume request sent, frames after the lost frames are dropped
+	lost     atomic.Int64
+}
+
+func newAckReader(conn io.ReadWriteCloser) (*ackReader, error) {
+	r := &ackReader{conn: conn, in: bufio.NewReader(conn)}
+	if err := r.send(magicResume, 0); err != nil { // start of session
+		conn.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// send an acknowledge or resume request
+func (r *ackReader) send(magic [2]byte, seq uint32) error {
+	var frame [8]byte
+	copy(frame[:2], magic[:])
+	binary.LittleEndian.PutUint32(frame[2:6], seq)
+	binary.LittleEndian.PutUint16(frame[6:8], crc16(frame[:6]))
+	_, err := r.conn.Write(frame[:])
+	return err
+}
+
+// receive the next valid frame, skipping bytes until the frame start
+func (r *ackReader) frame() (uint32, []byte, error) {
+	for {
+		b, err := r.in.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		if b != magicData[0] {
+			continue
+		}
+		head, err := r.in.Peek(frameHeader - 1)
+		if err != nil {
+			return 0, nil, err
+		}
+		length := int(binary.LittleEndian.Uint16(head[5:7]))
+		if head[0] != magicData[1] || length > maxPayload {
+			continue // no frame start
+		}
+		frame, err := r.in.Peek(frameHeader - 1 + length + frameCRC)
+		if err != nil {
+			return 0, nil, err
+		}
+		crc := binary.LittleEndian.Uint16(frame[frameHeader-1+length:])
+		if crc != crc16(frame[1:frameHeader-1+length]) {
+			continue // corrupted or no frame start
+		}
+		seq := binary.LittleEndian.Uint32(frame[1:5])
+		payload := make([]byte, length)
+		copy(payload, frame[frameHeader-1:])
+		_, _ = r.in.Discard(len(frame))
+		return seq, payload, nil
+	}
+}
+
+// Lost returns the number of frames lost before a resume request, they
+// are sent again by the target
+func (r *ackReader) Lost() int64 {
+	return r.lost.Load()
+}
+
+func (r *ackReader) Read(p []byte) (int, error) {
+	for len(r.payload) == 0 {
+		seq, payload, err := r.frame()
+		if err != nil {
+			return 0, err
+		}
+		switch {
+		case seq == r.expected:
+			r.expected++
+			r.resuming = false
+			r.payload = payload
+			err = r.send(magicAck, r.expected)
+		case seq < r.expected: // repeated frame
+			err = r.send(magicAck, r.expected)
+		case r.resuming: // frames in flight before the resume request
+		default: // lost frames
+			r.lost.Add(int64(seq - r.expected))
+			r.resuming = true
+			err = r.send(magicResume, r.expected)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.payload)
+	r.payload = r.payload[n:]
+	return n, nil
+}
+
+func (r *ackReader) Close() error {
+	return r.conn.Close()
+}
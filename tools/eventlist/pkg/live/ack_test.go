@@ -0,0 +1,158 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package live
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+type testConn struct {
+	io.Reader
+	written bytes.Buffer
+	closed  bool
+	err     error
+}
+
+func (c *testConn) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	return c.written.Write(p)
+}
+
+func (c *testConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func dataFrame(seq uint32, payload string) []byte {
+	frame := []byte{'E', 'R'}
+	frame = binary.LittleEndian.AppendUint32(frame, seq)
+	frame = binary.LittleEndian.AppendUint16(frame, uint16(len(payload)))
+	frame = append(frame, payload...)
+	return binary.LittleEndian.AppendUint16(frame, crc16(frame[2:]))
+}
+
+func control(magic string, seq uint32) string {
+	frame := []byte(magic)
+	frame = binary.LittleEndian.AppendUint32(frame, seq)
+	return string(binary.LittleEndian.AppendUint16(frame, crc16(frame)))
+}
+
+func Test_crc16(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		data string
+		want uint16
+	}{
+		{"empty", "", 0xFFFF},
+		{"check", "123456789", 0x29B1},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := crc16([]byte(tt.data)); got != tt.want {
+				t.Errorf("crc16() %s = 0x%04X, want 0x%04X", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ackReader(t *testing.T) {
+	t.Parallel()
+
+	corrupt := dataFrame(1, "xyz")
+	corrupt[9] ^= 0xFF
+	tests := []struct {
+		name    string
+		frames  [][]byte
+		want    string
+		written []string
+		lost    int64
+	}{
+		{"in order", [][]byte{dataFrame(0, "ab"), dataFrame(1, "cd")}, "abcd",
+			[]string{control("RS", 0), control("AK", 1), control("AK", 2)}, 0},
+		{"repeated", [][]byte{dataFrame(0, "ab"), dataFrame(0, "ab"), dataFrame(1, "cd")}, "abcd",
+			[]string{control("RS", 0), control("AK", 1), control("AK", 1), control("AK", 2)}, 0},
+		{"lost", [][]byte{dataFrame(0, "ab"), dataFrame(2, "ef"), dataFrame(1, "cd")}, "abcd",
+			[]string{control("RS", 0), control("AK", 1), control("RS", 1), control("AK", 2)}, 1},
+		{"in flight", [][]byte{dataFrame(0, "ab"), dataFrame(2, "ef"), dataFrame(3, "gh"), dataFrame(4, "ij"),
+			dataFrame(1, "cd"), dataFrame(2, "ef"), dataFrame(3, "gh"), dataFrame(4, "ij")}, "abcdefghij",
+			[]string{control("RS", 0), control("AK", 1), control("RS", 1),
+				control("AK", 2), control("AK", 3), control("AK", 4), control("AK", 5)}, 1},
+		{"lost twice", [][]byte{dataFrame(0, "ab"), dataFrame(2, "ef"), dataFrame(1, "cd"), dataFrame(2, "ef"),
+			dataFrame(5, "kl"), dataFrame(6, "mn"), dataFrame(3, "gh")}, "abcdefgh",
+			[]string{control("RS", 0), control("AK", 1), control("RS", 1), control("AK", 2), control("AK", 3),
+				control("RS", 3), control("AK", 4)}, 3},
+		{"corrupted", [][]byte{dataFrame(0, "ab"), corrupt, dataFrame(1, "cd")}, "abcd",
+			[]string{control("RS", 0), control("AK", 1), control("AK", 2)}, 0},
+		{"garbage", [][]byte{[]byte("EEx\\x00ER"), dataFrame(0, "ab")}, "ab",
+			[]string{control("RS", 0), control("AK", 1)}, 0},
+		{"too long", [][]byte{{'E', 'R', 0, 0, 0, 0, 0xFF, 0xFF}, dataFrame(0, "ab")}, "ab",
+			[]string{control("RS", 0), control("AK", 1)}, 0},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			conn := &testConn{Reader: bytes.NewReader(bytes.Join(tt.frames, nil))}
+			r, err := newAckReader(conn)
+			if err != nil {
+				t.Fatalf("newAckReader() %s error = %v", tt.name, err)
+			}
+			got, err := io.ReadAll(r)
+			if err != nil || string(got) != tt.want {
+				t.Errorf("ackReader.Read() %s = %q, %v, want %q", tt.name, got, err, tt.want)
+			}
+			var written []string
+			for conn.written.Len() > 0 {
+				written = append(written, string(conn.written.Next(8)))
+			}
+			if !reflect.DeepEqual(written, tt.written) {
+				t.Errorf("ackReader.Read() %s written = %q, want %q", tt.name, written, tt.written)
+			}
+			if lost := Lost(r); lost != tt.lost {
+				t.Errorf("Lost() %s = %d, want %d", tt.name, lost, tt.lost)
+			}
+			if err = r.Close(); err != nil || !conn.closed {
+				t.Errorf("ackReader.Close() %s error = %v", tt.name, err)
+			}
+		})
+	}
+}
+
+func Test_newAckReaderError(t *testing.T) {
+	t.Parallel()
+
+	errWrite := errors.New("write failed")
+	conn := &testConn{Reader: bytes.NewReader(nil), err: errWrite}
+	if _, err := newAckReader(conn); !errors.Is(err, errWrite) || !conn.closed {
+		t.Errorf("newAckReader() error = %v, want %v", err, errWrite)
+	}
+}
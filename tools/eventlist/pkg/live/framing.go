@@ -0,0 +1,180 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package live
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"eventlist/pkg/event"
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+var errFraming = errors.New("invalid framing")
+
+const (
+	slipEnd    = 0xC0
+	slipEsc    = 0xDB
+	slipEscEnd = 0xDC
+	slipEscEsc = 0xDD
+	detectSize = 512 // bytes used to detect the framing
+)
+
+func checkFraming(framing string) error {
+	switch framing {
+	case "", "none", "cobs", "slip", "auto":
+		return nil
+	}
+	return fmt.Errorf("%w: %s", errFraming, framing)
+}
+
+// decode a COBS frame without the delimiter
+func decodeCOBS(frame []byte) ([]byte, bool) {
+	out := make([]byte, 0, len(frame))
+	for i := 0; i < len(frame); {
+		code := int(frame[i])
+		if code == 0 || i+code > len(frame) {
+			return nil, false
+		}
+		out = append(out, frame[i+1:i+code]...)
+		i += code
+		if code < 0xFF && i < len(frame) {
+			out = append(out, 0)
+		}
+	}
+	return out, true
+}
+
+// decode a SLIP frame without the delimiters
+func decodeSLIP(frame []byte) ([]byte, bool) {
+	out := make([]byte, 0, len(frame))
+	for i := 0; i < len(frame); i++ {
+		c := frame[i]
+		if c == slipEsc {
+			if i++; i >= len(frame) {
+				return nil, false
+			}
+			switch frame[i] {
+			case slipEscEnd:
+				c = slipEnd
+			case slipEscEsc:
+				c = slipEsc
+			default:
+				return nil, false
+			}
+		}
+		out = append(out, c)
+	}
+	return out, true
+}
+
+// check if the data starts with an event record
+func isRecord(data []byte) bool {
+	if len(data) < 4 {
+		return false
+	}
+	typ := event.ByteOrder.Uint16(data[0:2])
+	length := int(event.ByteOrder.Uint16(data[2:4]))
+	return typ >= 1 && typ <= 3 && length >= 12 && 4+length <= len(data)
+}
+
+// detect the framing from the first frame of the data,
+// false if more data is needed
+func detectFraming(data []byte) (string, bool) {
+	if i := bytes.IndexByte(data, 0); i > 0 {
+		if frame, ok := decodeCOBS(data[:i]); ok && isRecord(frame) {
+			return "cobs", true
+		}
+	}
+	rest := bytes.TrimLeft(data, string([]byte{slipEnd}))
+	if i := bytes.IndexByte(rest, slipEnd); i > 0 {
+		if frame, ok := decodeSLIP(rest[:i]); ok && isRecord(frame) {
+			return "slip", true
+		}
+		return "none", true
+	}
+	return "none", len(data) >= detectSize
+}
+
+// framingReader returns the event records of a framed stream
+type framingReader struct {
+	io.Closer
+	in      *bufio.Reader
+	framing string
+	data    []byte // decoded but not yet read data
+	lost    atomic.Int64
+}
+
+func newFramingReader(in io.ReadCloser, framing string) io.ReadCloser {
+	if framing == "" || framing == "none" {
+		return in
+	}
+	return &framingReader{Closer: in, in: bufio.NewReaderSize(in, 2*detectSize), framing: framing}
+}
+
+// Lost returns the number of dropped corrupted frames
+func (f *framingReader) Lost() int64 {
+	return f.lost.Load()
+}
+
+func (f *framingReader) Read(p []byte) (int, error) {
+	for n := 1; f.framing == "auto"; n++ {
+		data, err := f.in.Peek(n)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return 0, err
+		}
+		if framing, ok := detectFraming(data); ok || err != nil {
+			f.framing = framing
+		}
+		if b := f.in.Buffered(); b > n {
+			n = b - 1 // continue with the received data
+		}
+	}
+	if f.framing == "none" {
+		return f.in.Read(p)
+	}
+	for len(f.data) == 0 {
+		delim := byte(0)
+		if f.framing == "slip" {
+			delim = slipEnd
+		}
+		frame, err := f.in.ReadBytes(delim)
+		if err != nil {
+			return 0, err // incomplete frame at the end
+		}
+		frame = frame[:len(frame)-1]
+		var data []byte
+		var ok bool
+		if f.framing == "slip" {
+			data, ok = decodeSLIP(frame)
+		} else {
+			data, ok = decodeCOBS(frame)
+		}
+		if ok {
+			f.data = data
+		} else if len(frame) != 0 {
+			f.lost.Add(1) // corrupted frames are dropped
+		}
+	}
+	n := copy(p, f.data)
+	f.data = f.data[n:]
+	return n, nil
+}
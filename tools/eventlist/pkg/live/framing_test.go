@@ -0,0 +1,206 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package live
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func encodeCOBS(data []byte) []byte {
+	out := []byte{0}
+	code, pos := byte(1), 0
+	for _, b := range data {
+		if b == 0 {
+			out[pos] = code
+			out = append(out, 0)
+			code, pos = 1, len(out)-1
+			continue
+		}
+		out = append(out, b)
+		if code++; code == 0xFF {
+			out[pos] = code
+			out = append(out, 0)
+			code, pos = 1, len(out)-1
+		}
+	}
+	out[pos] = code
+	return append(out, 0)
+}
+
+func encodeSLIP(data []byte) []byte {
+	out := []byte{slipEnd}
+	for _, b := range data {
+		switch b {
+		case slipEnd:
+			out = append(out, slipEsc, slipEscEnd)
+		case slipEsc:
+			out = append(out, slipEsc, slipEscEsc)
+		default:
+			out = append(out, b)
+		}
+	}
+	return append(out, slipEnd)
+}
+
+func Test_decodeCOBS(t *testing.T) {
+	t.Parallel()
+
+	long := bytes.Repeat([]byte{1}, 300)
+	tests := []struct {
+		name  string
+		data  []byte
+		want  []byte
+		want1 bool
+	}{
+		{"zero", []byte{0}, []byte{0}, true},
+		{"bytes", []byte{1, 2, 0, 0, 3}, []byte{1, 2, 0, 0, 3}, true},
+		{"long", long, long, true},
+		{"empty", []byte{}, []byte{}, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			frame := encodeCOBS(tt.data)
+			got, ok := decodeCOBS(frame[:len(frame)-1])
+			if !ok || !bytes.Equal(got, tt.want) {
+				t.Errorf("decodeCOBS() %s = %v, %v, want %v", tt.name, got, ok, tt.want)
+			}
+		})
+	}
+	for _, frame := range [][]byte{{3, 1}, {0, 1}} {
+		if _, ok := decodeCOBS(frame); ok {
+			t.Errorf("decodeCOBS() %v = ok, want invalid", frame)
+		}
+	}
+}
+
+func Test_decodeSLIP(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		frame []byte
+		want  []byte
+		ok    bool
+	}{
+		{"plain", []byte{1, 2, 3}, []byte{1, 2, 3}, true},
+		{"escaped", []byte{1, slipEsc, slipEscEnd, slipEsc, slipEscEsc}, []byte{1, slipEnd, slipEsc}, true},
+		{"end", []byte{1, slipEsc}, nil, false},
+		{"invalid", []byte{1, slipEsc, 1}, nil, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := decodeSLIP(tt.frame)
+			if ok != tt.ok || (ok && !bytes.Equal(got, tt.want)) {
+				t.Errorf("decodeSLIP() %s = %v, %v, want %v, %v", tt.name, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func Test_detectFraming(t *testing.T) {
+	t.Parallel()
+
+	records, err := os.ReadFile("../../testdata/test10.binary")
+	if err != nil {
+		t.Fatalf("os.ReadFile() error = %v", err)
+	}
+	tests := []struct {
+		name  string
+		data  []byte
+		want  string
+		want1 bool
+	}{
+		{"cobs", encodeCOBS(records), "cobs", true},
+		{"slip", encodeSLIP(records), "slip", true},
+		{"slip no start", encodeSLIP(records)[1:], "slip", true},
+		{"none", records, "none", false},
+		{"none end", append(records[:10:10], slipEnd), "none", true},
+		{"none long", bytes.Repeat(records, 20), "none", true},
+		{"slip part", encodeSLIP(records)[:10], "none", false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, got1 := detectFraming(tt.data)
+			if got != tt.want || got1 != tt.want1 {
+				t.Errorf("detectFraming() %s = %v, %v, want %v, %v", tt.name, got, got1, tt.want, tt.want1)
+			}
+		})
+	}
+}
+
+func Test_framingReader(t *testing.T) {
+	t.Parallel()
+
+	records, err := os.ReadFile("../../testdata/test10.binary")
+	if err != nil {
+		t.Fatalf("os.ReadFile() error = %v", err)
+	}
+	record1, record2 := records[:24], records[24:]
+	cobs := append(encodeCOBS(record1), encodeCOBS(record2)...)
+	slip := append(encodeSLIP(record1), encodeSLIP(record2)...)
+	broken := append(append(encodeSLIP(record1), 1, slipEsc, 1, slipEnd), encodeSLIP(record2)...)
+	tests := []struct {
+		name    string
+		framing string
+		data    []byte
+		want    []byte
+		lost    int64
+	}{
+		{"none", "none", records, records, 0},
+		{"cobs", "cobs", cobs, records, 0},
+		{"slip", "slip", slip, records, 0},
+		{"auto cobs", "auto", cobs, records, 0},
+		{"auto slip", "auto", slip, records, 0},
+		{"auto none", "auto", records, records, 0},
+		{"auto empty", "auto", nil, nil, 0},
+		{"broken", "slip", broken, records, 1},
+		{"incomplete", "cobs", cobs[:len(cobs)-1], record1, 0},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			in := newFramingReader(io.NopCloser(bytes.NewReader(tt.data)), tt.framing)
+			got, err := io.ReadAll(in)
+			if err != nil || !reflect.DeepEqual(got, tt.want) && len(got)+len(tt.want) > 0 {
+				t.Errorf("framingReader.Read() %s = %v, %v, want %v", tt.name, got, err, tt.want)
+			}
+			if lost := Lost(in); lost != tt.lost {
+				t.Errorf("Lost() %s = %d, want %d", tt.name, lost, tt.lost)
+			}
+			if err = in.Close(); err != nil {
+				t.Errorf("framingReader.Close() %s error = %v", tt.name, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package live
+
+import (
+	"errors"
+	"eventlist/pkg/probe"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.bug.st/serial"
+)
+
+var errClosed = errors.New("live source closed")
+
+var errAck = errors.New("acknowledgment protocol requires a tcp or serial source")
+
+var errAckFraming = errors.New("acknowledgment protocol cannot be combined with framing")
+
+var errProbeFraming = errors.New("probe sources have no framing")
+
+var errResyncAck = errors.New("resynchronization cannot be combined with the acknowledgment protocol")
+
+var errProbeResync = errors.New("probe sources mark lost records without resynchronization")
+
+var errRecorder = errors.New("probe source requires the address of " + probe.Symbol + ", e.g. with -a")
+
+// PollInterval is the time to wait for new data of a followed file
+var PollInterval = 100 * time.Millisecond
+
+// Options of a live source
+type Options struct {
+	Ack     bool   // use the acknowledgment protocol on tcp and serial sources
+	Framing string // framing of the records: none, cobs, slip or auto
+	Info    uint32 // address of the Event Recorder information of probe sources
+	Resync  bool   // skip invalid data up to the next record and reopen lost tcp and serial links
+}
+
+// Open opens a live event source. A source "tcp://host:port" connects
+// to a TCP server sending the event records, "serial:port[,baudrate]"
+// opens a serial port, "udp://[host]:port" receives datagrams,
+// "pyocd:[target][,probe]" reads the Event Recorder buffer of the target with
+// pyOCD, "gdb://host:port" with a GDB server, any other source is a file
+// that is followed while it grows. With Resync gaps of the records are
+// marked by an event IDGap.
+func Open(source string, opts Options) (io.ReadCloser, error) {
+	var in io.ReadCloser
+	var conn io.ReadWriteCloser
+	var err error
+	if err = checkFraming(opts.Framing); err != nil {
+		return nil, err
+	}
+	if opts.Ack && opts.Framing != "" && opts.Framing != "none" {
+		return nil, errAckFraming
+	}
+	if opts.Ack && opts.Resync {
+		return nil, errResyncAck
+	}
+	if addr, ok := strings.CutPrefix(source, "tcp://"); ok {
+		conn, err = net.Dial("tcp", addr)
+	} else if port, ok := strings.CutPrefix(source, "serial:"); ok {
+		conn, err = openSerial(port)
+	} else if opts.Ack {
+		return nil, errAck
+	} else if target, ok := strings.CutPrefix(source, "pyocd:"); ok {
+		return openProbe(opts, func() (probe.Memory, error) { return probe.OpenPyOCD(target) })
+	} else if addr, ok := strings.CutPrefix(source, "gdb://"); ok {
+		return openProbe(opts, func() (probe.Memory, error) { return probe.OpenGDB(addr) })
+	} else if addr, ok := strings.CutPrefix(source, "udp://"); ok {
+		in, err = openUDP(addr)
+	} else {
+		var file *os.File
+		if file, err = os.Open(source); err == nil {
+			in = &follower{file: file, done: make(chan struct{})}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if conn != nil {
+		if opts.Ack {
+			return newAckReader(conn)
+		}
+		in = conn
+	}
+	in = newFramingReader(in, opts.Framing)
+	if !opts.Resync {
+		return in, nil
+	}
+	var reopen func() (io.ReadCloser, error)
+	if conn != nil {
+		reopen = func() (io.ReadCloser, error) {
+			return Open(source, Options{Framing: opts.Framing})
+		}
+	}
+	return newSyncReader(in, reopen), nil
+}
+
+// open the Event Recorder buffer of a target as source
+func openProbe(opts Options, open func() (probe.Memory, error)) (io.ReadCloser, error) {
+	if opts.Info == 0 {
+		return nil, errRecorder
+	}
+	if opts.Framing != "" && opts.Framing != "none" {
+		return nil, errProbeFraming
+	}
+	if opts.Resync {
+		return nil, errProbeResync
+	}
+	mem, err := open()
+	if err != nil {
+		return nil, err
+	}
+	r, err := probe.NewReader(mem, opts.Info)
+	if err != nil {
+		_ = mem.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// Filterer is a live source changing the event filter of the target
+type Filterer interface {
+	SetFilter(f probe.Filter) error
+}
+
+// Resetter is a live source resetting the target
+type Resetter interface {
+	Reset() error
+}
+
+// Lost returns the number of frames of a source opened with framing or
+// the acknowledgment protocol that were corrupted or lost on the way,
+// plus the gaps of a source opened with Resync, 0 for other sources
+func Lost(in io.Reader) int64 {
+	if l, ok := in.(interface{ Lost() int64 }); ok {
+		return l.Lost()
+	}
+	return 0
+}
+
+// open a serial port given as "port[,baudrate]"
+func openSerial(port string) (serial.Port, error) {
+	mode := serial.Mode{BaudRate: 115200}
+	if name, baud, ok := strings.Cut(port, ","); ok {
+		rate, err := strconv.Atoi(baud)
+		if err != nil {
+			return nil, err
+		}
+		port = name
+		mode.BaudRate = rate
+	}
+	return serial.Open(port, &mode)
+}
+
+// udpReader returns the data of the received datagrams
+type udpReader struct {
+	conn net.PacketConn
+	buf  []byte
+	data []byte
+}
+
+func openUDP(addr string) (*udpReader, error) {
+	conn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &udpReader{conn: conn, buf: make([]byte, 65536)}, nil
+}
+
+func (u *udpReader) Read(p []byte) (int, error) {
+	for len(u.data) == 0 {
+		n, _, err := u.conn.ReadFrom(u.buf)
+		if err != nil {
+			return 0, err
+		}
+		u.data = u.buf[:n]
+	}
+	n := copy(p, u.data)
+	u.data = u.data[n:]
+	return n, nil
+}
+
+func (u *udpReader) Close() error {
+	return u.conn.Close()
+}
+
+// follower reads a file and waits for new data at its end
+type follower struct {
+	file *os.File
+	done chan struct{}
+	once sync.Once
+}
+
+func (f *follower) Read(p []byte) (int, error) {
+	for {
+		select {
+		case <-f.done:
+			return 0, io.EOF
+		default:
+		}
+		n, err := f.file.Read(p)
+		if n > 0 || (err != nil && !errors.Is(err, io.EOF)) {
+			return n, err
+		}
+		select {
+		case <-f.done:
+			return 0, io.EOF
+		case <-time.After(PollInterval):
+		}
+	}
+}
+
+func (f *follower) Close() error {
+	err := errClosed
+	f.once.Do(func() {
+		close(f.done)
+		err = f.file.Close()
+	})
+	return err
+}
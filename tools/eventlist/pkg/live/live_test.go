@@ -0,0 +1,153 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package live
+
+import (
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOpen(t *testing.T) {
+	t.Parallel()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		c, err := l.Accept()
+		if err == nil {
+			_, _ = c.Write([]byte("tcp data"))
+			c.Close()
+		}
+	}()
+	file := filepath.Join(t.TempDir(), "events.log")
+	if err := os.WriteFile(file, []byte("file data"), 0o600); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		source  string
+		ack     bool
+		framing string
+		want    string
+		wantErr bool
+	}{
+		{"tcp", "tcp://" + l.Addr().String(), false, "", "tcp data", false},
+		{"file", file, false, "", "file data", false},
+		{"file none", file, false, "none", "file data", false},
+		{"nix", "../../testdata/nix", false, "", "", true},
+		{"file ack", file, true, "", "", true},
+		{"tcp nix", "tcp://nix:port", false, "", "", true},
+		{"serial", "serial:/dev/nix", false, "", "", true},
+		{"serial baud", "serial:/dev/nix,fast", true, "", "", true},
+		{"udp nix", "udp://nix:port", false, "", "", true},
+		{"framing", file, false, "hdlc", "", true},
+		{"ack framing", file, true, "slip", "", true},
+		{"pyocd no info", "pyocd:auto", false, "", "", true},
+		{"pyocd ack", "pyocd:auto", true, "", "", true},
+		{"gdb no info", "gdb://localhost:1", false, "", "", true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			in, err := Open(tt.source, Options{Ack: tt.ack, Framing: tt.framing})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Open() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+				return
+			}
+			if in == nil {
+				return
+			}
+			defer in.Close()
+			buf := make([]byte, len(tt.want))
+			if _, err = io.ReadFull(in, buf); err != nil || string(buf) != tt.want {
+				t.Errorf("Open() %s = %v, %v, want %v", tt.name, string(buf), err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFollower(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "events.log")
+	if err := os.WriteFile(file, []byte("ab"), 0o600); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	in, err := Open(file, Options{})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	go func() {
+		time.Sleep(2 * PollInterval)
+		f, err := os.OpenFile(file, os.O_APPEND|os.O_WRONLY, 0o600)
+		if err == nil {
+			_, _ = f.WriteString("cd")
+			f.Close()
+		}
+	}()
+	buf := make([]byte, 4)
+	if _, err = io.ReadFull(in, buf); err != nil || string(buf) != "abcd" {
+		t.Errorf("follower.Read() = %v, %v, want abcd", string(buf), err)
+	}
+	go func() {
+		time.Sleep(PollInterval)
+		in.Close()
+	}()
+	if n, err := in.Read(buf); n != 0 || err != io.EOF {
+		t.Errorf("follower.Read() = %v, %v, want EOF", n, err)
+	}
+	if err := in.Close(); err == nil {
+		t.Errorf("follower.Close() = nil, want error")
+	}
+}
+
+func TestOpenUDP(t *testing.T) {
+	t.Parallel()
+
+	in, err := Open("udp://127.0.0.1:0", Options{})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer in.Close()
+	addr := in.(*udpReader).conn.LocalAddr().String()
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		t.Fatalf("net.Dial() error = %v", err)
+	}
+	defer conn.Close()
+	for _, d := range []string{"abc", "defg"} {
+		if _, err = conn.Write([]byte(d)); err != nil {
+			t.Fatalf("conn.Write() error = %v", err)
+		}
+	}
+	buf := make([]byte, 7)
+	if _, err = io.ReadFull(in, buf); err != nil || string(buf) != "abcdefg" {
+		t.Errorf("udpReader.Read() = %v, %v, want abcdefg", string(buf), err)
+	}
+}
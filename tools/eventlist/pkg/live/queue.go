@@ -0,0 +1,196 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package live
+
+import (
+	"bufio"
+	"errors"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/event"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Policies of a full queue
+const (
+	Block      = "block"       // the source is not read until there is room
+	DropOldest = "drop-oldest" // the oldest queued record is dropped
+	DropNewest = "drop-newest" // the received record is dropped
+)
+
+var errQueueSize = errors.New("queue size must be at least 1")
+
+// Queue holds up to a number of records read from a live source until they
+// are decoded, so a slow exporter neither stalls the source nor lets the
+// memory grow. A full queue blocks or drops records by its policy.
+type Queue struct {
+	in      io.ReadCloser
+	mu      sync.Mutex
+	cond    *sync.Cond
+	records [][]byte // ring of queued records
+	first   int      // index of the oldest record
+	count   int      // number of queued records
+	data    []byte   // record not yet read
+	policy  string
+	err     error // error of the source
+	closed  bool
+	high    int // maximum number of queued records
+	dropped atomic.Int64
+	once    sync.Once
+}
+
+// NewQueue starts reading the records of a source into a queue of size
+// records with a policy block, drop-oldest or drop-newest
+func NewQueue(in io.ReadCloser, size int, policy string) (*Queue, error) {
+	switch policy {
+	case Block, DropOldest, DropNewest:
+	default:
+		return nil, fmt.Errorf("invalid queue policy: %s", policy)
+	}
+	if size < 1 {
+		return nil, errQueueSize
+	}
+	q := &Queue{in: in, records: make([][]byte, size), policy: policy}
+	q.cond = sync.NewCond(&q.mu)
+	go q.receive()
+	return q, nil
+}
+
+// receive reads the records of the source until it fails
+func (q *Queue) receive() {
+	rd := bufio.NewReader(q.in)
+	for {
+		rec, err := readRecord(rd)
+		q.mu.Lock()
+		if err != nil {
+			q.err = err
+			q.cond.Broadcast()
+			q.mu.Unlock()
+			return
+		}
+		q.push(rec)
+		q.mu.Unlock()
+	}
+}
+
+// readRecord reads a complete record
+func readRecord(rd *bufio.Reader) ([]byte, error) {
+	head, err := rd.Peek(4)
+	if err != nil {
+		return nil, err
+	}
+	rec := make([]byte, 4+int(event.ByteOrder.Uint16(head[2:4])))
+	_, err = io.ReadFull(rd, rec)
+	return rec, err
+}
+
+// push adds a record to the queue, q.mu is locked
+func (q *Queue) push(rec []byte) {
+	size := len(q.records)
+	for q.count == size && q.policy == Block && !q.closed {
+		q.cond.Wait()
+	}
+	if q.closed {
+		return
+	}
+	if q.count == size {
+		q.dropped.Add(1)
+		if q.policy != DropOldest {
+			return
+		}
+		q.first = (q.first + 1) % size
+		q.count--
+	}
+	q.records[(q.first+q.count)%size] = rec
+	q.count++
+	if q.count > q.high {
+		q.high = q.count
+	}
+	q.cond.Broadcast()
+}
+
+func (q *Queue) Read(p []byte) (int, error) {
+	if len(q.data) == 0 {
+		q.mu.Lock()
+		for q.count == 0 && q.err == nil {
+			q.cond.Wait()
+		}
+		if q.count == 0 {
+			err := q.err
+			q.mu.Unlock()
+			return 0, err
+		}
+		q.data = q.records[q.first]
+		q.records[q.first] = nil
+		q.first = (q.first + 1) % len(q.records)
+		q.count--
+		q.cond.Broadcast()
+		q.mu.Unlock()
+	}
+	n := copy(p, q.data)
+	q.data = q.data[n:]
+	return n, nil
+}
+
+// Close closes the source, the queued records are still read
+func (q *Queue) Close() error {
+	err := errClosed
+	q.once.Do(func() {
+		q.mu.Lock()
+		q.closed = true
+		q.cond.Broadcast()
+		q.mu.Unlock()
+		err = q.in.Close()
+	})
+	return err
+}
+
+// Dropped returns the number of records dropped by a full queue
+func (q *Queue) Dropped() int64 {
+	return q.dropped.Load()
+}
+
+// Lost returns the lost frames of the source and the dropped records
+func (q *Queue) Lost() int64 {
+	return Lost(q.in) + q.Dropped()
+}
+
+// Event ignores the decoded events, the queue is an analyzer for its report
+func (q *Queue) Event(*bus.Event) error {
+	return nil
+}
+
+// End ends the report
+func (q *Queue) End() error {
+	return nil
+}
+
+// Report writes the size, the maximum fill and the dropped records
+func (q *Queue) Report(out io.Writer) error {
+	q.mu.Lock()
+	high := q.high
+	q.mu.Unlock()
+	title := "Live queue"
+	_, err := fmt.Fprintf(out, "   %s\n   %s\n\n%d records, %s: %d records at most, %d records dropped\n",
+		title, strings.Repeat("-", len(title)), len(q.records), q.policy, high, q.Dropped())
+	return err
+}
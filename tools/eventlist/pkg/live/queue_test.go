@@ -0,0 +1,131 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package live
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// wait until the queue read all records of the source
+func waitReceived(q *Queue) {
+	for {
+		q.mu.Lock()
+		err := q.err
+		q.mu.Unlock()
+		if err != nil {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestQueue(t *testing.T) {
+	t.Parallel()
+
+	var data []byte
+	for i := 1; i <= 5; i++ {
+		data = append(data, record2(uint64(i), 0x0A01, int32(i))...)
+	}
+	tests := []struct {
+		policy      string
+		want        []int32
+		wantDropped int64
+	}{
+		{Block, []int32{1, 2, 3, 4, 5}, 0},
+		{DropOldest, []int32{4, 5}, 3},
+		{DropNewest, []int32{1, 2}, 3},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.policy, func(t *testing.T) {
+			t.Parallel()
+
+			q, err := NewQueue(io.NopCloser(bytes.NewReader(data)), 2, tt.policy)
+			if err != nil {
+				t.Fatalf("NewQueue() error = %v", err)
+			}
+			if tt.policy != Block {
+				waitReceived(q)
+			}
+			var got []int32
+			for _, e := range readRecords(t, q, len(tt.want)) {
+				got = append(got, e.Value1)
+			}
+			if !reflect.DeepEqual(got, tt.want) || q.Dropped() != tt.wantDropped || q.Lost() != tt.wantDropped {
+				t.Errorf("Queue %s = %v, dropped %d, want %v, dropped %d", tt.policy, got, q.Dropped(), tt.want, tt.wantDropped)
+			}
+			if n, err := q.Read(make([]byte, 1)); n != 0 || err != io.EOF {
+				t.Errorf("Queue.Read() %s = %d, %v, want EOF", tt.policy, n, err)
+			}
+			var b strings.Builder
+			if err := q.Report(&b); err != nil || !strings.Contains(b.String(), "2 records, "+tt.policy+": 2 records at most") {
+				t.Errorf("Queue.Report() %s = %q, %v", tt.policy, b.String(), err)
+			}
+			if err := q.Close(); err != nil {
+				t.Errorf("Queue.Close() %s error = %v", tt.policy, err)
+			}
+			if err := q.Close(); err == nil {
+				t.Errorf("Queue.Close() %s = nil, want error", tt.policy)
+			}
+		})
+	}
+}
+
+func TestQueue_close(t *testing.T) {
+	t.Parallel()
+
+	// a blocked queue is released by Close
+	r, w := io.Pipe()
+	q, err := NewQueue(r, 1, Block)
+	if err != nil {
+		t.Fatalf("NewQueue() error = %v", err)
+	}
+	go func() {
+		for i := 1; i <= 3; i++ {
+			if _, err := w.Write(record2(uint64(i), 0x0A01, int32(i))); err != nil {
+				return
+			}
+		}
+	}()
+	time.Sleep(10 * time.Millisecond)
+	_ = q.Close()
+	got := readRecords(t, q, 1)
+	if got[0].Value1 != 1 {
+		t.Errorf("Queue.Read() = %+v, want value 1", got[0])
+	}
+	if _, err := q.Read(make([]byte, 1)); err == nil {
+		t.Errorf("Queue.Read() error = nil, want error")
+	}
+}
+
+func TestNewQueue(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewQueue(io.NopCloser(bytes.NewReader(nil)), 1, "drop"); err == nil {
+		t.Errorf("NewQueue() error = nil, want error")
+	}
+	if _, err := NewQueue(io.NopCloser(bytes.NewReader(nil)), 0, Block); err != errQueueSize {
+		t.Errorf("NewQueue() error = %v, want %v", err, errQueueSize)
+	}
+}
@@ -0,0 +1,174 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package live
+
+import (
+	"bufio"
+	"eventlist/pkg/event"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RetryInterval is the time between the attempts to reopen a lost link
+var RetryInterval = time.Second
+
+// syncReader returns the complete event records of a stream. Bytes that
+// start no valid record are skipped up to the next record, a lost link is
+// opened again. Each gap is marked by an IDGap event with the number of
+// skipped bytes at the time of the last record.
+type syncReader struct {
+	mu     sync.Mutex // in
+	in     io.ReadCloser
+	rd     *bufio.Reader
+	reopen func() (io.ReadCloser, error) // nil if the link cannot be reopened
+	out    []byte                        // record not yet read
+	last   uint64                        // time of the last record
+	skip   int32                         // bytes skipped since the last record
+	gap    bool                          // a gap is to be marked
+	lost   int64                         // lost frames of the previous links
+	gaps   atomic.Int64
+	done   chan struct{}
+	once   sync.Once
+}
+
+func newSyncReader(in io.ReadCloser, reopen func() (io.ReadCloser, error)) *syncReader {
+	// the buffer holds the largest record
+	return &syncReader{in: in, rd: bufio.NewReaderSize(in, 1<<16), reopen: reopen, done: make(chan struct{})}
+}
+
+// recordLength returns the length of the record starting with head
+// after type and length, false if head starts no valid record
+func recordLength(head []byte) (int, bool) {
+	typ := event.ByteOrder.Uint16(head[0:2])
+	length := int(event.ByteOrder.Uint16(head[2:4]))
+	switch typ {
+	case 1:
+		dlen := int(event.ByteOrder.Uint16(head[14:16]) & 0x7FFF)
+		return length, length >= 12+dlen && length <= 12+dlen+3 // padded to words
+	case 2:
+		return length, length == 20
+	case 3:
+		return length, length == 28
+	}
+	return 0, false
+}
+
+// Lost returns the number of gaps and of the frames lost by the framing
+func (s *syncReader) Lost() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lost + Lost(s.in) + s.gaps.Load()
+}
+
+func (s *syncReader) Read(p []byte) (int, error) {
+	for len(s.out) == 0 {
+		if err := s.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, s.out)
+	s.out = s.out[n:]
+	return n, nil
+}
+
+// next reads the next record, after an IDGap event if bytes were skipped
+func (s *syncReader) next() error {
+	head, err := s.rd.Peek(16)
+	if err == nil {
+		length, ok := recordLength(head)
+		if !ok {
+			_, _ = s.rd.Discard(1)
+			s.skip++
+			return nil
+		}
+		var rec []byte
+		if rec, err = s.rd.Peek(4 + length); err == nil {
+			if s.skip != 0 || s.gap {
+				e := event.Data{Typ: 2, Time: s.last, Value2: s.skip}
+				e.Info.ID = event.IDGap
+				s.out = e.AppendRecord(s.out[:0])
+				s.skip, s.gap = 0, false
+				s.gaps.Add(1)
+			}
+			s.out = append(s.out, rec...)
+			s.last = event.ByteOrder.Uint64(rec[4:12])
+			_, _ = s.rd.Discard(len(rec))
+			return nil
+		}
+	}
+	if s.reopen == nil || s.closed() {
+		return err
+	}
+	// the link is lost, a partly received record is skipped
+	s.skip += int32(s.rd.Buffered())
+	s.gap = true
+	return s.reconnect()
+}
+
+// closed returns true after Close
+func (s *syncReader) closed() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// reconnect reopens the link until it succeeds or the reader is closed
+func (s *syncReader) reconnect() error {
+	s.mu.Lock()
+	_ = s.in.Close()
+	s.lost += Lost(s.in)
+	s.mu.Unlock()
+	for {
+		select {
+		case <-s.done:
+			return io.EOF
+		case <-time.After(RetryInterval):
+		}
+		in, err := s.reopen()
+		if err != nil {
+			continue
+		}
+		s.mu.Lock()
+		if s.closed() {
+			s.mu.Unlock()
+			_ = in.Close()
+			return io.EOF
+		}
+		s.in = in
+		s.rd.Reset(in)
+		s.mu.Unlock()
+		return nil
+	}
+}
+
+func (s *syncReader) Close() error {
+	err := errClosed
+	s.once.Do(func() {
+		close(s.done)
+		s.mu.Lock()
+		err = s.in.Close()
+		s.mu.Unlock()
+	})
+	return err
+}
@@ -0,0 +1,170 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package live
+
+import (
+	"bufio"
+	"bytes"
+	"eventlist/pkg/event"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func record2(time uint64, id uint16, val1 int32) []byte {
+	e := event.Data{Typ: 2, Time: time, Value1: val1}
+	e.Info.ID = id
+	return e.AppendRecord(nil)
+}
+
+// readRecords reads n records of r
+func readRecords(t *testing.T, r io.Reader, n int) []event.Data {
+	t.Helper()
+	in := bufio.NewReader(r)
+	var events []event.Data
+	for i := 0; i < n; i++ {
+		var e event.Data
+		if err := e.Read(in); err != nil {
+			t.Fatalf("Data.Read() error = %v", err)
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+func gapRecord(time uint64, skip int32) event.Data {
+	e := event.Data{Typ: 2, Time: time, Value2: skip}
+	e.Info.ID = event.IDGap
+	return e
+}
+
+func TestSyncReader(t *testing.T) {
+	t.Parallel()
+
+	var data []byte
+	data = append(data, record2(5, 0x0A01, 1)...)
+	data = append(data, "xyz"...)
+	data = append(data, record2(9, 0x0A01, 2)...)
+	s := newSyncReader(io.NopCloser(bytes.NewReader(data)), nil)
+	got := readRecords(t, s, 3)
+	want := []event.Data{readRecords(t, bytes.NewReader(record2(5, 0x0A01, 1)), 1)[0],
+		gapRecord(5, 3), readRecords(t, bytes.NewReader(record2(9, 0x0A01, 2)), 1)[0]}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("syncReader = %+v, want %+v", got, want)
+	}
+	if n, err := s.Read(make([]byte, 1)); n != 0 || err != io.EOF {
+		t.Errorf("syncReader.Read() = %d, %v, want EOF", n, err)
+	}
+	if s.Lost() != 1 {
+		t.Errorf("syncReader.Lost() = %d, want 1", s.Lost())
+	}
+}
+
+func TestSyncReader_reconnect(t *testing.T) { //nolint:golint,paralleltest
+	saved := RetryInterval
+	RetryInterval = 10 * time.Millisecond
+	defer func() { RetryInterval = saved }()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	defer l.Close()
+	go func() {
+		// the first link is lost in the middle of a record
+		for _, data := range [][]byte{
+			append(record2(5, 0x0A01, 1), record2(7, 0x0A01, 2)[:10]...),
+			record2(9, 0x0A01, 3),
+		} {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = c.Write(data)
+			c.Close()
+		}
+	}()
+	in, err := Open("tcp://"+l.Addr().String(), Options{Resync: true})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	got := readRecords(t, in, 3)
+	want := []event.Data{readRecords(t, bytes.NewReader(record2(5, 0x0A01, 1)), 1)[0],
+		gapRecord(5, 10), readRecords(t, bytes.NewReader(record2(9, 0x0A01, 3)), 1)[0]}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("syncReader = %+v, want %+v", got, want)
+	}
+	if Lost(in) != 1 {
+		t.Errorf("Lost() = %d, want 1", Lost(in))
+	}
+	go func() {
+		time.Sleep(2 * RetryInterval)
+		in.Close()
+	}()
+	if n, err := in.Read(make([]byte, 1)); n != 0 || err == nil {
+		t.Errorf("syncReader.Read() = %d, %v, want error", n, err)
+	}
+	if err := in.Close(); err == nil {
+		t.Errorf("syncReader.Close() = nil, want error")
+	}
+}
+
+func TestRecordLength(t *testing.T) {
+	t.Parallel()
+
+	data := event.Data{Time: 1}
+	data.SetPayload([]byte("hello"))
+	tests := []struct {
+		name   string
+		head   []byte
+		want   int
+		wantOk bool
+	}{
+		{"record2", record2(1, 0x0A01, 1), 20, true},
+		{"record4", (&event.Data{Typ: 3}).AppendRecord(nil), 28, true},
+		{"data", data.AppendRecord(nil), 20, true},
+		{"data length", append([]byte{1, 0, 24, 0}, make([]byte, 12)...), 24, false},
+		{"type", append([]byte{4, 0, 20, 0}, make([]byte, 12)...), 0, false},
+		{"record2 length", append([]byte{2, 0, 21, 0}, make([]byte, 12)...), 21, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := recordLength(tt.head[:16])
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("recordLength() %s = %d, %v, want %d, %v", tt.name, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestOpen_resync(t *testing.T) {
+	t.Parallel()
+
+	if _, err := Open("tcp://localhost:1", Options{Ack: true, Resync: true}); err != errResyncAck {
+		t.Errorf("Open() error = %v, want %v", err, errResyncAck)
+	}
+	if _, err := Open("pyocd:auto", Options{Info: 0x20000000, Resync: true}); err != errProbeResync {
+		t.Errorf("Open() error = %v, want %v", err, errProbeResync)
+	}
+}
@@ -0,0 +1,139 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package live
+
+import (
+	"eventlist/pkg/bus"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const topSize = 5 // number of components of a snapshot
+
+// Snapshot writes the statistics of the received events at an interval
+// while the live session goes on: the events and the event rate since the
+// last snapshot, the lost frames, the components with the most events and
+// the error counts.
+type Snapshot struct {
+	mu         sync.Mutex
+	out        io.Writer
+	events     int
+	last       int       // events at the last snapshot
+	lastAt     time.Time // time of the last snapshot
+	components map[string]int
+	errors     map[string]int
+	done       chan struct{}
+	once       sync.Once
+	Lost       func() int64 // lost frames of the source, none if nil
+}
+
+// NewSnapshot writes a snapshot to out at every interval until End
+func NewSnapshot(out io.Writer, interval time.Duration) *Snapshot {
+	s := &Snapshot{out: out, lastAt: time.Now(), components: make(map[string]int),
+		errors: make(map[string]int), done: make(chan struct{})}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.done:
+				return
+			case now := <-ticker.C:
+				_ = s.Write(now)
+			}
+		}
+	}()
+	return s
+}
+
+func (s *Snapshot) Event(ev *bus.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events++
+	s.components[ev.Component()]++
+	if ev.Level() == "Error" {
+		s.errors[ev.Component()]++
+	}
+	return nil
+}
+
+// End stops the snapshots
+func (s *Snapshot) End() error {
+	s.once.Do(func() { close(s.done) })
+	return nil
+}
+
+// top returns the counts sorted by decreasing count and name, at most n
+func top(counts map[string]int, n int) []counter {
+	list := make([]counter, 0, len(counts))
+	for c, count := range counts {
+		list = append(list, counter{c, count})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].Component < list[j].Component
+	})
+	if n > 0 && len(list) > n {
+		list = list[:n]
+	}
+	return list
+}
+
+// join the counts as "name count, ..."
+func join(list []counter) string {
+	parts := make([]string, len(list))
+	for i, c := range list {
+		parts[i] = fmt.Sprintf("%s %d", c.Component, c.Count)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Write writes the snapshot at the time now
+func (s *Snapshot) Write(now time.Time) error {
+	s.mu.Lock()
+	rate := 0.0
+	if d := now.Sub(s.lastAt).Seconds(); d > 0 {
+		rate = float64(s.events-s.last) / d
+	}
+	failed := 0
+	for _, n := range s.errors {
+		failed += n
+	}
+	text := fmt.Sprintf("[%s] %d events, %.1f events/s, %d errors", now.Format("15:04:05"), s.events, rate, failed)
+	if s.Lost != nil {
+		text += fmt.Sprintf(", %d lost", s.Lost())
+	}
+	text += "\n"
+	if len(s.components) != 0 {
+		text += "  top: " + join(top(s.components, topSize)) + "\n"
+	}
+	if len(s.errors) != 0 {
+		text += "  errors: " + join(top(s.errors, 0)) + "\n"
+	}
+	s.last, s.lastAt = s.events, now
+	s.mu.Unlock()
+	_, err := io.WriteString(s.out, text)
+	return err
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package live
+
+import (
+	"eventlist/pkg/bus"
+	"eventlist/pkg/event"
+	"eventlist/pkg/xml/scvd"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSnapshot(t *testing.T) {
+	t.Parallel()
+
+	var b strings.Builder
+	s := NewSnapshot(&b, time.Hour)
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	s.lastAt = start
+	s.Lost = func() int64 { return 2 }
+	for i, c := range []string{"Net", "Motor", "Net", "RTX", "Net", "Motor"} {
+		level := "Op"
+		if c == "Motor" {
+			level = "Error"
+		}
+		def := &scvd.Event{Brief: c, Property: "Send", Level: level}
+		if err := s.Event(bus.NewEvent(i, float64(i), &event.Data{}, def, nil)); err != nil {
+			t.Errorf("Snapshot.Event() error = %v", err)
+		}
+	}
+	if err := s.Write(start.Add(2 * time.Second)); err != nil {
+		t.Errorf("Snapshot.Write() error = %v", err)
+	}
+	_ = s.Event(newEvent(6, 6, "Op"))
+	if err := s.Write(start.Add(4 * time.Second)); err != nil {
+		t.Errorf("Snapshot.Write() error = %v", err)
+	}
+	want := "[12:00:02] 6 events, 3.0 events/s, 2 errors, 2 lost\n" +
+		"  top: Net 3, Motor 2, RTX 1\n" +
+		"  errors: Motor 2\n" +
+		"[12:00:04] 7 events, 0.5 events/s, 2 errors, 2 lost\n" +
+		"  top: Net 4, Motor 2, RTX 1\n" +
+		"  errors: Motor 2\n"
+	if b.String() != want {
+		t.Errorf("Snapshot.Write() = %q, want %q", b.String(), want)
+	}
+	if err := s.End(); err != nil {
+		t.Errorf("Snapshot.End() error = %v", err)
+	}
+}
+
+func Test_top(t *testing.T) {
+	t.Parallel()
+
+	counts := map[string]int{"a": 1, "b": 3, "c": 3, "d": 2}
+	tests := []struct {
+		name string
+		n    int
+		want []counter
+	}{
+		{"all", 0, []counter{{"b", 3}, {"c", 3}, {"d", 2}, {"a", 1}}},
+		{"two", 2, []counter{{"b", 3}, {"c", 3}}},
+		{"more", 5, []counter{{"b", 3}, {"c", 3}, {"d", 2}, {"a", 1}}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := top(counts, tt.n); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("top() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package live
+
+import (
+	"eventlist/pkg/bus"
+	"eventlist/pkg/rtos"
+	"fmt"
+	"html/template"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+const (
+	recentSize  = 50 // number of recent events on the page
+	historySize = 60 // number of seconds of the sparklines
+)
+
+type entry struct {
+	Index     int
+	Time      float64
+	Level     string
+	Component string
+	Property  string
+	Value     string
+}
+
+// Status collects the state of the live event stream shown on the status page
+type Status struct {
+	mu       sync.Mutex
+	recent   []entry
+	errors   map[string]int
+	counts   map[string]int // events per component
+	rate     []float64      // events per second
+	load     []float64      // CPU load per second, negative if unknown
+	second   int64          // current second of the event time
+	count    int            // events in the current second
+	events   int
+	lastIdle float64
+	tracker  *rtos.Tracker
+	Metrics  http.Handler // served at /metrics if set
+}
+
+func NewStatus() *Status {
+	return &Status{errors: make(map[string]int), counts: make(map[string]int), tracker: rtos.NewTracker()}
+}
+
+func appendHistory(h []float64, v float64) []float64 {
+	h = append(h, v)
+	if len(h) > historySize {
+		h = h[len(h)-historySize:]
+	}
+	return h
+}
+
+// close the current second of the event time
+func (s *Status) next() {
+	s.rate = appendHistory(s.rate, float64(s.count))
+	load := -1.0
+	if idle, ok := s.tracker.IdleTime(float64(s.second + 1)); ok {
+		load = math.Max(0, math.Min(1, 1-(idle-s.lastIdle)))
+		s.lastIdle = idle
+	}
+	s.load = appendHistory(s.load, load)
+	s.count = 0
+	s.second++
+}
+
+func (s *Status) Event(ev *bus.Event) error {
+	value, _ := ev.Value()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	second := int64(ev.Time)
+	if s.events == 0 || second < s.second || second-s.second > historySize {
+		s.second = second // start or jump of the time
+		s.count = 0
+	}
+	for s.second < second {
+		s.next()
+	}
+	if err := s.tracker.Event(ev); err != nil {
+		return err
+	}
+	s.count++
+	s.events++
+	s.counts[ev.Component()]++
+	if ev.Level() == "Error" {
+		s.errors[ev.Component()]++
+	}
+	s.recent = append(s.recent, entry{ev.Index, ev.Time, ev.Level(), ev.Component(), ev.Property(), value})
+	if len(s.recent) > recentSize {
+		s.recent = s.recent[len(s.recent)-recentSize:]
+	}
+	return nil
+}
+
+func (s *Status) End() error {
+	return nil
+}
+
+type counter struct {
+	Component string
+	Count     int
+}
+
+type page struct {
+	Events int
+	Rate   string
+	Load   string
+	Errors []counter
+	Top    []counter
+	Recent []entry
+	RateSL template.HTML
+	LoadSL template.HTML
+}
+
+// draw a sparkline of the values, nil if there are no values
+func sparkline(values []float64, max float64) template.HTML {
+	var points string
+	for i, v := range values {
+		if v < 0 {
+			continue
+		}
+		if max <= 0 {
+			max = 1
+		}
+		points += fmt.Sprintf("%d,%.1f ", i*4, 30-v/max*30)
+	}
+	if points == "" {
+		return ""
+	}
+	return template.HTML(fmt.Sprintf(`<svg width="%d" height="32"><polyline fill="none" stroke="#06c" points="%s"/></svg>`, //nolint:gosec
+		historySize*4, points))
+}
+
+// get the content of the page
+func (s *Status) page() page {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := page{Events: s.events, Rate: "-", Load: "n/a"}
+	max := 0.0
+	for _, r := range s.rate {
+		max = math.Max(max, r)
+	}
+	if len(s.rate) > 0 {
+		p.Rate = fmt.Sprintf("%.0f", s.rate[len(s.rate)-1])
+	}
+	if len(s.load) > 0 && s.load[len(s.load)-1] >= 0 {
+		p.Load = fmt.Sprintf("%.0f%%", s.load[len(s.load)-1]*100)
+	}
+	p.RateSL = sparkline(s.rate, max)
+	p.LoadSL = sparkline(s.load, 1)
+	for c, n := range s.errors {
+		p.Errors = append(p.Errors, counter{c, n})
+	}
+	sort.Slice(p.Errors, func(i, j int) bool { return p.Errors[i].Component < p.Errors[j].Component })
+	p.Top = top(s.counts, topSize)
+	for i := len(s.recent) - 1; i >= 0; i-- {
+		p.Recent = append(p.Recent, s.recent[i])
+	}
+	return p
+}
+
+func (s *Status) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := statusPage.Execute(w, s.page()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Serve serves the status page and the metrics at the address in the background
+func (s *Status) Serve(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/", s)
+	if s.Metrics != nil {
+		mux.Handle("/metrics", s.Metrics)
+	}
+	go http.Serve(l, mux) //nolint:errcheck,gosec
+	return nil
+}
+
+var statusPage = template.Must(template.New("status").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="1">
+<title>eventlist live status</title>
+<style>
+body { font-family: sans-serif; margin: 20px; }
+table { border-collapse: collapse; font-size: 13px; }
+th, td { border: 1px solid #ccc; padding: 2px 6px; text-align: left; }
+th { background: #eee; }
+.Error { color: #d00; }
+</style>
+</head>
+<body>
+<h1>Live status</h1>
+<table>
+<tr><th>Events</th><td>{{.Events}}</td></tr>
+<tr><th>Events/s</th><td>{{.Rate}} {{.RateSL}}</td></tr>
+<tr><th>CPU load</th><td>{{.Load}} {{.LoadSL}}</td></tr>
+</table>
+<h2>Errors</h2>
+<table>
+<tr><th>Component</th><th>Count</th></tr>
+{{- range .Errors}}
+<tr><td>{{.Component}}</td><td class="Error">{{.Count}}</td></tr>
+{{- end}}
+</table>
+<h2>Top components</h2>
+<table>
+<tr><th>Component</th><th>Events</th></tr>
+{{- range .Top}}
+<tr><td>{{.Component}}</td><td>{{.Count}}</td></tr>
+{{- end}}
+</table>
+<h2>Recent events</h2>
+<table>
+<tr><th>Index</th><th>Time (s)</th><th>Component</th><th>Event Property</th><th>Value</th></tr>
+{{- range .Recent}}
+<tr class="{{.Level}}"><td>{{.Index}}</td><td>{{printf "%.8f" .Time}}</td><td>{{.Component}}</td><td>{{.Property}}</td><td>{{.Value}}</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
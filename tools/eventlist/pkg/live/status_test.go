@@ -0,0 +1,161 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package live
+
+import (
+	"eventlist/pkg/bus"
+	"eventlist/pkg/event"
+	"eventlist/pkg/xml/scvd"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newEvent(index int, time float64, level string) *bus.Event {
+	def := &scvd.Event{Brief: "Net", Property: "Send", Level: level}
+	return bus.NewEvent(index, time, &event.Data{}, def, func() (string, error) { return "len=<4>", nil })
+}
+
+func TestStatus(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		events []*bus.Event
+		rate   []float64
+		errors map[string]int
+		recent int
+	}{
+		{"empty", nil, nil, map[string]int{}, 0},
+		{"one second", []*bus.Event{newEvent(0, 0.1, "Op"), newEvent(1, 0.2, "Error")}, nil, map[string]int{"Net": 1}, 2},
+		{"seconds", []*bus.Event{newEvent(0, 0.1, "Op"), newEvent(1, 0.2, "Op"), newEvent(2, 2.5, "Op")}, []float64{2, 0}, map[string]int{}, 3},
+		{"jump", []*bus.Event{newEvent(0, 0.1, "Op"), newEvent(1, 1000, "Op"), newEvent(2, 1001, "Op")}, []float64{1}, map[string]int{}, 3},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := NewStatus()
+			for _, ev := range tt.events {
+				if err := s.Event(ev); err != nil {
+					t.Errorf("Status.Event() %s error = %v", tt.name, err)
+				}
+			}
+			if err := s.End(); err != nil {
+				t.Errorf("Status.End() %s error = %v", tt.name, err)
+			}
+			if len(s.rate) != len(tt.rate) {
+				t.Errorf("Status.Event() %s rate = %v, want %v", tt.name, s.rate, tt.rate)
+			}
+			for i := range tt.rate {
+				if i < len(s.rate) && s.rate[i] != tt.rate[i] {
+					t.Errorf("Status.Event() %s rate = %v, want %v", tt.name, s.rate, tt.rate)
+				}
+			}
+			for c, n := range tt.errors {
+				if s.errors[c] != n {
+					t.Errorf("Status.Event() %s errors = %v, want %v", tt.name, s.errors, tt.errors)
+				}
+			}
+			if len(s.recent) != tt.recent {
+				t.Errorf("Status.Event() %s recent = %v, want %v", tt.name, len(s.recent), tt.recent)
+			}
+		})
+	}
+}
+
+func TestStatus_recent(t *testing.T) {
+	t.Parallel()
+
+	s := NewStatus()
+	for i := 0; i < recentSize+10; i++ {
+		_ = s.Event(newEvent(i, float64(i)/100, "Op"))
+	}
+	p := s.page()
+	if len(p.Recent) != recentSize || p.Recent[0].Index != recentSize+9 {
+		t.Errorf("Status.page() recent = %v, want %v starting at %v", len(p.Recent), recentSize, recentSize+9)
+	}
+	if want := []counter{{"Net", recentSize + 10}}; !reflect.DeepEqual(p.Top, want) {
+		t.Errorf("Status.page() top = %v, want %v", p.Top, want)
+	}
+}
+
+func Test_sparkline(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		values []float64
+		max    float64
+		want   string
+	}{
+		{"empty", nil, 1, ""},
+		{"unknown", []float64{-1, -1}, 1, ""},
+		{"values", []float64{0, 1, 2}, 2, `points="0,30.0 4,15.0 8,0.0 "`},
+		{"zero", []float64{0}, 0, `points="0,30.0 "`},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := string(sparkline(tt.values, tt.max))
+			if !strings.Contains(got, tt.want) || (tt.want == "" && got != "") {
+				t.Errorf("sparkline() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatus_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	s := NewStatus()
+	_ = s.Event(newEvent(0, 0.5, "Error"))
+	_ = s.Event(newEvent(1, 1.5, "Op"))
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	body := w.Body.String()
+	for _, want := range []string{
+		`<meta http-equiv="refresh" content="1">`,
+		"<tr><th>Events</th><td>2</td></tr>",
+		`<tr><td>Net</td><td class="Error">1</td></tr>`,
+		`<tr class="Op"><td>1</td><td>1.50000000</td><td>Net</td><td>Send</td><td>len=&lt;4&gt;</td></tr>`,
+		"<td>n/a </td>",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Status.ServeHTTP() = %v, want %v", body, want)
+		}
+	}
+}
+
+func TestStatus_Serve(t *testing.T) {
+	t.Parallel()
+
+	s := NewStatus()
+	if err := s.Serve("127.0.0.1:0"); err != nil {
+		t.Errorf("Status.Serve() error = %v", err)
+	}
+	if err := s.Serve("nix:port"); err == nil {
+		t.Errorf("Status.Serve() error = nil, want error")
+	}
+}
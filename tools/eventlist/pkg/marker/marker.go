@@ -0,0 +1,117 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package marker lists the marker events, events of selected IDs or with the
+// SCVD attribute marker, e.g. the start of a test case, so the interesting
+// places of a long log are found quickly.
+package marker
+
+import (
+	"eventlist/pkg/bus"
+	"eventlist/pkg/xml/scvd"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Mark is one marker event
+type Mark struct {
+	Index     int
+	Time      float64
+	Component string
+	Property  string
+	Value     string
+}
+
+// Markers selects the marker events and keeps them for the report
+type Markers struct {
+	ranges [][2]uint16 // marker IDs of the command line
+	Marks  []Mark
+}
+
+// New creates the marker list, the events with the SCVD attribute
+// marker="true" are markers
+func New() *Markers {
+	return &Markers{}
+}
+
+// Add makes the events of the IDs first to last markers
+func (m *Markers) Add(first, last uint16) {
+	m.ranges = append(m.ranges, [2]uint16{first, last})
+}
+
+// Is reports if an event is a marker, def is nil if the event has no
+// definition
+func (m *Markers) Is(id uint16, def *scvd.Event) bool {
+	if def != nil && def.Marker {
+		return true
+	}
+	for _, r := range m.ranges {
+		if id >= r[0] && id <= r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// Event keeps a marker event
+func (m *Markers) Event(ev *bus.Event) error {
+	if !m.Is(ev.Data.Info.ID, ev.Def) {
+		return nil
+	}
+	value, err := ev.Value()
+	if err != nil {
+		value = "<" + err.Error() + ">"
+	}
+	m.Marks = append(m.Marks, Mark{Index: ev.Index, Time: ev.Time,
+		Component: ev.Component(), Property: ev.Property(), Value: value})
+	return nil
+}
+
+// End does nothing, the markers are kept for the report
+func (m *Markers) End() error {
+	return nil
+}
+
+// Report lists the markers with index and time
+func (m *Markers) Report(out io.Writer) error {
+	title := "Markers"
+	if _, err := fmt.Fprintf(out, "   %s\n   %s\n\n", title, strings.Repeat("-", len(title))); err != nil {
+		return err
+	}
+	if len(m.Marks) == 0 {
+		_, err := fmt.Fprintf(out, "No markers\n")
+		return err
+	}
+	componentSize := len("Component")
+	for _, mark := range m.Marks {
+		if len(mark.Component) > componentSize {
+			componentSize = len(mark.Component)
+		}
+	}
+	if _, err := fmt.Fprintf(out, "%5s %-10s %-*s %s\n", "Index", "Time (s)", componentSize, "Component", "Event"); err != nil {
+		return err
+	}
+	for _, mark := range m.Marks {
+		if _, err := fmt.Fprintf(out, "%5d %.8f %-*s %s %s\n", mark.Index, mark.Time,
+			componentSize, mark.Component, mark.Property, mark.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package marker
+
+import (
+	"bytes"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/event"
+	"eventlist/pkg/xml/scvd"
+	"testing"
+)
+
+var test = &scvd.Event{Brief: "Test", Property: "TestStart", Marker: true}
+
+func TestMarkers_Is(t *testing.T) {
+	t.Parallel()
+
+	m := New()
+	m.Add(0xA000, 0xA00F)
+	m.Add(0xB001, 0xB001)
+	tests := []struct {
+		name string
+		id   uint16
+		def  *scvd.Event
+		want bool
+	}{
+		{"range", 0xA005, nil, true},
+		{"single", 0xB001, nil, true},
+		{"outside", 0xA010, nil, false},
+		{"definition", 0x1000, test, true},
+		{"no marker", 0x1000, &scvd.Event{}, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := m.Is(tt.id, tt.def); got != tt.want {
+				t.Errorf("Markers.Is() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkers_Report(t *testing.T) {
+	t.Parallel()
+
+	value := func(s string) func() (string, error) { return func() (string, error) { return s, nil } }
+	events := []*bus.Event{
+		bus.NewEvent(0, 0.5, &event.Data{Info: event.Info{ID: 0x1000}}, test, value("case=1")),
+		bus.NewEvent(1, 1.0, &event.Data{Info: event.Info{ID: 0x2000}}, nil, value("x")),
+		bus.NewEvent(2, 1.5, &event.Data{Info: event.Info{ID: 0xA001}}, nil, value("val1=0x00000002")),
+	}
+	tests := []struct {
+		name   string
+		events []*bus.Event
+		want   string
+	}{
+		{"none", nil, "   Markers\n   -------\n\nNo markers\n"},
+		{"markers", events, "   Markers\n   -------\n\n" +
+			"Index Time (s)   Component Event\n" +
+			"    0 0.50000000 Test      TestStart case=1\n" +
+			"    2 1.50000000 0xA0      0xA001 val1=0x00000002\n"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := New()
+			m.Add(0xA000, 0xA00F)
+			for _, ev := range tt.events {
+				_ = m.Event(ev)
+			}
+			if err := m.End(); err != nil {
+				t.Errorf("Markers.End() %s error = %v", tt.name, err)
+			}
+			var b bytes.Buffer
+			if err := m.Report(&b); err != nil || b.String() != tt.want {
+				t.Errorf("Markers.Report() %s = %q, %v, want %q", tt.name, b.String(), err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package merge
+
+import (
+	"bufio"
+	"errors"
+	"eventlist/pkg/eval"
+	"eventlist/pkg/event"
+	"io"
+)
+
+// input is a capture with its next record
+type input struct {
+	rd   *bufio.Reader
+	next event.Data
+	ok   bool // next holds a record
+}
+
+func (in *input) read() error {
+	in.next = event.Data{}
+	err := in.next.Read(in.rd)
+	if errors.Is(err, eval.ErrEof) {
+		in.ok = false
+		return nil
+	}
+	in.ok = err == nil
+	return err
+}
+
+// Merge writes the records of the captures ordered by their timestamps and
+// returns the number of written records. Records with the same timestamp
+// keep the order of the captures. The timestamps are compared as recorded,
+// so the captures must use the same timestamp clock.
+func Merge(w io.Writer, captures ...io.Reader) (int, error) {
+	inputs := make([]*input, 0, len(captures))
+	for _, r := range captures {
+		in := &input{rd: bufio.NewReader(r)}
+		if err := in.read(); err != nil {
+			return 0, err
+		}
+		inputs = append(inputs, in)
+	}
+	out := bufio.NewWriter(w)
+	var buf []byte
+	count := 0
+	for {
+		var first *input
+		for _, in := range inputs {
+			if in.ok && (first == nil || in.next.Time < first.next.Time) {
+				first = in
+			}
+		}
+		if first == nil {
+			break
+		}
+		buf = first.next.AppendRecord(buf[:0])
+		if _, err := out.Write(buf); err != nil {
+			return count, err
+		}
+		count++
+		if err := first.read(); err != nil {
+			return count, err
+		}
+	}
+	return count, out.Flush()
+}
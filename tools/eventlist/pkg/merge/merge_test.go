@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package merge
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"eventlist/pkg/eval"
+	"eventlist/pkg/event"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// capture with a type 2 record per timestamp, val1 is the value
+func capture(times []uint64, value int32) []byte {
+	var buf []byte
+	for _, t := range times {
+		ev := event.Data{Typ: 2, Time: t, Value1: value, Info: event.Info{ID: 0x1000}}
+		buf = ev.AppendRecord(buf)
+	}
+	return buf
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	type rec struct {
+		time  uint64
+		value int32
+	}
+	tests := []struct {
+		name     string
+		captures [][]byte
+		want     []rec
+		wantErr  error
+	}{
+		{"none", nil, nil, nil},
+		{"empty", [][]byte{{}, capture([]uint64{1}, 1)}, []rec{{1, 1}}, nil},
+		{"interleaved", [][]byte{capture([]uint64{1, 4, 5}, 1), capture([]uint64{2, 3, 6}, 2)},
+			[]rec{{1, 1}, {2, 2}, {3, 2}, {4, 1}, {5, 1}, {6, 2}}, nil},
+		{"same time", [][]byte{capture([]uint64{1, 2}, 1), capture([]uint64{1, 2}, 2), capture([]uint64{2}, 3)},
+			[]rec{{1, 1}, {1, 2}, {2, 1}, {2, 2}, {2, 3}}, nil},
+		{"truncated", [][]byte{capture([]uint64{1}, 1), capture([]uint64{2}, 2)[:10]}, nil, io.ErrUnexpectedEOF},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var readers []io.Reader
+			for _, c := range tt.captures {
+				readers = append(readers, bytes.NewReader(c))
+			}
+			var out bytes.Buffer
+			n, err := Merge(&out, readers...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Merge() %s error = %v, want %v", tt.name, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			var got []rec
+			rd := bufio.NewReader(&out)
+			for {
+				var ev event.Data
+				if err := ev.Read(rd); err != nil {
+					if !errors.Is(err, eval.ErrEof) {
+						t.Errorf("Merge() %s record error = %v", tt.name, err)
+					}
+					break
+				}
+				got = append(got, rec{ev.Time, ev.Value1})
+			}
+			if n != len(tt.want) || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Merge() %s = %d %v, want %d %v", tt.name, n, got, len(tt.want), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package metrics serves the counters of the received events and the
+// durations of the start/stop events in the Prometheus text format.
+package metrics
+
+import (
+	"bytes"
+	"eventlist/pkg/bus"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Buckets are the upper bounds of the duration histograms in s
+var Buckets = []float64{1e-6, 1e-5, 1e-4, 1e-3, 1e-2, 0.1, 1, 10}
+
+type key struct {
+	component string
+	property  string
+	id        uint16
+}
+
+type histogram struct {
+	counts []int // per bucket, not cumulative
+	count  int
+	sum    float64
+}
+
+// Metrics counts the events per component and event ID and collects
+// the durations of the start/stop events per group and slot
+type Metrics struct {
+	mu        sync.Mutex
+	counts    map[key]int
+	starts    map[uint16]float64 // time of the start events without stop event
+	durations map[uint16]*histogram
+	lost      func() int64 // number of frames lost by the source, nil if unknown
+}
+
+func New() *Metrics {
+	return &Metrics{counts: make(map[key]int), starts: make(map[uint16]float64),
+		durations: make(map[uint16]*histogram)}
+}
+
+// SetLost sets the function returning the number of frames lost by the source
+func (m *Metrics) SetLost(lost func() int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lost = lost
+}
+
+func (m *Metrics) Event(ev *bus.Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.counts[key{ev.Component(), ev.Property(), ev.Data.Info.ID}]++
+	class, group, idx, start := ev.Data.Info.SplitID()
+	if class != 0xEF {
+		return nil
+	}
+	slot := group<<4 | idx
+	if start {
+		m.starts[slot] = ev.Time
+		return nil
+	}
+	begin, ok := m.starts[slot]
+	if !ok {
+		return nil
+	}
+	delete(m.starts, slot)
+	h := m.durations[slot]
+	if h == nil {
+		h = &histogram{counts: make([]int, len(Buckets))}
+		m.durations[slot] = h
+	}
+	d := ev.Time - begin
+	for i, le := range Buckets {
+		if d <= le {
+			h.counts[i]++
+			break
+		}
+	}
+	h.count++
+	h.sum += d
+	return nil
+}
+
+func (m *Metrics) End() error {
+	return nil
+}
+
+// escape a label value
+var escaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// Write writes the metrics in the Prometheus text format
+func (m *Metrics) Write(b *bytes.Buffer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b.WriteString("# HELP eventlist_events_total Number of received events per component and event ID.\n")
+	b.WriteString("# TYPE eventlist_events_total counter\n")
+	keys := make([]key, 0, len(m.counts))
+	for k := range m.counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].component != keys[j].component {
+			return keys[i].component < keys[j].component
+		}
+		return keys[i].id < keys[j].id
+	})
+	for _, k := range keys {
+		fmt.Fprintf(b, "eventlist_events_total{component=\"%s\",id=\"0x%04X\",property=\"%s\"} %d\n",
+			escaper.Replace(k.component), k.id, escaper.Replace(k.property), m.counts[k])
+	}
+
+	if m.lost != nil {
+		b.WriteString("# HELP eventlist_lost_frames_total Number of frames of the source that were corrupted or lost.\n")
+		b.WriteString("# TYPE eventlist_lost_frames_total counter\n")
+		fmt.Fprintf(b, "eventlist_lost_frames_total %d\n", m.lost())
+	}
+
+	b.WriteString("# HELP eventlist_duration_seconds Time between the start and the stop events.\n")
+	b.WriteString("# TYPE eventlist_duration_seconds histogram\n")
+	slots := make([]int, 0, len(m.durations))
+	for slot := range m.durations {
+		slots = append(slots, int(slot))
+	}
+	sort.Ints(slots)
+	for _, slot := range slots {
+		h := m.durations[uint16(slot)]
+		name := fmt.Sprintf("%c(%d)", byte(slot>>4+'A'), slot&0xF)
+		cumulative := 0
+		for i, le := range Buckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(b, "eventlist_duration_seconds_bucket{event=\"%s\",le=\"%s\"} %d\n",
+				name, strconv.FormatFloat(le, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(b, "eventlist_duration_seconds_bucket{event=\"%s\",le=\"+Inf\"} %d\n", name, h.count)
+		fmt.Fprintf(b, "eventlist_duration_seconds_sum{event=\"%s\"} %s\n", name, strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(b, "eventlist_duration_seconds_count{event=\"%s\"} %d\n", name, h.count)
+	}
+}
+
+// ServeHTTP serves the metrics for a scrape
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	var b bytes.Buffer
+	m.Write(&b)
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write(b.Bytes())
+}
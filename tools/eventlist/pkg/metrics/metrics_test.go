@@ -0,0 +1,80 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"eventlist/pkg/bus"
+	"eventlist/pkg/event"
+	"eventlist/pkg/xml/scvd"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	thread := &scvd.Event{Brief: "RTX \"Thread\"", Property: "Created"}
+	m := New()
+	m.SetLost(func() int64 { return 3 })
+	events := []struct {
+		id   uint16
+		time float64
+		def  *scvd.Event
+	}{
+		{0xEF00, 0.25, nil}, {0xF401, 0.5, thread}, {0xEF20, 0.2505, nil},
+		{0xEF20, 0.5, nil}, {0xEF00, 1.0, nil}, {0xF401, 1.5, thread}, {0xEF20, 3.0, nil},
+	}
+	for i, e := range events {
+		ev := bus.NewEvent(i, e.time, &event.Data{Info: event.Info{ID: e.id}}, e.def, nil)
+		if err := m.Event(ev); err != nil {
+			t.Errorf("Metrics.Event() error = %v", err)
+		}
+	}
+	if err := m.End(); err != nil {
+		t.Errorf("Metrics.End() error = %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Metrics.ServeHTTP() content type = %s", ct)
+	}
+	for _, want := range []string{
+		"# TYPE eventlist_events_total counter\n",
+		`eventlist_events_total{component="0xEF",id="0xEF00",property="0xEF00"} 2`,
+		`eventlist_events_total{component="0xEF",id="0xEF20",property="0xEF20"} 3`,
+		`eventlist_events_total{component="RTX \"Thread\"",id="0xF401",property="Created"} 2`,
+		"eventlist_lost_frames_total 3\n",
+		"# TYPE eventlist_duration_seconds histogram\n",
+		`eventlist_duration_seconds_bucket{event="A(0)",le="0.001"} 1`,
+		`eventlist_duration_seconds_bucket{event="A(0)",le="1"} 1`,
+		`eventlist_duration_seconds_bucket{event="A(0)",le="10"} 2`,
+		`eventlist_duration_seconds_bucket{event="A(0)",le="+Inf"} 2`,
+		`eventlist_duration_seconds_count{event="A(0)"} 2`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("Metrics.ServeHTTP() = %s, want %s", body, want)
+		}
+	}
+}
@@ -0,0 +1,316 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package mqtt publishes the decoded events to an MQTT broker with the
+// protocol version 3.1.1, one JSON message per event to the topic of its
+// component.
+package mqtt
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/where"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultPrefix is the prefix of the topics without path in the URL
+const DefaultPrefix = "eventlist"
+
+// AckTimeout is the time End waits for the acknowledgments of QoS 1
+var AckTimeout = 5 * time.Second
+
+var errURL = errors.New("invalid MQTT URL")
+
+var errRefused = errors.New("MQTT connection refused")
+
+// packet types
+const (
+	connect    = 0x10
+	connack    = 0x20
+	publish    = 0x30
+	puback     = 0x40
+	disconnect = 0xE0
+)
+
+// Message is the JSON payload of an event
+type Message struct {
+	Index     int     `json:"index"`
+	Time      float64 `json:"time"`
+	ID        string  `json:"id"`
+	Component string  `json:"component"`
+	Property  string  `json:"property"`
+	Level     string  `json:"level,omitempty"`
+	Value     string  `json:"value"`
+}
+
+// Publisher publishes the events passing the filter to the topic
+// <prefix>/<component>
+type Publisher struct {
+	broker    string
+	prefix    string
+	qos       byte
+	conn      net.Conn
+	out       *bufio.Writer
+	filter    *where.Filter
+	id        uint16
+	buf       []byte
+	mu        sync.Mutex
+	acked     int
+	acks      chan struct{}
+	readErr   error
+	Published int // number of published events
+	Dropped   int // number of events not matching the filter
+}
+
+// New connects to the broker of the URL
+// mqtt://[user:password@]host[:port][/prefix][?qos=1&client=id],
+// a nil filter publishes all events
+func New(rawURL string, filter *where.Filter) (*Publisher, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "mqtt" || len(u.Host) == 0 {
+		return nil, fmt.Errorf("%w: %s", errURL, rawURL)
+	}
+	p := &Publisher{prefix: strings.Trim(u.Path, "/"), filter: filter, acks: make(chan struct{}, 1)}
+	if len(p.prefix) == 0 {
+		p.prefix = DefaultPrefix
+	}
+	switch q := u.Query().Get("qos"); q {
+	case "", "0":
+	case "1":
+		p.qos = 1
+	default:
+		return nil, fmt.Errorf("%w: qos %s", errURL, q)
+	}
+	client := u.Query().Get("client")
+	if len(client) == 0 {
+		client = "eventlist-" + strconv.Itoa(os.Getpid())
+	}
+	p.broker = u.Host
+	if u.Port() == "" {
+		p.broker = net.JoinHostPort(u.Hostname(), "1883")
+	}
+	if p.conn, err = net.DialTimeout("tcp", p.broker, 10*time.Second); err != nil {
+		return nil, err
+	}
+	p.out = bufio.NewWriter(p.conn)
+	if err = p.connect(client, u.User); err != nil {
+		p.conn.Close()
+		return nil, err
+	}
+	go p.read()
+	return p, nil
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+	return append(b, s...)
+}
+
+// write a packet with its fixed header
+func (p *Publisher) write(typ byte, body []byte) error {
+	header := []byte{typ}
+	for n := len(body); ; {
+		c := byte(n & 0x7F)
+		n >>= 7
+		if n > 0 {
+			c |= 0x80
+		}
+		header = append(header, c)
+		if n == 0 {
+			break
+		}
+	}
+	if _, err := p.out.Write(header); err != nil {
+		return err
+	}
+	_, err := p.out.Write(body)
+	return err
+}
+
+// connect with a clean session and without keep alive
+func (p *Publisher) connect(client string, user *url.Userinfo) error {
+	flags := byte(0x02)
+	var password string
+	hasPassword := false
+	if user != nil {
+		flags |= 0x80
+		if password, hasPassword = user.Password(); hasPassword {
+			flags |= 0x40
+		}
+	}
+	body := appendString(nil, "MQTT")
+	body = append(body, 4, flags, 0, 0)
+	body = appendString(body, client)
+	if user != nil {
+		body = appendString(body, user.Username())
+	}
+	if hasPassword {
+		body = appendString(body, password)
+	}
+	if err := p.write(connect, body); err != nil {
+		return err
+	}
+	if err := p.out.Flush(); err != nil {
+		return err
+	}
+	_ = p.conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	var ack [4]byte
+	if _, err := io.ReadFull(p.conn, ack[:]); err != nil {
+		return err
+	}
+	_ = p.conn.SetReadDeadline(time.Time{})
+	if ack[0] != connack || ack[1] != 2 {
+		return fmt.Errorf("%w: unexpected packet 0x%02X", errRefused, ack[0])
+	}
+	if ack[3] != 0 {
+		return fmt.Errorf("%w: return code %d", errRefused, ack[3])
+	}
+	return nil
+}
+
+// count the acknowledgments of QoS 1 until the connection is closed
+func (p *Publisher) read() {
+	in := bufio.NewReader(p.conn)
+	for {
+		var packet [4]byte
+		_, err := io.ReadFull(in, packet[:2])
+		if err == nil && packet[1] > 2 {
+			err = fmt.Errorf("unexpected packet 0x%02X", packet[0])
+		}
+		if err == nil {
+			_, err = io.ReadFull(in, packet[2:2+packet[1]])
+		}
+		p.mu.Lock()
+		if err != nil {
+			p.readErr = err
+		} else if packet[0] == puback {
+			p.acked++
+		}
+		p.mu.Unlock()
+		select {
+		case p.acks <- struct{}{}:
+		default:
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// topic of a component, the wildcards and separators of MQTT are replaced
+func (p *Publisher) topic(component string) string {
+	return p.prefix + "/" + strings.Map(func(r rune) rune {
+		if r == '/' || r == '+' || r == '#' {
+			return '_'
+		}
+		return r
+	}, component)
+}
+
+// Event publishes the event if it passes the filter
+func (p *Publisher) Event(ev *bus.Event) error {
+	if !p.filter.Match(ev) {
+		p.Dropped++
+		return nil
+	}
+	value, _ := ev.Value()
+	payload, err := json.Marshal(Message{
+		Index:     ev.Index,
+		Time:      ev.Time,
+		ID:        fmt.Sprintf("0x%04X", ev.Data.Info.ID),
+		Component: ev.Component(),
+		Property:  ev.Property(),
+		Level:     ev.Level(),
+		Value:     value,
+	})
+	if err != nil {
+		return err
+	}
+	p.buf = appendString(p.buf[:0], p.topic(ev.Component()))
+	if p.qos == 1 {
+		p.id++
+		if p.id == 0 {
+			p.id = 1
+		}
+		p.buf = binary.BigEndian.AppendUint16(p.buf, p.id)
+	}
+	p.buf = append(p.buf, payload...)
+	if err = p.write(publish|p.qos<<1, p.buf); err != nil {
+		return err
+	}
+	p.Published++
+	return p.out.Flush()
+}
+
+// End waits for the acknowledgments and disconnects
+func (p *Publisher) End() error {
+	var err error
+	if p.qos == 1 {
+		err = p.wait()
+	}
+	if e := p.write(disconnect, nil); err == nil {
+		err = e
+	}
+	if e := p.out.Flush(); err == nil {
+		err = e
+	}
+	if e := p.conn.Close(); err == nil {
+		err = e
+	}
+	return err
+}
+
+// wait until all published events are acknowledged
+func (p *Publisher) wait() error {
+	timeout := time.After(AckTimeout)
+	for {
+		p.mu.Lock()
+		acked, readErr := p.acked, p.readErr
+		p.mu.Unlock()
+		switch {
+		case acked >= p.Published:
+			return nil
+		case readErr != nil:
+			return fmt.Errorf("%d of %d events not acknowledged: %w", p.Published-acked, p.Published, readErr)
+		}
+		select {
+		case <-p.acks:
+		case <-timeout:
+			return fmt.Errorf("%d of %d events not acknowledged", p.Published-acked, p.Published)
+		}
+	}
+}
+
+// Report writes the number of published and filtered events
+func (p *Publisher) Report(out io.Writer) error {
+	title := "MQTT"
+	_, err := fmt.Fprintf(out, "   %s\n   %s\n\n%s: %d events published to %s/#, %d events filtered out\n",
+		title, strings.Repeat("-", len(title)), p.broker, p.Published, p.prefix, p.Dropped)
+	return err
+}
@@ -0,0 +1,199 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mqtt
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/event"
+	"eventlist/pkg/where"
+	"eventlist/pkg/xml/scvd"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type packet struct {
+	typ  byte
+	body []byte
+}
+
+func readPacket(in *bufio.Reader) (packet, error) {
+	typ, err := in.ReadByte()
+	if err != nil {
+		return packet{}, err
+	}
+	n, shift := 0, 0
+	for {
+		c, err := in.ReadByte()
+		if err != nil {
+			return packet{}, err
+		}
+		n |= int(c&0x7F) << shift
+		shift += 7
+		if c&0x80 == 0 {
+			break
+		}
+	}
+	p := packet{typ: typ, body: make([]byte, n)}
+	_, err = io.ReadFull(in, p.body)
+	return p, err
+}
+
+// broker accepts one client, answers CONNECT with the return code and
+// passes the packets received until DISCONNECT
+func broker(t *testing.T, code byte) (string, <-chan []packet) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	received := make(chan []packet, 1)
+	go func() {
+		defer l.Close()
+		var packets []packet
+		defer func() { received <- packets }()
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		in := bufio.NewReader(conn)
+		for {
+			p, err := readPacket(in)
+			if err != nil {
+				return
+			}
+			packets = append(packets, p)
+			switch p.typ & 0xF0 {
+			case connect:
+				_, _ = conn.Write([]byte{connack, 2, 0, code})
+			case publish:
+				if p.typ&0x06 != 0 {
+					topic := int(binary.BigEndian.Uint16(p.body))
+					_, _ = conn.Write(append([]byte{puback, 2}, p.body[2+topic:4+topic]...))
+				}
+			case disconnect:
+				return
+			}
+		}
+	}()
+	return l.Addr().String(), received
+}
+
+func newEvent(index int, id uint16, def *scvd.Event) *bus.Event {
+	return bus.NewEvent(index, float64(index)/10, &event.Data{Info: event.Info{ID: id}}, def,
+		func() (string, error) { return "v=1", nil })
+}
+
+func TestPublisher(t *testing.T) {
+	t.Parallel()
+
+	net := &scvd.Event{Brief: "Net/IP", Property: "Rx", Level: "Op"}
+	tests := []struct {
+		name    string
+		query   string
+		user    string
+		filter  []string
+		topics  []string
+		connect string
+	}{
+		{"qos 0", "", "", nil, []string{"eventlist/Net_IP", "eventlist/0xFE"}, "\x00\x04MQTT\x04\x02"},
+		{"qos 1", "/fleet/dev1?qos=1&client=dev1", "user:pw@", nil, []string{"fleet/dev1/Net_IP", "fleet/dev1/0xFE"}, "\x00\x04MQTT\x04\xC2\x00\x00\x00\x04dev1\x00\x04user\x00\x02pw"},
+		{"filter", "", "", []string{"property=Rx"}, []string{"eventlist/Net_IP"}, ""},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			addr, received := broker(t, 0)
+			filter, _ := where.Parse(tt.filter)
+			p, err := New("mqtt://"+tt.user+addr+tt.query, filter)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			for i, ev := range []*bus.Event{newEvent(0, 0x0A01, net), newEvent(1, 0xFE00, nil)} {
+				if err := p.Event(ev); err != nil {
+					t.Errorf("Publisher.Event() %d error = %v", i, err)
+				}
+			}
+			if err := p.End(); err != nil {
+				t.Errorf("Publisher.End() error = %v", err)
+			}
+			packets := <-received
+			if len(packets) != len(tt.topics)+2 {
+				t.Fatalf("packets = %d, want %d", len(packets), len(tt.topics)+2)
+			}
+			if !strings.HasPrefix(string(packets[0].body), tt.connect) {
+				t.Errorf("CONNECT = %q, want prefix %q", packets[0].body, tt.connect)
+			}
+			var topics []string
+			for _, pk := range packets[1 : len(packets)-1] {
+				n := int(binary.BigEndian.Uint16(pk.body))
+				topics = append(topics, string(pk.body[2:2+n]))
+				payload := pk.body[2+n:]
+				if pk.typ&0x06 != 0 {
+					payload = payload[2:]
+				}
+				var m Message
+				if err := json.Unmarshal(payload, &m); err != nil || m.Value != "v=1" {
+					t.Errorf("PUBLISH payload = %s, error = %v", payload, err)
+				}
+			}
+			if !reflect.DeepEqual(topics, tt.topics) {
+				t.Errorf("PUBLISH topics = %v, want %v", topics, tt.topics)
+			}
+			if p.Published != len(tt.topics) || p.Dropped != 2-len(tt.topics) {
+				t.Errorf("Publisher = %d published, %d dropped, want %d, %d", p.Published, p.Dropped, len(tt.topics), 2-len(tt.topics))
+			}
+			var b bytes.Buffer
+			if err := p.Report(&b); err != nil || !strings.Contains(b.String(), "events published") {
+				t.Errorf("Publisher.Report() = %q, %v", b.String(), err)
+			}
+		})
+	}
+}
+
+func TestNew_errors(t *testing.T) {
+	t.Parallel()
+
+	addr, _ := broker(t, 5)
+	tests := []struct {
+		name string
+		url  string
+		want error
+	}{
+		{"scheme", "tcp://" + addr, errURL},
+		{"host", "mqtt:///x", errURL},
+		{"qos", "mqtt://" + addr + "?qos=2", errURL},
+		{"refused", "mqtt://" + addr, errRefused},
+	}
+	for _, tt := range tests {
+		if _, err := New(tt.url, nil); !errors.Is(err, tt.want) {
+			t.Errorf("New() %s error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
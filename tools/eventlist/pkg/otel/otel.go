@@ -0,0 +1,226 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package otel exports the start/stop event pairs as spans with the
+// OTLP/gRPC trace service of OpenTelemetry, so the activity of the
+// firmware shows up next to the traces of the backend, e.g. in Jaeger.
+package otel
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/binary"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/otel/pb"
+	"eventlist/pkg/rtos"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// BatchSize is the number of spans sent with one export request
+const BatchSize = 512
+
+// Timeout is the time an export request may take
+var Timeout = 10 * time.Second
+
+// resource of a span, one per component and thread
+type resource struct {
+	service string
+	thread  string
+}
+
+type start struct {
+	time     float64
+	index    int
+	value    string
+	resource resource
+}
+
+// Exporter sends a span per start/stop event pair, from the start to the
+// next stop event of the same slot, named A(0) to D(15). The component of
+// the start event is the service, the running thread the attribute
+// thread.name of the resource. The spans of a session belong to one trace,
+// their time is the start of the export plus the event time.
+type Exporter struct {
+	target   string
+	conn     *grpc.ClientConn
+	client   pb.TraceServiceClient
+	tracker  *rtos.Tracker
+	traceID  []byte
+	base     int64 // start of the export in ns
+	starts   map[uint16]start
+	batch    map[resource][]*pb.Span
+	spans    int    // spans in batch
+	spanID   uint64 // ID of the last span
+	Exported int    // number of exported spans
+}
+
+// New connects to the OTLP/gRPC receiver at the target host[:port], the
+// port defaults to 4317, with the prefix https:// the connection uses TLS
+func New(target string) (*Exporter, error) {
+	creds := insecure.NewCredentials()
+	addr := strings.TrimPrefix(target, "http://")
+	if a, ok := strings.CutPrefix(target, "https://"); ok {
+		addr = a
+		creds = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	}
+	addr = strings.TrimSuffix(addr, "/")
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "4317")
+	}
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+	e := &Exporter{target: target, conn: conn, client: pb.NewTraceServiceClient(conn),
+		tracker: rtos.NewTracker(), traceID: make([]byte, 16), base: time.Now().UnixNano(),
+		starts: make(map[uint16]start), batch: make(map[resource][]*pb.Span)}
+	if _, err = rand.Read(e.traceID); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return e, nil
+}
+
+func stringValue(s string) *pb.AnyValue {
+	return &pb.AnyValue{Value: &pb.AnyValue_StringValue{StringValue: s}}
+}
+
+func attribute(key string, v *pb.AnyValue) *pb.KeyValue {
+	return &pb.KeyValue{Key: key, Value: v}
+}
+
+// timestamp of an event time in ns
+func (e *Exporter) timestamp(t float64) uint64 {
+	return uint64(e.base + int64(t*1e9))
+}
+
+// Event collects the span of a stop event
+func (e *Exporter) Event(ev *bus.Event) error {
+	if err := e.tracker.Event(ev); err != nil {
+		return err
+	}
+	class, group, idx, isStart := ev.Data.Info.SplitID()
+	if class != 0xEF {
+		return nil
+	}
+	slot := group<<4 | idx
+	if isStart {
+		value, _ := ev.Value()
+		res := resource{service: ev.Component()}
+		if id, ok := e.tracker.Current(); ok {
+			res.thread = e.tracker.Name(id)
+		}
+		e.starts[slot] = start{ev.Time, ev.Index, value, res}
+		return nil
+	}
+	begin, ok := e.starts[slot]
+	if !ok {
+		return nil
+	}
+	delete(e.starts, slot)
+	value, _ := ev.Value()
+	e.spanID++
+	span := &pb.Span{
+		TraceId:           e.traceID,
+		SpanId:            binary.BigEndian.AppendUint64(nil, e.spanID),
+		Name:              fmt.Sprintf("%c(%d)", byte(group+'A'), idx),
+		Kind:              pb.Span_SPAN_KIND_INTERNAL,
+		StartTimeUnixNano: e.timestamp(begin.time),
+		EndTimeUnixNano:   e.timestamp(ev.Time),
+		Attributes: []*pb.KeyValue{
+			attribute("eventlist.index", &pb.AnyValue{Value: &pb.AnyValue_IntValue{IntValue: int64(begin.index)}}),
+			attribute("eventlist.start.value", stringValue(begin.value)),
+			attribute("eventlist.stop.value", stringValue(value)),
+		},
+	}
+	e.batch[begin.resource] = append(e.batch[begin.resource], span)
+	e.spans++
+	if e.spans >= BatchSize {
+		return e.export()
+	}
+	return nil
+}
+
+// send the spans of the batch, a resource per component and thread
+func (e *Exporter) export() error {
+	if e.spans == 0 {
+		return nil
+	}
+	resources := make([]resource, 0, len(e.batch))
+	for res := range e.batch {
+		resources = append(resources, res)
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].service != resources[j].service {
+			return resources[i].service < resources[j].service
+		}
+		return resources[i].thread < resources[j].thread
+	})
+	req := &pb.ExportTraceServiceRequest{}
+	for _, res := range resources {
+		attrs := []*pb.KeyValue{attribute("service.name", stringValue(res.service))}
+		if res.thread != "" {
+			attrs = append(attrs, attribute("thread.name", stringValue(res.thread)))
+		}
+		req.ResourceSpans = append(req.ResourceSpans, &pb.ResourceSpans{
+			Resource:   &pb.Resource{Attributes: attrs},
+			ScopeSpans: []*pb.ScopeSpans{{Scope: &pb.InstrumentationScope{Name: "eventlist"}, Spans: e.batch[res]}},
+		})
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	resp, err := e.client.Export(ctx, req)
+	if err != nil {
+		return fmt.Errorf("otlp export: %w", err)
+	}
+	e.Exported += e.spans
+	e.batch = make(map[resource][]*pb.Span)
+	e.spans = 0
+	if p := resp.GetPartialSuccess(); p != nil && p.RejectedSpans != 0 {
+		e.Exported -= int(p.RejectedSpans)
+		return fmt.Errorf("otlp export: %d spans rejected: %s", p.RejectedSpans, p.ErrorMessage)
+	}
+	return nil
+}
+
+// End sends the remaining spans and closes the connection
+func (e *Exporter) End() error {
+	err := e.export()
+	if cerr := e.conn.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Report writes the number of exported spans
+func (e *Exporter) Report(out io.Writer) error {
+	title := "OpenTelemetry"
+	_, err := fmt.Fprintf(out, "   %s\n   %s\n\n%s: %d spans exported\n",
+		title, strings.Repeat("-", len(title)), e.target, e.Exported)
+	return err
+}
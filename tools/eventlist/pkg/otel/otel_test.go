@@ -0,0 +1,161 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package otel
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/event"
+	"eventlist/pkg/otel/pb"
+	"eventlist/pkg/xml/scvd"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// receiver of the exported spans
+type receiver struct {
+	pb.UnimplementedTraceServiceServer
+	mu       sync.Mutex
+	requests []*pb.ExportTraceServiceRequest
+	resp     *pb.ExportTraceServiceResponse
+	err      error
+}
+
+func (r *receiver) Export(_ context.Context, req *pb.ExportTraceServiceRequest) (*pb.ExportTraceServiceResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req)
+	if r.resp == nil {
+		return &pb.ExportTraceServiceResponse{}, r.err
+	}
+	return r.resp, r.err
+}
+
+func serveReceiver(t *testing.T, r *receiver) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	s := grpc.NewServer()
+	pb.RegisterTraceServiceServer(s, r)
+	go func() { _ = s.Serve(l) }()
+	t.Cleanup(s.Stop)
+	return l.Addr().String()
+}
+
+var switched = &scvd.Event{Brief: "RTX Thread", Property: "ThreadSwitched"}
+
+func writeEvents(t *testing.T, e *Exporter) error {
+	t.Helper()
+	e.base = 0
+	events := []struct {
+		id    uint16
+		time  float64
+		value int32
+		def   *scvd.Event
+	}{
+		{0xEF00, 0.25, 0, nil}, {0xF419, 0.5, 0x20000010, switched}, {0xEF20, 0.75, 0, nil},
+		{0xEF01, 1.0, 0, nil}, {0xEF21, 1.5, 0, nil}, {0xEF22, 2.0, 0, nil},
+	}
+	for i, ev := range events {
+		data := &event.Data{Info: event.Info{ID: ev.id}, Value1: ev.value}
+		if err := e.Event(bus.NewEvent(i, ev.time, data, ev.def, nil)); err != nil {
+			t.Errorf("Exporter.Event() error = %v", err)
+		}
+	}
+	return e.End()
+}
+
+func TestExporter(t *testing.T) {
+	t.Parallel()
+
+	r := &receiver{}
+	e, err := New("http://" + serveReceiver(t, r))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err = writeEvents(t, e); err != nil {
+		t.Errorf("Exporter.End() error = %v", err)
+	}
+	if len(r.requests) != 1 {
+		t.Fatalf("Exporter requests = %d, want 1", len(r.requests))
+	}
+	var got []string
+	for _, rs := range r.requests[0].ResourceSpans {
+		var attrs []string
+		for _, kv := range rs.Resource.Attributes {
+			attrs = append(attrs, kv.Key+"="+kv.Value.GetStringValue())
+		}
+		for _, span := range rs.ScopeSpans[0].Spans {
+			got = append(got, strings.Join(attrs, ",")+" "+span.Name+" "+
+				time.Duration(span.EndTimeUnixNano-span.StartTimeUnixNano).String())
+			if !bytes.Equal(span.TraceId, e.traceID) || len(span.SpanId) != 8 {
+				t.Errorf("Exporter span %s IDs = %x, %x", span.Name, span.TraceId, span.SpanId)
+			}
+		}
+	}
+	want := []string{
+		"service.name=0xEF A(0) 500ms",
+		"service.name=0xEF,thread.name=0x20000010 A(1) 500ms",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Exporter spans = %q, want %q", got, want)
+	}
+	var b bytes.Buffer
+	if err := e.Report(&b); err != nil || !strings.Contains(b.String(), "2 spans exported") {
+		t.Errorf("Exporter.Report() = %q, %v", b.String(), err)
+	}
+}
+
+func TestExporter_error(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		r    *receiver
+		want string
+	}{
+		{"error", &receiver{err: errors.New("unavailable")}, "otlp export: "},
+		{"rejected", &receiver{resp: &pb.ExportTraceServiceResponse{
+			PartialSuccess: &pb.ExportTracePartialSuccess{RejectedSpans: 1, ErrorMessage: "too old"}}},
+			"otlp export: 1 spans rejected: too old"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e, err := New(serveReceiver(t, tt.r))
+			if err != nil {
+				t.Fatalf("New() %s error = %v", tt.name, err)
+			}
+			if err = writeEvents(t, e); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Exporter.End() %s error = %v, want %s", tt.name, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+var errExporter = errors.New("exporter already registered")
+
+// Exporter writes the event list of Print in a format, Begin is called
+// before the first event, Stats with the start/stop event statistic and
+// End after the events and the statistic
+type Exporter interface {
+	Begin(out io.Writer) error
+	Event(out io.Writer, rec *EventRecord) error
+	Stats(out io.Writer, stats []EventRecordStatistic) error
+	End(out io.Writer) error
+}
+
+// NewExporter creates an exporter for one Print
+type NewExporter func() Exporter
+
+// exporters of the formats selected with -f, json, xml and html are
+// marshalled from the collected events and are not exporters
+var exporters = map[string]NewExporter{
+	"txt":   func() Exporter { return &textWriter{} },
+	"jsonl": func() Exporter { return &jsonlWriter{} },
+	"stats": func() Exporter { return &statsWriter{} },
+}
+
+// RegisterExporter adds the exporter of a format, e.g. in an init function
+// of a package compiled into the tool
+func RegisterExporter(format string, fn NewExporter) error {
+	if _, ok := exporters[format]; ok || marshalledFormat(format) {
+		return fmt.Errorf("%w: %s", errExporter, format)
+	}
+	exporters[format] = fn
+	return nil
+}
+
+// Formats returns the sorted formats of the registered exporters
+func Formats() []string {
+	formats := make([]string, 0, len(exporters))
+	for format := range exporters {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// IsFormat reports if an exporter of the format is registered
+func IsFormat(format string) bool {
+	_, ok := exporters[format]
+	return ok
+}
+
+func marshalledFormat(format string) bool {
+	return format == "json" || format == "xml" || format == "html"
+}
+
+// the events are collected and marshalled at the end
+func marshalled() bool {
+	return marshalledFormat(FormatType)
+}
+
+// exporter of the event list, the text writer by default
+func (o *Output) export() Exporter {
+	if o.exporter == nil {
+		o.exporter = &textWriter{}
+	}
+	if t, ok := o.exporter.(*textWriter); ok {
+		t.o = o
+	}
+	return o.exporter
+}
+
+// textWriter writes the event list as text, nothing is written if
+// the format is not txt
+type textWriter struct {
+	o *Output
+}
+
+func (t *textWriter) Begin(out io.Writer) error {
+	return t.o.printHeader(out)
+}
+
+func (t *textWriter) Event(out io.Writer, rec *EventRecord) error {
+	return t.o.printEvent(out, rec)
+}
+
+func (t *textWriter) Stats(out io.Writer, stats []EventRecordStatistic) error {
+	if FormatType != "txt" {
+		return nil
+	}
+	if _, err := io.WriteString(out, "   Start/Stop event statistic\n"+
+		"   --------------------------\n\n"+
+		"Event count      total       min         max         average     first       last\n"+
+		"----- -----      -----       ---         ---         -------     -----       ----\n"); err != nil {
+		return err
+	}
+	for _, st := range stats {
+		_, err := fmt.Fprintf(out, "%-5s %5d%s %s %s %s %s %s %s\n"+
+			"      Min: Start: %.8f %s Stop: %.8f %s\n"+
+			"      Max: Start: %.8f %s Stop: %.8f %s\n\n",
+			st.Event, st.Count, st.AddCount, st.Total, st.Min, st.Max, st.Avg, st.First, st.Last,
+			st.MinTime, st.TextMinB, st.MinStopTime, st.TextMinE,
+			st.MaxTime, st.TextMaxB, st.MaxStopTime, st.TextMaxE)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *textWriter) End(io.Writer) error {
+	return nil
+}
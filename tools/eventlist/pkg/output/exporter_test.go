@@ -0,0 +1,125 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testExporter writes the calls of the exporter
+type testExporter struct{}
+
+func (testExporter) Begin(out io.Writer) error {
+	_, err := io.WriteString(out, "begin\n")
+	return err
+}
+
+func (testExporter) Event(out io.Writer, rec *EventRecord) error {
+	_, err := fmt.Fprintf(out, "event %d %s\n", rec.Index, rec.Component)
+	return err
+}
+
+func (testExporter) Stats(out io.Writer, stats []EventRecordStatistic) error {
+	_, err := fmt.Fprintf(out, "stats %d\n", len(stats))
+	return err
+}
+
+func (testExporter) End(out io.Writer) error {
+	_, err := io.WriteString(out, "end\n")
+	return err
+}
+
+func TestRegisterExporter(t *testing.T) { //nolint:golint,paralleltest
+	defer delete(exporters, "test")
+
+	tests := []struct {
+		name    string
+		format  string
+		wantErr bool
+	}{
+		{"new", "test", false},
+		{"twice", "test", true},
+		{"txt", "txt", true},
+		{"json", "json", true},
+		{"html", "html", true},
+	}
+	for _, tt := range tests {
+		err := RegisterExporter(tt.format, func() Exporter { return testExporter{} })
+		if (err != nil) != tt.wantErr || (err != nil && !errors.Is(err, errExporter)) {
+			t.Errorf("RegisterExporter() %s = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+	if got, want := Formats(), []string{"jsonl", "stats", "test", "txt"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Formats() = %v, want %v", got, want)
+	}
+	if !IsFormat("test") || IsFormat("json") {
+		t.Errorf("IsFormat() = %v, %v, want true, false", IsFormat("test"), IsFormat("json"))
+	}
+}
+
+func TestPrint_exporter(t *testing.T) { //nolint:golint,paralleltest
+	exporters["test"] = func() Exporter { return testExporter{} }
+	defer func() {
+		delete(exporters, "test")
+		TimeFactor = nil
+		FormatType = "txt"
+	}()
+
+	filename := filepath.Join(t.TempDir(), "out.txt")
+	eventFile := "../../testdata/test10.binary"
+	formatType := "test"
+	level := ""
+	if err := Print(&filename, &formatType, &level, &eventFile, nil, nil, nil, false, false); err != nil {
+		t.Fatalf("Print() error = %v", err)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("os.ReadFile() error = %v", err)
+	}
+	want := "begin\nevent 0 0xFF\nevent 1 0xFE\nstats 0\nend\n"
+	if string(data) != want {
+		t.Errorf("Print() %s = %q, want %q", formatType, data, want)
+	}
+}
+
+func TestTextWriter_Stats(t *testing.T) {
+	t.Parallel()
+
+	var o Output
+	var b strings.Builder
+	stats := []EventRecordStatistic{{Event: "A(0)", Count: 2, Total: "1s"}, {Event: "B(12)", Count: 1}}
+	if err := o.export().Stats(&b, stats); err != nil {
+		t.Fatalf("textWriter.Stats() error = %v", err)
+	}
+	want := "   Start/Stop event statistic\n   --------------------------\n\n" +
+		"Event count      total       min         max         average     first       last\n" +
+		"----- -----      -----       ---         ---         -------     -----       ----\n" +
+		"A(0)      2 1s     \n      Min: Start: 0.00000000  Stop: 0.00000000 \n      Max: Start: 0.00000000  Stop: 0.00000000 \n\n" +
+		"B(12)     1      \n      Min: Start: 0.00000000  Stop: 0.00000000 \n      Max: Start: 0.00000000  Stop: 0.00000000 \n\n"
+	if b.String() != want {
+		t.Errorf("textWriter.Stats() = %q, want %q", b.String(), want)
+	}
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output
+
+import (
+	"bufio"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+)
+
+// BufferSize is the size of the output buffer in bytes
+var BufferSize = 64 << 10
+
+// FlushInterval is the longest time the live events stay in the output
+// buffer, 0 flushes the buffer after every event
+var FlushInterval time.Duration
+
+// flusher flushes the output of the live mode after the flush interval
+// and on the flush signals, the writer is used with the flusher locked
+type flusher struct {
+	mu       sync.Mutex
+	out      *bufio.Writer
+	interval time.Duration
+	signals  chan os.Signal
+	done     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// start flushing out in the background
+func newFlusher(out *bufio.Writer, interval time.Duration) *flusher {
+	f := &flusher{out: out, interval: interval, done: make(chan struct{})}
+	var ticker *time.Ticker
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker = time.NewTicker(interval)
+		tick = ticker.C
+	}
+	if len(flushSignals) > 0 {
+		f.signals = make(chan os.Signal, 1)
+		signal.Notify(f.signals, flushSignals...)
+	}
+	if tick == nil && f.signals == nil {
+		return f
+	}
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		if ticker != nil {
+			defer ticker.Stop()
+		}
+		f.run(tick)
+	}()
+	return f
+}
+
+// flush on the ticks and signals until stopped
+func (f *flusher) run(tick <-chan time.Time) {
+	for {
+		select {
+		case <-tick:
+		case <-f.signals:
+		case <-f.done:
+			return
+		}
+		f.mu.Lock()
+		if f.out.Buffered() > 0 {
+			_ = f.out.Flush() // a write error is returned by the next write
+		}
+		f.mu.Unlock()
+	}
+}
+
+// lock the writer to write an event
+func (f *flusher) lock() {
+	f.mu.Lock()
+}
+
+// unlock the writer after an event has been written, without interval
+// the output is flushed
+func (f *flusher) unlock() error {
+	var err error
+	if f.interval <= 0 {
+		err = f.out.Flush()
+	}
+	f.mu.Unlock()
+	return err
+}
+
+// stop flushing in the background, the output is not flushed
+func (f *flusher) stop() {
+	if f.signals != nil {
+		signal.Stop(f.signals)
+	}
+	close(f.done)
+	f.wg.Wait()
+}
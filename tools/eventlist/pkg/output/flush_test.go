@@ -0,0 +1,99 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+)
+
+// buffer safe for reading while the flusher writes
+type syncBuffer struct {
+	mu sync.Mutex
+	b  bytes.Buffer
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.Write(p)
+}
+
+func (s *syncBuffer) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.String()
+}
+
+func TestFlusher(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		interval time.Duration
+	}{
+		{"every event", 0},
+		{"interval", 10 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var s syncBuffer
+			out := bufio.NewWriterSize(&s, 1024)
+			f := newFlusher(out, tt.interval)
+			f.lock()
+			_, _ = out.WriteString("event\n")
+			if err := f.unlock(); err != nil {
+				t.Errorf("flusher.unlock() %s error = %v", tt.name, err)
+			}
+			deadline := time.Now().Add(5 * time.Second)
+			for s.String() == "" && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+			f.stop()
+			if got := s.String(); got != "event\n" {
+				t.Errorf("flusher %s = %q, want %q", tt.name, got, "event\n")
+			}
+		})
+	}
+}
+
+func TestFlusher_interval(t *testing.T) {
+	t.Parallel()
+
+	var s syncBuffer
+	out := bufio.NewWriterSize(&s, 1024)
+	f := newFlusher(out, time.Hour)
+	f.lock()
+	_, _ = out.WriteString("event\n")
+	if err := f.unlock(); err != nil {
+		t.Errorf("flusher.unlock() error = %v", err)
+	}
+	f.stop()
+	if got := s.String(); got != "" {
+		t.Errorf("flusher.unlock() = %q, want buffered output", got)
+	}
+	if got := out.Buffered(); got != 6 {
+		t.Errorf("bufio.Writer.Buffered() = %d, want %d", got, 6)
+	}
+}
@@ -0,0 +1,212 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output
+
+import (
+	"bufio"
+	"eventlist/pkg/eval"
+	"eventlist/pkg/event"
+	"eventlist/pkg/index"
+	"eventlist/pkg/xml/scvd"
+	"fmt"
+	"io"
+)
+
+// Index writes an index file next to the log file and uses it in later
+// runs to seek to the first printed event and over blocks without events
+// passing Where
+var Index bool
+
+// counts the bytes read from the log file
+type countReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// indexer adds the records read by the statistic pass to the index
+type indexer struct {
+	builder *index.Builder
+	count   countReader
+	in      *bufio.Reader
+}
+
+// offset of the next record
+func (x *indexer) offset() int64 {
+	return x.count.n - int64(x.in.Buffered())
+}
+
+// add the record at the offset, tm is the time base before the record
+func (x *indexer) add(offset int64, ev *event.Data, tm *timer) {
+	clock := index.Clock{Time: tm.lastClockEvent, Before: tm.beforeClockEvent}
+	if tm.clocked && TimeFactor != nil {
+		clock.Factor = *TimeFactor
+	}
+	x.builder.Add(offset, ev.Time, ev.Info.ID, clock)
+}
+
+// load the index of the log file, without one the statistic pass builds it
+func (o *Output) loadIndex(b *event.Binary, eventFile string) {
+	o.index, o.indexer = nil, nil
+	if !Index {
+		return
+	}
+	ix, err := index.Load(eventFile)
+	if err == nil {
+		o.index = ix
+		return
+	}
+	x := &indexer{builder: index.NewBuilder(index.DefaultBlockSize)}
+	wrap := b.Wrap
+	b.Wrap = func(r io.Reader) io.Reader {
+		b.Wrap = wrap // count the bytes of the statistic pass only
+		if wrap != nil {
+			r = wrap(r)
+		}
+		x.count.r = r
+		return &x.count
+	}
+	o.indexer = x
+}
+
+// save the index built by the statistic pass if it has read all events
+func (o *Output) saveIndex(eventFile string, eventCount int) {
+	x := o.indexer
+	o.indexer = nil
+	if x == nil || x.in == nil || x.builder.Index().Records != int64(eventCount) {
+		return
+	}
+	o.index = x.builder.Index()
+	_ = o.index.Save(eventFile) // the index only speeds up later runs
+}
+
+// cursor reads the records of the event pass, with an index it seeks
+// over the blocks before the first printed event and over the blocks
+// without a component passing Where
+type cursor struct {
+	in    *bufio.Reader
+	file  *event.Binary // seeks over the blocks, nil discards them
+	tm    timer
+	no    int
+	ix    *index.Index
+	block int // next block of the index
+	skip  func(b *index.Block) bool
+	stop  *shutdown // ends the reading when interrupted
+}
+
+func (o *Output) newCursor(in *bufio.Reader, evdefs map[uint16]scvd.Event) *cursor {
+	c := &cursor{in: in, file: o.file, stop: o.shutdown}
+	// squashed, tracked and not shown events depend on the events before
+	if o.index == nil || FormatType != "txt" || Squash || o.tracking() {
+		return c
+	}
+	c.ix = o.index
+	if Where != nil {
+		names := componentNames(evdefs)
+		c.skip = func(b *index.Block) bool {
+			for no := 0; no < 256; no++ {
+				if b.HasComponent(uint8(no)) && Where.Component(uint8(no), names[no]) {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	for c.block < len(c.ix.Blocks) {
+		b := &c.ix.Blocks[c.block]
+		if int(b.Record+b.Count) > o.first && (c.skip == nil || !c.skip(b)) {
+			break
+		}
+		c.discard()
+	}
+	return c
+}
+
+// read the next record, with skip it is not read completely
+func (c *cursor) next(ev *event.Data, skip bool) error {
+	if c.stop.interrupted() {
+		return eval.ErrEof
+	}
+	for c.ix != nil && c.block < len(c.ix.Blocks) && c.no >= int(c.ix.Blocks[c.block].Record) {
+		if c.skip == nil || !c.skip(&c.ix.Blocks[c.block]) {
+			c.block++ // read the records of the block
+			break
+		}
+		c.discard()
+	}
+	if skip {
+		return ev.Skip(c.in)
+	}
+	return ev.Read(c.in)
+}
+
+// discard the records of the next block and take the time base of the
+// block after
+func (c *cursor) discard() {
+	b := &c.ix.Blocks[c.block]
+	if end := c.ix.End(c.block); c.file == nil || !c.file.Seek(c.in, end) {
+		_ = discard(c.in, end-b.Offset)
+	}
+	c.no = int(b.Record + b.Count)
+	c.block++
+	if c.block < len(c.ix.Blocks) {
+		clock := c.ix.Blocks[c.block].Clock
+		c.tm.lastClockEvent, c.tm.beforeClockEvent = clock.Time, clock.Before
+		if clock.Factor != 0 {
+			if TimeFactor == nil {
+				TimeFactor = new(float64)
+			}
+			*TimeFactor = clock.Factor
+			c.tm.clocked = true
+		}
+	}
+}
+
+// discard n bytes, n may exceed the int of 32-bit platforms
+func discard(in *bufio.Reader, n int64) error {
+	const chunk = 1 << 30
+	for n > 0 {
+		size := n
+		if size > chunk {
+			size = chunk
+		}
+		if _, err := in.Discard(int(size)); err != nil {
+			return err
+		}
+		n -= size
+	}
+	return nil
+}
+
+// names of the components by number as printed
+func componentNames(evdefs map[uint16]scvd.Event) [256][]string {
+	var names [256][]string
+	for no := range names {
+		names[no] = []string{fmt.Sprintf("0x%02X", no)} // events without definition
+	}
+	for id, def := range evdefs {
+		names[id>>8] = append(names[id>>8], def.Brief)
+	}
+	return names
+}
@@ -0,0 +1,185 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output
+
+import (
+	"eventlist/pkg/event"
+	"eventlist/pkg/index"
+	"eventlist/pkg/where"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// write a capture of several index blocks with a clock change in the
+// middle and component 0x0C in the last block only
+func writeIndexCapture(t *testing.T, name string) {
+	w, err := event.Create(name)
+	if err != nil {
+		t.Fatalf("event.Create() error = %v", err)
+	}
+	err = w.Clock(0, 1000000)
+	for i := 1; i < 4*index.DefaultBlockSize+100 && err == nil; i++ {
+		switch {
+		case i == 2*index.DefaultBlockSize+10:
+			err = w.EventRecord2(uint64(10*i), 0xFF03, 2000000, 0)
+		case i > 4*index.DefaultBlockSize:
+			err = w.EventRecord2(uint64(10*i), 0x0C01, int32(i), 0)
+		default:
+			err = w.EventRecord2(uint64(10*i), 0x0A01+uint16(i%2)<<8, int32(i), 0)
+		}
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		t.Fatalf("Writer.EventRecord2() error = %v", err)
+	}
+}
+
+func TestPrintIndex(t *testing.T) { //nolint:golint,paralleltest
+	dir := t.TempDir()
+	s := filepath.Join(dir, "index.binary")
+	writeIndexCapture(t, s)
+	formatType := "txt"
+	level := ""
+
+	print := func(useIndex bool, skip int, wheres []string) string {
+		Index = useIndex
+		Skip = skip
+		Where, _ = where.Parse(wheres)
+		defer func() {
+			Index, Skip, Where = false, 0, nil
+		}()
+		TimeFactor = nil
+		o := filepath.Join(dir, "index.out")
+		if err := Print(&o, &formatType, &level, &s, nil, nil, nil, false, false); err != nil {
+			t.Errorf("Print() error = %v", err)
+		}
+		data, err := os.ReadFile(o)
+		if err != nil {
+			t.Fatalf("os.ReadFile() error = %v", err)
+		}
+		return string(data)
+	}
+
+	tests := []struct {
+		name   string
+		skip   int
+		wheres []string
+	}{
+		{"all", 0, nil},
+		{"skip", 3000, nil},
+		{"skip after clock", 2*index.DefaultBlockSize + 20, nil},
+		{"where", 0, []string{"component=0x0C"}},
+		{"where id", 100, []string{"id=0x0B00-0x0BFF"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(index.Name(s))
+			want := print(false, tt.skip, tt.wheres)
+			if got := print(true, tt.skip, tt.wheres); got != want {
+				t.Errorf("Print() %s building the index = %v, want %v", tt.name, got, want)
+			}
+			if _, err := index.Load(s); err != nil {
+				t.Errorf("index.Load() %s error = %v", tt.name, err)
+			}
+			if got := print(true, tt.skip, tt.wheres); got != want {
+				t.Errorf("Print() %s with the index = %v, want %v", tt.name, got, want)
+			}
+		})
+	}
+}
+
+func TestOutput_newCursor(t *testing.T) { //nolint:golint,paralleltest
+	s := filepath.Join(t.TempDir(), "index.binary")
+	writeIndexCapture(t, s)
+	b := index.NewBuilder(index.DefaultBlockSize)
+	for i := 0; i < 4*index.DefaultBlockSize+100; i++ {
+		b.Add(int64(24*i), 0, 0x0A01, index.Clock{})
+	}
+	defer func() { Where = nil }()
+
+	tests := []struct {
+		name   string
+		first  int
+		wheres []string
+		want   int
+	}{
+		{"start", 0, nil, 0},
+		{"first", 3000, nil, 2 * index.DefaultBlockSize},
+		{"block start", 3 * index.DefaultBlockSize, nil, 3 * index.DefaultBlockSize},
+		{"where", 0, []string{"component=0x0C"}, 4*index.DefaultBlockSize + 100},
+		{"where match", 3000, []string{"component=0x0A"}, 2 * index.DefaultBlockSize},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Where, _ = where.Parse(tt.wheres)
+			o := &Output{first: tt.first, index: b.Index()}
+			var ib event.Binary
+			c := o.newCursor(ib.Open(&s), nil)
+			if c.no != tt.want {
+				t.Errorf("Output.newCursor() %s = %d, want %d", tt.name, c.no, tt.want)
+			}
+			ib.Close()
+		})
+	}
+}
+
+func TestOutput_newCursorSparse(t *testing.T) { //nolint:golint,paralleltest
+	if testing.Short() || runtime.GOOS == "windows" {
+		t.Skip("sparse capture of more than 4 GiB")
+	}
+	// a record at the start and two records behind a hole of 5 GiB
+	const offset = 5 << 30
+	s := filepath.Join(t.TempDir(), "sparse.binary")
+	file, err := os.Create(s)
+	if err != nil {
+		t.Fatalf("os.Create() error = %v", err)
+	}
+	first := event.Data{Typ: 2, Time: 1, Info: event.Info{ID: 0x0A01}}
+	var last []byte
+	for i := 2; i <= 3; i++ {
+		e := event.Data{Typ: 2, Time: uint64(i) << 32, Value1: int32(i), Info: event.Info{ID: 0x0A02}}
+		last = e.AppendRecord(last)
+	}
+	if _, err = file.Write(first.AppendRecord(nil)); err == nil {
+		_, err = file.WriteAt(last, offset)
+	}
+	file.Close()
+	if err != nil {
+		t.Fatalf("File.WriteAt() error = %v", err)
+	}
+	b := index.NewBuilder(1)
+	b.Add(0, 1, 0x0A01, index.Clock{})
+	b.Add(offset, 2<<32, 0x0A02, index.Clock{})
+	b.Add(offset+24, 3<<32, 0x0A02, index.Clock{})
+
+	var ib event.Binary
+	defer ib.Close()
+	o := &Output{first: 1, index: b.Index(), file: &ib}
+	c := o.newCursor(ib.Open(&s), nil)
+	for i := 2; i <= 3; i++ {
+		var ev event.Data
+		if err := c.next(&ev, false); err != nil || ev.Time != uint64(i)<<32 || ev.Value1 != int32(i) {
+			t.Errorf("cursor.next() = %v, %v, want time %d", ev, err, uint64(i)<<32)
+		}
+	}
+}
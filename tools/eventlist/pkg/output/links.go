@@ -0,0 +1,114 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output
+
+import (
+	"errors"
+	"eventlist/pkg/elf"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var errHyperlinks = errors.New("unknown hyperlink mode")
+
+// Hyperlinks selects the OSC 8 hyperlinks of the source locations in the
+// event list: auto, always or never. auto links the locations if the event
+// list is written as text to a terminal.
+var Hyperlinks = "auto"
+
+// SetHyperlinks sets the hyperlink mode of the event list
+func SetHyperlinks(mode string) error {
+	switch mode {
+	case "auto", "always", "never":
+		Hyperlinks = mode
+		return nil
+	}
+	return fmt.Errorf("%w: %s", errHyperlinks, mode)
+}
+
+// check if the source locations of the event list written to file are links
+func useLinks(file *os.File) bool {
+	switch Hyperlinks {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	return FormatType == "txt" && isTerminal(file)
+}
+
+// find the first source location file:line of a file in the DWARF line
+// table, returns the start and the end of the location, -1 if there is none
+func nextLocation(text []byte) (int, int) {
+	for colon := 0; colon < len(text); colon++ {
+		if text[colon] != ':' {
+			continue
+		}
+		end := colon + 1
+		for end < len(text) && text[end] >= '0' && text[end] <= '9' {
+			end++
+		}
+		if end == colon+1 {
+			continue
+		}
+		for start := 0; start < colon; start++ { // the longest file name
+			if elf.Lines.IsFile(string(text[start:colon])) {
+				return start, end
+			}
+		}
+	}
+	return -1, -1
+}
+
+// URL of a source file, the Windows paths of the line table become
+// file:///C:/dir/file.c
+func fileURL(name string) string {
+	name = strings.ReplaceAll(name, `\`, "/")
+	if len(name) >= 2 && name[1] == ':' {
+		name = "/" + name
+	} else if !strings.HasPrefix(name, "/") {
+		if abs, err := filepath.Abs(name); err == nil {
+			name = filepath.ToSlash(abs)
+		}
+	}
+	u := url.URL{Scheme: "file", Path: name}
+	return u.String()
+}
+
+// append a text with its source locations as OSC 8 hyperlinks to the files,
+// the visible text is not changed
+func appendLinks(b []byte, text []byte) []byte {
+	for {
+		start, end := nextLocation(text)
+		if start < 0 {
+			return append(b, text...)
+		}
+		colon := start + strings.LastIndexByte(string(text[start:end]), ':')
+		b = append(b, text[:start]...)
+		b = append(b, "\x1b]8;;"...)
+		b = append(b, fileURL(string(text[start:colon]))...)
+		b = append(b, "\x1b\\"...)
+		b = append(b, text[start:end]...)
+		b = append(b, "\x1b]8;;\x1b\\"...)
+		text = text[end:]
+	}
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output
+
+import (
+	"eventlist/pkg/elf"
+	"os"
+	"testing"
+)
+
+func TestSetHyperlinks(t *testing.T) { //nolint:golint,paralleltest
+	defer func() { Hyperlinks = "auto" }()
+
+	tests := []struct {
+		mode    string
+		wantErr bool
+	}{
+		{"always", false},
+		{"never", false},
+		{"auto", false},
+		{"sometimes", true},
+	}
+	for _, tt := range tests {
+		if err := SetHyperlinks(tt.mode); (err != nil) != tt.wantErr {
+			t.Errorf("SetHyperlinks() %s error = %v, wantErr %v", tt.mode, err, tt.wantErr)
+		}
+	}
+	if Hyperlinks != "auto" {
+		t.Errorf("SetHyperlinks() Hyperlinks = %s, want auto", Hyperlinks)
+	}
+	Hyperlinks = "always"
+	if !useLinks(os.Stdout) {
+		t.Errorf("useLinks() always = false, want true")
+	}
+	Hyperlinks = "never"
+	if useLinks(os.Stdout) {
+		t.Errorf("useLinks() never = true, want false")
+	}
+}
+
+func Test_fileURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		file string
+		want string
+	}{
+		{"windows", `C:\Keil\Blinky\main.c`, "file:///C:/Keil/Blinky/main.c"},
+		{"posix", "/src/app main.c", "file:///src/app%20main.c"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := fileURL(tt.file); got != tt.want {
+				t.Errorf("fileURL() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_appendLinks(t *testing.T) { //nolint:golint,paralleltest
+	state := elf.Current()
+	defer elf.Use(state)
+
+	elfFile := "../../testdata/elfsym.elf"
+	if err := elf.Sections.Readelf(&elfFile); err != nil {
+		t.Fatalf("elf.Sections.Readelf() error = %v", err)
+	}
+	file, line, found := elf.Lines.Find(0x10001596)
+	if !found || line != 67 {
+		t.Fatalf("elf.Lines.Find() = %s, %d, %v", file, line, found)
+	}
+
+	link := "\x1b]8;;file:///C:/Keil/ARM-Tests/Blinky/RTE/Device/IOTKit_CM33/system_IOTKit_CM33.c\x1b\\" +
+		file + ":67\x1b]8;;\x1b\\"
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"none", "pc=0x10001596 main.c:12", "pc=0x10001596 main.c:12"},
+		{"location", "pc=" + file + ":67 ", "pc=" + link + " "},
+		{"two", file + ":67," + file + ":67", link + "," + link},
+		{"no line", "at " + file + ":x", "at " + file + ":x"},
+	}
+	for _, tt := range tests {
+		if got := string(appendLinks(nil, []byte(tt.text))); got != tt.want {
+			t.Errorf("appendLinks() %s = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
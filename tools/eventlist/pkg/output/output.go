@@ -24,20 +24,189 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"errors"
+	"eventlist/pkg/annotate"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/dashboard"
 	"eventlist/pkg/eval"
 	"eventlist/pkg/event"
+	"eventlist/pkg/index"
+	"eventlist/pkg/pager"
+	"eventlist/pkg/plugin"
+	"eventlist/pkg/progress"
+	"eventlist/pkg/rotate"
+	"eventlist/pkg/rtos"
+	"eventlist/pkg/selftrace"
+	"eventlist/pkg/stats"
+	"eventlist/pkg/where"
 	"eventlist/pkg/xml/scvd"
 	"fmt"
+	"hash/fnv"
+	"io"
 	"math"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 )
 
-var errNoEvents = errors.New("cannot open event file")
+// ErrNoEvents is returned if the event file cannot be opened
+var ErrNoEvents = errors.New("cannot open event file")
+
+var errColumn = errors.New("unknown column")
+
+var errSort = errors.New("unknown sort key")
+
+var errColor = errors.New("unknown color mode")
 
 var TimeFactor *float64
 var FormatType = "txt"
 var Level = ""
 
+// Rotate rotates the output file of the live mode
+var Rotate rotate.Options
+
+// Decoders format the events of ID ranges not described by SCVD, nil without
+var Decoders *plugin.Set
+
+// Ignore are the IDs of the events that are neither decoded nor shown
+var Ignore map[uint16]bool
+
+// Tree indents the events between matching start and stop events
+var Tree bool
+
+// Marker reports if an event is a marker, nil without markers
+var Marker func(id uint16, def *scvd.Event) bool
+
+// Annotations are the notes added to the events, nil without
+var Annotations *annotate.List
+
+// ObjectNames replaces the IDs of the kernel objects in the values by the
+// names of their creation events
+var ObjectNames bool
+
+// Squash collapses runs of identical consecutive events into one line
+var Squash bool
+
+// StatsOnly prints the statistic only, without analyzers the statistic pass
+// reads the start/stop events only and formats the printed texts only
+var StatsOnly bool
+
+// Sort orders the printed event list by time, index, component or duration,
+// empty keeps the order of the event file
+var Sort = ""
+
+// Reverse prints the event list in reverse order
+var Reverse bool
+
+// SetSort sets the sort key of the printed event list
+func SetSort(key string) error {
+	switch key {
+	case "time", "index", "component", "duration":
+		Sort = key
+		return nil
+	}
+	return fmt.Errorf("%w: %s", errSort, key)
+}
+
+// Skip is the number of events not printed at the beginning
+var Skip int
+
+// Limit is the maximum number of printed events, 0 prints all
+var Limit int
+
+// Tail prints the last events only, 0 prints all
+var Tail int
+
+// ColorMode selects the colors of the event list: auto, always or never.
+// With auto the event list is colored if it is printed as text to a
+// terminal and the environment variable NO_COLOR is not set.
+var ColorMode = "auto"
+
+// SetColorMode sets the color mode of the event list
+func SetColorMode(mode string) error {
+	switch mode {
+	case "auto", "always", "never":
+		ColorMode = mode
+		return nil
+	}
+	return fmt.Errorf("%w: %s", errColor, mode)
+}
+
+// check if the event list written to file is colored
+func useColor(file *os.File) bool {
+	switch ColorMode {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	if FormatType != "txt" || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return isTerminal(file)
+}
+
+// isTerminal returns true if file is a terminal
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Trace records the stages of the decoder, nil disables the self trace
+var Trace *selftrace.Tracer
+
+// Where selects the printed events, nil prints all events
+var Where *where.Filter
+
+// Progress shows a progress bar on stderr while the event file is decoded
+var Progress bool
+
+// Pager is the command paging the output to a terminal, empty disables paging
+var Pager = ""
+
+// ANSI colors of the levels and the components
+var levelColors = map[string]string{
+	"Error":  "1;31",
+	"API":    "34",
+	"Detail": "2",
+}
+var componentColors = []string{"36", "35", "33", "32", "96", "95"}
+
+// Columns selects the columns of the text event list and their order
+var Columns = []string{"index", "time", "component", "event", "message"}
+
+// SetColumns sets the columns from a comma separated list
+func SetColumns(list string) error {
+	var columns []string
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		switch name {
+		case "index", "time", "component", "event", "message", "level", "thread", "cumulative", "raw":
+			columns = append(columns, name)
+		case "core": // the header of the records has no core ID, a capture is of one core
+			return fmt.Errorf("%w: %s, the records have no core ID", errColumn, name)
+		default:
+			return fmt.Errorf("%w: %s", errColumn, name)
+		}
+	}
+	Columns = columns
+	return nil
+}
+
+// Dashboard defines the layout of the html report, nil uses the default dashboard
+var Dashboard *dashboard.Spec
+
+// Analyzers are subscribed to the decoded event stream in addition to the statistic
+var Analyzers []bus.Analyzer
+
+// Shown is the number of events passing the filters of the last Print or Live
+var Shown int
+
+// Summary is printed at the top of the text output after the statistic pass,
+// it must be one of the Analyzers
+var Summary bus.Reporter
+
 func TimeInSecs(time uint64) float64 {
 	if TimeFactor == nil {
 		return 4e-8 * float64(time) // default
@@ -65,6 +234,12 @@ type eventStatistic struct {
 	textMinE  string
 	textMaxB  string
 	textMaxE  string
+	// events of the texts with StatsOnly, formatted when printed
+	evB    *bus.Event
+	evMinB *bus.Event
+	evMinE *bus.Event
+	evMaxB *bus.Event
+	evMaxE *bus.Event
 }
 
 type EventRecord struct {
@@ -73,6 +248,101 @@ type EventRecord struct {
 	Component     string  `json:"component" xml:"component"`
 	EventProperty string  `json:"eventProperty" xml:"eventProperty"`
 	Value         string  `json:"value" xml:"value"`
+	Thread        string  `json:"thread,omitempty" xml:"thread,omitempty"`
+	Repeat        int     `json:"repeat,omitempty" xml:"repeat,omitempty"`
+	LastTime      float64 `json:"lastTime,omitempty" xml:"lastTime,omitempty"`
+	Marker        bool    `json:"marker,omitempty" xml:"marker,omitempty"`
+	Annotation    string  `json:"annotation,omitempty" xml:"annotation,omitempty"`
+	id            uint16
+	duration      float64 // time between the start and stop event of a pair
+	cumulative    float64 // time in the pairs of the group of a start/stop event up to the event
+	level         string
+	raw           string
+	quoted        bool
+	depth         int
+	def           *scvd.Event  // definition of the event, nil if unknown
+	objects       []objectName // named objects of the values
+}
+
+// objectName is the name of an object ID in the values of a record
+type objectName struct {
+	id   uint32
+	name string
+}
+
+// append the text of a column of the event record
+func (rec *EventRecord) appendColumn(b []byte, name string) []byte {
+	switch name {
+	case "index":
+		return strconv.AppendInt(b, int64(rec.Index), 10)
+	case "time":
+		return strconv.AppendFloat(b, rec.Time, 'f', 8, 64)
+	case "component":
+		for i := 0; i < rec.depth; i++ {
+			b = append(b, "  "...)
+		}
+		return append(b, rec.Component...)
+	case "event":
+		return append(b, rec.EventProperty...)
+	case "level":
+		return append(b, rec.level...)
+	case "thread":
+		return append(b, rec.Thread...)
+	case "cumulative":
+		if rec.id>>8 != 0xEF {
+			return b
+		}
+		return strconv.AppendFloat(b, rec.cumulative, 'f', 8, 64)
+	case "raw":
+		return append(b, rec.raw...)
+	}
+	if rec.quoted {
+		b = append(b, '"')
+		b = append(b, rec.Value...)
+		return append(b, '"')
+	}
+	return append(b, rec.Value...)
+}
+
+// append a text padded with blanks to width runes, negative widths are left aligned
+func appendPadded(b []byte, text []byte, width int) []byte {
+	left := width < 0
+	if left {
+		width = -width
+	}
+	pad := width - utf8.RuneCount(text)
+	if !left {
+		for ; pad > 0; pad-- {
+			b = append(b, ' ')
+		}
+	}
+	b = append(b, text...)
+	for ; pad > 0; pad-- {
+		b = append(b, ' ')
+	}
+	return b
+}
+
+// check if two event records are repetitions of the same event
+func (rec *EventRecord) same(other *EventRecord) bool {
+	return rec.Component == other.Component && rec.EventProperty == other.EventProperty &&
+		rec.Value == other.Value && rec.raw == other.raw && rec.Thread == other.Thread &&
+		rec.level == other.level && rec.depth == other.depth && rec.Annotation == other.Annotation
+}
+
+// Level returns the level of the event, empty if unknown
+func (rec *EventRecord) Level() string {
+	return rec.level
+}
+
+// ID returns the event ID
+func (rec *EventRecord) ID() uint16 {
+	return rec.id
+}
+
+// Raw returns the values of the event as hex string
+func (rec *EventRecord) Raw() string {
+	return rec.raw
 }
 
 type EventRecordStatistic struct {
@@ -86,6 +356,9 @@ type EventRecordStatistic struct {
 	Min         string  `json:"min" xml:"min"`
 	Max         string  `json:"max" xml:"max"`
 	First       string  `json:"first" xml:"first"`
+	P50         string  `json:"p50" xml:"p50"`
+	P90         string  `json:"p90" xml:"p90"`
+	P99         string  `json:"p99" xml:"p99"`
 	Last        string  `json:"last" xml:"last"`
 	Avg         string  `json:"avg" xml:"avg"`
 	MinTime     float64 `json:"minTime" xml:"minTime"`
@@ -119,14 +392,16 @@ func (es *eventStatistic) init() {
 	es.lastTime = 0
 }
 
-func (es *eventStatistic) add(time float64, start bool, text string) {
+// add a start or stop event, the text is the value of the event
+// or empty with the event formatted when printed
+func (es *eventStatistic) add(time float64, start bool, text string, ev *bus.Event) {
 	if start {
 		if es.evStart {
 			return // ignore start event, was not stopped yet
 		}
 		es.evStart = true
 		es.start = time
-		es.textB = text
+		es.textB, es.evB = text, ev
 	} else {
 		if !es.evStart {
 			return // ignore already stopped events
@@ -136,14 +411,14 @@ func (es *eventStatistic) add(time float64, start bool, text string) {
 		if diff < es.min {
 			es.min = diff
 			es.minTime = es.start
-			es.textMinB = es.textB
-			es.textMinE = text
+			es.textMinB, es.evMinB = es.textB, es.evB
+			es.textMinE, es.evMinE = text, ev
 		}
 		if diff > es.max {
 			es.max = diff
 			es.maxTime = es.start
-			es.textMaxB = es.textB
-			es.textMaxE = text
+			es.textMaxB, es.evMaxB = es.textB, es.evB
+			es.textMaxE, es.evMaxE = text, ev
 		}
 		if !es.evFirst {
 			es.first = diff
@@ -159,22 +434,32 @@ func (es *eventStatistic) add(time float64, start bool, text string) {
 }
 
 type eventProperty struct {
-	values [16]eventStatistic
+	values  [16]eventStatistic
+	digests [16]stats.Digest // durations of the pairs for the percentiles
 }
 
 func (ep *eventProperty) init() {
 	for i := uint16(0); i < uint16(len(ep.values)); i++ {
 		ep.values[i].init()
+		ep.digests[i] = stats.Digest{}
 	}
 }
 
-func (ep *eventProperty) add(time float64, idx uint16, start bool, text string) {
+func (ep *eventProperty) add(time float64, idx uint16, start bool, text string, ev *bus.Event) {
 	if idx == 15 && !start { // stop 15 means stop all
 		for i := uint16(0); i < uint16(len(ep.values)); i++ {
-			ep.values[i].add(time, start, text)
+			ep.addValue(i, time, start, text, ev)
 		}
 	} else {
-		ep.values[idx].add(time, start, text)
+		ep.addValue(idx, time, start, text, ev)
+	}
+}
+
+func (ep *eventProperty) addValue(idx uint16, time float64, start bool, text string, ev *bus.Event) {
+	count := ep.values[idx].count
+	ep.values[idx].add(time, start, text, ev)
+	if ep.values[idx].count != count {
+		ep.digests[idx].Add(ep.values[idx].last)
 	}
 }
 
@@ -245,83 +530,291 @@ func (ep *eventProperty) getLast(idx uint16) string {
 	return convertUnit(ep.values[idx].last, "s")
 }
 
+// get the duration below which q of the durations are
+func (ep *eventProperty) getPercentile(idx uint16, q float64) string {
+	return convertUnit(ep.digests[idx].Quantile(q), "s")
+}
+
+// nesting tracks the open start/stop event pairs
+type nesting struct {
+	open []uint16 // start events without stop event, innermost last
+	max  int
+}
+
+// get the nesting depth of the event
+func (n *nesting) depth(ev *event.Data) int {
+	class, group, idx, start := ev.Info.SplitID()
+	if class != 0xEF {
+		return len(n.open)
+	}
+	key := group<<4 | idx
+	d := len(n.open)
+	if start {
+		n.open = append(n.open, key)
+		if len(n.open) > n.max {
+			n.max = len(n.open)
+		}
+		return d
+	}
+	for i := len(n.open) - 1; i >= 0; i-- {
+		if n.open[i] == key { // close the pair and the pairs started within
+			n.open = n.open[:i]
+			return i
+		}
+	}
+	return d
+}
+
+// pairTime sums per group A to D the time in which a start/stop pair of the
+// group is open, nested and overlapping pairs of a group are counted once
+type pairTime struct {
+	open  [4]uint16  // open slots of the groups
+	since [4]float64 // time the first slot of a group was opened
+	total [4]float64 // time of the closed intervals of a group
+}
+
+// add an event, returns the time in the pairs of its group up to the event,
+// 0 if it is no start/stop event
+func (p *pairTime) add(ev *event.Data, time float64) float64 {
+	class, group, idx, start := ev.Info.SplitID()
+	if class != 0xEF {
+		return 0
+	}
+	slot := uint16(1) << idx
+	switch {
+	case start:
+		if p.open[group] == 0 {
+			p.since[group] = time
+		}
+		p.open[group] |= slot
+	case p.open[group]&slot != 0:
+		p.open[group] &^= slot
+		if p.open[group] == 0 {
+			p.total[group] += time - p.since[group]
+		}
+	}
+	if p.open[group] != 0 {
+		return p.total[group] + time - p.since[group]
+	}
+	return p.total[group]
+}
+
+// timer converts the event timestamps to seconds using the recorder clock events
+type timer struct {
+	beforeClockEvent float64
+	lastClockEvent   uint64
+	clocked          bool // a clock event set TimeFactor
+}
+
+// get the time of the event in seconds
+func (tm *timer) time(ev *event.Data) float64 {
+	switch ev.Info.ID {
+	case 0xFF00: // EventRecorderInitialize
+		if ev.Value2 != 0 {
+			tm.beforeClockEvent = TimeInSecs(ev.Time)
+			tm.lastClockEvent = ev.Time
+			if TimeFactor == nil {
+				TimeFactor = new(float64)
+			}
+			*TimeFactor = 1.0 / float64(ev.Value2)
+			tm.clocked = true
+		}
+	case 0xFF03: // EventRecorderClock
+		if ev.Value1 != 0 {
+			tm.beforeClockEvent = TimeInSecs(ev.Time - tm.lastClockEvent)
+			tm.lastClockEvent = ev.Time
+			if TimeFactor == nil {
+				TimeFactor = new(float64)
+			}
+			*TimeFactor = 1.0 / float64(ev.Value1)
+			tm.clocked = true
+		}
+	}
+	return tm.beforeClockEvent + TimeInSecs(ev.Time-tm.lastClockEvent)
+}
+
 type Output struct {
 	evProps       [4]eventProperty
 	columns       []string
 	componentSize int
 	propertySize  int
+	threadSize    int
+	names         *rtos.Tracker          // threads seen while building the statistic
+	threads       *rtos.Tracker          // running thread of the printed events
+	nestSize      nesting                // nesting seen while building the statistic
+	nest          nesting                // nesting of the printed events
+	pairs         pairTime               // time in the start/stop pairs of the printed events
+	notes         *annotate.Cursor       // annotations of the printed events
+	note          string                 // annotation of the last followed event
+	pending       EventRecord            // squashed record not yet printed
+	pendingShow   bool                   // pending record passed the level filter
+	pendingOK     bool                   // pending record is valid
+	sorted        []EventRecord          // printed records held back for sorting
+	color         bool                   // color the event list
+	links         bool                   // source locations of the event list are hyperlinks
+	threadNames   bool                   // fill the running thread of the records
+	first         int                    // index of the first printed event
+	end           int                    // index after the last printed event, 0 prints all
+	stop          int                    // index the statistic pass stops at, 0 reads all events
+	index         *index.Index           // index of the log file, nil without
+	indexer       *indexer               // builds the index in the statistic pass
+	file          *event.Binary          // log file of the event pass
+	defs          map[uint16]*scvd.Event // definitions used, kept by pointer
+	line          []byte                 // scratch buffer of the printed line
+	cell          []byte                 // scratch buffer of the printed cell
+	shutdown      *shutdown              // stops reading the events, nil without
+	exporter      Exporter               // writes the event list, the text writer if nil
+}
+
+// definitions of the events written by eventlist, an SCVD file may
+// replace them
+var builtins = map[uint16]scvd.Event{
+	event.IDGap:   {Brief: "EventRecorder", Property: "Gap", Level: "Error", Value: "%d[val1] records lost, %d[val2] bytes skipped"},
+	event.IDReset: {Brief: "EventRecorder", Property: "TargetReset", Level: "Op", Value: "reset by the probe"},
+}
+
+// get the definition of an event, nil if unknown, the definitions
+// are kept by pointer to avoid a copy for every event, unknown
+// events are kept as nil
+func (o *Output) definition(evdefs map[uint16]scvd.Event, id uint16) *scvd.Event {
+	def, ok := o.defs[id]
+	if !ok {
+		if evdef, found := evdefs[id]; found {
+			def = new(scvd.Event)
+			*def = evdef
+		} else if evdef, found := builtins[id]; found {
+			def = &evdef
+		}
+		if o.defs == nil {
+			o.defs = make(map[uint16]*scvd.Event)
+		}
+		o.defs[id] = def
+	}
+	return def
+}
+
+// format the value of an event record
+func formatValue(ev *event.Data, evdef *scvd.Event, typedefs map[string]map[string]map[int16]string,
+	memberTypes map[string]map[string]string) (string, error) {
+	if d := Decoders.Find(ev.Info.ID); d != nil {
+		res, err := d.Decode(ev)
+		return res.Value, err
+	}
+	if ev.Info.ID == 0xFE00 && ev.Data != nil { // special case stdout
+		return escapeGen(string(*ev.Data)), nil
+	}
+	if evdef != nil {
+		return ev.EvalLine(*evdef, typedefs, memberTypes)
+	}
+	return ev.GetValuesAsString(), nil
+}
+
+// create the event of a record, the value is formatted on the first request
+func newEvent(no int, time float64, ev *event.Data, def *scvd.Event,
+	typedefs map[string]map[string]map[int16]string, memberTypes map[string]map[string]string) *bus.Event {
+	return bus.NewEvent(no, time, ev, def, func() (string, error) { return formatValue(ev, def, typedefs, memberTypes) })
+}
+
+// Event collects the column sizes and the start/stop event statistic
+func (o *Output) Event(ev *bus.Event) error {
+	if o.names != nil {
+		_ = o.names.Event(ev)
+	}
+	if Tree {
+		o.nestSize.depth(ev.Data)
+	}
+	if ev.Def != nil {
+		if len(ev.Def.Brief) > o.componentSize {
+			o.componentSize = len(ev.Def.Brief)
+		}
+		if len(ev.Def.Property) > o.propertySize {
+			o.propertySize = len(ev.Def.Property)
+		}
+	}
+	class, group, idx, start := ev.Data.Info.SplitID()
+	if class == 0xEF {
+		rep, _ := ev.Value()
+		o.evProps[group].add(ev.Time, idx, start, rep, nil)
+	}
+	return nil
+}
+
+func (o *Output) End() error {
+	return nil
 }
 
 func (o *Output) buildStatistic(in *bufio.Reader, evdefs map[uint16]scvd.Event,
-	typedefs map[string]map[string]map[int16]string) int {
+	typedefs map[string]map[string]map[int16]string, memberTypes map[string]map[string]string) int {
 	o.componentSize = len(o.columns[2]) // use minimum width of header
 	o.propertySize = len(o.columns[3])
+	o.names = rtos.NewTracker()
 	for i := uint16(0); i < uint16(len(o.evProps)); i++ {
 		o.evProps[i].init()
 	}
-	var beforeClockEvent float64
-	var lastClockEvent uint64
+	var b bus.Bus
+	b.Subscribe(o)
+	for _, a := range Analyzers {
+		b.Subscribe(a)
+	}
+	var tm timer
 	var eventCount int
-	for {
+	if o.indexer != nil {
+		o.indexer.in = in
+	}
+	readStage := Trace.Stage("statistic read")
+	publishStage := Trace.Stage("statistic analyze")
+	statsOnly := StatsOnly && len(Analyzers) == 0
+	for !o.shutdown.interrupted() && (o.stop <= 0 || eventCount < o.stop) {
 		var ev event.Data
-		if err := ev.Read(in); err != nil {
+		var offset int64
+		if o.indexer != nil {
+			offset = o.indexer.offset()
+		}
+		start := readStage.Start()
+		var err error
+		if statsOnly {
+			err = ev.ReadIf(in, func(id uint16) bool { return id>>8 == 0xEF })
+		} else {
+			err = ev.Read(in)
+		}
+		if err != nil {
 			if errors.Is(err, eval.ErrEof) {
 				break
 			}
 			fmt.Println(err)
 			return 0
 		}
+		readStage.Stop(start)
 		eventCount++
-		var evdef scvd.Event
-		var ok bool
-		var rep string
-		if evdef, ok = evdefs[ev.Info.ID]; ok {
-			if len(evdef.Brief) > o.componentSize {
-				o.componentSize = len(evdef.Brief)
-			}
-			if len(evdef.Property) > o.propertySize {
-				o.propertySize = len(evdef.Property)
-			}
-			class, _, _, _ := ev.Info.SplitID()
-			switch class {
-			case 0xEF:
-				rep, _ = ev.EvalLine(evdef, typedefs)
-			}
+		if o.indexer != nil {
+			o.indexer.add(offset, &ev, &tm)
 		}
-		class, group, idx, start := ev.Info.SplitID()
-		switch class {
-		case 0xEF:
-			if !ok { // rep not yet built up because of wrong or missing SCVD files
-				rep = ev.GetValuesAsString()
-			}
-			o.evProps[group].add(beforeClockEvent+TimeInSecs(ev.Time-lastClockEvent), idx, start, rep)
-		case 0xFF:
-			switch ev.Info.ID {
-			case 0xFF00: // EventRecorderInitialize
-				if ev.Value2 != 0 {
-					beforeClockEvent = TimeInSecs(ev.Time)
-					lastClockEvent = ev.Time
-					if TimeFactor == nil {
-						TimeFactor = new(float64)
-					}
-					*TimeFactor = 1.0 / float64(ev.Value2)
-				}
-			case 0xFF03: // EventRecorderClock
-				if ev.Value1 != 0 {
-					beforeClockEvent = TimeInSecs(ev.Time - lastClockEvent)
-					lastClockEvent = ev.Time
-					if TimeFactor == nil {
-						TimeFactor = new(float64)
-					}
-					*TimeFactor = 1.0 / float64(ev.Value1)
-				}
+		time := tm.time(&ev)
+		def := o.definition(evdefs, ev.Info.ID)
+		if statsOnly {
+			// the values are formatted for the printed texts only
+			if class, group, idx, start := ev.Info.SplitID(); class == 0xEF {
+				o.evProps[group].add(time, idx, start, "", newEvent(eventCount-1, time, &ev, def, typedefs, memberTypes))
 			}
+			continue
+		}
+		be := newEvent(eventCount-1, time, &ev, def, typedefs, memberTypes)
+		start = publishStage.Start()
+		if err := b.Publish(be); err != nil {
+			fmt.Println(err)
+			return 0
 		}
+		publishStage.Stop(start)
 	}
+	if err := b.End(); err != nil {
+		fmt.Println(err)
+	}
+	o.threadSize = o.names.NameSize()
+	o.componentSize += 2 * o.nestSize.max
 	return eventCount
 }
 
-func conditionalWrite(out *bufio.Writer, format string, a ...any) (err error) {
+func conditionalWrite(out io.Writer, format string, a ...any) (err error) {
 	if FormatType == "txt" {
 		_, err = fmt.Fprintf(out, format, a...)
 		return err
@@ -329,85 +822,58 @@ func conditionalWrite(out *bufio.Writer, format string, a ...any) (err error) {
 	return nil
 }
 
-func (o *Output) printStatistic(out *bufio.Writer, eventCount int, eventTable *EventsTable) error {
-	var err error
+// text of a start or stop event of the statistic, formatted from the event if empty
+func eventText(text string, ev *bus.Event) string {
+	if text == "" && ev != nil {
+		text, _ = ev.Value()
+	}
+	return text
+}
 
-	if out != nil && eventCount > 0 {
-		if err = conditionalWrite(out, "   Start/Stop event statistic\n"); err != nil {
-			return err
-		}
-		if err = conditionalWrite(out, "   --------------------------\n\n"); err != nil {
-			return err
-		}
-		if err = conditionalWrite(out, "Event count      total       min         max         average     first       last\n"); err != nil {
-			return err
-		}
-		if err = conditionalWrite(out, "----- -----      -----       ---         ---         -------     -----       ----\n"); err != nil {
-			return err
-		}
-		for i := uint16(0); i < uint16(len(o.evProps)); i++ {
-			for j := uint16(0); j < uint16(len(o.evProps[i].values)); j++ {
-				if o.evProps[i].values[j].evFirst {
-					eventStat := EventRecordStatistic{
-						Event:       fmt.Sprintf("%c(%d)", byte(i+'A'), j),
-						AddCount:    o.evProps[i].getAddCount(j),
-						Count:       o.evProps[i].getCount(j),
-						Total:       o.evProps[i].getTot(j),
-						Min:         o.evProps[i].getMin(j),
-						Max:         o.evProps[i].getMax(j),
-						Avg:         o.evProps[i].getAvg(j),
-						First:       o.evProps[i].getFirst(j),
-						Last:        o.evProps[i].getLast(j),
-						MinTime:     o.evProps[i].values[j].minTime,
-						TextMinB:    o.evProps[i].values[j].textMinB,
-						TextMinE:    o.evProps[i].values[j].textMinE,
-						MinStopTime: o.evProps[i].values[j].minTime + o.evProps[i].values[j].min,
-						MaxStopTime: o.evProps[i].values[j].maxTime + o.evProps[i].values[j].max,
-						MaxTime:     o.evProps[i].values[j].maxTime,
-						TextMaxB:    o.evProps[i].values[j].textMaxB,
-						TextMaxE:    o.evProps[i].values[j].textMaxE,
-					}
-					err = conditionalWrite(out, eventStat.Event)
-					if err == nil && j < 10 {
-						err = conditionalWrite(out, " ")
-					}
-					if err != nil {
-						return err
-					}
-					err = conditionalWrite(out, " %5d%s %s %s %s %s %s %s\n",
-						eventStat.Count,
-						eventStat.AddCount,
-						eventStat.Total,
-						eventStat.Min,
-						eventStat.Max,
-						eventStat.Avg,
-						eventStat.First,
-						eventStat.Last)
-					if err != nil {
-						return err
-					}
-					err = conditionalWrite(out, "      Min: Start: %.8f %s Stop: %.8f %s\n",
-						eventStat.MinTime,
-						eventStat.TextMinB,
-						eventStat.MinStopTime,
-						eventStat.TextMinE)
-					if err != nil {
-						return err
-					}
-					err = conditionalWrite(out, "      Max: Start: %.8f %s Stop: %.8f %s\n\n",
-						eventStat.MaxTime,
-						eventStat.TextMaxB,
-						eventStat.MaxStopTime,
-						eventStat.TextMaxE)
-					if err != nil {
-						return err
-					}
-					eventTable.Statistics = append(eventTable.Statistics, eventStat)
-				}
+// statistic of every start/stop event
+func (o *Output) statistics() []EventRecordStatistic {
+	var stats []EventRecordStatistic
+	for i := uint16(0); i < uint16(len(o.evProps)); i++ {
+		for j := uint16(0); j < uint16(len(o.evProps[i].values)); j++ {
+			if o.evProps[i].values[j].evFirst {
+				stats = append(stats, EventRecordStatistic{
+					Event:       fmt.Sprintf("%c(%d)", byte(i+'A'), j),
+					AddCount:    o.evProps[i].getAddCount(j),
+					Count:       o.evProps[i].getCount(j),
+					Total:       o.evProps[i].getTot(j),
+					Min:         o.evProps[i].getMin(j),
+					Max:         o.evProps[i].getMax(j),
+					Avg:         o.evProps[i].getAvg(j),
+					First:       o.evProps[i].getFirst(j),
+					Last:        o.evProps[i].getLast(j),
+					P50:         o.evProps[i].getPercentile(j, 0.5),
+					P90:         o.evProps[i].getPercentile(j, 0.9),
+					P99:         o.evProps[i].getPercentile(j, 0.99),
+					MinTime:     o.evProps[i].values[j].minTime,
+					TextMinB:    eventText(o.evProps[i].values[j].textMinB, o.evProps[i].values[j].evMinB),
+					TextMinE:    eventText(o.evProps[i].values[j].textMinE, o.evProps[i].values[j].evMinE),
+					MinStopTime: o.evProps[i].values[j].minTime + o.evProps[i].values[j].min,
+					MaxStopTime: o.evProps[i].values[j].maxTime + o.evProps[i].values[j].max,
+					MaxTime:     o.evProps[i].values[j].maxTime,
+					TextMaxB:    eventText(o.evProps[i].values[j].textMaxB, o.evProps[i].values[j].evMaxB),
+					TextMaxE:    eventText(o.evProps[i].values[j].textMaxE, o.evProps[i].values[j].evMaxE),
+				})
 			}
 		}
 	}
-	return err
+	return stats
+}
+
+func (o *Output) printStatistic(out *bufio.Writer, eventCount int, eventTable *EventsTable) error {
+	if out == nil || eventCount <= 0 {
+		return nil
+	}
+	stats := o.statistics()
+	if err := o.export().Stats(out, stats); err != nil {
+		return err
+	}
+	eventTable.Statistics = append(eventTable.Statistics, stats...)
+	return nil
 }
 
 func escapeGen(s string) string {
@@ -445,18 +911,382 @@ func escapeGen(s string) string {
 	return t
 }
 
+// build the record of an event, show is false if it is filtered by level
+func (o *Output) buildRecord(no int, time float64, ev *event.Data, evdefs map[uint16]scvd.Event,
+	typedefs map[string]map[string]map[int16]string, memberTypes map[string]map[string]string) (eventRecord EventRecord, show bool, err error) {
+	eventRecord = o.prepareRecord(no, time, ev, evdefs)
+	show, err = formatRecord(&eventRecord, ev, typedefs, memberTypes)
+	if show {
+		show = match(&eventRecord, ev)
+	}
+	return eventRecord, show, err
+}
+
+// prepare the record of an event with the state of the events before,
+// the events must be prepared in order
+func (o *Output) prepareRecord(no int, time float64, ev *event.Data, evdefs map[uint16]scvd.Event) EventRecord {
+	eventRecord := EventRecord{
+		id:    ev.Info.ID,
+		Index: no,
+		Time:  time,
+		def:   o.definition(evdefs, ev.Info.ID),
+	}
+	if ev.Info.ID == 0xFE00 && ev.Data != nil { // special case stdout
+		eventRecord.quoted = true
+	}
+	if ObjectNames {
+		eventRecord.objects = o.objectNames(ev)
+	}
+	if Marker != nil {
+		eventRecord.Marker = Marker(ev.Info.ID, eventRecord.def)
+	}
+	eventRecord.depth, eventRecord.cumulative = o.follow(no, time, ev, eventRecord.def)
+	eventRecord.Annotation = o.note
+	if id, ok := o.threads.Current(); ok && o.threadNames {
+		eventRecord.Thread = o.threads.Name(id)
+	}
+	return eventRecord
+}
+
+// format the values of a prepared record, returns whether it is shown,
+// records are formatted independent of each other
+func formatRecord(eventRecord *EventRecord, ev *event.Data,
+	typedefs map[string]map[string]map[int16]string, memberTypes map[string]map[string]string) (show bool, err error) {
+	if Ignore[ev.Info.ID] {
+		return false, nil
+	}
+	eventRecord.raw = ev.GetValuesAsString()
+	if d := Decoders.Find(ev.Info.ID); d != nil {
+		return formatDecoded(eventRecord, ev, d)
+	}
+	if evdef := eventRecord.def; evdef != nil {
+		eventRecord.level = evdef.Level
+		// Filter events by level
+		if Level == "" || evdef.Level == Level {
+			eventRecord.Component = evdef.Brief
+			eventRecord.EventProperty = evdef.Property
+			eventRecord.Value, err = formatValue(ev, evdef, typedefs, memberTypes)
+			if err == nil && len(eventRecord.objects) != 0 {
+				eventRecord.Value = replaceObjects(eventRecord.Value, eventRecord.objects)
+			}
+			if err != nil && Strict != nil {
+				Strict.value(eventRecord.Index, ev.Info.ID, err)
+				eventRecord.Value, err = "<"+err.Error()+">", nil
+			}
+			show = err == nil
+		}
+	} else {
+		if Strict != nil {
+			Strict.unknown(eventRecord.Index, ev.Info.ID)
+		}
+		eventRecord.Component = fmt.Sprintf("0x%02X", uint8(ev.Info.ID>>8))
+		eventRecord.EventProperty = fmt.Sprintf("0x%04X", ev.Info.ID)
+		eventRecord.Value, _ = formatValue(ev, nil, typedefs, memberTypes)
+		show = true
+	}
+	return show, err
+}
+
+// format a record by the decoder of its ID, the decoder replaces the
+// definition of the SCVD file
+func formatDecoded(eventRecord *EventRecord, ev *event.Data, d plugin.Decoder) (bool, error) {
+	res, err := d.Decode(ev)
+	if err != nil {
+		return false, err
+	}
+	eventRecord.level = res.Level
+	if Level != "" && res.Level != Level {
+		return false, nil
+	}
+	eventRecord.Component = res.Component
+	if eventRecord.Component == "" {
+		eventRecord.Component = fmt.Sprintf("0x%02X", uint8(ev.Info.ID>>8))
+	}
+	eventRecord.EventProperty = res.Property
+	if eventRecord.EventProperty == "" {
+		eventRecord.EventProperty = fmt.Sprintf("0x%04X", ev.Info.ID)
+	}
+	eventRecord.Value = res.Value
+	return true, nil
+}
+
+// match a formatted record against Where
+func match(eventRecord *EventRecord, ev *event.Data) bool {
+	if Where == nil {
+		return true
+	}
+	value := eventRecord.Value
+	def := eventRecord.def
+	if Decoders.Find(ev.Info.ID) != nil { // match the fields of the decoder
+		def = &scvd.Event{Brief: eventRecord.Component, Property: eventRecord.EventProperty, Level: eventRecord.level}
+	}
+	return Where.Match(bus.NewEvent(eventRecord.Index, eventRecord.Time, ev, def, func() (string, error) { return value, nil }))
+}
+
+// names of the objects created before the event in its values
+func (o *Output) objectNames(ev *event.Data) []objectName {
+	if o.threads == nil || ev.Typ < 2 || ev.Typ > 3 {
+		return nil
+	}
+	var objects []objectName
+	for _, v := range []int32{ev.Value1, ev.Value2, ev.Value3, ev.Value4}[:2*ev.Typ-2] {
+		if name, ok := o.threads.Object(uint32(v)); ok {
+			objects = append(objects, objectName{uint32(v), name})
+		}
+	}
+	return objects
+}
+
+// replace the object IDs formatted by %x in a value by their names
+func replaceObjects(value string, objects []objectName) string {
+	for _, obj := range objects {
+		for _, hex := range []string{
+			fmt.Sprintf("0x%02x", obj.id), fmt.Sprintf("0x%08x", obj.id),
+		} {
+			var b strings.Builder
+			rest := value
+			for {
+				i := strings.Index(rest, hex)
+				if i < 0 {
+					break
+				}
+				end := i + len(hex)
+				if end < len(rest) && isHexDigit(rest[end]) {
+					b.WriteString(rest[:end])
+				} else {
+					b.WriteString(rest[:i])
+					b.WriteString(obj.name)
+				}
+				rest = rest[end:]
+			}
+			b.WriteString(rest)
+			value = b.String()
+		}
+	}
+	return value
+}
+
+func isHexDigit(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+// follow the running thread, the nesting, the time in the start/stop
+// pairs and the annotations, returns the nesting depth and the time in the pairs
+func (o *Output) follow(no int, time float64, ev *event.Data, def *scvd.Event) (depth int, cumulative float64) {
+	if o.threads == nil {
+		o.threads = rtos.NewTracker()
+	}
+	_ = o.threads.Event(bus.NewEvent(no, time, ev, def, nil))
+	if Annotations != nil {
+		if o.notes == nil {
+			o.notes = Annotations.Cursor()
+		}
+		o.note = strings.Join(o.notes.Next(no, time), "; ")
+	}
+	if Tree {
+		depth = o.nest.depth(ev)
+	}
+	return depth, o.pairs.add(ev, time)
+}
+
+// add a record to the event list, with Squash a record is held back
+// until the next different record arrives
+func (o *Output) addRecord(out *bufio.Writer, rec *EventRecord, show bool, eventTable *EventsTable) error {
+	if !Squash {
+		collect(eventTable, rec)
+		if show {
+			return o.emit(out, rec)
+		}
+		return nil
+	}
+	if o.pendingOK && o.pendingShow == show && o.pending.same(rec) {
+		if o.pending.Repeat == 0 {
+			o.pending.Repeat = 1
+		}
+		o.pending.Repeat++
+		o.pending.LastTime = rec.Time
+		return nil
+	}
+	err := o.flushRecord(out, eventTable)
+	o.pending, o.pendingShow, o.pendingOK = *rec, show, true
+	return err
+}
+
+// add the record held back by Squash to the event list
+func (o *Output) flushRecord(out *bufio.Writer, eventTable *EventsTable) error {
+	if !o.pendingOK {
+		return nil
+	}
+	o.pendingOK = false
+	collect(eventTable, &o.pending)
+	if o.pendingShow {
+		return o.emit(out, &o.pending)
+	}
+	return nil
+}
+
+// add a record to the event list, the list is nil if it is not written
+func collect(eventTable *EventsTable, rec *EventRecord) {
+	if eventTable != nil {
+		eventTable.Events = append(eventTable.Events, *rec)
+	}
+}
+
+// print a record, with Sort or Reverse it is held back until all records are read
+func (o *Output) emit(out *bufio.Writer, rec *EventRecord) error {
+	Shown++
+	if Sort != "" || Reverse {
+		o.sorted = append(o.sorted, *rec)
+		return nil
+	}
+	return o.export().Event(out, rec)
+}
+
+// set the duration of the start and stop records of the event pairs
+func setDurations(records []EventRecord) {
+	starts := make(map[uint16]int)
+	for i := range records {
+		ev := event.Data{Info: event.Info{ID: records[i].id}}
+		class, group, idx, start := ev.Info.SplitID()
+		if class != 0xEF {
+			continue
+		}
+		key := group<<4 | idx
+		if start {
+			starts[key] = i
+		} else if s, ok := starts[key]; ok {
+			d := records[i].Time - records[s].Time
+			records[s].duration = d
+			records[i].duration = d
+			delete(starts, key)
+		}
+	}
+}
+
+// index after the last printed event if the statistic pass can stop there,
+// 0 if it reads all events: Tail needs the number of events, the statistic
+// only and the analyzers need all events
+func stopAt(showStatistic bool) int {
+	if Tail > 0 || Limit <= 0 || showStatistic || len(Analyzers) != 0 {
+		return 0
+	}
+	return Skip + Limit
+}
+
+// set the indexes of the printed events from Skip, Limit and Tail
+func (o *Output) window(eventCount int) {
+	o.first = Skip
+	if Tail > 0 {
+		o.first = eventCount - Tail
+		if o.first < 0 {
+			o.first = 0
+		}
+	}
+	o.end = 0
+	if Limit > 0 {
+		o.end = o.first + Limit
+	}
+}
+
+// print the records held back for sorting
+func (o *Output) printSorted(out *bufio.Writer) error {
+	records := o.sorted
+	o.sorted = nil
+	setDurations(records)
+	sort.SliceStable(records, func(i, j int) bool {
+		switch Sort {
+		case "time":
+			return records[i].Time < records[j].Time
+		case "index":
+			return records[i].Index < records[j].Index
+		case "component":
+			return records[i].Component < records[j].Component
+		case "duration":
+			return records[i].duration < records[j].duration
+		}
+		return false
+	})
+	if Reverse {
+		for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+			records[i], records[j] = records[j], records[i]
+		}
+	}
+	ex := o.export()
+	for i := range records {
+		if err := ex.Event(out, &records[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (o *Output) printEvents(out *bufio.Writer, in *bufio.Reader, evdefs map[uint16]scvd.Event,
-	typedefs map[string]map[string]map[int16]string, eventTable *EventsTable) error {
+	typedefs map[string]map[string]map[int16]string, memberTypes map[string]map[string]string,
+	eventTable *EventsTable) error {
 	if out == nil || in == nil {
 		return nil
 	}
 	var err error
-	no := 0
-	var beforeClockEvent float64
-	var lastClockEvent uint64
-	for {
+	o.threads = rtos.NewTracker()
+	o.threadNames = printsThreads()
+	o.nest = nesting{}
+	o.pairs = pairTime{}
+	o.notes = nil
+	if Workers > 1 {
+		err = o.decodeParallel(out, in, evdefs, typedefs, memberTypes, eventTable)
+	} else {
+		err = o.decode(out, in, evdefs, typedefs, memberTypes, eventTable)
+	}
+	if err == nil {
+		err = o.flushRecord(out, eventTable)
+	}
+	if err == nil && o.sorted != nil {
+		defer Trace.Span("sort")()
+	}
+	if err == nil {
+		err = o.printSorted(out)
+	}
+	return err
+}
+
+// the skipped events must be read completely to follow the thread, the nesting,
+// the objects, the time in the start/stop pairs or the annotations
+func (o *Output) tracking() bool {
+	return Tree || ObjectNames || Annotations != nil || hasColumn("thread") || hasColumn("cumulative")
+}
+
+// check if the event list has a column
+func hasColumn(name string) bool {
+	for _, column := range Columns {
+		if column == name {
+			return true
+		}
+	}
+	return false
+}
+
+// check if the printed records need the running thread: the thread column
+// or a template, the thread is not exported otherwise
+func printsThreads() bool {
+	return hasColumn("thread") || Export != nil
+}
+
+// decode the events one after the other
+func (o *Output) decode(out *bufio.Writer, in *bufio.Reader, evdefs map[uint16]scvd.Event,
+	typedefs map[string]map[string]map[int16]string, memberTypes map[string]map[string]string,
+	eventTable *EventsTable) error {
+	var err error
+	c := o.newCursor(in, evdefs)
+	track := o.tracking()
+	readStage := Trace.Stage("read")
+	decodeStage := Trace.Stage("decode")
+	writeStage := Trace.Stage("write")
+	for o.end <= 0 || c.no < o.end {
 		var ev event.Data
-		if err = ev.Read(in); err != nil {
+		start := readStage.Start()
+		err = c.next(&ev, c.no < o.first && !track)
+		readStage.Stop(start)
+		if err != nil {
 			if errors.Is(err, eval.ErrEof) {
 				err = nil
 				break // end of event data reached
@@ -466,81 +1296,134 @@ func (o *Output) printEvents(out *bufio.Writer, in *bufio.Reader, evdefs map[uin
 		if err != nil {
 			break
 		}
-		if ev.Info.ID == 0xFF00 { // EventRecorderInitialize
-			if ev.Value2 != 0 {
-				beforeClockEvent = TimeInSecs(ev.Time)
-				lastClockEvent = ev.Time
-				if TimeFactor == nil {
-					TimeFactor = new(float64)
-				}
-				*TimeFactor = 1.0 / float64(ev.Value2)
+		time := c.tm.time(&ev)
+		if c.no < o.first {
+			if track {
+				o.follow(c.no, time, &ev, o.definition(evdefs, ev.Info.ID))
 			}
+			c.no++
+			continue
 		}
-		if ev.Info.ID == 0xFF03 { // Clock event
-			if ev.Value1 != 0 {
-				beforeClockEvent = TimeInSecs(ev.Time - lastClockEvent)
-				lastClockEvent = ev.Time
-				if TimeFactor == nil {
-					TimeFactor = new(float64)
-				}
-				*TimeFactor = 1.0 / float64(ev.Value1)
-			}
+		start = decodeStage.Start()
+		eventRecord, show, err := o.buildRecord(c.no, time, &ev, evdefs, typedefs, memberTypes)
+		decodeStage.Stop(start)
+		if err != nil {
+			_ = o.flushRecord(out, eventTable)
+			collect(eventTable, &eventRecord)
+			return err
 		}
-		eventRecord := EventRecord{
-			Index: no,
-			Time:  beforeClockEvent + TimeInSecs(ev.Time-lastClockEvent),
-		}
-		var rep string
-		if evdef, ok := evdefs[ev.Info.ID]; ok {
-			// Filter events by level
-			if Level == "" || evdef.Level == Level {
-				eventRecord.Component = evdef.Brief
-				eventRecord.EventProperty = evdef.Property
-				if ev.Info.ID == 0xFE00 && ev.Data != nil { // special case stdout
-					s := escapeGen(string(*ev.Data))
-					eventRecord.Value = s
-					err = conditionalWrite(out, "%5d %.8f %*s %*s \"%s\"\n",
-						eventRecord.Index, eventRecord.Time, -o.componentSize,
-						eventRecord.Component, -o.propertySize, eventRecord.EventProperty, eventRecord.Value)
-				} else {
-					rep, err = ev.EvalLine(evdef, typedefs)
-					if err == nil {
-						eventRecord.Value = rep
-						err = conditionalWrite(out, "%5d %.8f %*s %*s %s\n",
-							eventRecord.Index, eventRecord.Time, -o.componentSize,
-							eventRecord.Component, -o.propertySize, eventRecord.EventProperty, eventRecord.Value)
-					}
-				}
-			}
-		} else {
-			eventRecord.Component = fmt.Sprintf("0x%02X%*s", uint8(ev.Info.ID>>8), 0, "")
-			eventRecord.EventProperty = fmt.Sprintf("0x%04X%*s", ev.Info.ID, 0, "")
-			if ev.Info.ID == 0xFE00 && ev.Data != nil { // special case stdout
-				s := escapeGen(string(*ev.Data))
-				eventRecord.Value = s
-				err = conditionalWrite(out, "%5d %.8f 0x%02X%*s 0x%04X%*s \"%s\"\n",
-					eventRecord.Index, eventRecord.Time,
-					uint8(ev.Info.ID>>8), -(o.componentSize - 4), "",
-					ev.Info.ID, -(o.propertySize - 6), "", eventRecord.Value)
-			} else {
-				rep = ev.GetValuesAsString()
-				eventRecord.Value = rep
-				err = conditionalWrite(out, "%5d %.8f 0x%02X%*s 0x%04X%*s %s\n",
-					eventRecord.Index, eventRecord.Time,
-					uint8(ev.Info.ID>>8), -(o.componentSize - 4), "",
-					ev.Info.ID, -(o.propertySize - 6), "", eventRecord.Value)
-			}
+		start = writeStage.Start()
+		if err = o.addRecord(out, &eventRecord, show, eventTable); err != nil {
+			return err
 		}
-		eventTable.Events = append(eventTable.Events, eventRecord)
-		if err != nil {
-			break
+		writeStage.Stop(start)
+		c.no++
+	}
+	return err
+}
+
+// get header and underline of a column
+func (o *Output) column(name string) (string, string) {
+	switch name {
+	case "index":
+		return o.columns[0], "-----"
+	case "time":
+		return o.columns[1], "--------"
+	case "component":
+		return o.columns[2], "---------"
+	case "event":
+		return o.columns[3], "--------------"
+	case "level":
+		return "Level", "-----"
+	case "thread":
+		return "Thread", "------"
+	case "cumulative":
+		return "Cumulative (s)", "--------------"
+	case "raw":
+		return "Raw Values", "----------"
+	}
+	return o.columns[4], "-----"
+}
+
+// get the width of a column, negative widths are left aligned
+func (o *Output) columnWidth(name string, last bool) int {
+	if last {
+		return 0 // no trailing blanks
+	}
+	switch name {
+	case "index":
+		return 5
+	case "time":
+		return -10
+	case "component":
+		return -o.componentSize
+	case "event":
+		return -o.propertySize
+	case "level":
+		return -6
+	case "thread":
+		return -o.threadSize
+	case "cumulative":
+		return -14
+	}
+	return 0
+}
+
+// color a cell by the component or the level of the event
+func colorize(name string, rec *EventRecord, cell string) string {
+	var code string
+	switch name {
+	case "component":
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(rec.Component))
+		code = componentColors[h.Sum32()%uint32(len(componentColors))]
+	case "event", "message", "level":
+		code = levelColors[rec.level]
+	}
+	if code == "" {
+		return cell
+	}
+	return "\x1b[" + code + "m" + cell + "\x1b[0m"
+}
+
+// print one line of the event list, the line is built in buffers
+// reused for all lines
+func (o *Output) printEvent(out io.Writer, rec *EventRecord) error {
+	if FormatType != "txt" {
+		return nil
+	}
+	line := o.line[:0]
+	if len(rec.Annotation) != 0 {
+		line = append(line, "      # "...)
+		line = append(line, rec.Annotation...)
+		line = append(line, '\n')
+	}
+	for i, name := range Columns {
+		width := o.columnWidth(name, i == len(Columns)-1)
+		if i > 0 {
+			line = append(line, ' ')
+		}
+		o.cell = rec.appendColumn(o.cell[:0], name)
+		if o.links && name == "message" {
+			o.cell = appendLinks(nil, appendPadded(nil, o.cell, width))
+			width = 0
+		}
+		if o.color {
+			line = append(line, colorize(name, rec, string(appendPadded(nil, o.cell, width)))...)
+		} else {
+			line = appendPadded(line, o.cell, width)
 		}
-		no++
 	}
+	if rec.Repeat > 1 {
+		line = fmt.Appendf(line, " (%d times, last %.8f)", rec.Repeat, rec.LastTime)
+	}
+	line = append(line, '\n')
+	o.line = line
+	_, err := out.Write(line)
 	return err
 }
 
-func (o *Output) printHeader(out *bufio.Writer) error {
+func (o *Output) printHeader(out io.Writer) error {
 	var err error
 	if err = conditionalWrite(out, "   Detailed event list\n"); err != nil {
 		return err
@@ -548,18 +1431,84 @@ func (o *Output) printHeader(out *bufio.Writer) error {
 	if err = conditionalWrite(out, "   -------------------\n\n"); err != nil {
 		return err
 	}
-	err = conditionalWrite(out, "%5s %-10s %*s %*s %s\n", o.columns[0], o.columns[1],
-		-o.componentSize, o.columns[2], -o.propertySize, o.columns[3], o.columns[4])
-	if err != nil {
+	var header, line string
+	for i, name := range Columns {
+		text, dashes := o.column(name)
+		width := o.columnWidth(name, i == len(Columns)-1)
+		if i > 0 {
+			header += " "
+			line += " "
+		}
+		header += fmt.Sprintf("%*s", width, text)
+		line += fmt.Sprintf("%*s", width, dashes)
+	}
+	if err = conditionalWrite(out, "%s\n", header); err != nil {
 		return err
 	}
-	err = conditionalWrite(out, "----- --------   %*s %*s -----\n",
-		-o.componentSize, "---------", -o.propertySize, "--------------")
+	err = conditionalWrite(out, "%s\n", line)
 	return err
 }
 
+// print the sections of the analyzers producing a report
+func printReports(out *bufio.Writer) error {
+	if FormatType != "txt" {
+		return nil
+	}
+	for _, a := range Analyzers {
+		if r, ok := a.(bus.Reporter); ok && r != Summary {
+			if _, err := out.WriteString("\n"); err != nil {
+				return err
+			}
+			if err := r.Report(out); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// print the events and statistics as html report defined by the dashboard
+func printHTML(out *bufio.Writer, eventsTable *EventsTable) error {
+	spec := Dashboard
+	if spec == nil {
+		spec = &dashboard.Default
+	}
+	events := make([]dashboard.Event, 0, len(eventsTable.Events))
+	for _, rec := range eventsTable.Events {
+		if Level != "" && rec.level != "" && rec.level != Level {
+			continue // filtered by level
+		}
+		events = append(events, dashboard.Event{
+			Index:      rec.Index,
+			Time:       rec.Time,
+			Level:      rec.level,
+			Component:  rec.Component,
+			Property:   rec.EventProperty,
+			Value:      rec.Value,
+			Marker:     rec.Marker,
+			Annotation: rec.Annotation,
+		})
+	}
+	stats := make([]dashboard.Statistic, 0, len(eventsTable.Statistics))
+	for _, st := range eventsTable.Statistics {
+		stats = append(stats, dashboard.Statistic{
+			Event: st.Event,
+			Count: st.Count,
+			Total: st.Total,
+			Min:   st.Min,
+			Max:   st.Max,
+			Avg:   st.Avg,
+		})
+	}
+	if err := spec.Render(out, events, stats); err != nil {
+		return err
+	}
+	return out.Flush()
+}
+
 func (o *Output) print(out *bufio.Writer, eventFile *string, evdefs map[uint16]scvd.Event,
-	typedefs map[string]map[string]map[int16]string, statBegin bool, showStatistic bool, eventsTable *EventsTable) error {
+	typedefs map[string]map[string]map[int16]string, memberTypes map[string]map[string]string,
+	statBegin bool, showStatistic bool, eventsTable *EventsTable) error {
 	var b event.Binary
 	var err error
 	var eventCount int
@@ -567,14 +1516,50 @@ func (o *Output) print(out *bufio.Writer, eventFile *string, evdefs map[uint16]s
 	o.columns = []string{"Index", "Time (s)", "Component", "Event Property", "Value"}
 
 	if eventFile == nil {
-		return errNoEvents
+		return ErrNoEvents
 	}
+	if Progress {
+		if info, err := os.Stat(*eventFile); err == nil {
+			passes := 2
+			if showStatistic {
+				passes = 1
+			}
+			bar := progress.New(os.Stderr, info.Size(), passes)
+			b.Wrap = bar.Reader
+			defer bar.Finish()
+		}
+	}
+
+	o.shutdown = newShutdown(nil)
+	defer func() {
+		o.shutdown.release()
+		Interrupted = o.shutdown.interrupted()
+	}()
+
+	o.loadIndex(&b, *eventFile)
+	endSpan := Trace.Span("statistic pass")
 	in := b.Open(eventFile)
 	if in != nil {
-		eventCount = o.buildStatistic(in, evdefs, typedefs)
+		o.stop = stopAt(showStatistic)
+		eventCount = o.buildStatistic(in, evdefs, typedefs, memberTypes)
 		err = b.Close()
+		if o.stop > 0 && eventCount == o.stop {
+			o.indexer = nil // the index of a part of the events is not saved
+		}
+		if err == nil && !o.shutdown.interrupted() {
+			o.saveIndex(*eventFile, eventCount)
+		}
 	} else {
-		err = errNoEvents
+		err = ErrNoEvents
+	}
+	endSpan()
+
+	o.window(eventCount)
+
+	if err == nil && Summary != nil && FormatType == "txt" {
+		if err = Summary.Report(out); err == nil {
+			err = conditionalWrite(out, "\n")
+		}
 	}
 
 	if err == nil && statBegin {
@@ -584,20 +1569,29 @@ func (o *Output) print(out *bufio.Writer, eventFile *string, evdefs map[uint16]s
 		}
 	}
 
-	if err == nil && !showStatistic {
-		err = o.printHeader(out)
+	// the events are not printed when interrupted before they were counted
+	if err == nil && !showStatistic && !o.shutdown.interrupted() {
+		err = o.export().Begin(out)
 		if err == nil {
+			endSpan = Trace.Span("event pass")
 			in = b.Open(eventFile)
 			if in != nil {
-				err = o.printEvents(out, in, evdefs, typedefs, eventsTable)
+				events := eventsTable
+				if !marshalled() {
+					events = nil // the events are exported while reading
+				}
+				o.file = &b
+				err = o.printEvents(out, in, evdefs, typedefs, memberTypes, events)
+				o.file = nil
 				if err != nil {
 					_ = b.Close()
 				} else {
 					err = b.Close()
 				}
 			} else {
-				err = errNoEvents // cannot happen because eventFile already was read
+				err = ErrNoEvents // cannot happen because eventFile already was read
 			}
+			endSpan()
 		}
 	}
 
@@ -609,6 +1603,14 @@ func (o *Output) print(out *bufio.Writer, eventFile *string, evdefs map[uint16]s
 			err = o.printStatistic(out, eventCount, eventsTable)
 		}
 	}
+	if err == nil {
+		err = o.export().End(out)
+	}
+	if err == nil {
+		endSpan = Trace.Span("reports")
+		err = printReports(out)
+		endSpan()
+	}
 	if err == nil {
 		err = out.Flush()
 	}
@@ -616,7 +1618,8 @@ func (o *Output) print(out *bufio.Writer, eventFile *string, evdefs map[uint16]s
 }
 
 func Print(filename *string, formatType *string, level *string, eventFile *string, evdefs map[uint16]scvd.Event,
-	typedefs map[string]map[string]map[int16]string, statBegin bool, showStatistic bool) error {
+	typedefs map[string]map[string]map[int16]string, memberTypes map[string]map[string]string,
+	statBegin bool, showStatistic bool) error {
 	var file *os.File
 	var err error
 	var o Output
@@ -632,26 +1635,57 @@ func Print(filename *string, formatType *string, level *string, eventFile *strin
 	if *TimeFactor == 0.0 {
 		*TimeFactor = 4e-8
 	}
+	Shown = 0
+	Interrupted = false
+	FormatType = "txt"
 	if formatType != nil {
-		if *formatType == "xml" || *formatType == "json" {
+		if *formatType == "xml" || *formatType == "json" || *formatType == "html" {
+			FormatType = *formatType
+		} else if newExporter, ok := exporters[*formatType]; ok {
 			FormatType = *formatType
+			o.exporter = newExporter()
 		}
 	}
+	if Export != nil {
+		FormatType = "template"
+		o.exporter = Export
+	}
+	if len(Outputs) != 0 {
+		t, err := newTee(o.export(), Outputs)
+		if err != nil {
+			return err
+		}
+		defer t.close()
+		o.exporter = t
+	}
+	Level = ""
 	if level != nil && *level != "" {
 		Level = *level
 	}
 
+	var w io.Writer
 	if filename != nil && len(*filename) != 0 {
 		if file, err = os.Create(*filename); err != nil {
 			return err
 		}
 		defer file.Close()
+		w = file
 	} else {
 		file = os.Stdout
+		w = file
+		if len(Pager) != 0 && isTerminal(file) {
+			if p, err := pager.Start(Pager, file); err == nil {
+				defer p.Close()
+				w = p
+			}
+		}
 	}
 
-	out := bufio.NewWriter(file)
-	err = o.print(out, eventFile, evdefs, typedefs, statBegin, showStatistic, &eventsTable)
+	out := bufio.NewWriterSize(w, BufferSize)
+	o.color = useColor(file)
+	o.links = useLinks(file)
+	err = o.print(out, eventFile, evdefs, typedefs, memberTypes, statBegin, showStatistic, &eventsTable)
+	defer Trace.Span("encode " + FormatType)()
 	if err == nil {
 		if FormatType == "json" {
 			output, err := json.Marshal(eventsTable)
@@ -662,6 +1696,8 @@ func Print(filename *string, formatType *string, level *string, eventFile *strin
 					out.Flush()
 				}
 			}
+		} else if FormatType == "html" {
+			err = printHTML(out, &eventsTable)
 		} else if FormatType == "xml" {
 			output, err := xml.Marshal(eventsTable)
 			if err == nil {
@@ -679,3 +1715,190 @@ func Print(filename *string, formatType *string, level *string, eventFile *strin
 	}
 	return err
 }
+
+// Decoder formats the events one after the other, e.g. of a live source
+// read by the caller
+type Decoder struct {
+	o           Output
+	tm          timer
+	no          int
+	evdefs      map[uint16]scvd.Event
+	typedefs    map[string]map[string]map[int16]string
+	memberTypes map[string]map[string]string
+}
+
+// NewDecoder creates a decoder of the events of the SCVD definitions, the
+// records have the running thread
+func NewDecoder(evdefs map[uint16]scvd.Event, typedefs map[string]map[string]map[int16]string,
+	memberTypes map[string]map[string]string) *Decoder {
+	return &Decoder{o: Output{threadNames: true}, evdefs: evdefs, typedefs: typedefs, memberTypes: memberTypes}
+}
+
+// Record returns the record of the next event, the value of the record is
+// the error message if it cannot be formatted
+func (d *Decoder) Record(ev *event.Data) EventRecord {
+	rec, _, err := d.o.buildRecord(d.no, d.tm.time(ev), ev, d.evdefs, d.typedefs, d.memberTypes)
+	if err != nil {
+		rec.Value = err.Error()
+	}
+	d.no++
+	return rec
+}
+
+// Decode reads the events and passes the record of every event to fn,
+// the value of a record is the error message if it cannot be formatted
+func Decode(in io.Reader, evdefs map[uint16]scvd.Event, typedefs map[string]map[string]map[int16]string,
+	memberTypes map[string]map[string]string,
+	fn func(rec *EventRecord, ev *event.Data) error) error {
+	d := NewDecoder(evdefs, typedefs, memberTypes)
+	rd := bufio.NewReader(in)
+	for {
+		var ev event.Data
+		if err := ev.Read(rd); err != nil {
+			if errors.Is(err, eval.ErrEof) {
+				return nil
+			}
+			return err
+		}
+		rec := d.Record(&ev)
+		if err := fn(&rec, &ev); err != nil {
+			return err
+		}
+	}
+}
+
+// print a live event and publish it to the analyzers
+func (o *Output) liveEvent(out *bufio.Writer, no int, time float64, ev *event.Data, evdefs map[uint16]scvd.Event,
+	typedefs map[string]map[string]map[int16]string, memberTypes map[string]map[string]string,
+	b *bus.Bus) error {
+	rec, show, err := o.buildRecord(no, time, ev, evdefs, typedefs, memberTypes)
+	if err == nil {
+		err = o.addRecord(out, &rec, show, nil)
+	}
+	if err != nil {
+		return err
+	}
+	return b.Publish(newEvent(no, time, ev, rec.def, typedefs, memberTypes))
+}
+
+// Live prints the events of a live source while they are received,
+// the statistic and the reports follow at the end of the stream
+func Live(filename *string, in io.Reader, evdefs map[uint16]scvd.Event,
+	typedefs map[string]map[string]map[int16]string, memberTypes map[string]map[string]string) (err error) {
+	var file *os.File
+	var o Output
+
+	if TimeFactor == nil {
+		TimeFactor = new(float64)
+	}
+	if *TimeFactor == 0.0 {
+		*TimeFactor = 4e-8
+	}
+	Shown = 0
+	Interrupted = false
+	FormatType = "txt" // the live events are printed as text only
+	var w io.Writer
+	if filename != nil && len(*filename) != 0 && Rotate.Enabled() {
+		var r *rotate.File
+		if r, err = rotate.Create(*filename, Rotate); err != nil {
+			return err
+		}
+		defer func() {
+			if closeErr := r.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+		w = r
+	} else if filename != nil && len(*filename) != 0 {
+		if file, err = os.Create(*filename); err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	} else {
+		file = os.Stdout
+		w = file
+	}
+	out := bufio.NewWriterSize(w, BufferSize)
+	defer out.Flush() // keep the events printed before an error
+	o.color = useColor(file)
+	o.links = useLinks(file)
+	o.threadNames = printsThreads()
+
+	o.columns = []string{"Index", "Time (s)", "Component", "Event Property", "Value"}
+	o.componentSize = len(o.columns[2]) // the widths cannot depend on the received events
+	o.propertySize = len(o.columns[3])
+	o.threadSize = rtos.NewTracker().NameSize()
+	for _, evdef := range evdefs {
+		if len(evdef.Brief) > o.componentSize {
+			o.componentSize = len(evdef.Brief)
+		}
+		if len(evdef.Property) > o.propertySize {
+			o.propertySize = len(evdef.Property)
+		}
+	}
+	for i := range o.evProps {
+		o.evProps[i].init()
+	}
+	var b bus.Bus
+	b.Subscribe(&o)
+	for _, a := range Analyzers {
+		b.Subscribe(a)
+	}
+
+	if err = o.export().Begin(out); err != nil {
+		return err
+	}
+	if err = out.Flush(); err != nil {
+		return err
+	}
+	rd := bufio.NewReader(in)
+	var tm timer
+	var no int
+	f := newFlusher(out, FlushInterval)
+	o.shutdown = newShutdown(func() {
+		if c, ok := in.(io.Closer); ok {
+			_ = c.Close() // unblocks the reading
+		}
+	})
+	for {
+		var ev event.Data
+		if err = ev.Read(rd); err != nil {
+			if errors.Is(err, eval.ErrEof) || o.shutdown.interrupted() {
+				err = nil
+			}
+			break
+		}
+		f.lock()
+		err = o.liveEvent(out, no, tm.time(&ev), &ev, evdefs, typedefs, memberTypes, &b)
+		if flushErr := f.unlock(); err == nil {
+			err = flushErr
+		}
+		if err != nil {
+			break
+		}
+		no++
+	}
+	f.stop()
+	o.shutdown.release()
+	Interrupted = o.shutdown.interrupted()
+	if flushErr := o.flushRecord(out, nil); err == nil {
+		err = flushErr
+	}
+	if endErr := b.End(); err == nil {
+		err = endErr
+	}
+	if err == nil {
+		err = conditionalWrite(out, "\n")
+	}
+	if err == nil {
+		err = o.printStatistic(out, no, &EventsTable{})
+	}
+	if err == nil {
+		err = printReports(out)
+	}
+	if err == nil {
+		err = out.Flush()
+	}
+	return err
+}
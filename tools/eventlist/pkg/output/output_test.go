@@ -22,12 +22,21 @@ import (
 	"bufio"
 	"bytes"
 	"errors"
+	"eventlist/pkg/annotate"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/dashboard"
+	"eventlist/pkg/elf"
 	"eventlist/pkg/event"
+	"eventlist/pkg/plugin"
+	"eventlist/pkg/rotate"
+	"eventlist/pkg/selftrace"
+	"eventlist/pkg/where"
 	"eventlist/pkg/xml/scvd"
 	"fmt"
 	"io"
 	"math"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -228,7 +237,7 @@ func Test_eventStatistic_add(t *testing.T) {
 				textMaxB: tt.fields.textMaxB,
 				textMaxE: tt.fields.textMaxE,
 			}
-			es.add(tt.args.time, tt.args.start, tt.args.text)
+			es.add(tt.args.time, tt.args.start, tt.args.text, nil)
 			if !reflect.DeepEqual(*es, tt.want) {
 				t.Errorf("eventStatistic.add() %s = %v, want %v", tt.name, *es, tt.want)
 			}
@@ -296,7 +305,7 @@ func Test_eventProperty_add(t *testing.T) {
 			ep := &eventProperty{
 				values: tt.fields.values,
 			}
-			ep.add(tt.args.time, tt.args.idx, tt.args.start, tt.args.text)
+			ep.add(tt.args.time, tt.args.idx, tt.args.start, tt.args.text, nil)
 			if ep.values[tt.args.idx].evStart != tt.wantev {
 				t.Errorf("eventProperty.add() %s = %v, want %v", tt.name,
 					ep.values[tt.args.idx].evStart, tt.wantev)
@@ -376,6 +385,34 @@ func Test_eventProperty_getAddCount(t *testing.T) {
 	}
 }
 
+func Test_eventProperty_getPercentile(t *testing.T) {
+	t.Parallel()
+
+	var ep eventProperty
+	ep.init()
+	for i := 1; i <= 100; i++ {
+		ep.add(float64(10*i), 2, true, "", nil)
+		ep.add(float64(10*i+i), 2, false, "", nil) // duration i s
+	}
+	ep.add(2000, 3, true, "", nil) // not stopped
+	tests := []struct {
+		name string
+		idx  uint16
+		q    float64
+		want string
+	}{
+		{"p50", 2, 0.5, convertUnit(50, "s")},
+		{"p90", 2, 0.9, convertUnit(90, "s")},
+		{"p99", 2, 0.99, convertUnit(99, "s")},
+		{"open", 3, 0.5, convertUnit(0, "s")},
+	}
+	for _, tt := range tests {
+		if got := ep.getPercentile(tt.idx, tt.q); got != tt.want {
+			t.Errorf("eventProperty.getPercentile() %s = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
 func Test_convertUnit(t *testing.T) {
 	t.Parallel()
 
@@ -608,6 +645,7 @@ func TestOutput_buildStatistic(t *testing.T) { //nolint:golint,paralleltest
 	eds[0xEF00] = scvd.Event{Brief: "briefbriefbrief", Property: "propertypropertyproperty", Value: "value"}
 
 	tds := make(map[string]map[string]map[int16]string)
+	mts := make(map[string]map[string]string)
 
 	var s1 = "../../testdata/test1.binary"
 	var s3 = "../../testdata/test3.binary"
@@ -622,9 +660,10 @@ func TestOutput_buildStatistic(t *testing.T) { //nolint:golint,paralleltest
 		propertySize  int
 	}
 	type args struct {
-		file     string
-		evdefs   map[uint16]scvd.Event
-		typedefs map[string]map[string]map[int16]string
+		file        string
+		evdefs      map[uint16]scvd.Event
+		typedefs    map[string]map[string]map[int16]string
+		memberTypes map[string]map[string]string
 	}
 	tests := []struct {
 		name   string
@@ -635,12 +674,12 @@ func TestOutput_buildStatistic(t *testing.T) { //nolint:golint,paralleltest
 		want2  int
 		want3  float64
 	}{
-		{"test1", fields{[4]eventProperty{}, []string{"Index", "Time (s)", "Component", "Event Property", "Value"}, 0, 0}, args{s1, eds0, tds}, 0, 9, 14, 0.0},
-		{"test3", fields{[4]eventProperty{}, []string{"Index", "Time (s)", "Component", "Event Property", "Value"}, 0, 0}, args{s3, eds0, tds}, 1, 9, 14, 0.0},
-		{"test4", fields{[4]eventProperty{}, []string{"Index", "Time (s)", "Component", "Event Property", "Value"}, 0, 0}, args{s4, eds0, tds}, 1, 9, 14, 0.5},
-		{"test6", fields{[4]eventProperty{}, []string{"Index", "Time (s)", "Component", "Event Property", "Value"}, 0, 0}, args{s6, eds0, tds}, 1, 9, 14, 0.25},
-		{"test7a", fields{[4]eventProperty{}, []string{"Index", "Time (s)", "Component", "Event Property", "Value"}, 0, 0}, args{s7, eds0, tds}, 1, 9, 14, 0.25},
-		{"test7b", fields{[4]eventProperty{}, []string{"Index", "Time (s)", "Component", "Event Property", "Value"}, 0, 0}, args{s7, eds, tds}, 1, 15, 24, 0.25},
+		{"test1", fields{[4]eventProperty{}, []string{"Index", "Time (s)", "Component", "Event Property", "Value"}, 0, 0}, args{s1, eds0, tds, mts}, 0, 9, 14, 0.0},
+		{"test3", fields{[4]eventProperty{}, []string{"Index", "Time (s)", "Component", "Event Property", "Value"}, 0, 0}, args{s3, eds0, tds, mts}, 1, 9, 14, 0.0},
+		{"test4", fields{[4]eventProperty{}, []string{"Index", "Time (s)", "Component", "Event Property", "Value"}, 0, 0}, args{s4, eds0, tds, mts}, 1, 9, 14, 0.5},
+		{"test6", fields{[4]eventProperty{}, []string{"Index", "Time (s)", "Component", "Event Property", "Value"}, 0, 0}, args{s6, eds0, tds, mts}, 1, 9, 14, 0.25},
+		{"test7a", fields{[4]eventProperty{}, []string{"Index", "Time (s)", "Component", "Event Property", "Value"}, 0, 0}, args{s7, eds0, tds, mts}, 1, 9, 14, 0.25},
+		{"test7b", fields{[4]eventProperty{}, []string{"Index", "Time (s)", "Component", "Event Property", "Value"}, 0, 0}, args{s7, eds, tds, mts}, 1, 15, 24, 0.25},
 	}
 	for _, tt := range tests { //nolint:golint,paralleltest
 		t.Run(tt.name, func(t *testing.T) {
@@ -653,7 +692,7 @@ func TestOutput_buildStatistic(t *testing.T) { //nolint:golint,paralleltest
 			TimeFactor = nil
 			var b event.Binary
 			in := b.Open(&tt.args.file)
-			if got := o.buildStatistic(in, tt.args.evdefs, tt.args.typedefs); got != tt.want {
+			if got := o.buildStatistic(in, tt.args.evdefs, tt.args.typedefs, tt.args.memberTypes); got != tt.want {
 				t.Errorf("Output.buildStatistic() %s = %v, want %v", tt.name, got, tt.want)
 			}
 			b.Close()
@@ -667,11 +706,116 @@ func TestOutput_buildStatistic(t *testing.T) { //nolint:golint,paralleltest
 	}
 }
 
+type testAnalyzer struct {
+	values []string
+	ended  bool
+}
+
+func (a *testAnalyzer) Event(ev *bus.Event) error {
+	value, err := ev.Value()
+	a.values = append(a.values, fmt.Sprintf("%d %s", ev.Index, value))
+	return err
+}
+
+func (a *testAnalyzer) End() error {
+	a.ended = true
+	return nil
+}
+
+func (a *testAnalyzer) Report(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "%d events\n", len(a.values))
+	return err
+}
+
+func Test_printReports(t *testing.T) { //nolint:golint,paralleltest
+	var b bytes.Buffer
+
+	Analyzers = []bus.Analyzer{&testAnalyzer{values: []string{"a", "b"}}, &testAnalyzer{}}
+	defer func() { Analyzers = nil }()
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{"txt", "txt", "\n2 events\n\n0 events\n"},
+		{"json", "json", ""},
+	}
+	for _, tt := range tests { //nolint:golint,paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			b.Reset()
+			FormatType = tt.format
+			defer func() { FormatType = "txt" }()
+			out := bufio.NewWriter(&b)
+			if err := printReports(out); err != nil {
+				t.Errorf("printReports() %s error = %v", tt.name, err)
+			}
+			out.Flush()
+			if b.String() != tt.want {
+				t.Errorf("printReports() %s = %q, want %q", tt.name, b.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestOutput_buildStatisticAnalyzers(t *testing.T) { //nolint:golint,paralleltest
+	var s10 = "../../testdata/test10.binary"
+
+	a := &testAnalyzer{}
+	Analyzers = []bus.Analyzer{a}
+	defer func() { Analyzers = nil }()
+
+	o := &Output{columns: []string{"Index", "Time (s)", "Component", "Event Property", "Value"}}
+	TimeFactor = nil
+	var b event.Binary
+	in := b.Open(&s10)
+	if got := o.buildStatistic(in, nil, nil, nil); got != 2 {
+		t.Errorf("Output.buildStatistic() = %v, want 2", got)
+	}
+	b.Close()
+	want := []string{"0 val1=0x00000004, val2=0x00000002", "1 hello wo"}
+	if !reflect.DeepEqual(a.values, want) {
+		t.Errorf("Output.buildStatistic() analyzer = %v, want %v", a.values, want)
+	}
+	if !a.ended {
+		t.Errorf("Output.buildStatistic() analyzer not ended")
+	}
+}
+
+func Test_formatValue(t *testing.T) {
+	t.Parallel()
+
+	data := []uint8("a\tb")
+	def := scvd.Event{Value: "v=%d[val1]"}
+	tests := []struct {
+		name  string
+		ev    event.Data
+		evdef *scvd.Event
+		want  string
+	}{
+		{"stdout", event.Data{Info: event.Info{ID: 0xFE00}, Data: &data}, &def, "a\\tb"},
+		{"unknown", event.Data{Typ: 2, Value1: 1, Value2: 2}, nil, "val1=0x00000001, val2=0x00000002"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := formatValue(&tt.ev, tt.evdef, nil, nil)
+			if err != nil {
+				t.Errorf("formatValue() %s error = %v", tt.name, err)
+			}
+			if got != tt.want {
+				t.Errorf("formatValue() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestOutput_printStatistic(t *testing.T) { //nolint:golint,paralleltest
 	var b bytes.Buffer
 
 	props0 := [4]eventProperty{}
-	props1 := [4]eventProperty{{[16]eventStatistic{{evFirst: true, count: 1, tot: 2, min: 3, max: 4, avg: 5, first: 6, last: 7}}}}
+	props1 := [4]eventProperty{{values: [16]eventStatistic{{evFirst: true, count: 1, tot: 2, min: 3, max: 4, avg: 5, first: 6, last: 7}}}}
 
 	header := "   Start/Stop event statistic\n" +
 		"   --------------------------\n\n" +
@@ -776,12 +920,12 @@ func TestOutput_printEvents(t *testing.T) { //nolint:golint,paralleltest
 	var s11 = "../../testdata/test11.binary"
 	var sNix = "../../testdata/xxxx"
 
-	line1 := "    0 0.00000124 0xFF     0xFF03       val1=0x00000004, val2=0x00000002\n" +
-		"    1 0.00000124 0xFE     0xFE00       \"hello wo\"\n"
+	line1 := "    0 0.00000124 0xFF      0xFF03         val1=0x00000004, val2=0x00000002\n" +
+		"    1 0.00000124 0xFE      0xFE00         \"hello wo\"\n"
 	line2 := "    0 0.00000124 briefbriefbrief propertypropertyproperty value\n" +
 		"    1 0.00000124 briefbriefbrief propertypropertyproperty \"hello wo\"\n"
-	line3 := "    0 0.00000124 0xFF     0xFF00       val1=0x00000004, val2=0x00000002\n" +
-		"    1 0.00000124 0xFE     0xFE00       \"hello wo\"\n"
+	line3 := "    0 0.00000124 0xFF      0xFF00         val1=0x00000004, val2=0x00000002\n" +
+		"    1 0.00000124 0xFE      0xFE00         \"hello wo\"\n"
 
 	type fields struct {
 		evProps       [4]eventProperty
@@ -790,10 +934,11 @@ func TestOutput_printEvents(t *testing.T) { //nolint:golint,paralleltest
 		propertySize  int
 	}
 	type args struct {
-		out      *bufio.Writer
-		in       *bufio.Reader
-		evdefs   map[uint16]scvd.Event
-		typedefs map[string]map[string]map[int16]string
+		out         *bufio.Writer
+		in          *bufio.Reader
+		evdefs      map[uint16]scvd.Event
+		typedefs    map[string]map[string]map[int16]string
+		memberTypes map[string]map[string]string
 	}
 	tests := []struct {
 		name    string
@@ -805,9 +950,9 @@ func TestOutput_printEvents(t *testing.T) { //nolint:golint,paralleltest
 	}{
 		{"readErr0", fields{}, args{}, &s0, "", false},
 		{"readErr1", fields{}, args{}, &s1, "", true},
-		{"read1", fields{}, args{}, &s10, line1, false},
+		{"read1", fields{componentSize: 9, propertySize: 14}, args{}, &s10, line1, false},
 		{"read2", fields{}, args{evdefs: eds}, &s10, line2, false},
-		{"read3", fields{}, args{}, &s11, line3, false},
+		{"read3", fields{componentSize: 9, propertySize: 14}, args{}, &s11, line3, false},
 		{"readNix", fields{}, args{}, &sNix, "", false},
 	}
 	eventsTable := EventsTable{
@@ -827,7 +972,7 @@ func TestOutput_printEvents(t *testing.T) { //nolint:golint,paralleltest
 				componentSize: tt.fields.componentSize,
 				propertySize:  tt.fields.propertySize,
 			}
-			if err := o.printEvents(tt.args.out, tt.args.in, tt.args.evdefs, tt.args.typedefs, &eventsTable); (err != nil) != tt.wantErr {
+			if err := o.printEvents(tt.args.out, tt.args.in, tt.args.evdefs, tt.args.typedefs, tt.args.memberTypes, &eventsTable); (err != nil) != tt.wantErr {
 				t.Errorf("Output.printEvents() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
 			}
 			tt.args.out.Flush()
@@ -842,6 +987,90 @@ func TestOutput_printEvents(t *testing.T) { //nolint:golint,paralleltest
 	}
 }
 
+func TestSetColumns(t *testing.T) { //nolint:golint,paralleltest
+	tests := []struct {
+		name    string
+		list    string
+		want    []string
+		wantErr bool
+	}{
+		{"one", "message", []string{"message"}, false},
+		{"order", "time, index,level,raw", []string{"time", "index", "level", "raw"}, false},
+		{"unknown", "index,core", []string{"index", "time", "component", "event", "message"}, true},
+		{"empty", "", []string{"index", "time", "component", "event", "message"}, true},
+	}
+	for _, tt := range tests { //nolint:golint,paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			saved := Columns
+			defer func() { Columns = saved }()
+			if err := SetColumns(tt.list); (err != nil) != tt.wantErr {
+				t.Errorf("SetColumns() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(Columns, tt.want) {
+				t.Errorf("SetColumns() %s = %v, want %v", tt.name, Columns, tt.want)
+			}
+		})
+	}
+	if err := SetColumns("core"); err == nil || !strings.Contains(err.Error(), "no core ID") {
+		t.Errorf("SetColumns() core error = %v, want no core ID", err)
+	}
+}
+
+func TestOutput_printEventsColumns(t *testing.T) { //nolint:golint,paralleltest
+	var s10 = "../../testdata/test10.binary"
+
+	eds := make(map[uint16]scvd.Event)
+	eds[0xFE00] = scvd.Event{Brief: "STDIO", Property: "stdout", Level: "Op"}
+
+	tests := []struct {
+		name    string
+		columns []string
+		want    string
+	}{
+		{"level", []string{"index", "level", "component", "message"},
+			"   Detailed event list\n   -------------------\n\n" +
+				"Index Level  Component Value\n" +
+				"----- -----  --------- -----\n" +
+				"    0        0xFF      val1=0x00000004, val2=0x00000002\n" +
+				"    1 Op     STDIO     \"hello wo\"\n"},
+		{"raw", []string{"event", "raw", "time"},
+			"   Detailed event list\n   -------------------\n\n" +
+				"Event Property Raw Values Time (s)\n" +
+				"-------------- ---------- --------\n" +
+				"0xFF03         val1=0x00000004, val2=0x00000002 0.00000124\n" +
+				"stdout         data=0x68656c6c6f20776f 0.00000124\n"},
+	}
+	for _, tt := range tests { //nolint:golint,paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			saved := Columns
+			defer func() { Columns = saved }()
+			Columns = tt.columns
+			TimeFactor = nil
+			var b bytes.Buffer
+			out := bufio.NewWriter(&b)
+			var ib event.Binary
+			in := ib.Open(&s10)
+			defer ib.Close()
+			o := &Output{
+				columns:       []string{"Index", "Time (s)", "Component", "Event Property", "Value"},
+				componentSize: 9,
+				propertySize:  14,
+			}
+			err := o.printHeader(out)
+			if err == nil {
+				err = o.printEvents(out, in, eds, nil, nil, &EventsTable{})
+			}
+			if err != nil {
+				t.Errorf("Output.printEvents() %s error = %v", tt.name, err)
+			}
+			out.Flush()
+			if b.String() != tt.want {
+				t.Errorf("Output.printEvents() %s = %v, want %v", tt.name, b.String(), tt.want)
+			}
+		})
+	}
+}
+
 func TestOutput_printHeader(t *testing.T) { //nolint:golint,paralleltest
 	var b bytes.Buffer
 
@@ -945,6 +1174,7 @@ func TestOutput_print(t *testing.T) { //nolint:golint,paralleltest
 		eventFile     *string
 		evdefs        map[uint16]scvd.Event
 		typedefs      map[string]map[string]map[int16]string
+		memberTypes   map[string]map[string]string
 		statBegin     bool
 		showStatistic bool
 	}
@@ -975,7 +1205,7 @@ func TestOutput_print(t *testing.T) { //nolint:golint,paralleltest
 				componentSize: tt.fields.componentSize,
 				propertySize:  tt.fields.propertySize,
 			}
-			if err := o.print(tt.args.out, tt.args.eventFile, tt.args.evdefs, tt.args.typedefs, tt.args.statBegin, tt.args.showStatistic, &eventsTable); (err != nil) != tt.wantErr {
+			if err := o.print(tt.args.out, tt.args.eventFile, tt.args.evdefs, tt.args.typedefs, tt.args.memberTypes, tt.args.statBegin, tt.args.showStatistic, &eventsTable); (err != nil) != tt.wantErr {
 				t.Errorf("Output.print() error = %v, wantErr %v", err, tt.wantErr)
 			}
 			tt.args.out.Flush()
@@ -1015,6 +1245,7 @@ func TestPrint(t *testing.T) { //nolint:golint,paralleltest
 		eventFile     *string
 		evdefs        map[uint16]scvd.Event
 		typedefs      map[string]map[string]map[int16]string
+		memberTypes   map[string]map[string]string
 		statBegin     bool
 		showStatistic bool
 	}
@@ -1031,7 +1262,7 @@ func TestPrint(t *testing.T) { //nolint:golint,paralleltest
 		t.Run(tt.name, func(t *testing.T) {
 			TimeFactor = nil
 			defer os.Remove(*tt.args.filename)
-			if err := Print(tt.args.filename, &formatType, &level, tt.args.eventFile, tt.args.evdefs, tt.args.typedefs, tt.args.statBegin, tt.args.showStatistic); (err != nil) != tt.wantErr {
+			if err := Print(tt.args.filename, &formatType, &level, tt.args.eventFile, tt.args.evdefs, tt.args.typedefs, tt.args.memberTypes, tt.args.statBegin, tt.args.showStatistic); (err != nil) != tt.wantErr {
 				t.Errorf("Print() error = %v, wantErr %v", err, tt.wantErr)
 			}
 			file, err := os.Open(*tt.args.filename)
@@ -1066,6 +1297,55 @@ func TestPrint(t *testing.T) { //nolint:golint,paralleltest
 	}
 }
 
+func TestPrintStatsOnly(t *testing.T) { //nolint:golint,paralleltest
+	dir := t.TempDir()
+	s := filepath.Join(dir, "stats.binary")
+	w, err := event.Create(s)
+	if err != nil {
+		t.Fatalf("event.Create() error = %v", err)
+	}
+	for i := 0; i < 20 && err == nil; i++ {
+		err = w.EventRecord2(uint64(100*i), 0xEF00|uint16(i%2), int32(i), 0)
+		if err == nil {
+			err = w.EventRecord2(uint64(100*i+10), 0x0A01, int32(i), 0)
+		}
+		if err == nil {
+			err = w.EventRecord2(uint64(100*i+10*(i%7)+20), 0xEF20|uint16(i%2), int32(i), 1)
+		}
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		t.Fatalf("Writer.EventRecord2() error = %v", err)
+	}
+	evdefs := map[uint16]scvd.Event{
+		0xEF00: {Brief: "EvCtrl", Property: "StartA", Value: "start=%d[val1]"},
+		0xEF20: {Brief: "EvCtrl", Property: "StopA", Value: "stop=%d[val1] %x[val2]"},
+	}
+	print := func(formatType string, statsOnly bool) string {
+		defer func() { StatsOnly = false }()
+		StatsOnly = statsOnly
+		TimeFactor = nil
+		out := filepath.Join(dir, "stats.out")
+		level := ""
+		if err := Print(&out, &formatType, &level, &s, evdefs, nil, nil, false, true); err != nil {
+			t.Errorf("Print() error = %v", err)
+		}
+		data, _ := os.ReadFile(out)
+		return string(data)
+	}
+	for _, formatType := range []string{"txt", "json"} {
+		want := print(formatType, false)
+		if !strings.Contains(want, "start=") || !strings.Contains(want, "stop=") {
+			t.Errorf("Print() %s = %v, want formatted texts", formatType, want)
+		}
+		if got := print(formatType, true); got != want {
+			t.Errorf("Print() %s stats only = %v, want %v", formatType, got, want)
+		}
+	}
+}
+
 func TestPrintJSON(t *testing.T) { //nolint:golint,paralleltest
 	o1 := "testOutput.json"
 
@@ -1080,6 +1360,7 @@ func TestPrintJSON(t *testing.T) { //nolint:golint,paralleltest
 		eventFile     *string
 		evdefs        map[uint16]scvd.Event
 		typedefs      map[string]map[string]map[int16]string
+		memberTypes   map[string]map[string]string
 		statBegin     bool
 		showStatistic bool
 	}
@@ -1096,7 +1377,7 @@ func TestPrintJSON(t *testing.T) { //nolint:golint,paralleltest
 		t.Run(tt.name, func(t *testing.T) {
 			TimeFactor = nil
 			defer os.Remove(*tt.args.filename)
-			if err := Print(tt.args.filename, &formatType, &level, tt.args.eventFile, tt.args.evdefs, tt.args.typedefs, tt.args.statBegin, tt.args.showStatistic); (err != nil) != tt.wantErr {
+			if err := Print(tt.args.filename, &formatType, &level, tt.args.eventFile, tt.args.evdefs, tt.args.typedefs, tt.args.memberTypes, tt.args.statBegin, tt.args.showStatistic); (err != nil) != tt.wantErr {
 				t.Errorf("Print() error = %v, wantErr %v", err, tt.wantErr)
 			}
 			file, err := os.Open(*tt.args.filename)
@@ -1141,6 +1422,7 @@ func TestPrintXML(t *testing.T) { //nolint:golint,paralleltest
 		eventFile     *string
 		evdefs        map[uint16]scvd.Event
 		typedefs      map[string]map[string]map[int16]string
+		memberTypes   map[string]map[string]string
 		statBegin     bool
 		showStatistic bool
 	}
@@ -1157,7 +1439,7 @@ func TestPrintXML(t *testing.T) { //nolint:golint,paralleltest
 		t.Run(tt.name, func(t *testing.T) {
 			TimeFactor = nil
 			defer os.Remove(*tt.args.filename)
-			if err := Print(tt.args.filename, &formatType, &level, tt.args.eventFile, tt.args.evdefs, tt.args.typedefs, tt.args.statBegin, tt.args.showStatistic); (err != nil) != tt.wantErr {
+			if err := Print(tt.args.filename, &formatType, &level, tt.args.eventFile, tt.args.evdefs, tt.args.typedefs, tt.args.memberTypes, tt.args.statBegin, tt.args.showStatistic); (err != nil) != tt.wantErr {
 				t.Errorf("Print() error = %v, wantErr %v", err, tt.wantErr)
 			}
 			file, err := os.Open(*tt.args.filename)
@@ -1187,3 +1469,1107 @@ func TestPrintXML(t *testing.T) { //nolint:golint,paralleltest
 		})
 	}
 }
+
+func TestPrintHTML(t *testing.T) { //nolint:golint,paralleltest
+	o1 := "testOutput.html"
+
+	var s10 = "../../testdata/test10.binary"
+
+	spec, err := dashboard.Load("../../testdata/dashboard.yaml")
+	if err != nil {
+		t.Fatalf("dashboard.Load() error = %v", err)
+	}
+
+	formatType := "html"
+	level := ""
+	tests := []struct {
+		name      string
+		dashboard *dashboard.Spec
+		want      []string
+	}{
+		{"default", nil, []string{"<title>Event Recorder report</title>", "<h2>Errors</h2>", "<td>hello wo</td>"}},
+		{"dashboard", spec, []string{"<title>Test report</title>", "<p>1 events</p>", "<td>0xFF03</td>"}},
+	}
+	for _, tt := range tests { //nolint:golint,paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			TimeFactor = nil
+			Dashboard = tt.dashboard
+			defer func() {
+				Dashboard = nil
+				FormatType = "txt"
+			}()
+			defer os.Remove(o1)
+			if err := Print(&o1, &formatType, &level, &s10, nil, nil, nil, false, false); err != nil {
+				t.Errorf("Print() error = %v", err)
+			}
+			buf, err := os.ReadFile(o1)
+			if err != nil {
+				t.Errorf("Print() error = %v, output file not created", err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(string(buf), w) {
+					t.Errorf("Print() %s = %v, want %v", tt.name, string(buf), w)
+				}
+			}
+		})
+	}
+}
+
+func Test_timer_time(t *testing.T) { //nolint:golint,paralleltest
+	tests := []struct {
+		name   string
+		events []event.Data
+		want   float64
+	}{
+		{"default", []event.Data{{Time: 25_000_000}}, 1.0},
+		{"init", []event.Data{{Time: 1000, Info: event.Info{ID: 0xFF00}, Value2: 1000}, {Time: 3000}}, 2.00004},
+		{"clock", []event.Data{{Time: 1000, Info: event.Info{ID: 0xFF00}, Value2: 1000}, {Time: 3000, Info: event.Info{ID: 0xFF03}, Value1: 100}, {Time: 3050}}, 2.5},
+	}
+	for _, tt := range tests { //nolint:golint,paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			TimeFactor = nil
+			defer func() { TimeFactor = nil }()
+			var tm timer
+			var got float64
+			for i := range tt.events {
+				got = tm.time(&tt.events[i])
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("timer.time() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLive(t *testing.T) { //nolint:golint,paralleltest
+	o1 := "testOutput.txt"
+
+	data, err := os.ReadFile("../../testdata/test10.binary")
+	if err != nil {
+		t.Fatalf("os.ReadFile() error = %v", err)
+	}
+	want := "   Detailed event list\n" +
+		"   -------------------\n" +
+		"\n" +
+		"Index Time (s)   Component Event Property Value\n" +
+		"----- --------   --------- -------------- -----\n" +
+		"    0 0.00000124 0xFF      0xFF03         val1=0x00000004, val2=0x00000002\n" +
+		"    1 0.00000124 0xFE      0xFE00         \"hello wo\"\n" +
+		"\n" +
+		"   Start/Stop event statistic\n" +
+		"   --------------------------\n" +
+		"\n" +
+		"Event count      total       min         max         average     first       last\n" +
+		"----- -----      -----       ---         ---         -------     -----       ----\n" +
+		"\n" +
+		"2 events\n"
+
+	tests := []struct {
+		name    string
+		in      io.Reader
+		want    string
+		wantErr bool
+	}{
+		{"events", bytes.NewReader(data), want, false},
+		{"broken", bytes.NewReader(data[:30]), "", true},
+	}
+	for _, tt := range tests { //nolint:golint,paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			TimeFactor = nil
+			a := &testAnalyzer{}
+			Analyzers = []bus.Analyzer{a}
+			defer func() { Analyzers = nil }()
+			defer os.Remove(o1)
+			err := Live(&o1, tt.in, nil, nil, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Live() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if !a.ended {
+				t.Errorf("Live() %s analyzer not ended", tt.name)
+			}
+			buf, _ := os.ReadFile(o1)
+			if tt.want != "" && string(buf) != tt.want {
+				t.Errorf("Live() %s = %v, want %v", tt.name, string(buf), tt.want)
+			}
+		})
+	}
+}
+
+func TestLive_rotate(t *testing.T) { //nolint:golint,paralleltest
+	data, err := os.ReadFile("../../testdata/test10.binary")
+	if err != nil {
+		t.Fatalf("os.ReadFile() error = %v", err)
+	}
+	Rotate = rotate.Options{Size: 200}
+	defer func() {
+		Rotate = rotate.Options{}
+		TimeFactor = nil
+	}()
+	dir := t.TempDir()
+	o1 := filepath.Join(dir, "live.txt")
+	if err := Live(&o1, bytes.NewReader(data), nil, nil, nil); err != nil {
+		t.Fatalf("Live() error = %v", err)
+	}
+	entries, _ := os.ReadDir(dir)
+	var size int
+	for _, e := range entries {
+		buf, _ := os.ReadFile(filepath.Join(dir, e.Name()))
+		if len(buf) == 0 || buf[len(buf)-1] != '\n' {
+			t.Errorf("Live() %s = %q, want complete lines", e.Name(), buf)
+		}
+		size += len(buf)
+	}
+	if len(entries) < 2 || size != 497 {
+		t.Errorf("Live() = %d files of %d bytes, want rotated files of 497 bytes", len(entries), size)
+	}
+}
+
+func TestOutput_printEventsThreads(t *testing.T) { //nolint:golint,paralleltest
+	var s = "../../testdata/rtx.binary"
+
+	elfFile := "../../testdata/elftest.elf"
+	if err := elf.Sections.Readelf(&elfFile); err != nil {
+		t.Fatalf("elf.Sections.Readelf() error = %v", err)
+	}
+	scvdFiles := []string{"../../testdata/rtx.xml"}
+	evdefs := make(map[uint16]scvd.Event)
+	if err := scvd.Get(&scvdFiles, evdefs, make(map[string]map[string]map[int16]string), make(map[string]map[string]string)); err != nil {
+		t.Fatalf("scvd.Get() error = %v", err)
+	}
+
+	want := "    0            ThreadCreated\n" +
+		"    1 def        ThreadSwitched\n" +
+		"    2 def        Work\n" +
+		"    3 0x20000200 ThreadSwitched\n" +
+		"    4 0x20000200 Work\n"
+	wantThreads := []string{"", "def", "def", "0x20000200", "0x20000200"}
+
+	saved := Columns
+	defer func() { Columns = saved }()
+	Columns = []string{"index", "thread", "event"}
+	TimeFactor = nil
+	o := &Output{columns: []string{"Index", "Time (s)", "Component", "Event Property", "Value"}}
+	var ib event.Binary
+	o.buildStatistic(ib.Open(&s), evdefs, nil, nil)
+	ib.Close()
+	if o.threadSize != 10 {
+		t.Errorf("Output.buildStatistic() threadSize = %v, want %v", o.threadSize, 10)
+	}
+	var b bytes.Buffer
+	out := bufio.NewWriter(&b)
+	var eventsTable EventsTable
+	err := o.printEvents(out, ib.Open(&s), evdefs, nil, nil, &eventsTable)
+	ib.Close()
+	if err != nil {
+		t.Errorf("Output.printEvents() error = %v", err)
+	}
+	out.Flush()
+	if b.String() != want {
+		t.Errorf("Output.printEvents() = %v, want %v", b.String(), want)
+	}
+	for i, rec := range eventsTable.Events {
+		if i < len(wantThreads) && rec.Thread != wantThreads[i] {
+			t.Errorf("Output.printEvents() %d thread = %v, want %v", i, rec.Thread, wantThreads[i])
+		}
+	}
+
+	Columns = nil // the thread is not exported without thread column
+	eventsTable = EventsTable{}
+	err = o.printEvents(bufio.NewWriter(io.Discard), ib.Open(&s), evdefs, nil, nil, &eventsTable)
+	ib.Close()
+	if err != nil || len(eventsTable.Events) != len(wantThreads) {
+		t.Errorf("Output.printEvents() = %d events, %v, want %d", len(eventsTable.Events), err, len(wantThreads))
+	}
+	for i, rec := range eventsTable.Events {
+		if rec.Thread != "" {
+			t.Errorf("Output.printEvents() without thread column %d thread = %v, want none", i, rec.Thread)
+		}
+	}
+}
+
+func Test_nesting_depth(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		ids  []uint16
+		want []int
+		max  int
+	}{
+		{"flat", []uint16{0x1000, 0x1001}, []int{0, 0}, 0},
+		{"pair", []uint16{0xEF00, 0x1000, 0xEF20, 0x1000}, []int{0, 1, 0, 0}, 1},
+		{"nested", []uint16{0xEF00, 0xEF41, 0x1000, 0xEF61, 0xEF20}, []int{0, 1, 2, 1, 0}, 2},
+		{"unclosed", []uint16{0xEF00, 0xEF01, 0x1000, 0xEF20, 0x1000}, []int{0, 1, 2, 0, 0}, 2},
+		{"stop only", []uint16{0xEF20, 0x1000}, []int{0, 0}, 0},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var n nesting
+			var got []int
+			for _, id := range tt.ids {
+				got = append(got, n.depth(&event.Data{Info: event.Info{ID: id}}))
+			}
+			if !reflect.DeepEqual(got, tt.want) || n.max != tt.max {
+				t.Errorf("nesting.depth() %s = %v, %d, want %v, %d", tt.name, got, n.max, tt.want, tt.max)
+			}
+		})
+	}
+}
+
+func Test_pairTime_add(t *testing.T) {
+	t.Parallel()
+
+	type ev struct {
+		id   uint16
+		time float64
+	}
+	tests := []struct {
+		name   string
+		events []ev
+		want   []float64
+	}{
+		{"no pair", []ev{{0x1000, 1}, {0x1001, 2}}, []float64{0, 0}},
+		{"pairs", []ev{{0xEF00, 1}, {0x1000, 1.5}, {0xEF20, 2}, {0xEF00, 3}, {0xEF20, 3.5}},
+			[]float64{0, 0, 1, 1, 1.5}},
+		{"nested", []ev{{0xEF00, 1}, {0xEF01, 1.5}, {0xEF21, 2}, {0xEF20, 3}, {0xEF01, 4}, {0xEF21, 5}},
+			[]float64{0, 0.5, 1, 2, 2, 3}},
+		{"groups", []ev{{0xEF00, 1}, {0xEF40, 2}, {0xEF20, 3}, {0xEF60, 5}}, []float64{0, 0, 2, 3}},
+		{"stop only", []ev{{0xEF20, 1}, {0xEF00, 2}, {0xEF00, 3}}, []float64{0, 0, 1}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var p pairTime
+			var got []float64
+			for _, e := range tt.events {
+				got = append(got, p.add(&event.Data{Info: event.Info{ID: e.id}}, e.time))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pairTime.add() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutput_printEventsCumulative(t *testing.T) { //nolint:golint,paralleltest
+	var s = "../../testdata/tree.binary"
+
+	want := "    2 0xEF01         0.00000800\n" +
+		"    3 0x1000         \n" +
+		"    4 0xEF21         0.00001600\n" +
+		"    5 0xEF20         0.00002000\n" +
+		"    6 0x1000         \n" +
+		"    7 0xEF40         0.00000000\n"
+
+	saved := Columns
+	defer func() { Columns = saved }()
+	Columns = []string{"index", "event", "cumulative"}
+	TimeFactor = nil
+	o := &Output{columns: []string{"Index", "Time (s)", "Component", "Event Property", "Value"}}
+	var ib event.Binary
+	o.buildStatistic(ib.Open(&s), nil, nil, nil)
+	ib.Close()
+	o.first = 2
+	var b bytes.Buffer
+	out := bufio.NewWriter(&b)
+	err := o.printEvents(out, ib.Open(&s), nil, nil, nil, nil)
+	ib.Close()
+	if err != nil {
+		t.Errorf("Output.printEvents() error = %v", err)
+	}
+	out.Flush()
+	if b.String() != want {
+		t.Errorf("Output.printEvents() = %q, want %q", b.String(), want)
+	}
+}
+
+func TestOutput_printEventsAnnotations(t *testing.T) { //nolint:golint,paralleltest
+	var s = "../../testdata/test.binary"
+
+	want := "      # firmware v2 flashed here\n" +
+		"    0 0xF000\n" +
+		"    1 0xFF00\n" +
+		"      # reset button pressed\n" +
+		"    2 0xFE00\n" +
+		"    3 0xFE00\n"
+
+	list, err := annotate.Load("../../testdata/annotations.yaml")
+	if err != nil {
+		t.Fatalf("annotate.Load() error = %v", err)
+	}
+	saved := Columns
+	defer func() { Columns, Annotations = saved, nil }()
+	Columns = []string{"index", "event"}
+	Annotations = list
+	TimeFactor = nil
+	o := &Output{columns: []string{"Index", "Time (s)", "Component", "Event Property", "Value"}}
+	var ib event.Binary
+	o.buildStatistic(ib.Open(&s), nil, nil, nil)
+	ib.Close()
+	for run := 0; run < 2; run++ { // the annotations start again with every run
+		var b bytes.Buffer
+		out := bufio.NewWriter(&b)
+		var eventsTable EventsTable
+		err = o.printEvents(out, ib.Open(&s), nil, nil, nil, &eventsTable)
+		ib.Close()
+		if err != nil {
+			t.Errorf("Output.printEvents() error = %v", err)
+		}
+		out.Flush()
+		if b.String() != want {
+			t.Errorf("Output.printEvents() = %q, want %q", b.String(), want)
+		}
+		if len(eventsTable.Events) != 4 || eventsTable.Events[2].Annotation != "reset button pressed" {
+			t.Errorf("Output.printEvents() events = %v", eventsTable.Events)
+		}
+	}
+}
+
+func TestOutput_printEventsTree(t *testing.T) { //nolint:golint,paralleltest
+	var s = "../../testdata/tree.binary"
+
+	want := "    0 0xEF          0xEF00\n" +
+		"    1   0x10        0x1000\n" +
+		"    2   0xEF        0xEF01\n" +
+		"    3     0x10      0x1000\n" +
+		"    4   0xEF        0xEF21\n" +
+		"    5 0xEF          0xEF20\n" +
+		"    6 0x10          0x1000\n" +
+		"    7 0xEF          0xEF40\n"
+
+	saved := Columns
+	defer func() {
+		Columns = saved
+		Tree = false
+	}()
+	Columns = []string{"index", "component", "event"}
+	Tree = true
+	TimeFactor = nil
+	o := &Output{columns: []string{"Index", "Time (s)", "Component", "Event Property", "Value"}}
+	var ib event.Binary
+	o.buildStatistic(ib.Open(&s), nil, nil, nil)
+	ib.Close()
+	var b bytes.Buffer
+	out := bufio.NewWriter(&b)
+	err := o.printEvents(out, ib.Open(&s), nil, nil, nil, &EventsTable{})
+	ib.Close()
+	if err != nil {
+		t.Errorf("Output.printEvents() error = %v", err)
+	}
+	out.Flush()
+	if b.String() != want {
+		t.Errorf("Output.printEvents() = %v, want %v", b.String(), want)
+	}
+}
+
+func TestEventRecord_same(t *testing.T) {
+	t.Parallel()
+
+	rec := EventRecord{Index: 1, Time: 1.0, Component: "C", EventProperty: "P", Value: "v", raw: "r"}
+	tests := []struct {
+		name  string
+		other EventRecord
+		want  bool
+	}{
+		{"same", EventRecord{Index: 2, Time: 2.0, Component: "C", EventProperty: "P", Value: "v", raw: "r"}, true},
+		{"component", EventRecord{Component: "D", EventProperty: "P", Value: "v", raw: "r"}, false},
+		{"property", EventRecord{Component: "C", EventProperty: "Q", Value: "v", raw: "r"}, false},
+		{"value", EventRecord{Component: "C", EventProperty: "P", Value: "w", raw: "r"}, false},
+		{"raw", EventRecord{Component: "C", EventProperty: "P", Value: "v", raw: "s"}, false},
+		{"thread", EventRecord{Component: "C", EventProperty: "P", Value: "v", raw: "r", Thread: "T"}, false},
+		{"depth", EventRecord{Component: "C", EventProperty: "P", Value: "v", raw: "r", depth: 1}, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := rec.same(&tt.other); got != tt.want {
+				t.Errorf("EventRecord.same() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutput_printEventsSquash(t *testing.T) { //nolint:golint,paralleltest
+	var s = "../../testdata/squash.binary"
+
+	want := "    0 0x1000 val1=0x00000001, val2=0x00000002 (3 times, last 0.00001200)\n" +
+		"    3 0x1000 val1=0x00000001, val2=0x00000003\n" +
+		"    4 0x1001 val1=0x00000001, val2=0x00000003 (2 times, last 0.00002400)\n" +
+		"    6 0x1000 val1=0x00000001, val2=0x00000002\n"
+
+	saved := Columns
+	defer func() {
+		Columns = saved
+		Squash = false
+	}()
+	Columns = []string{"index", "event", "message"}
+	Squash = true
+	TimeFactor = nil
+	o := &Output{columns: []string{"Index", "Time (s)", "Component", "Event Property", "Value"}, propertySize: 6}
+	var ib event.Binary
+	var b bytes.Buffer
+	out := bufio.NewWriter(&b)
+	var table EventsTable
+	err := o.printEvents(out, ib.Open(&s), nil, nil, nil, &table)
+	ib.Close()
+	if err != nil {
+		t.Errorf("Output.printEvents() error = %v", err)
+	}
+	out.Flush()
+	if b.String() != want {
+		t.Errorf("Output.printEvents() = %v, want %v", b.String(), want)
+	}
+	repeats := []int{}
+	for _, rec := range table.Events {
+		repeats = append(repeats, rec.Repeat)
+	}
+	if !reflect.DeepEqual(repeats, []int{3, 0, 2, 0}) {
+		t.Errorf("Output.printEvents() repeats = %v, want %v", repeats, []int{3, 0, 2, 0})
+	}
+}
+
+func TestSetSort(t *testing.T) { //nolint:golint,paralleltest
+	saved := Sort
+	defer func() { Sort = saved }()
+
+	tests := []struct {
+		name    string
+		key     string
+		wantErr bool
+	}{
+		{"time", "time", false},
+		{"index", "index", false},
+		{"component", "component", false},
+		{"duration", "duration", false},
+		{"unknown", "level", true},
+	}
+	for _, tt := range tests {
+		Sort = ""
+		err := SetSort(tt.key)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("SetSort() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if !tt.wantErr && Sort != tt.key {
+			t.Errorf("SetSort() %s = %v, want %v", tt.name, Sort, tt.key)
+		}
+	}
+}
+
+func Test_setDurations(t *testing.T) {
+	t.Parallel()
+
+	records := []EventRecord{
+		{id: 0xEF00, Time: 1.0},
+		{id: 0xEF01, Time: 2.0},
+		{id: 0x1000, Time: 3.0},
+		{id: 0xEF21, Time: 3.5},
+		{id: 0xEF20, Time: 5.0},
+		{id: 0xEF02, Time: 6.0},
+		{id: 0xEF22, Time: 6.0},
+		{id: 0xEF23, Time: 7.0},
+	}
+	want := []float64{4.0, 1.5, 0, 1.5, 4.0, 0, 0, 0}
+	setDurations(records)
+	var got []float64
+	for _, rec := range records {
+		got = append(got, rec.duration)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("setDurations() = %v, want %v", got, want)
+	}
+}
+
+func TestOutput_printEventsSort(t *testing.T) { //nolint:golint,paralleltest
+	var s = "../../testdata/tree.binary"
+
+	saved := Columns
+	defer func() {
+		Columns = saved
+		Sort = ""
+		Reverse = false
+	}()
+	Columns = []string{"index"}
+	TimeFactor = nil
+
+	tests := []struct {
+		name    string
+		sort    string
+		reverse bool
+		want    string
+	}{
+		{"none", "", false, "0 1 2 3 4 5 6 7 "},
+		{"reverse", "", true, "7 6 5 4 3 2 1 0 "},
+		{"time", "time", false, "0 1 2 3 4 5 6 7 "},
+		{"component", "component", false, "1 3 6 0 2 4 5 7 "},
+		{"duration", "duration", true, "5 0 4 2 7 6 3 1 "},
+	}
+	for _, tt := range tests {
+		Sort = tt.sort
+		Reverse = tt.reverse
+		o := &Output{columns: []string{"Index", "Time (s)", "Component", "Event Property", "Value"}}
+		var ib event.Binary
+		var b bytes.Buffer
+		out := bufio.NewWriter(&b)
+		err := o.printEvents(out, ib.Open(&s), nil, nil, nil, &EventsTable{})
+		ib.Close()
+		if err != nil {
+			t.Errorf("Output.printEvents() %s error = %v", tt.name, err)
+		}
+		out.Flush()
+		if got := strings.Join(strings.Fields(b.String()), " ") + " "; got != tt.want {
+			t.Errorf("Output.printEvents() %s = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestOutput_window(t *testing.T) { //nolint:golint,paralleltest
+	defer func() {
+		Skip, Limit, Tail = 0, 0, 0
+	}()
+
+	tests := []struct {
+		name              string
+		skip, limit, tail int
+		count             int
+		first, end        int
+	}{
+		{"all", 0, 0, 0, 10, 0, 0},
+		{"head", 0, 3, 0, 10, 0, 3},
+		{"skip", 4, 0, 0, 10, 4, 0},
+		{"skip limit", 4, 3, 0, 10, 4, 7},
+		{"tail", 0, 0, 3, 10, 7, 0},
+		{"tail long", 0, 0, 30, 10, 0, 0},
+	}
+	for _, tt := range tests {
+		Skip, Limit, Tail = tt.skip, tt.limit, tt.tail
+		var o Output
+		o.window(tt.count)
+		if o.first != tt.first || o.end != tt.end {
+			t.Errorf("Output.window() %s = %d, %d, want %d, %d", tt.name, o.first, o.end, tt.first, tt.end)
+		}
+	}
+}
+
+func Test_stopAt(t *testing.T) { //nolint:golint,paralleltest
+	defer func() {
+		Skip, Limit, Tail = 0, 0, 0
+		Analyzers = nil
+	}()
+
+	tests := []struct {
+		name              string
+		skip, limit, tail int
+		statistic         bool
+		analyzers         []bus.Analyzer
+		want              int
+	}{
+		{"all", 0, 0, 0, false, nil, 0},
+		{"head", 0, 3, 0, false, nil, 3},
+		{"skip limit", 4, 3, 0, false, nil, 7},
+		{"skip", 4, 0, 0, false, nil, 0},
+		{"tail", 0, 0, 3, false, nil, 0},
+		{"statistic", 0, 3, 0, true, nil, 0},
+		{"analyzer", 0, 3, 0, false, []bus.Analyzer{&testAnalyzer{}}, 0},
+	}
+	for _, tt := range tests {
+		Skip, Limit, Tail = tt.skip, tt.limit, tt.tail
+		Analyzers = tt.analyzers
+		if got := stopAt(tt.statistic); got != tt.want {
+			t.Errorf("stopAt() %s = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestOutput_buildStatisticStop(t *testing.T) { //nolint:golint,paralleltest
+	var s = "../../testdata/tree.binary"
+
+	TimeFactor = nil
+	o := &Output{columns: []string{"Index", "Time (s)", "Component", "Event Property", "Value"}, stop: 2}
+	var b event.Binary
+	in := b.Open(&s)
+	defer b.Close()
+	if got := o.buildStatistic(in, nil, nil, nil); got != 2 {
+		t.Errorf("Output.buildStatistic() = %v, want 2", got)
+	}
+	var ev event.Data
+	if err := ev.Read(in); err != nil {
+		t.Errorf("Output.buildStatistic() read all events, want the events after index 2 left, error = %v", err)
+	}
+}
+
+func TestOutput_printEventsWindow(t *testing.T) { //nolint:golint,paralleltest
+	var s = "../../testdata/tree.binary"
+
+	saved := Columns
+	defer func() {
+		Columns = saved
+		Tree = false
+	}()
+	TimeFactor = nil
+
+	tests := []struct {
+		name       string
+		columns    []string
+		tree       bool
+		first, end int
+		want       string
+	}{
+		{"all", []string{"index"}, false, 0, 0, "0 1 2 3 4 5 6 7 "},
+		{"head", []string{"index"}, false, 0, 2, "0 1 "},
+		{"skip", []string{"index"}, false, 6, 0, "6 7 "},
+		{"window", []string{"index", "time"}, false, 3, 5, "3 0.00001600 4 0.00002000 "},
+		{"tree", []string{"index", "component"}, true, 3, 5, "3 0x10 4 0xEF "},
+		{"behind", []string{"index"}, false, 10, 12, " "},
+	}
+	for _, tt := range tests {
+		Columns = tt.columns
+		Tree = tt.tree
+		o := &Output{columns: []string{"Index", "Time (s)", "Component", "Event Property", "Value"}, first: tt.first, end: tt.end}
+		var ib event.Binary
+		var b bytes.Buffer
+		out := bufio.NewWriter(&b)
+		table := EventsTable{}
+		err := o.printEvents(out, ib.Open(&s), nil, nil, nil, &table)
+		ib.Close()
+		if err != nil {
+			t.Errorf("Output.printEvents() %s error = %v", tt.name, err)
+		}
+		out.Flush()
+		if got := strings.Join(strings.Fields(b.String()), " ") + " "; got != tt.want {
+			t.Errorf("Output.printEvents() %s = %v, want %v", tt.name, got, tt.want)
+		}
+		if tt.tree && !strings.Contains(b.String(), "    0x10") {
+			t.Errorf("Output.printEvents() %s = %q, want indented component", tt.name, b.String())
+		}
+	}
+}
+
+func TestSetColorMode(t *testing.T) { //nolint:golint,paralleltest
+	defer func() { ColorMode = "auto" }()
+
+	for _, mode := range []string{"auto", "always", "never"} {
+		if err := SetColorMode(mode); err != nil || ColorMode != mode {
+			t.Errorf("SetColorMode() %s = %v, %v, want %v", mode, ColorMode, err, mode)
+		}
+	}
+	if err := SetColorMode("red"); !errors.Is(err, errColor) {
+		t.Errorf("SetColorMode() red error = %v, want %v", err, errColor)
+	}
+}
+
+func Test_useColor(t *testing.T) { //nolint:golint,paralleltest
+	defer func() {
+		ColorMode = "auto"
+		FormatType = "txt"
+	}()
+	file, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	if err != nil {
+		t.Errorf("os.Create() error = %v", err)
+		return
+	}
+	defer file.Close()
+
+	tests := []struct {
+		name    string
+		mode    string
+		format  string
+		noColor string
+		want    bool
+	}{
+		{"always", "always", "txt", "", true},
+		{"always no color", "always", "txt", "1", true},
+		{"never", "never", "txt", "", false},
+		{"auto file", "auto", "txt", "", false},
+		{"auto json", "auto", "json", "", false},
+		{"auto no color", "auto", "txt", "1", false},
+	}
+	for _, tt := range tests {
+		ColorMode = tt.mode
+		FormatType = tt.format
+		t.Setenv("NO_COLOR", tt.noColor)
+		if got := useColor(file); got != tt.want {
+			t.Errorf("useColor() %s = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func Test_colorize(t *testing.T) {
+	t.Parallel()
+
+	rec := EventRecord{Component: "App", level: "Error"}
+	plain := EventRecord{Component: "App", level: "Op"}
+	tests := []struct {
+		name   string
+		column string
+		rec    *EventRecord
+		want   string
+	}{
+		{"error event", "event", &rec, "\x1b[1;31mFail\x1b[0m"},
+		{"error message", "message", &rec, "\x1b[1;31mFail\x1b[0m"},
+		{"op event", "event", &plain, "Fail"},
+		{"index", "index", &rec, "Fail"},
+		{"component", "component", &rec, "\x1b[96mFail\x1b[0m"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := colorize(tt.column, tt.rec, "Fail"); got != tt.want {
+				t.Errorf("colorize() %s = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_appendPadded(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		text  string
+		width int
+	}{
+		{"right", "12", 5},
+		{"left", "App", -6},
+		{"none", "Value", 0},
+		{"longer", "Component", 4},
+		{"runes", "Grüße", -8},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			want := fmt.Sprintf("%*s", tt.width, tt.text)
+			if got := string(appendPadded([]byte("x"), []byte(tt.text), tt.width)); got != "x"+want {
+				t.Errorf("appendPadded() %s = %q, want %q", tt.name, got, "x"+want)
+			}
+		})
+	}
+}
+
+func TestEventRecord_appendColumn(t *testing.T) {
+	t.Parallel()
+
+	rec := EventRecord{Index: 42, Time: 1.5, Component: "Net", EventProperty: "Send", Value: "hi",
+		Thread: "main", level: "Op", raw: "val1=0x00000001", depth: 2}
+	quoted := EventRecord{Value: "hi", quoted: true}
+	pair := EventRecord{id: 0xEF20, cumulative: 0.75}
+	tests := []struct {
+		name string
+		rec  *EventRecord
+		want string
+	}{
+		{"index", &rec, "42"},
+		{"time", &rec, "1.50000000"},
+		{"component", &rec, "    Net"},
+		{"event", &rec, "Send"},
+		{"level", &rec, "Op"},
+		{"thread", &rec, "main"},
+		{"raw", &rec, "val1=0x00000001"},
+		{"cumulative", &rec, ""},
+		{"cumulative", &pair, "0.75000000"},
+		{"message", &rec, "hi"},
+		{"message", &quoted, "\"hi\""},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := string(tt.rec.appendColumn(nil, tt.name)); got != tt.want {
+				t.Errorf("EventRecord.appendColumn() %s = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutput_printEventAllocs(t *testing.T) { //nolint:golint,paralleltest
+	saved := Columns
+	defer func() { Columns = saved }()
+	Columns = []string{"index", "time", "component", "event", "message"}
+	o := &Output{componentSize: 10, propertySize: 14}
+	rec := EventRecord{Index: 7, Time: 0.25, Component: "Net", EventProperty: "Send", Value: "len=4"}
+	out := bufio.NewWriter(io.Discard)
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = o.printEvent(out, &rec)
+	})
+	if allocs != 0 {
+		t.Errorf("Output.printEvent() allocs = %v, want %v", allocs, 0)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile("../../testdata/test10.binary")
+	if err != nil {
+		t.Fatalf("os.ReadFile() error = %v", err)
+	}
+	evdefs := map[uint16]scvd.Event{0xFF03: {Brief: "EvCtrl", Property: "Clock", Level: "Detail", Value: "%x[val9"}}
+
+	var got []string
+	err = Decode(bytes.NewReader(data), evdefs, nil, nil, func(rec *EventRecord, ev *event.Data) error {
+		got = append(got, fmt.Sprintf("%d %04X %s %s %s|%s", rec.Index, ev.Info.ID, rec.Level(), rec.Component, rec.Value, rec.Raw()))
+		return nil
+	})
+	want := []string{
+		"0 FF03 Detail EvCtrl syntax error|val1=0x00000004, val2=0x00000002",
+		"1 FE00  0xFE hello wo|data=0x68656c6c6f20776f",
+	}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Decode() = %q, %v, want %q", got, err, want)
+	}
+
+	errStop := errors.New("stop")
+	if err = Decode(bytes.NewReader(data), nil, nil, nil, func(*EventRecord, *event.Data) error { return errStop }); err != errStop {
+		t.Errorf("Decode() error = %v, want %v", err, errStop)
+	}
+	if err = Decode(bytes.NewReader(data[:22]), nil, nil, nil, func(*EventRecord, *event.Data) error { return nil }); err == nil {
+		t.Errorf("Decode() error = nil, want error")
+	}
+}
+
+func TestPrintTrace(t *testing.T) { //nolint:golint,paralleltest
+	Trace = selftrace.New()
+	defer func() {
+		Trace = nil
+		TimeFactor = nil
+	}()
+	filename := filepath.Join(t.TempDir(), "out.txt")
+	eventFile := "../../testdata/test10.binary"
+	formatType := "txt"
+	level := ""
+	if err := Print(&filename, &formatType, &level, &eventFile, nil, nil, nil, false, false); err != nil {
+		t.Fatalf("Print() error = %v", err)
+	}
+	calls := make(map[string]any)
+	for _, ev := range Trace.Events() {
+		if ev.Ph == "X" {
+			calls[ev.Name] = ev.Args["calls"]
+		}
+	}
+	want := map[string]any{
+		"statistic pass": nil, "event pass": nil, "reports": nil, "encode txt": nil,
+		"statistic read": 2, "statistic analyze": 2, "read": 3, "decode": 2, "write": 2,
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Print() trace = %v, want %v", calls, want)
+	}
+}
+
+func TestOutput_printEventsWhere(t *testing.T) { //nolint:golint,paralleltest
+	var s = "../../testdata/squash.binary"
+
+	want := "    4 0x1001 val1=0x00000001, val2=0x00000003\n" +
+		"    5 0x1001 val1=0x00000001, val2=0x00000003\n"
+
+	saved := Columns
+	defer func() {
+		Columns = saved
+		Where = nil
+	}()
+	Columns = []string{"index", "event", "message"}
+	var err error
+	if Where, err = where.Parse([]string{"id=0x1001"}); err != nil {
+		t.Fatalf("where.Parse() error = %v", err)
+	}
+	TimeFactor = nil
+	o := &Output{columns: []string{"Index", "Time (s)", "Component", "Event Property", "Value"}, propertySize: 6}
+	var ib event.Binary
+	var b bytes.Buffer
+	out := bufio.NewWriter(&b)
+	var table EventsTable
+	err = o.printEvents(out, ib.Open(&s), nil, nil, nil, &table)
+	ib.Close()
+	if err != nil {
+		t.Errorf("Output.printEvents() error = %v", err)
+	}
+	out.Flush()
+	if b.String() != want {
+		t.Errorf("Output.printEvents() = %v, want %v", b.String(), want)
+	}
+}
+
+// decoder of the tests, the value is the first value of the event
+type testDecoder struct{}
+
+func (testDecoder) Decode(ev *event.Data) (plugin.Result, error) {
+	return plugin.Result{Component: "Vendor", Property: "Tick", Value: fmt.Sprint(ev.Value1), Level: "Op"}, nil
+}
+
+func TestOutput_printEventsDecoders(t *testing.T) { //nolint:golint,paralleltest
+	var s = "../../testdata/squash.binary"
+
+	want := "    4 Vendor Tick  1\n" +
+		"    5 Vendor Tick  1\n"
+
+	saved := Columns
+	defer func() {
+		Columns = saved
+		Where = nil
+		Decoders = nil
+	}()
+	Columns = []string{"index", "component", "event", "message"}
+	Decoders = new(plugin.Set)
+	Decoders.Add(0x1001, 0x1001, testDecoder{})
+	var err error
+	if Where, err = where.Parse([]string{"component=Vendor,level=Op"}); err != nil {
+		t.Fatalf("where.Parse() error = %v", err)
+	}
+	TimeFactor = nil
+	o := &Output{columns: []string{"Index", "Time (s)", "Component", "Event Property", "Value"}, componentSize: 6, propertySize: 5}
+	var ib event.Binary
+	var b bytes.Buffer
+	out := bufio.NewWriter(&b)
+	var table EventsTable
+	err = o.printEvents(out, ib.Open(&s), nil, nil, nil, &table)
+	ib.Close()
+	if err != nil {
+		t.Errorf("Output.printEvents() error = %v", err)
+	}
+	out.Flush()
+	if b.String() != want {
+		t.Errorf("Output.printEvents() = %q, want %q", b.String(), want)
+	}
+}
+
+func TestPrintSummary(t *testing.T) { //nolint:golint,paralleltest
+	a := &testAnalyzer{}
+	Analyzers = []bus.Analyzer{a, &testAnalyzer{values: []string{"x"}}}
+	Summary = a
+	defer func() {
+		Analyzers = nil
+		Summary = nil
+		TimeFactor = nil
+	}()
+	filename := filepath.Join(t.TempDir(), "out.txt")
+	eventFile := "../../testdata/test10.binary"
+	formatType := "txt"
+	level := ""
+	if err := Print(&filename, &formatType, &level, &eventFile, nil, nil, nil, false, true); err != nil {
+		t.Fatalf("Print() error = %v", err)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("os.ReadFile() error = %v", err)
+	}
+	got := string(data)
+	if !strings.HasPrefix(got, "2 events\n\n   Start/Stop event statistic\n") {
+		t.Errorf("Print() = %q, want summary at the top", got)
+	}
+	if strings.Count(got, "2 events\n") != 1 || !strings.HasSuffix(got, "\n3 events\n") {
+		t.Errorf("Print() = %q, want summary once and other reports at the end", got)
+	}
+}
+
+func TestOutput_definitionBuiltin(t *testing.T) {
+	t.Parallel()
+
+	o := &Output{}
+	def := o.definition(nil, event.IDReset)
+	if def == nil || def.Brief != "EventRecorder" || def.Property != "TargetReset" {
+		t.Errorf("Output.definition() = %v, want EventRecorder TargetReset", def)
+	}
+	evdefs := map[uint16]scvd.Event{event.IDReset: {Brief: "Boot", Property: "Reset"}}
+	o = &Output{}
+	if def := o.definition(evdefs, event.IDReset); def == nil || def.Brief != "Boot" {
+		t.Errorf("Output.definition() = %v, want Boot", def)
+	}
+}
+
+func Test_formatRecordIgnore(t *testing.T) { //nolint:golint,paralleltest
+	Ignore = map[uint16]bool{0x1000: true}
+	defer func() { Ignore = nil }()
+
+	for _, id := range []uint16{0x1000, 0x1001} {
+		rec := EventRecord{}
+		ev := event.Data{Typ: 2, Info: event.Info{ID: id}}
+		show, err := formatRecord(&rec, &ev, nil, nil)
+		if want := id != 0x1000; show != want || err != nil {
+			t.Errorf("formatRecord() 0x%04X = %v, %v, want %v", id, show, err, want)
+		}
+	}
+}
+
+func Test_replaceObjects(t *testing.T) {
+	t.Parallel()
+
+	objects := []objectName{{0x20000100, "main"}, {0x5, "sem"}}
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"id", "thread_id=0x20000100, timeout=100", "thread_id=main, timeout=100"},
+		{"twice", "0x20000100 0x20000100", "main main"},
+		{"short", "semaphore_id=0x05", "semaphore_id=sem"},
+		{"padded", "semaphore_id=0x00000005", "semaphore_id=sem"},
+		{"prefix", "id=0x2000010012, other=0x051", "id=0x2000010012, other=0x051"},
+		{"decimal", "count=5", "count=5"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := replaceObjects(tt.value, objects); got != tt.want {
+				t.Errorf("replaceObjects() %s = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutput_printEventsObjects(t *testing.T) { //nolint:golint,paralleltest
+	var s = "../../testdata/rtx.binary"
+
+	elfFile := "../../testdata/elftest.elf"
+	if err := elf.Sections.Readelf(&elfFile); err != nil {
+		t.Fatalf("elf.Sections.Readelf() error = %v", err)
+	}
+	scvdFiles := []string{"../../testdata/rtx.xml"}
+	evdefs := make(map[uint16]scvd.Event)
+	if err := scvd.Get(&scvdFiles, evdefs, make(map[string]map[string]map[int16]string), make(map[string]map[string]string)); err != nil {
+		t.Fatalf("scvd.Get() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		first int
+		want  string
+	}{
+		{"all", 0, "    0 thread_id=0x20000100, name=def\n    1 thread_id=def\n" +
+			"    2 a=1, b=2\n    3 thread_id=0x20000200\n    4 a=3, b=4\n"},
+		{"skipped", 1, "    1 thread_id=def\n    2 a=1, b=2\n    3 thread_id=0x20000200\n    4 a=3, b=4\n"},
+	}
+	saved := Columns
+	defer func() { Columns, ObjectNames = saved, false }()
+	Columns = []string{"index", "message"}
+	ObjectNames = true
+	TimeFactor = nil
+	for _, tt := range tests { //nolint:golint,paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Output{first: tt.first}
+			var ib event.Binary
+			var b bytes.Buffer
+			out := bufio.NewWriter(&b)
+			err := o.printEvents(out, ib.Open(&s), evdefs, nil, nil, nil)
+			ib.Close()
+			if err != nil {
+				t.Errorf("Output.printEvents() %s error = %v", tt.name, err)
+			}
+			out.Flush()
+			if b.String() != tt.want {
+				t.Errorf("Output.printEvents() %s = %q, want %q", tt.name, b.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+var errTarget = errors.New("invalid output, want <format>=<fileName>")
+
+// Target is a further output of Print, written while the events are read
+// for the main output
+type Target struct {
+	Format   string // name of a registered exporter other than txt
+	Filename string
+}
+
+// Outputs are the further outputs of Print
+var Outputs []Target
+
+// ParseTarget parses a further output <format>=<fileName>
+func ParseTarget(spec string) (Target, error) {
+	format, filename, ok := strings.Cut(spec, "=")
+	if !ok || len(filename) == 0 {
+		return Target{}, fmt.Errorf("%w: %s", errTarget, spec)
+	}
+	if !IsFormat(format) || format == "txt" {
+		return Target{}, fmt.Errorf("%w: unknown format %s", errTarget, format)
+	}
+	return Target{Format: format, Filename: filename}, nil
+}
+
+// output of an exporter to its own file
+type teeOutput struct {
+	exporter Exporter
+	file     *os.File
+	out      *bufio.Writer
+}
+
+// tee passes the calls to the exporter of the main output and to the
+// exporters of the further outputs
+type tee struct {
+	Exporter
+	outputs []teeOutput
+}
+
+// create the files of the further outputs
+func newTee(main Exporter, targets []Target) (*tee, error) {
+	t := &tee{Exporter: main}
+	for _, target := range targets {
+		file, err := os.Create(target.Filename)
+		if err != nil {
+			_ = t.close()
+			return nil, err
+		}
+		t.outputs = append(t.outputs, teeOutput{exporters[target.Format](), file, bufio.NewWriterSize(file, BufferSize)})
+	}
+	return t, nil
+}
+
+func (t *tee) Begin(out io.Writer) error {
+	if err := t.Exporter.Begin(out); err != nil {
+		return err
+	}
+	for _, o := range t.outputs {
+		if err := o.exporter.Begin(o.out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *tee) Event(out io.Writer, rec *EventRecord) error {
+	if err := t.Exporter.Event(out, rec); err != nil {
+		return err
+	}
+	for _, o := range t.outputs {
+		if err := o.exporter.Event(o.out, rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *tee) Stats(out io.Writer, stats []EventRecordStatistic) error {
+	if err := t.Exporter.Stats(out, stats); err != nil {
+		return err
+	}
+	for _, o := range t.outputs {
+		if err := o.exporter.Stats(o.out, stats); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *tee) End(out io.Writer) error {
+	if err := t.Exporter.End(out); err != nil {
+		return err
+	}
+	for _, o := range t.outputs {
+		if err := o.exporter.End(o.out); err != nil {
+			return err
+		}
+		if err := o.out.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// close the files of the further outputs
+func (t *tee) close() error {
+	var err error
+	for _, o := range t.outputs {
+		if e := o.file.Close(); err == nil {
+			err = e
+		}
+	}
+	t.outputs = nil
+	return err
+}
+
+// jsonlWriter writes every event as one line of JSON
+type jsonlWriter struct {
+	line []byte
+}
+
+func (w *jsonlWriter) Begin(io.Writer) error {
+	return nil
+}
+
+func (w *jsonlWriter) Event(out io.Writer, rec *EventRecord) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	w.line = append(append(w.line[:0], line...), '\n')
+	_, err = out.Write(w.line)
+	return err
+}
+
+func (w *jsonlWriter) Stats(io.Writer, []EventRecordStatistic) error {
+	return nil
+}
+
+func (w *jsonlWriter) End(io.Writer) error {
+	return nil
+}
+
+// statsWriter writes the start/stop event statistic as JSON array
+type statsWriter struct {
+	stats []EventRecordStatistic
+}
+
+func (w *statsWriter) Begin(io.Writer) error {
+	return nil
+}
+
+func (w *statsWriter) Event(io.Writer, *EventRecord) error {
+	return nil
+}
+
+func (w *statsWriter) Stats(_ io.Writer, stats []EventRecordStatistic) error {
+	w.stats = append(w.stats, stats...)
+	return nil
+}
+
+func (w *statsWriter) End(out io.Writer) error {
+	if w.stats == nil {
+		w.stats = []EventRecordStatistic{}
+	}
+	data, err := json.MarshalIndent(w.stats, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(append(data, '\n'))
+	return err
+}
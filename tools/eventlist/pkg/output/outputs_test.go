@@ -0,0 +1,112 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output
+
+import (
+	"encoding/json"
+	"eventlist/pkg/event"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseTarget(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		spec    string
+		want    Target
+		wantErr bool
+	}{
+		{"jsonl", "jsonl=events.jsonl", Target{"jsonl", "events.jsonl"}, false},
+		{"stats", "stats=out/stats.json", Target{"stats", "out/stats.json"}, false},
+		{"txt", "txt=events.txt", Target{}, true},
+		{"json", "json=events.json", Target{}, true},
+		{"no file", "jsonl=", Target{}, true},
+		{"no format", "events.jsonl", Target{}, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseTarget(tt.spec)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ParseTarget() %s = %v, %v, want %v, wantErr %v", tt.name, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPrint_outputs(t *testing.T) { //nolint:golint,paralleltest
+	dir := t.TempDir()
+	eventFile := filepath.Join(dir, "events.log")
+	w, err := event.Create(eventFile)
+	if err != nil {
+		t.Fatalf("event.Create() error = %v", err)
+	}
+	_ = w.EventRecord2(100, 0xEF00, 1, 0)
+	_ = w.EventRecord2(200, 0x0A05, 1, 2)
+	_ = w.EventRecord2(300, 0xEF20, 1, 0)
+	if err := w.Close(); err != nil {
+		t.Fatalf("Writer.Close() error = %v", err)
+	}
+
+	names := map[string]string{}
+	for _, format := range []string{"jsonl", "stats"} {
+		names[format] = filepath.Join(dir, format)
+		Outputs = append(Outputs, Target{format, names[format]})
+	}
+	defer func() {
+		Outputs = nil
+		TimeFactor = nil
+	}()
+	filename := filepath.Join(dir, "out.txt")
+	formatType := "txt"
+	level := ""
+	if err := Print(&filename, &formatType, &level, &eventFile, nil, nil, nil, false, false); err != nil {
+		t.Fatalf("Print() error = %v", err)
+	}
+
+	text, _ := os.ReadFile(filename)
+	if !strings.Contains(string(text), "Detailed event list") || !strings.Contains(string(text), "A(0)") {
+		t.Errorf("Print() txt = %s, want event list and statistic", text)
+	}
+	data, _ := os.ReadFile(names["jsonl"])
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Print() jsonl = %s, want 3 lines", data)
+	}
+	var rec EventRecord
+	if err := json.Unmarshal([]byte(lines[1]), &rec); err != nil || rec.Index != 1 || rec.EventProperty != "0x0A05" {
+		t.Errorf("Print() jsonl line = %s, %v", lines[1], err)
+	}
+	data, _ = os.ReadFile(names["stats"])
+	var stats []EventRecordStatistic
+	if err := json.Unmarshal(data, &stats); err != nil || len(stats) != 1 || stats[0].Event != "A(0)" || stats[0].Count != 1 {
+		t.Errorf("Print() stats = %s, %v", data, err)
+	}
+
+	Outputs = []Target{{"jsonl", filepath.Join(dir, "missing", "x")}}
+	if err := Print(&filename, &formatType, &level, &eventFile, nil, nil, nil, false, false); err == nil {
+		t.Errorf("Print() error = nil, want error of the missing directory")
+	}
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output
+
+import (
+	"bufio"
+	"errors"
+	"eventlist/pkg/eval"
+	"eventlist/pkg/event"
+	"eventlist/pkg/xml/scvd"
+	"fmt"
+)
+
+// Workers is the number of goroutines formatting the event values, with
+// more than one worker the events are decoded by a pipeline
+var Workers = 1
+
+// number of events passed between the stages of the pipeline at once
+const batchSize = 256
+
+// events of the pipeline, read and prepared in order by the read stage
+// and formatted by one of the workers
+type batch struct {
+	events  []event.Data
+	records []EventRecord
+	show    []bool
+	errs    []error
+	err     error         // read error after the events
+	done    chan struct{} // closed when the events are formatted
+}
+
+// decode the events by the pipeline read → format → write, the workers
+// format the batches in any order, the write stage takes them in order
+func (o *Output) decodeParallel(out *bufio.Writer, in *bufio.Reader, evdefs map[uint16]scvd.Event,
+	typedefs map[string]map[string]map[int16]string, memberTypes map[string]map[string]string,
+	eventTable *EventsTable) error {
+	quit := make(chan struct{})
+	defer close(quit)
+	work := make(chan *batch, Workers)
+	order := make(chan *batch, 2*Workers)
+	go o.readBatches(in, evdefs, work, order, quit)
+	for i := 0; i < Workers; i++ {
+		go formatBatches(work, typedefs, memberTypes)
+	}
+	writeStage := Trace.Stage("write")
+	for b := range order {
+		<-b.done
+		for i := range b.records {
+			rec := &b.records[i]
+			if b.errs[i] != nil {
+				_ = o.flushRecord(out, eventTable)
+				collect(eventTable, rec)
+				return b.errs[i]
+			}
+			show := b.show[i] && match(rec, &b.events[i])
+			start := writeStage.Start()
+			if err := o.addRecord(out, rec, show, eventTable); err != nil {
+				return err
+			}
+			writeStage.Stop(start)
+		}
+		if b.err != nil {
+			return b.err
+		}
+	}
+	return nil
+}
+
+// read the events and prepare their records in order
+func (o *Output) readBatches(in *bufio.Reader, evdefs map[uint16]scvd.Event,
+	work chan<- *batch, order chan<- *batch, quit <-chan struct{}) {
+	defer close(work)
+	defer close(order)
+	c := o.newCursor(in, evdefs)
+	track := o.tracking()
+	readStage := Trace.Stage("read")
+	for end := false; !end; {
+		b := &batch{done: make(chan struct{})}
+		for len(b.events) < batchSize {
+			if o.end > 0 && c.no >= o.end {
+				end = true
+				break
+			}
+			var ev event.Data
+			start := readStage.Start()
+			err := c.next(&ev, c.no < o.first && !track)
+			readStage.Stop(start)
+			if err != nil {
+				if !errors.Is(err, eval.ErrEof) {
+					fmt.Println(err)
+					b.err = err
+				}
+				end = true
+				break
+			}
+			time := c.tm.time(&ev)
+			if c.no < o.first {
+				if track {
+					o.follow(c.no, time, &ev, o.definition(evdefs, ev.Info.ID))
+				}
+				c.no++
+				continue
+			}
+			b.events = append(b.events, ev)
+			b.records = append(b.records, o.prepareRecord(c.no, time, &ev, evdefs))
+			c.no++
+		}
+		select {
+		case order <- b:
+		case <-quit:
+			return
+		}
+		select {
+		case work <- b:
+		case <-quit:
+			return
+		}
+	}
+}
+
+// format the batches until there are no more
+func formatBatches(work <-chan *batch, typedefs map[string]map[string]map[int16]string,
+	memberTypes map[string]map[string]string) {
+	decodeStage := Trace.Stage("decode")
+	for b := range work {
+		b.show = make([]bool, len(b.events))
+		b.errs = make([]error, len(b.events))
+		for i := range b.events {
+			start := decodeStage.Start()
+			b.show[i], b.errs[i] = formatRecord(&b.records[i], &b.events[i], typedefs, memberTypes)
+			decodeStage.Stop(start)
+		}
+		close(b.done)
+	}
+}
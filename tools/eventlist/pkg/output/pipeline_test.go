@@ -0,0 +1,160 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"eventlist/pkg/event"
+	"eventlist/pkg/xml/scvd"
+	"fmt"
+	"io"
+	"path/filepath"
+	"testing"
+)
+
+func TestOutput_decodeParallel(t *testing.T) { //nolint:golint,paralleltest
+	// more events than fit into a batch, with values evaluated by the workers
+	s := filepath.Join(t.TempDir(), "pipeline.binary")
+	w, err := event.Create(s)
+	if err != nil {
+		t.Fatalf("event.Create() error = %v", err)
+	}
+	for i := 0; i < 3*batchSize+17; i++ {
+		switch i % 4 {
+		case 0:
+			err = w.EventRecord2(uint64(10*i), 0xEF00|uint16(i%3), int32(i), 0)
+		case 1:
+			err = w.EventRecord2(uint64(10*i), 0x0A01, int32(i), int32(-i))
+		case 2:
+			err = w.EventRecordData(uint64(10*i), 0xFE00, []byte("line\n"))
+		case 3:
+			err = w.EventRecord2(uint64(10*i), 0xEF20|uint16((i-3)%3), int32(i), 0)
+		}
+		if err != nil {
+			t.Fatalf("Writer.EventRecord2() error = %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Writer.Close() error = %v", err)
+	}
+	evdefs := map[uint16]scvd.Event{
+		0x0A01: {Brief: "Test", Property: "Values", Value: "a=%d[val1] b=%d[val2] sum=%d[val1+val2]"},
+	}
+
+	print := func(workers int, first int, end int) string {
+		saved := Workers
+		defer func() { Workers = saved }()
+		Workers = workers
+		TimeFactor = nil
+		o := &Output{columns: []string{"Index", "Time (s)", "Component", "Event Property", "Value"}}
+		var ib event.Binary
+		o.buildStatistic(ib.Open(&s), evdefs, nil, nil)
+		ib.Close()
+		o.first, o.end = first, end
+		var b bytes.Buffer
+		out := bufio.NewWriter(&b)
+		err := o.printEvents(out, ib.Open(&s), evdefs, nil, nil, &EventsTable{})
+		ib.Close()
+		if err != nil {
+			t.Errorf("Output.printEvents() workers %d error = %v", workers, err)
+		}
+		out.Flush()
+		return b.String()
+	}
+
+	tests := []struct {
+		name   string
+		tree   bool
+		squash bool
+		first  int
+		end    int
+	}{
+		{"all", false, false, 0, 0},
+		{"tree", true, false, 0, 0},
+		{"squash", false, true, 0, 0},
+		{"window", false, false, batchSize - 3, 2*batchSize + 5},
+		{"window tree", true, false, 100, 0},
+	}
+	defer func() {
+		Tree = false
+		Squash = false
+	}()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Tree, Squash = tt.tree, tt.squash
+			want := print(1, tt.first, tt.end)
+			got := print(4, tt.first, tt.end)
+			if want == "" {
+				t.Fatalf("Output.printEvents() %s printed nothing", tt.name)
+			}
+			if got != want {
+				t.Errorf("Output.printEvents() %s = %v, want %v", tt.name, got, want)
+			}
+		})
+	}
+}
+
+func BenchmarkOutput_printEvents(b *testing.B) {
+	s := filepath.Join(b.TempDir(), "bench.binary")
+	w, err := event.Create(s)
+	if err != nil {
+		b.Fatalf("event.Create() error = %v", err)
+	}
+	for i := 0; i < 10000; i++ {
+		switch i % 3 {
+		case 0:
+			err = w.EventRecord2(uint64(10*i), 0x0A01, int32(i), int32(-i))
+		case 1:
+			err = w.EventRecord4(uint64(10*i), 0x0B02, 1, 2, 3, int32(i))
+		case 2:
+			err = w.EventRecordData(uint64(10*i), 0xFE00, []byte("hello\n"))
+		}
+		if err != nil {
+			b.Fatalf("Writer.EventRecord2() error = %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		b.Fatalf("Writer.Close() error = %v", err)
+	}
+	evdefs := map[uint16]scvd.Event{
+		0x0A01: {Brief: "Test", Property: "Values", Value: "a=%d[val1] b=%x[val2]"},
+		0x0B02: {Brief: "Net", Property: "Four", Value: "v=%u[val1] ip=%I[val4]"},
+	}
+	saved := Workers
+	defer func() { Workers = saved }()
+	for _, workers := range []int{1, 4} {
+		Workers = workers
+		b.Run(fmt.Sprintf("jobs=%d", workers), func(b *testing.B) {
+			TimeFactor = nil
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				o := &Output{columns: []string{"Index", "Time (s)", "Component", "Event Property", "Value"}}
+				var ib event.Binary
+				o.buildStatistic(ib.Open(&s), evdefs, nil, nil)
+				ib.Close()
+				out := bufio.NewWriter(io.Discard)
+				if err := o.printEvents(out, ib.Open(&s), evdefs, nil, nil, nil); err != nil {
+					b.Fatalf("Output.printEvents() error = %v", err)
+				}
+				ib.Close()
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+// Interrupted is set when the last Print or Live was stopped by SIGINT or
+// SIGTERM, its output covers the events read before the signal
+var Interrupted bool
+
+// signals stopping the reading of the events
+var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// shutdown stops the reading of the events on the first shutdown signal,
+// the output is completed with the events read before, a second signal
+// terminates the process
+type shutdown struct {
+	signals chan os.Signal
+	done    chan struct{}
+	stopped atomic.Bool
+	wg      sync.WaitGroup
+}
+
+// watch the shutdown signals, stop is called on the first one, e.g. to
+// close a live source blocking the reading
+func newShutdown(stop func()) *shutdown {
+	s := &shutdown{signals: make(chan os.Signal, 1), done: make(chan struct{})}
+	signal.Notify(s.signals, shutdownSignals...)
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		select {
+		case <-s.signals:
+			s.interrupt(stop)
+		case <-s.done:
+		}
+	}()
+	return s
+}
+
+// stop reading the events
+func (s *shutdown) interrupt(stop func()) {
+	signal.Stop(s.signals)
+	s.stopped.Store(true)
+	if stop != nil {
+		stop()
+	}
+}
+
+// interrupted reports whether a shutdown signal has been received
+func (s *shutdown) interrupted() bool {
+	return s != nil && s.stopped.Load()
+}
+
+// stop watching the signals
+func (s *shutdown) release() {
+	signal.Stop(s.signals)
+	close(s.done)
+	s.wg.Wait()
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"eventlist/pkg/eval"
+	"eventlist/pkg/event"
+	"testing"
+)
+
+func TestShutdown(t *testing.T) {
+	t.Parallel()
+
+	var s *shutdown
+	if s.interrupted() {
+		t.Errorf("shutdown.interrupted() nil = %v, want %v", true, false)
+	}
+	stopped := 0
+	s = newShutdown(func() { stopped++ })
+	if s.interrupted() {
+		t.Errorf("shutdown.interrupted() = %v, want %v", true, false)
+	}
+	s.interrupt(func() { stopped++ })
+	s.release()
+	if !s.interrupted() || stopped != 1 {
+		t.Errorf("shutdown.interrupt() = %v, %d, want %v, %d", s.interrupted(), stopped, true, 1)
+	}
+}
+
+func TestCursor_nextInterrupted(t *testing.T) {
+	t.Parallel()
+
+	var data []byte
+	for i := 0; i < 3; i++ {
+		e := event.Data{Typ: 2, Time: uint64(i), Info: event.Info{ID: 0x0A01}}
+		data = e.AppendRecord(data)
+	}
+	s := newShutdown(nil)
+	defer s.release()
+	o := Output{shutdown: s}
+	c := o.newCursor(bufio.NewReader(bytes.NewReader(data)), nil)
+	var ev event.Data
+	if err := c.next(&ev, false); err != nil {
+		t.Errorf("cursor.next() error = %v, want %v", err, nil)
+	}
+	s.interrupt(nil)
+	if err := c.next(&ev, false); !errors.Is(err, eval.ErrEof) {
+		t.Errorf("cursor.next() interrupted error = %v, want %v", err, eval.ErrEof)
+	}
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output
+
+import "eventlist/pkg/bus"
+
+// Statistic collects the start/stop event statistic of the published
+// events, e.g. of events decoded with Decode
+type Statistic struct {
+	o     Output
+	count int
+}
+
+// NewStatistic creates a statistic without events
+func NewStatistic() *Statistic {
+	s := &Statistic{}
+	for i := range s.o.evProps {
+		s.o.evProps[i].init()
+	}
+	return s
+}
+
+// Event adds a start or stop event to the statistic
+func (s *Statistic) Event(ev *bus.Event) error {
+	s.count++
+	class, group, idx, start := ev.Data.Info.SplitID()
+	if class == 0xEF {
+		rep, _ := ev.Value()
+		s.o.evProps[group].add(ev.Time, idx, start, rep, nil)
+	}
+	return nil
+}
+
+func (s *Statistic) End() error {
+	return nil
+}
+
+// Records returns the statistic of every start/stop event
+func (s *Statistic) Records() []EventRecordStatistic {
+	if s.count == 0 {
+		return nil
+	}
+	return s.o.statistics()
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output
+
+import (
+	"eventlist/pkg/bus"
+	"eventlist/pkg/event"
+	"testing"
+)
+
+func TestStatistic(t *testing.T) {
+	t.Parallel()
+
+	s := NewStatistic()
+	events := []struct {
+		id   uint16
+		time float64
+	}{
+		{0xEF00, 1.0}, {0x0A01, 1.5}, {0xEF20, 2.0},
+		{0xEF00, 3.0}, {0xEF20, 3.5},
+		{0xEF41, 4.0}, {0xEF61, 7.0},
+	}
+	for i, e := range events {
+		data := event.Data{Typ: 2, Info: event.Info{ID: e.id}}
+		ev := bus.NewEvent(i, e.time, &data, nil, func() (string, error) { return "", nil })
+		if err := s.Event(ev); err != nil {
+			t.Errorf("Statistic.Event() error = %v", err)
+		}
+	}
+	if err := s.End(); err != nil {
+		t.Errorf("Statistic.End() error = %v", err)
+	}
+	got := s.Records()
+	want := []struct {
+		event   string
+		count   int
+		minTime float64
+		maxTime float64
+	}{
+		{"A(0)", 2, 3.0, 1.0},
+		{"B(1)", 1, 4.0, 4.0},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Statistic.Records() = %v, want %d records", got, len(want))
+	}
+	for i, w := range want {
+		if got[i].Event != w.event || got[i].Count != w.count || got[i].MinTime != w.minTime || got[i].MaxTime != w.maxTime {
+			t.Errorf("Statistic.Records() %s = %+v, want %+v", w.event, got[i], w)
+		}
+	}
+	if got := NewStatistic().Records(); len(got) != 0 {
+		t.Errorf("Statistic.Records() without events = %v, want none", got)
+	}
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output
+
+import (
+	"errors"
+	"eventlist/pkg/event"
+	"eventlist/pkg/xml/scvd"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// kinds of the failures of the strict mode
+const (
+	unknownComponent  = iota // no event of the component is defined
+	unknownEvent             // the component is defined but not the event
+	unresolvedAddress        // a string address is not in the ELF file
+	expressionError          // the value of the event cannot be formatted
+	failureKinds
+)
+
+var failureNames = [failureKinds]string{"unknown components", "unknown events", "unresolved string addresses", "expression errors"}
+
+// failure is the first failure of a kind and the number of failures
+type failure struct {
+	count int
+	index int
+	id    uint16
+	err   error
+}
+
+// Failures collects the events that cannot be decoded completely, with
+// Strict the decoding goes on after a failure and the tool exits with an
+// error at the end
+type Failures struct {
+	mu         sync.Mutex // the records may be formatted in parallel
+	components map[uint8]bool
+	failures   [failureKinds]failure
+}
+
+// Strict collects the decode failures of the strict mode, nil stops the
+// decoding at the first expression error
+var Strict *Failures
+
+// NewFailures collects the failures of the events of the definitions
+func NewFailures(evdefs map[uint16]scvd.Event) *Failures {
+	f := &Failures{components: make(map[uint8]bool)}
+	for id := range evdefs {
+		f.components[uint8(id>>8)] = true
+	}
+	return f
+}
+
+// add a failure of an event
+func (f *Failures) add(kind int, index int, id uint16, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fl := &f.failures[kind]
+	if fl.count == 0 || index < fl.index {
+		fl.index, fl.id, fl.err = index, id, err
+	}
+	fl.count++
+}
+
+// unknown adds the failure of an event without definition
+func (f *Failures) unknown(index int, id uint16) {
+	if info := (event.Info{ID: id}); info.Builtin() {
+		return
+	}
+	kind := unknownEvent
+	if !f.components[uint8(id>>8)] {
+		kind = unknownComponent
+	}
+	f.add(kind, index, id, nil)
+}
+
+// value adds the failure of a value that cannot be formatted
+func (f *Failures) value(index int, id uint16, err error) {
+	kind := expressionError
+	if errors.Is(err, event.ErrUnresolved) {
+		kind = unresolvedAddress
+	}
+	f.add(kind, index, id, err)
+}
+
+// Count returns the number of failures
+func (f *Failures) Count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, fl := range f.failures {
+		n += fl.count
+	}
+	return n
+}
+
+// Report writes a line per kind of failure with the first failed event
+func (f *Failures) Report(w io.Writer) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for kind, fl := range f.failures {
+		if fl.count == 0 {
+			continue
+		}
+		line := fmt.Sprintf("%d %s, first: event %d, id 0x%04X", fl.count, failureNames[kind], fl.index, fl.id)
+		if fl.err != nil {
+			line += ": " + fl.err.Error()
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
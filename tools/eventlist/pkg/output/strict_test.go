@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output
+
+import (
+	"errors"
+	"eventlist/pkg/event"
+	"eventlist/pkg/xml/scvd"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFailures_unknown(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		id   uint16
+		want [failureKinds]int
+	}{
+		{"component", 0x4A00, [failureKinds]int{1, 0, 0, 0}},
+		{"event", 0x0A05, [failureKinds]int{0, 1, 0, 0}},
+		{"recorder", 0xFF03, [failureKinds]int{}},
+		{"stdout", 0xFE00, [failureKinds]int{}},
+		{"start", 0xEF21, [failureKinds]int{}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := NewFailures(map[uint16]scvd.Event{0x0A01: {}})
+			f.unknown(3, tt.id)
+			var got [failureKinds]int
+			for kind, fl := range f.failures {
+				got[kind] = fl.count
+			}
+			if got != tt.want {
+				t.Errorf("Failures.unknown() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFailures_Report(t *testing.T) {
+	t.Parallel()
+
+	f := NewFailures(nil)
+	if n := f.Count(); n != 0 {
+		t.Errorf("Failures.Count() = %d, want 0", n)
+	}
+	f.value(7, 0xF205, fmt.Errorf("%w: 0x%08x", event.ErrUnresolved, 0x4010))
+	f.value(2, 0xF206, fmt.Errorf("%w: 0x%08x", event.ErrUnresolved, 0x4020))
+	f.value(5, 0x0A01, errors.New("bad format"))
+	f.unknown(4, 0x4A00)
+	if n := f.Count(); n != 4 {
+		t.Errorf("Failures.Count() = %d, want 4", n)
+	}
+	var b strings.Builder
+	if err := f.Report(&b); err != nil {
+		t.Errorf("Failures.Report() error = %v", err)
+	}
+	want := "1 unknown components, first: event 4, id 0x4A00\n" +
+		"2 unresolved string addresses, first: event 2, id 0xF206: unresolved string address: 0x00004020\n" +
+		"1 expression errors, first: event 5, id 0x0A01: bad format\n"
+	if b.String() != want {
+		t.Errorf("Failures.Report() = %q, want %q", b.String(), want)
+	}
+}
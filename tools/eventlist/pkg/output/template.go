@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// Export replaces the exporter of the format of Print, e.g. by a template
+var Export Exporter
+
+// templateWriter writes the events with a text/template, the main
+// template is executed for every event with the EventRecord, the
+// optional templates "begin" and "end" before and after the events and
+// "stats" with the list of EventRecordStatistic
+type templateWriter struct {
+	tmpl *template.Template
+}
+
+var latexEscaper = strings.NewReplacer(`\`, `\textbackslash{}`, "&", `\&`, "%", `\%`, "$", `\$`,
+	"#", `\#`, "_", `\_`, "{", `\{`, "}", `\}`, "~", `\textasciitilde{}`, "^", `\textasciicircum{}`)
+
+var templateFuncs = template.FuncMap{
+	// csv quotes a field if needed
+	"csv": func(s string) string {
+		if strings.ContainsAny(s, "\",\r\n") {
+			return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+		}
+		return s
+	},
+	// latex escapes the special characters of LaTeX
+	"latex": latexEscaper.Replace,
+	// json quotes a value as JSON
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// LoadTemplate loads the template of the events from a file
+func LoadTemplate(filename string) (Exporter, error) {
+	text, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New(filepath.Base(filename)).Funcs(templateFuncs).Parse(string(text))
+	if err != nil {
+		return nil, err
+	}
+	return &templateWriter{tmpl: tmpl}, nil
+}
+
+// execute a template defined in the file, nothing if it is not defined
+func (w *templateWriter) execute(out io.Writer, name string, data any) error {
+	if t := w.tmpl.Lookup(name); t != nil {
+		return t.Execute(out, data)
+	}
+	return nil
+}
+
+func (w *templateWriter) Begin(out io.Writer) error {
+	return w.execute(out, "begin", nil)
+}
+
+func (w *templateWriter) Event(out io.Writer, rec *EventRecord) error {
+	return w.tmpl.Execute(out, rec)
+}
+
+func (w *templateWriter) Stats(out io.Writer, stats []EventRecordStatistic) error {
+	return w.execute(out, "stats", stats)
+}
+
+func (w *templateWriter) End(out io.Writer) error {
+	return w.execute(out, "end", nil)
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadTemplate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	bad := filepath.Join(dir, "bad.tmpl")
+	if err := os.WriteFile(bad, []byte("{{.Index"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		filename string
+	}{
+		{"missing", filepath.Join(dir, "missing.tmpl")},
+		{"syntax", bad},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := LoadTemplate(tt.filename); err == nil {
+				t.Errorf("LoadTemplate() %s error = nil, want error", tt.name)
+			}
+		})
+	}
+}
+
+func Test_templateFuncs(t *testing.T) {
+	t.Parallel()
+
+	csv := templateFuncs["csv"].(func(string) string)
+	latex := templateFuncs["latex"].(func(string) string)
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"csv plain", csv("abc"), "abc"},
+		{"csv comma", csv(`a,"b"`), `"a,""b"""`},
+		{"latex", latex(`50% of a_b & {c}`), `50\% of a\_b \& \{c\}`},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("templateFuncs %s = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestPrint_template(t *testing.T) { //nolint:golint,paralleltest
+	dir := t.TempDir()
+	tmpl := filepath.Join(dir, "events.tmpl")
+	text := `{{define "begin"}}index,id,component,value` + "\n" + `{{end -}}
+{{define "stats"}}{{len .}} statistics` + "\n" + `{{end -}}
+{{define "end"}}end` + "\n" + `{{end -}}
+{{.Index}},{{printf "0x%04X" .ID}},{{csv .Component}},{{csv .Value}}` + "\n"
+	if err := os.WriteFile(tmpl, []byte(text), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var err error
+	if Export, err = LoadTemplate(tmpl); err != nil {
+		t.Fatalf("LoadTemplate() error = %v", err)
+	}
+	defer func() {
+		Export = nil
+		TimeFactor = nil
+		FormatType = "txt"
+	}()
+
+	filename := filepath.Join(dir, "out.csv")
+	eventFile := "../../testdata/test10.binary"
+	level := ""
+	if err := Print(&filename, nil, &level, &eventFile, nil, nil, nil, false, false); err != nil {
+		t.Fatalf("Print() error = %v", err)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("os.ReadFile() error = %v", err)
+	}
+	want := "index,id,component,value\n" +
+		"0,0xFF03,0xFF,\"val1=0x00000004, val2=0x00000002\"\n" +
+		"1,0xFE00,0xFE,hello wo\n" +
+		"0 statistics\nend\n"
+	if string(data) != want {
+		t.Errorf("Print() = %q, want %q", data, want)
+	}
+}
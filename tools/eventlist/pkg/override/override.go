@@ -0,0 +1,140 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package override patches the event definitions of the SCVD files with a
+// local file, so broken vendor definitions need no change of the pack.
+package override
+
+import (
+	"errors"
+	"eventlist/pkg/xml/scvd"
+	"fmt"
+	"os"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+var errID = errors.New("invalid event ID")
+var errLevel = errors.New("invalid level")
+var errEntry = errors.New("invalid override")
+
+// Ignore is the value of the events that are not decoded
+const Ignore = "ignore"
+
+// Override replaces the fields of an event definition, empty fields keep
+// the fields of the SCVD file
+type Override struct {
+	Ignore    bool   `yaml:"-"`
+	Component string `yaml:"component"`
+	Property  string `yaml:"property"`
+	Value     string `yaml:"value"`
+	Level     string `yaml:"level"`
+}
+
+// Map assigns the overrides to event IDs
+type Map map[uint16]Override
+
+// Load reads an override file, a YAML map of event IDs to "ignore", to
+// the format of the value or to the fields of the definition:
+//
+//	0x4E01: ignore
+//	0x4E02: "len=%d[val1]"
+//	0x4E03:
+//	  component: MyNet
+//	  property: Send
+//	  value: "len=%d[val1] to %I[val2]"
+//	  level: Op
+func Load(filename string) (Map, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var entries map[string]yaml.Node
+	if err = yaml.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	m := make(Map)
+	for key, node := range entries {
+		id, err := strconv.ParseUint(key, 0, 16)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errID, key)
+		}
+		var o Override
+		switch node.Kind {
+		case yaml.ScalarNode:
+			if node.Value == Ignore {
+				o.Ignore = true
+			} else {
+				o.Value = node.Value
+			}
+		case yaml.MappingNode:
+			if err = node.Decode(&o); err != nil {
+				return nil, fmt.Errorf("%w: %s: %v", errEntry, key, err)
+			}
+		default:
+			return nil, fmt.Errorf("%w: %s", errEntry, key)
+		}
+		switch o.Level {
+		case "", "Error", "API", "Op", "Detail":
+		default:
+			return nil, fmt.Errorf("%w: %s: %s", errLevel, key, o.Level)
+		}
+		m[uint16(id)] = o
+	}
+	return m, nil
+}
+
+// Apply changes the event definitions, an event without definition gets
+// one with the number of its component and ID as names
+func (m Map) Apply(evdefs map[uint16]scvd.Event) {
+	for id, o := range m {
+		if o.Ignore {
+			continue
+		}
+		evdef, ok := evdefs[id]
+		if !ok {
+			evdef = scvd.Event{ID: scvd.ID(fmt.Sprintf("0x%04X", id)), Level: "Op",
+				Brief: fmt.Sprintf("0x%02X", uint8(id>>8)), Property: fmt.Sprintf("0x%04X", id)}
+		}
+		if len(o.Component) != 0 {
+			evdef.Brief = o.Component
+		}
+		if len(o.Property) != 0 {
+			evdef.Property = o.Property
+		}
+		if len(o.Value) != 0 {
+			evdef.Value = scvd.Value(o.Value)
+		}
+		if len(o.Level) != 0 {
+			evdef.Level = o.Level
+		}
+		evdefs[id] = evdef
+	}
+}
+
+// Ignored returns the IDs of the events that are not decoded
+func (m Map) Ignored() map[uint16]bool {
+	ignored := make(map[uint16]bool)
+	for id, o := range m {
+		if o.Ignore {
+			ignored[id] = true
+		}
+	}
+	return ignored
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package override
+
+import (
+	"errors"
+	"eventlist/pkg/xml/scvd"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	badID := filepath.Join(dir, "id.yaml")
+	_ = os.WriteFile(badID, []byte("0x10000: ignore\n"), 0600)
+	badEntry := filepath.Join(dir, "entry.yaml")
+	_ = os.WriteFile(badEntry, []byte("0x1000: [ignore]\n"), 0600)
+	badField := filepath.Join(dir, "field.yaml")
+	_ = os.WriteFile(badField, []byte("0x1000:\n  value: [x]\n"), 0600)
+	badYAML := filepath.Join(dir, "yaml.yaml")
+	_ = os.WriteFile(badYAML, []byte("- ignore\n"), 0600)
+
+	tests := []struct {
+		name     string
+		filename string
+		want     Map
+		wantErr  error
+	}{
+		{"ok", "../../testdata/override.yaml", Map{
+			0xF000: {Ignore: true},
+			0xFE00: {Value: "%x[val1]"},
+			0x1000: {Component: "Test", Property: "Poll", Value: "n=%d[val1]", Level: "Error"},
+		}, nil},
+		{"level", "../../testdata/override_err.yaml", nil, errLevel},
+		{"id", badID, nil, errID},
+		{"entry", badEntry, nil, errEntry},
+		{"field", badField, nil, errEntry},
+		{"yaml", badYAML, nil, nil},
+		{"nix", "../../testdata/nix.yaml", nil, os.ErrNotExist},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Load(tt.filename)
+			if tt.want == nil && err == nil {
+				t.Errorf("Load() %s error = nil, want error", tt.name)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Load() %s error = %v, want %v", tt.name, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Load() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMap_Apply(t *testing.T) {
+	t.Parallel()
+
+	m := Map{
+		0x1000: {Value: "n=%d[val1]"},
+		0x1001: {Ignore: true},
+		0x2003: {Property: "Send", Level: "Error"},
+	}
+	evdefs := map[uint16]scvd.Event{
+		0x1000: {Brief: "Test", Property: "Poll", Level: "Op", Value: "%x[val1]"},
+		0x1001: {Brief: "Test", Property: "Fail", Level: "Error"},
+	}
+	want := map[uint16]scvd.Event{
+		0x1000: {Brief: "Test", Property: "Poll", Level: "Op", Value: "n=%d[val1]"},
+		0x1001: {Brief: "Test", Property: "Fail", Level: "Error"},
+		0x2003: {ID: "0x2003", Brief: "0x20", Property: "Send", Level: "Error"},
+	}
+	m.Apply(evdefs)
+	if !reflect.DeepEqual(evdefs, want) {
+		t.Errorf("Map.Apply() = %v, want %v", evdefs, want)
+	}
+	if got := m.Ignored(); !reflect.DeepEqual(got, map[uint16]bool{0x1001: true}) {
+		t.Errorf("Map.Ignored() = %v, want 0x1001", got)
+	}
+}
@@ -0,0 +1,226 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package pack provides the SCVD files of CMSIS packs: the pack is looked up
+// in the public pack index, downloaded and its SCVD files are extracted
+// into the cache directory.
+package pack
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"eventlist/pkg/fetch"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var errID = errors.New("invalid pack ID, want <vendor>::<name>[@<version>]")
+var errNotFound = errors.New("pack not in the index")
+var errNoSCVD = errors.New("pack contains no SCVD file")
+
+// IndexURL is the public index of the packs, a pidx or vidx file
+var IndexURL = "https://www.keil.com/pack/index.pidx"
+
+// ID identifies a pack, an empty version is the latest one of the index
+type ID struct {
+	Vendor  string
+	Name    string
+	Version string
+}
+
+func (id ID) String() string {
+	s := id.Vendor + "::" + id.Name
+	if len(id.Version) != 0 {
+		s += "@" + id.Version
+	}
+	return s
+}
+
+// ParseID reads a pack ID, e.g. ARM::CMSIS-RTX@5.9.0
+func ParseID(s string) (ID, error) {
+	var id ID
+	var ok bool
+	id.Vendor, id.Name, ok = strings.Cut(strings.TrimSpace(s), "::")
+	if !ok {
+		return id, fmt.Errorf("%w: %s", errID, s)
+	}
+	id.Name, id.Version, _ = strings.Cut(id.Name, "@")
+	if len(id.Vendor) == 0 || len(id.Name) == 0 || strings.HasSuffix(s, "@") ||
+		strings.ContainsAny(id.Vendor+id.Name+id.Version, `/\:`) {
+		return id, fmt.Errorf("%w: %s", errID, s)
+	}
+	return id, nil
+}
+
+// index is a pidx file with the packs or a vidx file with the pidx files
+// of the vendors and packs
+type index struct {
+	Pdsc []struct {
+		URL     string `xml:"url,attr"`
+		Vendor  string `xml:"vendor,attr"`
+		Name    string `xml:"name,attr"`
+		Version string `xml:"version,attr"`
+	} `xml:"pindex>pdsc"`
+	Pidx []struct {
+		URL    string `xml:"url,attr"`
+		Vendor string `xml:"vendor,attr"`
+	} `xml:"vindex>pidx"`
+}
+
+// resolve returns the URL of the pack and its version, the pidx file of the
+// vendor is read if the index has none of its packs
+func resolve(id ID, indexURL string, depth int) (string, string, error) {
+	name, err := fetch.Get(indexURL)
+	if err != nil {
+		return "", "", err
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return "", "", err
+	}
+	var idx index
+	if err = xml.Unmarshal(data, &idx); err != nil {
+		return "", "", fmt.Errorf("%s: %w", indexURL, err)
+	}
+	for _, p := range idx.Pdsc {
+		if p.Vendor == id.Vendor && p.Name == id.Name {
+			version := id.Version
+			if len(version) == 0 {
+				version = p.Version
+			}
+			return strings.TrimSuffix(p.URL, "/") + "/" + id.Vendor + "." + id.Name + "." + version + ".pack", version, nil
+		}
+	}
+	if depth == 0 {
+		for _, p := range idx.Pidx {
+			if p.Vendor == id.Vendor {
+				url := p.URL
+				if !strings.HasSuffix(url, ".pidx") {
+					url = strings.TrimSuffix(url, "/") + "/" + p.Vendor + ".pidx"
+				}
+				return resolve(id, url, depth+1)
+			}
+		}
+	}
+	return "", "", fmt.Errorf("%w: %s", errNotFound, id)
+}
+
+// SCVDFiles returns the SCVD files of a pack in sorted order, the pack is
+// downloaded and extracted into the cache directory on the first use
+func SCVDFiles(id ID) ([]string, error) {
+	cache, err := fetch.Dir()
+	if err != nil {
+		return nil, err
+	}
+	if len(id.Version) != 0 {
+		if files, err := find(filepath.Join(cache, "packs", id.Vendor+"."+id.Name+"."+id.Version)); err == nil {
+			return files, nil
+		}
+	}
+	url, version, err := resolve(id, IndexURL, 0)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(cache, "packs", id.Vendor+"."+id.Name+"."+version)
+	if files, err := find(dir); err == nil {
+		return files, nil
+	}
+	name, err := fetch.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	err = extract(name, dir)
+	os.Remove(name) // the SCVD files are kept only
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", id, err)
+	}
+	return find(dir)
+}
+
+// find returns the SCVD files of an extracted pack
+func find(dir string) ([]string, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && strings.EqualFold(filepath.Ext(path), ".scvd") {
+			files = append(files, path)
+		}
+		return err
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+// extract the SCVD files of a pack into dir, they are written to a
+// temporary directory renamed to dir, so dir is complete if it exists
+func extract(name string, dir string) error {
+	r, err := zip.OpenReader(name)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	if err = os.MkdirAll(filepath.Dir(dir), 0700); err != nil {
+		return err
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(dir), ".pack-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	n := 0
+	for _, f := range r.File {
+		if !strings.EqualFold(filepath.Ext(f.Name), ".scvd") || !filepath.IsLocal(f.Name) {
+			continue
+		}
+		if err = extractFile(f, filepath.Join(tmp, filepath.FromSlash(f.Name))); err != nil {
+			return err
+		}
+		n++
+	}
+	if n == 0 {
+		return errNoSCVD
+	}
+	return os.Rename(tmp, dir)
+}
+
+func extractFile(f *zip.File, name string) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0700); err != nil {
+		return err
+	}
+	in, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, io.LimitReader(in, fetch.MaxSize))
+	if e := out.Close(); err == nil {
+		err = e
+	}
+	return err
+}
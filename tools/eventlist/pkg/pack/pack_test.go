@@ -0,0 +1,145 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pack
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"eventlist/pkg/fetch"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		s       string
+		want    ID
+		wantErr bool
+	}{
+		{"ARM::CMSIS-RTX@5.9.0", ID{"ARM", "CMSIS-RTX", "5.9.0"}, false},
+		{"Keil::MDK-Middleware", ID{"Keil", "MDK-Middleware", ""}, false},
+		{"ARM.CMSIS-RTX", ID{}, true},
+		{"::CMSIS", ID{}, true},
+		{"ARM::CMSIS@", ID{}, true},
+		{"ARM::../CMSIS@1.0", ID{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseID(tt.s)
+		if tt.wantErr {
+			if !errors.Is(err, errID) {
+				t.Errorf("ParseID() %s error = %v, want %v", tt.s, err, errID)
+			}
+			continue
+		}
+		if err != nil || got != tt.want || got.String() != tt.s {
+			t.Errorf("ParseID() %s = %v, %v, want %v", tt.s, got, err, tt.want)
+		}
+	}
+}
+
+// a pack with an SCVD file
+func packFile(t *testing.T, names ...string) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	w := zip.NewWriter(&b)
+	for _, name := range names {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = f.Write([]byte("<component_viewer/>\n"))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func TestSCVDFiles(t *testing.T) { //nolint:golint,paralleltest
+	rtx := packFile(t, "ARM.CMSIS-RTX.pdsc", "Source/RTX5.scvd", "../evil.scvd")
+	empty := packFile(t, "ARM.Empty.pdsc")
+	var requests []string
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		switch r.URL.Path {
+		case "/index.vidx":
+			_, _ = w.Write([]byte(`<index><vindex><pidx url="` + server.URL + `/arm/" vendor="ARM"/></vindex></index>`))
+		case "/arm/ARM.pidx":
+			_, _ = w.Write([]byte(`<index><pindex>
+<pdsc url="` + server.URL + `/packs/" vendor="ARM" name="CMSIS-RTX" version="5.9.0"/>
+<pdsc url="` + server.URL + `/packs" vendor="ARM" name="Empty" version="1.0.0"/>
+</pindex></index>`))
+		case "/packs/ARM.CMSIS-RTX.5.9.0.pack", "/packs/ARM.CMSIS-RTX.5.8.0.pack":
+			_, _ = w.Write(rtx)
+		case "/packs/ARM.Empty.1.0.0.pack":
+			_, _ = w.Write(empty)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	savedClient, savedDir, savedIndex := fetch.Client, fetch.CacheDir, IndexURL
+	defer func() { fetch.Client, fetch.CacheDir, IndexURL = savedClient, savedDir, savedIndex }()
+	fetch.Client = server.Client()
+	fetch.CacheDir = t.TempDir()
+	IndexURL = server.URL + "/index.vidx"
+
+	tests := []struct {
+		name    string
+		id      ID
+		want    string
+		wantErr error
+	}{
+		{"latest", ID{"ARM", "CMSIS-RTX", ""}, "ARM.CMSIS-RTX.5.9.0", nil},
+		{"version", ID{"ARM", "CMSIS-RTX", "5.8.0"}, "ARM.CMSIS-RTX.5.8.0", nil},
+		{"cached", ID{"ARM", "CMSIS-RTX", "5.8.0"}, "ARM.CMSIS-RTX.5.8.0", nil},
+		{"empty", ID{"ARM", "Empty", ""}, "", errNoSCVD},
+		{"vendor", ID{"Nix", "Pack", ""}, "", errNotFound},
+		{"name", ID{"ARM", "Nix", ""}, "", errNotFound},
+	}
+	for _, tt := range tests { //nolint:golint,paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			requests = nil
+			got, err := SCVDFiles(tt.id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("SCVDFiles() %s error = %v, want %v", tt.name, err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			want := []string{filepath.Join(fetch.CacheDir, "packs", tt.want, "Source", "RTX5.scvd")}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("SCVDFiles() %s = %v, want %v", tt.name, got, want)
+			}
+			if tt.name == "cached" && len(requests) != 0 {
+				t.Errorf("SCVDFiles() %s requests = %v, want none", tt.name, requests)
+			}
+		})
+	}
+	if files, _ := filepath.Glob(filepath.Join(fetch.CacheDir, "*.pack")); len(files) != 0 {
+		t.Errorf("SCVDFiles() packs = %v, want removed", files)
+	}
+}
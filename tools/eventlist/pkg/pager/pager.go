@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pager
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+var errCommand = errors.New("empty pager command")
+
+// DefaultCommand is the pager if $PAGER is not set
+const DefaultCommand = "less"
+
+// Command returns the pager command, $EVENTLIST_PAGER, $PAGER or the default
+// pager, empty if paging is disabled by an empty variable or "cat"
+func Command() string {
+	command, ok := os.LookupEnv("EVENTLIST_PAGER")
+	if !ok {
+		command, ok = os.LookupEnv("PAGER")
+	}
+	if !ok {
+		command = DefaultCommand
+	}
+	command = strings.TrimSpace(command)
+	if command == "cat" {
+		return ""
+	}
+	return command
+}
+
+// Pager pipes the written output through a pager process
+type Pager struct {
+	cmd    *exec.Cmd
+	pipe   io.WriteCloser
+	closed bool // the pager ended, e.g. the user quit before the end
+}
+
+// Start runs the pager command writing to stdout, less is configured like
+// git does unless $LESS is set: quit on one screen, pass colors, keep the screen
+func Start(command string, stdout *os.File) (*Pager, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, errCommand
+	}
+	cmd := exec.Command(args[0], args[1:]...) //nolint:gosec
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		cmd.Env = append(cmd.Env, "LESS=FRX")
+	}
+	if _, ok := os.LookupEnv("LV"); !ok {
+		cmd.Env = append(cmd.Env, "LV=-c")
+	}
+	pipe, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err = cmd.Start(); err != nil {
+		return nil, err
+	}
+	return &Pager{cmd: cmd, pipe: pipe}, nil
+}
+
+// Write passes the output to the pager, the output is discarded
+// after the pager ended
+func (p *Pager) Write(data []byte) (int, error) {
+	if p.closed {
+		return len(data), nil
+	}
+	n, err := p.pipe.Write(data)
+	if errors.Is(err, syscall.EPIPE) || errors.Is(err, os.ErrClosed) {
+		p.closed = true
+		return len(data), nil
+	}
+	return n, err
+}
+
+// Close ends the input of the pager and waits until the user quits it
+func (p *Pager) Close() error {
+	_ = p.pipe.Close()
+	err := p.cmd.Wait()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return nil // the exit code of the pager is not an error of the output
+	}
+	return err
+}
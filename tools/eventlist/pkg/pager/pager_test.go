@@ -0,0 +1,133 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pager
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCommand(t *testing.T) { //nolint:golint,paralleltest
+	tests := []struct {
+		name      string
+		pager     string
+		set       bool
+		eventlist string
+		want      string
+	}{
+		{"unset", "", false, "", DefaultCommand},
+		{"empty", "", true, "", ""},
+		{"cat", "cat", true, "", ""},
+		{"more", " more ", true, "", "more"},
+		{"less -R", "less -R", true, "", "less -R"},
+		{"eventlist", "more", true, "less -S", "less -S"},
+		{"eventlist cat", "more", true, "cat", ""},
+	}
+	for _, tt := range tests { //nolint:golint,paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PAGER", tt.pager)
+			if !tt.set {
+				os.Unsetenv("PAGER")
+			}
+			t.Setenv("EVENTLIST_PAGER", tt.eventlist)
+			if tt.eventlist == "" {
+				os.Unsetenv("EVENTLIST_PAGER")
+			}
+			if got := Command(); got != tt.want {
+				t.Errorf("Command() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStart(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat not available")
+	}
+	name := filepath.Join(t.TempDir(), "out.txt")
+	file, err := os.Create(name)
+	if err != nil {
+		t.Fatalf("os.Create() error = %v", err)
+	}
+	defer file.Close()
+
+	p, err := Start("cat -", file)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	want := strings.Repeat("event\n", 1000)
+	if n, err := p.Write([]byte(want)); n != len(want) || err != nil {
+		t.Errorf("Pager.Write() = %d, %v, want %d, nil", n, err, len(want))
+	}
+	if err = p.Close(); err != nil {
+		t.Errorf("Pager.Close() error = %v", err)
+	}
+	got, _ := os.ReadFile(name)
+	if string(got) != want {
+		t.Errorf("Start() output = %d bytes, want %d", len(got), len(want))
+	}
+}
+
+func TestPager_WriteQuit(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("true"); err != nil {
+		t.Skip("true not available")
+	}
+	p, err := Start("true", os.Stdout)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	_ = p.cmd.Wait() // the user quits the pager before the end of the output
+	data := []byte(strings.Repeat("event\n", 100000))
+	for i := 0; i < 3; i++ {
+		if n, err := p.Write(data); n != len(data) || err != nil {
+			t.Errorf("Pager.Write() = %d, %v, want %d, nil", n, err, len(data))
+		}
+	}
+}
+
+func TestStart_Error(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		command string
+		wantErr error
+	}{
+		{"empty", " ", errCommand},
+		{"nix", "nix-pager-command", exec.ErrNotFound},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := Start(tt.command, os.Stdout); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Start() %s error = %v, want %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+}
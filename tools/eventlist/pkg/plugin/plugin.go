@@ -0,0 +1,241 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package plugin formats the events of ID ranges that cannot be described
+// by SCVD, by decoders compiled into the tool or by decoder processes
+// answering requests on their standard input and output.
+package plugin
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"eventlist/pkg/event"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	errSpec  = errors.New("invalid decoder, want <first>-<last>=<command>")
+	errRange = errors.New("invalid ID range, want <first>-<last>")
+)
+
+// Result is the formatted event, empty component and property keep the
+// default of the event ID
+type Result struct {
+	Component string `json:"component,omitempty"`
+	Property  string `json:"property,omitempty"`
+	Value     string `json:"value"`
+	Level     string `json:"level,omitempty"`
+}
+
+// Decoder formats the events of an ID range, it must be safe for
+// concurrent use
+type Decoder interface {
+	Decode(ev *event.Data) (Result, error)
+}
+
+type entry struct {
+	first, last uint16
+	decoder     Decoder
+}
+
+// Set holds the decoders of the ID ranges, the first added range of an
+// ID is used
+type Set struct {
+	entries []entry
+}
+
+// Add adds a decoder of the IDs first to last
+func (s *Set) Add(first, last uint16, d Decoder) {
+	s.entries = append(s.entries, entry{first, last, d})
+}
+
+// Find returns the decoder of an ID, nil if there is none
+func (s *Set) Find(id uint16) Decoder {
+	if s == nil {
+		return nil
+	}
+	for i := range s.entries {
+		if id >= s.entries[i].first && id <= s.entries[i].last {
+			return s.entries[i].decoder
+		}
+	}
+	return nil
+}
+
+// Close stops the decoder processes
+func (s *Set) Close() error {
+	if s == nil {
+		return nil
+	}
+	var err error
+	for _, e := range s.entries {
+		if c, ok := e.decoder.(io.Closer); ok {
+			if e := c.Close(); err == nil {
+				err = e
+			}
+		}
+	}
+	return err
+}
+
+// Parse parses a decoder process <first>-<last>=<command>, e.g.
+// 0x8000-0x80FF=python3 decode.py, the arguments of the command are
+// separated by spaces, a single ID may be given without -<last>
+func Parse(spec string) (first, last uint16, command []string, err error) {
+	ids, cmd, ok := strings.Cut(spec, "=")
+	command = strings.Fields(cmd)
+	if !ok || len(command) == 0 {
+		return 0, 0, nil, fmt.Errorf("%w: %s", errSpec, spec)
+	}
+	if first, last, err = ParseRange(ids); err != nil {
+		return 0, 0, nil, fmt.Errorf("%w: %s", errSpec, spec)
+	}
+	return first, last, command, nil
+}
+
+// ParseRange parses an ID range <first>-<last>, e.g. 0x8000-0x80FF, a
+// single ID may be given without -<last>
+func ParseRange(ids string) (first, last uint16, err error) {
+	from, to, ranged := strings.Cut(ids, "-")
+	if !ranged {
+		to = from
+	}
+	f, err := strconv.ParseUint(strings.TrimSpace(from), 0, 16)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %s", errRange, ids)
+	}
+	l, err := strconv.ParseUint(strings.TrimSpace(to), 0, 16)
+	if err != nil || l < f {
+		return 0, 0, fmt.Errorf("%w: %s", errRange, ids)
+	}
+	return uint16(f), uint16(l), nil
+}
+
+// Request is sent to a decoder process as one line of JSON, the payload
+// is the data of the record in hex, the values of EventRecord2 and
+// EventRecord4 in memory order
+type Request struct {
+	ID      uint16 `json:"id"`
+	Type    uint16 `json:"type"`
+	Time    uint64 `json:"time"`
+	Payload string `json:"payload"`
+}
+
+// Response is the line of JSON answered by a decoder process
+type Response struct {
+	Result
+	Error string `json:"error,omitempty"`
+}
+
+// Process is a decoder process, every request is answered before the
+// next one is sent
+type Process struct {
+	cmd  *exec.Cmd
+	in   io.WriteCloser
+	out  *bufio.Reader
+	name string
+	mu   sync.Mutex
+	line []byte
+}
+
+// Start starts a decoder process, its standard error is passed through
+func Start(command []string) (*Process, error) {
+	cmd := exec.Command(command[0], command[1:]...) //nolint:gosec
+	cmd.Stderr = os.Stderr
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return &Process{cmd: cmd, in: in, out: bufio.NewReader(out), name: command[0]}, nil
+}
+
+// payload of the record of an event
+func payload(ev *event.Data) []byte {
+	switch ev.Typ {
+	case 1:
+		if ev.Data != nil {
+			return *ev.Data
+		}
+	case 2, 3:
+		values := []int32{ev.Value1, ev.Value2, ev.Value3, ev.Value4}[:2*ev.Typ-2]
+		b := make([]byte, 4*len(values))
+		for i, v := range values {
+			event.ByteOrder.PutUint32(b[4*i:], uint32(v))
+		}
+		return b
+	}
+	return nil
+}
+
+// Decode sends the event to the process and reads the answer
+func (p *Process) Decode(ev *event.Data) (Result, error) {
+	req, err := json.Marshal(Request{ev.Info.ID, ev.Typ, ev.Time, hex.EncodeToString(payload(ev))})
+	if err != nil {
+		return Result{}, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, err := p.in.Write(append(req, '\n')); err != nil {
+		return Result{}, fmt.Errorf("decoder %s: %w", p.name, err)
+	}
+	p.line = p.line[:0]
+	for {
+		part, isPrefix, err := p.out.ReadLine()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return Result{}, fmt.Errorf("decoder %s: %w", p.name, err)
+		}
+		p.line = append(p.line, part...)
+		if !isPrefix {
+			break
+		}
+	}
+	var resp Response
+	if err := json.Unmarshal(p.line, &resp); err != nil {
+		return Result{}, fmt.Errorf("decoder %s: %w", p.name, err)
+	}
+	if len(resp.Error) != 0 {
+		return Result{}, fmt.Errorf("decoder %s: event 0x%04X: %s", p.name, ev.Info.ID, resp.Error)
+	}
+	return resp.Result, nil
+}
+
+// Close closes the standard input of the process and waits for its end
+func (p *Process) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_ = p.in.Close()
+	return p.cmd.Wait()
+}
@@ -0,0 +1,200 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package plugin
+
+import (
+	"bufio"
+	"encoding/json"
+	"eventlist/pkg/event"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestHelperProcess is the decoder process started by the tests, it
+// answers the payload as value
+func TestHelperProcess(t *testing.T) { //nolint:golint,paralleltest
+	if os.Getenv("EVENTLIST_TEST_DECODER") != "1" {
+		return
+	}
+	in := bufio.NewScanner(os.Stdin)
+	for in.Scan() {
+		var req Request
+		if err := json.Unmarshal(in.Bytes(), &req); err != nil {
+			fmt.Println(`{"error":"bad request"}`)
+			continue
+		}
+		switch req.ID {
+		case 0x80FF:
+			fmt.Println(`{"error":"unknown event"}`)
+		case 0x80FE:
+			os.Exit(0)
+		default:
+			fmt.Printf(`{"component":"Vendor","value":"%d:%s","level":"Op"}`+"\n", req.Type, req.Payload)
+		}
+	}
+	os.Exit(0)
+}
+
+func startHelper(t *testing.T) *Process {
+	t.Helper()
+	t.Setenv("EVENTLIST_TEST_DECODER", "1")
+	p, err := Start([]string{os.Args[0], "-test.run=TestHelperProcess"})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	return p
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		spec        string
+		first, last uint16
+		command     []string
+		wantErr     bool
+	}{
+		{"range", "0x8000-0x80FF=python3 decode.py", 0x8000, 0x80FF, []string{"python3", "decode.py"}, false},
+		{"single", "0x8001=./dec", 0x8001, 0x8001, []string{"./dec"}, false},
+		{"decimal", "10 - 20=dec -v", 10, 20, []string{"dec", "-v"}, false},
+		{"no command", "0x8000-0x80FF=", 0, 0, nil, true},
+		{"no ids", "./dec", 0, 0, nil, true},
+		{"reversed", "0x80FF-0x8000=dec", 0, 0, nil, true},
+		{"too large", "0x8000-0x10000=dec", 0, 0, nil, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			first, last, command, err := Parse(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if first != tt.first || last != tt.last || !reflect.DeepEqual(command, tt.command) {
+				t.Errorf("Parse() %s = %x, %x, %v, want %x, %x, %v", tt.name, first, last, command, tt.first, tt.last, tt.command)
+			}
+		})
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		ids         string
+		first, last uint16
+		wantErr     bool
+	}{
+		{"range", "0xA100-0xA1FF", 0xA100, 0xA1FF, false},
+		{"single", "0xA105", 0xA105, 0xA105, false},
+		{"empty", "", 0, 0, true},
+		{"reversed", "0xA1FF-0xA100", 0, 0, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			first, last, err := ParseRange(tt.ids)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRange() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if first != tt.first || last != tt.last {
+				t.Errorf("ParseRange() %s = %x, %x, want %x, %x", tt.name, first, last, tt.first, tt.last)
+			}
+		})
+	}
+}
+
+type fixed string
+
+func (f fixed) Decode(*event.Data) (Result, error) {
+	return Result{Value: string(f)}, nil
+}
+
+func TestSet(t *testing.T) {
+	t.Parallel()
+
+	var none *Set
+	if none.Find(0x8000) != nil || none.Close() != nil {
+		t.Errorf("Set.Find() of nil = decoder, want nil")
+	}
+	var s Set
+	s.Add(0x8000, 0x80FF, fixed("a"))
+	s.Add(0x8000, 0x8000, fixed("b"))
+	s.Add(0x9000, 0x9000, fixed("c"))
+	tests := []struct {
+		id   uint16
+		want Decoder
+	}{
+		{0x8000, fixed("a")}, {0x80FF, fixed("a")}, {0x9000, fixed("c")}, {0x7FFF, nil}, {0x8100, nil},
+	}
+	for _, tt := range tests {
+		if got := s.Find(tt.id); got != tt.want {
+			t.Errorf("Set.Find() 0x%04X = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestProcess(t *testing.T) { //nolint:golint,paralleltest
+	p := startHelper(t)
+	data := []byte("abc")
+	tests := []struct {
+		name    string
+		ev      event.Data
+		want    Result
+		wantErr string
+	}{
+		{"data", event.Data{Typ: 1, Data: &data, Info: event.Info{ID: 0x8000}},
+			Result{Component: "Vendor", Value: "1:616263", Level: "Op"}, ""},
+		{"record2", event.Data{Typ: 2, Value1: 1, Value2: -1, Info: event.Info{ID: 0x8001}},
+			Result{Component: "Vendor", Value: "2:01000000ffffffff", Level: "Op"}, ""},
+		{"record4", event.Data{Typ: 3, Value4: 0x100, Info: event.Info{ID: 0x8001}},
+			Result{Component: "Vendor", Value: "3:000000000000000000000000" + "00010000", Level: "Op"}, ""},
+		{"error", event.Data{Typ: 2, Info: event.Info{ID: 0x80FF}}, Result{}, "event 0x80FF: unknown event"},
+		{"exit", event.Data{Typ: 2, Info: event.Info{ID: 0x80FE}}, Result{}, "unexpected EOF"},
+	}
+	for _, tt := range tests {
+		got, err := p.Decode(&tt.ev)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Process.Decode() %s error = %v, want %s", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Process.Decode() %s = %v, %v, want %v", tt.name, got, err, tt.want)
+		}
+	}
+	if err := p.Close(); err != nil {
+		t.Errorf("Process.Close() error = %v", err)
+	}
+
+	var s Set
+	s.Add(0x8000, 0x80FF, startHelper(t))
+	if err := s.Close(); err != nil {
+		t.Errorf("Set.Close() error = %v", err)
+	}
+}
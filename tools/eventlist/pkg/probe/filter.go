@@ -0,0 +1,130 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package probe
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// levels of the event filter, the bits of the recording argument of
+// EventRecorderEnable
+var filterLevels = map[string]uint8{"Error": 1, "API": 2, "Op": 4, "Detail": 8, "all": 15}
+
+// Filter enables or disables the recording of the events of levels and a
+// component range on the target, as EventRecorderEnable and
+// EventRecorderDisable
+type Filter struct {
+	Enable bool
+	Levels uint8 // bit 0: Error, 1: API, 2: Op, 3: Detail
+	First  uint8 // first component
+	Last   uint8 // last component
+}
+
+// ParseFilter parses a filter "[+|-]<levels>:<components>". The levels are
+// Error, API, Op and Detail separated by |, or all; the components are
+// <first>[-<last>] or all, e.g. "Op|Detail:0x80-0x8F" enables and
+// "-API:all" disables the events.
+func ParseFilter(spec string) (Filter, error) {
+	f := Filter{Enable: true}
+	s := strings.TrimSpace(spec)
+	if rest, ok := strings.CutPrefix(s, "-"); ok {
+		f.Enable = false
+		s = rest
+	} else {
+		s = strings.TrimPrefix(s, "+")
+	}
+	levels, comps, ok := strings.Cut(s, ":")
+	if !ok {
+		return Filter{}, fmt.Errorf("invalid filter: %s: missing components", spec)
+	}
+	for _, l := range strings.Split(levels, "|") {
+		bit, ok := filterLevels[strings.TrimSpace(l)]
+		if !ok {
+			return Filter{}, fmt.Errorf("invalid filter: %s: unknown level %s", spec, l)
+		}
+		f.Levels |= bit
+	}
+	comps = strings.TrimSpace(comps)
+	if comps == "all" {
+		f.First, f.Last = 0, 0xFE
+		return f, nil
+	}
+	first, last, ok := strings.Cut(comps, "-")
+	if !ok {
+		last = first
+	}
+	for _, c := range []struct {
+		s string
+		v *uint8
+	}{{first, &f.First}, {last, &f.Last}} {
+		v, err := strconv.ParseUint(strings.TrimSpace(c.s), 0, 8)
+		if err != nil || v == 0xFF {
+			return Filter{}, fmt.Errorf("invalid filter: %s: invalid component %s", spec, c.s)
+		}
+		*c.v = uint8(v)
+	}
+	if f.First > f.Last {
+		return Filter{}, fmt.Errorf("invalid filter: %s: reversed components", spec)
+	}
+	return f, nil
+}
+
+// apply the filter to the 128 bytes of EventFilter: 32 bytes per level
+// with a bit per component
+func (f Filter) apply(filter []byte) {
+	for level := 0; level < 4; level++ {
+		if f.Levels&(1<<level) == 0 {
+			continue
+		}
+		for c := int(f.First); c <= int(f.Last); c++ {
+			bit := &filter[32*level+(c>>3)]
+			if f.Enable {
+				*bit |= 1 << (c & 7)
+			} else {
+				*bit &^= 1 << (c & 7)
+			}
+		}
+	}
+}
+
+// SetFilter changes the event filter of the target, the filter is set
+// again when the Event Recorder is initialized, e.g. after a reset
+func (r *Reader) SetFilter(f Filter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.filters = append(r.filters, f)
+	return r.applyFilters()
+}
+
+// apply the filters set to the event filter of the target
+func (r *Reader) applyFilters() error {
+	if len(r.filters) == 0 {
+		return nil
+	}
+	var filter [128]byte
+	if err := r.mem.Read(r.info.Filter, filter[:]); err != nil {
+		return err
+	}
+	for _, f := range r.filters {
+		f.apply(filter[:])
+	}
+	return r.mem.Write(r.info.Filter, filter[:])
+}
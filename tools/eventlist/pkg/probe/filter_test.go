@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package probe
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseFilter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		spec    string
+		want    Filter
+		wantErr bool
+	}{
+		{"Op|Detail:0x80-0x8F", Filter{true, 12, 0x80, 0x8F}, false},
+		{"+Error:5", Filter{true, 1, 5, 5}, false},
+		{"-API:all", Filter{false, 2, 0, 0xFE}, false},
+		{" all : 0x10 - 0x20 ", Filter{true, 15, 0x10, 0x20}, false},
+		{"Op", Filter{}, true},
+		{"Trace:all", Filter{}, true},
+		{"Op:0xFF", Filter{}, true},
+		{"Op:0x100", Filter{}, true},
+		{"Op:x", Filter{}, true},
+		{"Op:0x20-0x10", Filter{}, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.spec, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseFilter(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseFilter() %s error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseFilter() %s = %+v, want %+v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReader_SetFilter(t *testing.T) {
+	t.Parallel()
+
+	tg := newTarget(1000)
+	r, err := NewReader(tg, infoAddr)
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	for _, f := range []Filter{{true, 15, 0, 0xFE}, {false, 12, 0x80, 0x8A}, {true, 8, 0x81, 0x81}} {
+		if err := r.SetFilter(f); err != nil {
+			t.Errorf("Reader.SetFilter() error = %v", err)
+		}
+	}
+	want := bytes.Repeat([]byte{0xFF}, 128)
+	for _, i := range []int{31, 63, 95, 127} {
+		want[i] = 0x7F // component 0xFF
+	}
+	want[64+16], want[64+17] = 0, 0xF8
+	want[96+16], want[96+17] = 0x02, 0xF8
+	if got := tg.mem[0x80 : 0x80+128]; !bytes.Equal(got, want) {
+		t.Errorf("Reader.SetFilter() = %x, want %x", got, want)
+	}
+	tg.fail = errClosed
+	if err := r.SetFilter(Filter{}); err != errClosed {
+		t.Errorf("Reader.SetFilter() error = %v, want %v", err, errClosed)
+	}
+}
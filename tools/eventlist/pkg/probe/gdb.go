@@ -0,0 +1,225 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package probe
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Timeout of an answer of the GDB server
+var Timeout = 5 * time.Second
+
+// DefaultReset is the monitor command of the GDB server resetting the
+// target and letting it run, "reset run" of OpenOCD
+const DefaultReset = "reset run"
+
+// maxPacket is the largest read of one packet, the hex answer of 2048 bytes
+// fits the packet size of common GDB servers
+const maxPacket = 2048
+
+// GDB accesses the memory of the target with the remote serial protocol of a
+// GDB server, e.g. of ST-LINK, J-Link or OpenOCD
+type GDB struct {
+	conn  net.Conn
+	in    *bufio.Reader
+	buf   []byte
+	reset string // monitor command of Reset
+}
+
+// OpenGDB connects to the GDB server at host:port, the target keeps
+// running, the server must not halt it on the connection. The query
+// "?reset=<command>" sets the monitor command of Reset, e.g.
+// "localhost:61234?reset=reset" for the ST-LINK GDB server.
+func OpenGDB(addr string) (*GDB, error) {
+	reset := DefaultReset
+	if host, query, ok := strings.Cut(addr, "?"); ok {
+		values, err := url.ParseQuery(query)
+		if err != nil {
+			return nil, err
+		}
+		if values.Has("reset") {
+			reset = values.Get("reset")
+		}
+		addr = host
+	}
+	conn, err := net.DialTimeout("tcp", addr, Timeout)
+	if err != nil {
+		return nil, err
+	}
+	return &GDB{conn: conn, in: bufio.NewReaderSize(conn, 2*maxPacket+64), reset: reset}, nil
+}
+
+// Read reads the memory at addr
+func (g *GDB) Read(addr uint32, b []byte) error {
+	for len(b) != 0 {
+		n := len(b)
+		if n > maxPacket {
+			n = maxPacket
+		}
+		answer, err := g.command(fmt.Sprintf("m%x,%x", addr, n))
+		if err != nil {
+			return fmt.Errorf("gdb: %w", err)
+		}
+		if len(answer) == 3 && answer[0] == 'E' {
+			return fmt.Errorf("gdb: read 0x%08X: error %s", addr, answer[1:])
+		}
+		if len(answer) != 2*n {
+			return fmt.Errorf("gdb: read 0x%08X: invalid answer %.40q", addr, answer)
+		}
+		if _, err := hex.Decode(b[:n], answer); err != nil {
+			return fmt.Errorf("gdb: read 0x%08X: %w", addr, err)
+		}
+		addr += uint32(n)
+		b = b[n:]
+	}
+	return nil
+}
+
+// Write writes the memory at addr
+func (g *GDB) Write(addr uint32, b []byte) error {
+	for len(b) != 0 {
+		n := len(b)
+		if n > maxPacket/2 {
+			n = maxPacket / 2
+		}
+		answer, err := g.command(fmt.Sprintf("M%x,%x:%x", addr, n, b[:n]))
+		if err != nil {
+			return fmt.Errorf("gdb: %w", err)
+		}
+		if string(answer) != "OK" {
+			return fmt.Errorf("gdb: write 0x%08X: error %s", addr, strings.TrimPrefix(string(answer), "E"))
+		}
+		addr += uint32(n)
+		b = b[n:]
+	}
+	return nil
+}
+
+// Reset resets the target with the monitor command of the reset
+func (g *GDB) Reset() error {
+	answer, err := g.command("qRcmd," + hex.EncodeToString([]byte(g.reset)))
+	// the output of the command precedes the result
+	for err == nil && len(answer) > 1 && answer[0] == 'O' && string(answer) != "OK" {
+		answer, _, err = g.packet()
+	}
+	if err != nil {
+		return fmt.Errorf("gdb: %w", err)
+	}
+	if string(answer) != "OK" {
+		return fmt.Errorf("gdb: monitor %s: failed %q", g.reset, answer)
+	}
+	return nil
+}
+
+// command sends a packet and returns the answer
+func (g *GDB) command(cmd string) ([]byte, error) {
+	var sum byte
+	for i := 0; i < len(cmd); i++ {
+		sum += cmd[i]
+	}
+	if err := g.conn.SetDeadline(time.Now().Add(Timeout)); err != nil {
+		return nil, err
+	}
+	if _, err := fmt.Fprintf(g.conn, "$%s#%02x", cmd, sum); err != nil {
+		return nil, err
+	}
+	for {
+		answer, resend, err := g.packet()
+		if err != nil || !resend {
+			return answer, err
+		}
+		if _, err := fmt.Fprintf(g.conn, "$%s#%02x", cmd, sum); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// packet reads the next packet and acknowledges it, resend is true if the
+// server requests the command again
+func (g *GDB) packet() (answer []byte, resend bool, err error) {
+	for {
+		var c byte
+		if c, err = g.in.ReadByte(); err != nil {
+			return nil, false, err
+		}
+		if c == '-' {
+			return nil, true, nil
+		}
+		if c != '$' {
+			continue // the acknowledgment of the command
+		}
+		var data []byte
+		data, err = g.in.ReadSlice('#')
+		if err != nil {
+			return nil, false, err
+		}
+		var check [2]byte
+		if _, err := io.ReadFull(g.in, check[:]); err != nil {
+			return nil, false, err
+		}
+		data = data[:len(data)-1]
+		var sum byte
+		for _, c := range data {
+			sum += c
+		}
+		if fmt.Sprintf("%02x", sum) != strings.ToLower(string(check[:])) {
+			if _, err := g.conn.Write([]byte{'-'}); err != nil {
+				return nil, false, err
+			}
+			continue
+		}
+		if _, err := g.conn.Write([]byte{'+'}); err != nil {
+			return nil, false, err
+		}
+		answer, err = g.expand(data)
+		return answer, false, err
+	}
+}
+
+// expand the run-length encoding of an answer: "c*n" repeats c n-29 times
+func (g *GDB) expand(data []byte) ([]byte, error) {
+	g.buf = g.buf[:0]
+	for i := 0; i < len(data); i++ {
+		if data[i] != '*' {
+			g.buf = append(g.buf, data[i])
+			continue
+		}
+		if len(g.buf) == 0 || i+1 == len(data) || data[i+1] < 29 {
+			return nil, errors.New("invalid run-length encoding")
+		}
+		i++
+		for n := int(data[i]) - 29; n > 0; n-- {
+			g.buf = append(g.buf, g.buf[len(g.buf)-1])
+		}
+	}
+	return g.buf, nil
+}
+
+// Close closes the connection, the target keeps running
+func (g *GDB) Close() error {
+	return g.conn.Close()
+}
@@ -0,0 +1,233 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package probe
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+)
+
+// gdbServer answers the memory reads as a GDB server, the memory at an
+// address holds the low byte of the address. The answers of some addresses
+// test the protocol.
+func gdbServer(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		in := bufio.NewReader(conn)
+		send := func(answer, check string) {
+			if len(check) == 0 {
+				var sum byte
+				for i := 0; i < len(answer); i++ {
+					sum += answer[i]
+				}
+				check = fmt.Sprintf("%02x", sum)
+			}
+			fmt.Fprintf(conn, "+$%s#%s", answer, check)
+		}
+		resent := false
+		for {
+			if _, err := in.ReadString('$'); err != nil {
+				return
+			}
+			packet, err := in.ReadString('#')
+			if err != nil {
+				return
+			}
+			var check [2]byte
+			if _, err := in.Read(check[:]); err != nil {
+				return
+			}
+			var addr uint32
+			var n int
+			if cmd, ok := strings.CutPrefix(packet, "qRcmd,"); ok {
+				if cmd == hex.EncodeToString([]byte("reset run"))+"#" {
+					send("O"+hex.EncodeToString([]byte("resetting\n")), "")
+					_, _ = in.ReadByte() // '+'
+					send("OK", "")
+				} else {
+					send("", "")
+				}
+				continue
+			}
+			if _, err := fmt.Sscanf(packet, "M%x,%x:", &addr, &n); err == nil {
+				if addr == 0 || len(packet) != strings.Index(packet, ":")+2*n+2 {
+					send("E02", "")
+				} else {
+					send("OK", "")
+				}
+				continue
+			}
+			if _, err := fmt.Sscanf(packet, "m%x,%x#", &addr, &n); err != nil {
+				send("", "")
+				continue
+			}
+			switch addr {
+			case 0:
+				send("E01", "")
+			case 1:
+				send("0*\"", "") // "0" and 5 more
+			case 2:
+				send("01", "")
+			case 3:
+				if !resent {
+					resent = true
+					fmt.Fprint(conn, "-")
+					continue
+				}
+				send("03040506", "")
+			case 4:
+				if !resent {
+					resent = true
+					send("04050607", "00")
+					_, _ = in.ReadByte() // '-'
+				}
+				send("04050607", "")
+			default:
+				var b strings.Builder
+				for i := 0; i < n; i++ {
+					fmt.Fprintf(&b, "%02x", byte(addr+uint32(i)))
+				}
+				send(b.String(), "")
+			}
+		}
+	}()
+	return l.Addr().String()
+}
+
+func TestGDB(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		addr    uint32
+		n       int
+		wantErr string
+	}{
+		{"read", 0x20000010, 16, ""},
+		{"packets", 0x20000000, 3000, ""},
+		{"error", 0, 4, "gdb: read 0x00000000: error 01"},
+		{"run-length", 1, 3, ""},
+		{"invalid", 2, 4, "gdb: read 0x00000002: invalid answer \"01\""},
+		{"resend", 3, 4, ""},
+		{"checksum", 4, 4, ""},
+	}
+	g, err := OpenGDB(gdbServer(t))
+	if err != nil {
+		t.Fatalf("OpenGDB() error = %v", err)
+	}
+	defer g.Close()
+	for _, tt := range tests {
+		b := make([]byte, tt.n)
+		err := g.Read(tt.addr, b)
+		if (err == nil && tt.wantErr != "") || (err != nil && err.Error() != tt.wantErr) {
+			t.Errorf("GDB.Read() %s error = %v, want %s", tt.name, err, tt.wantErr)
+			continue
+		}
+		want := make([]byte, tt.n)
+		for i := range want {
+			want[i] = byte(tt.addr + uint32(i))
+		}
+		if tt.addr == 1 {
+			want = []byte{0, 0, 0}
+		}
+		if err == nil && !bytes.Equal(b, want) {
+			t.Errorf("GDB.Read() %s = %x, want %x", tt.name, b, want)
+		}
+	}
+	if err := g.Write(0x20000000, make([]byte, 1500)); err != nil {
+		t.Errorf("GDB.Write() error = %v", err)
+	}
+	if err := g.Write(0, []byte{1}); err == nil || err.Error() != "gdb: write 0x00000000: error 02" {
+		t.Errorf("GDB.Write() error = %v, want error 02", err)
+	}
+	if err := g.Reset(); err != nil {
+		t.Errorf("GDB.Reset() error = %v", err)
+	}
+	g.reset = "reset halt"
+	if err := g.Reset(); err == nil || err.Error() != "gdb: monitor reset halt: failed \"\"" {
+		t.Errorf("GDB.Reset() error = %v, want failed", err)
+	}
+	if _, err := OpenGDB("127.0.0.1:0"); err == nil {
+		t.Errorf("OpenGDB() error = nil, want error")
+	}
+}
+
+func TestOpenGDB(t *testing.T) {
+	t.Parallel()
+
+	addr := gdbServer(t)
+	tests := []struct {
+		addr      string
+		wantReset string
+		wantErr   bool
+	}{
+		{addr + "?reset=reset", "reset", false},
+		{addr + "?reset=%zz", "", true},
+	}
+	for _, tt := range tests {
+		g, err := OpenGDB(tt.addr)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("OpenGDB() %s error = %v, wantErr %v", tt.addr, err, tt.wantErr)
+		}
+		if err == nil {
+			if g.reset != tt.wantReset {
+				t.Errorf("OpenGDB() %s reset = %q, want %q", tt.addr, g.reset, tt.wantReset)
+			}
+			g.Close()
+		}
+	}
+}
+
+func TestGDB_expand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		data    string
+		want    string
+		wantErr bool
+	}{
+		{"0102", "0102", false},
+		{"0* ", "0000", false},
+		{"ab*#cd", "abbbbbbbcd", false},
+		{"*#", "", true},
+		{"0*", "", true},
+	}
+	for _, tt := range tests {
+		var g GDB
+		got, err := g.expand([]byte(tt.data))
+		if (err != nil) != tt.wantErr || (!tt.wantErr && string(got) != tt.want) {
+			t.Errorf("GDB.expand() %q = %q, %v, want %q", tt.data, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,400 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package probe reads the Event Recorder buffer of a running target with a
+// debug probe. The records of the buffer are converted to the records of an
+// event file while they are written by the target, so a probe is a live
+// source of the decoder.
+package probe
+
+import (
+	"bytes"
+	"errors"
+	"eventlist/pkg/event"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Symbol is the symbol of the Event Recorder information in the ELF file
+const Symbol = "EventRecorderInfo"
+
+// PollInterval is the time between the reads of the Event Recorder status
+var PollInterval = 100 * time.Millisecond
+
+var errClosed = errors.New("probe closed")
+
+// Memory reads and writes the memory of the target
+type Memory interface {
+	Read(addr uint32, p []byte) error
+	Write(addr uint32, p []byte) error
+	Reset() error // reset the target and let it run
+	Close() error
+}
+
+// bits of the record information
+const (
+	recordDlenPos = 16
+	recordIRQ     = 0x00080000
+	recordSeqPos  = 20
+	recordFirst   = 0x01000000
+	recordLast    = 0x02000000
+	recordLocked  = 0x04000000
+	recordValid   = 0x08000000
+	recordMsbTS   = 0x10000000
+	recordMsbVal1 = 0x20000000
+	recordMsbVal2 = 0x40000000
+	recordTBit    = 0x80000000
+)
+
+// maxLocked is the number of records the target skips at most while they
+// are locked, a record before them that is not valid is not written anymore
+const maxLocked = 7
+
+// maxBackStep is the largest step back of the timestamps of two records not
+// taken as overflow of the timestamp
+const maxBackStep = 1 << 24
+
+// Info is the EventRecorderInfo of the target
+type Info struct {
+	Version uint16 // protocol version: major.minor
+	Count   uint32 // number of records of the buffer
+	Buffer  uint32 // address of the records
+	Filter  uint32 // address of the event filter
+	Status  uint32 // address of the status
+}
+
+// status of the Event Recorder
+type status struct {
+	index uint32 // index of the next record
+	freq  uint32 // timestamp frequency
+}
+
+// a data or four value event of several records
+type pending struct {
+	id   uint16
+	at   uint64 // time of the first record
+	irq  bool
+	data []byte
+}
+
+// Reader returns the events of the Event Recorder buffer as records of an
+// event file. The first record is an EventRecorderInitialize event with the
+// timestamp frequency of the target.
+type Reader struct {
+	mu      sync.Mutex // memory access
+	mem     Memory
+	info    Info
+	next    uint32 // index of the next record to read
+	high    uint64 // timestamp bits above 32
+	lastTS  uint32
+	pending [8]*pending // by context
+	buf     []byte      // the read records
+	out     bytes.Buffer
+	w       *event.Writer
+	filters []Filter // filters set, set again after an initialization
+	done    chan struct{}
+	once    sync.Once
+	lost    int64
+	gap     int32 // lost records not yet marked by an IDGap event
+}
+
+// ReadInfo reads the EventRecorderInfo at an address
+func ReadInfo(mem Memory, addr uint32) (Info, error) {
+	var b [20]byte
+	if err := mem.Read(addr, b[:]); err != nil {
+		return Info{}, err
+	}
+	order := event.ByteOrder
+	info := Info{
+		Version: order.Uint16(b[2:]),
+		Count:   order.Uint32(b[4:]),
+		Buffer:  order.Uint32(b[8:]),
+		Filter:  order.Uint32(b[12:]),
+		Status:  order.Uint32(b[16:]),
+	}
+	if b[0] != 1 || info.Version>>8 != 1 {
+		return Info{}, fmt.Errorf("no Event Recorder at 0x%08X: protocol %d version 0x%04X", addr, b[0], info.Version)
+	}
+	if info.Count < 8 || info.Count&(info.Count-1) != 0 {
+		return Info{}, fmt.Errorf("invalid Event Recorder buffer of %d records", info.Count)
+	}
+	return info, nil
+}
+
+// NewReader reads the events of the Event Recorder with the information at
+// addr, the records in the buffer are read first
+func NewReader(mem Memory, addr uint32) (*Reader, error) {
+	info, err := ReadInfo(mem, addr)
+	if err != nil {
+		return nil, err
+	}
+	r := &Reader{mem: mem, info: info, done: make(chan struct{})}
+	r.w = event.NewWriter(&r.out)
+	st, err := r.status()
+	if err != nil {
+		return nil, err
+	}
+	if st.index > info.Count {
+		r.next = st.index - info.Count
+	}
+	if err := r.w.Clock(0, st.freq); err != nil {
+		return nil, err
+	}
+	return r, r.w.Flush()
+}
+
+// Info returns the EventRecorderInfo of the target
+func (r *Reader) Info() Info {
+	return r.info
+}
+
+// read the status of the Event Recorder
+func (r *Reader) status() (status, error) {
+	var b [36]byte
+	if err := r.mem.Read(r.info.Status, b[:]); err != nil {
+		return status{}, err
+	}
+	order := event.ByteOrder
+	return status{index: order.Uint32(b[4:]), freq: order.Uint32(b[20:])}, nil
+}
+
+// Lost returns the number of records overwritten before they were read
+func (r *Reader) Lost() int64 {
+	return atomic.LoadInt64(&r.lost)
+}
+
+// Read returns the records of the events, it waits for new events of the target
+func (r *Reader) Read(p []byte) (int, error) {
+	for r.out.Len() == 0 {
+		select {
+		case <-r.done:
+			return 0, io.EOF
+		default:
+		}
+		n, err := r.poll()
+		if err != nil {
+			return 0, err
+		}
+		if n != 0 {
+			continue
+		}
+		select {
+		case <-r.done:
+			return 0, io.EOF
+		case <-time.After(PollInterval):
+		}
+	}
+	return r.out.Read(p)
+}
+
+// Reset resets the target and lets it run. The records in the buffer are
+// skipped, the next events are the events of the boot after an event
+// IDReset marking the reset.
+func (r *Reader) Reset() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	st, err := r.status()
+	if err != nil {
+		return err
+	}
+	r.next = st.index
+	r.pending = [8]*pending{}
+	if err := r.w.EventRecord2(r.high|uint64(r.lastTS), event.IDReset, 0, 0); err != nil {
+		return err
+	}
+	if err := r.w.Flush(); err != nil {
+		return err
+	}
+	return r.mem.Reset()
+}
+
+// Close ends the reading and closes the memory access
+func (r *Reader) Close() error {
+	err := errClosed
+	r.once.Do(func() {
+		close(r.done)
+		err = r.mem.Close()
+	})
+	return err
+}
+
+// poll reads the records written since the last poll and returns their number
+func (r *Reader) poll() (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	st, err := r.status()
+	if err != nil {
+		return 0, err
+	}
+	if int32(st.index-r.next) < 0 {
+		// the target was reset, the index restarts
+		r.next, r.high, r.lastTS = 0, 0, 0
+		r.pending = [8]*pending{}
+	}
+	if st.index-r.next > r.info.Count {
+		// the records were overwritten before they were read
+		skip := st.index - r.next - r.info.Count
+		r.lose(int32(skip))
+		r.next += skip
+		r.pending = [8]*pending{}
+	}
+	n := 0
+	for r.next != st.index {
+		// read up to the end of the buffer at once
+		pos := r.next & (r.info.Count - 1)
+		count := st.index - r.next
+		if count > r.info.Count-pos {
+			count = r.info.Count - pos
+		}
+		if cap(r.buf) < int(16*count) {
+			r.buf = make([]byte, 16*count)
+		}
+		buf := r.buf[:16*count]
+		if err := r.mem.Read(r.info.Buffer+16*pos, buf); err != nil {
+			return n, err
+		}
+		for ; len(buf) != 0; buf = buf[16:] {
+			ok, err := r.record(buf[:16], st.index)
+			if err != nil {
+				return n, err
+			}
+			if !ok {
+				return n, r.w.Flush()
+			}
+			r.next++
+			n++
+		}
+	}
+	return n, r.w.Flush()
+}
+
+// record converts the record at the index next, false if it is not written
+// yet; a record that is not written anymore is skipped
+func (r *Reader) record(b []byte, index uint32) (bool, error) {
+	order := event.ByteOrder
+	ts, val1, val2, info := order.Uint32(b), order.Uint32(b[4:]), order.Uint32(b[8:]), order.Uint32(b[12:])
+	seq := (r.next / r.info.Count) & 0xF
+	tbit := info & recordTBit
+	if info&recordValid == 0 || info&recordLocked != 0 || (info>>recordSeqPos)&0xF != seq ||
+		ts&recordTBit != tbit || val1&recordTBit != tbit || val2&recordTBit != tbit {
+		if index-r.next <= maxLocked {
+			return false, nil
+		}
+		r.lose(1)
+		return true, nil
+	}
+	ts = ts&^recordTBit | (info&recordMsbTS)<<3
+	val1 = val1&^recordTBit | (info&recordMsbVal1)<<2
+	val2 = val2&^recordTBit | (info&recordMsbVal2)<<1
+	return true, r.event(ts, val1, val2, info)
+}
+
+// event assembles the events of the records
+func (r *Reader) event(ts, val1, val2, info uint32) error {
+	id := uint16(info)
+	irq := info&recordIRQ != 0
+	// data length of a single record, context of several records
+	ctx := (info >> recordDlenPos) & 7
+	var values [8]byte
+	order := event.ByteOrder
+	order.PutUint32(values[:], val1)
+	order.PutUint32(values[4:], val2)
+	first, last := info&recordFirst != 0, info&recordLast != 0
+	if id == event.IDInitialize {
+		// the timestamp restarts with the initialization, it is no overflow
+		r.lastTS = ts
+	}
+	at := r.time(ts)
+	switch {
+	case first && last && ctx == 0:
+		if err := r.write(&event.Data{Typ: 2, Value1: int32(val1), Value2: int32(val2)}, id, at, irq); err != nil {
+			return err
+		}
+		if id == event.IDInitialize {
+			// the initialization resets the event filter
+			return r.applyFilters()
+		}
+		return nil
+	case first && last:
+		e := &event.Data{}
+		e.SetPayload(append([]byte{}, values[:ctx]...))
+		return r.write(e, id, at, irq)
+	case first:
+		r.pending[ctx] = &pending{id: id, at: at, irq: irq, data: append([]byte{}, values[:]...)}
+		return nil
+	case !last && id>>8 != 0xFF:
+		// data event without data
+		e := &event.Data{}
+		e.SetPayload(nil)
+		return r.write(e, id, at, irq)
+	}
+	p := r.pending[ctx]
+	if p == nil {
+		r.lose(1)
+		return nil
+	}
+	if !last {
+		p.data = append(p.data, values[:]...)
+		return nil
+	}
+	r.pending[ctx] = nil
+	if length := id >> 8; length != 0 && length <= 8 {
+		e := &event.Data{}
+		e.SetPayload(append(p.data, values[:length]...))
+		return r.write(e, p.id, p.at, p.irq)
+	}
+	first1, first2 := order.Uint32(p.data), order.Uint32(p.data[4:])
+	e := &event.Data{Typ: 3, Value1: int32(first1), Value2: int32(first2), Value3: int32(val1), Value4: int32(val2)}
+	return r.write(e, p.id, p.at, p.irq)
+}
+
+// time returns the 64 bit time of a timestamp. The records are read in
+// the order of the buffer, a record of an interrupt may be written before
+// the record of the interrupted code with an older timestamp.
+func (r *Reader) time(ts uint32) uint64 {
+	if ts < r.lastTS && r.lastTS-ts > maxBackStep {
+		r.high += 1 << 32
+		r.lastTS = ts
+	} else if ts > r.lastTS {
+		r.lastTS = ts
+	}
+	return r.high | uint64(ts)
+}
+
+// lose counts lost records, they are marked before the next event
+func (r *Reader) lose(n int32) {
+	atomic.AddInt64(&r.lost, int64(n))
+	r.gap += n
+}
+
+// write the record of an event, after an IDGap event if records were lost
+func (r *Reader) write(e *event.Data, id uint16, at uint64, irq bool) error {
+	if r.gap != 0 {
+		if err := r.w.EventRecord2(at, event.IDGap, r.gap, 0); err != nil {
+			return err
+		}
+		r.gap = 0
+	}
+	e.Time = at
+	e.Info.ID = id
+	e.Info.SetIRQ(irq)
+	return r.w.Write(e)
+}
@@ -0,0 +1,345 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package probe
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"eventlist/pkg/event"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// addresses of the Event Recorder of the simulated target
+const (
+	infoAddr   = 0x20000000
+	statusAddr = 0x20000040
+	bufferAddr = 0x20000100
+	testCount  = 8
+)
+
+// target simulates the recording of the events of EventRecorder.c
+type target struct {
+	mem    []byte // from infoAddr
+	index  uint32
+	ctx    uint32
+	fail   error
+	resets int
+}
+
+func newTarget(freq uint32) *target {
+	t := &target{mem: make([]byte, bufferAddr-infoAddr+16*testCount)}
+	le := binary.LittleEndian
+	t.mem[0] = 1
+	le.PutUint16(t.mem[2:], 0x0101)
+	le.PutUint32(t.mem[4:], testCount)
+	le.PutUint32(t.mem[8:], bufferAddr)
+	le.PutUint32(t.mem[12:], 0x20000080)
+	le.PutUint32(t.mem[16:], statusAddr)
+	le.PutUint32(t.mem[statusAddr-infoAddr+20:], freq)
+	return t
+}
+
+func (t *target) Read(addr uint32, p []byte) error {
+	if t.fail != nil {
+		return t.fail
+	}
+	if addr < infoAddr || int(addr-infoAddr)+len(p) > len(t.mem) {
+		return errors.New("no memory")
+	}
+	copy(p, t.mem[addr-infoAddr:])
+	return nil
+}
+
+func (t *target) Write(addr uint32, p []byte) error {
+	if addr < infoAddr || int(addr-infoAddr)+len(p) > len(t.mem) {
+		return errors.New("no memory")
+	}
+	copy(t.mem[addr-infoAddr:], p)
+	return nil
+}
+
+func (t *target) Reset() error {
+	t.resets++
+	return t.fail
+}
+
+func (t *target) Close() error {
+	return nil
+}
+
+// item writes a record as EventRecordItem
+func (t *target) item(id, ts, val1, val2 uint32) {
+	le := binary.LittleEndian
+	i := t.index
+	t.index++
+	le.PutUint32(t.mem[statusAddr-infoAddr+4:], t.index)
+	rec := t.mem[bufferAddr-infoAddr+16*(i%testCount):]
+	info := id | ((i/testCount)<<recordSeqPos)&0x00F00000 |
+		(ts>>3)&recordMsbTS | (val1>>2)&recordMsbVal1 | (val2>>1)&recordMsbVal2 | recordValid
+	info |= (le.Uint32(rec[12:]) & recordTBit) ^ recordTBit
+	tbit := info & recordTBit
+	le.PutUint32(rec, ts&^recordTBit|tbit)
+	le.PutUint32(rec[4:], val1&^recordTBit|tbit)
+	le.PutUint32(rec[8:], val2&^recordTBit|tbit)
+	le.PutUint32(rec[12:], info)
+}
+
+func (t *target) record2(id uint16, ts, val1, val2 uint32) {
+	t.item(uint32(id)|recordFirst|recordLast, ts, val1, val2)
+}
+
+func (t *target) record4(id uint16, ts, val1, val2, val3, val4 uint32) {
+	t.ctx = (t.ctx + 1) & 7
+	ctx := t.ctx << recordDlenPos
+	t.item(uint32(id)|ctx|recordFirst, ts, val1, val2)
+	t.item(1|ctx|recordLast, ts, val3, val4)
+}
+
+func (t *target) data(id uint16, ts uint32, data []byte) {
+	le := binary.LittleEndian
+	var val [8]byte
+	switch {
+	case len(data) == 0:
+		t.item(uint32(id), ts, 0, 0)
+		return
+	case len(data) <= 8:
+		copy(val[:], data)
+		t.item(uint32(id)|uint32(len(data))<<recordDlenPos|recordFirst|recordLast, ts, le.Uint32(val[:]), le.Uint32(val[4:]))
+		return
+	}
+	t.ctx = (t.ctx + 1) & 7
+	ctx := t.ctx << recordDlenPos
+	t.item(uint32(id)|ctx|recordFirst, ts, le.Uint32(data), le.Uint32(data[4:]))
+	data = data[8:]
+	next := uint32(0xFF01) | ctx
+	for ; len(data) > 8; data = data[8:] {
+		t.item(next, ts, le.Uint32(data), le.Uint32(data[4:]))
+		next++
+	}
+	val = [8]byte{}
+	copy(val[:], data)
+	t.item(next&^0xFF00|uint32(len(data))<<8|recordLast, ts, le.Uint32(val[:]), le.Uint32(val[4:]))
+}
+
+// read the events of the reader
+func readEvents(t *testing.T, r *Reader) []event.Data {
+	t.Helper()
+	if _, err := r.poll(); err != nil {
+		t.Fatalf("Reader.poll() error = %v", err)
+	}
+	var events []event.Data
+	in := bufio.NewReader(bytes.NewReader(r.out.Bytes()))
+	r.out.Reset()
+	for {
+		var e event.Data
+		if err := e.Read(in); err != nil {
+			return events
+		}
+		events = append(events, e)
+	}
+}
+
+func record(typ uint16, time uint64, id uint16, values ...int32) event.Data {
+	e := event.Data{Typ: typ, Time: time}
+	e.Info.ID = id
+	for i, v := range values {
+		*[]*int32{&e.Value1, &e.Value2, &e.Value3, &e.Value4}[i] = v
+	}
+	return e
+}
+
+func data(time uint64, id uint16, payload string, irq bool) event.Data {
+	e := event.Data{Time: time}
+	e.Info.ID = id
+	e.SetPayload([]byte(payload))
+	e.Info.SetIRQ(irq)
+	return e
+}
+
+func TestReader(t *testing.T) {
+	t.Parallel()
+
+	tg := newTarget(1000)
+	tg.record2(0x0A01, 10, 0x80000001, 7)
+	r, err := NewReader(tg, infoAddr)
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	if r.Info().Count != testCount || r.Info().Buffer != bufferAddr {
+		t.Errorf("Reader.Info() = %+v", r.Info())
+	}
+	tg.record4(0x0A02, 20, 1, 2, 3, 4)
+	tg.data(0xFE00, 30, []byte("hello world!"))
+	tg.item(0xFE01|3<<recordDlenPos|recordFirst|recordLast|recordIRQ, 0xFFFFFFF0, 0x00636261, 0)
+	tg.data(0xFE02, 0x10, nil)
+	want := []event.Data{
+		record(2, 0, 0xFF00, 0, 1000),
+		record(2, 10, 0x0A01, -0x7FFFFFFF, 7),
+		record(3, 20, 0x0A02, 1, 2, 3, 4),
+		data(30, 0xFE00, "hello world!", false),
+		data(0xFFFFFFF0, 0xFE01, "abc", true),
+		data(0x100000010, 0xFE02, "", false),
+	}
+	if got := readEvents(t, r); !reflect.DeepEqual(got, want) {
+		t.Errorf("Reader = %+v, want %+v", got, want)
+	}
+
+	// overwritten records and an event of several records partly overwritten
+	tg.data(0xFE00, 0x20, []byte("0123456789abcdefghijklmnopqrstuvwxyz"))
+	tg.record2(0x0A01, 0x30, 1, 2)
+	tg.record2(0x0A01, 0x40, 3, 4)
+	tg.record2(0x0A01, 0x50, 5, 6)
+	tg.record2(0x0A01, 0x60, 7, 8)
+	want = []event.Data{
+		record(2, 0x100000030, 0xFF0E, 5, 0),
+		record(2, 0x100000030, 0x0A01, 1, 2),
+		record(2, 0x100000040, 0x0A01, 3, 4),
+		record(2, 0x100000050, 0x0A01, 5, 6),
+		record(2, 0x100000060, 0x0A01, 7, 8),
+	}
+	if got := readEvents(t, r); !reflect.DeepEqual(got, want) || r.Lost() != 5 {
+		t.Errorf("Reader = %+v, lost %d, want %+v, lost 5", got, r.Lost(), want)
+	}
+
+	// a record in writing is read by the next poll
+	tg.record2(0x0A01, 0x70, 9, 10)
+	rec := tg.mem[bufferAddr-infoAddr+16*((tg.index-1)%testCount)+12:]
+	binary.LittleEndian.PutUint32(rec, binary.LittleEndian.Uint32(rec)|recordLocked)
+	if got := readEvents(t, r); len(got) != 0 {
+		t.Errorf("Reader = %+v, want no event", got)
+	}
+	binary.LittleEndian.PutUint32(rec, binary.LittleEndian.Uint32(rec)&^recordLocked)
+	want = []event.Data{record(2, 0x100000070, 0x0A01, 9, 10)}
+	if got := readEvents(t, r); !reflect.DeepEqual(got, want) {
+		t.Errorf("Reader = %+v, want %+v", got, want)
+	}
+
+	// the reset target starts at index 0
+	tg.index = 0
+	tg.record2(0x0A03, 5, 1, 1)
+	want = []event.Data{record(2, 5, 0x0A03, 1, 1)}
+	if got := readEvents(t, r); !reflect.DeepEqual(got, want) {
+		t.Errorf("Reader = %+v, want %+v", got, want)
+	}
+
+	tg.fail = io.ErrUnexpectedEOF
+	if _, err := r.poll(); err != io.ErrUnexpectedEOF {
+		t.Errorf("Reader.poll() error = %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func TestReader_Reset(t *testing.T) {
+	t.Parallel()
+
+	tg := newTarget(1000)
+	tg.record2(0x0A01, 0x30000000, 1, 2)
+	r, err := NewReader(tg, infoAddr)
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	readEvents(t, r)
+	if err := r.SetFilter(Filter{true, 8, 0x80, 0x80}); err != nil {
+		t.Fatalf("Reader.SetFilter() error = %v", err)
+	}
+	tg.record2(0x0A01, 0x30000010, 3, 4)
+	if err := r.Reset(); err != nil || tg.resets != 1 {
+		t.Errorf("Reader.Reset() error = %v, resets %d", err, tg.resets)
+	}
+	// the initialization after the reset clears the filter
+	copy(tg.mem[0x80:0x80+128], make([]byte, 128))
+	tg.record2(event.IDInitialize, 5, 2, 1000)
+	tg.record2(0x0A02, 8, 5, 6)
+	want := []event.Data{
+		record(2, 0x30000000, event.IDReset, 0, 0),
+		record(2, 5, event.IDInitialize, 2, 1000),
+		record(2, 8, 0x0A02, 5, 6),
+	}
+	if got := readEvents(t, r); !reflect.DeepEqual(got, want) {
+		t.Errorf("Reader = %+v, want %+v", got, want)
+	}
+	if tg.mem[0x80+96+16] != 1 {
+		t.Errorf("Reader filter = %x, want the filter set again", tg.mem[0x80:0x80+128])
+	}
+	tg.fail = errClosed
+	if err := r.Reset(); err != errClosed {
+		t.Errorf("Reader.Reset() error = %v, want %v", err, errClosed)
+	}
+}
+
+func TestReader_Read(t *testing.T) {
+	t.Parallel()
+
+	tg := newTarget(1000)
+	r, err := NewReader(tg, infoAddr)
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	b := make([]byte, 64)
+	if n, err := r.Read(b); n != 24 || err != nil {
+		t.Errorf("Reader.Read() = %d, %v, want 24, nil", n, err)
+	}
+	go func() {
+		time.Sleep(2 * PollInterval)
+		_ = r.Close()
+	}()
+	if n, err := r.Read(b); n != 0 || err != io.EOF {
+		t.Errorf("Reader.Read() = %d, %v, want 0, EOF", n, err)
+	}
+	if err := r.Close(); err != errClosed {
+		t.Errorf("Reader.Close() error = %v, want %v", err, errClosed)
+	}
+}
+
+func TestReadInfo(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		addr    uint32
+		patch   func(tg *target)
+		wantErr bool
+	}{
+		{"ok", infoAddr, func(tg *target) {}, false},
+		{"no memory", 0x10, func(tg *target) {}, true},
+		{"protocol", infoAddr, func(tg *target) { tg.mem[0] = 2 }, true},
+		{"version", infoAddr, func(tg *target) { tg.mem[3] = 2 }, true},
+		{"count", infoAddr, func(tg *target) { tg.mem[4] = 12 }, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tg := newTarget(1000)
+			tt.patch(tg)
+			info, err := ReadInfo(tg, tt.addr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ReadInfo() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if !tt.wantErr && info != (Info{0x0101, testCount, bufferAddr, 0x20000080, statusAddr}) {
+				t.Errorf("ReadInfo() %s = %+v", tt.name, info)
+			}
+		})
+	}
+}
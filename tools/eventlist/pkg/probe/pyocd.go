@@ -0,0 +1,178 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package probe
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// pyocdScript serves the memory accesses of the standard input with pyOCD:
+// "read <addr> <length>" is answered by the hex data, "write <addr> <hex>"
+// and "reset" by "ok", an error by "error <message>"
+const pyocdScript = `import sys
+from pyocd.core.helpers import ConnectHelper
+options = {"connect_mode": "attach"}
+if sys.argv[1]:
+    options["target_override"] = sys.argv[1]
+session = ConnectHelper.session_with_chosen_probe(unique_id=sys.argv[2] or None, blocking=False, options=options)
+if session is None:
+    print("error no debug probe found", flush=True)
+    sys.exit(1)
+with session:
+    print("ready", flush=True)
+    for line in sys.stdin:
+        cmd = line.split()
+        try:
+            if cmd[0] == "read":
+                data = session.target.read_memory_block8(int(cmd[1], 16), int(cmd[2]))
+                print(bytes(data).hex(), flush=True)
+            elif cmd[0] == "write":
+                session.target.write_memory_block8(int(cmd[1], 16), bytes.fromhex(cmd[2]))
+                print("ok", flush=True)
+            elif cmd[0] == "reset":
+                session.target.reset()
+                print("ok", flush=True)
+            else:
+                print("error unknown command", cmd[0], flush=True)
+        except Exception as e:
+            print("error", e, flush=True)
+`
+
+// PyOCDCommand runs the script serving the memory accesses, the target type
+// and the unique ID of the probe are appended
+var PyOCDCommand = []string{"python3", "-c", pyocdScript}
+
+// PyOCD accesses the memory of the target with a pyOCD process
+type PyOCD struct {
+	cmd  *exec.Cmd
+	in   io.WriteCloser
+	out  *bufio.Reader
+	line []byte
+}
+
+// OpenPyOCD connects to the target with pyOCD, the source is the target
+// type and optionally the unique ID of the probe: "<target>[,<probe>]",
+// the target type auto or an empty one is the target of the probe
+func OpenPyOCD(source string) (*PyOCD, error) {
+	target, uid, _ := strings.Cut(source, ",")
+	if target == "auto" {
+		target = ""
+	}
+	command := append(append([]string{}, PyOCDCommand...), target, uid)
+	cmd := exec.Command(command[0], command[1:]...) //nolint:gosec
+	cmd.Stderr = os.Stderr
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("pyocd: %w", err)
+	}
+	p := &PyOCD{cmd: cmd, in: in, out: bufio.NewReader(out)}
+	line, err := p.readLine()
+	if err == nil && line != "ready" {
+		err = errors.New(strings.TrimPrefix(line, "error "))
+	}
+	if err != nil {
+		_ = p.Close()
+		return nil, fmt.Errorf("pyocd: %w", err)
+	}
+	return p, nil
+}
+
+// read a line of the answer
+func (p *PyOCD) readLine() (string, error) {
+	p.line = p.line[:0]
+	for {
+		part, isPrefix, err := p.out.ReadLine()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return "", err
+		}
+		p.line = append(p.line, part...)
+		if !isPrefix {
+			return string(p.line), nil
+		}
+	}
+}
+
+// Read reads the memory at addr
+func (p *PyOCD) Read(addr uint32, b []byte) error {
+	if _, err := fmt.Fprintf(p.in, "read %x %d\n", addr, len(b)); err != nil {
+		return fmt.Errorf("pyocd: %w", err)
+	}
+	line, err := p.readLine()
+	if err != nil {
+		return fmt.Errorf("pyocd: %w", err)
+	}
+	if msg, ok := strings.CutPrefix(line, "error "); ok {
+		return fmt.Errorf("pyocd: read 0x%08X: %s", addr, msg)
+	}
+	if len(line) != 2*len(b) {
+		return fmt.Errorf("pyocd: read 0x%08X: invalid answer %.40q", addr, line)
+	}
+	if _, err := hex.Decode(b, []byte(line)); err != nil {
+		return fmt.Errorf("pyocd: read 0x%08X: invalid answer %.40q", addr, line)
+	}
+	return nil
+}
+
+// Write writes the memory at addr
+func (p *PyOCD) Write(addr uint32, b []byte) error {
+	return p.command(fmt.Sprintf("write %x %x", addr, b), fmt.Sprintf("write 0x%08X", addr))
+}
+
+// Reset resets the target and lets it run
+func (p *PyOCD) Reset() error {
+	return p.command("reset", "reset")
+}
+
+// command sends a command answered by "ok"
+func (p *PyOCD) command(cmd string, name string) error {
+	if _, err := fmt.Fprintln(p.in, cmd); err != nil {
+		return fmt.Errorf("pyocd: %w", err)
+	}
+	line, err := p.readLine()
+	if err != nil {
+		return fmt.Errorf("pyocd: %w", err)
+	}
+	if line != "ok" {
+		return fmt.Errorf("pyocd: %s: %s", name, strings.TrimPrefix(line, "error "))
+	}
+	return nil
+}
+
+// Close ends the pyOCD process, the target keeps running
+func (p *PyOCD) Close() error {
+	_ = p.in.Close()
+	return p.cmd.Wait()
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package probe
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestHelperProcess is the pyOCD process started by the tests, the memory
+// at an address holds the low byte of the address, reads of 0 fail
+func TestHelperProcess(t *testing.T) { //nolint:golint,paralleltest
+	if os.Getenv("EVENTLIST_TEST_PYOCD") != "1" {
+		return
+	}
+	args := os.Args[len(os.Args)-2:]
+	if args[0] == "nix" {
+		fmt.Println("error no debug probe found")
+		os.Exit(1)
+	}
+	fmt.Println("ready")
+	in := bufio.NewScanner(os.Stdin)
+	for in.Scan() {
+		var addr uint32
+		var n int
+		var data string
+		if in.Text() == "reset" {
+			fmt.Println("ok")
+			continue
+		}
+		if _, err := fmt.Sscanf(in.Text(), "write %x %s", &addr, &data); err == nil {
+			if addr == 0 {
+				fmt.Println("error memory transfer fault")
+			} else {
+				fmt.Println("ok")
+			}
+			continue
+		}
+		if _, err := fmt.Sscanf(in.Text(), "read %x %d", &addr, &n); err != nil {
+			fmt.Println("error unknown command")
+			continue
+		}
+		switch addr {
+		case 0:
+			fmt.Println("error memory transfer fault")
+		case 1:
+			fmt.Println("00")
+		default:
+			for i := 0; i < n; i++ {
+				fmt.Printf("%02x", byte(addr+uint32(i)))
+			}
+			fmt.Println()
+		}
+	}
+	os.Exit(0)
+}
+
+func TestPyOCD(t *testing.T) { //nolint:golint,paralleltest
+	t.Setenv("EVENTLIST_TEST_PYOCD", "1")
+	saved := PyOCDCommand
+	defer func() { PyOCDCommand = saved }()
+	PyOCDCommand = []string{os.Args[0], "-test.run=TestHelperProcess", "--"}
+
+	if _, err := OpenPyOCD("nix"); err == nil || !strings.Contains(err.Error(), "no debug probe found") {
+		t.Errorf("OpenPyOCD() error = %v, want no debug probe found", err)
+	}
+	p, err := OpenPyOCD("auto,0240000032044e45")
+	if err != nil {
+		t.Fatalf("OpenPyOCD() error = %v", err)
+	}
+	tests := []struct {
+		addr    uint32
+		want    []byte
+		wantErr string
+	}{
+		{0x20000010, []byte{0x10, 0x11, 0x12, 0x13}, ""},
+		{0, nil, "pyocd: read 0x00000000: memory transfer fault"},
+		{1, nil, "pyocd: read 0x00000001: invalid answer \"00\""},
+	}
+	for _, tt := range tests {
+		b := make([]byte, 4)
+		err := p.Read(tt.addr, b)
+		if (err == nil && tt.wantErr != "") || (err != nil && err.Error() != tt.wantErr) {
+			t.Errorf("PyOCD.Read() 0x%08X error = %v, want %s", tt.addr, err, tt.wantErr)
+		}
+		if tt.want != nil && !bytes.Equal(b, tt.want) {
+			t.Errorf("PyOCD.Read() 0x%08X = %x, want %x", tt.addr, b, tt.want)
+		}
+	}
+	if err := p.Write(0x20000010, []byte{1, 2}); err != nil {
+		t.Errorf("PyOCD.Write() error = %v", err)
+	}
+	if err := p.Write(0, []byte{1, 2}); err == nil || err.Error() != "pyocd: write 0x00000000: memory transfer fault" {
+		t.Errorf("PyOCD.Write() error = %v, want memory transfer fault", err)
+	}
+	if err := p.Reset(); err != nil {
+		t.Errorf("PyOCD.Reset() error = %v", err)
+	}
+	if err := p.Close(); err != nil {
+		t.Errorf("PyOCD.Close() error = %v", err)
+	}
+	PyOCDCommand = []string{"../../testdata/nix"}
+	if _, err := OpenPyOCD("auto"); err == nil {
+		t.Errorf("OpenPyOCD() error = nil, want error")
+	}
+}
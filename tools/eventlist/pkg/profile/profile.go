@@ -0,0 +1,145 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package profile
+
+import (
+	"errors"
+	"eventlist/pkg/rtos"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+var errProfile = errors.New("unknown profile")
+var errInvalid = errors.New("invalid profile")
+
+// Profile bundles the decoding settings of a target configuration
+type Profile struct {
+	Description string  `yaml:"description"`
+	Endian      string  `yaml:"endian"` // byte order of the records: little or big
+	Clock       float64 `yaml:"clock"`  // timestamp frequency in Hz, used until a clock event is recorded
+	Float       string  `yaml:"float"`  // interpretation of %T values: float, double or half
+	Fixed       string  `yaml:"fixed"`  // interpretation of %T values as fixed point number, e.g. Q15
+	RTOS        string  `yaml:"rtos"`   // bundled event definitions of an RTOS, e.g. zephyr
+}
+
+// Builtin are the profiles of common target configurations
+var Builtin = map[string]Profile{
+	"cm0plus-le": {
+		Description: "Cortex-M0+, little endian, SysTick or timer timestamps at 48 MHz",
+		Endian:      "little",
+		Clock:       48e6,
+	},
+	"cm4-le-dwt": {
+		Description: "Cortex-M4, little endian, DWT cycle counter at 168 MHz",
+		Endian:      "little",
+		Clock:       168e6,
+	},
+	"cm7-be-systick": {
+		Description: "Cortex-M7, big endian, SysTick based timestamps at 216 MHz",
+		Endian:      "big",
+		Clock:       216e6,
+	},
+	"cm33-le-dwt": {
+		Description: "Cortex-M33, little endian, DWT cycle counter at 100 MHz",
+		Endian:      "little",
+		Clock:       100e6,
+	},
+	"zephyr": {
+		Description: "Zephyr RTOS tracing events, little endian",
+		Endian:      "little",
+		RTOS:        "zephyr",
+	},
+}
+
+// Profiles maps the profile names to the profiles
+type Profiles map[string]Profile
+
+// New returns the builtin profiles
+func New() Profiles {
+	p := make(Profiles)
+	for name, profile := range Builtin {
+		p[name] = profile
+	}
+	return p
+}
+
+// Load adds the profiles of a YAML file, a map of profile names to profiles,
+// a profile with the name of a builtin profile replaces it
+func (p Profiles) Load(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	var profiles map[string]Profile
+	if err = yaml.Unmarshal(data, &profiles); err != nil {
+		return err
+	}
+	for name, profile := range profiles {
+		switch profile.Endian {
+		case "", "little", "big":
+		default:
+			return fmt.Errorf("%w: %s: endian %s", errInvalid, name, profile.Endian)
+		}
+		if len(profile.Float) != 0 && len(profile.Fixed) != 0 {
+			return fmt.Errorf("%w: %s: only one of float and fixed allowed", errInvalid, name)
+		}
+		if profile.Clock < 0 {
+			return fmt.Errorf("%w: %s: clock %g", errInvalid, name, profile.Clock)
+		}
+		if len(profile.RTOS) != 0 {
+			if _, err := rtos.Definitions(profile.RTOS); err != nil {
+				return fmt.Errorf("%w: %s: %v", errInvalid, name, err)
+			}
+		}
+		p[name] = profile
+	}
+	return nil
+}
+
+// Get returns the profile of a name
+func (p Profiles) Get(name string) (Profile, error) {
+	profile, ok := p[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("%w: %s", errProfile, name)
+	}
+	return profile, nil
+}
+
+// List writes the names and descriptions of the profiles
+func (p Profiles) List(w io.Writer) error {
+	names := make([]string, 0, len(p))
+	size := 0
+	for name := range p {
+		names = append(names, name)
+		if len(name) > size {
+			size = len(name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, err := fmt.Fprintf(w, "%*s %s\n", -size, name, p[name].Description); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package profile
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestProfiles_Load(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	badClock := filepath.Join(dir, "clock.yaml")
+	_ = os.WriteFile(badClock, []byte("x:\n  clock: -1\n"), 0600)
+	badType := filepath.Join(dir, "type.yaml")
+	_ = os.WriteFile(badType, []byte("x:\n  float: float\n  fixed: Q15\n"), 0600)
+	badRTOS := filepath.Join(dir, "rtos.yaml")
+	_ = os.WriteFile(badRTOS, []byte("x:\n  rtos: rtx4\n"), 0600)
+	badYAML := filepath.Join(dir, "yaml.yaml")
+	_ = os.WriteFile(badYAML, []byte("- cm3\n"), 0600)
+
+	tests := []struct {
+		name     string
+		filename string
+		want     map[string]Profile
+		wantErr  error
+	}{
+		{"ok", "../../testdata/profiles.yaml", map[string]Profile{
+			"cm3-le-board": {
+				Description: "Cortex-M3 board, little endian, cycle counter at 72 MHz",
+				Endian:      "little", Clock: 72e6, RTOS: "freertos"},
+			"cm7-be-systick": {
+				Description: "Cortex-M7 board, big endian, SysTick at 400 MHz",
+				Endian:      "big", Clock: 400e6, Fixed: "Q15"},
+			"cm0plus-le": Builtin["cm0plus-le"],
+		}, nil},
+		{"endian", "../../testdata/profiles_err.yaml", nil, errInvalid},
+		{"clock", badClock, nil, errInvalid},
+		{"type", badType, nil, errInvalid},
+		{"rtos", badRTOS, nil, errInvalid},
+		{"yaml", badYAML, nil, nil},
+		{"nix", "../../testdata/nix.yaml", nil, os.ErrNotExist},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := New()
+			err := p.Load(tt.filename)
+			if tt.want == nil && err == nil {
+				t.Errorf("Profiles.Load() %s error = nil, want error", tt.name)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Profiles.Load() %s error = %v, want %v", tt.name, err, tt.wantErr)
+			}
+			for name, want := range tt.want {
+				if got := p[name]; !reflect.DeepEqual(got, want) {
+					t.Errorf("Profiles.Load() %s %s = %v, want %v", tt.name, name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestProfiles_Get(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		profile string
+		want    Profile
+		wantErr error
+	}{
+		{"cm0plus", "cm0plus-le", Builtin["cm0plus-le"], nil},
+		{"cm7", "cm7-be-systick", Builtin["cm7-be-systick"], nil},
+		{"zephyr", "zephyr", Profile{Description: "Zephyr RTOS tracing events, little endian", Endian: "little", RTOS: "zephyr"}, nil},
+		{"unknown", "cm99", Profile{}, errProfile},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := New().Get(tt.profile)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Profiles.Get() %s error = %v, want %v", tt.name, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Profiles.Get() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProfiles_List(t *testing.T) {
+	t.Parallel()
+
+	p := Profiles{
+		"b-long-name": {Description: "second"},
+		"a":           {Description: "first"},
+	}
+	var b bytes.Buffer
+	if err := p.List(&b); err != nil {
+		t.Errorf("Profiles.List() error = %v", err)
+	}
+	want := "a           first\nb-long-name second\n"
+	if got := b.String(); got != want {
+		t.Errorf("Profiles.List() = %q, want %q", got, want)
+	}
+}
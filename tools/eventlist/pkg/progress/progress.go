@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package progress
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	width    = 30                     // characters of the bar
+	delay    = 500 * time.Millisecond // small files are decoded without progress bar
+	interval = 100 * time.Millisecond // minimum time between two updates
+)
+
+// Bar shows the processed bytes and the estimated remaining time
+// of a file that is read in one or more passes
+type Bar struct {
+	w      io.Writer
+	total  int64 // bytes of one pass
+	passes int
+	pass   int
+	done   int64 // bytes read in all passes
+	start  time.Time
+	last   time.Time
+	shown  bool
+	now    func() time.Time
+}
+
+// New creates a progress bar writing to w for a file of total bytes
+func New(w io.Writer, total int64, passes int) *Bar {
+	if passes < 1 {
+		passes = 1
+	}
+	return &Bar{w: w, total: total, passes: passes, now: time.Now}
+}
+
+type reader struct {
+	r   io.Reader
+	bar *Bar
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.bar.add(int64(n))
+	return n, err
+}
+
+// Reader starts the next pass, the bytes read from the returned reader are
+// counted; a nil Bar returns r
+func (b *Bar) Reader(r io.Reader) io.Reader {
+	if b == nil {
+		return r
+	}
+	if b.pass == 0 {
+		b.start = b.now()
+	}
+	b.pass++
+	b.done = int64(b.pass-1) * b.total
+	return &reader{r: r, bar: b}
+}
+
+func (b *Bar) add(n int64) {
+	b.done += n
+	now := b.now()
+	if now.Sub(b.start) < delay || now.Sub(b.last) < interval {
+		return
+	}
+	b.last = now
+	b.shown = true
+	fmt.Fprint(b.w, "\r"+b.String(now))
+}
+
+// String returns the progress line at time now
+func (b *Bar) String(now time.Time) string {
+	all := b.total * int64(b.passes)
+	ratio := 1.0
+	if all > 0 {
+		ratio = float64(b.done) / float64(all)
+	}
+	if ratio > 1 {
+		ratio = 1
+	}
+	n := int(ratio * width)
+	bar := strings.Repeat("=", n)
+	if n < width {
+		bar += ">" + strings.Repeat(" ", width-n-1)
+	}
+	eta := "--:--"
+	if elapsed := now.Sub(b.start); ratio > 0 {
+		eta = duration(time.Duration(float64(elapsed) * (1 - ratio) / ratio))
+	}
+	pass := b.done - int64(b.pass-1)*b.total
+	line := fmt.Sprintf("[%s] %3d%% %s/%s ETA %s", bar, int(ratio*100), Bytes(pass), Bytes(b.total), eta)
+	if b.passes > 1 {
+		line = fmt.Sprintf("pass %d/%d %s", b.pass, b.passes, line)
+	}
+	return line
+}
+
+// Finish clears the progress line
+func (b *Bar) Finish() {
+	if b == nil || !b.shown {
+		return
+	}
+	fmt.Fprint(b.w, "\r\033[K")
+	b.shown = false
+}
+
+// Bytes formats a number of bytes with binary units
+func Bytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func duration(d time.Duration) string {
+	d = d.Round(time.Second)
+	h := int(d / time.Hour)
+	m := int(d % time.Hour / time.Minute)
+	s := int(d % time.Minute / time.Second)
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%d:%02d", m, s)
+}
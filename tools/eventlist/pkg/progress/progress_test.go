@@ -0,0 +1,114 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package progress
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func TestBar_String(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		total  int64
+		passes int
+		pass   int
+		done   int64
+		now    time.Duration
+		want   string
+	}{
+		{"begin", 1 << 30, 1, 1, 0, time.Second, "[>                             ]   0% 0 B/1.0 GiB ETA --:--"},
+		{"half", 1 << 30, 1, 1, 1 << 29, 10 * time.Second, "[===============>              ]  50% 512.0 MiB/1.0 GiB ETA 0:10"},
+		{"pass 2", 4 << 30, 2, 2, 6 << 30, time.Hour, "pass 2/2 [======================>       ]  75% 2.0 GiB/4.0 GiB ETA 20:00"},
+		{"end", 100, 2, 2, 200, 2 * time.Hour, "pass 2/2 [==============================] 100% 100 B/100 B ETA 0:00"},
+		{"empty", 0, 1, 1, 0, time.Second, "[==============================] 100% 0 B/0 B ETA 0:00"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			b := New(io.Discard, tt.total, tt.passes)
+			b.start = start
+			b.pass = tt.pass
+			b.done = tt.done
+			if got := b.String(start.Add(tt.now)); got != tt.want {
+				t.Errorf("Bar.String() %s = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBar_Reader(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	var out bytes.Buffer
+	b := New(&out, 4, 2)
+	b.now = func() time.Time { now = now.Add(300 * time.Millisecond); return now }
+
+	for pass := 0; pass < 2; pass++ {
+		data, err := io.ReadAll(b.Reader(iotest.OneByteReader(strings.NewReader("abcd"))))
+		if err != nil || string(data) != "abcd" {
+			t.Errorf("Bar.Reader() = %q, %v, want abcd", data, err)
+		}
+	}
+	if b.done != 8 {
+		t.Errorf("Bar.Reader() done = %d, want 8", b.done)
+	}
+	b.Finish()
+	got := out.String()
+	if !strings.Contains(got, "\rpass 2/2 [") || !strings.HasSuffix(got, "\r\033[K") {
+		t.Errorf("Bar.Reader() output = %q, want progress of pass 2 and cleared line", got)
+	}
+
+	var n *Bar
+	r := strings.NewReader("x")
+	if got := n.Reader(r); got != r {
+		t.Errorf("Bar.Reader() nil = %v, want %v", got, r)
+	}
+	n.Finish()
+}
+
+func TestBytes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{3 << 20, "3.0 MiB"},
+		{5 << 40, "5.0 TiB"},
+	}
+	for _, tt := range tests {
+		if got := Bytes(tt.n); got != tt.want {
+			t.Errorf("Bytes() %d = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redact
+
+import (
+	"bufio"
+	"errors"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/where"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var errField = errors.New("invalid redact field")
+var errRange = errors.New("invalid address range")
+var errMode = errors.New("unknown redact mode")
+
+// Field selects a value of the events matching the filter, e.g. val2 of
+// the events of "id=0xF000"
+type Field struct {
+	Filter *where.Filter
+	Value  int // 1 to 4
+}
+
+// ParseField parses "<conditions>:val<n>", e.g. "component=MyNet:val2"
+func ParseField(s string) (Field, error) {
+	i := strings.LastIndex(s, ":")
+	if i < 0 || len(s) != i+5 || s[i+1:i+4] != "val" || s[i+4] < '1' || s[i+4] > '4' {
+		return Field{}, fmt.Errorf("%w: %s", errField, s)
+	}
+	filter, err := where.Parse([]string{s[:i]})
+	if err != nil {
+		return Field{}, err
+	}
+	return Field{Filter: filter, Value: int(s[i+4] - '0')}, nil
+}
+
+// Range is an address range, both limits included
+type Range struct {
+	Low, High uint32
+}
+
+// ParseRanges parses a comma separated list of ranges "low-high",
+// e.g. "0x20000000-0x2003FFFF,0x40000000-0x5FFFFFFF"
+func ParseRanges(s string) ([]Range, error) {
+	var ranges []Range
+	for _, item := range strings.Split(s, ",") {
+		low, high, ok := strings.Cut(strings.TrimSpace(item), "-")
+		l, errLow := strconv.ParseUint(low, 0, 32)
+		h, errHigh := strconv.ParseUint(high, 0, 32)
+		if !ok || errLow != nil || errHigh != nil || l > h {
+			return nil, fmt.Errorf("%w: %s", errRange, item)
+		}
+		ranges = append(ranges, Range{uint32(l), uint32(h)})
+	}
+	return ranges, nil
+}
+
+// Options select what is redacted
+type Options struct {
+	// Mode is "hash" or "placeholder": a hash replaces equal values by
+	// equal values, so the events can still be correlated, a placeholder
+	// replaces all values by the same value
+	Mode      string
+	Salt      string  // salt of the hashes, hides values that can be guessed
+	Strings   bool    // the payload of the data records, e.g. strings
+	Addresses []Range // the values within the address ranges
+	Fields    []Field // the selected values
+}
+
+// Redactor writes the records with redacted values, clock events are
+// written unchanged
+type Redactor struct {
+	opt      Options
+	out      *bufio.Writer
+	buf      []byte
+	Written  int // number of written records
+	Redacted int // number of records with a redacted value
+}
+
+// New creates a redactor writing to w
+func New(w io.Writer, opt Options) (*Redactor, error) {
+	switch opt.Mode {
+	case "":
+		opt.Mode = "hash"
+	case "hash", "placeholder":
+	default:
+		return nil, fmt.Errorf("%w: %s", errMode, opt.Mode)
+	}
+	return &Redactor{opt: opt, out: bufio.NewWriter(w)}, nil
+}
+
+func (r *Redactor) hash(data []byte) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(r.opt.Salt))
+	_, _ = h.Write(data)
+	return h.Sum32()
+}
+
+// payload replaces the bytes of the data except zeros and line feeds, a
+// hash by the hex digits of a hash of the data, a placeholder by '*'
+func (r *Redactor) payload(data []byte) []byte {
+	digits := strings.Repeat("*", 8)
+	if r.opt.Mode == "hash" {
+		digits = fmt.Sprintf("%08x", r.hash(data))
+	}
+	redacted := make([]byte, len(data))
+	for i, c := range data {
+		if c == 0 || c == '\n' {
+			redacted[i] = c
+		} else {
+			redacted[i] = digits[i%len(digits)]
+		}
+	}
+	return redacted
+}
+
+// address replaces a value within an address range by an address of the
+// same range with the same alignment, the placeholder is the begin of the range
+func (r *Redactor) address(v uint32, rg Range) uint32 {
+	if r.opt.Mode == "placeholder" {
+		return rg.Low
+	}
+	size := uint64(rg.High-rg.Low) + 1
+	a := rg.Low + uint32(uint64(r.hash([]byte(strconv.FormatUint(uint64(v), 16))))%size)
+	a = a&^3 | v&3
+	if a < rg.Low || a > rg.High {
+		return v
+	}
+	return a
+}
+
+// value replaces a selected value by a hash or the placeholder 0
+func (r *Redactor) value(v uint32) uint32 {
+	if r.opt.Mode == "placeholder" {
+		return 0
+	}
+	return r.hash([]byte(strconv.FormatUint(uint64(v), 16)))
+}
+
+// Event writes the record of the event with redacted values
+func (r *Redactor) Event(ev *bus.Event) error {
+	data := *ev.Data
+	redacted := false
+	if data.Info.ID != 0xFF00 && data.Info.ID != 0xFF03 {
+		if r.opt.Strings && data.Typ == 1 && data.Data != nil {
+			payload := r.payload(*data.Data)
+			data.Data = &payload
+			redacted = true
+		}
+		if data.Typ == 2 || data.Typ == 3 {
+			values := []*int32{&data.Value1, &data.Value2, &data.Value3, &data.Value4}
+			if data.Typ == 2 {
+				values = values[:2]
+			}
+			for i, p := range values {
+				v := uint32(*p)
+				for _, f := range r.opt.Fields {
+					if f.Value == i+1 && f.Filter.Match(ev) {
+						v = r.value(v)
+						break
+					}
+				}
+				for _, rg := range r.opt.Addresses {
+					if v == uint32(*p) && v >= rg.Low && v <= rg.High {
+						v = r.address(v, rg)
+						break
+					}
+				}
+				if v != uint32(*p) {
+					*p = int32(v)
+					redacted = true
+				}
+			}
+		}
+	}
+	if redacted {
+		r.Redacted++
+	}
+	r.buf = data.AppendRecord(r.buf[:0])
+	if _, err := r.out.Write(r.buf); err != nil {
+		return err
+	}
+	r.Written++
+	return nil
+}
+
+// End flushes the written records
+func (r *Redactor) End() error {
+	return r.out.Flush()
+}
@@ -0,0 +1,197 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redact
+
+import (
+	"bufio"
+	"bytes"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/event"
+	"reflect"
+	"testing"
+)
+
+func TestParseField(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		s       string
+		want    int
+		wantErr bool
+	}{
+		{"ok", "id=0xF000:val2", 2, false},
+		{"component", "component=My:Net:val4", 4, false},
+		{"no value", "id=0xF000", 0, true},
+		{"value", "id=0xF000:val5", 0, true},
+		{"name", "id=0xF000:value1", 0, true},
+		{"condition", "nix=1:val1", 0, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseField(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseField() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+				return
+			}
+			if got.Value != tt.want {
+				t.Errorf("ParseField() %s = %d, want %d", tt.name, got.Value, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRanges(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		s       string
+		want    []Range
+		wantErr bool
+	}{
+		{"one", "0x20000000-0x2000FFFF", []Range{{0x20000000, 0x2000FFFF}}, false},
+		{"two", "0x20000000-0x2000FFFF, 16-32", []Range{{0x20000000, 0x2000FFFF}, {16, 32}}, false},
+		{"order", "32-16", nil, true},
+		{"dash", "0x20000000", nil, true},
+		{"number", "0x20000000-x", nil, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseRanges(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseRanges() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRanges() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+// redact writes the events through a redactor and returns the records read back
+func redact(t *testing.T, opt Options, events []event.Data) []event.Data {
+	var b bytes.Buffer
+	r, err := New(&b, opt)
+	if err != nil {
+		t.Errorf("New() error = %v", err)
+		return nil
+	}
+	for i := range events {
+		if err = r.Event(bus.NewEvent(i, float64(i), &events[i], nil, nil)); err != nil {
+			t.Errorf("Redactor.Event() error = %v", err)
+		}
+	}
+	if err = r.End(); err != nil {
+		t.Errorf("Redactor.End() error = %v", err)
+	}
+	var got []event.Data
+	rd := bufio.NewReader(&b)
+	for range events {
+		var data event.Data
+		if err = data.Read(rd); err != nil {
+			t.Errorf("Read() error = %v", err)
+			break
+		}
+		got = append(got, data)
+	}
+	return got
+}
+
+func values(id uint16, v ...int32) event.Data {
+	d := event.Data{Typ: 3, Value1: v[0], Value2: v[1], Value3: v[2], Value4: v[3]}
+	d.Info.ID = id
+	return d
+}
+
+func TestRedactor_Event(t *testing.T) {
+	t.Parallel()
+
+	field, _ := ParseField("id=0xF000:val3")
+	ram := []Range{{0x20000000, 0x2000FFFF}}
+	tests := []struct {
+		name string
+		opt  Options
+		in   event.Data
+		want [4]int32
+	}{
+		{"none", Options{}, values(0xF000, 1, 2, 3, 0x20001000), [4]int32{1, 2, 3, 0x20001000}},
+		{"field", Options{Mode: "placeholder", Fields: []Field{field}}, values(0xF000, 1, 2, 3, 4), [4]int32{1, 2, 0, 4}},
+		{"other field", Options{Mode: "placeholder", Fields: []Field{field}}, values(0xF001, 1, 2, 3, 4), [4]int32{1, 2, 3, 4}},
+		{"address", Options{Mode: "placeholder", Addresses: ram}, values(0xF000, 1, 0x20001000, 3, 0x30000000), [4]int32{1, 0x20000000, 3, 0x30000000}},
+		{"clock", Options{Mode: "placeholder", Addresses: []Range{{0, 0xFFFFFFFF}}}, values(0xFF00, 1, 2, 3, 4), [4]int32{1, 2, 3, 4}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := redact(t, tt.opt, []event.Data{tt.in})
+			if len(got) != 1 {
+				return
+			}
+			if v := [4]int32{got[0].Value1, got[0].Value2, got[0].Value3, got[0].Value4}; v != tt.want {
+				t.Errorf("Redactor.Event() %s = %x, want %x", tt.name, v, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactor_hash(t *testing.T) {
+	t.Parallel()
+
+	// data record of event 0xFE00 with the 8 bytes "secret\n\x00" padded to 12 bytes
+	record := []byte{1, 0, 24, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xFE, 8, 0, 's', 'e', 'c', 'r', 'e', 't', '\n', 0, 0, 0, 0, 0}
+	var str event.Data
+	if err := str.Read(bufio.NewReader(bytes.NewReader(record))); err != nil {
+		t.Errorf("Read() error = %v", err)
+	}
+	opt := Options{Strings: true, Addresses: []Range{{0x20000000, 0x2000FFFF}}}
+	got := redact(t, opt, []event.Data{values(0xF000, 0x20001001, 0x20001001, 0x20002001, 7), str})
+	if len(got) != 2 {
+		return
+	}
+	a, b, c := uint32(got[0].Value1), uint32(got[0].Value2), uint32(got[0].Value3)
+	if a != b || a == c || a == 0x20001001 || a < 0x20000000 || a > 0x2000FFFF || a&3 != 1 {
+		t.Errorf("Redactor.Event() addresses = %x %x %x", a, b, c)
+	}
+	payload := *got[1].Data
+	if len(payload) != 8 || string(payload[:6]) == "secret" || payload[6] != '\n' || payload[7] != 0 {
+		t.Errorf("Redactor.Event() payload = %q", payload)
+	}
+	salted := redact(t, Options{Salt: "x", Addresses: opt.Addresses}, []event.Data{values(0xF000, 0x20001001, 0, 0, 0)})
+	if len(salted) == 1 && uint32(salted[0].Value1) == a {
+		t.Errorf("Redactor.Event() salted = %x, want other than %x", uint32(salted[0].Value1), a)
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	if _, err := New(&bytes.Buffer{}, Options{Mode: "xxx"}); err == nil {
+		t.Errorf("New() error = nil, want %v", errMode)
+	}
+}
@@ -0,0 +1,203 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package rotate writes an output file that is renamed and replaced by a
+// new file when it reaches a size or an age, e.g. the output of a live
+// capture running for days.
+package rotate
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Options of the rotation, a file is rotated when the next write exceeds
+// Size bytes or when it is older than Interval, zero disables a limit
+type Options struct {
+	Size     int64
+	Interval time.Duration
+	Compress bool // compress the rotated files with gzip
+}
+
+// Enabled reports if the file is rotated
+func (o Options) Enabled() bool {
+	return o.Size > 0 || o.Interval > 0
+}
+
+// File is an output file rotated between lines, the rotated files are
+// named <name>-<time of rotation><extension>, e.g. live-20230401-120000.txt
+type File struct {
+	name    string
+	opts    Options
+	file    *os.File
+	size    int64
+	lineEnd bool // the last written byte is a line end
+	opened  time.Time
+	wg      sync.WaitGroup // compressions running in the background
+	mu      sync.Mutex
+	errs    []error // errors of the compressions
+	now     func() time.Time
+	Rotated int // number of rotated files
+}
+
+// Create creates the output file
+func Create(name string, opts Options) (*File, error) {
+	f := &File{name: name, opts: opts, now: time.Now}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *File) open() error {
+	file, err := os.Create(f.name)
+	if err != nil {
+		return err
+	}
+	f.file, f.size, f.opened = file, 0, f.now()
+	return nil
+}
+
+// due reports if the file is rotated before writing n bytes
+func (f *File) due(n int) bool {
+	if f.size == 0 {
+		return false
+	}
+	if f.opts.Size > 0 && f.size+int64(n) > f.opts.Size {
+		return true
+	}
+	return f.opts.Interval > 0 && f.now().Sub(f.opened) >= f.opts.Interval
+}
+
+// Write writes p, the file is rotated before p if due, a line already
+// started is completed before the rotation
+func (f *File) Write(p []byte) (int, error) {
+	if !f.due(len(p)) {
+		return f.write(p)
+	}
+	end := 0
+	if !f.lineEnd {
+		end = bytes.IndexByte(p, '\n') + 1
+		if end == 0 {
+			return f.write(p) // rotated at the end of the line
+		}
+	}
+	n, err := f.write(p[:end])
+	if err != nil {
+		return n, err
+	}
+	if err := f.rotate(); err != nil {
+		return n, err
+	}
+	m, err := f.write(p[end:])
+	return n + m, err
+}
+
+func (f *File) write(p []byte) (int, error) {
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	if n > 0 {
+		f.lineEnd = p[n-1] == '\n'
+	}
+	return n, err
+}
+
+// name of a rotated file, not used yet
+func (f *File) rotatedName() string {
+	ext := filepath.Ext(f.name)
+	base := strings.TrimSuffix(f.name, ext) + "-" + f.now().Format("20060102-150405")
+	name := base + ext
+	for i := 1; exists(name) || exists(name+".gz"); i++ {
+		name = fmt.Sprintf("%s-%d%s", base, i, ext)
+	}
+	return name
+}
+
+func exists(name string) bool {
+	_, err := os.Stat(name)
+	return err == nil
+}
+
+// rename the file and create a new one
+func (f *File) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	rotated := f.rotatedName()
+	if err := os.Rename(f.name, rotated); err != nil {
+		return err
+	}
+	f.Rotated++
+	if f.opts.Compress {
+		f.wg.Add(1)
+		go func() {
+			defer f.wg.Done()
+			if err := compress(rotated); err != nil {
+				f.mu.Lock()
+				f.errs = append(f.errs, err)
+				f.mu.Unlock()
+			}
+		}()
+	}
+	return f.open()
+}
+
+// compress a rotated file to <name>.gz and remove it
+func compress(name string) error {
+	in, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(name + ".gz")
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(out)
+	zw.Name = filepath.Base(name)
+	_, err = io.Copy(zw, in)
+	if e := zw.Close(); err == nil {
+		err = e
+	}
+	if e := out.Close(); err == nil {
+		err = e
+	}
+	if err != nil {
+		_ = os.Remove(name + ".gz")
+		return err
+	}
+	_ = in.Close()
+	return os.Remove(name)
+}
+
+// Close closes the file and waits for the compressions
+func (f *File) Close() error {
+	err := f.file.Close()
+	f.wg.Wait()
+	if err == nil && len(f.errs) != 0 {
+		err = f.errs[0]
+	}
+	return err
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rotate
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// contents of the files in dir by name
+func readDir(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string)
+	for _, e := range entries {
+		f, err := os.Open(filepath.Join(dir, e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var r io.Reader = f
+		if strings.HasSuffix(e.Name(), ".gz") {
+			if r, err = gzip.NewReader(f); err != nil {
+				t.Fatal(err)
+			}
+		}
+		data, err := io.ReadAll(r)
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[e.Name()] = string(data)
+	}
+	return files
+}
+
+func names(files map[string]string) []string {
+	list := make([]string, 0, len(files))
+	for name := range files {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
+
+func TestFile(t *testing.T) {
+	t.Parallel()
+
+	clock := time.Date(2023, 4, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		opts   Options
+		writes []string
+		step   time.Duration // clock advance before every write
+		want   map[string]string
+	}{
+		{"size", Options{Size: 10}, []string{"line 1\n", "line 2\nline", " 3\n", "line 4\n"}, 0, map[string]string{
+			"live-20230401-120000.txt":   "line 1\n",
+			"live-20230401-120000-1.txt": "line 2\nline 3\n",
+			"live.txt":                   "line 4\n",
+		}},
+		{"split line", Options{Size: 8}, []string{"a\n", "bcdefgh", "ij\n"}, 0, map[string]string{
+			"live-20230401-120000.txt":   "a\n",
+			"live-20230401-120000-1.txt": "bcdefghij\n",
+			"live.txt":                   "",
+		}},
+		{"interval", Options{Interval: time.Minute}, []string{"1\n", "2\n", "3\n", "4\n"}, 40 * time.Second, map[string]string{
+			"live-20230401-120120.txt": "1\n",
+			"live-20230401-120240.txt": "2\n3\n",
+			"live.txt":                 "4\n",
+		}},
+		{"compress", Options{Size: 4, Compress: true}, []string{"abc\n", "def\n"}, 0, map[string]string{
+			"live-20230401-120000.txt.gz": "abc\n",
+			"live.txt":                    "def\n",
+		}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			now := clock
+			f, err := Create(filepath.Join(dir, "live.txt"), tt.opts)
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			f.now = func() time.Time { return now }
+			f.opened = now
+			for _, w := range tt.writes {
+				now = now.Add(tt.step)
+				if n, err := f.Write([]byte(w)); err != nil || n != len(w) {
+					t.Fatalf("File.Write() %s = %d, %v, want %d", tt.name, n, err, len(w))
+				}
+			}
+			if err := f.Close(); err != nil {
+				t.Fatalf("File.Close() error = %v", err)
+			}
+			if got := readDir(t, dir); len(got) != len(tt.want) {
+				t.Errorf("File %s = %v, want %v", tt.name, names(got), names(tt.want))
+			} else {
+				for name, want := range tt.want {
+					if got[name] != want {
+						t.Errorf("File %s %s = %q, want %q", tt.name, name, got[name], want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	t.Parallel()
+
+	if _, err := Create(filepath.Join(t.TempDir(), "missing", "live.txt"), Options{Size: 1}); err == nil {
+		t.Errorf("Create() error = nil, want error")
+	}
+	if (Options{}).Enabled() || !(Options{Interval: time.Second}).Enabled() {
+		t.Errorf("Options.Enabled() = wrong")
+	}
+}
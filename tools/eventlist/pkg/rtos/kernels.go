@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rtos
+
+import (
+	_ "embed" // the event definitions of the kernels
+	"errors"
+	"fmt"
+	"sort"
+)
+
+//go:embed freertos.scvd
+var freertosSCVD []byte
+
+//go:embed zephyr.scvd
+var zephyrSCVD []byte
+
+var errKernel = errors.New("unknown RTOS")
+
+// definitions are the SCVD files of the kernels without own SCVD file
+var definitions = map[string][]byte{
+	"freertos": freertosSCVD,
+	"zephyr":   zephyrSCVD,
+}
+
+// Kernels returns the names of the kernels with bundled event definitions
+func Kernels() []string {
+	names := make([]string, 0, len(definitions))
+	for name := range definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Definitions returns the bundled SCVD file of a kernel
+func Definitions(kernel string) ([]byte, error) {
+	data, ok := definitions[kernel]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errKernel, kernel)
+	}
+	return data, nil
+}
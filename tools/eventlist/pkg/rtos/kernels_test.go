@@ -0,0 +1,140 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rtos
+
+import (
+	"bytes"
+	"errors"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/event"
+	"eventlist/pkg/xml/scvd"
+	"eventlist/pkg/xml/xsd"
+	"reflect"
+	"testing"
+)
+
+func TestDefinitions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		kernel  string
+		wantErr error
+	}{
+		{"freertos", "freertos", nil},
+		{"zephyr", "zephyr", nil},
+		{"unknown", "rtx4", errKernel},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := Definitions(tt.kernel)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Definitions() %s error = %v, want %v", tt.name, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			violations, err := xsd.SCVD().Validate(bytes.NewReader(data))
+			if err != nil || len(violations) != 0 {
+				t.Errorf("Definitions() %s = %v, %v, want valid SCVD file", tt.name, violations, err)
+			}
+			evdefs := make(map[uint16]scvd.Event)
+			if err := scvd.Parse(bytes.NewReader(data), evdefs, make(map[string]map[string]map[int16]string), make(map[string]map[string]string)); err != nil {
+				t.Errorf("Definitions() %s error = %v", tt.name, err)
+			}
+		})
+	}
+	if got := Kernels(); !reflect.DeepEqual(got, []string{"freertos", "zephyr"}) {
+		t.Errorf("Kernels() = %v, want [freertos zephyr]", got)
+	}
+}
+
+// pack a name into the values of an event in memory order
+func packed(name string) []int32 {
+	var b [12]byte
+	copy(b[:], name)
+	return []int32{int32(event.ByteOrder.Uint32(b[0:])), int32(event.ByteOrder.Uint32(b[4:])),
+		int32(event.ByteOrder.Uint32(b[8:]))}
+}
+
+func TestTracker_kernels(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                     string
+		kernel                   string
+		create, switched, delete uint16
+		idle                     string
+		object                   uint16 // creation of an object without name
+	}{
+		{"freertos", "freertos", 0xF000, 0xF003, 0xF002, "IDLE", 0xF100},
+		{"zephyr", "zephyr", 0xF000, 0xF002, 0xF004, "idle", 0xF200},
+		{"zephyr name", "zephyr", 0xF001, 0xF002, 0xF004, "idle", 0xF203},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			data, _ := Definitions(tt.kernel)
+			evdefs := make(map[uint16]scvd.Event)
+			if err := scvd.Parse(bytes.NewReader(data), evdefs, make(map[string]map[string]map[int16]string), make(map[string]map[string]string)); err != nil {
+				t.Fatalf("scvd.Parse() %s error = %v", tt.name, err)
+			}
+			record := func(time float64, id uint16, values ...int32) *bus.Event {
+				values = append(values, 0, 0, 0, 0)
+				ev := &event.Data{Typ: 3, Value1: values[0], Value2: values[1], Value3: values[2], Value4: values[3],
+					Info: event.Info{ID: id}}
+				def := evdefs[id]
+				return bus.NewEvent(0, time, ev, &def, nil)
+			}
+
+			tr := NewTracker()
+			for _, ev := range []*bus.Event{
+				record(0.0, tt.create, append([]int32{0x100}, packed(tt.idle)...)...),
+				record(0.0, tt.create, append([]int32{0x200}, packed("blinky")...)...),
+				record(0.0, tt.object, 0x300, 1),
+				record(0.5, tt.switched, 0x100, 0),
+				record(1.0, tt.switched, 0x200, 1),
+				record(1.5, tt.delete, 0x200),
+			} {
+				if err := tr.Event(ev); err != nil {
+					t.Errorf("Tracker.Event() %s error = %v", tt.name, err)
+				}
+			}
+			current, running := tr.Current()
+			if current != 0x200 || !running || tr.Name(0x100) != tt.idle || tr.Name(0x200) != "blinky" {
+				t.Errorf("Tracker %s = 0x%X %v %s %s, want 0x200 true %s blinky", tt.name,
+					current, running, tr.Name(0x100), tr.Name(0x200), tt.idle)
+			}
+			if idle, known := tr.IdleTime(2.0); idle != 0.5 || !known {
+				t.Errorf("Tracker.IdleTime() %s = %v, %v, want 0.5, true", tt.name, idle, known)
+			}
+			if name, ok := tr.Object(0x200); ok {
+				t.Errorf("Tracker.Object() %s = %s, want deleted", tt.name, name)
+			}
+			if name, ok := tr.Object(0x300); ok {
+				t.Errorf("Tracker.Object() %s = %s, want object without name", tt.name, name)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rtos
+
+import (
+	"eventlist/pkg/bus"
+	"eventlist/pkg/elf"
+	"eventlist/pkg/event"
+	"fmt"
+	"strings"
+)
+
+// names of the idle threads of the kernels
+var idleNames = map[string]bool{
+	"osRtxIdleThread": true, // RTX5
+	"IDLE":            true, // FreeRTOS
+	"idle":            true, // Zephyr
+}
+
+// properties of the thread switch events of the kernels, val1 is the
+// running thread
+var switchEvents = map[string]bool{
+	"ThreadSwitched":   true, // RTX5
+	"TaskSwitchedIn":   true, // FreeRTOS
+	"ThreadSwitchedIn": true, // Zephyr
+}
+
+// location of the name in an event naming an object
+const (
+	nameVal2   = iota + 1 // val2 is the address of the name
+	nameVal3              // val3 is the address of the name
+	namePacked            // val2 to val4 are the first 12 characters
+)
+
+// events naming an object by property, val1 is the object ID
+var nameEvents = map[string]int{
+	// RTX5
+	"ThreadCreated":       nameVal3,
+	"TimerCreated":        nameVal2,
+	"EventFlagsCreated":   nameVal2,
+	"MutexCreated":        nameVal2,
+	"SemaphoreCreated":    nameVal2,
+	"MemoryPoolCreated":   nameVal2,
+	"MessageQueueCreated": nameVal2,
+	// FreeRTOS
+	"TaskCreated":     namePacked,
+	"QueueCreated":    0, // named by QueueRegistered
+	"QueueRegistered": nameVal2,
+	// Zephyr
+	"ThreadCreate":   namePacked,
+	"ThreadNameSet":  namePacked,
+	"WorkQueueStart": namePacked,
+}
+
+// properties of the events naming a thread
+var threadEvents = map[string]bool{
+	"ThreadCreated": true, // RTX5
+	"TaskCreated":   true, // FreeRTOS
+	"ThreadCreate":  true, // Zephyr
+	"ThreadNameSet": true,
+}
+
+// suffixes of the properties of the events ending an object
+var endSuffixes = []string{"Destroyed", "Deleted", "Aborted"}
+
+// Tracker follows the running thread from the kernel events of RTX5,
+// FreeRTOS or Zephyr
+type Tracker struct {
+	names   map[uint32]string
+	objects map[uint32]string // names of the created objects until destroyed
+	current uint32
+	running bool    // true after the first thread switch
+	since   float64 // time of the last thread switch
+	idle    float64 // time spent in the idle thread until the last switch
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{names: make(map[uint32]string), objects: make(map[uint32]string)}
+}
+
+func (t *Tracker) Event(ev *bus.Event) error {
+	if ev.Def == nil {
+		return nil
+	}
+	t.register(ev)
+	if switchEvents[ev.Def.Property] {
+		if t.running && t.isIdle(t.current) {
+			t.idle += ev.Time - t.since
+		}
+		t.current = uint32(ev.Data.Value1)
+		t.since = ev.Time
+		t.running = true
+	}
+	return nil
+}
+
+// name of an object in the values of an event, empty if there is none
+func objectName(ev *event.Data, location int) string {
+	var ptr int32
+	switch {
+	case location == nameVal2 && (ev.Typ == 2 || ev.Typ == 3):
+		ptr = ev.Value2
+	case location == nameVal3 && ev.Typ == 3:
+		ptr = ev.Value3
+	case location == namePacked && ev.Typ == 3:
+		var b [12]byte
+		for i, v := range []int32{ev.Value2, ev.Value3, ev.Value4} {
+			event.ByteOrder.PutUint32(b[4*i:], uint32(v))
+		}
+		name, _, _ := strings.Cut(string(b[:]), "\x00")
+		return name
+	}
+	if ptr == 0 {
+		return ""
+	}
+	return elf.Sections.GetString(uint64(uint32(ptr)))
+}
+
+// register the name of an object from the event naming it, the name is
+// removed by the <Object>Destroyed, <Object>Deleted or <Object>Aborted event
+func (t *Tracker) register(ev *bus.Event) {
+	id := uint32(ev.Data.Value1)
+	property := ev.Def.Property
+	for _, suffix := range endSuffixes {
+		if strings.HasSuffix(property, suffix) {
+			delete(t.objects, id)
+			return
+		}
+	}
+	location, ok := nameEvents[property]
+	if !ok {
+		return
+	}
+	name := objectName(ev.Data, location)
+	if len(name) == 0 {
+		delete(t.objects, id) // the ID of a destroyed object is reused
+		return
+	}
+	t.objects[id] = name
+	if threadEvents[property] {
+		t.names[id] = name
+	}
+}
+
+// Object returns the name of a created object, false if the ID is no
+// object or the object has no name
+func (t *Tracker) Object(id uint32) (string, bool) {
+	name, ok := t.objects[id]
+	return name, ok
+}
+
+func (t *Tracker) End() error {
+	return nil
+}
+
+func (t *Tracker) isIdle(id uint32) bool {
+	return idleNames[t.names[id]]
+}
+
+// Name returns the thread name, the thread ID if the name is unknown
+func (t *Tracker) Name(id uint32) string {
+	if name, ok := t.names[id]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%08X", id)
+}
+
+// NameSize returns the maximum length of the thread names
+func (t *Tracker) NameSize() int {
+	size := len(t.Name(0)) // unknown names are shown as ID
+	for _, name := range t.names {
+		if len(name) > size {
+			size = len(name)
+		}
+	}
+	return size
+}
+
+// Current returns the running thread, false before the first thread switch
+func (t *Tracker) Current() (uint32, bool) {
+	return t.current, t.running
+}
+
+// IdleTime returns the time spent in the idle thread up to now,
+// false if no idle thread is known
+func (t *Tracker) IdleTime(now float64) (float64, bool) {
+	known := false
+	for _, name := range t.names {
+		if idleNames[name] {
+			known = true
+		}
+	}
+	if !known || !t.running {
+		return 0, false
+	}
+	idle := t.idle
+	if t.isIdle(t.current) && now > t.since {
+		idle += now - t.since
+	}
+	return idle, true
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rtos
+
+import (
+	"eventlist/pkg/bus"
+	"eventlist/pkg/elf"
+	"eventlist/pkg/event"
+	"eventlist/pkg/xml/scvd"
+	"testing"
+)
+
+func switched(time float64, id int32) *bus.Event {
+	return bus.NewEvent(0, time, &event.Data{Typ: 2, Value1: id}, &scvd.Event{Property: "ThreadSwitched"}, nil)
+}
+
+func TestTracker(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		current uint32
+		running bool
+		idle    float64
+		known   bool
+	}
+	tests := []struct {
+		name   string
+		names  map[uint32]string
+		events []*bus.Event
+		now    float64
+		want   want
+	}{
+		{"none", nil, nil, 1.0, want{0, false, 0, false}},
+		{"unknown", nil, []*bus.Event{bus.NewEvent(0, 0.5, &event.Data{Value1: 2}, nil, nil)}, 1.0, want{0, false, 0, false}},
+		{"no idle", map[uint32]string{1: "main"}, []*bus.Event{switched(0.5, 1)}, 1.0, want{1, true, 0, false}},
+		{"idle running", map[uint32]string{1: "main", 2: "osRtxIdleThread"},
+			[]*bus.Event{switched(0.5, 1), switched(1.0, 2)}, 2.0, want{2, true, 1.0, true}},
+		{"idle done", map[uint32]string{1: "main", 2: "osRtxIdleThread"},
+			[]*bus.Event{switched(0.5, 2), switched(1.0, 1), switched(1.5, 2), switched(1.75, 1)}, 2.0, want{1, true, 0.75, true}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tr := NewTracker()
+			for id, name := range tt.names {
+				tr.names[id] = name
+			}
+			for _, ev := range tt.events {
+				if err := tr.Event(ev); err != nil {
+					t.Errorf("Tracker.Event() %s error = %v", tt.name, err)
+				}
+			}
+			if err := tr.End(); err != nil {
+				t.Errorf("Tracker.End() %s error = %v", tt.name, err)
+			}
+			current, running := tr.Current()
+			idle, known := tr.IdleTime(tt.now)
+			got := want{current, running, idle, known}
+			if got != tt.want {
+				t.Errorf("Tracker %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTracker_Name(t *testing.T) {
+	t.Parallel()
+
+	tr := NewTracker()
+	tr.names[1] = "main"
+	tests := []struct {
+		name string
+		id   uint32
+		want string
+	}{
+		{"known", 1, "main"},
+		{"unknown", 0x20001000, "0x20001000"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tr.Name(tt.id); got != tt.want {
+				t.Errorf("Tracker.Name() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTracker_NameSize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		names map[uint32]string
+		want  int
+	}{
+		{"none", nil, 10},
+		{"short", map[uint32]string{1: "main"}, 10},
+		{"long", map[uint32]string{1: "main", 2: "osRtxIdleThread"}, 15},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tr := NewTracker()
+			for id, name := range tt.names {
+				tr.names[id] = name
+			}
+			if got := tr.NameSize(); got != tt.want {
+				t.Errorf("Tracker.NameSize() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func object(property string, typ uint16, id, name int32) *bus.Event {
+	ev := &event.Data{Typ: typ, Value1: id, Value2: name, Value3: name}
+	return bus.NewEvent(0, 0, ev, &scvd.Event{Property: property}, nil)
+}
+
+func TestTracker_Object(t *testing.T) { //nolint:golint,paralleltest
+	saved := elf.Sections
+	defer func() { elf.Sections = saved }()
+	name := "../../testdata/elftest.elf"
+	if err := elf.Sections.Readelf(&name); err != nil {
+		t.Fatalf("Readelf() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		events []*bus.Event
+		id     uint32
+		want   string
+		ok     bool
+	}{
+		{"mutex", []*bus.Event{object("MutexCreated", 2, 0x2000, 0x4010)}, 0x2000, "def", true},
+		{"thread", []*bus.Event{object("ThreadCreated", 3, 0x3000, 0x4011)}, 0x3000, "ef", true},
+		{"destroyed", []*bus.Event{object("MutexCreated", 2, 0x2000, 0x4010), object("MutexDestroyed", 2, 0x2000, 0)}, 0x2000, "", false},
+		{"reused", []*bus.Event{object("MutexCreated", 2, 0x2000, 0x4010), object("SemaphoreCreated", 2, 0x2000, 0)}, 0x2000, "", false},
+		{"data", []*bus.Event{object("MutexCreated", 1, 0x2000, 0x4010)}, 0x2000, "", false},
+		{"other", []*bus.Event{object("MutexCreated", 2, 0x2000, 0x4010)}, 0x4000, "", false},
+	}
+	for _, tt := range tests { //nolint:golint,paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			tr := NewTracker()
+			for _, ev := range tt.events {
+				if err := tr.Event(ev); err != nil {
+					t.Errorf("Tracker.Event() %s error = %v", tt.name, err)
+				}
+			}
+			if got, ok := tr.Object(tt.id); got != tt.want || ok != tt.ok {
+				t.Errorf("Tracker.Object() %s = %v, %v, want %v, %v", tt.name, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package script
+
+import (
+	"bytes"
+	"eventlist/pkg/bus"
+	"fmt"
+	"io"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Script runs a Lua script as analyzer of the decoded event stream.
+// The script may define the functions on_event(ev) and on_end(),
+// results are written with the functions of the report table.
+type Script struct {
+	state   *lua.LState
+	onEvent lua.LValue
+	onEnd   lua.LValue
+	report  bytes.Buffer
+}
+
+// Load runs the main chunk of a script file
+func Load(filename string) (*Script, error) {
+	s := new(Script)
+	s.state = lua.NewState(lua.Options{SkipOpenLibs: true})
+	libs := []struct {
+		name string
+		fn   lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+	}
+	for _, lib := range libs { // no io and os library
+		s.state.Push(s.state.NewFunction(lib.fn))
+		s.state.Push(lua.LString(lib.name))
+		s.state.Call(1, 0)
+	}
+	for _, name := range []string{"dofile", "loadfile", "load", "loadstring", "require", "module"} {
+		s.state.SetGlobal(name, lua.LNil) // no loading of files or other chunks
+	}
+	s.state.SetGlobal("report", s.state.SetFuncs(s.state.NewTable(), map[string]lua.LGFunction{
+		"section": s.section,
+		"print":   s.print,
+		"table":   s.table,
+	}))
+	if err := s.state.DoFile(filename); err != nil {
+		s.state.Close()
+		return nil, err
+	}
+	s.onEvent = s.state.GetGlobal("on_event")
+	s.onEnd = s.state.GetGlobal("on_end")
+	return s, nil
+}
+
+// Event calls on_event with a table of the event fields
+func (s *Script) Event(ev *bus.Event) error {
+	if s.onEvent.Type() != lua.LTFunction {
+		return nil
+	}
+	value, _ := ev.Value()
+	t := s.state.NewTable()
+	t.RawSetString("index", lua.LNumber(ev.Index))
+	t.RawSetString("time", lua.LNumber(ev.Time))
+	t.RawSetString("id", lua.LNumber(ev.Data.Info.ID))
+	t.RawSetString("known", lua.LBool(ev.Def != nil))
+	t.RawSetString("level", lua.LString(ev.Level()))
+	t.RawSetString("component", lua.LString(ev.Component()))
+	t.RawSetString("property", lua.LString(ev.Property()))
+	t.RawSetString("value", lua.LString(value))
+	t.RawSetString("val1", lua.LNumber(ev.Data.Value1))
+	t.RawSetString("val2", lua.LNumber(ev.Data.Value2))
+	t.RawSetString("val3", lua.LNumber(ev.Data.Value3))
+	t.RawSetString("val4", lua.LNumber(ev.Data.Value4))
+	if ev.Data.Data != nil {
+		t.RawSetString("data", lua.LString(*ev.Data.Data))
+	}
+	return s.state.CallByParam(lua.P{Fn: s.onEvent, NRet: 0, Protect: true}, t)
+}
+
+// End calls on_end and closes the interpreter
+func (s *Script) End() error {
+	defer s.state.Close()
+	if s.onEnd.Type() != lua.LTFunction {
+		return nil
+	}
+	return s.state.CallByParam(lua.P{Fn: s.onEnd, NRet: 0, Protect: true})
+}
+
+// Report writes the report built by the script
+func (s *Script) Report(w io.Writer) error {
+	_, err := w.Write(s.report.Bytes())
+	return err
+}
+
+// report.section(title) starts a new report section
+func (s *Script) section(L *lua.LState) int {
+	title := L.CheckString(1)
+	fmt.Fprintf(&s.report, "   %s\n   %s\n\n", title, strings.Repeat("-", len(title)))
+	return 0
+}
+
+// report.print(...) writes one line
+func (s *Script) print(L *lua.LState) int {
+	for i := 1; i <= L.GetTop(); i++ {
+		if i > 1 {
+			s.report.WriteByte(' ')
+		}
+		s.report.WriteString(L.ToStringMeta(L.Get(i)).String())
+	}
+	s.report.WriteByte('\n')
+	return 0
+}
+
+// report.table(headers, rows) writes a table with aligned columns
+func (s *Script) table(L *lua.LState) int {
+	var lines [][]string
+	header := L.CheckTable(1)
+	var cells []string
+	header.ForEach(func(_, v lua.LValue) { cells = append(cells, L.ToStringMeta(v).String()) })
+	lines = append(lines, cells)
+	L.CheckTable(2).ForEach(func(_, row lua.LValue) {
+		cells = nil
+		if t, ok := row.(*lua.LTable); ok {
+			t.ForEach(func(_, v lua.LValue) { cells = append(cells, L.ToStringMeta(v).String()) })
+		}
+		lines = append(lines, cells)
+	})
+	var widths []int
+	for _, line := range lines {
+		for i, cell := range line {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if len(cell) > widths[i] {
+				widths[i] = len(cell)
+			}
+		}
+	}
+	underline := make([]string, len(lines[0]))
+	for i, cell := range lines[0] {
+		underline[i] = strings.Repeat("-", len(cell))
+	}
+	lines = append(lines[:1], append([][]string{underline}, lines[1:]...)...)
+	for _, line := range lines {
+		for i, cell := range line {
+			if i < len(line)-1 {
+				fmt.Fprintf(&s.report, "%*s ", -widths[i], cell)
+			} else {
+				s.report.WriteString(cell)
+			}
+		}
+		s.report.WriteByte('\n')
+	}
+	s.report.WriteByte('\n')
+	return 0
+}
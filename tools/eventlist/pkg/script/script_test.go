@@ -0,0 +1,139 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package script
+
+import (
+	"bytes"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/event"
+	"eventlist/pkg/xml/scvd"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		file    string
+		wantErr bool
+	}{
+		{"ok", "../../testdata/test.lua", false},
+		{"runtime error", "../../testdata/test_err.lua", false},
+		{"missing", "../../testdata/nix.lua", true},
+		{"syntax error", "../../testdata/test.xml", true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := Load(tt.file)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Load() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if s != nil {
+				_ = s.End()
+			}
+		})
+	}
+}
+
+func TestLoad_sandbox(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		code string
+	}{
+		{"dofile", `dofile("../../testdata/test.lua")`},
+		{"loadfile", `loadfile("../../testdata/test.lua")`},
+		{"load", `load(function() return nil end)`},
+		{"loadstring", `loadstring("return 1")`},
+		{"require", `require("os")`},
+		{"module", `module("m")`},
+		{"io", `io.open("../../testdata/test.lua")`},
+		{"os", `os.remove("../../testdata/test.lua")`},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			filename := filepath.Join(t.TempDir(), "sandbox.lua")
+			if err := os.WriteFile(filename, []byte(tt.code), 0600); err != nil {
+				t.Fatalf("os.WriteFile() error = %v", err)
+			}
+			s, err := Load(filename)
+			if err == nil {
+				_ = s.End()
+				t.Errorf("Load() %s error = nil, want error", tt.name)
+			}
+		})
+	}
+}
+
+func TestScript(t *testing.T) {
+	t.Parallel()
+
+	def := scvd.Event{Brief: "MyComp", Property: "Send", Level: "API", Value: "len=%d[val1]"}
+	events := []*bus.Event{
+		bus.NewEvent(0, 0.1, &event.Data{Info: event.Info{ID: 0x0A00}, Typ: 2, Value1: 4}, &def, nil),
+		bus.NewEvent(1, 0.2, &event.Data{Info: event.Info{ID: 0xFF03}, Typ: 2}, nil, nil),
+		bus.NewEvent(2, 0.3, &event.Data{Info: event.Info{ID: 0x0A00}, Typ: 2, Value1: 8}, &def, nil),
+	}
+	want := "   Events per component\n" +
+		"   --------------------\n\n" +
+		"Component Count\n" +
+		"--------- -----\n" +
+		"MyComp    2\n" +
+		"0xFF      1\n\n" +
+		"components: 2\n"
+
+	s, err := Load("../../testdata/test.lua")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	for _, ev := range events {
+		if err = s.Event(ev); err != nil {
+			t.Errorf("Script.Event() error = %v", err)
+		}
+	}
+	if err = s.End(); err != nil {
+		t.Errorf("Script.End() error = %v", err)
+	}
+	var b bytes.Buffer
+	if err = s.Report(&b); err != nil {
+		t.Errorf("Script.Report() error = %v", err)
+	}
+	if b.String() != want {
+		t.Errorf("Script.Report() = %v, want %v", b.String(), want)
+	}
+
+	s, err = Load("../../testdata/test_err.lua")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if err = s.Event(events[0]); err == nil {
+		t.Errorf("Script.Event() error = nil, want error")
+	}
+	_ = s.End()
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scvdgen
+
+import (
+	"eventlist/pkg/xml/scvd"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+var levelMacros = map[string]string{
+	"Error": "EventLevelError", "API": "EventLevelAPI", "Op": "EventLevelOp", "Detail": "EventLevelDetail",
+}
+
+// identifier returns the part of a C identifier of a name, other characters
+// than letters, digits and _ are removed, a following letter is upper case
+func identifier(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if upper {
+				r = unicode.ToUpper(r)
+			}
+			b.WriteRune(r)
+			upper = false
+		case r == '_':
+			b.WriteRune(r)
+		default:
+			upper = true
+		}
+	}
+	return b.String()
+}
+
+// comment returns a text usable in a line comment
+func comment(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// WriteHeader writes a C header with the event IDs of an SCVD file: a
+// component number macro Evt<brief>_No, an ID macro of each event and a
+// macro recording the event with two values. The ID macro is the property
+// if it starts with Evt, e.g. of an SCVD file of scvd-gen, else
+// Evt<brief>_<property>.
+func WriteHeader(w io.Writer, viewer *scvd.ComponentViewer, source string) error {
+	var b strings.Builder
+	guard := strings.ToUpper(identifier(viewer.Component.Name)) + "_EVENTS_H"
+	if unicode.IsDigit(rune(guard[0])) || guard[0] == '_' {
+		guard = "EVT_" + guard
+	}
+	fmt.Fprintf(&b, "/* Event IDs of %s, generated from %s, do not edit */\n\n", comment(viewer.Component.Name), comment(source))
+	fmt.Fprintf(&b, "#ifndef %s\n#define %s\n\n#include \"EventRecorder.h\"\n\n", guard, guard)
+
+	components := make(map[uint8]string)
+	for _, c := range viewer.Events.Group.Component {
+		no, err := number(nil, c.No)
+		if err != nil {
+			return fmt.Errorf("component %s: %w", c.Name, err)
+		}
+		name := c.Brief
+		if len(name) == 0 {
+			name = c.Name
+		}
+		macro := "Evt" + identifier(name) + "_No"
+		components[uint8(no)] = identifier(name)
+		fmt.Fprintf(&b, "#define %-24s 0x%02XU  ///< %s\n", macro, no, comment(c.Name))
+	}
+	if len(components) != 0 {
+		b.WriteString("\n")
+	}
+
+	type definition struct {
+		id, evr, value string
+	}
+	var defs []definition
+	used := make(map[string]bool)
+	for _, e := range viewer.Events.Events {
+		id, err := e.ID.Value()
+		if err != nil {
+			return fmt.Errorf("event %s: %w", e.ID, err)
+		}
+		level, ok := levelMacros[e.Level]
+		if !ok {
+			return fmt.Errorf("event %s: %w: %s", e.ID, errValue, e.Level)
+		}
+		no := fmt.Sprintf("0x%02XU", id>>8)
+		brief, ok := components[uint8(id>>8)]
+		if ok {
+			no = "Evt" + brief + "_No"
+		} else {
+			brief = fmt.Sprintf("%02X", id>>8)
+		}
+		name := identifier(e.Property)
+		if !strings.HasPrefix(name, "Evt") {
+			name = "Evt" + brief + "_" + name
+		}
+		if used[name] {
+			name = fmt.Sprintf("%s_%04X", name, id)
+		}
+		used[name] = true
+		value := fmt.Sprintf("EventID(%s, %s, 0x%02XU)", level, no, id&0xFF)
+		if len(e.Info) != 0 {
+			value = fmt.Sprintf("%-50s ///< %s", value, comment(e.Info))
+		}
+		defs = append(defs, definition{name, "Evr" + strings.TrimPrefix(name, "Evt"), value})
+	}
+	for _, d := range defs {
+		fmt.Fprintf(&b, "#define %-24s %s\n", d.id, d.value)
+	}
+	b.WriteString("\n")
+	for _, d := range defs {
+		fmt.Fprintf(&b, "#define %s(v1, v2) EventRecord2(%s, (uint32_t)(v1), (uint32_t)(v2))\n", d.evr, d.id)
+	}
+	fmt.Fprintf(&b, "\n#endif /* %s */\n", guard)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scvdgen
+
+import (
+	"eventlist/pkg/xml/scvd"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteHeader(t *testing.T) {
+	t.Parallel()
+
+	// the header of the SCVD file of a header defines the same events
+	h, err := Parse(strings.NewReader(header))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	name := filepath.Join(t.TempDir(), "net.scvd")
+	file, _ := os.Create(name)
+	if err := h.Write(file, "Network"); err != nil {
+		t.Fatalf("Header.Write() error = %v", err)
+	}
+	file.Close()
+	viewer, err := scvd.Load(name)
+	if err != nil {
+		t.Fatalf("scvd.Load() error = %v", err)
+	}
+	var b strings.Builder
+	if err := WriteHeader(&b, viewer, "net.scvd"); err != nil {
+		t.Fatalf("WriteHeader() error = %v", err)
+	}
+	got, err := Parse(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := &Header{
+		Components: []Component{{0xA1, "Net"}, {0xB2, "Component0xB2"}},
+		Events: []Event{
+			{"EvtNetInit", 0xA100, "API", ""},
+			{"EvtNetSend", 0xA101, "Op", `packet "sent"`},
+			{"EvtNetFail", 0xA102, "Error", "send <failed>"},
+			{"EvtDrvIrq", 0xB210, "Detail", ""},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("WriteHeader() = %+v, want %+v", got, want)
+	}
+	for _, line := range []string{
+		"/* Event IDs of Network, generated from net.scvd, do not edit */\n\n#ifndef NETWORK_EVENTS_H\n",
+		"#define EvtNet_No                0xA1U  ///< Net\n",
+		"#define EvrNetSend(v1, v2) EventRecord2(EvtNetSend, (uint32_t)(v1), (uint32_t)(v2))\n",
+		"#endif /* NETWORK_EVENTS_H */\n",
+	} {
+		if !strings.Contains(b.String(), line) {
+			t.Errorf("WriteHeader() = %s, want %q", b.String(), line)
+		}
+	}
+}
+
+func TestWriteHeader_names(t *testing.T) {
+	t.Parallel()
+
+	viewer, err := scvd.Load("../../testdata/test.xml")
+	if err != nil {
+		t.Fatalf("scvd.Load() error = %v", err)
+	}
+	viewer.Events.Events = append(viewer.Events.Events, scvd.Event{ID: "0xFE00", Level: "Op", Property: "stdout"},
+		scvd.Event{ID: "0x1001", Level: "API", Property: "1st call"})
+	var b strings.Builder
+	if err := WriteHeader(&b, viewer, "test.xml"); err != nil {
+		t.Fatalf("WriteHeader() error = %v", err)
+	}
+	for _, line := range []string{
+		"#define EvtEvStat_No             0xEFU  ///< Start/Stop Statistics\n",
+		"#define EvtEvStat_StartA0        EventID(EventLevelDetail, EvtEvStat_No, 0x00U)     ///< Call\n",
+		"#define EvtSTDIO_Stdout          EventID(EventLevelOp, EvtSTDIO_No, 0x00U)",
+		"#define EvtSTDIO_Stdout_FE00     EventID(EventLevelOp, EvtSTDIO_No, 0x00U)\n",
+		"#define Evt10_1stCall            EventID(EventLevelAPI, 0x10U, 0x01U)\n",
+	} {
+		if !strings.Contains(b.String(), line) {
+			t.Errorf("WriteHeader() = %s, want %q", b.String(), line)
+		}
+	}
+
+	viewer.Component.Name = "1 Stub"
+	b.Reset()
+	if err := WriteHeader(&b, viewer, "test.xml"); err != nil || !strings.Contains(b.String(), "#ifndef EVT_1STUB_EVENTS_H\n") {
+		t.Errorf("WriteHeader() = %s, %v, want guard EVT_1STUB_EVENTS_H", b.String(), err)
+	}
+	viewer.Events.Events = append(viewer.Events.Events, scvd.Event{ID: "0x1002", Level: "Warning"})
+	if err := WriteHeader(&b, viewer, "test.xml"); err == nil {
+		t.Errorf("WriteHeader() error = nil, want level error")
+	}
+	viewer.Events.Group.Component[0].No = "nix"
+	if err := WriteHeader(&b, viewer, "test.xml"); err == nil {
+		t.Errorf("WriteHeader() error = nil, want component error")
+	}
+}
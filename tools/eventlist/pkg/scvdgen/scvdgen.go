@@ -0,0 +1,181 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package scvdgen writes the skeleton of an SCVD file for the event IDs
+// defined in a C header with the EventID macro of the Event Recorder, and
+// conversely a C header of the event IDs of an SCVD file.
+package scvdgen
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var errNoEvents = errors.New("no EventID definition found")
+var errValue = errors.New("invalid EventID argument")
+
+// Event is an event ID defined in the header
+type Event struct {
+	Name  string // name of the macro
+	ID    uint16
+	Level string
+	Info  string // comment of the definition
+}
+
+// Component is a component number of the events
+type Component struct {
+	No   uint8
+	Name string // name of the component number macro without Evt and _No, e.g. Net of EvtNet_No
+}
+
+// Header are the events and components of a C header
+type Header struct {
+	Components []Component
+	Events     []Event
+}
+
+var levels = map[string]string{
+	"EventLevelError": "Error", "EventLevelAPI": "API", "EventLevelOp": "Op", "EventLevelDetail": "Detail",
+}
+
+var levelNames = []string{"Error", "API", "Op", "Detail"}
+
+var (
+	reEventID = regexp.MustCompile(`^\s*#\s*define\s+(\w+)\s+\(?\s*EventID\s*\(\s*(\w+)\s*,\s*(\w+)\s*,\s*(\w+)\s*\)\s*\)?\s*(?://+<?\s*(.*))?$`)
+	reNumber  = regexp.MustCompile(`^\s*#\s*define\s+(\w+)\s+\(?\s*((?:0[xX])?[0-9a-fA-F]+)[uUlL]*\s*\)?\s*(?://.*|/\*.*)?$`)
+)
+
+// Parse reads the EventID definitions of a header, the arguments are
+// numbers or macros defining numbers, e.g.
+//
+//	#define EvtNet_No   0xA1U
+//	#define EvtNetSend  EventID(EventLevelOp, EvtNet_No, 0x01U)  ///< packet sent
+func Parse(r io.Reader) (*Header, error) {
+	numbers := make(map[string]uint64)
+	h := &Header{}
+	components := make(map[uint8]string)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if m := reEventID.FindStringSubmatch(text); m != nil {
+			level, ok := levels[m[2]]
+			if !ok {
+				n, err := number(numbers, m[2])
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %w", line, err)
+				}
+				level = levelNames[n>>16&3]
+			}
+			no, err := number(numbers, m[3])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			msg, err := number(numbers, m[4])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			if _, ok := components[uint8(no)]; !ok {
+				components[uint8(no)] = componentName(m[3], uint8(no))
+			}
+			h.Events = append(h.Events, Event{Name: m[1], ID: uint16(no&0xFF)<<8 | uint16(msg&0xFF),
+				Level: level, Info: strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(m[5]), "*/"))})
+			continue
+		}
+		if m := reNumber.FindStringSubmatch(text); m != nil {
+			if n, err := strconv.ParseUint(m[2], 0, 32); err == nil {
+				numbers[m[1]] = n
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(h.Events) == 0 {
+		return nil, errNoEvents
+	}
+	for no, name := range components {
+		h.Components = append(h.Components, Component{No: no, Name: name})
+	}
+	sort.Slice(h.Components, func(i, j int) bool { return h.Components[i].No < h.Components[j].No })
+	sort.SliceStable(h.Events, func(i, j int) bool { return h.Events[i].ID < h.Events[j].ID })
+	return h, nil
+}
+
+// number returns the value of a number or a macro defining a number
+func number(numbers map[string]uint64, s string) (uint64, error) {
+	if n, ok := numbers[s]; ok {
+		return n, nil
+	}
+	n, err := strconv.ParseUint(strings.TrimRight(s, "uUlL"), 0, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", errValue, s)
+	}
+	return n, nil
+}
+
+// componentName returns the name of a component number macro, e.g. Net of
+// EvtNet_No, or a name of the number
+func componentName(macro string, no uint8) string {
+	if _, err := strconv.ParseUint(strings.TrimRight(macro, "uUlL"), 0, 32); err == nil {
+		return fmt.Sprintf("Component 0x%02X", no)
+	}
+	name := strings.TrimSuffix(strings.TrimPrefix(macro, "Evt"), "_No")
+	if len(name) == 0 {
+		return macro
+	}
+	return name
+}
+
+// escape a text for an attribute
+func escape(s string) string {
+	var b bytes.Buffer
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// Write writes the SCVD skeleton of the header, the events print their
+// values as hexadecimal numbers
+func (h *Header) Write(w io.Writer, name string) error {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="utf-8"?>
+
+<component_viewer schemaVersion="1.2.1" xmlns:xs="http://www.w3.org/2001/XMLSchema-instance" xs:noNamespaceSchemaLocation="Component_Viewer.xsd">
+
+`)
+	fmt.Fprintf(&b, "  <component name=\"%s\" version=\"1.0.0\"/>\n\n  <events>\n    <group name=\"%s\">\n", escape(name), escape(name))
+	for _, c := range h.Components {
+		fmt.Fprintf(&b, "      <component name=\"%s\" brief=\"%s\" no=\"0x%02X\" prefix=\"\" info=\"\"/>\n",
+			escape(c.Name), escape(c.Name), c.No)
+	}
+	b.WriteString("    </group>\n\n")
+	for _, e := range h.Events {
+		fmt.Fprintf(&b, "    <event id=\"0x%04X\" level=\"%s\" property=\"%s\" value=\"val1=%%x[val1], val2=%%x[val2]\" info=\"%s\"/>\n",
+			e.ID, e.Level, escape(e.Name), escape(e.Info))
+	}
+	b.WriteString("  </events>\n\n</component_viewer>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scvdgen
+
+import (
+	"errors"
+	"eventlist/pkg/xml/scvd"
+	"eventlist/pkg/xml/xsd"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const header = `#include "EventRecorder.h"
+
+#define EvtNet_No       0xA1U  /* network */
+#define EvtNetSend      EventID(EventLevelOp,     EvtNet_No, 0x01U)  ///< packet "sent"
+#define EvtNetInit      (EventID(EventLevelAPI,   EvtNet_No, 0x00U))
+#define EvtNetFail      EventID(EventLevelError,  EvtNet_No, 2)      // send <failed>
+#define EvtDrvIrq       EventID(0x30000U,         0xB2,      0x10)
+`
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	h, err := Parse(strings.NewReader(header))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := &Header{
+		Components: []Component{{0xA1, "Net"}, {0xB2, "Component 0xB2"}},
+		Events: []Event{
+			{"EvtNetInit", 0xA100, "API", ""},
+			{"EvtNetSend", 0xA101, "Op", `packet "sent"`},
+			{"EvtNetFail", 0xA102, "Error", "send <failed>"},
+			{"EvtDrvIrq", 0xB210, "Detail", ""},
+		},
+	}
+	if !reflect.DeepEqual(h, want) {
+		t.Errorf("Parse() = %+v, want %+v", h, want)
+	}
+
+	tests := []struct {
+		name    string
+		header  string
+		wantErr error
+	}{
+		{"none", "#define X 1\n", errNoEvents},
+		{"level", "#define E EventID(Level, 1, 2)\n", errValue},
+		{"no", "#define E EventID(EventLevelOp, No, 2)\n", errValue},
+		{"msg", "#define E EventID(EventLevelOp, 1, Msg)\n", errValue},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := Parse(strings.NewReader(tt.header)); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parse() %s error = %v, want %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHeader_Write(t *testing.T) {
+	t.Parallel()
+
+	h, err := Parse(strings.NewReader(header))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	var b strings.Builder
+	if err := h.Write(&b, "Net & Drv"); err != nil {
+		t.Fatalf("Header.Write() error = %v", err)
+	}
+	violations, err := xsd.SCVD().Validate(strings.NewReader(b.String()))
+	if err != nil || len(violations) != 0 {
+		t.Errorf("Header.Write() = %v, %v, want valid SCVD file", violations, err)
+	}
+	events := make(map[uint16]scvd.Event)
+	if err := scvd.Parse(strings.NewReader(b.String()), events, make(map[string]map[string]map[int16]string), make(map[string]map[string]string)); err != nil {
+		t.Fatalf("scvd.Parse() error = %v", err)
+	}
+	if e := events[0xA101]; e.Brief != "Net" || e.Property != "EvtNetSend" || e.Level != "Op" || e.Info != `packet "sent"` {
+		t.Errorf("Header.Write() event 0xA101 = %+v", e)
+	}
+	if len(events) != 4 || events[0xB210].Brief != "Component 0xB2" {
+		t.Errorf("Header.Write() events = %v, want 4", events)
+	}
+}
@@ -0,0 +1,187 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package selftrace
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// thread IDs of the trace
+const (
+	pipelineTid = 1 // stages of the pipeline in the order of execution
+	stageTid    = 2 // per event stages, the accumulated time of all calls
+)
+
+// Event is an event of the Chrome trace event format, times are in µs
+type Event struct {
+	Name string         `json:"name"`
+	Cat  string         `json:"cat,omitempty"`
+	Ph   string         `json:"ph"`
+	Ts   float64        `json:"ts"`
+	Dur  float64        `json:"dur,omitempty"`
+	Pid  int            `json:"pid"`
+	Tid  int            `json:"tid"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+// Tracer records the stages of the decoder itself. The stages of the
+// pipeline are recorded as spans, the hot path stages executed for every
+// event accumulate their time. All methods of a nil Tracer do nothing,
+// so tracing costs a nil check only if it is disabled.
+type Tracer struct {
+	start  time.Time
+	spans  []Event
+	stages []*Stage
+}
+
+// Stage accumulates the time of a stage executed for every event
+type Stage struct {
+	name  string
+	mu    sync.Mutex // the stage may run in several goroutines
+	total time.Duration
+	calls int
+}
+
+// New starts a trace
+func New() *Tracer {
+	return &Tracer{start: time.Now()}
+}
+
+// Span starts a stage of the pipeline, the returned function ends it
+func (t *Tracer) Span(name string) func() {
+	if t == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		t.spans = append(t.spans, Event{
+			Name: name,
+			Cat:  "pipeline",
+			Ph:   "X",
+			Ts:   micros(start.Sub(t.start)),
+			Dur:  micros(time.Since(start)),
+			Pid:  1,
+			Tid:  pipelineTid,
+		})
+	}
+}
+
+// Stage returns the hot path stage of a name
+func (t *Tracer) Stage(name string) *Stage {
+	if t == nil {
+		return nil
+	}
+	for _, s := range t.stages {
+		if s.name == name {
+			return s
+		}
+	}
+	s := &Stage{name: name}
+	t.stages = append(t.stages, s)
+	return s
+}
+
+// Start returns the start time of a call of the stage
+func (s *Stage) Start() time.Time {
+	if s == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// Stop adds the time since start to the stage
+func (s *Stage) Stop(start time.Time) {
+	if s == nil {
+		return
+	}
+	d := time.Since(start)
+	s.mu.Lock()
+	s.total += d
+	s.calls++
+	s.mu.Unlock()
+}
+
+func micros(d time.Duration) float64 {
+	return float64(d.Nanoseconds()) / 1e3
+}
+
+// Events returns the trace events, the hot path stages are laid out
+// one after the other with the number of calls and the average time
+func (t *Tracer) Events() []Event {
+	if t == nil {
+		return nil
+	}
+	events := []Event{
+		{Name: "process_name", Ph: "M", Pid: 1, Args: map[string]any{"name": "eventlist"}},
+		{Name: "thread_name", Ph: "M", Pid: 1, Tid: pipelineTid, Args: map[string]any{"name": "pipeline"}},
+		{Name: "thread_name", Ph: "M", Pid: 1, Tid: stageTid, Args: map[string]any{"name": "per event stages"}},
+	}
+	events = append(events, t.spans...)
+	ts := 0.0
+	for _, s := range t.stages {
+		ev := Event{
+			Name: s.name,
+			Cat:  "stage",
+			Ph:   "X",
+			Ts:   ts,
+			Dur:  micros(s.total),
+			Pid:  1,
+			Tid:  stageTid,
+			Args: map[string]any{"calls": s.calls},
+		}
+		if s.calls > 0 {
+			ev.Args["average ns"] = s.total.Nanoseconds() / int64(s.calls)
+		}
+		events = append(events, ev)
+		ts += ev.Dur
+	}
+	return events
+}
+
+// Write writes the trace in the JSON object format of Chrome traces,
+// it can be opened with chrome://tracing or https://ui.perfetto.dev
+func (t *Tracer) Write(w io.Writer) error {
+	trace := struct {
+		TraceEvents     []Event `json:"traceEvents"`
+		DisplayTimeUnit string  `json:"displayTimeUnit"`
+	}{t.Events(), "ms"}
+	return json.NewEncoder(w).Encode(trace)
+}
+
+// WriteFile writes the trace to a file
+func (t *Tracer) WriteFile(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	out := bufio.NewWriter(file)
+	err = t.Write(out)
+	if err == nil {
+		err = out.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package selftrace
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTracer_nil(t *testing.T) {
+	t.Parallel()
+
+	var tr *Tracer
+	tr.Span("pass")()
+	s := tr.Stage("read")
+	s.Stop(s.Start())
+	if s != nil {
+		t.Errorf("Tracer.Stage() = %v, want nil", s)
+	}
+	if got := tr.Events(); got != nil {
+		t.Errorf("Tracer.Events() = %v, want nil", got)
+	}
+}
+
+func TestTracer_Events(t *testing.T) {
+	t.Parallel()
+
+	tr := New()
+	end := tr.Span("pass")
+	read := tr.Stage("read")
+	decode := tr.Stage("decode")
+	for i := 0; i < 3; i++ {
+		read.Stop(read.Start())
+		decode.Stop(decode.Start())
+	}
+	decode.total = 3 * time.Microsecond
+	end()
+	if got := tr.Stage("read"); got != read {
+		t.Errorf("Tracer.Stage() = %p, want %p", got, read)
+	}
+
+	events := tr.Events()
+	if len(events) != 6 {
+		t.Fatalf("Tracer.Events() = %v, want 6 events", events)
+	}
+	tests := []struct {
+		name string
+		ev   Event
+		ph   string
+		tid  int
+	}{
+		{"pass", events[3], "X", pipelineTid},
+		{"read", events[4], "X", stageTid},
+		{"decode", events[5], "X", stageTid},
+	}
+	for _, tt := range tests {
+		if tt.ev.Name != tt.name || tt.ev.Ph != tt.ph || tt.ev.Tid != tt.tid {
+			t.Errorf("Tracer.Events() %s = %v, want ph %s tid %d", tt.name, tt.ev, tt.ph, tt.tid)
+		}
+	}
+	if events[5].Ts != events[4].Dur {
+		t.Errorf("Tracer.Events() decode ts = %v, want %v", events[5].Ts, events[4].Dur)
+	}
+	if events[5].Dur != 3 || events[5].Args["calls"] != 3 || events[5].Args["average ns"] != int64(1000) {
+		t.Errorf("Tracer.Events() decode = %v, want dur 3 calls 3 average ns 1000", events[5])
+	}
+}
+
+func TestTracer_Write(t *testing.T) {
+	t.Parallel()
+
+	tr := New()
+	tr.Span("pass")()
+	var b bytes.Buffer
+	if err := tr.Write(&b); err != nil {
+		t.Errorf("Tracer.Write() error = %v", err)
+	}
+	var trace struct {
+		TraceEvents     []Event `json:"traceEvents"`
+		DisplayTimeUnit string  `json:"displayTimeUnit"`
+	}
+	if err := json.Unmarshal(b.Bytes(), &trace); err != nil {
+		t.Errorf("Tracer.Write() = %s, error = %v", b.String(), err)
+	}
+	if len(trace.TraceEvents) != 4 || trace.TraceEvents[3].Name != "pass" || trace.DisplayTimeUnit != "ms" {
+		t.Errorf("Tracer.Write() = %s, want pass span", b.String())
+	}
+}
+
+func TestTracer_WriteFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	tests := []struct {
+		name     string
+		filename string
+		wantErr  bool
+	}{
+		{"ok", filepath.Join(dir, "trace.json"), false},
+		{"nix", filepath.Join(dir, "nix", "trace.json"), true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := New().WriteFile(tt.filename)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Tracer.WriteFile() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if _, statErr := os.Stat(tt.filename); (statErr != nil) != tt.wantErr {
+				t.Errorf("Tracer.WriteFile() %s file error = %v, wantErr %v", tt.name, statErr, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package serve
+
+import (
+	"context"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/event"
+	"eventlist/pkg/output"
+	"eventlist/pkg/serve/pb"
+	"eventlist/pkg/where"
+	"eventlist/pkg/xml/scvd"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Decoder implements the gRPC service of pb/eventlist.proto, every request
+// decodes its capture without a workspace
+type Decoder struct {
+	pb.UnimplementedDecoderServer
+}
+
+func requestOptions(req *pb.DecodeRequest) (Options, []byte) {
+	opts := Options{Log: req.Log, SCVD: req.Scvd, ELF: req.Elf, Where: req.Where, Level: req.Level}
+	if len(req.Log) != 0 {
+		return opts, nil
+	}
+	return opts, req.Data // an empty capture is a missing log file
+}
+
+// Decode streams the decoded events passing the filter of the request
+func (Decoder) Decode(req *pb.DecodeRequest, stream pb.Decoder_DecodeServer) error {
+	var filter *where.Filter
+	if len(req.Where) != 0 {
+		var err error
+		if filter, err = where.Parse(req.Where); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	opts, data := requestOptions(req)
+	sent := false
+	_, err := decodeLog(opts, data, func(rec *output.EventRecord, ev *event.Data, def *scvd.Event) error {
+		level := rec.Level()
+		if req.Level != "" && level != req.Level {
+			return nil
+		}
+		if filter != nil {
+			value := rec.Value
+			if !filter.Match(bus.NewEvent(rec.Index, rec.Time, ev, def,
+				func() (string, error) { return value, nil })) {
+				return nil
+			}
+		}
+		sent = true
+		return stream.Send(&pb.Event{
+			Index:     int64(rec.Index),
+			Time:      rec.Time,
+			Component: rec.Component,
+			Property:  rec.EventProperty,
+			Level:     level,
+			Value:     rec.Value,
+			Thread:    rec.Thread,
+		})
+	})
+	return statusError(err, sent)
+}
+
+// Statistics returns the start/stop statistic of all events of the request
+func (Decoder) Statistics(_ context.Context, req *pb.DecodeRequest) (*pb.StatisticsReply, error) {
+	stats := output.NewStatistic()
+	opts, data := requestOptions(req)
+	_, err := decodeLog(opts, data, func(rec *output.EventRecord, ev *event.Data, def *scvd.Event) error {
+		value := rec.Value
+		return stats.Event(bus.NewEvent(rec.Index, rec.Time, ev, def,
+			func() (string, error) { return value, nil }))
+	})
+	if err != nil {
+		return nil, statusError(err, false)
+	}
+	reply := &pb.StatisticsReply{}
+	for _, s := range stats.Records() {
+		reply.Statistics = append(reply.Statistics, &pb.Statistic{
+			Event:   s.Event,
+			Count:   int64(s.Count),
+			Total:   s.Total,
+			Min:     s.Min,
+			Max:     s.Max,
+			Avg:     s.Avg,
+			First:   s.First,
+			Last:    s.Last,
+			P50:     s.P50,
+			P90:     s.P90,
+			P99:     s.P99,
+			MinTime: s.MinTime,
+			MaxTime: s.MaxTime,
+		})
+	}
+	return reply, nil
+}
+
+// the errors before the first event are caused by the request
+func statusError(err error, sent bool) error {
+	switch {
+	case err == nil:
+		return nil
+	case status.Code(err) != codes.Unknown:
+		return err // error of the stream
+	case sent:
+		return status.Error(codes.DataLoss, err.Error())
+	default:
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+}
+
+// ServeGRPC serves the gRPC decoding service at the address until the
+// listener fails
+func ServeGRPC(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	s := grpc.NewServer()
+	pb.RegisterDecoderServer(s, Decoder{})
+	return s.Serve(l)
+}
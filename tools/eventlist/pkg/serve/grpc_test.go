@@ -0,0 +1,119 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package serve
+
+import (
+	"context"
+	"errors"
+	"eventlist/pkg/serve/pb"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// client of a decoding service served in memory
+func decoderClient(t *testing.T) pb.DecoderClient {
+	t.Helper()
+	l := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	pb.RegisterDecoderServer(s, Decoder{})
+	go func() { _ = s.Serve(l) }()
+	conn, err := grpc.Dial("bufnet", grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return l.Dial() }))
+	if err != nil {
+		t.Fatalf("grpc.Dial() error = %v", err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		s.Stop()
+	})
+	return pb.NewDecoderClient(conn)
+}
+
+func decodeAll(c pb.DecoderClient, req *pb.DecodeRequest) ([]string, error) {
+	stream, err := c.Decode(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+	components := []string{}
+	for {
+		ev, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return components, nil
+		}
+		if err != nil {
+			return components, err
+		}
+		components = append(components, ev.Component)
+	}
+}
+
+func TestDecoder_Decode(t *testing.T) {
+	t.Parallel()
+
+	c := decoderClient(t)
+	data := writeStartStop(t)
+	tests := []struct {
+		name string
+		req  *pb.DecodeRequest
+		want []string
+		code codes.Code
+	}{
+		{"log", &pb.DecodeRequest{Log: "../../testdata/test10.binary", Scvd: []string{"../../testdata/test.xml"}}, []string{"0xFF", "STDIO"}, codes.OK},
+		{"where", &pb.DecodeRequest{Log: "../../testdata/test10.binary", Where: []string{"component=0xFE"}}, []string{"0xFE"}, codes.OK},
+		{"level", &pb.DecodeRequest{Log: "../../testdata/test10.binary", Level: "Error"}, []string{}, codes.OK},
+		{"data", &pb.DecodeRequest{Data: data}, []string{"0xFF", "0xEF", "0xEF", "0xEF", "0xEF"}, codes.OK},
+		{"no log", &pb.DecodeRequest{}, []string{}, codes.InvalidArgument},
+		{"nix log", &pb.DecodeRequest{Log: "../../testdata/nix.binary"}, []string{}, codes.InvalidArgument},
+		{"nix scvd", &pb.DecodeRequest{Log: "../../testdata/test10.binary", Scvd: []string{"nix.xml"}}, []string{}, codes.InvalidArgument},
+		{"bad where", &pb.DecodeRequest{Log: "../../testdata/test10.binary", Where: []string{"x"}}, []string{}, codes.InvalidArgument},
+		{"truncated", &pb.DecodeRequest{Data: data[:len(data)-3]}, []string{"0xFF", "0xEF", "0xEF", "0xEF"}, codes.DataLoss},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := decodeAll(c, tt.req)
+			if !reflect.DeepEqual(got, tt.want) || status.Code(err) != tt.code {
+				t.Errorf("Decoder.Decode() %s = %v, %v, want %v, %v", tt.name, got, err, tt.want, tt.code)
+			}
+		})
+	}
+}
+
+func TestDecoder_Statistics(t *testing.T) {
+	t.Parallel()
+
+	c := decoderClient(t)
+	reply, err := c.Statistics(context.Background(), &pb.DecodeRequest{Data: writeStartStop(t)})
+	if err != nil || len(reply.Statistics) != 1 || reply.Statistics[0].Event != "A(0)" || reply.Statistics[0].Count != 2 {
+		t.Errorf("Decoder.Statistics() = %v, %v, want A(0) with 2 pairs", reply, err)
+	}
+	if _, err = c.Statistics(context.Background(), &pb.DecodeRequest{Log: "../../testdata/nix.binary"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Decoder.Statistics() error = %v, want %v", err, codes.InvalidArgument)
+	}
+}
@@ -0,0 +1,224 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package serve
+
+import (
+	"bufio"
+	"errors"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/elf"
+	"eventlist/pkg/eval"
+	"eventlist/pkg/event"
+	"eventlist/pkg/live"
+	"eventlist/pkg/metrics"
+	"eventlist/pkg/output"
+	"eventlist/pkg/where"
+	"eventlist/pkg/xml/scvd"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/websocket"
+)
+
+// LiveQueue is the number of events queued for a WebSocket client,
+// further events are dropped until the client catches up
+const LiveQueue = 1024
+
+// Live decodes the events of a live source and pushes them to the
+// WebSocket clients of /api/live
+type Live struct {
+	mu       sync.Mutex
+	clients  map[*liveClient]struct{}
+	decoder  *output.Decoder
+	evdefs   map[uint16]scvd.Event
+	elf      elf.State // ELF files of the source, used under decodeMu
+	factor   *float64  // time factor of the source, used under decodeMu
+	metrics  *metrics.Metrics
+	received int
+}
+
+type liveClient struct {
+	events chan Event
+	filter *where.Filter
+	level  string
+}
+
+// NewLive reads the SCVD and ELF files of the options, the log file and
+// the filter of the options are not used
+func NewLive(opts Options) (*Live, error) {
+	l := &Live{clients: make(map[*liveClient]struct{}), evdefs: make(map[uint16]scvd.Event), metrics: metrics.New()}
+	typedefs := make(map[string]map[string]map[int16]string)
+	memberTypes := make(map[string]map[string]string)
+	files := append([]string{}, opts.SCVD...)
+	if err := scvd.Get(&files, l.evdefs, typedefs, memberTypes); err != nil {
+		return nil, err
+	}
+	l.decoder = output.NewDecoder(l.evdefs, typedefs, memberTypes)
+	if len(opts.ELF) != 0 {
+		decodeMu.Lock()
+		defer decodeMu.Unlock()
+		saved := elf.Current()
+		defer elf.Use(saved)
+		elf.Use(elf.State{})
+		if err := elf.Sections.Readelf(&opts.ELF); err != nil {
+			return nil, err
+		}
+		l.elf = elf.Current()
+	}
+	return l, nil
+}
+
+// Run decodes the events of the source until it ends
+func (l *Live) Run(in io.Reader) error {
+	l.metrics.SetLost(func() int64 { return live.Lost(in) })
+	rd := bufio.NewReader(in)
+	for {
+		var ev event.Data
+		if err := ev.Read(rd); err != nil {
+			if errors.Is(err, eval.ErrEof) {
+				return nil
+			}
+			return err
+		}
+		l.publish(&ev, l.record(&ev))
+	}
+}
+
+// format an event with the ELF files and the time factor of the source
+func (l *Live) record(ev *event.Data) output.EventRecord {
+	decodeMu.Lock()
+	defer decodeMu.Unlock()
+	saved, savedFactor := elf.Current(), output.TimeFactor
+	defer func() {
+		elf.Use(saved)
+		output.TimeFactor = savedFactor
+	}()
+	elf.Use(l.elf)
+	output.TimeFactor = l.factor
+	rec := l.decoder.Record(ev)
+	l.factor = output.TimeFactor
+	return rec
+}
+
+// push an event to the clients of its filter
+func (l *Live) publish(ev *event.Data, rec output.EventRecord) {
+	e := Event{
+		Index:     rec.Index,
+		Time:      rec.Time,
+		Component: rec.Component,
+		Property:  rec.EventProperty,
+		Level:     rec.Level(),
+		Value:     rec.Value,
+		Thread:    rec.Thread,
+	}
+	var def *scvd.Event
+	if evdef, ok := l.evdefs[ev.Info.ID]; ok {
+		def = &evdef
+	}
+	be := bus.NewEvent(rec.Index, rec.Time, ev, def, func() (string, error) { return rec.Value, nil })
+	_ = l.metrics.Event(be)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.received++
+	for c := range l.clients {
+		if (c.level != "" && e.Level != c.level) || !c.filter.Match(be) {
+			continue
+		}
+		select {
+		case c.events <- e:
+		default: // the client is too slow
+		}
+	}
+}
+
+// Received returns the number of events received from the source
+func (l *Live) Received() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.received
+}
+
+func (l *Live) subscribe(c *liveClient) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.clients[c] = struct{}{}
+}
+
+func (l *Live) unsubscribe(c *liveClient) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.clients, c)
+}
+
+var errOrigin = errors.New("WebSocket origin not allowed")
+
+// accept the WebSocket handshakes of the pages of the server only, a page of
+// another site open in the browser must not read the events, clients other
+// than browsers send no origin
+func checkOrigin(_ *websocket.Config, r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		if r.Header.Get("Sec-Fetch-Mode") != "" || strings.HasPrefix(r.Header.Get("User-Agent"), "Mozilla/") {
+			return fmt.Errorf("%w: no origin of a browser", errOrigin)
+		}
+		return nil
+	}
+	u, err := url.Parse(origin)
+	if err != nil || u.Host != r.Host {
+		return fmt.Errorf("%w: %s", errOrigin, origin)
+	}
+	return nil
+}
+
+// ServeHTTP sends the events passing the filter of the query, e.g.
+// ?where=component=Net&level=Error, as JSON messages of the WebSocket, the
+// handshake of a page of another origin is rejected
+func (l *Live) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := &liveClient{events: make(chan Event, LiveQueue), level: r.URL.Query().Get("level")}
+	if wheres := r.URL.Query()["where"]; len(wheres) != 0 {
+		var err error
+		if c.filter, err = where.Parse(wheres); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	websocket.Server{Handshake: checkOrigin, Handler: func(ws *websocket.Conn) {
+		l.subscribe(c)
+		defer l.unsubscribe(c)
+		closed := make(chan struct{})
+		go func() {
+			_, _ = io.Copy(io.Discard, ws) // ends when the client closes
+			close(closed)
+		}()
+		for {
+			select {
+			case e := <-c.events:
+				if err := websocket.JSON.Send(ws, e); err != nil {
+					return
+				}
+			case <-closed:
+				return
+			}
+		}
+	}}.ServeHTTP(w, r)
+}
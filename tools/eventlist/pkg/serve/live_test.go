@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package serve
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+func TestLive(t *testing.T) {
+	t.Parallel()
+
+	l, err := NewLive(Options{SCVD: []string{"../../testdata/test.xml"}})
+	if err != nil {
+		t.Fatalf("NewLive() error = %v", err)
+	}
+	s := New()
+	s.Live = l
+	server := httptest.NewServer(s)
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/live"
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"all", "", []string{"0xFF", "EvStat", "0xEF", "EvStat", "0xEF"}},
+		{"where", "?where=id=0xEF00", []string{"EvStat", "EvStat"}},
+		{"level", "?level=Error", []string{}},
+	}
+	conns := make([]*websocket.Conn, len(tests))
+	for i, tt := range tests {
+		if conns[i], err = websocket.Dial(url+tt.query, "", server.URL); err != nil {
+			t.Fatalf("websocket.Dial() %s error = %v", tt.name, err)
+		}
+		defer conns[i].Close()
+	}
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(time.Millisecond) {
+		l.mu.Lock()
+		n := len(l.clients)
+		l.mu.Unlock()
+		if n == len(tests) || time.Now().After(deadline) {
+			break
+		}
+	}
+
+	r, w := io.Pipe()
+	done := make(chan error)
+	go func() { done <- l.Run(r) }()
+	_, _ = w.Write(writeStartStop(t))
+	w.Close()
+	if err := <-done; err != nil {
+		t.Errorf("Live.Run() error = %v", err)
+	}
+	if got := l.Received(); got != 5 {
+		t.Errorf("Live.Received() = %d, want %d", got, 5)
+	}
+	for i, tt := range tests {
+		got := []string{}
+		for len(got) < len(tt.want) {
+			var e Event
+			if err := websocket.JSON.Receive(conns[i], &e); err != nil {
+				t.Fatalf("websocket.JSON.Receive() %s error = %v", tt.name, err)
+			}
+			got = append(got, e.Component)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Live %s = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	for _, want := range []string{
+		`eventlist_events_total{component="EvStat",id="0xEF00",property=`,
+		`eventlist_duration_seconds_count{event="A(0)"} 2`,
+		"eventlist_lost_frames_total 0\n",
+	} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("GET /metrics = %s, want %s", rec.Body.String(), want)
+		}
+	}
+	if code := request(t, New(), "GET", "/metrics", nil, nil); code != http.StatusNotFound {
+		t.Errorf("GET /metrics without source = %d, want %d", code, http.StatusNotFound)
+	}
+	if code := request(t, s, "GET", "/api/live?where=x", nil, nil); code != http.StatusBadRequest {
+		t.Errorf("GET /api/live?where=x = %d, want %d", code, http.StatusBadRequest)
+	}
+	if code := request(t, New(), "GET", "/api/live", nil, nil); code != http.StatusNotFound {
+		t.Errorf("GET /api/live without source = %d, want %d", code, http.StatusNotFound)
+	}
+	if _, err := NewLive(Options{SCVD: []string{"nix.xml"}}); err == nil {
+		t.Errorf("NewLive() error = %v, want error", err)
+	}
+	if ws, err := websocket.Dial(url, "", "http://evil.example"); err == nil {
+		ws.Close()
+		t.Errorf("websocket.Dial() of a foreign origin error = nil, want error")
+	}
+}
+
+func Test_checkOrigin(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		header  map[string]string
+		wantErr bool
+	}{
+		{"same host", map[string]string{"Origin": "http://localhost:8080"}, false},
+		{"foreign", map[string]string{"Origin": "http://evil.example"}, true},
+		{"other port", map[string]string{"Origin": "http://localhost:9090"}, true},
+		{"invalid", map[string]string{"Origin": "http://%zz"}, true},
+		{"no origin", map[string]string{"User-Agent": "curl/8.0"}, false},
+		{"browser", map[string]string{"User-Agent": "Mozilla/5.0"}, true},
+		{"fetch", map[string]string{"Sec-Fetch-Mode": "websocket"}, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/live", nil)
+			for key, value := range tt.header {
+				r.Header.Set(key, value)
+			}
+			if err := checkOrigin(nil, r); (err != nil) != tt.wantErr {
+				t.Errorf("checkOrigin() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+}
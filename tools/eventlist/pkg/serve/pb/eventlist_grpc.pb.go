@@ -0,0 +1,197 @@
+//
+// Copyright (c) 2023 Arm Limited. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the License); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an AS IS BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Decoding service of eventlist, served with "eventlist serve --grpc <address>"
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: eventlist.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Decoder_Decode_FullMethodName     = "/eventlist.Decoder/Decode"
+	Decoder_Statistics_FullMethodName = "/eventlist.Decoder/Statistics"
+)
+
+// DecoderClient is the client API for Decoder service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DecoderClient interface {
+	// Decode streams the decoded events of a capture passing the filter
+	Decode(ctx context.Context, in *DecodeRequest, opts ...grpc.CallOption) (Decoder_DecodeClient, error)
+	// Statistics returns the start/stop statistic of all events of a capture
+	Statistics(ctx context.Context, in *DecodeRequest, opts ...grpc.CallOption) (*StatisticsReply, error)
+}
+
+type decoderClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDecoderClient(cc grpc.ClientConnInterface) DecoderClient {
+	return &decoderClient{cc}
+}
+
+func (c *decoderClient) Decode(ctx context.Context, in *DecodeRequest, opts ...grpc.CallOption) (Decoder_DecodeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Decoder_ServiceDesc.Streams[0], Decoder_Decode_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &decoderDecodeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Decoder_DecodeClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type decoderDecodeClient struct {
+	grpc.ClientStream
+}
+
+func (x *decoderDecodeClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *decoderClient) Statistics(ctx context.Context, in *DecodeRequest, opts ...grpc.CallOption) (*StatisticsReply, error) {
+	out := new(StatisticsReply)
+	err := c.cc.Invoke(ctx, Decoder_Statistics_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DecoderServer is the server API for Decoder service.
+// All implementations must embed UnimplementedDecoderServer
+// for forward compatibility
+type DecoderServer interface {
+	// Decode streams the decoded events of a capture passing the filter
+	Decode(*DecodeRequest, Decoder_DecodeServer) error
+	// Statistics returns the start/stop statistic of all events of a capture
+	Statistics(context.Context, *DecodeRequest) (*StatisticsReply, error)
+	mustEmbedUnimplementedDecoderServer()
+}
+
+// UnimplementedDecoderServer must be embedded to have forward compatible implementations.
+type UnimplementedDecoderServer struct {
+}
+
+func (UnimplementedDecoderServer) Decode(*DecodeRequest, Decoder_DecodeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Decode not implemented")
+}
+func (UnimplementedDecoderServer) Statistics(context.Context, *DecodeRequest) (*StatisticsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Statistics not implemented")
+}
+func (UnimplementedDecoderServer) mustEmbedUnimplementedDecoderServer() {}
+
+// UnsafeDecoderServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DecoderServer will
+// result in compilation errors.
+type UnsafeDecoderServer interface {
+	mustEmbedUnimplementedDecoderServer()
+}
+
+func RegisterDecoderServer(s grpc.ServiceRegistrar, srv DecoderServer) {
+	s.RegisterService(&Decoder_ServiceDesc, srv)
+}
+
+func _Decoder_Decode_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DecodeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DecoderServer).Decode(m, &decoderDecodeServer{stream})
+}
+
+type Decoder_DecodeServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type decoderDecodeServer struct {
+	grpc.ServerStream
+}
+
+func (x *decoderDecodeServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Decoder_Statistics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DecoderServer).Statistics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Decoder_Statistics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DecoderServer).Statistics(ctx, req.(*DecodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Decoder_ServiceDesc is the grpc.ServiceDesc for Decoder service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Decoder_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "eventlist.Decoder",
+	HandlerType: (*DecoderServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Statistics",
+			Handler:    _Decoder_Statistics_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Decode",
+			Handler:       _Decoder_Decode_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "eventlist.proto",
+}
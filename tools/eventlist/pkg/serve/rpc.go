@@ -0,0 +1,205 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package serve
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+)
+
+// JSON-RPC error codes
+const (
+	codeParse          = -32700
+	codeInvalidRequest = -32600
+	codeMethod         = -32601
+	codeParams         = -32602
+	codeServer         = -32000 // the request failed, e.g. of an unknown workspace
+)
+
+var errHeader = errors.New("missing Content-Length header")
+
+type rpcRequest struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type rpcResponse struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// parameters of the requests of a workspace
+type rpcParams struct {
+	ID     string   `json:"id"`
+	Where  []string `json:"where,omitempty"`
+	Level  string   `json:"level,omitempty"`
+	Offset *int     `json:"offset,omitempty"`
+	Limit  *int     `json:"limit,omitempty"`
+	Time   float64  `json:"time,omitempty"`
+}
+
+// read the content of the next message, nil at the end of the input
+func readMessage(in *bufio.Reader) ([]byte, error) {
+	header, err := textproto.NewReader(in).ReadMIMEHeader()
+	if err != nil {
+		if errors.Is(err, io.EOF) && len(header) == 0 {
+			return nil, nil
+		}
+		return nil, err
+	}
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil || length < 0 {
+		return nil, errHeader
+	}
+	content := make([]byte, length)
+	_, err = io.ReadFull(in, content)
+	return content, err
+}
+
+func writeMessage(out *bufio.Writer, v any) error {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(out, "Content-Length: %d\r\n\r\n", len(content)); err != nil {
+		return err
+	}
+	if _, err := out.Write(content); err != nil {
+		return err
+	}
+	return out.Flush()
+}
+
+// ServeRPC answers the JSON-RPC 2.0 requests read from in, framed with the
+// Content-Length header of the Language Server Protocol, until in ends or
+// the exit notification. The methods are
+//
+//	list                          the Info of all workspaces
+//	open    Options               open a workspace, returns its Info
+//	filter  {id, where, level}    set the Filter, resets the cursor
+//	page    {id, offset, limit}   page of the filtered events, without
+//	                              offset the page at the cursor
+//	stats   {id}                  start/stop statistic of all events
+//	state   {id, time}            system state at the time in seconds
+//	close   {id}                  close a workspace
+//	exit                          end the session
+func (s *Server) ServeRPC(in io.Reader, out io.Writer) error {
+	rd := bufio.NewReader(in)
+	wr := bufio.NewWriter(out)
+	for {
+		content, err := readMessage(rd)
+		if err != nil || content == nil {
+			return err
+		}
+		var req rpcRequest
+		resp := rpcResponse{Version: "2.0", ID: json.RawMessage("null")}
+		if err := json.Unmarshal(content, &req); err != nil {
+			resp.Error = &rpcError{codeParse, err.Error()}
+		} else if req.Version != "2.0" || req.Method == "" {
+			resp.Error = &rpcError{codeInvalidRequest, "invalid request"}
+		} else {
+			if req.Method == "exit" {
+				return nil
+			}
+			if len(req.ID) == 0 {
+				_, _ = s.call(req.Method, req.Params) // notification without response
+				continue
+			}
+			resp.ID = req.ID
+			resp.Result, resp.Error = s.call(req.Method, req.Params)
+			if resp.Error == nil && resp.Result == nil {
+				resp.Result = json.RawMessage("null")
+			}
+		}
+		if err := writeMessage(wr, resp); err != nil {
+			return err
+		}
+	}
+}
+
+// call a method of the JSON-RPC API
+func (s *Server) call(method string, raw json.RawMessage) (any, *rpcError) {
+	switch method {
+	case "list":
+		return s.List(), nil
+	case "open":
+		var opts Options
+		if err := json.Unmarshal(raw, &opts); err != nil {
+			return nil, &rpcError{codeParams, err.Error()}
+		}
+		ws, err := s.Open(opts)
+		if err != nil {
+			return nil, &rpcError{codeServer, err.Error()}
+		}
+		return ws.Info(), nil
+	case "filter", "page", "stats", "state", "close":
+	default:
+		return nil, &rpcError{codeMethod, "unknown method " + method}
+	}
+	var p rpcParams
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, &rpcError{codeParams, err.Error()}
+	}
+	ws, err := s.Get(p.ID)
+	if err != nil {
+		return nil, &rpcError{codeServer, err.Error()}
+	}
+	switch method {
+	case "filter":
+		if err := ws.SetFilter(Filter{Where: p.Where, Level: p.Level}); err != nil {
+			return nil, &rpcError{codeParams, err.Error()}
+		}
+		return ws.Info(), nil
+	case "page":
+		offset, limit := -1, DefaultLimit // the page at the cursor
+		if p.Offset != nil {
+			offset = *p.Offset
+		}
+		if p.Limit != nil {
+			limit = *p.Limit
+		}
+		if (p.Offset != nil && offset < 0) || limit < 0 {
+			return nil, &rpcError{codeParams, "negative offset or limit"}
+		}
+		return ws.Page(offset, limit), nil
+	case "stats":
+		return ws.Statistics(), nil
+	case "state":
+		if p.Time < 0 {
+			return nil, &rpcError{codeParams, "negative time"}
+		}
+		return ws.State(p.Time), nil
+	}
+	_ = s.Close(p.ID)
+	return nil, nil
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package serve
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// frame the messages with the Content-Length header
+func frame(messages ...string) string {
+	var b strings.Builder
+	for _, m := range messages {
+		fmt.Fprintf(&b, "Content-Length: %d\r\n\r\n%s", len(m), m)
+	}
+	return b.String()
+}
+
+type testResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+func TestServer_ServeRPC(t *testing.T) {
+	t.Parallel()
+
+	s := New()
+	ws, err := s.Open(Options{Log: "../../testdata/test10.binary", SCVD: []string{"../../testdata/test.xml"}})
+	if err != nil {
+		t.Fatalf("Server.Open() error = %v", err)
+	}
+	id := ws.ID()
+	in := frame(
+		`{"jsonrpc":"2.0","id":1,"method":"list"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"filter","params":{"id":"`+id+`","where":["component=STDIO"]}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"page","params":{"id":"`+id+`"}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"page","params":{"id":"`+id+`","offset":-1}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"stats","params":{"id":"`+id+`"}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"state","params":{"id":"`+id+`","time":1}}`,
+		`{"jsonrpc":"2.0","id":7,"method":"nix"}`,
+		`{"jsonrpc":"2.0","id":8,"method":"page","params":{"id":"nix"}}`,
+		`{"jsonrpc":"2.0","id":9,"method":"open","params":{"log":"../../testdata/nix.binary"}}`,
+		`{"jsonrpc":"2.0","id":10,"method":"open","params":[1]}`,
+		`{"jsonrpc":"2.0","method":"close","params":{"id":"`+id+`"}}`,
+		`{"jsonrpc":"2.0","id":"a","method":"list"}`,
+		`{"id":11,"method":"list"}`,
+		`{"jsonrpc":`,
+		`{"jsonrpc":"2.0","method":"exit"}`,
+		`{"jsonrpc":"2.0","id":12,"method":"list"}`,
+	)
+	var out bytes.Buffer
+	if err := s.ServeRPC(strings.NewReader(in), &out); err != nil {
+		t.Fatalf("Server.ServeRPC() error = %v", err)
+	}
+	want := []struct {
+		id     string
+		result string
+		code   int
+	}{
+		{"1", `[{"id":"` + id + `","options":{"log":"../../testdata/test10.binary","scvd":["../../testdata/test.xml"]},"events":2,"filtered":2,"cursor":0}]`, 0},
+		{"2", `{"id":"` + id + `","options":{"log":"../../testdata/test10.binary","scvd":["../../testdata/test.xml"],"where":["component=STDIO"]},"events":2,"filtered":1,"cursor":0}`, 0},
+		{"3", `{"events":[{"index":1,"time":0.00000124,"component":"STDIO","property":"stdout","level":"Op","value":"hello wo"}],"offset":0,"next":1,"total":1}`, 0},
+		{"4", "", codeParams},
+		{"5", `[]`, 0},
+		{"6", "", 0},
+		{"7", "", codeMethod},
+		{"8", "", codeServer},
+		{"9", "", codeServer},
+		{"10", "", codeParams},
+		{`"a"`, `[]`, 0},
+		{"null", "", codeInvalidRequest},
+		{"null", "", codeParse},
+	}
+	rd := bufio.NewReader(&out)
+	for _, w := range want {
+		content, err := readMessage(rd)
+		if err != nil || content == nil {
+			t.Fatalf("Server.ServeRPC() %s = %s, %v, want response", w.id, content, err)
+		}
+		var resp testResponse
+		if err := json.Unmarshal(content, &resp); err != nil {
+			t.Fatalf("Server.ServeRPC() %s = %s, %v", w.id, content, err)
+		}
+		if string(resp.ID) != w.id {
+			t.Errorf("Server.ServeRPC() id = %s, want %s", resp.ID, w.id)
+		}
+		if w.code != 0 {
+			if resp.Error == nil || resp.Error.Code != w.code {
+				t.Errorf("Server.ServeRPC() %s error = %v, want code %d", w.id, resp.Error, w.code)
+			}
+		} else if resp.Error != nil || (w.result != "" && string(resp.Result) != w.result) {
+			t.Errorf("Server.ServeRPC() %s = %s, %v, want %s", w.id, resp.Result, resp.Error, w.result)
+		}
+	}
+	if content, err := readMessage(rd); content != nil || err != nil {
+		t.Errorf("Server.ServeRPC() = %s, %v after exit, want nothing", content, err)
+	}
+
+	if err := s.ServeRPC(strings.NewReader("Content-Type: x\r\n\r\n{}"), &out); err != errHeader {
+		t.Errorf("Server.ServeRPC() error = %v, want %v", err, errHeader)
+	}
+}
@@ -0,0 +1,680 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package serve
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/elf"
+	"eventlist/pkg/event"
+	"eventlist/pkg/output"
+	"eventlist/pkg/state"
+	"eventlist/pkg/where"
+	"eventlist/pkg/xml/scvd"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var errLog = errors.New("missing log file")
+
+var errWorkspace = errors.New("unknown workspace")
+
+var errJob = errors.New("unknown job")
+
+var errUpload = errors.New("uploads not enabled")
+
+var errLive = errors.New("no live source")
+
+// DefaultMaxUpload is the largest uploaded file in bytes
+const DefaultMaxUpload = 1 << 30
+
+// DefaultLimit is the number of events of a page if no limit is given
+const DefaultLimit = 100
+
+// decodeMu serializes the decoding, the ELF files and the time factor
+// are process wide and are set to the ones of the decoded workspace
+var decodeMu sync.Mutex
+
+// Options of a workspace, the files are paths on the server
+type Options struct {
+	Log   string   `json:"log"`
+	SCVD  []string `json:"scvd,omitempty"`
+	ELF   string   `json:"elf,omitempty"`
+	Where []string `json:"where,omitempty"`
+	Level string   `json:"level,omitempty"`
+}
+
+// Filter of the events of a workspace
+type Filter struct {
+	Where []string `json:"where,omitempty"`
+	Level string   `json:"level,omitempty"`
+}
+
+// Event is a decoded event as returned by the API
+type Event struct {
+	Index     int     `json:"index"`
+	Time      float64 `json:"time"`
+	Component string  `json:"component"`
+	Property  string  `json:"property"`
+	Level     string  `json:"level,omitempty"`
+	Value     string  `json:"value"`
+	Thread    string  `json:"thread,omitempty"`
+}
+
+type item struct {
+	event Event
+	data  event.Data
+	def   *scvd.Event
+}
+
+// Workspace is an open capture with its own SCVD and ELF files, filter and cursor
+type Workspace struct {
+	mu     sync.Mutex
+	id     string
+	opts   Options
+	elf    elf.State // ELF files of the workspace, used under decodeMu
+	items  []item
+	view   []int // indices of the items passing the filter
+	cursor int   // position in view of the next page
+	filter *where.Filter
+	stats  []output.EventRecordStatistic // start/stop statistic of all events
+}
+
+// Info is the state of a workspace as returned by the API
+type Info struct {
+	ID       string  `json:"id"`
+	Options  Options `json:"options"`
+	Events   int     `json:"events"`
+	Filtered int     `json:"filtered"`
+	Cursor   int     `json:"cursor"`
+}
+
+// Page is a part of the filtered events of a workspace
+type Page struct {
+	Events []Event `json:"events"`
+	Offset int     `json:"offset"`
+	Next   int     `json:"next"`
+	Total  int     `json:"total"`
+}
+
+// Upload is a file uploaded to the server, its path is used in the Options
+type Upload struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+// Job decodes the log file of a workspace in the background
+type Job struct {
+	ID        string  `json:"id"`
+	Status    string  `json:"status"` // running, done or failed
+	Options   Options `json:"options"`
+	Workspace string  `json:"workspace,omitempty"` // ID of the opened workspace
+	Error     string  `json:"error,omitempty"`
+}
+
+// Server holds the workspaces, each workspace is addressed by its ID
+type Server struct {
+	UploadDir  string // directory of the uploaded files, no uploads if empty
+	MaxUpload  int64  // largest uploaded file in bytes
+	Live       *Live  // live source of /api/live, nil without
+	UI         bool   // serve the viewer at /
+	mu         sync.Mutex
+	workspaces map[string]*Workspace
+	jobs       map[string]*Job
+	running    sync.WaitGroup // jobs decoding
+}
+
+// New creates a server without workspaces
+func New() *Server {
+	return &Server{MaxUpload: DefaultMaxUpload, workspaces: make(map[string]*Workspace), jobs: make(map[string]*Job)}
+}
+
+func newID() string {
+	b := make([]byte, 6)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Open creates a workspace and decodes its log file
+func (s *Server) Open(opts Options) (*Workspace, error) {
+	if len(opts.Log) == 0 {
+		return nil, errLog
+	}
+	ws := &Workspace{opts: opts}
+	if err := ws.setFilter(Filter{Where: opts.Where, Level: opts.Level}); err != nil {
+		return nil, err
+	}
+	if err := ws.decode(); err != nil {
+		return nil, err
+	}
+	ws.applyFilter()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for {
+		ws.id = newID()
+		if s.workspaces[ws.id] == nil {
+			break
+		}
+	}
+	s.workspaces[ws.id] = ws
+	return ws, nil
+}
+
+// Submit starts a job opening a workspace in the background
+func (s *Server) Submit(opts Options) Job {
+	s.mu.Lock()
+	job := &Job{Status: "running", Options: opts}
+	for {
+		job.ID = newID()
+		if s.jobs[job.ID] == nil {
+			break
+		}
+	}
+	s.jobs[job.ID] = job
+	info := *job
+	s.mu.Unlock()
+
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		ws, err := s.Open(opts)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if err != nil {
+			job.Status, job.Error = "failed", err.Error()
+		} else {
+			job.Status, job.Workspace = "done", ws.id
+		}
+	}()
+	return info
+}
+
+// Job returns the state of the job of an ID
+func (s *Server) Job(id string) (Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job := s.jobs[id]
+	if job == nil {
+		return Job{}, fmt.Errorf("%w: %s", errJob, id)
+	}
+	return *job, nil
+}
+
+// Jobs returns the state of all jobs sorted by ID
+func (s *Server) Jobs() []Job {
+	s.mu.Lock()
+	list := make([]Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		list = append(list, *job)
+	}
+	s.mu.Unlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+// Upload stores a file in the upload directory, the name is kept as
+// suffix of the file name, e.g. for the extension of an ELF file
+func (s *Server) Upload(name string, in io.Reader) (Upload, error) {
+	if len(s.UploadDir) == 0 {
+		return Upload{}, errUpload
+	}
+	name = filepath.Base(filepath.Clean("/" + name))
+	if name == "/" || name == "." {
+		name = "upload.log"
+	}
+	file, err := os.CreateTemp(s.UploadDir, "*-"+strings.ReplaceAll(name, "*", "_"))
+	if err != nil {
+		return Upload{}, err
+	}
+	size, err := io.Copy(file, io.LimitReader(in, s.MaxUpload+1))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && size > s.MaxUpload {
+		err = fmt.Errorf("upload larger than %d bytes", s.MaxUpload)
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return Upload{}, err
+	}
+	return Upload{Name: name, Path: file.Name(), Size: size}, nil
+}
+
+// Get returns the workspace of an ID
+func (s *Server) Get(id string) (*Workspace, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ws := s.workspaces[id]
+	if ws == nil {
+		return nil, fmt.Errorf("%w: %s", errWorkspace, id)
+	}
+	return ws, nil
+}
+
+// Close removes the workspace of an ID
+func (s *Server) Close(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.workspaces[id] == nil {
+		return fmt.Errorf("%w: %s", errWorkspace, id)
+	}
+	delete(s.workspaces, id)
+	return nil
+}
+
+// List returns the state of all workspaces sorted by ID
+func (s *Server) List() []Info {
+	s.mu.Lock()
+	list := make([]Info, 0, len(s.workspaces))
+	for _, ws := range s.workspaces {
+		list = append(list, ws.Info())
+	}
+	s.mu.Unlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+// ID returns the ID of the workspace
+func (ws *Workspace) ID() string {
+	return ws.id
+}
+
+// decodeLog reads the SCVD and ELF files of the options and passes the
+// record of every event of the log file, or of data if not nil, to fn,
+// the ELF files of the decoding are returned
+func decodeLog(opts Options, data []byte,
+	fn func(rec *output.EventRecord, ev *event.Data, def *scvd.Event) error) (elf.State, error) {
+	evdefs := make(map[uint16]scvd.Event)
+	typedefs := make(map[string]map[string]map[int16]string)
+	memberTypes := make(map[string]map[string]string)
+	files := append([]string{}, opts.SCVD...)
+	if err := scvd.Get(&files, evdefs, typedefs, memberTypes); err != nil {
+		return elf.State{}, err
+	}
+	var in io.Reader = bytes.NewReader(data)
+	if data == nil {
+		if len(opts.Log) == 0 {
+			return elf.State{}, errLog
+		}
+		file, err := os.Open(opts.Log)
+		if err != nil {
+			return elf.State{}, err
+		}
+		defer file.Close()
+		in = file
+	}
+
+	decodeMu.Lock()
+	defer decodeMu.Unlock()
+	saved, savedFactor := elf.Current(), output.TimeFactor
+	defer func() {
+		elf.Use(saved)
+		output.TimeFactor = savedFactor
+	}()
+	elf.Use(elf.State{})
+	output.TimeFactor = nil
+	if len(opts.ELF) != 0 {
+		if err := elf.Sections.Readelf(&opts.ELF); err != nil {
+			return elf.State{}, err
+		}
+	}
+	err := output.Decode(in, evdefs, typedefs, memberTypes, func(rec *output.EventRecord, ev *event.Data) error {
+		var def *scvd.Event
+		if evdef, ok := evdefs[ev.Info.ID]; ok {
+			def = &evdef
+		}
+		return fn(rec, ev, def)
+	})
+	return elf.Current(), err
+}
+
+// decode reads the SCVD, ELF and log file of the workspace
+func (ws *Workspace) decode() error {
+	stats := output.NewStatistic()
+	var err error
+	ws.elf, err = decodeLog(ws.opts, nil, func(rec *output.EventRecord, ev *event.Data, def *scvd.Event) error {
+		it := item{
+			event: Event{
+				Index:     rec.Index,
+				Time:      rec.Time,
+				Component: rec.Component,
+				Property:  rec.EventProperty,
+				Level:     rec.Level(),
+				Value:     rec.Value,
+				Thread:    rec.Thread,
+			},
+			data: *ev,
+			def:  def,
+		}
+		ws.items = append(ws.items, it)
+		value := rec.Value
+		return stats.Event(bus.NewEvent(rec.Index, rec.Time, ev, def,
+			func() (string, error) { return value, nil }))
+	})
+	ws.stats = stats.Records()
+	return err
+}
+
+func (ws *Workspace) setFilter(f Filter) error {
+	var filter *where.Filter
+	if len(f.Where) != 0 {
+		var err error
+		if filter, err = where.Parse(f.Where); err != nil {
+			return err
+		}
+	}
+	ws.filter = filter
+	ws.opts.Where = f.Where
+	ws.opts.Level = f.Level
+	return nil
+}
+
+// applyFilter selects the events of the filter and resets the cursor
+func (ws *Workspace) applyFilter() {
+	ws.view = ws.view[:0]
+	for i := range ws.items {
+		it := &ws.items[i]
+		if ws.opts.Level != "" && it.event.Level != ws.opts.Level {
+			continue
+		}
+		value := it.event.Value
+		ev := bus.NewEvent(it.event.Index, it.event.Time, &it.data, it.def,
+			func() (string, error) { return value, nil })
+		if ws.filter.Match(ev) {
+			ws.view = append(ws.view, i)
+		}
+	}
+	ws.cursor = 0
+}
+
+// SetFilter sets the filter of the workspace and resets its cursor
+func (ws *Workspace) SetFilter(f Filter) error {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if err := ws.setFilter(f); err != nil {
+		return err
+	}
+	ws.applyFilter()
+	return nil
+}
+
+// Info returns the state of the workspace
+func (ws *Workspace) Info() Info {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	return Info{ID: ws.id, Options: ws.opts, Events: len(ws.items), Filtered: len(ws.view), Cursor: ws.cursor}
+}
+
+// Page returns limit filtered events from offset, a negative offset
+// returns the events at the cursor of the workspace and advances it
+func (ws *Workspace) Page(offset int, limit int) Page {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	useCursor := offset < 0
+	if useCursor {
+		offset = ws.cursor
+	}
+	if offset > len(ws.view) {
+		offset = len(ws.view)
+	}
+	end := offset + limit
+	if limit <= 0 || end > len(ws.view) {
+		end = len(ws.view)
+	}
+	page := Page{Events: make([]Event, 0, end-offset), Offset: offset, Next: end, Total: len(ws.view)}
+	for _, i := range ws.view[offset:end] {
+		page.Events = append(page.Events, ws.items[i].event)
+	}
+	if useCursor {
+		ws.cursor = end
+	}
+	return page
+}
+
+// Statistics returns the start/stop statistic of all events, the filter
+// of the workspace is not applied
+func (ws *Workspace) Statistics() []output.EventRecordStatistic {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if ws.stats == nil {
+		return []output.EventRecordStatistic{}
+	}
+	return ws.stats
+}
+
+// State reconstructs the system state at a time from all events up to it,
+// the filter of the workspace is not applied
+func (ws *Workspace) State(time float64) state.State {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	decodeMu.Lock()
+	defer decodeMu.Unlock()
+	saved := elf.Current()
+	defer elf.Use(saved)
+	elf.Use(ws.elf)
+	b := state.New(time)
+	for i := range ws.items {
+		it := &ws.items[i]
+		value := it.event.Value
+		_ = b.Event(bus.NewEvent(it.event.Index, it.event.Time, &it.data, it.def,
+			func() (string, error) { return value, nil }))
+	}
+	return b.State()
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func intParam(r *http.Request, name string, def int) (int, error) {
+	s := r.URL.Query().Get(name)
+	if s == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s: %s", name, s)
+	}
+	return n, nil
+}
+
+// ServeHTTP implements the API:
+//
+//	GET    /                                the viewer, if UI is set
+//	POST   /api/uploads?name=file           upload a file, returns its Upload
+//	GET    /api/live                        WebSocket of the live events,
+//	                                        ?where=filter&level=level
+//	GET    /metrics                         Prometheus metrics of the live
+//	                                        events
+//	GET    /api/jobs                        list the jobs
+//	POST   /api/jobs                        open a workspace with Options in
+//	                                        the background
+//	GET    /api/jobs/{id}                   state of a job
+//	GET    /api/workspaces                  list the workspaces
+//	POST   /api/workspaces                  open a workspace with Options
+//	GET    /api/workspaces/{id}             state of a workspace
+//	DELETE /api/workspaces/{id}             close a workspace
+//	PUT    /api/workspaces/{id}/filter      set the Filter, resets the cursor
+//	GET    /api/workspaces/{id}/events      page of the filtered events,
+//	                                        ?offset=n&limit=n, without offset
+//	                                        the page at the cursor
+//	GET    /api/workspaces/{id}/statistics  start/stop statistic of all events
+//	GET    /api/workspaces/{id}/state       system state at ?time=seconds
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/" && s.UI:
+		serveUI(w, r)
+		return
+	case r.URL.Path == "/api/uploads":
+		s.serveUpload(w, r)
+		return
+	case r.URL.Path == "/api/live":
+		if s.Live == nil {
+			writeError(w, http.StatusNotFound, errLive)
+			return
+		}
+		s.Live.ServeHTTP(w, r)
+		return
+	case r.URL.Path == "/metrics":
+		if s.Live == nil {
+			writeError(w, http.StatusNotFound, errLive)
+			return
+		}
+		s.Live.metrics.ServeHTTP(w, r)
+		return
+	case r.URL.Path == "/api/jobs" || strings.HasPrefix(r.URL.Path, "/api/jobs/"):
+		s.serveJobs(w, r, strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/jobs"), "/"))
+		return
+	case !strings.HasPrefix(r.URL.Path, "/api/workspaces"):
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown path %s", r.URL.Path))
+		return
+	}
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/workspaces"), "/")
+	if path == "" {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, s.List())
+		case http.MethodPost:
+			var opts Options
+			if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			}
+			ws, err := s.Open(opts)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			}
+			writeJSON(w, http.StatusCreated, ws.Info())
+		default:
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		}
+		return
+	}
+	id, sub, _ := strings.Cut(path, "/")
+	ws, err := s.Get(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	switch {
+	case sub == "" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, ws.Info())
+	case sub == "" && r.Method == http.MethodDelete:
+		_ = s.Close(id)
+		w.WriteHeader(http.StatusNoContent)
+	case sub == "filter" && r.Method == http.MethodPut:
+		var f Filter
+		if err = json.NewDecoder(r.Body).Decode(&f); err == nil {
+			err = ws.SetFilter(f)
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, ws.Info())
+	case sub == "events" && r.Method == http.MethodGet:
+		offset, err := intParam(r, "offset", -1)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		limit, err := intParam(r, "limit", DefaultLimit)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, ws.Page(offset, limit))
+	case sub == "statistics" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, ws.Statistics())
+	case sub == "state" && r.Method == http.MethodGet:
+		time, err := strconv.ParseFloat(r.URL.Query().Get("time"), 64)
+		if err != nil || time < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid time: %s", r.URL.Query().Get("time")))
+			return
+		}
+		writeJSON(w, http.StatusOK, ws.State(time))
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown request %s %s", r.Method, r.URL.Path))
+	}
+}
+
+func (s *Server) serveUpload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+	up, err := s.Upload(r.URL.Query().Get("name"), r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, up)
+}
+
+func (s *Server) serveJobs(w http.ResponseWriter, r *http.Request, id string) {
+	switch {
+	case id == "" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, s.Jobs())
+	case id == "" && r.Method == http.MethodPost:
+		var opts Options
+		if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeJSON(w, http.StatusAccepted, s.Submit(opts))
+	case id != "" && r.Method == http.MethodGet:
+		job, err := s.Job(id)
+		if err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, job)
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown request %s %s", r.Method, r.URL.Path))
+	}
+}
+
+// Serve serves the API at the address until the listener fails
+func (s *Server) Serve(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return http.Serve(l, s) //nolint:gosec
+}
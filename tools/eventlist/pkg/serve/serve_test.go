@@ -0,0 +1,318 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package serve
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"eventlist/pkg/event"
+	"eventlist/pkg/output"
+	"eventlist/pkg/state"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func request(t *testing.T, h http.Handler, method string, url string, body any, v any) int {
+	t.Helper()
+	var in io.Reader
+	if body != nil {
+		data, _ := json.Marshal(body)
+		in = bytes.NewReader(data)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, url, in))
+	if v != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Errorf("%s %s = %s, error = %v", method, url, rec.Body.String(), err)
+		}
+	}
+	return rec.Code
+}
+
+func TestServer_workspaces(t *testing.T) {
+	t.Parallel()
+
+	s := New()
+	var withSCVD, without Info
+	if code := request(t, s, "POST", "/api/workspaces", Options{
+		Log: "../../testdata/test10.binary", SCVD: []string{"../../testdata/test.xml"}}, &withSCVD); code != http.StatusCreated {
+		t.Fatalf("POST /api/workspaces = %d, want %d", code, http.StatusCreated)
+	}
+	if code := request(t, s, "POST", "/api/workspaces", Options{Log: "../../testdata/test10.binary"}, &without); code != http.StatusCreated {
+		t.Fatalf("POST /api/workspaces = %d, want %d", code, http.StatusCreated)
+	}
+	if withSCVD.ID == without.ID || withSCVD.Events != 2 || without.Events != 2 {
+		t.Errorf("POST /api/workspaces = %v, %v, want two workspaces with 2 events", withSCVD, without)
+	}
+
+	var list []Info
+	request(t, s, "GET", "/api/workspaces", nil, &list)
+	if len(list) != 2 {
+		t.Errorf("GET /api/workspaces = %v, want 2 workspaces", list)
+	}
+
+	// every workspace decodes with its own SCVD files and has its own cursor
+	var page Page
+	request(t, s, "GET", "/api/workspaces/"+withSCVD.ID+"/events?limit=1", nil, &page)
+	request(t, s, "GET", "/api/workspaces/"+withSCVD.ID+"/events?limit=1", nil, &page)
+	if len(page.Events) != 1 || page.Events[0].Component != "STDIO" || page.Offset != 1 || page.Next != 2 {
+		t.Errorf("GET events = %v, want STDIO event at offset 1", page)
+	}
+	request(t, s, "GET", "/api/workspaces/"+without.ID+"/events?limit=1", nil, &page)
+	if len(page.Events) != 1 || page.Events[0].Component != "0xFF" || page.Offset != 0 {
+		t.Errorf("GET events = %v, want 0xFF event at offset 0", page)
+	}
+	request(t, s, "GET", "/api/workspaces/"+without.ID+"/events?offset=1&limit=0", nil, &page)
+	if len(page.Events) != 1 || page.Events[0].Component != "0xFE" {
+		t.Errorf("GET events offset = %v, want 0xFE event", page)
+	}
+
+	var st state.State
+	request(t, s, "GET", "/api/workspaces/"+withSCVD.ID+"/state?time=1000", nil, &st)
+	if st.Time != 1000 || st.Events != 2 || len(st.Metrics) != 2 || st.Metrics[1].Component != "STDIO" {
+		t.Errorf("GET state = %v, want 2 events with STDIO metric", st)
+	}
+	request(t, s, "GET", "/api/workspaces/"+withSCVD.ID+"/state?time=0", nil, &st)
+	if st.Events != 0 || len(st.Metrics) != 0 {
+		t.Errorf("GET state at 0 = %v, want no events", st)
+	}
+
+	var info Info
+	request(t, s, "PUT", "/api/workspaces/"+without.ID+"/filter", Filter{Where: []string{"component=0xFE"}}, &info)
+	if info.Filtered != 1 || info.Cursor != 0 || !reflect.DeepEqual(info.Options.Where, []string{"component=0xFE"}) {
+		t.Errorf("PUT filter = %v, want 1 filtered event", info)
+	}
+	request(t, s, "GET", "/api/workspaces/"+without.ID+"/events", nil, &page)
+	if len(page.Events) != 1 || page.Events[0].Component != "0xFE" || page.Total != 1 {
+		t.Errorf("GET filtered events = %v, want 0xFE event", page)
+	}
+	request(t, s, "GET", "/api/workspaces/"+withSCVD.ID, nil, &info)
+	if info.Filtered != 2 || info.Cursor != 2 {
+		t.Errorf("GET workspace = %v, want unchanged filter and cursor", info)
+	}
+
+	if code := request(t, s, "DELETE", "/api/workspaces/"+withSCVD.ID, nil, nil); code != http.StatusNoContent {
+		t.Errorf("DELETE workspace = %d, want %d", code, http.StatusNoContent)
+	}
+	if code := request(t, s, "GET", "/api/workspaces/"+withSCVD.ID, nil, nil); code != http.StatusNotFound {
+		t.Errorf("GET closed workspace = %d, want %d", code, http.StatusNotFound)
+	}
+}
+
+// capture with two start/stop pairs of A(0)
+func writeStartStop(t *testing.T) []byte {
+	t.Helper()
+	name := filepath.Join(t.TempDir(), "startstop.binary")
+	w, err := event.Create(name)
+	if err != nil {
+		t.Fatalf("event.Create() error = %v", err)
+	}
+	err = w.Clock(0, 1000)
+	for i, id := range []uint16{0xEF00, 0xEF20, 0xEF00, 0xEF20} {
+		if err == nil {
+			err = w.EventRecord2(uint64(1000*(i+1)+100*(i%2)*(i+1)), id, 0, 0)
+		}
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		t.Fatalf("Writer.EventRecord2() error = %v", err)
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatalf("os.ReadFile() error = %v", err)
+	}
+	return data
+}
+
+func TestServer_jobs(t *testing.T) {
+	t.Parallel()
+
+	s := New()
+	s.UploadDir = t.TempDir()
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest("POST", "/api/uploads?name=../x/Events.log", bytes.NewReader(writeStartStop(t))))
+	var up Upload
+	if err := json.Unmarshal(rec.Body.Bytes(), &up); err != nil || rec.Code != http.StatusCreated {
+		t.Fatalf("POST /api/uploads = %d %s, error = %v", rec.Code, rec.Body.String(), err)
+	}
+	if up.Name != "Events.log" || filepath.Dir(up.Path) != s.UploadDir || !strings.HasSuffix(up.Path, "-Events.log") || up.Size == 0 {
+		t.Errorf("POST /api/uploads = %v, want Events.log in %s", up, s.UploadDir)
+	}
+
+	var job Job
+	if code := request(t, s, "POST", "/api/jobs", Options{Log: up.Path}, &job); code != http.StatusAccepted || job.ID == "" {
+		t.Fatalf("POST /api/jobs = %d %v, want %d", code, job, http.StatusAccepted)
+	}
+	var failed Job
+	request(t, s, "POST", "/api/jobs", Options{Log: "../../testdata/nix.binary"}, &failed)
+	s.running.Wait()
+	request(t, s, "GET", "/api/jobs/"+job.ID, nil, &job)
+	if job.Status != "done" || job.Workspace == "" || job.Error != "" {
+		t.Errorf("GET /api/jobs/%s = %v, want done", job.ID, job)
+	}
+	request(t, s, "GET", "/api/jobs/"+failed.ID, nil, &failed)
+	if failed.Status != "failed" || failed.Workspace != "" || failed.Error == "" {
+		t.Errorf("GET /api/jobs/%s = %v, want failed", failed.ID, failed)
+	}
+	var jobs []Job
+	request(t, s, "GET", "/api/jobs", nil, &jobs)
+	if len(jobs) != 2 {
+		t.Errorf("GET /api/jobs = %v, want 2 jobs", jobs)
+	}
+
+	var stats []output.EventRecordStatistic
+	request(t, s, "GET", "/api/workspaces/"+job.Workspace+"/statistics", nil, &stats)
+	if len(stats) != 1 || stats[0].Event != "A(0)" || stats[0].Count != 2 {
+		t.Errorf("GET statistics = %v, want A(0) with 2 pairs", stats)
+	}
+	ws, _ := s.Open(Options{Log: "../../testdata/test10.binary"})
+	request(t, s, "GET", "/api/workspaces/"+ws.ID()+"/statistics", nil, &stats)
+	if len(stats) != 0 {
+		t.Errorf("GET statistics = %v, want none", stats)
+	}
+}
+
+func TestServer_Upload(t *testing.T) {
+	t.Parallel()
+
+	s := New()
+	if _, err := s.Upload("a.log", strings.NewReader("data")); !errors.Is(err, errUpload) {
+		t.Errorf("Server.Upload() error = %v, want %v", err, errUpload)
+	}
+	s.UploadDir = t.TempDir()
+	s.MaxUpload = 4
+	tests := []struct {
+		name    string
+		data    string
+		want    string
+		wantErr bool
+	}{
+		{"a.log", "data", "a.log", false},
+		{"", "", "upload.log", false},
+		{"../../b.elf", "", "b.elf", false},
+		{"large.log", "large", "", true},
+	}
+	for _, tt := range tests {
+		got, err := s.Upload(tt.name, strings.NewReader(tt.data))
+		if (err != nil) != tt.wantErr || got.Name != tt.want {
+			t.Errorf("Server.Upload() %s = %v, %v, want %s", tt.name, got, err, tt.want)
+		}
+	}
+	if files, _ := os.ReadDir(s.UploadDir); len(files) != 3 {
+		t.Errorf("Server.Upload() files = %d, want %d", len(files), 3)
+	}
+}
+
+func TestServer_errors(t *testing.T) {
+	t.Parallel()
+
+	s := New()
+	ws, err := s.Open(Options{Log: "../../testdata/test10.binary"})
+	if err != nil {
+		t.Fatalf("Server.Open() error = %v", err)
+	}
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		body   any
+		want   int
+	}{
+		{"path", "GET", "/api/nix", nil, http.StatusNotFound},
+		{"method", "PATCH", "/api/workspaces", nil, http.StatusMethodNotAllowed},
+		{"body", "POST", "/api/workspaces", "log", http.StatusBadRequest},
+		{"no log", "POST", "/api/workspaces", Options{}, http.StatusBadRequest},
+		{"nix log", "POST", "/api/workspaces", Options{Log: "../../testdata/nix.binary"}, http.StatusBadRequest},
+		{"nix scvd", "POST", "/api/workspaces", Options{Log: "../../testdata/test10.binary", SCVD: []string{"nix.xml"}}, http.StatusBadRequest},
+		{"nix elf", "POST", "/api/workspaces", Options{Log: "../../testdata/test10.binary", ELF: "nix.elf"}, http.StatusBadRequest},
+		{"where", "POST", "/api/workspaces", Options{Log: "../../testdata/test10.binary", Where: []string{"x"}}, http.StatusBadRequest},
+		{"workspace", "GET", "/api/workspaces/nix", nil, http.StatusNotFound},
+		{"filter", "PUT", "/api/workspaces/" + ws.ID() + "/filter", Filter{Where: []string{"x=1"}}, http.StatusBadRequest},
+		{"offset", "GET", "/api/workspaces/" + ws.ID() + "/events?offset=x", nil, http.StatusBadRequest},
+		{"limit", "GET", "/api/workspaces/" + ws.ID() + "/events?limit=-1", nil, http.StatusBadRequest},
+		{"time", "GET", "/api/workspaces/" + ws.ID() + "/state?time=x", nil, http.StatusBadRequest},
+		{"no time", "GET", "/api/workspaces/" + ws.ID() + "/state", nil, http.StatusBadRequest},
+		{"sub", "GET", "/api/workspaces/" + ws.ID() + "/nix", nil, http.StatusNotFound},
+		{"upload method", "GET", "/api/uploads", nil, http.StatusMethodNotAllowed},
+		{"upload dir", "POST", "/api/uploads", "data", http.StatusBadRequest},
+		{"job body", "POST", "/api/jobs", "log", http.StatusBadRequest},
+		{"job", "GET", "/api/jobs/nix", nil, http.StatusNotFound},
+		{"job method", "DELETE", "/api/jobs", nil, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var body map[string]string
+			if got := request(t, s, tt.method, tt.url, tt.body, &body); got != tt.want || body["error"] == "" {
+				t.Errorf("%s %s = %d %v, want %d with error", tt.method, tt.url, got, body, tt.want)
+			}
+		})
+	}
+
+	if err := s.Close("nix"); !errors.Is(err, errWorkspace) {
+		t.Errorf("Server.Close() error = %v, want %v", err, errWorkspace)
+	}
+	if _, err := s.Open(Options{Log: "../../testdata/nix.binary"}); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Server.Open() error = %v, want %v", err, os.ErrNotExist)
+	}
+}
+
+func TestWorkspace_Page(t *testing.T) {
+	t.Parallel()
+
+	ws := &Workspace{items: make([]item, 5), view: []int{0, 2, 4}}
+	for i := range ws.items {
+		ws.items[i].event.Index = i
+	}
+	tests := []struct {
+		name   string
+		offset int
+		limit  int
+		want   []int
+		next   int
+	}{
+		{"cursor", -1, 2, []int{0, 2}, 2},
+		{"cursor next", -1, 2, []int{4}, 3},
+		{"cursor end", -1, 2, []int{}, 3},
+		{"offset", 1, 1, []int{2}, 2},
+		{"all", 0, 0, []int{0, 2, 4}, 3},
+		{"beyond", 7, 1, []int{}, 3},
+	}
+	for _, tt := range tests {
+		page := ws.Page(tt.offset, tt.limit)
+		got := []int{}
+		for _, ev := range page.Events {
+			got = append(got, ev.Index)
+		}
+		if !reflect.DeepEqual(got, tt.want) || page.Next != tt.next {
+			t.Errorf("Workspace.Page() %s = %v next %d, want %v next %d", tt.name, got, page.Next, tt.want, tt.next)
+		}
+	}
+}
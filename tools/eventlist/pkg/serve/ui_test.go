@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServer_UI(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		ui     bool
+		method string
+		want   int
+	}{
+		{"viewer", true, "GET", http.StatusOK},
+		{"method", true, "POST", http.StatusMethodNotAllowed},
+		{"no viewer", false, "GET", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := New()
+			s.UI = tt.ui
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, httptest.NewRequest(tt.method, "/", nil))
+			if rec.Code != tt.want {
+				t.Errorf("%s / = %d, want %d", tt.method, rec.Code, tt.want)
+			}
+			if tt.want == http.StatusOK && (!strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html") ||
+				!strings.Contains(rec.Body.String(), "/api/workspaces")) {
+				t.Errorf("GET / = %s, want the viewer", rec.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
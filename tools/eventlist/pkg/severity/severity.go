@@ -0,0 +1,75 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package severity
+
+import (
+	"errors"
+	"eventlist/pkg/xml/scvd"
+	"fmt"
+	"os"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+var errID = errors.New("invalid event ID")
+var errLevel = errors.New("invalid level")
+
+// Map assigns new levels to event IDs
+type Map map[uint16]string
+
+// Load reads a mapping file, a YAML map of event IDs to levels:
+//
+//	0x4E01: Error
+//	0x4E02: Detail
+func Load(filename string) (Map, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var entries map[string]string
+	if err = yaml.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	m := make(Map)
+	for key, level := range entries {
+		id, err := strconv.ParseUint(key, 0, 16)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errID, key)
+		}
+		switch level {
+		case "Error", "API", "Op", "Detail":
+		default:
+			return nil, fmt.Errorf("%w: %s: %s", errLevel, key, level)
+		}
+		m[uint16(id)] = level
+	}
+	return m, nil
+}
+
+// Apply changes the levels of the event definitions,
+// events without definition are not changed
+func (m Map) Apply(evdefs map[uint16]scvd.Event) {
+	for id, level := range m {
+		if evdef, ok := evdefs[id]; ok {
+			evdef.Level = level
+			evdefs[id] = evdef
+		}
+	}
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package severity
+
+import (
+	"errors"
+	"eventlist/pkg/xml/scvd"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	badID := filepath.Join(dir, "id.yaml")
+	_ = os.WriteFile(badID, []byte("0x10000: Error\n"), 0600)
+	badYAML := filepath.Join(dir, "yaml.yaml")
+	_ = os.WriteFile(badYAML, []byte("- Error\n"), 0600)
+
+	tests := []struct {
+		name     string
+		filename string
+		want     Map
+		wantErr  error
+	}{
+		{"ok", "../../testdata/severity.yaml", Map{0x1000: "Error", 0x1001: "Detail"}, nil},
+		{"level", "../../testdata/severity_err.yaml", nil, errLevel},
+		{"id", badID, nil, errID},
+		{"yaml", badYAML, nil, nil},
+		{"nix", "../../testdata/nix.yaml", nil, os.ErrNotExist},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Load(tt.filename)
+			if tt.want == nil && err == nil {
+				t.Errorf("Load() %s error = nil, want error", tt.name)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Load() %s error = %v, want %v", tt.name, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Load() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMap_Apply(t *testing.T) {
+	t.Parallel()
+
+	evdefs := map[uint16]scvd.Event{
+		0x1000: {Property: "Poll", Level: "Op"},
+		0x1001: {Property: "Fail", Level: "Error"},
+	}
+	want := map[uint16]scvd.Event{
+		0x1000: {Property: "Poll", Level: "Error"},
+		0x1001: {Property: "Fail", Level: "Error"},
+	}
+	Map{0x1000: "Error", 0x2000: "Detail"}.Apply(evdefs)
+	if !reflect.DeepEqual(evdefs, want) {
+		t.Errorf("Map.Apply() = %v, want %v", evdefs, want)
+	}
+}
@@ -0,0 +1,327 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sink
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// BatchSize is the number of messages sent with one produce request of Kafka
+const BatchSize = 500
+
+// MetadataRetries is the number of metadata requests while the leader of a
+// new topic is elected
+const MetadataRetries = 10
+
+var errKafka = errors.New("kafka")
+
+// API keys and versions of the Kafka protocol
+const (
+	apiProduce       = 0
+	apiMetadata      = 3
+	produceVersion   = 3 // the first version with record batches
+	metadataVersion  = 4 // the first version with allow_auto_topic_creation
+	clientID         = "eventlist"
+	errLeaderMissing = 5 // LEADER_NOT_AVAILABLE
+	errUnknownTopic  = 3 // UNKNOWN_TOPIC_OR_PARTITION
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+type kafkaRecord struct {
+	key   string
+	value []byte
+	time  int64 // in ms
+}
+
+// kafkaClient produces the messages to a partition of a topic, the key of
+// a message is the component of its event
+type kafkaClient struct {
+	conn        net.Conn
+	in          *bufio.Reader
+	topic       string
+	partition   int32
+	acks        int16
+	correlation int32
+	batch       []kafkaRecord
+}
+
+// kafka://host[:port][/topic][?partition=n&acks=0|1|all]
+func openKafka(u *url.URL) (Client, error) {
+	c := &kafkaClient{topic: topic(u), acks: 1}
+	q := u.Query()
+	if p := q.Get("partition"); p != "" {
+		n, err := strconv.ParseInt(p, 10, 32)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%w: partition %s", errURL, p)
+		}
+		c.partition = int32(n)
+	}
+	switch a := q.Get("acks"); a {
+	case "", "1":
+	case "0":
+		c.acks = 0
+	case "all", "-1":
+		c.acks = -1
+	default:
+		return nil, fmt.Errorf("%w: acks %s", errURL, a)
+	}
+	addr := hostPort(u, "9092")
+	if err := c.dial(addr); err != nil {
+		return nil, err
+	}
+	leader, err := c.leader()
+	if err == nil && leader != addr {
+		c.conn.Close()
+		err = c.dial(leader)
+	}
+	if err != nil {
+		c.conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *kafkaClient) dial(addr string) error {
+	conn, err := net.DialTimeout("tcp", addr, Timeout)
+	if err != nil {
+		return err
+	}
+	c.conn = conn
+	c.in = bufio.NewReader(conn)
+	return nil
+}
+
+func appendKafkaString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+	return append(b, s...)
+}
+
+// send a request and return the response without header, nil if no
+// response is expected
+func (c *kafkaClient) request(apiKey int16, version int16, body []byte) ([]byte, error) {
+	c.correlation++
+	req := make([]byte, 4, 4+10+len(clientID)+len(body))
+	req = binary.BigEndian.AppendUint16(req, uint16(apiKey))
+	req = binary.BigEndian.AppendUint16(req, uint16(version))
+	req = binary.BigEndian.AppendUint32(req, uint32(c.correlation))
+	req = appendKafkaString(req, clientID)
+	req = append(req, body...)
+	binary.BigEndian.PutUint32(req, uint32(len(req)-4))
+	_ = c.conn.SetDeadline(time.Now().Add(Timeout))
+	if _, err := c.conn.Write(req); err != nil {
+		return nil, err
+	}
+	if apiKey == apiProduce && c.acks == 0 {
+		return nil, nil
+	}
+	var size [4]byte
+	if _, err := io.ReadFull(c.in, size[:]); err != nil {
+		return nil, err
+	}
+	resp := make([]byte, binary.BigEndian.Uint32(size[:]))
+	if _, err := io.ReadFull(c.in, resp); err != nil {
+		return nil, err
+	}
+	if len(resp) < 4 || int32(binary.BigEndian.Uint32(resp)) != c.correlation {
+		return nil, fmt.Errorf("%w: unexpected response", errKafka)
+	}
+	return resp[4:], nil
+}
+
+// decoder of a response, the first error is kept
+type decoder struct {
+	b   []byte
+	err error
+}
+
+func (d *decoder) next(n int) []byte {
+	if d.err == nil && (n < 0 || len(d.b) < n) {
+		d.err = fmt.Errorf("%w: short response", errKafka)
+	}
+	if d.err != nil {
+		return make([]byte, 8) // zero values
+	}
+	v := d.b[:n]
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *decoder) int16() int16 { return int16(binary.BigEndian.Uint16(d.next(2))) }
+func (d *decoder) int32() int32 { return int32(binary.BigEndian.Uint32(d.next(4))) }
+func (d *decoder) int64() int64 { return int64(binary.BigEndian.Uint64(d.next(8))) }
+
+// string or nullable string
+func (d *decoder) string() string {
+	n := d.int16()
+	if n < 0 {
+		return ""
+	}
+	return string(d.next(int(n)))
+}
+
+// address of the leader of the partition from the metadata, the topic is
+// created if the broker allows it
+func (c *kafkaClient) leader() (string, error) {
+	body := binary.BigEndian.AppendUint32(nil, 1)
+	body = appendKafkaString(body, c.topic)
+	body = append(body, 1) // allow_auto_topic_creation
+	for try := 1; ; try++ {
+		resp, err := c.request(apiMetadata, metadataVersion, body)
+		if err != nil {
+			return "", err
+		}
+		d := decoder{b: resp}
+		d.int32() // throttle_time_ms
+		brokers := make(map[int32]string)
+		for n := d.int32(); n > 0 && d.err == nil; n-- {
+			node, host, port := d.int32(), d.string(), d.int32()
+			d.string() // rack
+			brokers[node] = net.JoinHostPort(host, strconv.Itoa(int(port)))
+		}
+		d.string() // cluster_id
+		d.int32()  // controller_id
+		code, leader := int16(errUnknownTopic), int32(-1)
+		for n := d.int32(); n > 0 && d.err == nil; n-- {
+			topicCode, name := d.int16(), d.string()
+			d.next(1) // is_internal
+			for p := d.int32(); p > 0 && d.err == nil; p-- {
+				partitionCode, index, node := d.int16(), d.int32(), d.int32()
+				d.next(4 * int(d.int32())) // replica_nodes
+				d.next(4 * int(d.int32())) // isr_nodes
+				if name == c.topic && index == c.partition {
+					code, leader = partitionCode, node
+				}
+			}
+			if name == c.topic && topicCode != 0 {
+				code = topicCode
+			}
+		}
+		if d.err != nil {
+			return "", d.err
+		}
+		if code == 0 {
+			if addr, ok := brokers[leader]; ok {
+				return addr, nil
+			}
+			code = errLeaderMissing
+		}
+		if (code != errLeaderMissing && code != errUnknownTopic) || try == MetadataRetries {
+			return "", fmt.Errorf("%w: topic %s partition %d: error code %d", errKafka, c.topic, c.partition, code)
+		}
+		time.Sleep(200 * time.Millisecond) // the leader of a new topic is elected
+	}
+}
+
+func (c *kafkaClient) Send(key string, msg []byte) error {
+	c.batch = append(c.batch, kafkaRecord{key, msg, time.Now().UnixMilli()})
+	if len(c.batch) >= BatchSize {
+		return c.produce()
+	}
+	return nil
+}
+
+// record batch of the message format v2
+func recordBatch(records []kafkaRecord) []byte {
+	var recs, rec []byte
+	base := records[0].time
+	for i, r := range records {
+		rec = append(rec[:0], 0) // attributes
+		rec = binary.AppendVarint(rec, r.time-base)
+		rec = binary.AppendVarint(rec, int64(i))
+		rec = binary.AppendVarint(rec, int64(len(r.key)))
+		rec = append(rec, r.key...)
+		rec = binary.AppendVarint(rec, int64(len(r.value)))
+		rec = append(rec, r.value...)
+		rec = binary.AppendVarint(rec, 0) // headers
+		recs = binary.AppendVarint(recs, int64(len(rec)))
+		recs = append(recs, rec...)
+	}
+	b := make([]byte, 0, 61+len(recs))
+	b = binary.BigEndian.AppendUint64(b, 0)          // base offset
+	b = binary.BigEndian.AppendUint32(b, 0)          // batch length, set below
+	b = binary.BigEndian.AppendUint32(b, 0xFFFFFFFF) // partition leader epoch
+	b = append(b, 2)                                 // magic
+	b = binary.BigEndian.AppendUint32(b, 0)          // CRC, set below
+	b = binary.BigEndian.AppendUint16(b, 0)          // attributes
+	b = binary.BigEndian.AppendUint32(b, uint32(len(records)-1))
+	b = binary.BigEndian.AppendUint64(b, uint64(base))
+	b = binary.BigEndian.AppendUint64(b, uint64(records[len(records)-1].time))
+	b = binary.BigEndian.AppendUint64(b, 0xFFFFFFFFFFFFFFFF) // producer ID
+	b = binary.BigEndian.AppendUint16(b, 0xFFFF)             // producer epoch
+	b = binary.BigEndian.AppendUint32(b, 0xFFFFFFFF)         // base sequence
+	b = binary.BigEndian.AppendUint32(b, uint32(len(records)))
+	b = append(b, recs...)
+	binary.BigEndian.PutUint32(b[8:], uint32(len(b)-12))
+	binary.BigEndian.PutUint32(b[17:], crc32.Checksum(b[21:], castagnoli))
+	return b
+}
+
+// send the batch with a produce request
+func (c *kafkaClient) produce() error {
+	if len(c.batch) == 0 {
+		return nil
+	}
+	batch := recordBatch(c.batch)
+	c.batch = c.batch[:0]
+	body := binary.BigEndian.AppendUint16(nil, 0xFFFF) // transactional_id
+	body = binary.BigEndian.AppendUint16(body, uint16(c.acks))
+	body = binary.BigEndian.AppendUint32(body, uint32(Timeout.Milliseconds()))
+	body = binary.BigEndian.AppendUint32(body, 1)
+	body = appendKafkaString(body, c.topic)
+	body = binary.BigEndian.AppendUint32(body, 1)
+	body = binary.BigEndian.AppendUint32(body, uint32(c.partition))
+	body = binary.BigEndian.AppendUint32(body, uint32(len(batch)))
+	body = append(body, batch...)
+	resp, err := c.request(apiProduce, produceVersion, body)
+	if err != nil || resp == nil {
+		return err
+	}
+	d := decoder{b: resp}
+	for n := d.int32(); n > 0 && d.err == nil; n-- {
+		d.string() // name
+		for p := d.int32(); p > 0 && d.err == nil; p-- {
+			d.int32() // index
+			if code := d.int16(); code != 0 && d.err == nil {
+				return fmt.Errorf("%w: produce to topic %s: error code %d", errKafka, c.topic, code)
+			}
+			d.int64() // base_offset
+			d.int64() // log_append_time_ms
+		}
+	}
+	return d.err
+}
+
+// Close sends the remaining messages
+func (c *kafkaClient) Close() error {
+	err := c.produce()
+	if e := c.conn.Close(); err == nil {
+		err = e
+	}
+	return err
+}
@@ -0,0 +1,213 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sink
+
+import (
+	"bufio"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"net"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Kafka broker with the node ID 1, leader of all partitions
+type kafkaBroker struct {
+	mu        sync.Mutex
+	records   []string // key and value
+	metadata  int      // number of metadata requests
+	electing  int      // metadata responses with LEADER_NOT_AVAILABLE
+	errorCode int16    // error code of the produce responses
+	badCRC    int
+}
+
+func appendResponse(resp []byte, v ...any) []byte {
+	for _, x := range v {
+		switch x := x.(type) {
+		case int16:
+			resp = binary.BigEndian.AppendUint16(resp, uint16(x))
+		case int32:
+			resp = binary.BigEndian.AppendUint32(resp, uint32(x))
+		case int64:
+			resp = binary.BigEndian.AppendUint64(resp, uint64(x))
+		case string:
+			resp = appendKafkaString(resp, x)
+		case byte:
+			resp = append(resp, x)
+		}
+	}
+	return resp
+}
+
+// keys and values of a record batch
+func (b *kafkaBroker) decodeBatch(batch []byte) {
+	if binary.BigEndian.Uint32(batch[17:]) != crc32.Checksum(batch[21:], castagnoli) {
+		b.badCRC++
+		return
+	}
+	recs := batch[61:]
+	for n := binary.BigEndian.Uint32(batch[57:]); n > 0; n-- {
+		_, k := binary.Varint(recs)
+		recs = recs[k+1:] // length and attributes
+		for i := 0; i < 2; i++ {
+			_, k = binary.Varint(recs) // timestamp and offset delta
+			recs = recs[k:]
+		}
+		var kv []string
+		for i := 0; i < 2; i++ {
+			l, k := binary.Varint(recs)
+			kv = append(kv, string(recs[k:k+int(l)]))
+			recs = recs[k+int(l):]
+		}
+		_, k = binary.Varint(recs) // headers
+		recs = recs[k:]
+		b.records = append(b.records, strings.Join(kv, " "))
+	}
+}
+
+func (b *kafkaBroker) serve(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+	host, port, _ := net.SplitHostPort(l.Addr().String())
+	portNo, _ := strconv.Atoi(port)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go b.handle(conn, host, int32(portNo))
+		}
+	}()
+	return l.Addr().String()
+}
+
+func (b *kafkaBroker) handle(conn net.Conn, host string, port int32) {
+	defer conn.Close()
+	in := bufio.NewReader(conn)
+	for {
+		var size [4]byte
+		if _, err := io.ReadFull(in, size[:]); err != nil {
+			return
+		}
+		req := make([]byte, binary.BigEndian.Uint32(size[:]))
+		if _, err := io.ReadFull(in, req); err != nil {
+			return
+		}
+		d := decoder{b: req}
+		apiKey, _, correlation := d.int16(), d.int16(), d.int32()
+		d.string() // client ID
+		resp := appendResponse(nil, correlation)
+		b.mu.Lock()
+		switch apiKey {
+		case apiMetadata:
+			d.int32()
+			topic := d.string()
+			code := int16(0)
+			if b.metadata++; b.metadata <= b.electing {
+				code = errLeaderMissing
+			}
+			resp = appendResponse(resp, int32(0), int32(1), int32(1), host, port, int16(-1), int16(-1), int32(1),
+				int32(1), code, topic, byte(0), int32(1), int16(0), int32(0), int32(1), int32(1), int32(1), int32(1), int32(1))
+		case apiProduce:
+			d.int16() // transactional ID
+			acks := d.int16()
+			d.int32()
+			d.int32()
+			topic := d.string()
+			d.int32()
+			partition := d.int32()
+			b.decodeBatch(d.next(int(d.int32())))
+			if acks == 0 {
+				b.mu.Unlock()
+				continue
+			}
+			resp = appendResponse(resp, int32(1), topic, int32(1), partition, b.errorCode, int64(0), int64(-1), int32(0))
+		}
+		b.mu.Unlock()
+		_, _ = conn.Write(appendResponse(nil, int32(len(resp))))
+		_, _ = conn.Write(resp)
+	}
+}
+
+func TestKafka(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		query    string
+		electing int
+		code     int16
+		want     []string
+		err      string
+	}{
+		{"acks 1", "", 0, 0, []string{`RTX Thread {"v":0}`, `Net/IP {"v":1}`}, ""},
+		{"acks 0", "?acks=0", 0, 0, []string{`RTX Thread {"v":0}`, `Net/IP {"v":1}`}, ""},
+		{"new topic", "?acks=all", 2, 0, []string{`RTX Thread {"v":0}`, `Net/IP {"v":1}`}, ""},
+		{"error", "?partition=0", 0, 2, []string{`RTX Thread {"v":0}`, `Net/IP {"v":1}`}, "error code 2"},
+		{"no leader", "", MetadataRetries, 0, nil, "error code 5"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			b := &kafkaBroker{electing: tt.electing, errorCode: tt.code}
+			u, _ := url.Parse("kafka://" + b.serve(t) + "/events" + tt.query)
+			c, err := openKafka(u)
+			if err == nil {
+				for i, key := range []string{"RTX Thread", "Net/IP"} {
+					if err := c.Send(key, []byte(`{"v":`+strconv.Itoa(i)+`}`)); err != nil {
+						t.Errorf("kafkaClient.Send() %s error = %v", tt.name, err)
+					}
+				}
+				err = c.Close()
+			}
+			if (err != nil || tt.err != "") && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Errorf("kafkaClient %s error = %v, want %s", tt.name, err, tt.err)
+			}
+			for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(time.Millisecond) {
+				b.mu.Lock()
+				records := b.records
+				b.mu.Unlock()
+				if len(records) >= len(tt.want) || time.Now().After(deadline) {
+					if !reflect.DeepEqual(records, tt.want) && len(records)+len(tt.want) != 0 {
+						t.Errorf("kafkaClient %s records = %q, want %q", tt.name, records, tt.want)
+					}
+					break
+				}
+			}
+			b.mu.Lock()
+			if b.badCRC != 0 {
+				t.Errorf("kafkaClient %s record batch CRC wrong", tt.name)
+			}
+			b.mu.Unlock()
+		})
+	}
+}
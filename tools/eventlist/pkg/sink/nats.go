@@ -0,0 +1,189 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sink
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+var errNATS = errors.New("NATS")
+
+// natsClient publishes to the subjects <subject>.<component> of a NATS
+// server with the text protocol of NATS
+type natsClient struct {
+	conn    net.Conn
+	mu      sync.Mutex // writes of Send and of the reader
+	out     *bufio.Writer
+	subject string
+	pongs   chan struct{}
+	err     error // error sent by the server, under mu
+}
+
+// nats://[user:password@|token@]host[:port][/subject]
+func openNATS(u *url.URL) (Client, error) {
+	conn, err := net.DialTimeout("tcp", hostPort(u, "4222"), Timeout)
+	if err != nil {
+		return nil, err
+	}
+	c := &natsClient{conn: conn, out: bufio.NewWriter(conn), subject: topic(u), pongs: make(chan struct{}, 1)}
+	in := bufio.NewReader(conn)
+	if err = c.connect(in, u.User); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	go c.read(in)
+	return c, nil
+}
+
+func readLine(in *bufio.Reader) (string, error) {
+	line, err := in.ReadString('\n')
+	return strings.TrimRight(line, "\r\n"), err
+}
+
+// receive the INFO of the server, send CONNECT and wait for the PONG
+// of a PING, which confirms the connection
+func (c *natsClient) connect(in *bufio.Reader, user *url.Userinfo) error {
+	_ = c.conn.SetReadDeadline(time.Now().Add(Timeout))
+	defer c.conn.SetReadDeadline(time.Time{}) //nolint:errcheck
+	line, err := readLine(in)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		return fmt.Errorf("%w: unexpected %q", errNATS, line)
+	}
+	opts := map[string]any{"verbose": false, "pedantic": false, "name": "eventlist", "lang": "go", "protocol": 1}
+	if user != nil {
+		if password, ok := user.Password(); ok {
+			opts["user"], opts["pass"] = user.Username(), password
+		} else {
+			opts["auth_token"] = user.Username()
+		}
+	}
+	data, _ := json.Marshal(opts)
+	if _, err = fmt.Fprintf(c.out, "CONNECT %s\r\nPING\r\n", data); err != nil {
+		return err
+	}
+	if err = c.out.Flush(); err != nil {
+		return err
+	}
+	for {
+		if line, err = readLine(in); err != nil {
+			return err
+		}
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			_, _ = c.out.WriteString("PONG\r\n")
+			err = c.out.Flush()
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("%w: %s", errNATS, strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// answer the PINGs of the server and keep its errors
+func (c *natsClient) read(in *bufio.Reader) {
+	for {
+		line, err := readLine(in)
+		c.mu.Lock()
+		switch {
+		case err != nil:
+			if c.err == nil {
+				c.err = err
+			}
+		case line == "PING":
+			_, _ = c.out.WriteString("PONG\r\n")
+			_ = c.out.Flush()
+		case strings.HasPrefix(line, "-ERR"):
+			c.err = fmt.Errorf("%w: %s", errNATS, strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+		c.mu.Unlock()
+		if line == "PONG" || err != nil {
+			select {
+			case c.pongs <- struct{}{}:
+			default:
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// subject of a component, the separators and wildcards of NATS are replaced
+func (c *natsClient) subjectOf(component string) string {
+	return c.subject + "." + strings.Map(func(r rune) rune {
+		if r == '.' || r == '*' || r == '>' || r == ' ' || r == '\t' {
+			return '_'
+		}
+		return r
+	}, component)
+}
+
+func (c *natsClient) Send(key string, msg []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return c.err
+	}
+	if _, err := fmt.Fprintf(c.out, "PUB %s %d\r\n", c.subjectOf(key), len(msg)); err != nil {
+		return err
+	}
+	_, _ = c.out.Write(msg)
+	_, _ = c.out.WriteString("\r\n")
+	return c.out.Flush()
+}
+
+// Close waits for the PONG of a PING, the server has processed all
+// messages before it
+func (c *natsClient) Close() error {
+	c.mu.Lock()
+	_, _ = c.out.WriteString("PING\r\n")
+	err := c.out.Flush()
+	c.mu.Unlock()
+	if err == nil {
+		select {
+		case <-c.pongs:
+		case <-time.After(Timeout):
+			err = fmt.Errorf("%w: no PONG", errNATS)
+		}
+	}
+	c.mu.Lock()
+	if err == nil && c.err != nil {
+		err = c.err
+	}
+	c.mu.Unlock()
+	if e := c.conn.Close(); err == nil {
+		err = e
+	}
+	return err
+}
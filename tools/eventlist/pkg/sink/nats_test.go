@@ -0,0 +1,121 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sink
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type natsSession struct {
+	connect string
+	pub     []string // subject and payload
+	pong    bool     // the client answered the PING of the server
+}
+
+// NATS server of one connection, reply is sent after CONNECT
+func natsServer(t *testing.T, reply string) (string, chan natsSession) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+	session := make(chan natsSession, 1)
+	go func() {
+		var s natsSession
+		defer func() { session <- s }()
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		in := bufio.NewReader(conn)
+		_, _ = io.WriteString(conn, "INFO {\"server_id\":\"test\"}\r\n")
+		for {
+			line, err := readLine(in)
+			if err != nil {
+				return
+			}
+			fields := strings.Fields(line)
+			switch {
+			case strings.HasPrefix(line, "CONNECT "):
+				s.connect = strings.TrimPrefix(line, "CONNECT ")
+				_, _ = io.WriteString(conn, reply)
+			case line == "PING":
+				_, _ = io.WriteString(conn, "PONG\r\n")
+			case line == "PONG":
+				s.pong = true
+			case len(fields) == 3 && fields[0] == "PUB":
+				n, _ := strconv.Atoi(fields[2])
+				payload := make([]byte, n+2)
+				if _, err := io.ReadFull(in, payload); err != nil {
+					return
+				}
+				s.pub = append(s.pub, fields[1]+" "+string(payload[:n]))
+			}
+		}
+	}()
+	return l.Addr().String(), session
+}
+
+func TestNATS(t *testing.T) {
+	t.Parallel()
+
+	addr, session := natsServer(t, "PING\r\n")
+	c, err := openNATS(&url.URL{Scheme: "nats", Host: addr, Path: "/farm", User: url.User("token")})
+	if err != nil {
+		t.Fatalf("openNATS() error = %v", err)
+	}
+	for _, key := range []string{"RTX Thread", "Net.IP"} {
+		if err := c.Send(key, []byte(`{"v":1}`)); err != nil {
+			t.Errorf("natsClient.Send() error = %v", err)
+		}
+	}
+	if err := c.Close(); err != nil {
+		t.Errorf("natsClient.Close() error = %v", err)
+	}
+	s := <-session
+	if !strings.Contains(s.connect, `"auth_token":"token"`) {
+		t.Errorf("CONNECT = %s, want auth_token", s.connect)
+	}
+	want := []string{`farm.RTX_Thread {"v":1}`, `farm.Net_IP {"v":1}`}
+	if !reflect.DeepEqual(s.pub, want) {
+		t.Errorf("PUB = %q, want %q", s.pub, want)
+	}
+	if !s.pong {
+		t.Errorf("PING of the server not answered")
+	}
+}
+
+func TestNATS_error(t *testing.T) {
+	t.Parallel()
+
+	addr, _ := natsServer(t, "-ERR 'Authorization Violation'\r\n")
+	u := &url.URL{Scheme: "nats", Host: addr, User: url.UserPassword("user", "pw")}
+	if _, err := openNATS(u); err == nil || !strings.Contains(err.Error(), "Authorization Violation") {
+		t.Errorf("openNATS() error = %v, want Authorization Violation", err)
+	}
+}
@@ -0,0 +1,159 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package sink streams the decoded events as JSON messages to a message
+// bus, e.g. Kafka or NATS. The bus is selected by the scheme of the URL,
+// further buses are added with Register.
+package sink
+
+import (
+	"encoding/json"
+	"errors"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/mqtt"
+	"eventlist/pkg/where"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultTopic is the topic or subject without path in the URL
+const DefaultTopic = "eventlist"
+
+// Timeout is the time to connect or to wait for a response of the bus
+var Timeout = 10 * time.Second
+
+var errURL = errors.New("invalid sink URL")
+
+// Client sends the messages to a message bus
+type Client interface {
+	// Send sends the message with the key, the component of the event
+	Send(key string, msg []byte) error
+	// Close sends the pending messages and closes the connection
+	Close() error
+}
+
+// Open connects to the bus of the URL
+type Open func(u *url.URL) (Client, error)
+
+var clients = map[string]Open{
+	"kafka": openKafka,
+	"nats":  openNATS,
+}
+
+// Register adds the client of a message bus for the scheme of its URLs
+func Register(scheme string, open Open) {
+	clients[scheme] = open
+}
+
+// Schemes returns the schemes of the known message buses
+func Schemes() []string {
+	schemes := make([]string, 0, len(clients))
+	for scheme := range clients {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+// topic of the URL path
+func topic(u *url.URL) string {
+	if t := strings.Trim(u.Path, "/"); len(t) != 0 {
+		return t
+	}
+	return DefaultTopic
+}
+
+// address of the URL host with the default port
+func hostPort(u *url.URL, port string) string {
+	if u.Port() == "" {
+		return net.JoinHostPort(u.Hostname(), port)
+	}
+	return u.Host
+}
+
+// Sink sends the events passing the filter, the messages are the ones
+// of --mqtt
+type Sink struct {
+	url     string
+	topic   string
+	client  Client
+	filter  *where.Filter
+	Sent    int // number of sent events
+	Dropped int // number of events not matching the filter
+}
+
+// New connects to the message bus of the URL, a nil filter sends all events
+func New(rawURL string, filter *where.Filter) (*Sink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || len(u.Host) == 0 {
+		return nil, fmt.Errorf("%w: %s", errURL, rawURL)
+	}
+	open, ok := clients[u.Scheme]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s, known schemes: %s", errURL, rawURL, strings.Join(Schemes(), ", "))
+	}
+	client, err := open(u)
+	if err != nil {
+		return nil, err
+	}
+	return &Sink{url: u.Redacted(), topic: topic(u), client: client, filter: filter}, nil
+}
+
+// Event sends the event if it passes the filter
+func (s *Sink) Event(ev *bus.Event) error {
+	if !s.filter.Match(ev) {
+		s.Dropped++
+		return nil
+	}
+	value, _ := ev.Value()
+	msg, err := json.Marshal(mqtt.Message{
+		Index:     ev.Index,
+		Time:      ev.Time,
+		ID:        fmt.Sprintf("0x%04X", ev.Data.Info.ID),
+		Component: ev.Component(),
+		Property:  ev.Property(),
+		Level:     ev.Level(),
+		Value:     value,
+	})
+	if err != nil {
+		return err
+	}
+	if err = s.client.Send(ev.Component(), msg); err != nil {
+		return err
+	}
+	s.Sent++
+	return nil
+}
+
+// End sends the pending messages and disconnects
+func (s *Sink) End() error {
+	return s.client.Close()
+}
+
+// Report writes the number of sent and filtered events
+func (s *Sink) Report(out io.Writer) error {
+	title := "Message bus"
+	_, err := fmt.Fprintf(out, "   %s\n   %s\n\n%s: %d events sent to %s, %d events filtered out\n",
+		title, strings.Repeat("-", len(title)), s.url, s.Sent, s.topic, s.Dropped)
+	return err
+}
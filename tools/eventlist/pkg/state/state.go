@@ -0,0 +1,160 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package state
+
+import (
+	"eventlist/pkg/bus"
+	"eventlist/pkg/rtos"
+	"fmt"
+	"sort"
+)
+
+// Session is a start event of a start/stop pair without its stop event
+type Session struct {
+	Name     string  `json:"name"` // slot of the pair, A(0) to D(15)
+	Property string  `json:"property"`
+	Start    float64 `json:"start"`
+	Value    string  `json:"value"`
+}
+
+// Metric is the last value of an event type
+type Metric struct {
+	Component string  `json:"component"`
+	Property  string  `json:"property"`
+	Time      float64 `json:"time"`
+	Value     string  `json:"value"`
+	Count     int     `json:"count"`
+}
+
+// State is the system state reconstructed at a time
+type State struct {
+	Time     float64   `json:"time"`
+	Events   int       `json:"events"` // number of events up to the time
+	Last     float64   `json:"last"`   // time of the last event up to the time
+	Thread   string    `json:"thread,omitempty"`
+	Sessions []Session `json:"sessions"`
+	Metrics  []Metric  `json:"metrics"`
+}
+
+// Builder collects the state from the event stream, events after
+// the time of the query are ignored
+type Builder struct {
+	time     float64
+	events   int
+	last     float64
+	threads  *rtos.Tracker
+	sessions map[uint16]Session
+	metrics  map[string]*Metric
+}
+
+// New creates a builder of the state at time
+func New(time float64) *Builder {
+	return &Builder{
+		time:     time,
+		threads:  rtos.NewTracker(),
+		sessions: make(map[uint16]Session),
+		metrics:  make(map[string]*Metric),
+	}
+}
+
+func (b *Builder) Event(ev *bus.Event) error {
+	if ev.Time > b.time {
+		return nil
+	}
+	b.events++
+	if ev.Time > b.last {
+		b.last = ev.Time
+	}
+	_ = b.threads.Event(ev)
+	value, _ := ev.Value()
+	class, group, idx, start := ev.Data.Info.SplitID()
+	if class == 0xEF {
+		key := group<<4 | idx
+		if start {
+			b.sessions[key] = Session{
+				Name:     fmt.Sprintf("%c(%d)", 'A'+group, idx),
+				Property: ev.Property(),
+				Start:    ev.Time,
+				Value:    value,
+			}
+		} else {
+			delete(b.sessions, key)
+		}
+	}
+	key := ev.Component() + "\x00" + ev.Property()
+	m := b.metrics[key]
+	if m == nil {
+		m = &Metric{Component: ev.Component(), Property: ev.Property()}
+		b.metrics[key] = m
+	}
+	m.Time, m.Value = ev.Time, value
+	m.Count++
+	return nil
+}
+
+func (b *Builder) End() error {
+	return nil
+}
+
+// State returns the state of the events up to the time, the sessions
+// are sorted by start time and the metrics by component and property
+func (b *Builder) State() State {
+	s := State{Time: b.time, Events: b.events, Last: b.last,
+		Sessions: make([]Session, 0, len(b.sessions)), Metrics: make([]Metric, 0, len(b.metrics))}
+	if id, ok := b.threads.Current(); ok {
+		s.Thread = b.threads.Name(id)
+	}
+	for _, session := range b.sessions {
+		s.Sessions = append(s.Sessions, session)
+	}
+	sort.Slice(s.Sessions, func(i, j int) bool {
+		if s.Sessions[i].Start != s.Sessions[j].Start {
+			return s.Sessions[i].Start < s.Sessions[j].Start
+		}
+		return s.Sessions[i].Name < s.Sessions[j].Name
+	})
+	for _, m := range b.metrics {
+		s.Metrics = append(s.Metrics, *m)
+	}
+	sort.Slice(s.Metrics, func(i, j int) bool {
+		if s.Metrics[i].Component != s.Metrics[j].Component {
+			return s.Metrics[i].Component < s.Metrics[j].Component
+		}
+		return s.Metrics[i].Property < s.Metrics[j].Property
+	})
+	return s
+}
+
+// Lines returns the state as text lines
+func (s *State) Lines() []string {
+	thread := s.Thread
+	if thread == "" {
+		thread = "unknown"
+	}
+	lines := []string{
+		fmt.Sprintf("State at %.8f s: %d events, last at %.8f s, thread %s", s.Time, s.Events, s.Last, thread),
+	}
+	for _, session := range s.Sessions {
+		lines = append(lines, fmt.Sprintf("Open %s %s since %.8f s: %s", session.Name, session.Property, session.Start, session.Value))
+	}
+	for _, m := range s.Metrics {
+		lines = append(lines, fmt.Sprintf("%s %s = %s (%.8f s, %d events)", m.Component, m.Property, m.Value, m.Time, m.Count))
+	}
+	return lines
+}
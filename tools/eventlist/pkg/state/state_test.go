@@ -0,0 +1,119 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package state
+
+import (
+	"eventlist/pkg/bus"
+	"eventlist/pkg/event"
+	"eventlist/pkg/xml/scvd"
+	"reflect"
+	"testing"
+)
+
+func ev(time float64, id uint16, property string, value string, val1 int32) *bus.Event {
+	return bus.NewEvent(0, time, &event.Data{Typ: 2, Value1: val1, Info: event.Info{ID: id}},
+		&scvd.Event{Brief: "C", Property: property}, func() (string, error) { return value, nil })
+}
+
+func TestBuilder_State(t *testing.T) {
+	t.Parallel()
+
+	events := []*bus.Event{
+		ev(1.0, 0xEF00, "StartA", "a0", 0),
+		ev(1.5, 0xEF41, "StartB", "b1", 0),
+		ev(2.0, 0x1000, "ThreadSwitched", "t1", 1),
+		ev(2.5, 0xEF20, "StopA", "a0 done", 0),
+		ev(3.0, 0x1000, "ThreadSwitched", "t2", 2),
+	}
+	tests := []struct {
+		name     string
+		time     float64
+		events   int
+		thread   string
+		sessions []Session
+		metrics  int
+	}{
+		{"before", 0.5, 0, "", []Session{}, 0},
+		{"one open", 1.0, 1, "", []Session{{"A(0)", "StartA", 1.0, "a0"}}, 1},
+		{"two open", 2.0, 3, "0x00000001", []Session{{"A(0)", "StartA", 1.0, "a0"}, {"B(1)", "StartB", 1.5, "b1"}}, 3},
+		{"stopped", 2.7, 4, "0x00000001", []Session{{"B(1)", "StartB", 1.5, "b1"}}, 4},
+		{"end", 10, 5, "0x00000002", []Session{{"B(1)", "StartB", 1.5, "b1"}}, 4},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			b := New(tt.time)
+			for _, e := range events {
+				if err := b.Event(e); err != nil {
+					t.Fatalf("Builder.Event() error = %v", err)
+				}
+			}
+			_ = b.End()
+			s := b.State()
+			if s.Time != tt.time || s.Events != tt.events || s.Thread != tt.thread {
+				t.Errorf("Builder.State() %s = %v, %d, %q, want %v, %d, %q", tt.name, s.Time, s.Events, s.Thread, tt.time, tt.events, tt.thread)
+			}
+			if !reflect.DeepEqual(s.Sessions, tt.sessions) {
+				t.Errorf("Builder.State() %s sessions = %v, want %v", tt.name, s.Sessions, tt.sessions)
+			}
+			if len(s.Metrics) != tt.metrics {
+				t.Errorf("Builder.State() %s metrics = %v, want %d", tt.name, s.Metrics, tt.metrics)
+			}
+		})
+	}
+}
+
+func TestBuilder_metrics(t *testing.T) {
+	t.Parallel()
+
+	b := New(5)
+	_ = b.Event(ev(1, 0x1001, "Level", "10", 10))
+	_ = b.Event(ev(2, 0x1002, "Alarm", "on", 1))
+	_ = b.Event(ev(3, 0x1001, "Level", "12", 12))
+	_ = b.Event(ev(6, 0x1001, "Level", "15", 15))
+	want := []Metric{
+		{"C", "Alarm", 2, "on", 1},
+		{"C", "Level", 3, "12", 2},
+	}
+	if got := b.State().Metrics; !reflect.DeepEqual(got, want) {
+		t.Errorf("Builder.State() metrics = %v, want %v", got, want)
+	}
+}
+
+func TestState_Lines(t *testing.T) {
+	t.Parallel()
+
+	s := State{Time: 2, Events: 3, Last: 1.5, Thread: "main",
+		Sessions: []Session{{"A(0)", "Start", 1, "x"}},
+		Metrics:  []Metric{{"C", "Level", 1.5, "12", 2}}}
+	want := []string{
+		"State at 2.00000000 s: 3 events, last at 1.50000000 s, thread main",
+		"Open A(0) Start since 1.00000000 s: x",
+		"C Level = 12 (1.50000000 s, 2 events)",
+	}
+	if got := s.Lines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("State.Lines() = %v, want %v", got, want)
+	}
+	s = State{}
+	if got := s.Lines()[0]; got != "State at 0.00000000 s: 0 events, last at 0.00000000 s, thread unknown" {
+		t.Errorf("State.Lines() = %v, want unknown thread", got)
+	}
+}
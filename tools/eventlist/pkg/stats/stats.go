@@ -0,0 +1,178 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package stats summarizes a stream of values in constant memory: count,
+// min, max and mean are kept exactly, percentiles are estimated by a
+// merging t-digest once there are more than ExactSize values.
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// ExactSize is the number of values kept for exact percentiles
+const ExactSize = 1000
+
+// DefaultCompression bounds the number of centroids of the digest
+const DefaultCompression = 100
+
+type centroid struct {
+	mean, weight float64
+}
+
+// Digest summarizes values, the zero value is an empty digest
+type Digest struct {
+	Compression float64 // DefaultCompression if 0
+	count       int
+	sum         float64
+	min, max    float64
+	values      []float64  // values not merged into the centroids
+	centroids   []centroid // sorted by mean, nil while exact
+}
+
+// Add adds a value
+func (d *Digest) Add(x float64) {
+	if d.count == 0 || x < d.min {
+		d.min = x
+	}
+	if d.count == 0 || x > d.max {
+		d.max = x
+	}
+	d.count++
+	d.sum += x
+	d.values = append(d.values, x)
+	if (d.centroids == nil && len(d.values) > ExactSize) ||
+		(d.centroids != nil && len(d.values) >= 5*int(d.compression())) {
+		d.merge()
+	}
+}
+
+// Count returns the number of values
+func (d *Digest) Count() int {
+	return d.count
+}
+
+// Min returns the smallest value, 0 without values
+func (d *Digest) Min() float64 {
+	return d.min
+}
+
+// Max returns the largest value, 0 without values
+func (d *Digest) Max() float64 {
+	return d.max
+}
+
+// Sum returns the sum of the values
+func (d *Digest) Sum() float64 {
+	return d.sum
+}
+
+// Mean returns the average of the values, 0 without values
+func (d *Digest) Mean() float64 {
+	if d.count == 0 {
+		return 0
+	}
+	return d.sum / float64(d.count)
+}
+
+// Exact reports if the percentiles are exact
+func (d *Digest) Exact() bool {
+	return d.centroids == nil
+}
+
+// Quantile returns the value at q between 0 and 1, with exact values the
+// nearest rank, 0 without values
+func (d *Digest) Quantile(q float64) float64 {
+	if d.count == 0 {
+		return 0
+	}
+	if d.centroids == nil {
+		sort.Float64s(d.values)
+		i := int(math.Ceil(q*float64(len(d.values)))) - 1
+		if i < 0 {
+			i = 0
+		}
+		if i >= len(d.values) {
+			i = len(d.values) - 1
+		}
+		return d.values[i]
+	}
+	d.merge()
+	total := float64(d.count)
+	target := q * total
+	if target <= 0 {
+		return d.min
+	}
+	if target >= total {
+		return d.max
+	}
+	// interpolate between the centers of the centroids, min and max
+	prevMean, prevRank := d.min, 0.0
+	rank := 0.0
+	for _, c := range d.centroids {
+		mid := rank + c.weight/2
+		if target < mid {
+			return prevMean + (c.mean-prevMean)*(target-prevRank)/(mid-prevRank)
+		}
+		prevMean, prevRank = c.mean, mid
+		rank += c.weight
+	}
+	return prevMean + (d.max-prevMean)*(target-prevRank)/(total-prevRank)
+}
+
+func (d *Digest) compression() float64 {
+	if d.Compression <= 0 {
+		return DefaultCompression
+	}
+	return d.Compression
+}
+
+// scale function k1 of the t-digest, limits the weight of the centroids
+// near the tails
+func (d *Digest) k(q float64) float64 {
+	return d.compression() / (2 * math.Pi) * math.Asin(2*q-1)
+}
+
+// merge the values into the centroids
+func (d *Digest) merge() {
+	if len(d.values) == 0 {
+		return
+	}
+	all := make([]centroid, 0, len(d.centroids)+len(d.values))
+	all = append(all, d.centroids...)
+	for _, x := range d.values {
+		all = append(all, centroid{x, 1})
+	}
+	d.values = d.values[:0]
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+	total := float64(d.count)
+	merged := all[:1]
+	done := 0.0 // weight of the centroids before the last one
+	for _, c := range all[1:] {
+		last := &merged[len(merged)-1]
+		if d.k((done+last.weight+c.weight)/total)-d.k(done/total) <= 1 {
+			last.mean += (c.mean - last.mean) * c.weight / (last.weight + c.weight)
+			last.weight += c.weight
+		} else {
+			done += last.weight
+			merged = append(merged, c)
+		}
+	}
+	d.centroids = append(make([]centroid, 0, len(merged)), merged...)
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stats
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestDigest_exact(t *testing.T) {
+	t.Parallel()
+
+	var d Digest
+	for _, v := range []float64{5, 1, 4, 2, 3} {
+		d.Add(v)
+	}
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"count", float64(d.Count()), 5},
+		{"min", d.Min(), 1},
+		{"max", d.Max(), 5},
+		{"sum", d.Sum(), 15},
+		{"mean", d.Mean(), 3},
+		{"p0", d.Quantile(0), 1},
+		{"p20", d.Quantile(0.2), 1},
+		{"p50", d.Quantile(0.5), 3},
+		{"p90", d.Quantile(0.9), 5},
+		{"p100", d.Quantile(1), 5},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("Digest %s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+	if !d.Exact() {
+		t.Errorf("Digest.Exact() = false, want true")
+	}
+	var empty Digest
+	if empty.Quantile(0.5) != 0 || empty.Mean() != 0 {
+		t.Errorf("Digest empty = %v, %v, want 0", empty.Quantile(0.5), empty.Mean())
+	}
+}
+
+func TestDigest_stream(t *testing.T) {
+	t.Parallel()
+
+	var d Digest
+	r := rand.New(rand.NewSource(1)) //nolint:gosec
+	const n = 1000000
+	for i := 0; i < n; i++ {
+		d.Add(r.Float64())
+	}
+	if d.Exact() || d.Count() != n || d.Min() < 0 || d.Max() > 1 {
+		t.Errorf("Digest = exact %v count %d min %v max %v, want estimate of %d values", d.Exact(), d.Count(), d.Min(), d.Max(), n)
+	}
+	if math.Abs(d.Mean()-0.5) > 0.001 {
+		t.Errorf("Digest.Mean() = %v, want 0.5", d.Mean())
+	}
+	for _, q := range []float64{0.01, 0.1, 0.5, 0.9, 0.99, 0.999} {
+		if got := d.Quantile(q); math.Abs(got-q) > 0.005 {
+			t.Errorf("Digest.Quantile(%v) = %v, want %v", q, got, q)
+		}
+	}
+	// the memory is bounded by the compression
+	if len(d.centroids) > 2*DefaultCompression || cap(d.values) > 2*ExactSize {
+		t.Errorf("Digest size = %d centroids, %d values, want at most %d, %d", len(d.centroids), cap(d.values), 2*DefaultCompression, 2*ExactSize)
+	}
+}
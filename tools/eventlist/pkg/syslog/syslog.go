@@ -0,0 +1,236 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package syslog forwards the decoded events as RFC 5424 syslog messages or
+// as JSON structured logs, the severity is mapped from the event level.
+package syslog
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/where"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// formats of the messages
+const (
+	RFC5424 = "rfc5424"
+	JSON    = "json"
+)
+
+// facility local0 of the messages
+const facility = 16
+
+var errFormat = errors.New("invalid syslog format")
+
+// Start is the time of the first event the event times are added to, the
+// time of New if zero
+var Start time.Time
+
+// Anonymous leaves the host name and the process ID out of the messages
+var Anonymous bool
+
+// severities of the event levels, events without level are informational
+var severities = map[string]int{
+	"Error":  3, // error
+	"API":    5, // notice
+	"Op":     6, // informational
+	"Detail": 7, // debug
+}
+
+var severityNames = []string{"emerg", "alert", "crit", "error", "warning", "notice", "info", "debug"}
+
+func severity(level string) int {
+	if s, ok := severities[level]; ok {
+		return s
+	}
+	return 6
+}
+
+// Entry is a JSON structured log message of an event
+type Entry struct {
+	Timestamp string  `json:"timestamp"` // start of the forwarding plus the event time
+	Severity  string  `json:"severity"`
+	Host      string  `json:"host"`
+	App       string  `json:"app"`
+	Index     int     `json:"index"`
+	Time      float64 `json:"time"` // event time in s
+	ID        string  `json:"id"`
+	Component string  `json:"component"`
+	Property  string  `json:"property"`
+	Level     string  `json:"level,omitempty"`
+	Message   string  `json:"message"` // value of the event
+}
+
+// Forwarder sends a message per event passing the filter, to a syslog
+// server with UDP or TCP, or appends the messages to a file
+type Forwarder struct {
+	target    string
+	format    string
+	conn      net.Conn // UDP: a datagram per message
+	stream    bool     // TCP: octet counting framing of RFC 5424, JSON lines
+	file      *os.File
+	out       *bufio.Writer
+	filter    *where.Filter
+	host      string
+	pid       string
+	base      time.Time
+	line      []byte
+	frame     []byte
+	Forwarded int    // number of forwarded events
+	Dropped   int    // number of events not matching the filter
+	Name      string // name of the target in the report
+}
+
+// New connects to the syslog server of udp://host[:514] or
+// tcp://host[:514], any other target is a file, a nil filter forwards
+// all events
+func New(target string, format string, filter *where.Filter) (*Forwarder, error) {
+	if format == "" {
+		format = RFC5424
+	}
+	if format != RFC5424 && format != JSON {
+		return nil, fmt.Errorf("%w: %s", errFormat, format)
+	}
+	f := &Forwarder{target: target, format: format, filter: filter, pid: strconv.Itoa(os.Getpid()), base: Start, Name: target}
+	if f.base.IsZero() {
+		f.base = time.Now()
+	}
+	var err error
+	if f.host, err = os.Hostname(); err != nil || f.host == "" || Anonymous {
+		f.host = "-"
+	}
+	if Anonymous {
+		f.pid = "-"
+	}
+	network, addr, ok := strings.Cut(target, "://")
+	switch {
+	case ok && (network == "udp" || network == "tcp"):
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			addr = net.JoinHostPort(addr, "514")
+		}
+		if f.conn, err = net.DialTimeout(network, addr, 10*time.Second); err != nil {
+			return nil, err
+		}
+		f.stream = network == "tcp"
+	default:
+		if f.file, err = os.Create(target); err != nil {
+			return nil, err
+		}
+		f.out = bufio.NewWriter(f.file)
+	}
+	return f, nil
+}
+
+// escape a parameter value of the structured data
+var escaper = strings.NewReplacer(`"`, `\"`, `\`, `\\`, `]`, `\]`)
+
+// append the RFC 5424 message of the event, the structured data uses the
+// enterprise number 32473 reserved for documentation
+func (f *Forwarder) appendRFC5424(b []byte, ev *bus.Event, ts time.Time, value string) []byte {
+	b = fmt.Appendf(b, "<%d>1 %s %s eventlist %s 0x%04X [event@32473 index=\"%d\" time=\"%s\" component=\"%s\" property=\"%s\"",
+		facility*8+severity(ev.Level()), ts.Format("2006-01-02T15:04:05.000000Z07:00"), f.host, f.pid,
+		ev.Data.Info.ID, ev.Index, strconv.FormatFloat(ev.Time, 'f', -1, 64),
+		escaper.Replace(ev.Component()), escaper.Replace(ev.Property()))
+	if level := ev.Level(); level != "" {
+		b = fmt.Appendf(b, " level=\"%s\"", level)
+	}
+	b = append(b, "] "...)
+	return append(b, value...)
+}
+
+func (f *Forwarder) appendJSON(b []byte, ev *bus.Event, ts time.Time, value string) ([]byte, error) {
+	data, err := json.Marshal(Entry{
+		Timestamp: ts.Format(time.RFC3339Nano),
+		Severity:  severityNames[severity(ev.Level())],
+		Host:      f.host,
+		App:       "eventlist",
+		Index:     ev.Index,
+		Time:      ev.Time,
+		ID:        fmt.Sprintf("0x%04X", ev.Data.Info.ID),
+		Component: ev.Component(),
+		Property:  ev.Property(),
+		Level:     ev.Level(),
+		Message:   value,
+	})
+	return append(b, data...), err
+}
+
+// Event forwards the event if it passes the filter
+func (f *Forwarder) Event(ev *bus.Event) error {
+	if !f.filter.Match(ev) {
+		f.Dropped++
+		return nil
+	}
+	value, _ := ev.Value()
+	ts := f.base.Add(time.Duration(ev.Time * float64(time.Second))).UTC()
+	var msg []byte
+	var err error
+	if f.format == JSON {
+		if msg, err = f.appendJSON(f.line[:0], ev, ts, value); err != nil {
+			return err
+		}
+	} else {
+		msg = f.appendRFC5424(f.line[:0], ev, ts, value)
+	}
+	f.line = msg
+	switch {
+	case f.file != nil:
+		_, err = f.out.Write(append(msg, '\n'))
+	case f.stream && f.format == RFC5424:
+		f.frame = append(strconv.AppendInt(f.frame[:0], int64(len(msg)), 10), ' ')
+		_, err = f.conn.Write(append(f.frame, msg...))
+	case f.stream:
+		_, err = f.conn.Write(append(msg, '\n'))
+	default:
+		_, err = f.conn.Write(msg)
+	}
+	if err != nil {
+		return err
+	}
+	f.Forwarded++
+	return nil
+}
+
+// End closes the connection or writes the buffered messages and closes the file
+func (f *Forwarder) End() error {
+	if f.conn != nil {
+		return f.conn.Close()
+	}
+	err := f.out.Flush()
+	if e := f.file.Close(); err == nil {
+		err = e
+	}
+	return err
+}
+
+// Report writes the number of forwarded and filtered events
+func (f *Forwarder) Report(out io.Writer) error {
+	title := "Syslog"
+	_, err := fmt.Fprintf(out, "   %s\n   %s\n\n%s: %d events forwarded as %s, %d events filtered out\n",
+		title, strings.Repeat("-", len(title)), f.Name, f.Forwarded, f.format, f.Dropped)
+	return err
+}
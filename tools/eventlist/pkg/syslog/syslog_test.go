@@ -0,0 +1,211 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package syslog
+
+import (
+	"bufio"
+	"bytes"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/event"
+	"eventlist/pkg/where"
+	"eventlist/pkg/xml/scvd"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	created = &scvd.Event{Brief: "RTX Thread", Property: "ThreadCreated", Level: "Op"}
+	failed  = &scvd.Event{Brief: "Net \"IP\"", Property: "Error", Level: "Error"}
+)
+
+func writeEvents(t *testing.T, f *Forwarder) {
+	t.Helper()
+	f.base = time.Date(2023, 11, 14, 10, 0, 0, 0, time.UTC)
+	f.host, f.pid = "lab", "42"
+	events := []*bus.Event{
+		bus.NewEvent(0, 0.5, &event.Data{Info: event.Info{ID: 0xF401}}, created,
+			func() (string, error) { return "thread_id=0x20000100", nil }),
+		bus.NewEvent(1, 1.25, &event.Data{Info: event.Info{ID: 0x4E01}}, failed,
+			func() (string, error) { return "code=5]", nil }),
+	}
+	for _, ev := range events {
+		if err := f.Event(ev); err != nil {
+			t.Errorf("Forwarder.Event() error = %v", err)
+		}
+	}
+	if err := f.End(); err != nil {
+		t.Errorf("Forwarder.End() error = %v", err)
+	}
+}
+
+const (
+	wantCreated = `<134>1 2023-11-14T10:00:00.500000Z lab eventlist 42 0xF401 [event@32473 index="0" time="0.5" component="RTX Thread" property="ThreadCreated" level="Op"] thread_id=0x20000100`
+	wantFailed  = `<131>1 2023-11-14T10:00:01.250000Z lab eventlist 42 0x4E01 [event@32473 index="1" time="1.25" component="Net \"IP\"" property="Error" level="Error"] code=5]`
+	wantJSON    = `{"timestamp":"2023-11-14T10:00:01.25Z","severity":"error","host":"lab","app":"eventlist","index":1,"time":1.25,"id":"0x4E01","component":"Net \"IP\"","property":"Error","level":"Error","message":"code=5]"}`
+)
+
+func TestForwarder_file(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		format string
+		filter []string
+		want   []string
+	}{
+		{"rfc5424", "", nil, []string{wantCreated, wantFailed}},
+		{"json", JSON, []string{"level=Error"}, []string{wantJSON}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			name := filepath.Join(t.TempDir(), "events.log")
+			filter, _ := where.Parse(tt.filter)
+			f, err := New(name, tt.format, filter)
+			if err != nil {
+				t.Fatalf("New() %s error = %v", tt.name, err)
+			}
+			writeEvents(t, f)
+			data, err := os.ReadFile(name)
+			if got := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"); err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Forwarder %s = %q, %v, want %q", tt.name, got, err, tt.want)
+			}
+			var b bytes.Buffer
+			if err := f.Report(&b); err != nil || !strings.Contains(b.String(), "events forwarded as") {
+				t.Errorf("Forwarder.Report() %s = %q, %v", tt.name, b.String(), err)
+			}
+			if f.Forwarded+f.Dropped != 2 || f.Forwarded != len(tt.want) {
+				t.Errorf("Forwarder %s = %d forwarded, %d dropped", tt.name, f.Forwarded, f.Dropped)
+			}
+		})
+	}
+}
+
+func TestForwarder_udp(t *testing.T) {
+	t.Parallel()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.ListenPacket() error = %v", err)
+	}
+	defer conn.Close()
+	f, err := New("udp://"+conn.LocalAddr().String(), RFC5424, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	writeEvents(t, f)
+	buf := make([]byte, 1024)
+	for _, want := range []string{wantCreated, wantFailed} {
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil || string(buf[:n]) != want {
+			t.Errorf("Forwarder udp = %q, %v, want %q", buf[:n], err, want)
+		}
+	}
+}
+
+func TestForwarder_tcp(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{"rfc5424", RFC5424, fmt.Sprintf("%d %s%d %s", len(wantCreated), wantCreated, len(wantFailed), wantFailed)},
+		{"json", JSON, wantJSON + "\n"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("net.Listen() error = %v", err)
+			}
+			defer l.Close()
+			received := make(chan string, 1)
+			go func() {
+				conn, err := l.Accept()
+				if err != nil {
+					received <- err.Error()
+					return
+				}
+				defer conn.Close()
+				data, _ := io.ReadAll(bufio.NewReader(conn))
+				received <- string(data)
+			}()
+			filter, _ := where.Parse([]string{"level=Error"})
+			if tt.format == RFC5424 {
+				filter = nil
+			}
+			f, err := New("tcp://"+l.Addr().String(), tt.format, filter)
+			if err != nil {
+				t.Fatalf("New() %s error = %v", tt.name, err)
+			}
+			writeEvents(t, f)
+			if got := <-received; got != tt.want {
+				t.Errorf("Forwarder tcp %s = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	if _, err := New(filepath.Join(t.TempDir(), "x.log"), "cef", nil); err == nil {
+		t.Errorf("New() error = nil, want error")
+	}
+	if _, err := New(filepath.Join(t.TempDir(), "nix", "x.log"), "", nil); err == nil {
+		t.Errorf("New() error = nil, want error")
+	}
+	if _, err := New("tcp://127.0.0.1:1", "", nil); err == nil {
+		t.Errorf("New() error = nil, want error")
+	}
+}
+
+func TestNew_anonymous(t *testing.T) { //nolint:golint,paralleltest
+	Start, Anonymous = time.Unix(0, 0).UTC(), true
+	defer func() { Start, Anonymous = time.Time{}, false }()
+
+	f, err := New(filepath.Join(t.TempDir(), "events.log"), "", nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer f.End()
+	if !f.base.Equal(Start) || f.host != "-" || f.pid != "-" {
+		t.Errorf("New() = %v, %q, %q, want %v, -, -", f.base, f.host, f.pid, Start)
+	}
+	f.Name = "events.log"
+	var b bytes.Buffer
+	if err := f.Report(&b); err != nil || !strings.Contains(b.String(), "\nevents.log: 0 events") {
+		t.Errorf("Forwarder.Report() = %q, %v", b.String(), err)
+	}
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package unknown collects the events without SCVD definition, so the
+// authors of SCVD files see which events are missing.
+package unknown
+
+import (
+	"eventlist/pkg/bus"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Event is an event ID without definition
+type Event struct {
+	ID     uint16
+	Count  int
+	First  int    // index of the first event
+	Sample string // values of the first event
+}
+
+// Summary collects the events without definition by their ID
+type Summary struct {
+	events map[uint16]*Event
+	Known  func(id uint16) bool // IDs decoded without SCVD definition, e.g. by plugins
+}
+
+// New creates the summary of the unknown events
+func New() *Summary {
+	return &Summary{events: make(map[uint16]*Event)}
+}
+
+// Event counts an event without definition, the events of the Event
+// Recorder, stdout and the start/stop events need none
+func (s *Summary) Event(ev *bus.Event) error {
+	if ev.Def != nil || ev.Data.Info.Builtin() {
+		return nil
+	}
+	id := ev.Data.Info.ID
+	if e, ok := s.events[id]; ok {
+		e.Count++
+		return nil
+	}
+	if s.Known != nil && s.Known(id) {
+		return nil
+	}
+	s.events[id] = &Event{ID: id, Count: 1, First: ev.Index, Sample: ev.Data.GetValuesAsString()}
+	return nil
+}
+
+// End has nothing to finish
+func (s *Summary) End() error {
+	return nil
+}
+
+// Events returns the unknown events sorted by their ID
+func (s *Summary) Events() []Event {
+	events := make([]Event, 0, len(s.events))
+	for _, e := range s.events {
+		events = append(events, *e)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+	return events
+}
+
+// Report writes a line per unknown event ID with the number of events,
+// the index and the values of the first one
+func (s *Summary) Report(w io.Writer) error {
+	title := "Unknown events"
+	if _, err := fmt.Fprintf(w, "   %s\n   %s\n\n", title, strings.Repeat("-", len(title))); err != nil {
+		return err
+	}
+	events := s.Events()
+	if len(events) == 0 {
+		_, err := io.WriteString(w, "all events are defined\n")
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "%-6s %8s %8s %s\n", "Id", "Count", "First", "Sample"); err != nil {
+		return err
+	}
+	for _, e := range events {
+		if _, err := fmt.Fprintf(w, "0x%04X %8d %8d %s\n", e.ID, e.Count, e.First, e.Sample); err != nil {
+			return err
+		}
+	}
+	return nil
+}
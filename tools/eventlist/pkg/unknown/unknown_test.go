@@ -0,0 +1,65 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package unknown
+
+import (
+	"eventlist/pkg/bus"
+	"eventlist/pkg/event"
+	"eventlist/pkg/xml/scvd"
+	"strings"
+	"testing"
+)
+
+func TestSummary_Report(t *testing.T) {
+	t.Parallel()
+
+	events := []struct {
+		id  uint16
+		def *scvd.Event
+	}{
+		{0x4A01, nil}, {0xFF03, nil}, {0x0A01, &scvd.Event{}}, {0x4A01, nil}, {0xEF00, nil}, {0x1002, nil}, {0xC000, nil},
+	}
+	s := New()
+	s.Known = func(id uint16) bool { return id>>8 == 0xC0 }
+	for i, e := range events {
+		data := &event.Data{Typ: 2, Value1: int32(i), Info: event.Info{ID: e.id}}
+		if err := s.Event(bus.NewEvent(i, 0, data, e.def, nil)); err != nil {
+			t.Errorf("Summary.Event() error = %v", err)
+		}
+	}
+	if err := s.End(); err != nil {
+		t.Errorf("Summary.End() error = %v", err)
+	}
+	var b strings.Builder
+	if err := s.Report(&b); err != nil {
+		t.Errorf("Summary.Report() error = %v", err)
+	}
+	want := "   Unknown events\n   --------------\n\n" +
+		"Id        Count    First Sample\n" +
+		"0x1002        1        5 val1=0x00000005, val2=0x00000000\n" +
+		"0x4A01        2        0 val1=0x00000000, val2=0x00000000\n"
+	if b.String() != want {
+		t.Errorf("Summary.Report() = %q, want %q", b.String(), want)
+	}
+
+	b.Reset()
+	if err := New().Report(&b); err != nil || !strings.HasSuffix(b.String(), "all events are defined\n") {
+		t.Errorf("Summary.Report() = %q, %v", b.String(), err)
+	}
+}
@@ -0,0 +1,513 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package view
+
+import (
+	"eventlist/pkg/bus"
+	"eventlist/pkg/event"
+	"eventlist/pkg/output"
+	"eventlist/pkg/state"
+	"eventlist/pkg/xml/scvd"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+const (
+	detailHeight  = 7 // separator and six lines
+	maxColumnSize = 24
+	helpText      = "q quit  / search  n/N next/prev  m/M marker  f filter  t time  F follow  Enter detail  s state"
+)
+
+// Item is one decoded event of the viewer
+type Item struct {
+	Record output.EventRecord
+	ID     uint16
+	Def    *scvd.Event // nil if there is no SCVD definition
+	Data   event.Data
+}
+
+// Viewer is an interactive terminal viewer of the decoded events
+type Viewer struct {
+	title         string
+	items         []Item
+	visible       []int // indexes of the items passing the filter
+	filter        string
+	search        string
+	cursor        int // position in visible
+	top           int // first shown position in visible
+	follow        bool
+	detail        bool
+	state         bool   // the detail pane shows the state at the selected item
+	prompt        string // label of the active input line, empty if none
+	input         []rune
+	message       string
+	componentSize int
+	propertySize  int
+
+	mu      sync.Mutex
+	pending []Item // decoded items not yet added
+	posted  bool   // an interrupt announcing the pending items is queued
+	done    bool
+	err     error
+}
+
+// New creates a viewer with a title shown in the header line
+func New(title string) *Viewer {
+	return &Viewer{title: title, componentSize: len("Component"), propertySize: len("Event")}
+}
+
+// check if an item contains the text in its component, property or value
+func contains(item *Item, text string) bool {
+	text = strings.ToLower(text)
+	rec := &item.Record
+	return strings.Contains(strings.ToLower(rec.Component), text) ||
+		strings.Contains(strings.ToLower(rec.EventProperty), text) ||
+		strings.Contains(strings.ToLower(rec.Value), text)
+}
+
+// Add appends decoded items
+func (v *Viewer) Add(items ...Item) {
+	for _, item := range items {
+		v.items = append(v.items, item)
+		if v.filter == "" || contains(&item, v.filter) {
+			v.visible = append(v.visible, len(v.items)-1)
+		}
+		v.componentSize = columnSize(v.componentSize, item.Record.Component)
+		v.propertySize = columnSize(v.propertySize, item.Record.EventProperty)
+	}
+	if v.follow {
+		v.End()
+	}
+}
+
+// widen a column for text up to maxColumnSize
+func columnSize(size int, text string) int {
+	if len(text) > size {
+		size = len(text)
+	}
+	if size > maxColumnSize {
+		size = maxColumnSize
+	}
+	return size
+}
+
+// Current returns the selected item, nil if there is none
+func (v *Viewer) Current() *Item {
+	if v.cursor < 0 || v.cursor >= len(v.visible) {
+		return nil
+	}
+	return &v.items[v.visible[v.cursor]]
+}
+
+// SetFilter shows only the items containing text, the selected item is kept if possible
+func (v *Viewer) SetFilter(text string) {
+	selected := -1
+	if item := v.Current(); item != nil {
+		selected = item.Record.Index
+	}
+	v.filter = text
+	v.visible = v.visible[:0]
+	for i := range v.items {
+		if text == "" || contains(&v.items[i], text) {
+			v.visible = append(v.visible, i)
+		}
+	}
+	v.cursor = sort.Search(len(v.visible), func(i int) bool { return v.items[v.visible[i]].Record.Index >= selected })
+	v.Move(0)
+}
+
+// Find selects the next item containing the search text, backwards if forward is false
+func (v *Viewer) Find(forward bool) bool {
+	n := len(v.visible)
+	if v.search == "" || n == 0 {
+		return false
+	}
+	step := 1
+	if !forward {
+		step = n - 1
+	}
+	for i, pos := 0, v.cursor; i < n; i++ {
+		pos = (pos + step) % n
+		if contains(&v.items[v.visible[pos]], v.search) {
+			v.cursor = pos
+			return true
+		}
+	}
+	return false
+}
+
+// NextMarker selects the next marker event, backwards if forward is false
+func (v *Viewer) NextMarker(forward bool) bool {
+	n := len(v.visible)
+	step := 1
+	if !forward {
+		step = n - 1
+	}
+	for i, pos := 0, v.cursor; i < n; i++ {
+		pos = (pos + step) % n
+		if v.items[v.visible[pos]].Record.Marker {
+			v.cursor = pos
+			return true
+		}
+	}
+	return false
+}
+
+// JumpTime selects the first item at or after the time in seconds
+func (v *Viewer) JumpTime(t float64) {
+	v.cursor = sort.Search(len(v.visible), func(i int) bool { return v.items[v.visible[i]].Record.Time >= t })
+	v.Move(0)
+}
+
+// Move moves the selection by delta items
+func (v *Viewer) Move(delta int) {
+	v.cursor += delta
+	if v.cursor >= len(v.visible) {
+		v.cursor = len(v.visible) - 1
+	}
+	if v.cursor < 0 {
+		v.cursor = 0
+	}
+}
+
+// End selects the last item
+func (v *Viewer) End() {
+	v.cursor = len(v.visible) - 1
+	v.Move(0)
+}
+
+// State reconstructs the system state at the time of an item from all
+// items up to it, the filter is not applied
+func (v *Viewer) State(item *Item) state.State {
+	b := state.New(item.Record.Time)
+	for i := range v.items {
+		it := &v.items[i]
+		value := it.Record.Value
+		_ = b.Event(bus.NewEvent(it.Record.Index, it.Record.Time, &it.Data, it.Def,
+			func() (string, error) { return value, nil }))
+	}
+	return b.State()
+}
+
+// number of list lines for a screen height
+func (v *Viewer) listHeight(height int) int {
+	h := height - 2 // header and status line
+	if v.detail {
+		h -= detailHeight
+	}
+	if h < 1 {
+		h = 1
+	}
+	return h
+}
+
+// HandleKey processes a key, returns true to quit
+func (v *Viewer) HandleKey(ev *tcell.EventKey, height int) bool {
+	v.message = ""
+	if v.prompt != "" {
+		v.handleInput(ev)
+		return false
+	}
+	page := v.listHeight(height)
+	switch ev.Key() {
+	case tcell.KeyEscape, tcell.KeyCtrlC:
+		return true
+	case tcell.KeyUp:
+		v.Move(-1)
+	case tcell.KeyDown:
+		v.Move(1)
+	case tcell.KeyPgUp:
+		v.Move(-page)
+	case tcell.KeyPgDn:
+		v.Move(page)
+	case tcell.KeyHome:
+		v.Move(-len(v.visible))
+	case tcell.KeyEnd:
+		v.End()
+	case tcell.KeyEnter:
+		v.detail = !v.detail
+	case tcell.KeyRune:
+		return v.handleRune(ev.Rune(), page)
+	}
+	return false
+}
+
+func (v *Viewer) handleRune(r rune, page int) bool {
+	switch r {
+	case 'q':
+		return true
+	case 'k':
+		v.Move(-1)
+	case 'j':
+		v.Move(1)
+	case ' ':
+		v.Move(page)
+	case 'g':
+		v.Move(-len(v.visible))
+	case 'G':
+		v.End()
+	case 'd':
+		v.detail = !v.detail
+	case 's':
+		v.state = !v.state
+		v.detail = v.state || v.detail
+	case '/':
+		v.prompt, v.input = "/", nil
+	case 'f':
+		v.prompt, v.input = "filter: ", []rune(v.filter)
+	case 't':
+		v.prompt, v.input = "time (s): ", nil
+	case 'n', 'N':
+		if !v.Find(r == 'n') {
+			v.message = "not found: " + v.search
+		}
+	case 'm', 'M':
+		if !v.NextMarker(r == 'm') {
+			v.message = "no markers"
+		}
+	case 'F':
+		v.follow = !v.follow
+		if v.follow {
+			v.End()
+		}
+	case '?':
+		v.message = helpText
+	}
+	return false
+}
+
+// edit the input line, Enter applies it
+func (v *Viewer) handleInput(ev *tcell.EventKey) {
+	switch ev.Key() {
+	case tcell.KeyEscape:
+		v.prompt = ""
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if len(v.input) > 0 {
+			v.input = v.input[:len(v.input)-1]
+		}
+	case tcell.KeyRune:
+		v.input = append(v.input, ev.Rune())
+	case tcell.KeyEnter:
+		text := string(v.input)
+		switch v.prompt {
+		case "/":
+			v.search = text
+			if !v.Find(true) {
+				v.message = "not found: " + text
+			}
+		case "filter: ":
+			v.SetFilter(text)
+		default:
+			t, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				v.message = "invalid time: " + text
+			} else {
+				v.JumpTime(t)
+			}
+		}
+		v.prompt = ""
+	}
+}
+
+// write text at a line, cut at the screen width
+func drawLine(s tcell.Screen, y int, text string, style tcell.Style) {
+	width, _ := s.Size()
+	x := 0
+	for _, r := range text {
+		if x >= width {
+			break
+		}
+		s.SetContent(x, y, r, nil, style)
+		x++
+	}
+	for ; x < width; x++ {
+		s.SetContent(x, y, ' ', nil, style)
+	}
+}
+
+func cut(text string, size int) string {
+	if len(text) > size {
+		return text[:size]
+	}
+	return text
+}
+
+// line of an item in the list
+func (v *Viewer) line(item *Item) string {
+	rec := &item.Record
+	return fmt.Sprintf("%5d %.8f %*s %*s %s", rec.Index, rec.Time, -v.componentSize, cut(rec.Component, v.componentSize),
+		-v.propertySize, cut(rec.EventProperty, v.propertySize), rec.Value)
+}
+
+// lines of the detail pane of an item
+func detailLines(item *Item) []string {
+	rec := &item.Record
+	lines := []string{
+		fmt.Sprintf("Index %d  Time %.8f s  ID 0x%04X  Level %s  Thread %s", rec.Index, rec.Time, item.ID, rec.Level(), rec.Thread),
+		fmt.Sprintf("%s %s: %s", rec.Component, rec.EventProperty, rec.Value),
+		"Raw: " + rec.Raw(),
+	}
+	if item.Def == nil {
+		return append(lines, "SCVD: no event definition")
+	}
+	return append(lines,
+		fmt.Sprintf("SCVD: <event id=\"0x%04X\" level=\"%s\" property=\"%s\"", item.ID, item.Def.Level, item.Def.Property),
+		fmt.Sprintf("        value=\"%s\"", item.Def.Value),
+		fmt.Sprintf("        info=\"%s\"/>", item.Def.Info))
+}
+
+// Draw renders the viewer
+func (v *Viewer) Draw(s tcell.Screen) {
+	width, height := s.Size()
+	s.Clear()
+	reverse := tcell.StyleDefault.Reverse(true)
+	header := fmt.Sprintf(" %s  %d/%d events", v.title, len(v.visible), len(v.items))
+	if v.filter != "" {
+		header += "  filter: " + v.filter
+	}
+	if v.follow {
+		header += "  [follow]"
+	}
+	drawLine(s, 0, header, reverse)
+
+	rows := v.listHeight(height)
+	if v.cursor < v.top {
+		v.top = v.cursor
+	}
+	if v.cursor >= v.top+rows {
+		v.top = v.cursor - rows + 1
+	}
+	if v.top < 0 {
+		v.top = 0
+	}
+	for row := 0; row < rows && v.top+row < len(v.visible); row++ {
+		item := &v.items[v.visible[v.top+row]]
+		style := tcell.StyleDefault
+		switch item.Record.Level() {
+		case "Error":
+			style = style.Foreground(tcell.ColorRed)
+		case "Detail":
+			style = style.Dim(true)
+		}
+		if item.Record.Marker {
+			style = style.Bold(true).Underline(true)
+		}
+		if v.top+row == v.cursor {
+			style = style.Reverse(true)
+		}
+		drawLine(s, 1+row, v.line(item), style)
+	}
+
+	if v.detail {
+		y := 1 + rows
+		drawLine(s, y, strings.Repeat("-", width), tcell.StyleDefault)
+		if item := v.Current(); item != nil {
+			lines := detailLines(item)
+			if v.state {
+				st := v.State(item)
+				lines = st.Lines()
+			}
+			for i := 0; i < len(lines) && i < detailHeight-1; i++ {
+				drawLine(s, y+1+i, lines[i], tcell.StyleDefault)
+			}
+		}
+	}
+
+	status := v.message
+	switch {
+	case v.prompt != "":
+		status = v.prompt + string(v.input)
+		s.ShowCursor(len([]rune(status)), height-1)
+	case status == "":
+		status = "? help"
+		s.HideCursor()
+	default:
+		s.HideCursor()
+	}
+	drawLine(s, height-1, status, tcell.StyleDefault)
+	s.Show()
+}
+
+// decode the events in the background and announce them with interrupt events
+func (v *Viewer) decode(s tcell.Screen, in io.Reader, evdefs map[uint16]scvd.Event,
+	typedefs map[string]map[string]map[int16]string, memberTypes map[string]map[string]string) {
+	post := func() {
+		if !v.posted && s.PostEvent(tcell.NewEventInterrupt(nil)) == nil {
+			v.posted = true
+		}
+	}
+	err := output.Decode(in, evdefs, typedefs, memberTypes, func(rec *output.EventRecord, ev *event.Data) error {
+		item := Item{Record: *rec, ID: ev.Info.ID, Data: *ev}
+		if evdef, ok := evdefs[ev.Info.ID]; ok {
+			item.Def = &evdef
+		}
+		v.mu.Lock()
+		v.pending = append(v.pending, item)
+		post()
+		v.mu.Unlock()
+		return nil
+	})
+	v.mu.Lock()
+	v.done, v.err = true, err
+	v.posted = false
+	post()
+	v.mu.Unlock()
+}
+
+// take the decoded items
+func (v *Viewer) update() {
+	v.mu.Lock()
+	items := v.pending
+	v.pending = nil
+	v.posted = false
+	if v.done && v.err != nil {
+		v.message = v.err.Error()
+		v.err = nil
+	}
+	v.mu.Unlock()
+	v.Add(items...)
+}
+
+// Run shows the events read from in until the user quits, s must be initialized
+func (v *Viewer) Run(s tcell.Screen, in io.Reader, evdefs map[uint16]scvd.Event,
+	typedefs map[string]map[string]map[int16]string, memberTypes map[string]map[string]string) {
+	go v.decode(s, in, evdefs, typedefs, memberTypes)
+	for {
+		v.Draw(s)
+		switch ev := s.PollEvent().(type) {
+		case nil:
+			return // screen finalized
+		case *tcell.EventResize:
+			s.Sync()
+		case *tcell.EventKey:
+			_, height := s.Size()
+			if v.HandleKey(ev, height) {
+				return
+			}
+		case *tcell.EventInterrupt:
+			v.update()
+		}
+	}
+}
@@ -0,0 +1,365 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package view
+
+import (
+	"bytes"
+	"eventlist/pkg/event"
+	"eventlist/pkg/output"
+	"eventlist/pkg/xml/scvd"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// items with the times 0.0, 0.1, ... and the values "v0", "v1", ...
+func items(n int) []Item {
+	var list []Item
+	for i := 0; i < n; i++ {
+		component := "App"
+		if i%2 == 1 {
+			component = "Net"
+		}
+		list = append(list, Item{
+			Record: output.EventRecord{Index: i, Time: float64(i) / 10, Component: component,
+				EventProperty: "Ev", Value: "v" + string(rune('0'+i))},
+			ID: 0x1000 + uint16(i),
+		})
+	}
+	return list
+}
+
+func newViewer(n int) *Viewer {
+	v := New("test")
+	v.Add(items(n)...)
+	return v
+}
+
+func key(k tcell.Key) *tcell.EventKey {
+	return tcell.NewEventKey(k, 0, tcell.ModNone)
+}
+
+func runes(text string) []*tcell.EventKey {
+	var keys []*tcell.EventKey
+	for _, r := range text {
+		keys = append(keys, tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+	return keys
+}
+
+// screen text of a line without trailing blanks
+func screenLine(s tcell.SimulationScreen, y int) string {
+	cells, width, _ := s.GetContents()
+	var b strings.Builder
+	for x := 0; x < width; x++ {
+		b.Write(cells[y*width+x].Bytes)
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+func TestViewer_SetFilter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		filter  string
+		cursor  int
+		visible int
+		current int
+	}{
+		{"none", "", 3, 10, 3},
+		{"component", "net", 3, 5, 3},
+		{"keep nearest", "net", 4, 5, 5},
+		{"value", "V7", 0, 1, 7},
+		{"nothing", "xyz", 2, 0, -1},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			v := newViewer(10)
+			v.cursor = tt.cursor
+			v.SetFilter(tt.filter)
+			current := -1
+			if item := v.Current(); item != nil {
+				current = item.Record.Index
+			}
+			if len(v.visible) != tt.visible || current != tt.current {
+				t.Errorf("Viewer.SetFilter() %s = %d, %d, want %d, %d", tt.name, len(v.visible), current, tt.visible, tt.current)
+			}
+		})
+	}
+}
+
+func TestViewer_Find(t *testing.T) {
+	t.Parallel()
+
+	v := newViewer(10)
+	v.search = "net"
+	want := []int{1, 3, 5}
+	for _, w := range want {
+		if !v.Find(true) || v.Current().Record.Index != w {
+			t.Errorf("Viewer.Find() forward = %d, want %d", v.Current().Record.Index, w)
+		}
+	}
+	if !v.Find(false) || v.Current().Record.Index != 3 {
+		t.Errorf("Viewer.Find() backward = %d, want %d", v.Current().Record.Index, 3)
+	}
+	v.cursor = 9
+	if !v.Find(true) || v.Current().Record.Index != 1 {
+		t.Errorf("Viewer.Find() wrap = %d, want %d", v.Current().Record.Index, 1)
+	}
+	v.search = "xyz"
+	if v.Find(true) {
+		t.Errorf("Viewer.Find() xyz = true, want false")
+	}
+}
+
+func TestViewer_NextMarker(t *testing.T) {
+	t.Parallel()
+
+	v := newViewer(10)
+	if v.NextMarker(true) {
+		t.Errorf("Viewer.NextMarker() without markers = true, want false")
+	}
+	v.items[2].Record.Marker = true
+	v.items[6].Record.Marker = true
+	want := []int{2, 6, 2}
+	for _, w := range want {
+		if !v.NextMarker(true) || v.Current().Record.Index != w {
+			t.Errorf("Viewer.NextMarker() forward = %d, want %d", v.Current().Record.Index, w)
+		}
+	}
+	if !v.NextMarker(false) || v.Current().Record.Index != 6 {
+		t.Errorf("Viewer.NextMarker() backward = %d, want %d", v.Current().Record.Index, 6)
+	}
+	if quit := v.HandleKey(runes("M")[0], 5); quit || v.Current().Record.Index != 2 {
+		t.Errorf("Viewer.HandleKey() M = %d, want %d", v.Current().Record.Index, 2)
+	}
+}
+
+func TestViewer_JumpTime(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		time float64
+		want int
+	}{
+		{"begin", -1, 0},
+		{"exact", 0.3, 3},
+		{"between", 0.45, 5},
+		{"end", 5, 9},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			v := newViewer(10)
+			v.JumpTime(tt.time)
+			if got := v.Current().Record.Index; got != tt.want {
+				t.Errorf("Viewer.JumpTime() %s = %d, want %d", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestViewer_HandleKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		keys   []*tcell.EventKey
+		cursor int
+		quit   bool
+	}{
+		{"down", []*tcell.EventKey{key(tcell.KeyDown), key(tcell.KeyDown)}, 2, false},
+		{"up", append(runes("jjj"), key(tcell.KeyUp)), 2, false},
+		{"end", runes("G"), 9, false},
+		{"home", append(runes("G"), key(tcell.KeyHome)), 0, false},
+		{"page", []*tcell.EventKey{key(tcell.KeyPgDn)}, 3, false},
+		{"search", append(runes("/v5"), key(tcell.KeyEnter)), 5, false},
+		{"search next", append(append(runes("/net"), key(tcell.KeyEnter)), runes("nN")...), 1, false},
+		{"time", append(runes("t0.7"), key(tcell.KeyEnter)), 7, false},
+		{"time cancel", append(runes("t0.7"), key(tcell.KeyEscape)), 0, false},
+		{"backspace", append(runes("t0.79"), key(tcell.KeyBackspace2), key(tcell.KeyEnter)), 7, false},
+		{"filter", append(runes("fnet"), key(tcell.KeyEnter), key(tcell.KeyDown)), 1, false},
+		{"follow", runes("F"), 9, false},
+		{"quit", runes("q"), 0, true},
+		{"escape", []*tcell.EventKey{key(tcell.KeyEscape)}, 0, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			v := newViewer(10)
+			quit := false
+			for _, k := range tt.keys {
+				quit = v.HandleKey(k, 5)
+			}
+			if v.cursor != tt.cursor || quit != tt.quit {
+				t.Errorf("Viewer.HandleKey() %s = %d, %v, want %d, %v", tt.name, v.cursor, quit, tt.cursor, tt.quit)
+			}
+		})
+	}
+}
+
+func TestViewer_Draw(t *testing.T) {
+	t.Parallel()
+
+	s := tcell.NewSimulationScreen("")
+	if err := s.Init(); err != nil {
+		t.Fatalf("Screen.Init() error = %v", err)
+	}
+	defer s.Fini()
+	s.SetSize(60, 12)
+
+	v := newViewer(10)
+	v.items[2].Def = &scvd.Event{Level: "Op", Property: "Ev", Value: "v=%d[val1]", Info: "test"}
+	v.Move(2)
+	v.HandleKey(key(tcell.KeyEnter), 12)
+	v.follow = true
+	v.Draw(s)
+
+	want := []struct {
+		y    int
+		text string
+	}{
+		{0, " test  10/10 events  [follow]"},
+		{1, "    0 0.00000000 App       Ev    v0"},
+		{3, "    2 0.20000000 App       Ev    v2"},
+		{4, strings.Repeat("-", 60)},
+		{5, "Index 2  Time 0.20000000 s  ID 0x1002  Level   Thread"},
+		{6, "App Ev: v2"},
+		{7, "Raw:"},
+		{8, "SCVD: <event id=\"0x1002\" level=\"Op\" property=\"Ev\""},
+		{9, "        value=\"v=%d[val1]\""},
+		{10, "        info=\"test\"/>"},
+		{11, "? help"},
+	}
+	for _, w := range want {
+		if got := screenLine(s, w.y); got != w.text {
+			t.Errorf("Viewer.Draw() line %d = %q, want %q", w.y, got, w.text)
+		}
+	}
+	_, _, style, _ := s.GetContent(0, 3)
+	if _, _, attr := style.Decompose(); attr&tcell.AttrReverse == 0 {
+		t.Errorf("Viewer.Draw() selected line not reversed")
+	}
+
+	v.HandleKey(runes("/")[0], 12)
+	v.Draw(s)
+	if got := screenLine(s, 11); got != "/" {
+		t.Errorf("Viewer.Draw() prompt = %q, want %q", got, "/")
+	}
+}
+
+func TestViewer_State(t *testing.T) {
+	t.Parallel()
+
+	v := New("test")
+	list := items(4)
+	for i, id := range []uint16{0xEF00, 0xEF01, 0xEF20, 0x1003} {
+		list[i].Data = event.Data{Info: event.Info{ID: id}}
+		list[i].Def = &scvd.Event{Brief: list[i].Record.Component, Property: "Ev"}
+	}
+	v.Add(list...)
+	v.SetFilter("net")
+
+	tests := []struct {
+		name     string
+		pos      int
+		events   int
+		sessions []string
+	}{
+		{"first", 0, 2, []string{"A(0)", "A(1)"}},
+		{"last", 1, 4, []string{"A(1)"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			item := &v.items[v.visible[tt.pos]]
+			st := v.State(item)
+			var sessions []string
+			for _, session := range st.Sessions {
+				sessions = append(sessions, session.Name)
+			}
+			if st.Events != tt.events || strings.Join(sessions, ",") != strings.Join(tt.sessions, ",") {
+				t.Errorf("Viewer.State() %s = %d, %v, want %d, %v", tt.name, st.Events, sessions, tt.events, tt.sessions)
+			}
+		})
+	}
+
+	s := tcell.NewSimulationScreen("")
+	if err := s.Init(); err != nil {
+		t.Fatalf("Screen.Init() error = %v", err)
+	}
+	defer s.Fini()
+	s.SetSize(80, 12)
+	v.HandleKey(runes("s")[0], 12)
+	v.Draw(s)
+	want := "State at 0.10000000 s: 2 events, last at 0.10000000 s, thread unknown"
+	if got := screenLine(s, 5); got != want {
+		t.Errorf("Viewer.Draw() state = %q, want %q", got, want)
+	}
+	want = "Open A(0) Ev since 0.00000000 s: v0"
+	if got := screenLine(s, 6); got != want {
+		t.Errorf("Viewer.Draw() state = %q, want %q", got, want)
+	}
+}
+
+func TestViewer_Run(t *testing.T) {
+	t.Parallel()
+
+	s := tcell.NewSimulationScreen("")
+	if err := s.Init(); err != nil {
+		t.Fatalf("Screen.Init() error = %v", err)
+	}
+	defer s.Fini()
+	data, err := os.ReadFile("../../testdata/tree.binary")
+	if err != nil {
+		t.Fatalf("os.ReadFile() error = %v", err)
+	}
+
+	v := New("tree")
+	go func() {
+		for {
+			v.mu.Lock()
+			done := v.done
+			v.mu.Unlock()
+			if done {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+		s.InjectKey(tcell.KeyRune, 'q', tcell.ModNone)
+	}()
+	v.Run(s, bytes.NewReader(data), nil, nil, nil)
+	if len(v.items) != 8 || v.items[7].ID != 0xEF40 {
+		t.Errorf("Viewer.Run() items = %d, want %d", len(v.items), 8)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package watch detects the changes of input files, e.g. to decode a
+// capture again when its SCVD files are edited.
+package watch
+
+import (
+	"os"
+	"time"
+)
+
+// PollInterval is the time between the checks of the files
+var PollInterval = 500 * time.Millisecond
+
+// stamp is the state of a file, zero if it does not exist
+type stamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func stat(name string) stamp {
+	info, err := os.Stat(name)
+	if err != nil {
+		return stamp{}
+	}
+	return stamp{info.ModTime(), info.Size()}
+}
+
+// Watcher detects the changes of the modification time or the size of files
+type Watcher struct {
+	files  []string
+	stamps map[string]stamp
+}
+
+// New watches the files, the changes after New are detected
+func New(files []string) *Watcher {
+	w := &Watcher{files: files, stamps: make(map[string]stamp, len(files))}
+	for _, name := range files {
+		w.stamps[name] = stat(name)
+	}
+	return w
+}
+
+// Changed returns the files changed since the last call
+func (w *Watcher) Changed() []string {
+	var changed []string
+	for _, name := range w.files {
+		st := stat(name)
+		if st != w.stamps[name] {
+			w.stamps[name] = st
+			changed = append(changed, name)
+		}
+	}
+	return changed
+}
+
+// Wait waits for changed files, false if done is closed before. The files
+// are returned after they did not change for a poll interval, so an editor
+// has finished saving them.
+func (w *Watcher) Wait(done <-chan struct{}) ([]string, bool) {
+	var changed []string
+	for {
+		select {
+		case <-done:
+			return nil, false
+		case <-time.After(PollInterval):
+		}
+		more := w.Changed()
+		if len(more) == 0 && len(changed) != 0 {
+			return changed, true
+		}
+		for _, name := range more {
+			if !contains(changed, name) {
+				changed = append(changed, name)
+			}
+		}
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWatcher(t *testing.T) { //nolint:golint,paralleltest
+	saved := PollInterval
+	PollInterval = 10 * time.Millisecond
+	defer func() { PollInterval = saved }()
+
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a.scvd"), filepath.Join(dir, "b.scvd")
+	if err := os.WriteFile(a, []byte("a"), 0o600); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	w := New([]string{a, b})
+	if got := w.Changed(); len(got) != 0 {
+		t.Errorf("Watcher.Changed() = %v, want none", got)
+	}
+	if err := os.WriteFile(a, []byte("aa"), 0o600); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	if err := os.WriteFile(b, []byte("b"), 0o600); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	done := make(chan struct{})
+	if got, ok := w.Wait(done); !ok || !reflect.DeepEqual(got, []string{a, b}) {
+		t.Errorf("Watcher.Wait() = %v, %v, want %v", got, ok, []string{a, b})
+	}
+	if err := os.Remove(b); err != nil {
+		t.Fatalf("os.Remove() error = %v", err)
+	}
+	if got := w.Changed(); !reflect.DeepEqual(got, []string{b}) {
+		t.Errorf("Watcher.Changed() = %v, want %v", got, []string{b})
+	}
+	close(done)
+	if got, ok := w.Wait(done); ok || got != nil {
+		t.Errorf("Watcher.Wait() = %v, %v, want false", got, ok)
+	}
+}
@@ -0,0 +1,176 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package where
+
+import (
+	"errors"
+	"eventlist/pkg/bus"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+var errCondition = errors.New("invalid --where condition")
+
+// condition compares one field of an event with a pattern
+type condition struct {
+	key    string
+	not    bool
+	value  string // path.Match pattern of component, property, level and value
+	lo, hi uint16 // range of the event ID
+}
+
+// Filter selects events by their component, property, level, value or ID.
+// A filter expression is a comma separated list of conditions "key=pattern"
+// or "key!=pattern" that all must match, e.g. "component=RTX*,level!=Detail".
+// The patterns use the syntax of path.Match, the ID is a number or a range,
+// e.g. "id=0xEF00-0xEFFF". An event passes a filter of several expressions
+// if one of the expressions matches.
+type Filter struct {
+	exprs [][]condition
+}
+
+// Parse creates a filter of one or more expressions
+func Parse(exprs []string) (*Filter, error) {
+	f := &Filter{}
+	for _, expr := range exprs {
+		var conds []condition
+		for _, item := range strings.Split(expr, ",") {
+			c, err := parseCondition(strings.TrimSpace(item))
+			if err != nil {
+				return nil, err
+			}
+			conds = append(conds, c)
+		}
+		f.exprs = append(f.exprs, conds)
+	}
+	return f, nil
+}
+
+func parseCondition(s string) (condition, error) {
+	var c condition
+	i := strings.IndexByte(s, '=')
+	if i <= 0 {
+		return c, fmt.Errorf("%w: %s", errCondition, s)
+	}
+	c.key, c.value = strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+1:])
+	if strings.HasSuffix(c.key, "!") {
+		c.key, c.not = strings.TrimSpace(c.key[:len(c.key)-1]), true
+	}
+	switch c.key {
+	case "component", "property", "level", "value":
+		if _, err := path.Match(c.value, ""); err != nil {
+			return c, fmt.Errorf("%w: %s: %v", errCondition, s, err)
+		}
+	case "id":
+		lo, hi, ok := strings.Cut(c.value, "-")
+		if !ok {
+			hi = lo
+		}
+		l, errLo := strconv.ParseUint(strings.TrimSpace(lo), 0, 16)
+		h, errHi := strconv.ParseUint(strings.TrimSpace(hi), 0, 16)
+		if errLo != nil || errHi != nil || l > h {
+			return c, fmt.Errorf("%w: %s", errCondition, s)
+		}
+		c.lo, c.hi = uint16(l), uint16(h)
+	default:
+		return c, fmt.Errorf("%w: %s: unknown key %s", errCondition, s, c.key)
+	}
+	return c, nil
+}
+
+func (c *condition) match(ev *bus.Event) bool {
+	var ok bool
+	switch c.key {
+	case "component":
+		ok, _ = path.Match(c.value, ev.Component())
+	case "property":
+		ok, _ = path.Match(c.value, ev.Property())
+	case "level":
+		ok, _ = path.Match(c.value, ev.Level())
+	case "value":
+		value, _ := ev.Value()
+		ok, _ = path.Match(c.value, value)
+	case "id":
+		ok = ev.Data.Info.ID >= c.lo && ev.Data.Info.ID <= c.hi
+	}
+	return ok != c.not
+}
+
+// Match returns true if the event passes the filter, a nil filter passes all events
+func (f *Filter) Match(ev *bus.Event) bool {
+	if f == nil || len(f.exprs) == 0 {
+		return true
+	}
+	for _, conds := range f.exprs {
+		all := true
+		for i := range conds {
+			if !conds[i].match(ev) {
+				all = false
+				break
+			}
+		}
+		if all {
+			return true
+		}
+	}
+	return false
+}
+
+// Component returns false if no event of the component number can pass the
+// filter, names are the possible component names of the number. Conditions
+// other than component and id are assumed to match.
+func (f *Filter) Component(no uint8, names []string) bool {
+	if f == nil || len(f.exprs) == 0 {
+		return true
+	}
+	for _, conds := range f.exprs {
+		all := true
+		for i := range conds {
+			if !conds[i].component(no, names) {
+				all = false
+				break
+			}
+		}
+		if all {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *condition) component(no uint8, names []string) bool {
+	lo, hi := uint16(no)<<8, uint16(no)<<8|0xFF
+	switch c.key {
+	case "component":
+		for _, name := range names {
+			if ok, _ := path.Match(c.value, name); ok != c.not {
+				return true
+			}
+		}
+		return false
+	case "id":
+		if c.not {
+			return c.lo > lo || c.hi < hi // some ID of the component is outside the range
+		}
+		return c.lo <= hi && c.hi >= lo
+	}
+	return true
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package where
+
+import (
+	"errors"
+	"eventlist/pkg/bus"
+	"eventlist/pkg/event"
+	"eventlist/pkg/xml/scvd"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		exprs   []string
+		wantErr error
+	}{
+		{"ok", []string{"component=RTX*, level!=Detail", "id=0xEF00-0xEFFF"}, nil},
+		{"id", []string{"id=10"}, nil},
+		{"none", nil, nil},
+		{"no =", []string{"component"}, errCondition},
+		{"no key", []string{"=RTX"}, errCondition},
+		{"key", []string{"thread=main"}, errCondition},
+		{"pattern", []string{"component=[RTX"}, errCondition},
+		{"id range", []string{"id=0xEFFF-0xEF00"}, errCondition},
+		{"id number", []string{"id=0x10000"}, errCondition},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := Parse(tt.exprs); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parse() %s error = %v, want %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFilter_Match(t *testing.T) {
+	t.Parallel()
+
+	def := &scvd.Event{Brief: "RTX", Property: "ThreadSwitch", Level: "Op"}
+	rtx := bus.NewEvent(0, 0, &event.Data{Info: event.Info{ID: 0xF213}}, def,
+		func() (string, error) { return "thread=main", nil })
+	unknown := bus.NewEvent(1, 0, &event.Data{Info: event.Info{ID: 0xEF01}}, nil, nil)
+
+	tests := []struct {
+		name        string
+		exprs       []string
+		wantRTX     bool
+		wantUnknown bool
+	}{
+		{"none", nil, true, true},
+		{"component", []string{"component=RTX"}, true, false},
+		{"component not", []string{"component!=RTX"}, false, true},
+		{"property", []string{"property=Thread*"}, true, false},
+		{"level", []string{"level=Op"}, true, false},
+		{"level empty", []string{"level="}, false, true},
+		{"value", []string{"value=thread=*"}, true, false},
+		{"id", []string{"id=0xEF00-0xEFFF"}, false, true},
+		{"id single", []string{"id=0xF213"}, true, false},
+		{"and", []string{"component=RTX,level=Error"}, false, false},
+		{"or", []string{"component=RTX,level=Error", "id=0xEF01"}, false, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := Parse(tt.exprs)
+			if err != nil {
+				t.Fatalf("Parse() %s error = %v", tt.name, err)
+			}
+			if got := f.Match(rtx); got != tt.wantRTX {
+				t.Errorf("Filter.Match() %s RTX = %v, want %v", tt.name, got, tt.wantRTX)
+			}
+			if got := f.Match(unknown); got != tt.wantUnknown {
+				t.Errorf("Filter.Match() %s unknown = %v, want %v", tt.name, got, tt.wantUnknown)
+			}
+		})
+	}
+
+	var f *Filter
+	if !f.Match(rtx) {
+		t.Errorf("Filter.Match() nil = false, want true")
+	}
+}
+
+func TestFilter_Component(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		exprs   []string
+		wantRTX bool // component 0xF2 named RTX
+		wantEF  bool // component 0xEF without name
+	}{
+		{"none", nil, true, true},
+		{"component", []string{"component=RTX*"}, true, false},
+		{"component not", []string{"component!=RTX*"}, false, true},
+		{"component unnamed", []string{"component=0xEF"}, false, true},
+		{"property", []string{"property=Thread*"}, true, true},
+		{"id", []string{"id=0xEF00-0xEF0F"}, false, true},
+		{"id not part", []string{"id!=0xEF00-0xEF0F"}, true, true},
+		{"id not all", []string{"id!=0xEF00-0xEFFF"}, true, false},
+		{"and", []string{"component=RTX,id=0xEF01"}, false, false},
+		{"or", []string{"component=RTX*", "id=0xEF01"}, true, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := Parse(tt.exprs)
+			if err != nil {
+				t.Fatalf("Parse() %s error = %v", tt.name, err)
+			}
+			if got := f.Component(0xF2, []string{"RTX Thread"}); got != tt.wantRTX {
+				t.Errorf("Filter.Component() %s RTX = %v, want %v", tt.name, got, tt.wantRTX)
+			}
+			if got := f.Component(0xEF, []string{"0xEF"}); got != tt.wantEF {
+				t.Errorf("Filter.Component() %s 0xEF = %v, want %v", tt.name, got, tt.wantEF)
+			}
+		})
+	}
+}
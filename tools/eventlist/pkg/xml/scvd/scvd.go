@@ -19,12 +19,14 @@
 package scvd
 
 import (
+	"bytes"
 	"encoding/xml"
 	"errors"
 	"eventlist/pkg/eval"
+	"io"
+	"io/fs"
 	"os"
 	"strconv"
-	"strings"
 )
 
 type Value string
@@ -84,6 +86,7 @@ type Event struct {
 	HName    string `xml:"hname,attr"`
 	Value    Value  `xml:"value,attr"`
 	Info     string `xml:"info,attr"`
+	Marker   bool   `xml:"marker,attr"`
 	Brief    string
 }
 
@@ -112,13 +115,50 @@ type ComponentViewer struct {
 	Events    Events    `xml:"events"`
 }
 
+// MaxSize is the maximum size of an SCVD file, a larger file is rejected
+// instead of being read into memory
+const MaxSize = 16 << 20
+
+// ErrSize is returned for an SCVD file larger than MaxSize
+var ErrSize = errors.New("SCVD file too large")
+
+func (viewer *ComponentViewer) read(r io.Reader) error {
+	data, err := io.ReadAll(io.LimitReader(r, MaxSize+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > MaxSize {
+		return ErrSize
+	}
+	return xml.NewDecoder(bytes.NewReader(data)).Decode(viewer)
+}
+
+// FS opens the SCVD files if set, e.g. the files given to the WebAssembly
+// build by a browser, nil opens the files of the operating system
+var FS fs.FS
+
 func (viewer *ComponentViewer) getFromFile(name *string) error {
-	data, err := os.ReadFile(*name)
-	if err == nil {
-		d := xml.NewDecoder(strings.NewReader(string(data)))
-		err = d.Decode(&viewer)
+	var file io.ReadCloser
+	var err error
+	if FS != nil {
+		file, err = FS.Open(*name)
+	} else {
+		file, err = os.Open(*name)
+	}
+	if err != nil {
+		return err
 	}
-	return err
+	defer file.Close()
+	return viewer.read(file)
+}
+
+// Load reads an SCVD file
+func Load(name string) (*ComponentViewer, error) {
+	var viewer ComponentViewer
+	if err := viewer.getFromFile(&name); err != nil {
+		return nil, err
+	}
+	return &viewer, nil
 }
 
 // get the enum value with calculation
@@ -139,66 +179,91 @@ func (id *ID) getIdValue() (uint16, error) { //nolint:golint,revive
 	return uint16(n.GetInt()), nil
 }
 
+// Value returns the value of the ID expression, e.g. 0xFE00+0x01
+func (id ID) Value() (uint16, error) {
+	return id.getIdValue()
+}
+
 func getOne(filename *string, events map[uint16]Event,
-	typedefs map[string]map[string]map[int16]string) error {
+	typedefs map[string]map[string]map[int16]string, memberTypes map[string]map[string]string) error {
 	var viewer ComponentViewer
-	var err error
-	if err = viewer.getFromFile(filename); err == nil {
-		// create a components map indexed by "no" to speed up things
-		components := make(map[uint8]*GroupComponent)
-		for _, component := range viewer.Events.Group.Component {
-			var no uint64
-			no, err = strconv.ParseUint(component.No, 0, 8)
-			if err != nil {
-				break
-			}
-			components[uint8(no)] = &component
-		}
+	if err := viewer.getFromFile(filename); err != nil {
+		return err
+	}
+	return viewer.add(events, typedefs, memberTypes)
+}
+
+// Parse reads an SCVD file from r and adds its events, typedefs and
+// member types to the maps
+func Parse(r io.Reader, events map[uint16]Event,
+	typedefs map[string]map[string]map[int16]string, memberTypes map[string]map[string]string) error {
+	var viewer ComponentViewer
+	if err := viewer.read(r); err != nil {
+		return err
+	}
+	return viewer.add(events, typedefs, memberTypes)
+}
+
+// add the events, the enums of the typedefs and the member types to the maps
+func (viewer *ComponentViewer) add(events map[uint16]Event,
+	typedefs map[string]map[string]map[int16]string, memberTypes map[string]map[string]string) error {
+	// create a components map indexed by "no" to speed up things
+	components := make(map[uint8]*GroupComponent)
+	for i := range viewer.Events.Group.Component {
+		component := &viewer.Events.Group.Component[i]
+		no, err := strconv.ParseUint(component.No, 0, 8)
 		if err != nil {
 			return err // cannot decode component number
 		}
-		for _, event := range viewer.Events.Events {
-			id, err := event.ID.getIdValue()
-			if err != nil {
-				return err // cannot decode IdValue
-			}
-			if components[uint8(id>>8)] != nil {
-				event.Brief = components[uint8(id>>8)].Brief
-			}
-			events[id] = event
+		components[uint8(no)] = component
+	}
+	for _, event := range viewer.Events.Events {
+		id, err := event.ID.getIdValue()
+		if err != nil {
+			return err // cannot decode IdValue
 		}
-		// extract enums from typedefs
-		for _, typedef := range viewer.Typedefs.Typedef {
-			if len(typedef.Members) > 0 {
-				members := make(map[string]map[int16]string)
-				for _, member := range typedef.Members {
-					if len(member.Enums) > 0 {
-						enums := make(map[int16]string)
-						for _, enum := range member.Enums {
-							var en int16
-							if en, err = enum.getInfo(); err != nil {
-								return err
-							}
-							enums[en] = enum.Name
+		if components[uint8(id>>8)] != nil {
+			event.Brief = components[uint8(id>>8)].Brief
+		}
+		events[id] = event
+	}
+	// extract enums from typedefs
+	for _, typedef := range viewer.Typedefs.Typedef {
+		if len(typedef.Members) > 0 {
+			members := make(map[string]map[int16]string)
+			types := memberTypes[typedef.Name]
+			if types == nil {
+				types = make(map[string]string)
+				memberTypes[typedef.Name] = types
+			}
+			for _, member := range typedef.Members {
+				types[member.Name] = member.Type
+				if len(member.Enums) > 0 {
+					enums := make(map[int16]string)
+					for _, enum := range member.Enums {
+						en, err := enum.getInfo()
+						if err != nil {
+							return err
 						}
-						members[member.Name] = enums
+						enums[en] = enum.Name
 					}
-				}
-				if len(members) > 0 {
-					typedefs[typedef.Name] = members
+					members[member.Name] = enums
 				}
 			}
+			if len(members) > 0 {
+				typedefs[typedef.Name] = members
+			}
 		}
 	}
-	return err
+	return nil
 }
 
-// returns the events and typedef map
+// returns the events, typedef and member type maps
 func Get(scvdFiles *[]string, events map[uint16]Event,
-	typedefs map[string]map[string]map[int16]string) error {
+	typedefs map[string]map[string]map[int16]string, memberTypes map[string]map[string]string) error {
 	if scvdFiles != nil {
 		for _, scvdFile := range *scvdFiles {
-			if err := getOne(&scvdFile, events, typedefs); err != nil {
+			if err := getOne(&scvdFile, events, typedefs, memberTypes); err != nil {
 				return err
 			}
 		}
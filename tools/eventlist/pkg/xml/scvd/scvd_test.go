@@ -20,7 +20,10 @@
 package scvd
 
 import (
+	"os"
+	"strings"
 	"testing"
+	"testing/fstest"
 )
 
 func TestComponentViewer_getFromFile(t *testing.T) {
@@ -127,11 +130,13 @@ func Test_getOne(t *testing.T) {
 	var nameErr3 = "../../../testdata/test_err3.xml"
 	var evs = make(map[uint16]Event)
 	var tds = make(map[string]map[string]map[int16]string)
+	var mts = make(map[string]map[string]string)
 
 	type args struct {
-		filename *string
-		events   map[uint16]Event
-		typedefs map[string]map[string]map[int16]string
+		filename    *string
+		events      map[uint16]Event
+		typedefs    map[string]map[string]map[int16]string
+		memberTypes map[string]map[string]string
 	}
 	tests := []struct {
 		name    string
@@ -144,15 +149,15 @@ func Test_getOne(t *testing.T) {
 		tdWant  string
 		wantErr bool
 	}{
-		{"getOne", args{&name, evs, tds}, 0xEF00, "File=fff", "attr", "member", 1, "ready", false},
-		{"getOne err", args{&wrongName, evs, tds}, 0, "", "", "", 0, "", true},
-		{"getOne err1", args{&nameErr1, evs, tds}, 0, "", "", "", 0, "", true},
-		{"getOne err2", args{&nameErr2, evs, tds}, 0, "", "", "", 0, "", true},
-		{"getOne err3", args{&nameErr3, evs, tds}, 0, "", "", "", 0, "", true},
+		{"getOne", args{&name, evs, tds, mts}, 0xEF00, "File=fff", "attr", "member", 1, "ready", false},
+		{"getOne err", args{&wrongName, evs, tds, mts}, 0, "", "", "", 0, "", true},
+		{"getOne err1", args{&nameErr1, evs, tds, mts}, 0, "", "", "", 0, "", true},
+		{"getOne err2", args{&nameErr2, evs, tds, mts}, 0, "", "", "", 0, "", true},
+		{"getOne err3", args{&nameErr3, evs, tds, mts}, 0, "", "", "", 0, "", true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := getOne(tt.args.filename, tt.args.events, tt.args.typedefs); (err != nil) != tt.wantErr {
+			if err := getOne(tt.args.filename, tt.args.events, tt.args.typedefs, tt.args.memberTypes); (err != nil) != tt.wantErr {
 				t.Errorf("getOne() error = %v, wantErr %v", err, tt.wantErr)
 			}
 			if string(evs[tt.ev].Value) != tt.evWant {
@@ -165,30 +170,173 @@ func Test_getOne(t *testing.T) {
 	}
 }
 
+func TestParse(t *testing.T) {
+	const two = `<component_viewer><events><group>
+<component name="A" brief="CompA" no="0x01"/><component name="B" brief="CompB" no="0x02"/>
+</group><event id="0x0101" property="a"/><event id="0x0201" property="b"/></events></component_viewer>`
+
+	tests := []struct {
+		name    string
+		in      string
+		id      uint16
+		want    string
+		wantErr bool
+	}{
+		{"first component", two, 0x0101, "CompA", false},
+		{"second component", two, 0x0201, "CompB", false},
+		{"syntax", "<component_viewer>", 0, "", true},
+		{"component no", `<component_viewer><events><group><component no="0x100"/></group></events></component_viewer>`, 0, "", true},
+		{"too large", strings.Repeat(" ", MaxSize+1), 0, "", true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			evs := make(map[uint16]Event)
+			tds := make(map[string]map[string]map[int16]string)
+			mts := make(map[string]map[string]string)
+			if err := Parse(strings.NewReader(tt.in), evs, tds, mts); (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if evs[tt.id].Brief != tt.want {
+				t.Errorf("Parse() %s = %v, want %v", tt.name, evs[tt.id].Brief, tt.want)
+			}
+		})
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	for _, name := range []string{"test.xml", "rtx.xml", "test_err1.xml", "test_err2.xml", "test_err3.xml"} {
+		data, err := os.ReadFile("../../../testdata/" + name)
+		if err != nil {
+			f.Fatalf("os.ReadFile() error = %v", err)
+		}
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		evs := make(map[uint16]Event)
+		tds := make(map[string]map[string]map[int16]string)
+		mts := make(map[string]map[string]string)
+		_ = Parse(strings.NewReader(string(data)), evs, tds, mts)
+	})
+}
+
 func TestGet(t *testing.T) {
 	var files = []string{"../../../testdata/test.xml"}
 	var files1 = []string{"../../../testdata/xxxxx"}
 	var evs = make(map[uint16]Event)
 	var tds = make(map[string]map[string]map[int16]string)
+	var mts = make(map[string]map[string]string)
 
 	type args struct {
-		scvdFiles *[]string
-		events    map[uint16]Event
-		typedefs  map[string]map[string]map[int16]string
+		scvdFiles   *[]string
+		events      map[uint16]Event
+		typedefs    map[string]map[string]map[int16]string
+		memberTypes map[string]map[string]string
 	}
 	tests := []struct {
 		name    string
 		args    args
 		wantErr bool
 	}{
-		{"Get", args{&files, evs, tds}, false},
-		{"Get err", args{&files1, evs, tds}, true},
+		{"Get", args{&files, evs, tds, mts}, false},
+		{"Get err", args{&files1, evs, tds, mts}, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := Get(tt.args.scvdFiles, tt.args.events, tt.args.typedefs); (err != nil) != tt.wantErr {
+			if err := Get(tt.args.scvdFiles, tt.args.events, tt.args.typedefs, tt.args.memberTypes); (err != nil) != tt.wantErr {
 				t.Errorf("Get() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
+
+func TestGet_FS(t *testing.T) {
+	data, err := os.ReadFile("../../../testdata/test.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	FS = fstest.MapFS{"scvd/test.xml": &fstest.MapFile{Data: data}}
+	defer func() { FS = nil }()
+
+	evs := make(map[uint16]Event)
+	tds := make(map[string]map[string]map[int16]string)
+	mts := make(map[string]map[string]string)
+	files := []string{"scvd/test.xml"}
+	if err := Get(&files, evs, tds, mts); err != nil || len(evs) == 0 {
+		t.Errorf("Get() of FS = %d events, %v, want events", len(evs), err)
+	}
+	files = []string{"../../../testdata/test.xml"}
+	if err := Get(&files, evs, tds, mts); err == nil {
+		t.Errorf("Get() of FS error = nil, want error of a file outside FS")
+	}
+}
+
+func TestGet_memberTypes(t *testing.T) {
+	var files = []string{"../../../testdata/test.xml"}
+	var evs = make(map[uint16]Event)
+	var tds = make(map[string]map[string]map[int16]string)
+	var mts = make(map[string]map[string]string)
+
+	if err := Get(&files, evs, tds, mts); err != nil {
+		t.Errorf("Get() cannot read %s", files[0])
+		return
+	}
+	tests := []struct {
+		name    string
+		typedef string
+		member  string
+		want    string
+		wantOk  bool
+	}{
+		{"uint32_t", "attr", "member", "uint32_t", true},
+		{"float", "sensor", "temp", "float", true},
+		{"double", "sensor", "value", "double", true},
+		{"no member", "sensor", "xxx", "", false},
+		{"no typedef", "xxx", "temp", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := mts[tt.typedef][tt.member]
+			if ok != tt.wantOk {
+				t.Errorf("Get() %s ok = %v, want %v", tt.name, ok, tt.wantOk)
+			}
+			if got != tt.want {
+				t.Errorf("Get() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+	if _, ok := tds["sensor"]; ok {
+		t.Errorf("Get() typedefs = %v, want enums only", tds)
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	data, err := os.ReadFile("../../../testdata/rtx.xml")
+	if err != nil {
+		b.Fatalf("os.ReadFile() error = %v", err)
+	}
+	text := string(data)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		evs := make(map[uint16]Event)
+		tds := make(map[string]map[string]map[int16]string)
+		mts := make(map[string]map[string]string)
+		if err := Parse(strings.NewReader(text), evs, tds, mts); err != nil {
+			b.Fatalf("Parse() error = %v", err)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	viewer, err := Load("../../../testdata/test.xml")
+	if err != nil || viewer.Component.Name != "EventRecorderStub" || len(viewer.Events.Events) != 2 {
+		t.Errorf("Load() = %+v, %v, want EventRecorderStub", viewer, err)
+	}
+	if id, err := viewer.Events.Events[1].ID.Value(); err != nil || id != 0xFE00 {
+		t.Errorf("ID.Value() = 0x%04X, %v, want 0xFE00", id, err)
+	}
+	if _, err := Load("../../../testdata/xxxxx"); err == nil {
+		t.Errorf("Load() error = nil, want error")
+	}
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scvd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var errVersion = errors.New("invalid component version")
+
+// CompareVersions compares two dotted versions part by part, numeric parts
+// as numbers, missing parts are 0, returns -1, 0 or 1
+func CompareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		x, y := "0", "0"
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		nx, errx := strconv.ParseUint(x, 10, 64)
+		ny, erry := strconv.ParseUint(y, 10, 64)
+		switch {
+		case errx == nil && erry == nil && nx != ny:
+			if nx < ny {
+				return -1
+			}
+			return 1
+		case (errx != nil || erry != nil) && x != y:
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// ParseVersions reads a comma separated list of component versions, e.g.
+// "RTX5=5.9.0,MyNet=1.2"
+func ParseVersions(list string) (map[string]string, error) {
+	versions := make(map[string]string)
+	for _, item := range strings.Split(list, ",") {
+		name, version, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok || len(name) == 0 || len(version) == 0 {
+			return nil, fmt.Errorf("%w: %s", errVersion, item)
+		}
+		versions[name] = version
+	}
+	return versions, nil
+}
+
+// Select picks the SCVD files of one version of each component: the
+// version given for the name or short name of the component, else the
+// latest one. Files of other components and files that cannot be read stay
+// in the list. Returns the selected files and a warning for each component
+// without the given version.
+func Select(files []string, versions map[string]string) ([]string, []string) {
+	components := make([]Component, len(files))
+	byName := make(map[string][]int)
+	var names []string
+	for i := range files {
+		var viewer ComponentViewer
+		if err := viewer.getFromFile(&files[i]); err != nil || len(viewer.Component.Name) == 0 {
+			continue
+		}
+		components[i] = viewer.Component
+		name := viewer.Component.Name
+		if _, ok := byName[name]; !ok {
+			names = append(names, name)
+		}
+		byName[name] = append(byName[name], i)
+	}
+
+	use := make(map[string]string) // selected version of the components
+	var warnings []string
+	for _, name := range names {
+		latest := ""
+		for _, i := range byName[name] {
+			if v := components[i].Version; latest == "" || CompareVersions(v, latest) > 0 {
+				latest = v
+			}
+		}
+		want, ok := versions[name]
+		if !ok {
+			want, ok = versions[components[byName[name][0]].Shortname]
+		}
+		if !ok {
+			use[name] = latest
+			continue
+		}
+		// the given version, else the latest version before it, else the first one after it
+		best := ""
+		for _, i := range byName[name] {
+			v := components[i].Version
+			switch {
+			case best == "":
+				best = v
+			case CompareVersions(v, want) <= 0 && (CompareVersions(best, want) > 0 || CompareVersions(v, best) > 0):
+				best = v
+			case CompareVersions(v, want) > 0 && CompareVersions(best, want) > 0 && CompareVersions(v, best) < 0:
+				best = v
+			}
+		}
+		if CompareVersions(best, want) != 0 {
+			warnings = append(warnings, fmt.Sprintf("no SCVD file of %s %s, using version %s", name, want, best))
+		}
+		use[name] = best
+	}
+
+	selected := make([]string, 0, len(files))
+	for i, file := range files {
+		if name := components[i].Name; len(name) != 0 && CompareVersions(components[i].Version, use[name]) != 0 {
+			continue
+		}
+		selected = append(selected, file)
+	}
+	return selected, warnings
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scvd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"5.9.0", "5.9.0", 0},
+		{"5.9.0", "5.10.0", -1},
+		{"5.10", "5.9.1", 1},
+		{"1.0", "1.0.0", 0},
+		{"1.0.1", "1.0", 1},
+		{"1.0.0-rc1", "1.0.0-rc2", -1},
+	}
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions() %s %s = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestParseVersions(t *testing.T) {
+	t.Parallel()
+
+	got, err := ParseVersions("RTX5=5.9.0, MyNet=1.2")
+	if want := map[string]string{"RTX5": "5.9.0", "MyNet": "1.2"}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseVersions() = %v, %v, want %v", got, err, want)
+	}
+	for _, list := range []string{"RTX5", "RTX5=", "=1.0", "RTX5=1,"} {
+		if _, err := ParseVersions(list); !errors.Is(err, errVersion) {
+			t.Errorf("ParseVersions() %s error = %v, want %v", list, err, errVersion)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	file := func(name, component, short, version string) string {
+		path := filepath.Join(dir, name)
+		_ = os.WriteFile(path, []byte(`<component_viewer><component name="`+component+
+			`" shortname="`+short+`" version="`+version+`"/></component_viewer>`), 0600)
+		return path
+	}
+	rtx58 := file("rtx58.scvd", "RTX5", "RTX", "5.8.0")
+	rtx510 := file("rtx510.scvd", "RTX5", "RTX", "5.10.0")
+	rtx59 := file("rtx59.scvd", "RTX5", "RTX", "5.9.0")
+	rtx59b := file("rtx59b.scvd", "RTX5", "RTX", "5.9.0")
+	net := file("net.scvd", "MyNet", "", "1.0.0")
+	nix := filepath.Join(dir, "nix.scvd")
+	files := []string{rtx58, net, rtx510, rtx59, nix, rtx59b}
+
+	tests := []struct {
+		name         string
+		versions     map[string]string
+		want         []string
+		wantWarnings []string
+	}{
+		{"latest", nil, []string{net, rtx510, nix}, nil},
+		{"exact", map[string]string{"RTX5": "5.9.0"}, []string{net, rtx59, nix, rtx59b}, nil},
+		{"shortname", map[string]string{"RTX": "5.8"}, []string{rtx58, net, nix}, nil},
+		{"before", map[string]string{"RTX5": "5.9.2"}, []string{net, rtx59, nix, rtx59b},
+			[]string{"no SCVD file of RTX5 5.9.2, using version 5.9.0"}},
+		{"after", map[string]string{"RTX5": "5.1.0", "MyNet": "2.0"}, []string{rtx58, net, nix},
+			[]string{"no SCVD file of RTX5 5.1.0, using version 5.8.0", "no SCVD file of MyNet 2.0, using version 1.0.0"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, warnings := Select(files, tt.versions)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Select() %s = %v, want %v", tt.name, got, tt.want)
+			}
+			if !reflect.DeepEqual(warnings, tt.wantWarnings) {
+				t.Errorf("Select() %s warnings = %v, want %v", tt.name, warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
@@ -0,0 +1,446 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package svd reads the peripherals, registers and bit fields of a
+// CMSIS-SVD file and decodes register dumps recorded as events into
+// peripheral.register.field = value.
+package svd
+
+import (
+	"encoding/xml"
+	"errors"
+	"eventlist/pkg/event"
+	"eventlist/pkg/plugin"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	errNoPeripherals = errors.New("no peripherals in SVD file")
+	errNumber        = errors.New("invalid number")
+	errDerived       = errors.New("unknown peripheral derived from")
+	errShort         = errors.New("register dump too short")
+)
+
+type xmlEnum struct {
+	Name      string `xml:"name"`
+	Value     string `xml:"value"`
+	IsDefault string `xml:"isDefault"`
+}
+
+type xmlField struct {
+	Name      string    `xml:"name"`
+	BitOffset string    `xml:"bitOffset"`
+	BitWidth  string    `xml:"bitWidth"`
+	Lsb       string    `xml:"lsb"`
+	Msb       string    `xml:"msb"`
+	BitRange  string    `xml:"bitRange"`
+	Enums     []xmlEnum `xml:"enumeratedValues>enumeratedValue"`
+}
+
+type xmlDim struct {
+	Dim          string `xml:"dim"`
+	DimIncrement string `xml:"dimIncrement"`
+	DimIndex     string `xml:"dimIndex"`
+}
+
+type xmlRegister struct {
+	xmlDim
+	Name          string     `xml:"name"`
+	AddressOffset string     `xml:"addressOffset"`
+	Size          string     `xml:"size"`
+	Fields        []xmlField `xml:"fields>field"`
+}
+
+type xmlCluster struct {
+	xmlDim
+	Name          string        `xml:"name"`
+	AddressOffset string        `xml:"addressOffset"`
+	Size          string        `xml:"size"`
+	Registers     []xmlRegister `xml:"register"`
+	Clusters      []xmlCluster  `xml:"cluster"`
+}
+
+type xmlPeripheral struct {
+	DerivedFrom string        `xml:"derivedFrom,attr"`
+	Name        string        `xml:"name"`
+	BaseAddress string        `xml:"baseAddress"`
+	Size        string        `xml:"size"`
+	Registers   []xmlRegister `xml:"registers>register"`
+	Clusters    []xmlCluster  `xml:"registers>cluster"`
+}
+
+type xmlDevice struct {
+	Name        string          `xml:"name"`
+	Size        string          `xml:"size"`
+	Peripherals []xmlPeripheral `xml:"peripherals>peripheral"`
+}
+
+// Field is a bit field of a register
+type Field struct {
+	Name   string
+	Offset uint
+	Width  uint
+	Enums  map[uint32]string // names of the enumerated values
+	Other  string            // name of the values without enumerated value
+}
+
+// Register is a register of a peripheral, the name contains the names of
+// the clusters
+type Register struct {
+	Peripheral string
+	Name       string
+	Address    uint32
+	Size       uint // in bits
+	Fields     []Field
+}
+
+// Device are the registers of an SVD file by address, the first register
+// of an address is used if registers alternate
+type Device struct {
+	Name      string
+	registers map[uint32]*Register
+}
+
+// number parses a scaledNonNegativeInteger, hex with 0x, binary with #
+// or decimal
+func number(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	var n uint64
+	var err error
+	switch {
+	case strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X"):
+		n, err = strconv.ParseUint(s[2:], 16, 64)
+	case strings.HasPrefix(s, "#"):
+		n, err = strconv.ParseUint(s[1:], 2, 64)
+	default:
+		n, err = strconv.ParseUint(s, 10, 64)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", errNumber, s)
+	}
+	return n, nil
+}
+
+// optional number, def if s is empty
+func optional(s string, def uint64) (uint64, error) {
+	if len(strings.TrimSpace(s)) == 0 {
+		return def, nil
+	}
+	return number(s)
+}
+
+// names and address increment of the elements of a dim array, a single
+// element without dim
+func (d *xmlDim) expand(name string) ([]string, uint64, error) {
+	if len(d.Dim) == 0 {
+		return []string{name}, 0, nil
+	}
+	dim, err := number(d.Dim)
+	if err != nil {
+		return nil, 0, err
+	}
+	increment, err := number(d.DimIncrement)
+	if err != nil {
+		return nil, 0, err
+	}
+	var index []string
+	if from, to, ok := strings.Cut(d.DimIndex, "-"); ok {
+		f, err1 := strconv.Atoi(strings.TrimSpace(from))
+		l, err2 := strconv.Atoi(strings.TrimSpace(to))
+		if err1 != nil || err2 != nil {
+			return nil, 0, fmt.Errorf("%w: %q", errNumber, d.DimIndex)
+		}
+		for i := f; i <= l; i++ {
+			index = append(index, strconv.Itoa(i))
+		}
+	} else if len(d.DimIndex) != 0 {
+		for _, s := range strings.Split(d.DimIndex, ",") {
+			index = append(index, strings.TrimSpace(s))
+		}
+	}
+	names := make([]string, dim)
+	for i := range names {
+		idx := strconv.Itoa(i)
+		if i < len(index) {
+			idx = index[i]
+		}
+		names[i] = strings.ReplaceAll(strings.ReplaceAll(name, "[%s]", idx), "%s", idx)
+	}
+	return names, increment, nil
+}
+
+func (f *xmlField) field() (Field, error) {
+	field := Field{Name: f.Name}
+	var lsb, msb uint64
+	var err error
+	switch {
+	case len(f.BitRange) != 0:
+		bits := strings.Trim(strings.TrimSpace(f.BitRange), "[]")
+		m, l, _ := strings.Cut(bits, ":")
+		if msb, err = number(m); err == nil {
+			lsb, err = number(l)
+		}
+	case len(f.Lsb) != 0:
+		if lsb, err = number(f.Lsb); err == nil {
+			msb, err = number(f.Msb)
+		}
+	default:
+		var width uint64
+		if lsb, err = number(f.BitOffset); err == nil {
+			width, err = optional(f.BitWidth, 1)
+			msb = lsb + width - 1
+		}
+	}
+	if err != nil {
+		return field, fmt.Errorf("field %s: %w", f.Name, err)
+	}
+	if msb < lsb || msb > 63 {
+		return field, fmt.Errorf("field %s: %w: bits %d to %d", f.Name, errNumber, lsb, msb)
+	}
+	field.Offset, field.Width = uint(lsb), uint(msb-lsb+1)
+	for _, e := range f.Enums {
+		if e.IsDefault == "true" || e.IsDefault == "1" {
+			field.Other = e.Name
+			continue
+		}
+		v, err := number(e.Value)
+		if err != nil { // values with don't care bits are not decoded
+			continue
+		}
+		if field.Enums == nil {
+			field.Enums = make(map[uint32]string)
+		}
+		field.Enums[uint32(v)] = e.Name
+	}
+	return field, nil
+}
+
+// add the registers of a peripheral or cluster at base
+func (d *Device) add(peripheral, prefix string, base, size uint64,
+	registers []xmlRegister, clusters []xmlCluster) error {
+	for i := range registers {
+		r := &registers[i]
+		names, increment, err := r.expand(r.Name)
+		if err != nil {
+			return fmt.Errorf("register %s.%s: %w", peripheral, r.Name, err)
+		}
+		offset, err := number(r.AddressOffset)
+		if err != nil {
+			return fmt.Errorf("register %s.%s: %w", peripheral, r.Name, err)
+		}
+		bits, err := optional(r.Size, size)
+		if err != nil {
+			return fmt.Errorf("register %s.%s: %w", peripheral, r.Name, err)
+		}
+		fields := make([]Field, len(r.Fields))
+		for j := range r.Fields {
+			if fields[j], err = r.Fields[j].field(); err != nil {
+				return fmt.Errorf("register %s.%s: %w", peripheral, r.Name, err)
+			}
+		}
+		sort.Slice(fields, func(a, b int) bool { return fields[a].Offset < fields[b].Offset })
+		for n, name := range names {
+			addr := uint32(base + offset + uint64(n)*increment)
+			if _, ok := d.registers[addr]; ok {
+				continue
+			}
+			d.registers[addr] = &Register{Peripheral: peripheral, Name: prefix + name,
+				Address: addr, Size: uint(bits), Fields: fields}
+		}
+	}
+	for i := range clusters {
+		c := &clusters[i]
+		names, increment, err := c.expand(c.Name)
+		if err != nil {
+			return fmt.Errorf("cluster %s.%s: %w", peripheral, c.Name, err)
+		}
+		offset, err := number(c.AddressOffset)
+		if err != nil {
+			return fmt.Errorf("cluster %s.%s: %w", peripheral, c.Name, err)
+		}
+		bits, err := optional(c.Size, size)
+		if err != nil {
+			return fmt.Errorf("cluster %s.%s: %w", peripheral, c.Name, err)
+		}
+		for n, name := range names {
+			if err := d.add(peripheral, prefix+name+".", base+offset+uint64(n)*increment, bits,
+				c.Registers, c.Clusters); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Parse reads an SVD file
+func Parse(r io.Reader) (*Device, error) {
+	var dev xmlDevice
+	if err := xml.NewDecoder(r).Decode(&dev); err != nil {
+		return nil, err
+	}
+	if len(dev.Peripherals) == 0 {
+		return nil, errNoPeripherals
+	}
+	size, err := optional(dev.Size, 32)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*xmlPeripheral, len(dev.Peripherals))
+	for i := range dev.Peripherals {
+		byName[dev.Peripherals[i].Name] = &dev.Peripherals[i]
+	}
+	d := &Device{Name: dev.Name, registers: make(map[uint32]*Register)}
+	for i := range dev.Peripherals {
+		p := &dev.Peripherals[i]
+		registers, clusters, pSize := p.Registers, p.Clusters, p.Size
+		if len(p.DerivedFrom) != 0 {
+			base, ok := byName[p.DerivedFrom]
+			if !ok {
+				return nil, fmt.Errorf("peripheral %s: %w %s", p.Name, errDerived, p.DerivedFrom)
+			}
+			if len(registers) == 0 && len(clusters) == 0 {
+				registers, clusters = base.Registers, base.Clusters
+			}
+			if len(pSize) == 0 {
+				pSize = base.Size
+			}
+		}
+		baseAddress, err := number(p.BaseAddress)
+		if err != nil {
+			return nil, fmt.Errorf("peripheral %s: %w", p.Name, err)
+		}
+		bits, err := optional(pSize, size)
+		if err != nil {
+			return nil, fmt.Errorf("peripheral %s: %w", p.Name, err)
+		}
+		if err := d.add(p.Name, "", baseAddress, bits, registers, clusters); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// Load reads an SVD file by name
+func Load(name string) (*Device, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	d, err := Parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return d, nil
+}
+
+// Find returns the register of an address, nil if there is none
+func (d *Device) Find(addr uint32) *Register {
+	return d.registers[addr]
+}
+
+// format a field value, decimal if narrower than a byte
+func (f *Field) format(v uint32) string {
+	s := strconv.FormatUint(uint64(v), 10)
+	if f.Width >= 8 {
+		s = fmt.Sprintf("0x%X", v)
+	}
+	name, ok := f.Enums[v]
+	if !ok {
+		name = f.Other
+	}
+	if len(name) != 0 {
+		s += " (" + name + ")"
+	}
+	return s
+}
+
+// Format decodes a value of the register into its fields, e.g.
+// GPIOA.MODER.MODER0 = 1 (Output), GPIOA.MODER.MODER1 = 0 (Input), the
+// value of a register without fields in hex
+func (r *Register) Format(value uint32) string {
+	if r.Size < 32 {
+		value &= 1<<r.Size - 1
+	}
+	name := r.Peripheral + "." + r.Name
+	if len(r.Fields) == 0 {
+		return fmt.Sprintf("%s = 0x%0*X", name, (r.Size+3)/4, value)
+	}
+	parts := make([]string, 0, len(r.Fields))
+	for i := range r.Fields {
+		f := &r.Fields[i]
+		v := uint32(uint64(value) >> f.Offset & (1<<f.Width - 1))
+		parts = append(parts, name+"."+f.Name+" = "+f.format(v))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// register dump of an event: the address and the values of the
+// registers at the following word addresses
+func dump(ev *event.Data) (uint32, []uint32, error) {
+	switch ev.Typ {
+	case 1:
+		if ev.Data != nil && len(*ev.Data) >= 8 {
+			data := *ev.Data
+			values := make([]uint32, (len(data)-4)/4)
+			for i := range values {
+				values[i] = event.ByteOrder.Uint32(data[4+4*i:])
+			}
+			return event.ByteOrder.Uint32(data), values, nil
+		}
+	case 2:
+		return uint32(ev.Value1), []uint32{uint32(ev.Value2)}, nil
+	case 3:
+		return uint32(ev.Value1), []uint32{uint32(ev.Value2), uint32(ev.Value3), uint32(ev.Value4)}, nil
+	}
+	return 0, nil, fmt.Errorf("event 0x%04X: %w", ev.Info.ID, errShort)
+}
+
+// Decode formats an event as register dump, the first value is the
+// address, the following values are the registers at consecutive word
+// addresses: the value of EventRecord2, the three values of EventRecord4
+// or the words of the data
+func (d *Device) Decode(ev *event.Data) (plugin.Result, error) {
+	addr, values, err := dump(ev)
+	if err != nil {
+		return plugin.Result{}, err
+	}
+	res := plugin.Result{Level: "Detail"}
+	var names, parts []string
+	for i, v := range values {
+		a := addr + uint32(4*i)
+		r := d.Find(a)
+		if r == nil {
+			parts = append(parts, fmt.Sprintf("0x%08X = 0x%08X", a, v))
+			continue
+		}
+		if len(res.Component) == 0 {
+			res.Component = r.Peripheral
+		}
+		names = append(names, r.Name)
+		parts = append(parts, r.Format(v))
+	}
+	res.Property = strings.Join(names, ",")
+	res.Value = strings.Join(parts, "; ")
+	return res, nil
+}
@@ -0,0 +1,159 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package svd
+
+import (
+	"errors"
+	"eventlist/pkg/event"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		file    string
+		wantErr error
+	}{
+		{"ok", "../../../testdata/device.svd", nil},
+		{"derived", "../../../testdata/device_err.svd", errDerived},
+		{"no peripherals", "../../../testdata/test.xml", errNoPeripherals},
+		{"nix", "../../../testdata/nix.svd", os.ErrNotExist},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Load(tt.file)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Load() %s error = %v, want %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDevice_Find(t *testing.T) {
+	t.Parallel()
+
+	d, err := Load("../../../testdata/device.svd")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	tests := []struct {
+		name string
+		addr uint32
+		want string
+		size uint
+	}{
+		{"register", 0x300066A8, "TIM0.CTRL", 32},
+		{"size", 0x300066B0, "TIM0.DATA", 16},
+		{"dim index", 0x30006604, "TIM0.REGB", 32},
+		{"cluster", 0x3000661C, "TIM0.CH1.CCR", 32},
+		{"derived", 0x300076AC, "TIM1.STATUS", 32},
+		{"none", 0x30006608, "", 0},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got string
+			var size uint
+			if r := d.Find(tt.addr); r != nil {
+				got, size = r.Peripheral+"."+r.Name, r.Size
+			}
+			if got != tt.want || size != tt.size {
+				t.Errorf("Device.Find() %s = %s %d, want %s %d", tt.name, got, size, tt.want, tt.size)
+			}
+		})
+	}
+}
+
+func TestRegister_Format(t *testing.T) {
+	t.Parallel()
+
+	d, err := Load("../../../testdata/device.svd")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	tests := []struct {
+		name  string
+		addr  uint32
+		value uint32
+		want  string
+	}{
+		{"fields", 0x300066A8, 0x5dc3, "TIM0.CTRL.EN = 1, TIM0.CTRL.MODE = 1 (Single), TIM0.CTRL.PRESC = 0x5DC"},
+		{"default", 0x300066A8, 0x6, "TIM0.CTRL.EN = 0, TIM0.CTRL.MODE = 3 (Reserved), TIM0.CTRL.PRESC = 0x0"},
+		{"enum", 0x300066AC, 0xFFFFFFFE, "TIM0.STATUS.READY = 0 (Busy)"},
+		{"no fields", 0x300066B0, 0x12345678, "TIM0.DATA = 0x5678"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := d.Find(tt.addr).Format(tt.value); got != tt.want {
+				t.Errorf("Register.Format() %s = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDevice_Decode(t *testing.T) {
+	t.Parallel()
+
+	d, err := Load("../../../testdata/device.svd")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	data := []uint8{0xAC, 0x66, 0x00, 0x30, 0x01, 0x00, 0x00, 0x00}
+	tests := []struct {
+		name    string
+		ev      event.Data
+		want    string
+		wantErr bool
+	}{
+		{"record2", event.Data{Typ: 2, Value1: 0x300066AC, Value2: 1}, "TIM0 STATUS TIM0.STATUS.READY = 1 (Ready)", false},
+		{"record4", event.Data{Typ: 3, Value1: 0x300066A8, Value2: 1, Value3: 1, Value4: 0xAB},
+			"TIM0 CTRL,STATUS,DATA TIM0.CTRL.EN = 1, TIM0.CTRL.MODE = 0 (Off), TIM0.CTRL.PRESC = 0x0; " +
+				"TIM0.STATUS.READY = 1 (Ready); TIM0.DATA = 0x00AB", false},
+		{"data", event.Data{Typ: 1, Data: &data}, "TIM0 STATUS TIM0.STATUS.READY = 1 (Ready)", false},
+		{"unknown", event.Data{Typ: 2, Value1: 0x10, Value2: 1}, "  0x00000010 = 0x00000001", false},
+		{"short", event.Data{Typ: 1}, "", true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			res, err := d.Decode(&tt.ev)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Device.Decode() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			got := strings.Join([]string{res.Component, res.Property, res.Value}, " ")
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("Device.Decode() %s = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package xsd validates XML files against the subset of XML Schema used by
+// the schema of the SCVD files: the elements allowed in an element, the
+// attributes, required attributes and the enumerations and patterns of
+// their values.
+package xsd
+
+import (
+	_ "embed" // the SCVD schema
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+)
+
+//go:embed Component_Viewer.xsd
+var scvdSchema []byte
+
+var errSchema = errors.New("invalid schema")
+
+// simpleType restricts the values of an attribute
+type simpleType struct {
+	enums   map[string]bool
+	pattern *regexp.Regexp
+}
+
+// attribute is an attribute of a complex type
+type attribute struct {
+	typ      string
+	required bool
+}
+
+// complexType are the child elements and the attributes of an element
+type complexType struct {
+	name     string
+	elements map[string]string // type names of the child elements
+	attrs    map[string]attribute
+}
+
+// Schema is a parsed XML schema
+type Schema struct {
+	simpleTypes  map[string]*simpleType
+	complexTypes map[string]*complexType
+	root         *complexType
+	anonymous    int
+}
+
+// Violation is a part of a file that does not match the schema
+type Violation struct {
+	Line int
+	Msg  string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%d: %s", v.Line, v.Msg)
+}
+
+// node is an element of the schema
+type node struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Nodes   []node     `xml:",any"`
+}
+
+func (n *node) attr(name string) string {
+	for _, a := range n.Attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// SCVD returns the bundled schema of the SCVD files
+func SCVD() *Schema {
+	s, err := Parse(scvdSchema)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Parse reads a schema
+func Parse(data []byte) (*Schema, error) {
+	var n node
+	if err := xml.Unmarshal(data, &n); err != nil {
+		return nil, err
+	}
+	if n.XMLName.Local != "schema" {
+		return nil, fmt.Errorf("%w: root element %s", errSchema, n.XMLName.Local)
+	}
+	s := &Schema{simpleTypes: make(map[string]*simpleType), complexTypes: make(map[string]*complexType),
+		root: &complexType{elements: make(map[string]string), attrs: make(map[string]attribute)}}
+	for i := range n.Nodes {
+		child := &n.Nodes[i]
+		switch child.XMLName.Local {
+		case "simpleType":
+			t, err := s.simpleType(child)
+			if err != nil {
+				return nil, err
+			}
+			s.simpleTypes[child.attr("name")] = t
+		case "complexType":
+			s.complexType(child, child.attr("name"))
+		case "element":
+			s.root.elements[child.attr("name")] = s.elementType(child)
+		}
+	}
+	return s, nil
+}
+
+// simpleType reads the enumerations and the pattern of a restriction
+func (s *Schema) simpleType(n *node) (*simpleType, error) {
+	t := &simpleType{}
+	for _, r := range n.Nodes {
+		if r.XMLName.Local != "restriction" {
+			continue
+		}
+		for _, f := range r.Nodes {
+			switch f.XMLName.Local {
+			case "enumeration":
+				if t.enums == nil {
+					t.enums = make(map[string]bool)
+				}
+				t.enums[f.attr("value")] = true
+			case "pattern":
+				re, err := regexp.Compile("^(?:" + f.attr("value") + ")$")
+				if err != nil {
+					return nil, fmt.Errorf("%w: %s: %v", errSchema, n.attr("name"), err)
+				}
+				t.pattern = re
+			}
+		}
+	}
+	return t, nil
+}
+
+// complexType adds the type of n with the name
+func (s *Schema) complexType(n *node, name string) {
+	t := &complexType{name: name, elements: make(map[string]string), attrs: make(map[string]attribute)}
+	s.complexTypes[name] = t
+	s.content(n, t)
+}
+
+// content adds the elements and attributes of the model groups and the
+// extension of n to t
+func (s *Schema) content(n *node, t *complexType) {
+	for i := range n.Nodes {
+		child := &n.Nodes[i]
+		switch child.XMLName.Local {
+		case "element":
+			t.elements[child.attr("name")] = s.elementType(child)
+		case "attribute":
+			t.attrs[child.attr("name")] = attribute{typ: child.attr("type"), required: child.attr("use") == "required"}
+		case "sequence", "choice", "all", "simpleContent", "complexContent", "extension":
+			s.content(child, t)
+		}
+	}
+}
+
+// elementType returns the type name of an element, an anonymous type
+// gets a generated name
+func (s *Schema) elementType(n *node) string {
+	if typ := n.attr("type"); len(typ) != 0 {
+		return typ
+	}
+	for i := range n.Nodes {
+		if n.Nodes[i].XMLName.Local == "complexType" {
+			s.anonymous++
+			name := fmt.Sprintf("#%d", s.anonymous)
+			s.complexType(&n.Nodes[i], name)
+			return name
+		}
+	}
+	return ""
+}
+
+// valid checks a value of a type
+func (s *Schema) valid(typ string, value string) bool {
+	switch typ {
+	case "xs:boolean":
+		return value == "true" || value == "false" || value == "1" || value == "0"
+	}
+	t, ok := s.simpleTypes[typ]
+	if !ok {
+		return true
+	}
+	if t.enums != nil && !t.enums[value] {
+		return false
+	}
+	return t.pattern == nil || t.pattern.MatchString(value)
+}
+
+// Validate checks an XML file, the elements in unknown elements are not
+// checked, an error is returned if the file is no XML
+func (s *Schema) Validate(r io.Reader) ([]Violation, error) {
+	var violations []Violation
+	d := xml.NewDecoder(r)
+	add := func(format string, a ...any) {
+		line, _ := d.InputPos()
+		violations = append(violations, Violation{Line: line, Msg: fmt.Sprintf(format, a...)})
+	}
+	stack := []*complexType{s.root}
+	names := []string{""}
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			return violations, nil
+		}
+		if err != nil {
+			return violations, err
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			parent := stack[len(stack)-1]
+			var t *complexType
+			if parent != nil {
+				typ, ok := parent.elements[tok.Name.Local]
+				switch {
+				case !ok && len(names) == 1:
+					add("unknown root element <%s>", tok.Name.Local)
+				case !ok:
+					add("unknown element <%s> in <%s>", tok.Name.Local, names[len(names)-1])
+				default:
+					t = s.complexTypes[typ]
+				}
+			}
+			if t != nil {
+				s.attributes(t, tok, add)
+			}
+			stack = append(stack, t)
+			names = append(names, tok.Name.Local)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+			names = names[:len(names)-1]
+		}
+	}
+}
+
+// attributes checks the attributes of an element, namespace declarations
+// and qualified attributes, e.g. xs:noNamespaceSchemaLocation, are allowed
+func (s *Schema) attributes(t *complexType, tok xml.StartElement, add func(string, ...any)) {
+	seen := make(map[string]bool)
+	for _, a := range tok.Attr {
+		if len(a.Name.Space) != 0 || a.Name.Local == "xmlns" {
+			continue
+		}
+		seen[a.Name.Local] = true
+		attr, ok := t.attrs[a.Name.Local]
+		if !ok {
+			add("unknown attribute %s of <%s>", a.Name.Local, tok.Name.Local)
+			continue
+		}
+		if !s.valid(attr.typ, a.Value) {
+			add("invalid value of attribute %s of <%s>: %s", a.Name.Local, tok.Name.Local, a.Value)
+		}
+	}
+	var missing []string
+	for name, attr := range t.attrs {
+		if attr.required && !seen[name] {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	for _, name := range missing {
+		add("missing attribute %s of <%s>", name, tok.Name.Local)
+	}
+}
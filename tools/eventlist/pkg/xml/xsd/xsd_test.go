@@ -0,0 +1,120 @@
+/*
+ * Copyright (c) 2023 Arm Limited. All rights reserved.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the License); you may
+ * not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an AS IS BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package xsd
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSchema_Validate(t *testing.T) {
+	t.Parallel()
+
+	s := SCVD()
+	tests := []struct {
+		name    string
+		xml     string
+		want    []Violation
+		wantErr bool
+	}{
+		{"ok", `<component_viewer xmlns:xs="http://www.w3.org/2001/XMLSchema-instance" xs:noNamespaceSchemaLocation="Component_Viewer.xsd">
+<component name="Test" version="1.0.0"/>
+<events>
+<group><component name="Test" brief="Test" no="0x10"/></group>
+<event id="0x1000" level="Op" property="Poll" value="n=%d[val1]"/>
+</events>
+</component_viewer>`, nil, false},
+		{"element", `<component_viewer>
+<typedefs>
+<typedef name="t">
+<pint name="x"><nix/></pint>
+</typedef>
+</typedefs>
+</component_viewer>`, []Violation{{4, "unknown element <pint> in <typedef>"}}, false},
+		{"attributes", `<component_viewer>
+<events>
+<event id="0x1000" levl="Op"/>
+<event id="0x1001" level="Warning"/>
+</events>
+<component name="x" shortname="y" versoin="1"/>
+</component_viewer>`, []Violation{
+			{3, "unknown attribute levl of <event>"},
+			{3, "missing attribute level of <event>"},
+			{4, "invalid value of attribute level of <event>: Warning"},
+			{6, "unknown attribute versoin of <component>"},
+		}, false},
+		{"pattern", `<component_viewer><events><group><component no="0xG0"/></group></events></component_viewer>`,
+			[]Violation{{1, "invalid value of attribute no of <component>: 0xG0"}}, false},
+		{"root", `<viewer/>`, []Violation{{1, "unknown root element <viewer>"}}, false},
+		{"syntax", `<component_viewer><events></component_viewer>`, nil, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := s.Validate(strings.NewReader(tt.xml))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Schema.Validate() %s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Schema.Validate() %s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		xsd     string
+		wantErr error
+	}{
+		{"ok", `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"><xs:element name="a"/></xs:schema>`, nil},
+		{"root", `<schema2/>`, errSchema},
+		{"pattern", `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"><xs:simpleType name="n">
+<xs:restriction base="xs:string"><xs:pattern value="[0-9"/></xs:restriction></xs:simpleType></xs:schema>`, errSchema},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse([]byte(tt.xsd))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parse() %s error = %v, want %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+	if _, err := Parse([]byte("<")); err == nil {
+		t.Errorf("Parse() error = nil, want error")
+	}
+}
+
+func TestViolation_String(t *testing.T) {
+	t.Parallel()
+
+	if got := (Violation{12, "unknown element <pint> in <typedef>"}).String(); got != "12: unknown element <pint> in <typedef>" {
+		t.Errorf("Violation.String() = %q", got)
+	}
+}
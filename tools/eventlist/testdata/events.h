@@ -0,0 +1,7 @@
+/* event IDs of the network stack */
+#include "EventRecorder.h"
+
+#define EvtNet_No       0xA1U
+#define EvtNetInit      EventID(EventLevelAPI,   EvtNet_No, 0x00U)  ///< stack initialized
+#define EvtNetSend      EventID(EventLevelOp,    EvtNet_No, 0x01U)  ///< packet sent
+#define EvtNetFail      EventID(EventLevelError, EvtNet_No, 0x02U)  ///< send failed
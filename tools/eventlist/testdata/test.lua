@@ -0,0 +1,21 @@
+-- count the events per component
+local counts = {}
+local names = {}
+
+function on_event(ev)
+  if counts[ev.component] == nil then
+    counts[ev.component] = 0
+    table.insert(names, ev.component)
+  end
+  counts[ev.component] = counts[ev.component] + 1
+end
+
+function on_end()
+  report.section("Events per component")
+  local rows = {}
+  for _, name in ipairs(names) do
+    table.insert(rows, {name, counts[name]})
+  end
+  report.table({"Component", "Count"}, rows)
+  report.print("components:", #names)
+end
@@ -0,0 +1,3 @@
+function on_event(ev)
+  error("failed")
+end